@@ -167,8 +167,16 @@ const (
 	AgentServiceName  = "AGENT_SERVICE_NAME"
 	MongoOplogSize    = "MONGO_OPLOG_SIZE"
 	NUMACtlPreference = "NUMA_CTL_PREFERENCE"
+	LoggingConfig     = "LOGGING_CONFIG"
+	MaxLogSize        = "MAX_LOG_SIZE"
+	AgentCPUQuota     = "AGENT_CPU_QUOTA"
+	AgentMemoryLimit  = "AGENT_MEMORY_LIMIT"
 )
 
+// DefaultMaxLogSize is the size, in megabytes, at which agent log
+// files are rotated if no MaxLogSize value is configured.
+const DefaultMaxLogSize = 300
+
 // The Config interface is the sole way that the agent gets access to the
 // configuration information for the machine and unit agents.  There should
 // only be one instance of a config object for any given agent, and this
@@ -307,6 +315,31 @@ func LogFilename(c Config) string {
 	return filepath.Join(c.LogDir(), c.Tag().String()+".log")
 }
 
+// MaxLogSizeMB returns the size, in megabytes, at which the agent's
+// log file should be rotated.
+func MaxLogSizeMB(c Config) int {
+	if value := c.Value(MaxLogSize); value != "" {
+		if size, err := strconv.Atoi(value); err == nil && size > 0 {
+			return size
+		}
+		logger.Warningf("ignoring invalid %s value %q", MaxLogSize, value)
+	}
+	return DefaultMaxLogSize
+}
+
+// ConfigureLogging applies the logging configuration recorded in the
+// agent's config, if any, so that the agent logs at the model's
+// configured levels before it is able to ask the controller for them.
+func ConfigureLogging(c Config) {
+	value := c.Value(LoggingConfig)
+	if value == "" {
+		return
+	}
+	if err := loggo.ConfigureLoggers(value); err != nil {
+		logger.Warningf("ignoring invalid %s value %q: %v", LoggingConfig, value, err)
+	}
+}
+
 type ConfigMutator func(ConfigSetter) error
 
 type ConfigWriter interface {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
@@ -400,6 +401,27 @@ func (*suite) TestAttributes(c *gc.C) {
 	c.Assert(conf.UpgradedToVersion(), jc.DeepEquals, jujuversion.Current)
 }
 
+func (*suite) TestConfigureLogging(c *gc.C) {
+	defer loggo.DefaultContext().ResetLoggerLevels()
+	conf, err := agent.NewAgentConfig(attributeParams)
+	c.Assert(err, jc.ErrorIsNil)
+	conf.SetValue(agent.LoggingConfig, "<root>=WARNING;juju.agent.test=TRACE")
+	agent.ConfigureLogging(conf)
+	c.Assert(loggo.GetLogger("juju.agent.test").LogLevel(), gc.Equals, loggo.TRACE)
+}
+
+func (*suite) TestMaxLogSizeMB(c *gc.C) {
+	conf, err := agent.NewAgentConfig(attributeParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(agent.MaxLogSizeMB(conf), gc.Equals, agent.DefaultMaxLogSize)
+
+	conf.SetValue(agent.MaxLogSize, "50")
+	c.Assert(agent.MaxLogSizeMB(conf), gc.Equals, 50)
+
+	conf.SetValue(agent.MaxLogSize, "lots")
+	c.Assert(agent.MaxLogSizeMB(conf), gc.Equals, agent.DefaultMaxLogSize)
+}
+
 func (*suite) TestStateServingInfo(c *gc.C) {
 	servingInfo := stateServingInfo()
 	conf, err := agent.NewStateMachineConfig(attributeParams, servingInfo)
@@ -456,6 +478,23 @@ func (*suite) TestWriteAndRead(c *gc.C) {
 	c.Assert(reread, jc.DeepEquals, conf)
 }
 
+func (*suite) TestWriteAndReadStateServingInfoWithCertChain(c *gc.C) {
+	testParams := attributeParams
+	testParams.Paths.DataDir = c.MkDir()
+	testParams.Paths.LogDir = c.MkDir()
+	servingInfo := stateServingInfo()
+	servingInfo.CertChain = "intermediate cert"
+	conf, err := agent.NewStateMachineConfig(testParams, servingInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(conf.Write(), gc.IsNil)
+	reread, err := agent.ReadConfig(agent.ConfigPath(conf.DataDir(), conf.Tag()))
+	c.Assert(err, jc.ErrorIsNil)
+	gotInfo, ok := reread.StateServingInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(gotInfo, jc.DeepEquals, servingInfo)
+}
+
 func (*suite) TestAPIInfoMissingAddress(c *gc.C) {
 	conf := agent.EmptyConfig()
 	_, ok := conf.APIInfo()
@@ -221,6 +221,7 @@ func paramsStateServingInfoToStateStateServingInfo(i params.StateServingInfo) st
 		StatePort:      i.StatePort,
 		Cert:           i.Cert,
 		PrivateKey:     i.PrivateKey,
+		CertChain:      i.CertChain,
 		CAPrivateKey:   i.CAPrivateKey,
 		SharedSecret:   i.SharedSecret,
 		SystemIdentity: i.SystemIdentity,
@@ -48,15 +48,16 @@ type format_2_0Serialization struct {
 	Values      map[string]string `yaml:"values"`
 
 	// Only controller machines have these next items set.
-	ControllerCert     string `yaml:"controllercert,omitempty"`
-	ControllerKey      string `yaml:"controllerkey,omitempty"`
-	CAPrivateKey       string `yaml:"caprivatekey,omitempty"`
-	APIPort            int    `yaml:"apiport,omitempty"`
-	StatePort          int    `yaml:"stateport,omitempty"`
-	SharedSecret       string `yaml:"sharedsecret,omitempty"`
-	SystemIdentity     string `yaml:"systemidentity,omitempty"`
-	MongoVersion       string `yaml:"mongoversion,omitempty"`
-	MongoMemoryProfile string `yaml:"mongomemoryprofile,omitempty"`
+	ControllerCert      string `yaml:"controllercert,omitempty"`
+	ControllerKey       string `yaml:"controllerkey,omitempty"`
+	ControllerCertChain string `yaml:"controllercertchain,omitempty"`
+	CAPrivateKey        string `yaml:"caprivatekey,omitempty"`
+	APIPort             int    `yaml:"apiport,omitempty"`
+	StatePort           int    `yaml:"stateport,omitempty"`
+	SharedSecret        string `yaml:"sharedsecret,omitempty"`
+	SystemIdentity      string `yaml:"systemidentity,omitempty"`
+	MongoVersion        string `yaml:"mongoversion,omitempty"`
+	MongoMemoryProfile  string `yaml:"mongomemoryprofile,omitempty"`
 }
 
 func init() {
@@ -118,6 +119,7 @@ func (formatter_2_0) unmarshal(data []byte) (*configInternal, error) {
 		config.servingInfo = &params.StateServingInfo{
 			Cert:           format.ControllerCert,
 			PrivateKey:     format.ControllerKey,
+			CertChain:      format.ControllerCertChain,
 			CAPrivateKey:   format.CAPrivateKey,
 			APIPort:        format.APIPort,
 			StatePort:      format.StatePort,
@@ -178,6 +180,7 @@ func (formatter_2_0) marshal(config *configInternal) ([]byte, error) {
 	if config.servingInfo != nil {
 		format.ControllerCert = config.servingInfo.Cert
 		format.ControllerKey = config.servingInfo.PrivateKey
+		format.ControllerCertChain = config.servingInfo.CertChain
 		format.CAPrivateKey = config.servingInfo.CAPrivateKey
 		format.APIPort = config.servingInfo.APIPort
 		format.StatePort = config.servingInfo.StatePort
@@ -89,6 +89,24 @@ func (c *State) WatchCredential(tag names.CloudCredentialTag) (watcher.NotifyWat
 	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
 }
 
+// WatchControllerConfig returns a watcher which reports when the
+// controller's configuration, including its CA certificate, has
+// changed.
+func (st *State) WatchControllerConfig() (watcher.NotifyWatcher, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("WatchControllerConfig() (need V3+)")
+	}
+	var result params.NotifyWatchResult
+	err := st.facade.FacadeCall("WatchControllerConfig", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
 type Entity struct {
 	st  *State
 	tag names.Tag
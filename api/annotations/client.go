@@ -41,6 +41,51 @@ func (c *Client) Set(annotations map[string]map[string]string) ([]params.ErrorRe
 	return results.Results, nil
 }
 
+// GetMetadata returns the operator metadata of the given entities.
+func (c *Client) GetMetadata(tags []string) ([]params.OperatorMetadataResult, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("GetMetadata() (need V3+)")
+	}
+	var results params.OperatorMetadataResults
+	if err := c.facade.FacadeCall("GetMetadata", entitiesFromTags(tags), &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// SetMetadata replaces the operator metadata of the given entities,
+// keyed by tag. Setting empty metadata removes it.
+func (c *Client) SetMetadata(metadata map[string]params.OperatorMetadata) ([]params.ErrorResult, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("SetMetadata() (need V3+)")
+	}
+	args := params.SetOperatorMetadataArgs{}
+	for tag, m := range metadata {
+		args.Metadata = append(args.Metadata, params.EntityOperatorMetadata{
+			EntityTag: tag,
+			Metadata:  m,
+		})
+	}
+	results := new(params.ErrorResults)
+	if err := c.facade.FacadeCall("SetMetadata", args, results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// ListMetadata returns the operator metadata of every entity in the
+// model selected by the filter.
+func (c *Client) ListMetadata(filter params.OperatorMetadataFilter) ([]params.OperatorMetadataResult, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("ListMetadata() (need V3+)")
+	}
+	var results params.OperatorMetadataResults
+	if err := c.facade.FacadeCall("ListMetadata", filter, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
 func entitiesFromTags(tags []string) params.Entities {
 	entities := []params.Entity{}
 	for _, tag := range tags {
@@ -4,6 +4,9 @@
 package annotations_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -91,3 +94,88 @@ func (s *annotationsMockSuite) TestGetEntitiesAnnotations(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 	c.Assert(found, gc.HasLen, 1)
 }
+
+type bestVersionCaller struct {
+	basetesting.APICallerFunc
+	bestVersion int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(string) int {
+	return c.bestVersion
+}
+
+func (s *annotationsMockSuite) TestListMetadata(c *gc.C) {
+	var called bool
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(
+			objType string,
+			version int,
+			id, request string,
+			a, response interface{}) error {
+			called = true
+			c.Check(objType, gc.Equals, "Annotations")
+			c.Check(version, gc.Equals, 3)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListMetadata")
+			c.Check(a, jc.DeepEquals, params.OperatorMetadataFilter{Owner: "bob"})
+			*(response.(*params.OperatorMetadataResults)) = params.OperatorMetadataResults{
+				Results: []params.OperatorMetadataResult{{
+					EntityTag: "machine-0",
+					Metadata:  params.OperatorMetadata{Owner: "bob", Expiry: &expiry},
+				}},
+			}
+			return nil
+		})
+	annotationsClient := annotations.NewClient(bestVersionCaller{apiCaller, 3})
+	found, err := annotationsClient.ListMetadata(params.OperatorMetadataFilter{Owner: "bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(found, jc.DeepEquals, []params.OperatorMetadataResult{{
+		EntityTag: "machine-0",
+		Metadata:  params.OperatorMetadata{Owner: "bob", Expiry: &expiry},
+	}})
+}
+
+func (s *annotationsMockSuite) TestSetMetadata(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(
+			objType string,
+			version int,
+			id, request string,
+			a, response interface{}) error {
+			called = true
+			c.Check(request, gc.Equals, "SetMetadata")
+			c.Check(a, jc.DeepEquals, params.SetOperatorMetadataArgs{
+				Metadata: []params.EntityOperatorMetadata{{
+					EntityTag: "machine-0",
+					Metadata:  params.OperatorMetadata{CostCenter: "ops"},
+				}},
+			})
+			response.(*params.ErrorResults).Results = []params.ErrorResult{{}}
+			return nil
+		})
+	annotationsClient := annotations.NewClient(bestVersionCaller{apiCaller, 3})
+	results, err := annotationsClient.SetMetadata(map[string]params.OperatorMetadata{
+		"machine-0": {CostCenter: "ops"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, gc.HasLen, 1)
+}
+
+func (s *annotationsMockSuite) TestMetadataNotImplemented(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		})
+	annotationsClient := annotations.NewClient(bestVersionCaller{apiCaller, 2})
+	_, err := annotationsClient.GetMetadata([]string{"machine-0"})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = annotationsClient.SetMetadata(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = annotationsClient.ListMetadata(params.OperatorMetadataFilter{})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
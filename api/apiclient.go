@@ -94,6 +94,10 @@ type state struct {
 	// Login
 	facadeVersions map[string][]int
 
+	// facadeDeprecations holds the deprecated facade versions as
+	// reported by Login.
+	facadeDeprecations map[string][]params.FacadeDeprecation
+
 	// pingFacadeVersion is the version to use for the pinger. This is lazily
 	// set at initialization to avoid a race in our tests. See
 	// http://pad.lv/1614732 for more details regarding the race.
@@ -751,6 +755,11 @@ func (s *state) APICall(facade string, version int, id, method string, args, res
 		Clock:       s.clock,
 	}
 	err := retry.Call(retrySpec)
+	if reqErr, ok := errors.Cause(err).(*rpc.RequestError); ok && reqErr.CorrelationId != "" {
+		// Report the correlation id so that the failure can be
+		// found in the controller logs.
+		logger.Infof("%s.%s failed with correlation id %s", facade, method, reqErr.CorrelationId)
+	}
 	return errors.Trace(err)
 }
 
@@ -826,6 +835,18 @@ func (s *state) AllFacadeVersions() map[string][]int {
 	return facades
 }
 
+// FacadeDeprecation reports whether the API server has deprecated the
+// given version of the named facade, and if so, the details of the
+// deprecation.
+func (s *state) FacadeDeprecation(facade string, version int) (params.FacadeDeprecation, bool) {
+	for _, d := range s.facadeDeprecations[facade] {
+		if d.Version == version {
+			return d, true
+		}
+	}
+	return params.FacadeDeprecation{}, false
+}
+
 // BestFacadeVersion compares the versions of facades that we know about, and
 // the versions available from the server, and reports back what version is the
 // 'best available' to use.
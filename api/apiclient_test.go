@@ -330,6 +330,22 @@ func (s *apiclientSuite) TestOpenWithRedirect(c *gc.C) {
 	})
 }
 
+func (s *apiclientSuite) TestFacadeDeprecation(c *gc.C) {
+	conn := api.NewTestingState(api.TestingStateParams{
+		FacadeVersions: map[string][]int{"Foo": {1, 2}},
+		Deprecations: map[string][]params.FacadeDeprecation{
+			"Foo": {{Version: 1, Since: "2.2.0", Sunset: "3.0.0"}},
+		},
+	})
+	d, ok := conn.FacadeDeprecation("Foo", 1)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(d, jc.DeepEquals, params.FacadeDeprecation{Version: 1, Since: "2.2.0", Sunset: "3.0.0"})
+	_, ok = conn.FacadeDeprecation("Foo", 2)
+	c.Check(ok, jc.IsFalse)
+	_, ok = conn.FacadeDeprecation("Bar", 1)
+	c.Check(ok, jc.IsFalse)
+}
+
 func (s *apiclientSuite) TestAPICallNoError(c *gc.C) {
 	clock := &fakeClock{}
 	conn := api.NewTestingState(api.TestingStateParams{
@@ -50,6 +50,24 @@ func (c *Client) SetMetricCredentials(service string, credentials []byte) error
 	return errors.Trace(results.OneError())
 }
 
+// SetTags replaces the user-defined tags on the given application.
+func (c *Client) SetTags(application string, tags map[string]string) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotImplementedf("SetTags() (need V5+)")
+	}
+	args := params.SetResourceTags{
+		Entities: []params.EntityResourceTags{{
+			Tag:  names.NewApplicationTag(application).String(),
+			Tags: tags,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetTags", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // ModelUUID returns the model UUID from the client connection.
 func (c *Client) ModelUUID() string {
 	tag, ok := c.st.ModelTag()
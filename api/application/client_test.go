@@ -332,3 +332,43 @@ func (s *applicationSuite) TestDestroyUnitsInvalidIds(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+type bestVersionCaller struct {
+	basetesting.APICallerFunc
+	bestVersion int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(string) int {
+	return c.bestVersion
+}
+
+func (s *applicationSuite) TestSetTags(c *gc.C) {
+	var called bool
+	client := application.NewClient(bestVersionCaller{func(objType string, version int, id, request string, a, response interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "Application")
+		c.Check(version, gc.Equals, 5)
+		c.Check(request, gc.Equals, "SetTags")
+		c.Check(a, jc.DeepEquals, params.SetResourceTags{
+			Entities: []params.EntityResourceTags{{
+				Tag:  "application-foo",
+				Tags: map[string]string{"team": "ops"},
+			}},
+		})
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	}, 5})
+	err := client.SetTags("foo", map[string]string{"team": "ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetTagsNotSupported(c *gc.C) {
+	client := application.NewClient(bestVersionCaller{func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	}, 4})
+	err := client.SetTags("foo", nil)
+	c.Assert(err, gc.ErrorMatches, `SetTags\(\) \(need V5\+\) not implemented`)
+}
@@ -252,6 +252,27 @@ func (c *Client) SetModelAgentVersion(version version.Number) error {
 	return c.facade.FacadeCall("SetModelAgentVersion", args, nil)
 }
 
+// SetDesiredAgentVersion stages an upgrade to the given version on the
+// given machines, or on the machines hosting the given applications'
+// units, ahead of the model's agent-version. A zero version clears the
+// staged upgrade.
+func (c *Client) SetDesiredAgentVersion(version version.Number, entities ...names.Tag) error {
+	args := params.SetDesiredAgentVersions{
+		Entities: make([]params.DesiredAgentVersion, len(entities)),
+	}
+	for i, entity := range entities {
+		args.Entities[i] = params.DesiredAgentVersion{
+			Tag:     entity.String(),
+			Version: version,
+		}
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetDesiredAgentVersions", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
@@ -441,6 +441,7 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		IncludeModule: []string{"c", "d"},
 		ExcludeEntity: []string{"e", "f"},
 		ExcludeModule: []string{"g", "h"},
+		MessageRegexp: "hook .* failed",
 		Limit:         100,
 		Backlog:       200,
 		Level:         loggo.ERROR,
@@ -462,6 +463,7 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		"includeModule": params.IncludeModule,
 		"excludeEntity": params.ExcludeEntity,
 		"excludeModule": params.ExcludeModule,
+		"messageRegexp": {"hook .* failed"},
 		"maxLines":      {"100"},
 		"backlog":       {"200"},
 		"level":         {"ERROR"},
@@ -59,6 +59,23 @@ func (c *Client) Cloud(tag names.CloudTag) (jujucloud.Cloud, error) {
 	return cloudFromParams(tag.Id(), *results.Results[0].Cloud), nil
 }
 
+// ProviderConfigSchema returns the provider specific model config
+// attributes of the cloud with the given tag.
+func (c *Client) ProviderConfigSchema(tag names.CloudTag) ([]params.ConfigSchemaField, error) {
+	var results params.ProviderConfigSchemaResults
+	args := params.Entities{[]params.Entity{{tag.String()}}}
+	if err := c.facade.FacadeCall("ProviderConfigSchema", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if results.Results[0].Error != nil {
+		return nil, results.Results[0].Error
+	}
+	return results.Results[0].Fields, nil
+}
+
 func cloudFromParams(cloudName string, p params.Cloud) jujucloud.Cloud {
 	authTypes := make([]jujucloud.AuthType, len(p.AuthTypes))
 	for i, authType := range p.AuthTypes {
@@ -21,6 +21,42 @@ type cloudSuite struct {
 
 var _ = gc.Suite(&cloudSuite{})
 
+func (s *cloudSuite) TestProviderConfigSchema(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Cloud")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ProviderConfigSchema")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "cloud-foo"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ProviderConfigSchemaResults{})
+			results := result.(*params.ProviderConfigSchemaResults)
+			results.Results = append(results.Results, params.ProviderConfigSchemaResult{
+				Fields: []params.ConfigSchemaField{{
+					Name:    "vpc-id",
+					Type:    "string",
+					Default: "",
+				}},
+			})
+			return nil
+		},
+	)
+
+	client := cloudapi.NewClient(apiCaller)
+	fields, err := client.ProviderConfigSchema(names.NewCloudTag("foo"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fields, jc.DeepEquals, []params.ConfigSchemaField{{
+		Name:    "vpc-id",
+		Type:    "string",
+		Default: "",
+	}})
+}
+
 func (s *cloudSuite) TestCloud(c *gc.C) {
 	apiCaller := basetesting.APICallerFunc(
 		func(objType string,
@@ -4,6 +4,8 @@
 package common
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/api/base"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
@@ -79,3 +81,24 @@ func (a *APIAddresser) WatchAPIHostPorts() (watcher.NotifyWatcher, error) {
 	}
 	return apiwatcher.NewNotifyWatcher(a.facade.RawAPICaller(), result), nil
 }
+
+// WatchAPIHostPortsChanges returns a watcher that reports the
+// host/port addresses of the API servers each time they change, so
+// that they need not be fetched separately. It returns an error
+// satisfying errors.IsNotImplemented if the API server cannot report
+// the addresses themselves.
+func (a *APIAddresser) WatchAPIHostPortsChanges() (watcher.APIHostPortsWatcher, error) {
+	caller := a.facade.RawAPICaller()
+	if caller.BestFacadeVersion("APIHostPortsWatcher") < 1 {
+		return nil, errors.NotImplementedf("WatchAPIHostPortsChanges() (need APIHostPortsWatcher V1+)")
+	}
+	var result params.NotifyWatchResult
+	err := a.facade.FacadeCall("WatchAPIHostPorts", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewAPIHostPortsWatcher(caller, result.NotifyWatcherId), nil
+}
@@ -34,6 +34,9 @@ type DebugLogParams struct {
 	// ExcludeModule lists logging modules to exclude from the resposne. If a
 	// module is specified, all the submodules are also excluded.
 	ExcludeModule []string
+	// MessageRegexp, if set, is a regular expression that the returned
+	// log messages must match.
+	MessageRegexp string
 	// Limit defines the maximum number of lines to return. Once this many
 	// have been sent, the socket is closed.  If zero, all filtered lines are
 	// sent down the connection until the client closes the connection.
@@ -62,6 +65,9 @@ func (args DebugLogParams) URLQuery() url.Values {
 		"excludeEntity": args.ExcludeEntity,
 		"excludeModule": args.ExcludeModule,
 	}
+	if args.MessageRegexp != "" {
+		attrs.Set("messageRegexp", args.MessageRegexp)
+	}
 	if args.Replay {
 		attrs.Set("replay", fmt.Sprint(args.Replay))
 	}
@@ -297,6 +297,93 @@ func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
 	return result.MigrationId, nil
 }
 
+// AuditLog returns the entries in the controller's audit log selected
+// by the filter, most recent first. Callers must be controller
+// administrators.
+func (c *Client) AuditLog(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	if c.BestAPIVersion() < 4 {
+		return nil, errors.NotImplementedf("AuditLog() (need V4+)")
+	}
+	var result params.AuditLogResults
+	if err := c.facade.FacadeCall("AuditLog", filter, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Entries, nil
+}
+
+// ModelLeases returns the leases held in each of the given models.
+// Callers must be controller administrators.
+func (c *Client) ModelLeases(models ...names.ModelTag) ([]params.LeaseDetailsResult, error) {
+	if c.BestAPIVersion() < 5 {
+		return nil, errors.NotImplementedf("ModelLeases() (need V5+)")
+	}
+	args := params.Entities{
+		Entities: make([]params.Entity, len(models)),
+	}
+	for i, model := range models {
+		args.Entities[i].Tag = model.String()
+	}
+	var result params.LeaseDetailsResults
+	if err := c.facade.FacadeCall("ModelLeases", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(result.Results) != len(models) {
+		return nil, errors.Errorf("expected %d results, got %d", len(models), len(result.Results))
+	}
+	return result.Results, nil
+}
+
+// StageCARotation starts replacing the controller's CA with the given
+// CA certificate and private key, both PEM encoded.
+func (c *Client) StageCARotation(caCert, caPrivateKey string) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotImplementedf("StageCARotation() (need V6+)")
+	}
+	args := params.StageCARotationArgs{
+		CACert:       caCert,
+		CAPrivateKey: caPrivateKey,
+	}
+	return errors.Trace(c.facade.FacadeCall("StageCARotation", args, nil))
+}
+
+// CutOverCARotation replaces the controller's certificate with one
+// signed by the staged CA.
+func (c *Client) CutOverCARotation() error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotImplementedf("CutOverCARotation() (need V6+)")
+	}
+	return errors.Trace(c.facade.FacadeCall("CutOverCARotation", nil, nil))
+}
+
+// CompleteCARotation finishes a CA rotation that has been cut over.
+func (c *Client) CompleteCARotation() error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotImplementedf("CompleteCARotation() (need V6+)")
+	}
+	return errors.Trace(c.facade.FacadeCall("CompleteCARotation", nil, nil))
+}
+
+// AbortCARotation abandons a CA rotation that has not been cut over.
+func (c *Client) AbortCARotation() error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotImplementedf("AbortCARotation() (need V6+)")
+	}
+	return errors.Trace(c.facade.FacadeCall("AbortCARotation", nil, nil))
+}
+
+// CARotationStatus returns the CA rotation in progress, or nil if
+// there is none.
+func (c *Client) CARotationStatus() (*params.CARotationStatus, error) {
+	if c.BestAPIVersion() < 6 {
+		return nil, errors.NotImplementedf("CARotationStatus() (need V6+)")
+	}
+	var result params.CARotationStatusResult
+	if err := c.facade.FacadeCall("CARotationStatus", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Status, nil
+}
+
 func macaroonsToJSON(macs []macaroon.Slice) (string, error) {
 	if len(macs) == 0 {
 		return "", nil
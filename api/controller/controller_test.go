@@ -194,6 +194,48 @@ func (s *Suite) TestHostedModelConfigs_FormatResults(c *gc.C) {
 	c.Assert(third.Error.Error(), gc.Equals, "validating CloudSpec: empty Type not valid")
 }
 
+type bestVersionCaller struct {
+	apitesting.APICallerFunc
+	bestVersion int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(string) int {
+	return c.bestVersion
+}
+
+func (s *Suite) TestAuditLog(c *gc.C) {
+	filter := params.AuditLogFilter{
+		UserTag: "user-bob",
+		Limit:   10,
+	}
+	entries := []params.AuditLogEntry{{
+		ModelTag:   "model-" + utils.MustNewUUID().String(),
+		OriginName: "user-bob",
+		Operation:  "Application:v1 - Destroy",
+	}}
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Controller")
+		c.Check(request, gc.Equals, "AuditLog")
+		c.Check(arg, jc.DeepEquals, filter)
+		*(result.(*params.AuditLogResults)) = params.AuditLogResults{Entries: entries}
+		return nil
+	})
+	client := controller.NewClient(bestVersionCaller{apiCaller, 4})
+	result, err := client.AuditLog(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, entries)
+}
+
+func (s *Suite) TestAuditLogNotImplemented(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	client := controller.NewClient(bestVersionCaller{apiCaller, 3})
+	_, err := client.AuditLog(params.AuditLogFilter{})
+	c.Assert(err, gc.ErrorMatches, `AuditLog\(\) \(need V4\+\) not implemented`)
+}
+
 func makeClient(results params.InitiateMigrationResults) (
 	*controller.Client, *jujutesting.Stub,
 ) {
@@ -229,3 +271,88 @@ func makeSpec() controller.MigrationSpec {
 func randomUUID() string {
 	return utils.MustNewUUID().String()
 }
+
+func (s *Suite) TestModelLeases(c *gc.C) {
+	modelTag := names.NewModelTag(utils.MustNewUUID().String())
+	leases := []params.LeaseDetails{{
+		Namespace:  "application-leadership",
+		Name:       "mysql",
+		Holder:     "mysql/0",
+		Extensions: 3,
+	}}
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Controller")
+		c.Check(request, gc.Equals, "ModelLeases")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: modelTag.String()}},
+		})
+		*(result.(*params.LeaseDetailsResults)) = params.LeaseDetailsResults{
+			Results: []params.LeaseDetailsResult{{Leases: leases}},
+		}
+		return nil
+	})
+	client := controller.NewClient(bestVersionCaller{apiCaller, 5})
+	result, err := client.ModelLeases(modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []params.LeaseDetailsResult{{Leases: leases}})
+}
+
+func (s *Suite) TestModelLeasesNotImplemented(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	client := controller.NewClient(bestVersionCaller{apiCaller, 4})
+	_, err := client.ModelLeases(names.NewModelTag(utils.MustNewUUID().String()))
+	c.Assert(err, gc.ErrorMatches, `ModelLeases\(\) \(need V5\+\) not implemented`)
+}
+
+func (s *Suite) TestStageCARotation(c *gc.C) {
+	called := false
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Controller")
+		c.Check(request, gc.Equals, "StageCARotation")
+		c.Check(arg, jc.DeepEquals, params.StageCARotationArgs{
+			CACert:       "ca-cert",
+			CAPrivateKey: "ca-key",
+		})
+		called = true
+		return nil
+	})
+	client := controller.NewClient(bestVersionCaller{apiCaller, 6})
+	err := client.StageCARotation("ca-cert", "ca-key")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *Suite) TestCARotationStatus(c *gc.C) {
+	status := &params.CARotationStatus{
+		Phase:          "staged",
+		CACert:         "new",
+		PreviousCACert: "old",
+	}
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Controller")
+		c.Check(request, gc.Equals, "CARotationStatus")
+		*(result.(*params.CARotationStatusResult)) = params.CARotationStatusResult{Status: status}
+		return nil
+	})
+	client := controller.NewClient(bestVersionCaller{apiCaller, 6})
+	result, err := client.CARotationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, status)
+}
+
+func (s *Suite) TestCARotationNotImplemented(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	client := controller.NewClient(bestVersionCaller{apiCaller, 5})
+	err := client.StageCARotation("ca-cert", "ca-key")
+	c.Assert(err, gc.ErrorMatches, `StageCARotation\(\) \(need V6\+\) not implemented`)
+	err = client.CutOverCARotation()
+	c.Assert(err, gc.ErrorMatches, `CutOverCARotation\(\) \(need V6\+\) not implemented`)
+	_, err = client.CARotationStatus()
+	c.Assert(err, gc.ErrorMatches, `CARotationStatus\(\) \(need V6\+\) not implemented`)
+}
@@ -12,6 +12,7 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 )
 
@@ -54,6 +55,7 @@ type TestingStateParams struct {
 	ModelTag       string
 	APIHostPorts   [][]network.HostPort
 	FacadeVersions map[string][]int
+	Deprecations   map[string][]params.FacadeDeprecation
 	ServerScheme   string
 	ServerRoot     string
 	RPCConnection  RPCConnection
@@ -74,15 +76,16 @@ func NewTestingState(params TestingStateParams) Connection {
 		modelTag = t
 	}
 	st := &state{
-		client:            params.RPCConnection,
-		clock:             params.Clock,
-		addr:              params.Address,
-		modelTag:          modelTag,
-		hostPorts:         params.APIHostPorts,
-		facadeVersions:    params.FacadeVersions,
-		serverScheme:      params.ServerScheme,
-		serverRootAddress: params.ServerRoot,
-		broken:            params.Broken,
+		client:             params.RPCConnection,
+		clock:              params.Clock,
+		addr:               params.Address,
+		modelTag:           modelTag,
+		hostPorts:          params.APIHostPorts,
+		facadeVersions:     params.FacadeVersions,
+		facadeDeprecations: params.Deprecations,
+		serverScheme:       params.ServerScheme,
+		serverRootAddress:  params.ServerRoot,
+		broken:             params.Broken,
 	}
 	return st
 }
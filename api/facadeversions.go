@@ -12,12 +12,13 @@ package api
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
 	"Action":                       2,
-	"Agent":                        2,
+	"Agent":                        3,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
-	"Annotations":                  2,
-	"Application":                  4,
+	"Annotations":                  3,
+	"APIHostPortsWatcher":          1,
+	"Application":                  5,
 	"ApplicationScaler":            1,
 	"Backups":                      1,
 	"Block":                        2,
@@ -27,7 +28,7 @@ var facadeVersions = map[string]int{
 	"Cleaner":                      2,
 	"Client":                       1,
 	"Cloud":                        1,
-	"Controller":                   3,
+	"Controller":                   6,
 	"CrossModelRelations":          1,
 	"Deployer":                     1,
 	"DiscoverSpaces":               2,
@@ -38,34 +39,35 @@ var facadeVersions = map[string]int{
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
 	"ImageManager":                 2,
-	"ImageMetadata":                2,
+	"ImageMetadata":                4,
 	"InstancePoller":               3,
 	"KeyManager":                   1,
 	"KeyUpdater":                   1,
 	"LeadershipService":            2,
 	"LifeFlag":                     1,
 	"LogForwarding":                1,
-	"Logger":                       1,
+	"Logger":                       2,
 	"MachineActions":               1,
-	"MachineManager":               3,
+	"MachineManager":               5,
 	"MachineUndertaker":            1,
 	"Machiner":                     1,
 	"MeterStatus":                  1,
 	"MetricsAdder":                 2,
 	"MetricsDebug":                 2,
+	"MongoDebug":                   1,
 	"MetricsManager":               1,
 	"MigrationFlag":                1,
 	"MigrationMaster":              1,
 	"MigrationMinion":              1,
 	"MigrationStatusWatcher":       1,
 	"MigrationTarget":              1,
-	"ModelConfig":                  1,
-	"ModelManager":                 2,
+	"ModelConfig":                  3,
+	"ModelManager":                 3,
 	"NotifyWatcher":                1,
 	"Payloads":                     1,
 	"PayloadsHookContext":          1,
 	"Pinger":                       1,
-	"Provisioner":                  3,
+	"Provisioner":                  4,
 	"ProxyUpdater":                 1,
 	"Reboot":                       2,
 	"RelationUnitsWatcher":         1,
@@ -75,6 +77,7 @@ var facadeVersions = map[string]int{
 	"ResourcesHookContext":         1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
+	"Secrets":                      1,
 	"Singular":                     1,
 	"Spaces":                       2,
 	"SSHClient":                    2,
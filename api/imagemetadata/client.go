@@ -7,7 +7,10 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/watcher"
 )
 
 // Client provides access to cloud image metadata.
@@ -91,3 +94,47 @@ func (c *Client) Delete(imageId string) error {
 	}
 	return nil
 }
+
+// Explain reports the image that would be selected when provisioning
+// a machine with the given series, architectures, region, stream and
+// constraints, together with every candidate considered in order of
+// preference. Empty values are filled in from the model.
+func (c *Client) Explain(
+	series string,
+	arches []string,
+	region, stream string,
+	cons constraints.Value,
+) (params.ImageMetadataExplainResult, error) {
+	if c.facade.BestAPIVersion() < 3 {
+		return params.ImageMetadataExplainResult{}, errors.NotImplementedf("Explain() (need V3+)")
+	}
+	in := params.ImageMetadataExplainParams{
+		Series:      series,
+		Arches:      arches,
+		Region:      region,
+		Stream:      stream,
+		Constraints: cons,
+	}
+	var out params.ImageMetadataExplainResult
+	if err := c.facade.FacadeCall("Explain", in, &out); err != nil {
+		return params.ImageMetadataExplainResult{}, errors.Trace(err)
+	}
+	return out, nil
+}
+
+// Watch returns a strings watcher that notifies of the ids of stored
+// cloud image metadata as they are added, changed or removed.
+func (c *Client) Watch() (watcher.StringsWatcher, error) {
+	if c.facade.BestAPIVersion() < 4 {
+		return nil, errors.NotImplementedf("Watch() (need V4+)")
+	}
+	var result params.StringsWatchResult
+	if err := c.facade.FacadeCall("Watch", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), result)
+	return w, nil
+}
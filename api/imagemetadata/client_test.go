@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/imagemetadata"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -355,3 +356,100 @@ func (s *imagemetadataSuite) TestDeleteFacadeCallError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, msg)
 	c.Assert(called, jc.IsTrue)
 }
+
+type bestVersionCaller struct {
+	testing.APICallerFunc
+	bestVersion int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(string) int {
+	return c.bestVersion
+}
+
+func (s *imagemetadataSuite) TestExplain(c *gc.C) {
+	called := false
+	apiCaller := testing.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "ImageMetadata")
+			c.Check(version, gc.Equals, 3)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Explain")
+			c.Check(a, jc.DeepEquals, params.ImageMetadataExplainParams{
+				Series:      "xenial",
+				Region:      "region",
+				Constraints: constraints.MustParse("arch=amd64"),
+			})
+
+			selected := params.CloudImageMetadata{ImageId: "image-1", Arch: "amd64"}
+			results := result.(*params.ImageMetadataExplainResult)
+			results.Selected = &selected
+			results.Candidates = []params.ImageMetadataCandidate{{Metadata: selected}}
+			return nil
+		})
+	client := imagemetadata.NewClient(bestVersionCaller{apiCaller, 3})
+	result, err := client.Explain("xenial", nil, "region", "", constraints.MustParse("arch=amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result.Selected, gc.NotNil)
+	c.Assert(result.Selected.ImageId, gc.Equals, "image-1")
+	c.Assert(result.Candidates, gc.HasLen, 1)
+}
+
+func (s *imagemetadataSuite) TestExplainNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		})
+	client := imagemetadata.NewClient(bestVersionCaller{apiCaller, 2})
+	_, err := client.Explain("xenial", nil, "", "", constraints.Value{})
+	c.Assert(err, gc.ErrorMatches, `Explain\(\) \(need V3\+\) not implemented`)
+}
+
+func (s *imagemetadataSuite) TestWatchError(c *gc.C) {
+	called := false
+	apiCaller := testing.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "ImageMetadata")
+			c.Check(version, gc.Equals, 4)
+			c.Check(request, gc.Equals, "Watch")
+			c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResult{})
+			*(result.(*params.StringsWatchResult)) = params.StringsWatchResult{
+				Error: &params.Error{Message: "boom"},
+			}
+			return nil
+		})
+	client := imagemetadata.NewClient(bestVersionCaller{apiCaller, 4})
+	_, err := client.Watch()
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *imagemetadataSuite) TestWatchNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		})
+	client := imagemetadata.NewClient(bestVersionCaller{apiCaller, 3})
+	_, err := client.Watch()
+	c.Assert(err, gc.ErrorMatches, `Watch\(\) \(need V4\+\) not implemented`)
+}
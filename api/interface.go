@@ -27,6 +27,7 @@ import (
 	"github.com/juju/juju/api/unitassigner"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/api/upgrader"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 )
 
@@ -217,6 +218,11 @@ type Connection interface {
 	// keeping it for now, but it's not apparently used anywhere else.
 	AllFacadeVersions() map[string][]int
 
+	// FacadeDeprecation reports whether the API server has
+	// deprecated the given version of the named facade, and if so,
+	// the details of the deprecation.
+	FacadeDeprecation(facade string, version int) (params.FacadeDeprecation, bool)
+
 	// AuthTag returns the tag of the authorized user of the state API
 	// connection.
 	AuthTag() names.Tag
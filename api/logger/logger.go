@@ -6,6 +6,7 @@ package logger
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
@@ -72,3 +73,27 @@ func (st *State) WatchLoggingConfig(agentTag names.Tag) (watcher.NotifyWatcher,
 	w := apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// AgentSettings returns the model-level agent settings for the agent
+// specified by agentTag.
+func (st *State) AgentSettings(agentTag names.Tag) (params.AgentSettings, error) {
+	if st.facade.BestAPIVersion() < 2 {
+		return params.AgentSettings{}, errors.NotImplementedf("AgentSettings() (need V2+)")
+	}
+	var results params.AgentSettingsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: agentTag.String()}},
+	}
+	err := st.facade.FacadeCall("AgentSettings", args, &results)
+	if err != nil {
+		return params.AgentSettings{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.AgentSettings{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if err := result.Error; err != nil {
+		return params.AgentSettings{}, err
+	}
+	return *result.Result, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/logger"
+	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/watcher/watchertest"
@@ -77,3 +78,23 @@ func (s *loggerSuite) TestWatchLoggingConfig(c *gc.C) {
 	s.setLoggingConfig(c, loggingConfig)
 	wc.AssertOneChange()
 }
+
+func (s *loggerSuite) TestAgentSettings(c *gc.C) {
+	err := s.BackingState.UpdateModelConfig(map[string]interface{}{
+		"agent-logging-config": "<root>=DEBUG;unit=INFO",
+		"agent-max-log-size":   50,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := s.logger.AgentSettings(s.rawMachine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.AgentSettings{
+		LoggingConfig: "<root>=DEBUG;unit=INFO",
+		MaxLogSize:    50,
+	})
+}
+
+func (s *loggerSuite) TestAgentSettingsWrongMachine(c *gc.C) {
+	_, err := s.logger.AgentSettings(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 )
 
 const machineManagerFacade = "MachineManager"
@@ -38,6 +39,61 @@ func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]pa
 	return results.Machines, err
 }
 
+// InstanceTypes returns the instance types known to the provider in
+// the model's cloud region, filtered by each of the given constraints.
+// Empty constraints match all instance types.
+func (client *Client) InstanceTypes(cons ...constraints.Value) ([]params.InstanceTypesResult, error) {
+	args := params.ModelInstanceTypesConstraints{
+		Constraints: make([]params.ModelInstanceTypesConstraint, len(cons)),
+	}
+	for i := range cons {
+		args.Constraints[i].Value = &cons[i]
+	}
+	var results params.InstanceTypesResults
+	if err := client.facade.FacadeCall("InstanceTypes", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(cons) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(cons), n)
+	}
+	return results.Results, nil
+}
+
+// EstimateCosts returns the estimated cost of an instance satisfying
+// each of the given constraints.
+func (client *Client) EstimateCosts(cons ...constraints.Value) ([]params.CostEstimateResult, error) {
+	if client.BestAPIVersion() < 4 {
+		return nil, errors.NotImplementedf("EstimateCosts() (need V4+)")
+	}
+	args := params.CostEstimateParams{Constraints: cons}
+	var results params.CostEstimateResults
+	if err := client.facade.FacadeCall("EstimateCosts", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != len(cons) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(cons), n)
+	}
+	return results.Results, nil
+}
+
+// SetTags replaces the user-defined tags on the given machine.
+func (client *Client) SetTags(machine string, tags map[string]string) error {
+	if client.BestAPIVersion() < 5 {
+		return errors.NotImplementedf("SetTags() (need V5+)")
+	}
+	args := params.SetResourceTags{
+		Entities: []params.EntityResourceTags{{
+			Tag:  names.NewMachineTag(machine).String(),
+			Tags: tags,
+		}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("SetTags", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // DestroyMachines removes a given set of machines.
 func (client *Client) DestroyMachines(machines ...string) ([]params.DestroyMachineResult, error) {
 	return client.destroyMachines("DestroyMachine", machines)
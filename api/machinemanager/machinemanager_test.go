@@ -13,6 +13,7 @@ import (
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -180,3 +181,124 @@ func (s *MachinemanagerSuite) TestDestroyMachinesInvalidIds(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+func (s *MachinemanagerSuite) TestSetTags(c *gc.C) {
+	var callCount int
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(version, gc.Equals, 5)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetTags")
+		c.Check(arg, jc.DeepEquals, params.SetResourceTags{
+			Entities: []params.EntityResourceTags{{
+				Tag:  "machine-0",
+				Tags: map[string]string{"team": "ops"},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		callCount++
+		return nil
+	})
+	client := machinemanager.NewClient(bestVersionCaller{apiCaller, 5})
+	err := client.SetTags("0", map[string]string{"team": "ops"})
+	c.Check(err, gc.ErrorMatches, "boom")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestSetTagsNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	client := machinemanager.NewClient(bestVersionCaller{apiCaller, 4})
+	err := client.SetTags("0", nil)
+	c.Check(err, gc.ErrorMatches, `SetTags\(\) \(need V5\+\) not implemented`)
+}
+
+type bestVersionCaller struct {
+	basetesting.APICallerFunc
+	bestVersion int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(string) int {
+	return c.bestVersion
+}
+
+func (s *MachinemanagerSuite) TestInstanceTypes(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	apiResult := []params.InstanceTypesResult{{
+		InstanceTypes: []params.InstanceType{{
+			Name:     "m3.medium",
+			Arches:   []string{"amd64"},
+			CPUCores: 1,
+			Memory:   3840,
+			Cost:     67,
+		}},
+		CostUnit:     "$USD/h",
+		CostDivisor:  1000,
+		CostCurrency: "USD",
+	}}
+	var callCount int
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "InstanceTypes")
+		c.Check(arg, jc.DeepEquals, params.ModelInstanceTypesConstraints{
+			Constraints: []params.ModelInstanceTypesConstraint{{Value: &cons}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.InstanceTypesResults{})
+		*(result.(*params.InstanceTypesResults)) = params.InstanceTypesResults{
+			Results: apiResult,
+		}
+		callCount++
+		return nil
+	})
+	client := newClient(apiCaller)
+	result, err := client.InstanceTypes(cons)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, apiResult)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestEstimateCosts(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	apiResult := []params.CostEstimateResult{{
+		InstanceType: "m3.medium",
+		HourlyCost:   0.067,
+		Currency:     "USD",
+	}}
+	var callCount int
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(version, gc.Equals, 4)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "EstimateCosts")
+		c.Check(arg, jc.DeepEquals, params.CostEstimateParams{
+			Constraints: []constraints.Value{cons},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.CostEstimateResults{})
+		*(result.(*params.CostEstimateResults)) = params.CostEstimateResults{
+			Results: apiResult,
+		}
+		callCount++
+		return nil
+	})
+	client := machinemanager.NewClient(bestVersionCaller{apiCaller, 4})
+	result, err := client.EstimateCosts(cons)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, apiResult)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestEstimateCostsNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	client := machinemanager.NewClient(bestVersionCaller{apiCaller, 3})
+	_, err := client.EstimateCosts(constraints.Value{})
+	c.Check(err, gc.ErrorMatches, `EstimateCosts\(\) \(need V4\+\) not implemented`)
+}
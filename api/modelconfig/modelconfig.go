@@ -4,6 +4,8 @@
 package modelconfig
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/api/base"
@@ -68,3 +70,92 @@ func (c *Client) ModelUnset(keys ...string) error {
 	args := params.ModelUnset{Keys: keys}
 	return c.facade.FacadeCall("ModelUnset", args, nil)
 }
+
+// ModelConfigDiff validates the given change to the model config,
+// without applying it, and returns the changes it would make. If the
+// change is not valid, the error describing why is returned along with
+// the changes; it is an *config.ImmutableChangeError if the change
+// alters attributes that may not be changed.
+func (c *Client) ModelConfigDiff(attrs map[string]interface{}, unset ...string) ([]config.AttrChange, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("ModelConfigDiff() (need V2+)")
+	}
+	args := params.ModelConfigDiffArgs{
+		Config: attrs,
+		Unset:  unset,
+	}
+	var result params.ModelConfigDiffResult
+	if err := c.facade.FacadeCall("ModelConfigDiff", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	changes := attrChanges(result.Changes)
+	if len(result.ImmutableViolations) > 0 {
+		return changes, &config.ImmutableChangeError{
+			Changes: immutableChanges(result.ImmutableViolations),
+		}
+	}
+	if result.Error != nil {
+		return changes, result.Error
+	}
+	return changes, nil
+}
+
+// HistoryEntry describes a change that was made to the model config.
+type HistoryEntry struct {
+	// Time is when the change was made.
+	Time time.Time
+
+	// Author is the tag of the entity that made the change, or
+	// empty if it is not known.
+	Author string
+
+	// Changes holds the attributes that were changed.
+	Changes []config.AttrChange
+}
+
+// ModelConfigHistory returns the changes that have been made to the
+// model config, most recent first. If limit is positive, no more than
+// that many changes are returned.
+func (c *Client) ModelConfigHistory(limit int) ([]HistoryEntry, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("ModelConfigHistory() (need V3+)")
+	}
+	args := params.ModelConfigHistoryArgs{Limit: limit}
+	var result params.ModelConfigHistoryResult
+	if err := c.facade.FacadeCall("ModelConfigHistory", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	entries := make([]HistoryEntry, len(result.Entries))
+	for i, entry := range result.Entries {
+		entries[i] = HistoryEntry{
+			Time:    entry.Time,
+			Author:  entry.Author,
+			Changes: attrChanges(entry.Changes),
+		}
+	}
+	return entries, nil
+}
+
+func attrChanges(in []params.ConfigChange) []config.AttrChange {
+	var out []config.AttrChange
+	for _, change := range in {
+		out = append(out, config.AttrChange{
+			Attr: change.Attr,
+			Old:  change.Old,
+			New:  change.New,
+		})
+	}
+	return out
+}
+
+func immutableChanges(in []params.ConfigChange) []config.ImmutableChange {
+	var out []config.ImmutableChange
+	for _, change := range in {
+		out = append(out, config.ImmutableChange{
+			Attr: change.Attr,
+			Old:  change.Old,
+			New:  change.New,
+		})
+	}
+	return out
+}
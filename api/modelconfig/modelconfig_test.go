@@ -4,6 +4,8 @@
 package modelconfig_test
 
 import (
+	"time"
+
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -127,3 +129,122 @@ func (s *modelconfigSuite) TestModelUnset(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+type bestVersionCaller struct {
+	basetesting.APICallerFunc
+	bestVersion int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(string) int {
+	return c.bestVersion
+}
+
+func (s *modelconfigSuite) TestModelConfigDiff(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ModelConfig")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ModelConfigDiff")
+			c.Check(a, jc.DeepEquals, params.ModelConfigDiffArgs{
+				Config: map[string]interface{}{"name": "renamed", "foo": "bar"},
+				Unset:  []string{"baz"},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ModelConfigDiffResult{})
+			*(result.(*params.ModelConfigDiffResult)) = params.ModelConfigDiffResult{
+				Changes: []params.ConfigChange{
+					{Attr: "baz", Old: "qux"},
+					{Attr: "foo", New: "bar"},
+					{Attr: "name", Old: "only", New: "renamed"},
+				},
+				ImmutableViolations: []params.ConfigChange{
+					{Attr: "name", Old: "only", New: "renamed"},
+				},
+				Error: &params.Error{Message: `cannot change name from "only" to "renamed"`},
+			}
+			return nil
+		},
+	)
+	client := modelconfig.NewClient(bestVersionCaller{apiCaller, 2})
+	changes, err := client.ModelConfigDiff(map[string]interface{}{
+		"name": "renamed",
+		"foo":  "bar",
+	}, "baz")
+	c.Assert(err, jc.Satisfies, config.IsImmutableChangeError)
+	c.Assert(err, gc.ErrorMatches, `cannot change name from "only" to "renamed"`)
+	c.Assert(changes, jc.DeepEquals, []config.AttrChange{
+		{Attr: "baz", Old: "qux"},
+		{Attr: "foo", New: "bar"},
+		{Attr: "name", Old: "only", New: "renamed"},
+	})
+}
+
+func (s *modelconfigSuite) TestModelConfigDiffNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+	)
+	client := modelconfig.NewClient(bestVersionCaller{apiCaller, 1})
+	_, err := client.ModelConfigDiff(nil, "foo")
+	c.Assert(err, gc.ErrorMatches, `ModelConfigDiff\(\) \(need V2\+\) not implemented`)
+}
+
+func (s *modelconfigSuite) TestModelConfigHistory(c *gc.C) {
+	changed := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ModelConfig")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ModelConfigHistory")
+			c.Check(a, jc.DeepEquals, params.ModelConfigHistoryArgs{Limit: 5})
+			c.Assert(result, gc.FitsTypeOf, &params.ModelConfigHistoryResult{})
+			*(result.(*params.ModelConfigHistoryResult)) = params.ModelConfigHistoryResult{
+				Entries: []params.ModelConfigHistoryEntry{{
+					Time:   changed,
+					Author: "user-bob",
+					Changes: []params.ConfigChange{
+						{Attr: "foo", Old: "bar", New: "baz"},
+					},
+				}},
+			}
+			return nil
+		},
+	)
+	client := modelconfig.NewClient(bestVersionCaller{apiCaller, 3})
+	history, err := client.ModelConfigHistory(5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, []modelconfig.HistoryEntry{{
+		Time:    changed,
+		Author:  "user-bob",
+		Changes: []config.AttrChange{{Attr: "foo", Old: "bar", New: "baz"}},
+	}})
+}
+
+func (s *modelconfigSuite) TestModelConfigHistoryNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+	)
+	client := modelconfig.NewClient(bestVersionCaller{apiCaller, 2})
+	_, err := client.ModelConfigHistory(0)
+	c.Assert(err, gc.ErrorMatches, `ModelConfigHistory\(\) \(need V3\+\) not implemented`)
+}
@@ -237,6 +237,51 @@ func (c *Client) DumpModelDB(model names.ModelTag) (map[string]interface{}, erro
 	return result.Result, nil
 }
 
+// ModelQuotas returns the quotas of the specified model, and its use
+// of the resources they limit.
+func (c *Client) ModelQuotas(model names.ModelTag) (params.ModelQuotas, params.ModelQuotaUsage, error) {
+	if c.BestAPIVersion() < 3 {
+		return params.ModelQuotas{}, params.ModelQuotaUsage{}, errors.NotImplementedf("ModelQuotas")
+	}
+	var results params.ModelQuotasResults
+	entities := params.Entities{
+		Entities: []params.Entity{{Tag: model.String()}},
+	}
+	if err := c.facade.FacadeCall("ModelQuotas", entities, &results); err != nil {
+		return params.ModelQuotas{}, params.ModelQuotaUsage{}, errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return params.ModelQuotas{}, params.ModelQuotaUsage{}, errors.Errorf("unexpected result count: %d", count)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ModelQuotas{}, params.ModelQuotaUsage{}, result.Error
+	}
+	if result.Quotas == nil || result.Usage == nil {
+		return params.ModelQuotas{}, params.ModelQuotaUsage{}, errors.New("missing quotas in result")
+	}
+	return *result.Quotas, *result.Usage, nil
+}
+
+// SetModelQuotas sets the quotas of the specified model. A zero limit
+// means that the resource is not limited.
+func (c *Client) SetModelQuotas(model names.ModelTag, quotas params.ModelQuotas) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetModelQuotas")
+	}
+	var results params.ErrorResults
+	args := params.SetModelQuotas{
+		Models: []params.ModelQuotasParams{{
+			ModelTag: model.String(),
+			Quotas:   quotas,
+		}},
+	}
+	if err := c.facade.FacadeCall("SetModelQuotas", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // DestroyModel puts the specified model into a "dying" state, which will
 // cause the model's resources to be cleaned up, after which the model will
 // be removed.
@@ -4,6 +4,7 @@
 package modelmanager_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -335,3 +336,75 @@ func (s *dumpModelSuite) TestDumpModelDBError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "fake error")
 	c.Assert(out, gc.IsNil)
 }
+
+type modelQuotasSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&modelQuotasSuite{})
+
+type bestVersionCaller struct {
+	basetesting.APICallerFunc
+	bestVersion int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(string) int {
+	return c.bestVersion
+}
+
+func (s *modelQuotasSuite) TestModelQuotas(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "ModelQuotas")
+			c.Check(args, jc.DeepEquals, params.Entities{[]params.Entity{{testing.ModelTag.String()}}})
+			*(result.(*params.ModelQuotasResults)) = params.ModelQuotasResults{
+				Results: []params.ModelQuotasResult{{
+					Quotas: &params.ModelQuotas{MaxMachines: 3},
+					Usage:  &params.ModelQuotaUsage{Machines: 1, Units: 2},
+				}},
+			}
+			return nil
+		})
+	client := modelmanager.NewClient(bestVersionCaller{apiCaller, 3})
+	quotas, usage, err := client.ModelQuotas(testing.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, params.ModelQuotas{MaxMachines: 3})
+	c.Assert(usage, gc.Equals, params.ModelQuotaUsage{Machines: 1, Units: 2})
+}
+
+func (s *modelQuotasSuite) TestSetModelQuotas(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "SetModelQuotas")
+			c.Check(args, jc.DeepEquals, params.SetModelQuotas{
+				Models: []params.ModelQuotasParams{{
+					ModelTag: testing.ModelTag.String(),
+					Quotas:   params.ModelQuotas{MaxStorageGB: 50},
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+				}},
+			}
+			return nil
+		})
+	client := modelmanager.NewClient(bestVersionCaller{apiCaller, 3})
+	err := client.SetModelQuotas(testing.ModelTag, params.ModelQuotas{MaxStorageGB: 50})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *modelQuotasSuite) TestModelQuotasNotImplemented(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	client := modelmanager.NewClient(bestVersionCaller{apiCaller, 2})
+	_, _, err := client.ModelQuotas(testing.ModelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = client.SetModelQuotas(testing.ModelTag, params.ModelQuotas{})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package mongodebug provides a client for the MongoDebug facade,
+// which reports on a controller's use of mongo.
+package mongodebug
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the MongoDebug facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Client for the MongoDebug facade.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "MongoDebug")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SessionStats returns the current statistics about the controller's
+// mongo sessions and sockets.
+func (c *Client) SessionStats() (params.MongoSessionStats, error) {
+	var stats params.MongoSessionStats
+	if err := c.facade.FacadeCall("SessionStats", nil, &stats); err != nil {
+		return params.MongoSessionStats{}, errors.Trace(err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongodebug_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/mongodebug"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestSessionStats(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MongoDebug")
+		c.Check(request, gc.Equals, "SessionStats")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.MongoSessionStats)) = params.MongoSessionStats{
+			SocketsAlive: 3,
+			SocketsInUse: 2,
+		}
+		return nil
+	})
+	client := mongodebug.NewClient(apiCaller)
+	stats, err := client.SessionStats()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats, jc.DeepEquals, params.MongoSessionStats{
+		SocketsAlive: 3,
+		SocketsInUse: 2,
+	})
+}
+
+func (s *clientSuite) TestSessionStatsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	client := mongodebug.NewClient(apiCaller)
+	_, err := client.SessionStats()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
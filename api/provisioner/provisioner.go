@@ -132,6 +132,36 @@ func (st *State) MachinesWithTransientErrors() ([]*Machine, []params.StatusResul
 	return machines, results.Results, nil
 }
 
+// BulkProvisioningInfo returns the provisioning info for each of the
+// given machines, along with the tools of the given version it may be
+// started with. If arch is not empty, the tools are restricted to that
+// architecture rather than to the machine's constraints. If v is zero,
+// no tools are found.
+//
+// BulkProvisioningInfo returns an error satisfying errors.IsNotImplemented
+// if the API server does not support it.
+func (st *State) BulkProvisioningInfo(v version.Number, arch string, tags ...names.MachineTag) ([]params.BulkProvisioningInfoResult, error) {
+	if st.facade.BestAPIVersion() < 4 {
+		return nil, errors.NotImplementedf("BulkProvisioningInfo() (need V4+)")
+	}
+	args := params.BulkProvisioningInfoArgs{
+		Entities:     make([]params.Entity, len(tags)),
+		AgentVersion: v,
+		Arch:         arch,
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.BulkProvisioningInfoResults
+	if err := st.facade.FacadeCall("BulkProvisioningInfo", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(tags) {
+		return nil, errors.Errorf("expected %d results, got %d", len(tags), len(results.Results))
+	}
+	return results.Results, nil
+}
+
 // FindTools returns al ist of tools matching the specified version number and
 // series, and, arch. If arch is blank, a default will be used.
 func (st *State) FindTools(v version.Number, series string, arch string) (tools.List, error) {
@@ -455,6 +455,22 @@ func (s *provisionerSuite) TestProvisioningInfoMachineNotFound(c *gc.C) {
 	// auth tests in apiserver
 }
 
+func (s *provisionerSuite) TestBulkProvisioningInfo(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := s.provisioner.BulkProvisioningInfo(
+		jujuversion.Current, arch.HostArch(),
+		machine.Tag().(names.MachineTag), names.NewMachineTag("42"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Check(results[0].Result.Series, gc.Equals, "quantal")
+	c.Check(results[0].ToolsError, gc.IsNil)
+	c.Check(results[0].Tools, gc.Not(gc.HasLen), 0)
+	c.Check(results[1].Error, gc.ErrorMatches, "machine 42 not found")
+}
+
 func (s *provisionerSuite) TestWatchContainers(c *gc.C) {
 	apiMachine, err := s.provisioner.Machine(s.machine.Tag().(names.MachineTag))
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,108 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets provides a client for the Secrets facade, through
+// which operators manage a model's secrets and units read the secrets
+// they have been granted access to.
+package secrets
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Secrets facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Client for the Secrets facade.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "Secrets")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CreateSecret creates a secret with the given name, description and
+// initial value.
+func (c *Client) CreateSecret(name, description string, data map[string]string) error {
+	args := params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			Name:        name,
+			Description: description,
+			Data:        data,
+		}},
+	}
+	return c.oneError("CreateSecrets", args)
+}
+
+// ListSecrets describes the model's secrets, without their values.
+func (c *Client) ListSecrets() ([]params.SecretDetails, error) {
+	var results params.ListSecretsResults
+	if err := c.facade.FacadeCall("ListSecrets", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// GrantSecret allows the given application or unit to read the named
+// secret.
+func (c *Client) GrantSecret(name string, tag names.Tag) error {
+	return c.oneError("GrantSecrets", secretAccessArgs(name, tag))
+}
+
+// RevokeSecret stops the given application or unit from reading the
+// named secret.
+func (c *Client) RevokeSecret(name string, tag names.Tag) error {
+	return c.oneError("RevokeSecrets", secretAccessArgs(name, tag))
+}
+
+// RotateSecret replaces the value of the named secret.
+func (c *Client) RotateSecret(name string, data map[string]string) error {
+	args := params.RotateSecretArgs{
+		Args: []params.RotateSecretArg{{
+			Name: name,
+			Data: data,
+		}},
+	}
+	return c.oneError("RotateSecrets", args)
+}
+
+// RemoveSecret removes the named secret.
+func (c *Client) RemoveSecret(name string) error {
+	return c.oneError("RemoveSecrets", params.SecretNames{Names: []string{name}})
+}
+
+// GetSecretValues returns the latest values of the named secrets, in
+// the same order.
+func (c *Client) GetSecretValues(secretNames ...string) ([]params.SecretValueResult, error) {
+	var results params.SecretValueResults
+	args := params.SecretNames{Names: secretNames}
+	if err := c.facade.FacadeCall("GetSecretValues", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(secretNames) {
+		return nil, errors.Errorf("expected %d results, got %d", len(secretNames), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+func (c *Client) oneError(request string, args interface{}) error {
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(request, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+func secretAccessArgs(name string, tag names.Tag) params.SecretAccessArgs {
+	return params.SecretAccessArgs{
+		Args: []params.SecretAccessArg{{
+			Name: name,
+			Tag:  tag.String(),
+		}},
+	}
+}
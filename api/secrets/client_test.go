@@ -0,0 +1,120 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/secrets"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestCreateSecret(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Secrets")
+		c.Check(request, gc.Equals, "CreateSecrets")
+		c.Check(arg, jc.DeepEquals, params.CreateSecretArgs{
+			Args: []params.CreateSecretArg{{
+				Name:        "db-password",
+				Description: "database password",
+				Data:        map[string]string{"password": "sekrit"},
+			}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		called = true
+		return nil
+	})
+	client := secrets.NewClient(apiCaller)
+	err := client.CreateSecret("db-password", "database password", map[string]string{"password": "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestGrantSecretError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "GrantSecrets")
+		c.Check(arg, jc.DeepEquals, params.SecretAccessArgs{
+			Args: []params.SecretAccessArg{{
+				Name: "db-password",
+				Tag:  "application-mysql",
+			}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		return nil
+	})
+	client := secrets.NewClient(apiCaller)
+	err := client.GrantSecret("db-password", names.NewApplicationTag("mysql"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestListSecrets(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "ListSecrets")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.ListSecretsResults)) = params.ListSecretsResults{
+			Results: []params.SecretDetails{{
+				Name:     "db-password",
+				Revision: 2,
+				Readers:  []string{"application-mysql"},
+			}},
+		}
+		return nil
+	})
+	client := secrets.NewClient(apiCaller)
+	details, err := client.ListSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details, jc.DeepEquals, []params.SecretDetails{{
+		Name:     "db-password",
+		Revision: 2,
+		Readers:  []string{"application-mysql"},
+	}})
+}
+
+func (s *clientSuite) TestGetSecretValues(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "GetSecretValues")
+		c.Check(arg, jc.DeepEquals, params.SecretNames{Names: []string{"db-password", "api-key"}})
+		*(result.(*params.SecretValueResults)) = params.SecretValueResults{
+			Results: []params.SecretValueResult{{
+				Revision: 2,
+				Data:     map[string]string{"password": "sekrit"},
+			}, {
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		return nil
+	})
+	client := secrets.NewClient(apiCaller)
+	results, err := client.GetSecretValues("db-password", "api-key")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Data, jc.DeepEquals, map[string]string{"password": "sekrit"})
+	c.Assert(results[1].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *clientSuite) TestGetSecretValuesWrongCount(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return nil
+	})
+	client := secrets.NewClient(apiCaller)
+	_, err := client.GetSecretValues("db-password")
+	c.Assert(err, gc.ErrorMatches, "expected 1 results, got 0")
+}
@@ -183,8 +183,17 @@ func (st *state) setLoginResult(p loginResultParams) error {
 	st.hostPorts = hostPorts
 
 	st.facadeVersions = make(map[string][]int, len(p.facades))
+	st.facadeDeprecations = make(map[string][]params.FacadeDeprecation)
 	for _, facade := range p.facades {
 		st.facadeVersions[facade.Name] = facade.Versions
+		if len(facade.Deprecated) > 0 {
+			st.facadeDeprecations[facade.Name] = facade.Deprecated
+		}
+	}
+	for name := range facadeVersions {
+		if d, ok := st.FacadeDeprecation(name, st.BestFacadeVersion(name)); ok {
+			logger.Debugf("using %s facade version %d, deprecated since %s", name, d.Version, d.Since)
+		}
 	}
 
 	st.setLoggedIn()
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/migration"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/watcher"
 )
 
@@ -458,3 +459,62 @@ func (w *migrationStatusWatcher) loop() error {
 func (w *migrationStatusWatcher) Changes() <-chan watcher.MigrationStatus {
 	return w.out
 }
+
+// NewAPIHostPortsWatcher takes the NotifyWatcherId returned by a
+// WatchAPIHostPorts API call and returns a watcher which reports the
+// host/port addresses of the API servers each time they change.
+func NewAPIHostPortsWatcher(caller base.APICaller, watcherId string) watcher.APIHostPortsWatcher {
+	w := &apiHostPortsWatcher{
+		caller: caller,
+		id:     watcherId,
+		out:    make(chan [][]network.HostPort),
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+type apiHostPortsWatcher struct {
+	commonWatcher
+	caller base.APICaller
+	id     string
+	out    chan [][]network.HostPort
+}
+
+func (w *apiHostPortsWatcher) loop() error {
+	w.newResult = func() interface{} { return new(params.APIHostPortsResult) }
+	w.call = makeWatcherAPICaller(w.caller, "APIHostPortsWatcher", w.id)
+	w.commonWatcher.init()
+	go w.commonLoop()
+
+	for {
+		var data interface{}
+		var ok bool
+
+		select {
+		case data, ok = <-w.in:
+			if !ok {
+				// The tomb is already killed with the correct error
+				// at this point, so just return.
+				return nil
+			}
+		case <-w.tomb.Dying():
+			return nil
+		}
+
+		servers := data.(*params.APIHostPortsResult).NetworkHostsPorts()
+		select {
+		case w.out <- servers:
+		case <-w.tomb.Dying():
+			return nil
+		}
+	}
+}
+
+// Changes returns a channel that receives the host/port addresses of
+// the API servers each time they change.
+func (w *apiHostPortsWatcher) Changes() <-chan [][]network.HostPort {
+	return w.out
+}
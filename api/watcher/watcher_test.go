@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
@@ -98,6 +99,32 @@ func (s *watcherSuite) TestNotifyWatcherStopsWithPendingSend(c *gc.C) {
 	wc.AssertStops()
 }
 
+func (s *watcherSuite) TestWatchAPIHostPorts(c *gc.C) {
+	var result params.NotifyWatchResult
+	err := s.stateAPI.APICall("Machiner", s.stateAPI.BestFacadeVersion("Machiner"), "", "WatchAPIHostPorts", nil, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+
+	w := watcher.NewAPIHostPortsWatcher(s.stateAPI, result.NotifyWatcherId)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	servers := [][]network.HostPort{
+		network.NewHostPorts(1234, "0.1.2.3"),
+	}
+	err = s.State.SetAPIHostPorts(servers)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case changes := <-w.Changes():
+		c.Assert(changes, jc.DeepEquals, servers)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for API addresses")
+	}
+}
+
 func (s *watcherSuite) TestWatchUnitsKeepsEvents(c *gc.C) {
 	// Create two services, relate them, and add one unit to each - a
 	// principal and a subordinate.
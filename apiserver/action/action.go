@@ -16,6 +16,10 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Action", 2, NewActionAPI)
+	common.RequireFacadeAccess("Action", "Enqueue", permission.WriteAccess)
+	common.RequireFacadeAccess("Action", "Cancel", permission.WriteAccess)
+	common.RequireFacadeAccess("Action", "Run", permission.AdminAccess)
+	common.RequireFacadeAccess("Action", "RunOnAllMachines", permission.AdminAccess)
 }
 
 // ActionAPI implements the client API for interacting with Actions
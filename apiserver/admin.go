@@ -88,6 +88,10 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 			defer a.srv.limiter.Release()
 		}
 	}
+	if !a.srv.allowedClients.allowsLogin(a.root.remoteAddr, isUser) {
+		logger.Infof("refusing login of %q from %s", req.AuthTag, a.root.remoteAddr)
+		return fail, common.ErrAddressNotAllowed
+	}
 
 	controllerOnlyLogin := a.root.modelUUID == ""
 	controllerMachineLogin := false
@@ -205,6 +209,19 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		apiRoot = restrictRoot(apiRoot, modelFacadesOnly)
 	}
 
+	if isUser {
+		apiRoot = restrictRoot(apiRoot, methodAccessChecker(
+			common.Facades, a.root, model.ControllerTag(), model.ModelTag()))
+	}
+
+	if a.srv.rateLimit.enabled() {
+		limiter := newRequestRateLimiter(a.srv.rateLimit, a.srv.clock)
+		apiRoot = restrictRoot(apiRoot, limiter.check)
+	}
+	if a.srv.concurrencyLimit.enabled() {
+		apiRoot = limitConcurrency(apiRoot, a.srv.concurrencyLimit)
+	}
+
 	a.root.rpcConn.ServeRoot(apiRoot, serverError)
 
 	return loginResult, nil
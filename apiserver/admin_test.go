@@ -6,6 +6,7 @@ package apiserver_test
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strconv"
 	"sync"
@@ -533,6 +534,45 @@ func (s *loginSuite) TestFailedLoginDuringMaintenance(c *gc.C) {
 	checkLogin(names.NewMachineTag("99999"))
 }
 
+func (s *loginSuite) TestLoginRefusedFromDisallowedAddress(c *gc.C) {
+	_, userNet, err := net.ParseCIDR("192.0.2.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg := defaultServerConfig(c, s.State)
+	cfg.AllowedClients = apiserver.AllowedClientsConfig{
+		Users: []*net.IPNet{userNet},
+	}
+	info, srv := newServerWithConfig(c, s.State, cfg)
+	defer assertStop(c, srv)
+	info.ModelTag = s.State.ModelTag()
+
+	// Agents may still connect from anywhere, so the connection is
+	// accepted but user logins over it are refused.
+	st := s.openAPIWithoutLogin(c, info)
+	err = st.Login(s.AdminUserTag(c), "dummy-secret", "", nil)
+	c.Assert(err, gc.ErrorMatches, "connections from this address are not allowed")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeForbidden)
+}
+
+func (s *loginSuite) TestHTTPRequestRefusedFromDisallowedAddress(c *gc.C) {
+	_, allowedNet, err := net.ParseCIDR("192.0.2.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg := defaultServerConfig(c, s.State)
+	cfg.AllowedClients = apiserver.AllowedClientsConfig{
+		Users:  []*net.IPNet{allowedNet},
+		Agents: []*net.IPNet{allowedNet},
+	}
+	info, srv := newServerWithConfig(c, s.State, cfg)
+	defer assertStop(c, srv)
+
+	// Endpoints other than the API itself are refused too.
+	for _, path := range []string{"/gui-version", "/charms", "/tools", "/register", "/log"} {
+		resp, err := utils.GetNonValidatingHTTPClient().Get("https://" + info.Addrs[0] + path)
+		c.Assert(err, jc.ErrorIsNil)
+		resp.Body.Close()
+		c.Check(resp.StatusCode, gc.Equals, http.StatusForbidden, gc.Commentf("path %s", path))
+	}
+}
+
 type validationChecker func(c *gc.C, err error, st api.Connection)
 
 func (s *baseLoginSuite) checkLoginWithValidator(c *gc.C, validator apiserver.LoginValidator, checker validationChecker) {
@@ -23,6 +23,23 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Agent", 2, NewAgentAPIV2)
+	// Version 3 adds WatchControllerConfig.
+	common.RegisterStandardFacade("Agent", 3, NewAgentAPIV3)
+}
+
+// AgentAPIV3 implements the version 3 of the API provided to an agent.
+type AgentAPIV3 struct {
+	*AgentAPIV2
+}
+
+// NewAgentAPIV3 returns an object implementing version 3 of the Agent API
+// with the given authorizer representing the currently logged in client.
+func NewAgentAPIV3(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV3, error) {
+	api, err := NewAgentAPIV2(st, resources, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentAPIV3{api}, nil
 }
 
 // AgentAPIV2 implements the version 2 of the API provided to an agent.
@@ -117,6 +134,7 @@ func (api *AgentAPIV2) StateServingInfo() (result params.StateServingInfo, err e
 		StatePort:      info.StatePort,
 		Cert:           info.Cert,
 		PrivateKey:     info.PrivateKey,
+		CertChain:      info.CertChain,
 		CAPrivateKey:   info.CAPrivateKey,
 		SharedSecret:   info.SharedSecret,
 		SystemIdentity: info.SystemIdentity,
@@ -186,3 +204,18 @@ func (api *AgentAPIV2) WatchCredentials(args params.Entities) (params.NotifyWatc
 	}
 	return results, nil
 }
+
+// WatchControllerConfig returns a watcher that notifies when the
+// controller's configuration, including the CA certificate agents use
+// to verify the controllers, is changed.
+func (api *AgentAPIV3) WatchControllerConfig() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	watch := api.st.WatchControllerConfig()
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		result.Error = common.ServerError(watcher.EnsureErr(watch))
+	}
+	return result, nil
+}
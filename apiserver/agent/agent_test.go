@@ -276,3 +276,23 @@ func (s *agentSuite) TestWatchAuthError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 }
+
+func (s *agentSuite) TestWatchControllerConfig(c *gc.C) {
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.WatchControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+
+	w := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w)
+
+	// Check that the Watch has consumed the initial events ("returned" in the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.State.StageCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
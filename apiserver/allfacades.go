@@ -55,6 +55,7 @@ import (
 	_ "github.com/juju/juju/apiserver/migrationtarget" // ModelUser Write
 	_ "github.com/juju/juju/apiserver/modelconfig"     // ModelUser Write
 	_ "github.com/juju/juju/apiserver/modelmanager"    // ModelUser Write
+	_ "github.com/juju/juju/apiserver/mongodebug"      // Controller Superuser
 	_ "github.com/juju/juju/apiserver/payloads"
 	_ "github.com/juju/juju/apiserver/payloadshookcontext"
 	_ "github.com/juju/juju/apiserver/provisioner"
@@ -66,6 +67,7 @@ import (
 	_ "github.com/juju/juju/apiserver/resourceshookcontext"
 	_ "github.com/juju/juju/apiserver/resumer"
 	_ "github.com/juju/juju/apiserver/retrystrategy"
+	_ "github.com/juju/juju/apiserver/secrets" // ModelUser Write
 	_ "github.com/juju/juju/apiserver/singular"
 	_ "github.com/juju/juju/apiserver/spaces"    // ModelUser Write
 	_ "github.com/juju/juju/apiserver/sshclient" // ModelUser Write
@@ -0,0 +1,52 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net"
+)
+
+// AllowedClientsConfig holds the networks from which clients may
+// connect to the API server. An empty list of networks places no
+// restriction on the clients it applies to.
+type AllowedClientsConfig struct {
+	// Users holds the networks from which users may log in.
+	Users []*net.IPNet
+
+	// Agents holds the networks from which agents may log in.
+	Agents []*net.IPNet
+}
+
+// allowsConnection reports whether a client connecting from the given
+// address may log in as either a user or an agent. Connections from
+// other addresses are refused before any request is read.
+func (c AllowedClientsConfig) allowsConnection(remoteAddr string) bool {
+	return c.allowsLogin(remoteAddr, true) || c.allowsLogin(remoteAddr, false)
+}
+
+// allowsLogin reports whether a client connecting from the given
+// address may log in as a user, if isUser is true, or as an agent.
+func (c AllowedClientsConfig) allowsLogin(remoteAddr string, isUser bool) bool {
+	nets := c.Agents
+	if isUser {
+		nets = c.Users
+	}
+	if len(nets) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
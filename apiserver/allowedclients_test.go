@@ -0,0 +1,61 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"net"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type allowedClientsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&allowedClientsSuite{})
+
+func mustParseCIDRs(c *gc.C, cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		c.Assert(err, jc.ErrorIsNil)
+		nets[i] = n
+	}
+	return nets
+}
+
+func (s *allowedClientsSuite) TestUnrestricted(c *gc.C) {
+	var config apiserver.AllowedClientsConfig
+	c.Check(apiserver.AllowsConnection(config, "203.0.113.1:1234"), jc.IsTrue)
+	c.Check(apiserver.AllowsLogin(config, "203.0.113.1:1234", true), jc.IsTrue)
+	c.Check(apiserver.AllowsLogin(config, "203.0.113.1:1234", false), jc.IsTrue)
+}
+
+func (s *allowedClientsSuite) TestUsersAndAgents(c *gc.C) {
+	config := apiserver.AllowedClientsConfig{
+		Users:  mustParseCIDRs(c, "10.0.0.0/8", "2001:db8::/32"),
+		Agents: mustParseCIDRs(c, "192.168.1.0/24"),
+	}
+	for i, test := range []struct {
+		addr       string
+		user       bool
+		agent      bool
+		connection bool
+	}{
+		{addr: "10.1.2.3:1234", user: true, connection: true},
+		{addr: "[2001:db8::1]:1234", user: true, connection: true},
+		{addr: "192.168.1.10:1234", agent: true, connection: true},
+		{addr: "203.0.113.1:1234"},
+		{addr: "not-an-address"},
+	} {
+		c.Logf("test %d: %s", i, test.addr)
+		c.Check(apiserver.AllowsLogin(config, test.addr, true), gc.Equals, test.user)
+		c.Check(apiserver.AllowsLogin(config, test.addr, false), gc.Equals, test.agent)
+		c.Check(apiserver.AllowsConnection(config, test.addr), gc.Equals, test.connection)
+	}
+}
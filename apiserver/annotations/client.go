@@ -16,6 +16,9 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Annotations", 2, NewAPI)
+	common.RegisterStandardFacade("Annotations", 3, NewAPIv3)
+	common.RequireFacadeAccess("Annotations", "Set", permission.WriteAccess)
+	common.RequireFacadeAccess("Annotations", "SetMetadata", permission.WriteAccess)
 }
 
 var getState = func(st *state.State) annotationAccess {
@@ -35,6 +38,11 @@ type API struct {
 	authorizer facade.Authorizer
 }
 
+// APIv3 extends the annotations API with typed operator metadata.
+type APIv3 struct {
+	*API
+}
+
 // NewAPI returns a new charm annotator API facade.
 func NewAPI(
 	st *state.State,
@@ -168,3 +176,130 @@ func (api *API) setEntityAnnotations(entityTag string, annotations map[string]st
 	}
 	return api.access.SetAnnotations(entity, annotations)
 }
+
+// NewAPIv3 returns a new annotations API facade, version 3.
+func NewAPIv3(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*APIv3, error) {
+	api, err := NewAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &APIv3{api}, nil
+}
+
+// GetMetadata returns the operator metadata of the given entities.
+// Each entity is treated independently and, hence, will fail or
+// succeed independently.
+func (api *APIv3) GetMetadata(args params.Entities) params.OperatorMetadataResults {
+	results := make([]params.OperatorMetadataResult, len(args.Entities))
+	canReadErr := api.checkCanRead()
+	for i, entity := range args.Entities {
+		results[i].EntityTag = entity.Tag
+		if canReadErr != nil {
+			results[i].Error = common.ServerError(canReadErr)
+			continue
+		}
+		metadata, err := api.getEntityMetadata(entity.Tag)
+		if err != nil {
+			results[i].Error = annotateError(err, entity.Tag, "getting metadata")
+			continue
+		}
+		results[i].Metadata = metadataToParams(metadata)
+	}
+	return params.OperatorMetadataResults{Results: results}
+}
+
+// SetMetadata replaces the operator metadata of the given entities.
+// Setting empty metadata removes it.
+func (api *APIv3) SetMetadata(args params.SetOperatorMetadataArgs) params.ErrorResults {
+	results := make([]params.ErrorResult, len(args.Metadata))
+	canWriteErr := api.checkCanWrite()
+	for i, arg := range args.Metadata {
+		if canWriteErr != nil {
+			results[i].Error = common.ServerError(canWriteErr)
+			continue
+		}
+		if err := api.setEntityMetadata(arg.EntityTag, metadataFromParams(arg.Metadata)); err != nil {
+			results[i].Error = annotateError(err, arg.EntityTag, "setting metadata")
+		}
+	}
+	return params.ErrorResults{Results: results}
+}
+
+// ListMetadata returns the operator metadata of every entity in the
+// model selected by the filter.
+func (api *APIv3) ListMetadata(args params.OperatorMetadataFilter) (params.OperatorMetadataResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.OperatorMetadataResults{}, err
+	}
+	filter := state.OperatorMetadataFilter{
+		Kind:       args.Kind,
+		Owner:      args.Owner,
+		CostCenter: args.CostCenter,
+	}
+	if args.ExpiresBefore != nil {
+		filter.ExpiresBefore = *args.ExpiresBefore
+	}
+	found, err := api.access.FindOperatorMetadata(filter)
+	if err != nil {
+		return params.OperatorMetadataResults{}, errors.Trace(err)
+	}
+	results := make([]params.OperatorMetadataResult, len(found))
+	for i, f := range found {
+		results[i] = params.OperatorMetadataResult{
+			EntityTag: f.Tag.String(),
+			Metadata:  metadataToParams(f.Metadata),
+		}
+	}
+	return params.OperatorMetadataResults{Results: results}, nil
+}
+
+func (api *API) getEntityMetadata(entityTag string) (state.OperatorMetadata, error) {
+	tag, err := names.ParseTag(entityTag)
+	if err != nil {
+		return state.OperatorMetadata{}, errors.Trace(err)
+	}
+	entity, err := api.findEntity(tag)
+	if err != nil {
+		return state.OperatorMetadata{}, errors.Trace(err)
+	}
+	return api.access.OperatorMetadata(entity)
+}
+
+func (api *API) setEntityMetadata(entityTag string, metadata state.OperatorMetadata) error {
+	tag, err := names.ParseTag(entityTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	entity, err := api.findEntity(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return api.access.SetOperatorMetadata(entity, metadata)
+}
+
+func metadataToParams(m state.OperatorMetadata) params.OperatorMetadata {
+	result := params.OperatorMetadata{
+		Owner:      m.Owner,
+		CostCenter: m.CostCenter,
+	}
+	if !m.Expiry.IsZero() {
+		expiry := m.Expiry
+		result.Expiry = &expiry
+	}
+	return result
+}
+
+func metadataFromParams(m params.OperatorMetadata) state.OperatorMetadata {
+	result := state.OperatorMetadata{
+		Owner:      m.Owner,
+		CostCenter: m.CostCenter,
+	}
+	if m.Expiry != nil {
+		result.Expiry = *m.Expiry
+	}
+	return result
+}
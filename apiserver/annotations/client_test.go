@@ -5,6 +5,7 @@ package annotations_test
 
 import (
 	"fmt"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -231,6 +232,62 @@ func (s *annotationSuite) TestMultipleEntitiesAnnotations(c *gc.C) {
 	c.Assert(rGet, jc.IsTrue)
 }
 
+func (s *annotationSuite) TestOperatorMetadata(c *gc.C) {
+	api, err := annotations.NewAPIv3(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Jobs: []state.MachineJob{state.JobHostUnits},
+	})
+	wordpress := s.Factory.MakeApplication(c, nil)
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	setResults := api.SetMetadata(params.SetOperatorMetadataArgs{
+		Metadata: []params.EntityOperatorMetadata{{
+			EntityTag: machine.Tag().String(),
+			Metadata: params.OperatorMetadata{
+				Owner:      "bob",
+				CostCenter: "ops",
+				Expiry:     &expiry,
+			},
+		}, {
+			EntityTag: wordpress.Tag().String(),
+			Metadata:  params.OperatorMetadata{Owner: "mary"},
+		}, {
+			EntityTag: wordpress.Tag().String(),
+			Metadata:  params.OperatorMetadata{Owner: "not valid"},
+		}},
+	})
+	c.Assert(setResults.Results, gc.HasLen, 3)
+	c.Assert(setResults.Results[0].Error, gc.IsNil)
+	c.Assert(setResults.Results[1].Error, gc.IsNil)
+	c.Assert(setResults.Results[2].Error, gc.ErrorMatches, `.*owner "not valid" not valid`)
+
+	getResults := api.GetMetadata(params.Entities{
+		Entities: []params.Entity{{Tag: machine.Tag().String()}},
+	})
+	c.Assert(getResults, jc.DeepEquals, params.OperatorMetadataResults{
+		Results: []params.OperatorMetadataResult{{
+			EntityTag: machine.Tag().String(),
+			Metadata: params.OperatorMetadata{
+				Owner:      "bob",
+				CostCenter: "ops",
+				Expiry:     &expiry,
+			},
+		}},
+	})
+
+	listResults, err := api.ListMetadata(params.OperatorMetadataFilter{
+		Kind: names.ApplicationTagKind,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(listResults, jc.DeepEquals, params.OperatorMetadataResults{
+		Results: []params.OperatorMetadataResult{{
+			EntityTag: wordpress.Tag().String(),
+			Metadata:  params.OperatorMetadata{Owner: "mary"},
+		}},
+	})
+}
+
 func (s *annotationSuite) testSetGetEntitiesAnnotations(c *gc.C, tag names.Tag) {
 	entity := tag.String()
 	entities := []string{entity}
@@ -13,6 +13,9 @@ type annotationAccess interface {
 	FindEntity(tag names.Tag) (state.Entity, error)
 	GetAnnotations(entity state.GlobalEntity) (map[string]string, error)
 	SetAnnotations(entity state.GlobalEntity, annotations map[string]string) error
+	OperatorMetadata(entity state.GlobalEntity) (state.OperatorMetadata, error)
+	SetOperatorMetadata(entity state.GlobalEntity, metadata state.OperatorMetadata) error
+	FindOperatorMetadata(filter state.OperatorMetadataFilter) ([]state.EntityOperatorMetadata, error)
 	ModelTag() names.ModelTag
 }
 
@@ -32,6 +35,18 @@ func (s stateShim) SetAnnotations(entity state.GlobalEntity, annotations map[str
 	return s.state.SetAnnotations(entity, annotations)
 }
 
+func (s stateShim) OperatorMetadata(entity state.GlobalEntity) (state.OperatorMetadata, error) {
+	return s.state.OperatorMetadata(entity)
+}
+
+func (s stateShim) SetOperatorMetadata(entity state.GlobalEntity, metadata state.OperatorMetadata) error {
+	return s.state.SetOperatorMetadata(entity, metadata)
+}
+
+func (s stateShim) FindOperatorMetadata(filter state.OperatorMetadataFilter) ([]state.EntityOperatorMetadata, error) {
+	return s.state.FindOperatorMetadata(filter)
+}
+
 func (s stateShim) ModelTag() names.ModelTag {
 	return s.state.ModelTag()
 }
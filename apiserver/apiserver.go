@@ -24,6 +24,8 @@ import (
 	"github.com/juju/pubsub"
 	"github.com/juju/utils"
 	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/crypto/acme"
 	"golang.org/x/crypto/acme/autocert"
 	"gopkg.in/juju/names.v2"
@@ -35,6 +37,7 @@ import (
 	"github.com/juju/juju/apiserver/common/apihttp"
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/params"
+	jujucert "github.com/juju/juju/cert"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/resourceadapters"
 	"github.com/juju/juju/rpc"
@@ -50,6 +53,10 @@ var defaultHTTPMethods = []string{"GET", "POST", "HEAD", "PUT", "DELETE", "OPTIO
 // accept
 const loginRateLimit = 10
 
+// defaultShutdownTimeout is how long the server waits for outstanding
+// requests to complete when it stops, unless configured otherwise.
+const defaultShutdownTimeout = 30 * time.Second
+
 // Server holds the server side of the API.
 type Server struct {
 	tomb              tomb.Tomb
@@ -63,6 +70,9 @@ type Server struct {
 	dataDir           string
 	logDir            string
 	limiter           utils.Limiter
+	rateLimit         RateLimitConfig
+	allowedClients    AllowedClientsConfig
+	concurrencyLimit  ConcurrencyLimitConfig
 	validator         LoginValidator
 	adminAPIFactories map[int]adminAPIFactory
 	modelUUID         string
@@ -90,6 +100,14 @@ type Server struct {
 	// is to support registering the handlers underneath the
 	// "/introspection" prefix.
 	registerIntrospectionHandlers func(func(string, http.Handler))
+
+	// prometheusGatherer is the source of the metrics served at
+	// "/metrics", or nil if they are not served.
+	prometheusGatherer prometheus.Gatherer
+
+	// shutdownTimeout bounds how long connections are drained for
+	// when the server stops.
+	shutdownTimeout time.Duration
 }
 
 // LoginValidator functions are used to decide whether login requests
@@ -110,6 +128,11 @@ type ServerConfig struct {
 	Hub         *pubsub.StructuredHub
 	CertChanged <-chan params.StateServingInfo
 
+	// CertChain optionally holds the intermediate CA certificates
+	// that are served along with Cert, so that clients trusting only
+	// a root CA can verify it.
+	CertChain string
+
 	// AutocertDNSName holds the DNS name for which
 	// official TLS certificates will be obtained. If this is
 	// empty, no certificates will be requested.
@@ -133,11 +156,33 @@ type ServerConfig struct {
 	// StatePool is created by the machine agent and passed in.
 	StatePool *state.StatePool
 
+	// RateLimit holds the limits on the rate of API requests made
+	// over each connection once it has logged in.
+	RateLimit RateLimitConfig
+
+	// ConcurrencyLimit holds the limit on the number of API requests
+	// in flight at once over each connection once it has logged in.
+	ConcurrencyLimit ConcurrencyLimitConfig
+
+	// AllowedClients holds the networks from which users and agents
+	// may connect to the API.
+	AllowedClients AllowedClientsConfig
+
 	// RegisterIntrospectionHandlers is a function that will
 	// call a function with (path, http.Handler) tuples. This
 	// is to support registering the handlers underneath the
 	// "/introspection" prefix.
 	RegisterIntrospectionHandlers func(func(string, http.Handler))
+
+	// PrometheusGatherer, if non-nil, is the source of the metrics
+	// served to authorised users at "/metrics".
+	PrometheusGatherer prometheus.Gatherer
+
+	// ShutdownTimeout bounds how long the server waits, when it is
+	// stopped, for outstanding API requests to complete before it
+	// closes their connections. If zero, defaultShutdownTimeout is
+	// used.
+	ShutdownTimeout time.Duration
 }
 
 func (c *ServerConfig) Validate() error {
@@ -157,6 +202,13 @@ func (c *ServerConfig) Validate() error {
 	return nil
 }
 
+func (c *ServerConfig) shutdownTimeout() time.Duration {
+	if c.ShutdownTimeout == 0 {
+		return defaultShutdownTimeout
+	}
+	return c.ShutdownTimeout
+}
+
 func (c *ServerConfig) pingClock() clock.Clock {
 	if c.PingClock == nil {
 		return c.Clock
@@ -195,17 +247,20 @@ func newServer(s *state.State, lis net.Listener, cfg ServerConfig) (_ *Server, e
 	}
 
 	srv := &Server{
-		clock:       cfg.Clock,
-		pingClock:   cfg.pingClock(),
-		lis:         lis,
-		newObserver: cfg.NewObserver,
-		state:       s,
-		statePool:   stPool,
-		tag:         cfg.Tag,
-		dataDir:     cfg.DataDir,
-		logDir:      cfg.LogDir,
-		limiter:     utils.NewLimiter(loginRateLimit),
-		validator:   cfg.Validator,
+		clock:            cfg.Clock,
+		pingClock:        cfg.pingClock(),
+		lis:              lis,
+		newObserver:      cfg.NewObserver,
+		state:            s,
+		statePool:        stPool,
+		tag:              cfg.Tag,
+		dataDir:          cfg.DataDir,
+		logDir:           cfg.LogDir,
+		limiter:          utils.NewLimiter(loginRateLimit),
+		rateLimit:        cfg.RateLimit,
+		allowedClients:   cfg.AllowedClients,
+		concurrencyLimit: cfg.ConcurrencyLimit,
+		validator:        cfg.Validator,
 		adminAPIFactories: map[int]adminAPIFactory{
 			3: newAdminAPIV3,
 		},
@@ -213,6 +268,8 @@ func newServer(s *state.State, lis net.Listener, cfg ServerConfig) (_ *Server, e
 		certChanged:                   cfg.CertChanged,
 		allowModelAccess:              cfg.AllowModelAccess,
 		registerIntrospectionHandlers: cfg.RegisterIntrospectionHandlers,
+		prometheusGatherer:            cfg.PrometheusGatherer,
+		shutdownTimeout:               cfg.shutdownTimeout(),
 	}
 
 	srv.tlsConfig = srv.newTLSConfig(cfg)
@@ -222,7 +279,7 @@ func newServer(s *state.State, lis net.Listener, cfg ServerConfig) (_ *Server, e
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	if err := srv.updateCertificate(cfg.Cert, cfg.Key); err != nil {
+	if err := srv.updateCertificate(jujucert.JoinChain(cfg.Cert, cfg.CertChain), cfg.Key); err != nil {
 		return nil, errors.Annotatef(err, "cannot set initial certificate")
 	}
 
@@ -370,7 +427,7 @@ func (srv *Server) run() {
 	go func() {
 		logger.Debugf("Starting API http server on address %q", srv.lis.Addr())
 		httpSrv := &http.Server{
-			Handler:   mux,
+			Handler:   srv.restrictClients(mux),
 			TLSConfig: srv.tlsConfig,
 			ErrorLog: log.New(&loggoWrapper{
 				level:  loggo.WARNING,
@@ -574,6 +631,14 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		}
 		srv.registerIntrospectionHandlers(handle)
 	}
+	if srv.prometheusGatherer != nil {
+		// The metrics are also served under "/introspection", but
+		// monitoring systems conventionally scrape "/metrics".
+		add("/metrics", introspectionHandler{
+			httpCtxt,
+			promhttp.HandlerFor(srv.prometheusGatherer, promhttp.HandlerOpts{}),
+		})
+	}
 
 	// Add HTTP handlers for local-user macaroon authentication.
 	localLoginHandlers := &localLoginHandlers{srv.authCtxt, srv.state}
@@ -647,6 +712,22 @@ func (srv *Server) trackRequests(handler http.Handler) http.Handler {
 	})
 }
 
+// restrictClients wraps a http.Handler, refusing requests from
+// addresses from which neither users nor agents may connect, so that
+// the allowed client networks apply to every endpoint.
+func (srv *Server) restrictClients(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !srv.allowedClients.allowsConnection(r.RemoteAddr) {
+			logger.Infof("refusing %s request for %q from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			if err := sendError(w, common.ErrAddressNotAllowed); err != nil {
+				logger.Errorf("%v", err)
+			}
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
 func registerEndpoint(ep apihttp.Endpoint, mux *pat.PatternServeMux) {
 	mux.Add(ep.Method, ep.Pattern, ep.Handler)
 	if ep.Method == "GET" {
@@ -671,14 +752,14 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	handler := func(conn *websocket.Conn) {
 		modelUUID := req.URL.Query().Get(":modeluuid")
 		logger.Tracef("got a request for model %q", modelUUID)
-		if err := srv.serveConn(conn, modelUUID, apiObserver, req.Host); err != nil {
+		if err := srv.serveConn(conn, modelUUID, apiObserver, req.Host, req.RemoteAddr); err != nil {
 			logger.Errorf("error serving RPCs: %v", err)
 		}
 	}
 	websocketServer(w, req, handler)
 }
 
-func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, host string) error {
+func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, host, remoteAddr string) error {
 	codec := jsoncodec.NewWebsocket(wsConn)
 	conn := rpc.NewConn(codec, apiObserver)
 
@@ -700,7 +781,7 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserv
 
 	if err == nil {
 		defer releaser()
-		h, err = newAPIHandler(srv, st, conn, modelUUID, host)
+		h, err = newAPIHandler(srv, st, conn, modelUUID, host, remoteAddr)
 	}
 
 	if err != nil {
@@ -716,6 +797,15 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserv
 	select {
 	case <-conn.Dead():
 	case <-srv.tomb.Dying():
+		// Refuse new requests and abort watchers with a retryable
+		// error, giving outstanding requests a chance to complete
+		// before the connection is closed.
+		if !conn.Drain(common.ErrServerShuttingDown, srv.clock.After(srv.shutdownTimeout)) {
+			logger.Warningf("API requests still outstanding after %v, closing connection", srv.shutdownTimeout)
+			if err := codec.Close(); err != nil {
+				logger.Debugf("error closing codec: %v", err)
+			}
+		}
 	}
 	return conn.Close()
 }
@@ -772,7 +862,7 @@ func (srv *Server) processCertChanges() error {
 				break
 			}
 			logger.Infof("received API server certificate")
-			if err := srv.updateCertificate(info.Cert, info.PrivateKey); err != nil {
+			if err := srv.updateCertificate(jujucert.JoinChain(info.Cert, info.CertChain), info.PrivateKey); err != nil {
 				logger.Errorf("cannot update certificate: %v", err)
 			}
 		case <-srv.tomb.Dying():
@@ -782,7 +872,8 @@ func (srv *Server) processCertChanges() error {
 }
 
 // updateCertificate updates the current CA certificate and key
-// from the given cert and key.
+// from the given cert and key. The cert may be followed by any
+// intermediate CA certificates to serve with it.
 func (srv *Server) updateCertificate(cert, key string) error {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
@@ -43,6 +43,26 @@ func init() {
 	// methods, superseding the existing DestroyUnits and
 	// Destroy methods respectively.
 	common.RegisterStandardFacade("Application", 4, newAPI)
+	// Version 5 adds SetTags.
+	common.RegisterStandardFacade("Application", 5, newAPI)
+	common.RequireFacadeAccess("Application", "SetMetricCredentials", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "SetTags", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Deploy", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Update", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "SetCharm", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Set", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Unset", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Expose", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Unexpose", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "AddUnits", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "DestroyUnits", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "DestroyUnit", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Destroy", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "DestroyApplication", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "SetConstraints", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "AddRelation", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "Consume", permission.WriteAccess)
+	common.RequireFacadeAccess("Application", "DestroyRelation", permission.WriteAccess)
 }
 
 // API implements the application interface and is the concrete
@@ -158,6 +178,35 @@ func (api *API) SetMetricCredentials(args params.ApplicationMetricCredentials) (
 	return result, nil
 }
 
+// SetTags replaces the user-defined tags on each of the given
+// applications.
+func (api *API) SetTags(args params.SetResourceTags) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Entities {
+		applicationTag, err := names.ParseApplicationTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		application, err := api.backend.Application(applicationTag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = application.SetTags(arg.Tags)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // Deploy fetches the charms from the charm store and deploys them
 // using the specified placement directives.
 func (api *API) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, error) {
@@ -193,6 +193,41 @@ func (s *ApplicationSuite) TestBlockRemoveDestroyRelation(c *gc.C) {
 	s.relation.CheckNoCalls(c)
 }
 
+func (s *ApplicationSuite) TestSetTags(c *gc.C) {
+	s.application.SetErrors(errors.New("boom"))
+	results, err := s.api.SetTags(params.SetResourceTags{
+		Entities: []params.EntityResourceTags{
+			{Tag: "application-foo", Tags: map[string]string{"team": "ops"}},
+			{Tag: "unit-foo-0"},
+			{Tag: "application-bar"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: "boom"}},
+			{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+			{},
+		},
+	})
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+	s.backend.CheckCallNames(c, "ModelTag", "Application", "Application")
+	s.application.CheckCalls(c, []testing.StubCall{
+		{"SetTags", []interface{}{map[string]string{"team": "ops"}}},
+		{"SetTags", []interface{}{map[string]string(nil)}},
+	})
+}
+
+func (s *ApplicationSuite) TestBlockChangesSetTags(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("foo"))
+	_, err := s.api.SetTags(params.SetResourceTags{
+		Entities: []params.EntityResourceTags{{Tag: "application-foo"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "foo")
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+	s.application.CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestDestroyApplication(c *gc.C) {
 	results, err := s.api.DestroyApplication(params.Entities{
 		Entities: []params.Entity{
@@ -380,6 +415,11 @@ func (a *mockApplication) Destroy() error {
 	return a.NextErr()
 }
 
+func (a *mockApplication) SetTags(tags map[string]string) error {
+	a.MethodCall(a, "SetTags", tags)
+	return a.NextErr()
+}
+
 type mockCharm struct {
 	application.Charm
 	testing.Stub
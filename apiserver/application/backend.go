@@ -67,6 +67,7 @@ type Application interface {
 	SetExposed() error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
+	SetTags(map[string]string) error
 	UpdateConfigSettings(charm.Settings) error
 }
 
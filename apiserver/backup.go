@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/httpattachment"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 )
@@ -53,7 +54,7 @@ func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		logger.Infof("backups download request successful for %q", id)
 	case "PUT":
 		logger.Infof("handling backups upload request")
-		id, err := h.upload(backups, resp, req)
+		id, err := h.upload(st, backups, resp, req)
 		if err != nil {
 			h.sendError(resp, err)
 			return
@@ -81,11 +82,14 @@ func (h *backupHandler) download(backups backups.Backups, resp http.ResponseWrit
 	return args.ID, err
 }
 
-func (h *backupHandler) upload(backups backups.Backups, resp http.ResponseWriter, req *http.Request) (string, error) {
+func (h *backupHandler) upload(st *state.State, backups backups.Backups, resp http.ResponseWriter, req *http.Request) (string, error) {
 	// Since we want to stream the archive in we cannot simply use
 	// mime/multipart directly.
 	defer req.Body.Close()
 
+	if err := limitUpload(req, st, controller.Config.BackupUploadMaxSize); err != nil {
+		return "", errors.Trace(err)
+	}
 	var metaResult params.BackupsMetadataResult
 	archive, err := httpattachment.Get(req, &metaResult)
 	if err != nil {
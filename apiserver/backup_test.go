@@ -108,7 +108,7 @@ func (s *backupsSuite) TestAuthRequiresClientNotMachine(c *gc.C) {
 		url:      s.backupURL(c),
 		nonce:    "fake_nonce",
 	})
-	s.assertErrorResponse(c, resp, http.StatusInternalServerError, "tag kind machine not valid")
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "tag kind machine not valid")
 
 	// Now try a user login.
 	resp = s.authRequest(c, httpRequestParams{method: "POST", url: s.backupURL(c)})
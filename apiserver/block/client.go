@@ -15,6 +15,8 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Block", 2, NewAPI)
+	common.RequireFacadeAccess("Block", "SwitchBlockOn", permission.WriteAccess)
+	common.RequireFacadeAccess("Block", "SwitchBlockOff", permission.WriteAccess)
 }
 
 // Block defines the methods on the block API end point.
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/apiserver/application"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
 )
@@ -222,6 +223,9 @@ func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.UR
 		return nil, errors.BadRequestf("expected Content-Type: application/zip, got: %v", contentType)
 	}
 
+	if err := limitUpload(r, st, controller.Config.CharmUploadMaxSize); err != nil {
+		return nil, errors.Trace(err)
+	}
 	charmFileName, err := writeCharmToTempFile(r.Body)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -24,6 +24,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
@@ -145,7 +146,7 @@ func (s *charmsSuite) TestPOSTRequiresUserAuth(c *gc.C) {
 		nonce:       "noncy",
 		contentType: "foo/bar",
 	})
-	s.assertErrorResponse(c, resp, http.StatusInternalServerError, ".*tag kind machine not valid$")
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, ".*tag kind machine not valid$")
 
 	// Now try a user login.
 	resp = s.authRequest(c, httpRequestParams{method: "POST", url: s.charmsURI(c, "")})
@@ -808,3 +809,31 @@ func charmsBakeryGetError(resp *http.Response) error {
 		},
 	}
 }
+
+type charmsUploadLimitSuite struct {
+	charmsCommonSuite
+}
+
+var _ = gc.Suite(&charmsUploadLimitSuite{})
+
+func (s *charmsUploadLimitSuite) SetUpTest(c *gc.C) {
+	s.ControllerConfigAttrs = map[string]interface{}{
+		controller.CharmUploadMaxSizeKey: "1M",
+	}
+	s.charmsCommonSuite.SetUpTest(c)
+}
+
+func (s *charmsUploadLimitSuite) TestUploadWithinLimit(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	resp := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), "application/zip", ch.Path)
+	s.assertUploadResponse(c, resp, "local:quantal/dummy-1")
+}
+
+func (s *charmsUploadLimitSuite) TestUploadTooLarge(c *gc.C) {
+	archivePath := filepath.Join(c.MkDir(), "charm.zip")
+	err := ioutil.WriteFile(archivePath, make([]byte, 2*1024*1024), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), "application/zip", archivePath)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, ".*upload exceeds the limit of 1048576 bytes$")
+}
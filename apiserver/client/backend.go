@@ -66,6 +66,7 @@ type Backend interface {
 	ModelConstraints() (constraints.Value, error)
 	ModelTag() names.ModelTag
 	ModelUUID() string
+	ReadSecondaryPreferred() (Backend, func())
 	RemoveUserAccess(names.UserTag, names.Tag) error
 	SetAnnotations(state.GlobalEntity, map[string]string) error
 	SetModelAgentVersion(version.Number) error
@@ -84,6 +85,14 @@ type stateShim struct {
 	*state.State
 }
 
+// ReadSecondaryPreferred returns a Backend whose queries are served
+// by a mongo secondary when one is available, and a func that must be
+// called when it is no longer needed.
+func (s stateShim) ReadSecondaryPreferred() (Backend, func()) {
+	st, closer := s.State.ReadSecondaryPreferred()
+	return stateShim{st}, closer
+}
+
 func (s stateShim) Unit(name string) (Unit, error) {
 	u, err := s.State.Unit(name)
 	if err != nil {
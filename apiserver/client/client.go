@@ -10,6 +10,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/utils/os"
 	"github.com/juju/utils/series"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/application"
@@ -31,6 +32,18 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 1, newClient)
+	common.RequireFacadeAccess("Client", "Resolved", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "SetModelConstraints", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "AddMachines", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "AddMachinesV2", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "InjectMachines", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "DestroyMachines", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "SetModelAgentVersion", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "SetDesiredAgentVersions", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "AbortCurrentUpgrade", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "AddCharm", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "AddCharmWithAuthorization", permission.WriteAccess)
+	common.RequireFacadeAccess("Client", "RetryProvisioning", permission.WriteAccess)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -350,13 +363,23 @@ func (c *Client) addOneMachine(p params.AddMachineParams) (*state.Machine, error
 		placementDirective = p.Placement.Directive
 	}
 
+	volumes, err := common.MachineVolumeParams(p.Disks)
+	if err != nil {
+		return nil, err
+	}
+	cons, err := common.MachineSpacesConstraints(p.Constraints, p.Spaces)
+	if err != nil {
+		return nil, err
+	}
+
 	jobs, err := common.StateJobs(p.Jobs)
 	if err != nil {
 		return nil, err
 	}
 	template := state.MachineTemplate{
 		Series:      p.Series,
-		Constraints: p.Constraints,
+		Constraints: cons,
+		Volumes:     volumes,
 		InstanceId:  p.InstanceId,
 		Jobs:        jobs,
 		Nonce:       p.Nonce,
@@ -381,7 +404,11 @@ func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (param
 	}
 
 	var result params.ProvisioningScriptResult
-	icfg, err := InstanceConfig(c.api.state(), args.MachineId, args.Nonce, args.DataDir)
+	var agentVersion version.Number
+	if args.AgentVersion != nil {
+		agentVersion = *args.AgentVersion
+	}
+	icfg, err := InstanceConfig(c.api.state(), args.MachineId, args.Nonce, args.DataDir, agentVersion)
 	if err != nil {
 		return result, common.ServerError(errors.Annotate(
 			err, "getting instance config",
@@ -546,6 +573,48 @@ func (c *Client) SetModelAgentVersion(args params.SetModelAgentVersion) error {
 	return c.api.stateAccessor.SetModelAgentVersion(args.Version)
 }
 
+// SetDesiredAgentVersions stages an upgrade on the given machines, or on
+// the machines hosting the given applications' units, ahead of the
+// model's agent-version. A zero version clears the staged upgrade.
+func (c *Client) SetDesiredAgentVersions(args params.SetDesiredAgentVersions) (params.ErrorResults, error) {
+	if err := c.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		err := c.setDesiredAgentVersion(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (c *Client) setDesiredAgentVersion(arg params.DesiredAgentVersion) error {
+	tag, err := names.ParseTag(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch tag := tag.(type) {
+	case names.MachineTag:
+		machine, err := c.api.stateAccessor.Machine(tag.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return machine.SetDesiredAgentVersion(arg.Version)
+	case names.ApplicationTag:
+		application, err := c.api.stateAccessor.Application(tag.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return application.SetDesiredAgentVersion(arg.Version)
+	}
+	return errors.NotValidf("tag %q", arg.Tag)
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
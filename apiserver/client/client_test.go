@@ -41,6 +41,7 @@ import (
 	"github.com/juju/juju/state/presence"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 	jujuversion "github.com/juju/juju/version"
@@ -241,6 +242,41 @@ func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	s.assertModelVersion(c, s.State, "9.8.7")
 }
 
+func (s *serverSuite) TestSetDesiredAgentVersions(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	unitMachineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.client.SetDesiredAgentVersions(params.SetDesiredAgentVersions{
+		Entities: []params.DesiredAgentVersion{
+			{Tag: machine.Tag().String(), Version: version.MustParse("9.8.7")},
+			{Tag: application.Tag().String(), Version: version.MustParse("9.8.6")},
+			{Tag: "unit-foo-0", Version: version.MustParse("9.8.7")},
+			{Tag: "machine-42", Version: version.MustParse("9.8.7")},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.IsNil)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `tag "unit-foo-0" not valid`)
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `machine 42 not found`)
+
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	v, ok := machine.DesiredAgentVersion()
+	c.Check(ok, jc.IsTrue)
+	c.Check(v, gc.Equals, version.MustParse("9.8.7"))
+
+	unitMachine, err := s.State.Machine(unitMachineId)
+	c.Assert(err, jc.ErrorIsNil)
+	v, ok = unitMachine.DesiredAgentVersion()
+	c.Check(ok, jc.IsTrue)
+	c.Check(v, gc.Equals, version.MustParse("9.8.6"))
+}
+
 func (s *serverSuite) makeMigratingModel(c *gc.C, name string, mode state.MigrationMode) {
 	otherSt := s.Factory.MakeModel(c, &factory.ModelParams{
 		Name:  name,
@@ -996,6 +1032,30 @@ func (s *clientSuite) TestClientAddMachinesWithPlacement(c *gc.C) {
 	c.Assert(m.Placement(), gc.DeepEquals, apiParams[3].Placement.Directive)
 }
 
+func (s *clientSuite) TestClientAddMachinesWithDisksAndSpaces(c *gc.C) {
+	apiParams := []params.AddMachineParams{{
+		Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Constraints: constraints.MustParse("mem=4G spaces=db"),
+		Disks:       []storage.Constraints{{Pool: "loop", Size: 1024, Count: 2}},
+		Spaces:      []string{"db", "public"},
+	}, {
+		Jobs:   []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Spaces: []string{"-invalid"},
+	}}
+	machines, err := s.APIState.Client().AddMachines(apiParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 2)
+	c.Assert(machines[0].Error, gc.IsNil)
+	s.checkMachine(c, machines[0].Machine, series.LatestLts(), "mem=4096M spaces=db,public")
+	c.Assert(machines[1].Error, gc.ErrorMatches, `space name "-invalid" not valid`)
+
+	m, err := s.BackingState.Machine(machines[0].Machine)
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := m.VolumeAttachments()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 2)
+}
+
 func (s *clientSuite) TestClientAddMachinesSomeErrors(c *gc.C) {
 	// Here we check that adding a number of containers correctly handles the
 	// case that some adds succeed and others fail and report the errors
@@ -1118,7 +1178,7 @@ func (s *clientSuite) TestProvisioningScript(c *gc.C) {
 		Nonce:     apiParams.Nonce,
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	icfg, err := client.InstanceConfig(s.State, machineId, apiParams.Nonce, "")
+	icfg, err := client.InstanceConfig(s.State, machineId, apiParams.Nonce, "", version.Zero)
 	c.Assert(err, jc.ErrorIsNil)
 	provisioningScript, err := sshprovisioner.ProvisioningScript(icfg)
 	c.Assert(err, jc.ErrorIsNil)
@@ -183,6 +183,14 @@ func unitMatchExposure(u *state.Unit, patterns []string) (bool, bool, error) {
 	return matchExposure(patterns, s)
 }
 
+func unitMatchTags(u *state.Unit, patterns []string) (bool, bool, error) {
+	s, err := u.Application()
+	if err != nil {
+		return false, false, err
+	}
+	return matchTags(patterns, s.Tags())
+}
+
 func unitMatchPort(u *state.Unit, patterns []string) (bool, bool, error) {
 	portRanges, err := u.OpenedPorts()
 	if err != nil {
@@ -205,6 +213,9 @@ func buildServiceMatcherShims(s *state.Application, patterns ...string) (shims [
 	// Match on exposure.
 	shims = append(shims, func() (bool, bool, error) { return matchExposure(patterns, s) })
 
+	// Match on tags.
+	shims = append(shims, func() (bool, bool, error) { return matchTags(patterns, s.Tags()) })
+
 	// If the service has an unit instance that matches any of the
 	// given criteria, consider the service a match as well.
 	unitShims, err := buildShimsForUnit(s.AllUnits, patterns...)
@@ -254,6 +265,10 @@ func buildMachineMatcherShims(m *state.Machine, patterns []string) (shims []clos
 	}
 	shims = append(shims, func() (bool, bool, error) { return matchSubnet(patterns, addrs...) })
 
+	// Look at machine tags.
+	machineTags := m.Tags()
+	shims = append(shims, func() (bool, bool, error) { return matchTags(patterns, machineTags) })
+
 	// Units may be able to match the pattern. Ultimately defer to
 	// that logic, and guard against breaking the predicate-chain.
 	shims = append(shims, func() (bool, bool, error) { return false, true, nil })
@@ -270,6 +285,7 @@ func buildUnitMatcherShims(u *state.Unit, patterns []string) []closurePredicate
 		closeOver(unitMatchAgentStatus),
 		closeOver(unitMatchWorkloadStatus),
 		closeOver(unitMatchExposure),
+		closeOver(unitMatchTags),
 		closeOver(unitMatchPort),
 	}
 }
@@ -307,6 +323,30 @@ func matchSubnet(patterns []string, addresses ...string) (bool, bool, error) {
 	return false, oneValidPattern, nil
 }
 
+// tagPatternPrefix introduces a pattern that matches machines and
+// applications by their tags: "tag:key=value" matches those with the
+// tag set to value, and "tag:key" those with the tag set at all.
+const tagPatternPrefix = "tag:"
+
+func matchTags(patterns []string, entityTags map[string]string) (bool, bool, error) {
+	oneValidPattern := false
+	for _, p := range patterns {
+		if !strings.HasPrefix(p, tagPatternPrefix) {
+			continue
+		}
+		oneValidPattern = true
+		key, value := strings.TrimPrefix(p, tagPatternPrefix), ""
+		hasValue := false
+		if i := strings.Index(key, "="); i >= 0 {
+			key, value, hasValue = key[:i], key[i+1:], true
+		}
+		if v, ok := entityTags[key]; ok && (!hasValue || v == value) {
+			return true, true, nil
+		}
+	}
+	return false, oneValidPattern, nil
+}
+
 func matchExposure(patterns []string, s *state.Application) (bool, bool, error) {
 	if len(patterns) >= 1 && patterns[0] == "exposed" {
 		return s.IsExposed(), true, nil
@@ -8,21 +8,26 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
+	"github.com/juju/version"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/controller/authentication"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
 )
 
 // InstanceConfig returns information from the environment config that
 // is needed for machine cloud-init (for non-controllers only). It
-// is exposed for testing purposes.
+// is exposed for testing purposes. If agentVersion is not zero, the
+// machine is given tools of that version rather than the model's
+// agent-version, and it is recorded as the machine's desired agent
+// version so that the machine's agent keeps running it.
 // TODO(rog) fix environs/manual tests so they do not need to call this, or move this elsewhere.
-func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instancecfg.InstanceConfig, error) {
+func InstanceConfig(st *state.State, machineId, nonce, dataDir string, agentVersion version.Number) (*instancecfg.InstanceConfig, error) {
 	modelConfig, err := st.ModelConfig()
 	if err != nil {
 		return nil, errors.Annotate(err, "getting model config")
@@ -44,9 +49,13 @@ func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instanc
 	}
 
 	// Find the appropriate tools information.
-	agentVersion, ok := modelConfig.AgentVersion()
-	if !ok {
-		return nil, errors.New("no agent version set in model configuration")
+	overrideVersion := agentVersion != version.Zero
+	if !overrideVersion {
+		var ok bool
+		agentVersion, ok = modelConfig.AgentVersion()
+		if !ok {
+			return nil, errors.New("no agent version set in model configuration")
+		}
 	}
 	environment, err := st.Model()
 	if err != nil {
@@ -75,19 +84,23 @@ func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instanc
 	if err != nil {
 		return nil, errors.Annotate(err, "getting API addresses")
 	}
-	apiAddrs := make(set.Strings)
-	for _, hostPorts := range apiHostPorts {
-		for _, hp := range hostPorts {
-			apiAddrs.Add(hp.NetAddr())
-		}
-	}
 	apiInfo := &api.Info{
-		Addrs:    apiAddrs.SortedValues(),
+		Addrs:    prioritizedAPIAddrs(apiHostPorts),
 		CACert:   st.CACert(),
 		ModelTag: st.ModelTag(),
 	}
 
-	auth := authentication.NewAuthenticator(st.MongoConnectionInfo(), apiInfo)
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting controller config")
+	}
+	backend, err := authentication.GetBackend(controllerConfig.MachineAuthBackend())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Only controllers need mongo connection info, and this
+	// is never used to provision controllers.
+	auth := authentication.NewAuthenticatorWithBackend(nil, apiInfo, backend)
 	_, apiInfo, err = auth.SetupAuthentication(machine)
 	if err != nil {
 		return nil, errors.Annotate(err, "setting up machine authentication")
@@ -99,9 +112,6 @@ func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instanc
 	if err != nil {
 		return nil, errors.Annotate(err, "initializing instance config")
 	}
-	if dataDir != "" {
-		icfg.DataDir = dataDir
-	}
 	if err := icfg.SetTools(toolsList); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -109,5 +119,33 @@ func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instanc
 	if err != nil {
 		return nil, errors.Annotate(err, "finishing instance config")
 	}
+	// An explicitly requested data directory takes precedence
+	// over the model's agent-datadir setting.
+	if dataDir != "" {
+		icfg.DataDir = dataDir
+	}
+	if overrideVersion {
+		if err := machine.SetDesiredAgentVersion(agentVersion); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return icfg, nil
 }
+
+// prioritizedAPIAddrs flattens the API addresses of all controllers,
+// ordering them so that cloud-local addresses come first and public
+// addresses last. This ensures agents in the same cloud network as the
+// controllers do not connect over public addresses.
+func prioritizedAPIAddrs(apiHostPorts [][]network.HostPort) []string {
+	collapsed := network.UniqueHostPorts(network.CollapseHostPorts(apiHostPorts))
+	seen := make(set.Strings)
+	var addrs []string
+	for _, addr := range network.PrioritizeInternalHostPorts(collapsed, false) {
+		if addr == "" || seen.Contains(addr) {
+			continue
+		}
+		seen.Add(addr)
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
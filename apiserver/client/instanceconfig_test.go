@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
@@ -18,9 +19,11 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/binarystorage"
 	"github.com/juju/juju/state/multiwatcher"
 	jujutesting "github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
+	jujuversion "github.com/juju/juju/version"
 )
 
 type machineConfigSuite struct {
@@ -44,7 +47,7 @@ func (s *machineConfigSuite) TestMachineConfig(c *gc.C) {
 	c.Assert(len(machines), gc.Equals, 1)
 
 	machineId := machines[0].Machine
-	instanceConfig, err := client.InstanceConfig(s.State, machineId, apiParams.Nonce, "")
+	instanceConfig, err := client.InstanceConfig(s.State, machineId, apiParams.Nonce, "", version.Zero)
 	c.Assert(err, jc.ErrorIsNil)
 
 	cfg, err := s.State.ControllerConfig()
@@ -59,6 +62,31 @@ func (s *machineConfigSuite) TestMachineConfig(c *gc.C) {
 	})
 }
 
+func (s *machineConfigSuite) TestMachineConfigPrefersCloudLocalAddresses(c *gc.C) {
+	err := s.State.SetAPIHostPorts([][]network.HostPort{
+		network.NewHostPorts(17070, "8.8.8.8", "10.0.0.1"),
+		network.NewHostPorts(17070, "10.0.0.2", "8.8.4.4", "10.0.0.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	hc := instance.MustParseHardware("mem=4G arch=amd64")
+	apiParams := params.AddMachineParams{
+		Jobs:       []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		InstanceId: instance.Id("1234"),
+		Nonce:      "foo",
+		HardwareCharacteristics: hc,
+	}
+	machines, err := s.APIState.Client().AddMachines([]params.AddMachineParams{apiParams})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(machines), gc.Equals, 1)
+
+	instanceConfig, err := client.InstanceConfig(s.State, machines[0].Machine, apiParams.Nonce, "", version.Zero)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(instanceConfig.APIInfo.Addrs, gc.DeepEquals, []string{
+		"10.0.0.1:17070", "10.0.0.2:17070", "8.8.8.8:17070", "8.8.4.4:17070",
+	})
+}
+
 func (s *machineConfigSuite) TestMachineConfigNoArch(c *gc.C) {
 	apiParams := params.AddMachineParams{
 		Jobs:       []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
@@ -68,7 +96,7 @@ func (s *machineConfigSuite) TestMachineConfigNoArch(c *gc.C) {
 	machines, err := s.APIState.Client().AddMachines([]params.AddMachineParams{apiParams})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(len(machines), gc.Equals, 1)
-	_, err = client.InstanceConfig(s.State, machines[0].Machine, apiParams.Nonce, "")
+	_, err = client.InstanceConfig(s.State, machines[0].Machine, apiParams.Nonce, "", version.Zero)
 	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("arch is not set for %q", "machine-"+machines[0].Machine))
 }
 
@@ -86,6 +114,46 @@ func (s *machineConfigSuite) TestMachineConfigNoTools(c *gc.C) {
 	}
 	machines, err := s.APIState.Client().AddMachines([]params.AddMachineParams{apiParams})
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = client.InstanceConfig(s.State, machines[0].Machine, apiParams.Nonce, "")
+	_, err = client.InstanceConfig(s.State, machines[0].Machine, apiParams.Nonce, "", version.Zero)
 	c.Assert(err, gc.ErrorMatches, "finding tools: "+coretools.ErrNoMatches.Error())
 }
+
+func (s *machineConfigSuite) TestMachineConfigAgentVersionOverride(c *gc.C) {
+	hc := instance.MustParseHardware("mem=4G arch=amd64")
+	apiParams := params.AddMachineParams{
+		Jobs:       []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		InstanceId: instance.Id("1234"),
+		Nonce:      "foo",
+		HardwareCharacteristics: hc,
+	}
+	machines, err := s.APIState.Client().AddMachines([]params.AddMachineParams{apiParams})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machines[0].Machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Make tools available for a newer version than the model's.
+	canary := jujuversion.Current
+	canary.Minor++
+	storage, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	data := "canary tools"
+	err = storage.Add(strings.NewReader(data), binarystorage.Metadata{
+		Version: version.Binary{Number: canary, Series: machine.Series(), Arch: "amd64"}.String(),
+		Size:    int64(len(data)),
+		SHA256:  "d82d5c8d89ef06b4b2d6fd2a4a9f4b3a5a4d68e7bcbb1a2d0a7f4e5b4f54a9e0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	instanceConfig, err := client.InstanceConfig(s.State, machine.Id(), apiParams.Nonce, "", canary)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceConfig.AgentVersion().Number, gc.Equals, canary)
+
+	// The override is recorded, so that the machine's agent is not
+	// moved to the model's agent-version.
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	desired, ok := machine.DesiredAgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(desired, gc.Equals, canary)
+}
@@ -150,15 +150,28 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 			}
 		}
 
-		if err == nil {
-			sort.Sort(byTime(hist))
-		}
-
-		results.Results = append(results.Results,
-			params.StatusHistoryResult{
-				History: params.History{Statuses: hist},
-				Error:   common.ServerError(errors.Annotatef(err, "fetching status history for %q", request.Tag)),
+		if err != nil {
+			results.Results = append(results.Results, params.StatusHistoryResult{
+				Error: common.ServerError(errors.Annotatef(err, "fetching status history for %q", request.Tag)),
 			})
+			continue
+		}
+		sort.Stable(byTime(hist))
+
+		result := params.StatusHistoryResult{}
+		if request.Pagination != nil {
+			start, end, page, err := common.Paginate(len(hist), *request.Pagination)
+			if err != nil {
+				results.Results = append(results.Results, params.StatusHistoryResult{
+					Error: common.ServerError(err),
+				})
+				continue
+			}
+			hist = hist[start:end]
+			result.Pagination = &page
+		}
+		result.History = params.History{Statuses: hist}
+		results.Results = append(results.Results, result)
 	}
 	return results
 }
@@ -169,32 +182,37 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		return params.FullStatus{}, err
 	}
 
+	// Gathering the status of a large model reads a great many
+	// documents, so spare the primary where possible.
+	backend, closeBackend := c.api.stateAccessor.ReadSecondaryPreferred()
+	defer closeBackend()
+
 	var noStatus params.FullStatus
 	var context statusContext
 	var err error
 	if context.applications, context.units, context.latestCharms, err =
-		fetchAllApplicationsAndUnits(c.api.stateAccessor, len(args.Patterns) <= 0); err != nil {
+		fetchAllApplicationsAndUnits(backend, len(args.Patterns) <= 0); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch applications and units")
 	}
 	if featureflag.Enabled(feature.CrossModelRelations) {
 		if context.remoteApplications, err =
-			fetchRemoteApplications(c.api.stateAccessor); err != nil {
+			fetchRemoteApplications(backend); err != nil {
 			return noStatus, errors.Annotate(err, "could not fetch remote applications")
 		}
 	}
-	if context.machines, err = fetchMachines(c.api.stateAccessor, nil); err != nil {
+	if context.machines, err = fetchMachines(backend, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
 	}
 	// These may be empty when machines have not finished deployment.
 	if context.ipAddresses, context.spaces, context.linkLayerDevices, err =
-		fetchNetworkInterfaces(c.api.stateAccessor); err != nil {
+		fetchNetworkInterfaces(backend); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch IP addresses and link layer devices")
 	}
-	if context.relations, err = fetchRelations(c.api.stateAccessor); err != nil {
+	if context.relations, err = fetchRelations(backend); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch relations")
 	}
 	if len(context.applications) > 0 {
-		if context.leaders, err = c.api.stateAccessor.ApplicationLeaders(); err != nil {
+		if context.leaders, err = backend.ApplicationLeaders(); err != nil {
 			return noStatus, errors.Annotate(err, " could not fetch leaders")
 		}
 	}
@@ -625,6 +643,7 @@ func makeMachineStatus(
 
 	status.Series = machine.Series()
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
+	status.Tags = machine.Tags()
 	status.WantsVote = machine.WantsVote()
 	status.HasVote = machine.HasVote()
 	sInfo, err := machine.InstanceStatus()
@@ -810,6 +829,7 @@ func (context *statusContext) processApplication(application *state.Application)
 		Series:  application.Series(),
 		Exposed: application.IsExposed(),
 		Life:    processLife(application),
+		Tags:    application.Tags(),
 	}
 
 	if latestCharm, ok := context.latestCharms[*applicationCharm.URL().WithRevision(-1)]; ok && latestCharm != nil {
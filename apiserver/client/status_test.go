@@ -71,6 +71,29 @@ func (s *statusSuite) TestFullStatusUnitLeadership(c *gc.C) {
 	c.Assert(unit.Leader, jc.IsTrue)
 }
 
+func (s *statusSuite) TestFullStatusTags(c *gc.C) {
+	tagged := s.addMachine(c)
+	err := tagged.SetTags(map[string]string{"team": "web"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addMachine(c)
+	app := s.Factory.MakeApplication(c, nil)
+	err = app.SetTags(map[string]string{"team": "db"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	status, err := client.Status([]string{"tag:team=web"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Machines, gc.HasLen, 1)
+	c.Check(status.Machines[tagged.Id()].Tags, jc.DeepEquals, map[string]string{"team": "web"})
+	c.Check(status.Applications, gc.HasLen, 0)
+
+	status, err = client.Status([]string{"tag:team"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Machines, gc.HasLen, 1)
+	c.Assert(status.Applications, gc.HasLen, 1)
+	c.Check(status.Applications[app.Name()].Tags, jc.DeepEquals, map[string]string{"team": "db"})
+}
+
 var _ = gc.Suite(&statusUnitTestSuite{})
 
 type statusUnitTestSuite struct {
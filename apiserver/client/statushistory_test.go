@@ -218,6 +218,64 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	checkStatusInfo(c, h.Results[0].History.Statuses, expected)
 }
 
+func (s *statusHistoryTestSuite) TestStatusHistoryPaginated(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{
+			Status:  status.Maintenance,
+			Message: "working",
+		},
+		{
+			Status:  status.Active,
+			Message: "running",
+		},
+		{
+			Status:  status.Blocked,
+			Message: "waiting",
+		},
+	})
+	request := params.StatusHistoryRequest{
+		Tag:        "unit-unit-0",
+		Kind:       status.KindWorkload.String(),
+		Filter:     params.StatusHistoryFilter{Size: 10},
+		Pagination: &params.Pagination{Limit: 2},
+	}
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{request},
+	})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	checkStatusInfo(c, h.Results[0].History.Statuses, []status.StatusInfo{
+		s.st.unitHistory[2],
+		s.st.unitHistory[1],
+	})
+	c.Assert(h.Results[0].Pagination, gc.NotNil)
+	c.Assert(h.Results[0].Pagination.NextCursor, gc.Not(gc.Equals), "")
+
+	request.Pagination = &params.Pagination{Limit: 2, Cursor: h.Results[0].Pagination.NextCursor}
+	h = s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{request},
+	})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	checkStatusInfo(c, h.Results[0].History.Statuses, []status.StatusInfo{
+		s.st.unitHistory[0],
+	})
+	c.Assert(h.Results[0].Pagination, jc.DeepEquals, &params.PaginationResult{})
+}
+
+func (s *statusHistoryTestSuite) TestStatusHistoryInvalidCursor(c *gc.C) {
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:        "unit-unit-0",
+			Kind:       status.KindWorkload.String(),
+			Filter:     params.StatusHistoryFilter{Size: 10},
+			Pagination: &params.Pagination{Cursor: "bogus"},
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.ErrorMatches, `cursor "bogus" not valid`)
+	c.Assert(h.Results[0].Error.Code, gc.Equals, params.CodeNotValid)
+}
+
 type mockState struct {
 	client.Backend
 	unitHistory  []status.StatusInfo
@@ -6,7 +6,10 @@
 package cloud
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
+	"github.com/juju/schema"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -14,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
@@ -131,6 +135,68 @@ func cloudToParams(cloud cloud.Cloud) params.Cloud {
 	}
 }
 
+// ProviderConfigSchema returns the provider specific model config
+// attributes of the specified clouds. The defaults of secret
+// attributes are not returned.
+func (api *CloudAPI) ProviderConfigSchema(args params.Entities) (params.ProviderConfigSchemaResults, error) {
+	results := params.ProviderConfigSchemaResults{
+		Results: make([]params.ProviderConfigSchemaResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) ([]params.ConfigSchemaField, error) {
+		tag, err := names.ParseCloudTag(arg.Tag)
+		if err != nil {
+			return nil, err
+		}
+		cloud, err := api.backend.Cloud(tag.Id())
+		if err != nil {
+			return nil, err
+		}
+		s, err := config.ProviderSchema(cloud.Type)
+		if err != nil {
+			return nil, err
+		}
+		return configSchemaToParams(s), nil
+	}
+	for i, arg := range args.Entities {
+		fields, err := one(arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		} else {
+			results.Results[i].Fields = fields
+		}
+	}
+	return results, nil
+}
+
+func configSchemaToParams(s config.ProviderConfigSchema) []params.ConfigSchemaField {
+	attrNames := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		attrNames = append(attrNames, name)
+	}
+	sort.Strings(attrNames)
+	fields := make([]params.ConfigSchemaField, len(attrNames))
+	for i, name := range attrNames {
+		attr := s.Fields[name]
+		fields[i] = params.ConfigSchemaField{
+			Name:        name,
+			Type:        string(attr.Type),
+			Description: attr.Description,
+			Secret:      attr.Secret,
+			Values:      attr.Values,
+		}
+		value, ok := s.Defaults[name]
+		switch {
+		case !ok:
+			fields[i].Required = true
+		case attr.Secret || value == schema.Omit:
+			// The default is either secret or absent.
+		default:
+			fields[i].Default = value
+		}
+	}
+	return fields
+}
+
 // DefaultCloud returns the tag of the cloud that models will be
 // created in by default.
 func (api *CloudAPI) DefaultCloud() (params.StringResult, error) {
@@ -5,9 +5,11 @@ package cloud_test
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/schema"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
 
 	cloudfacade "github.com/juju/juju/apiserver/cloud"
@@ -15,6 +17,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	_ "github.com/juju/juju/provider/dummy"
 )
 
@@ -72,6 +75,74 @@ func (s *cloudSuite) TestCloud(c *gc.C) {
 	})
 }
 
+func (s *cloudSuite) TestProviderConfigSchema(c *gc.C) {
+	results, err := s.api.ProviderConfigSchema(params.Entities{
+		Entities: []params.Entity{{Tag: "cloud-my-cloud"}, {Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Cloud", []interface{}{"my-cloud"}},
+	})
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	fields := make(map[string]params.ConfigSchemaField)
+	for _, field := range results.Results[0].Fields {
+		fields[field.Name] = field
+	}
+	c.Assert(fields["broken"], jc.DeepEquals, params.ConfigSchemaField{
+		Name:        "broken",
+		Type:        "string",
+		Description: "Whitespace-separated Environ methods that should return an error when called",
+		Default:     "",
+	})
+	c.Assert(fields["controller"].Type, gc.Equals, "bool")
+	c.Assert(fields["controller"].Default, gc.Equals, false)
+	c.Assert(results.Results[1].Error, jc.DeepEquals, &params.Error{
+		Message: `"machine-0" is not a valid cloud tag`,
+	})
+}
+
+func (s *cloudSuite) TestProviderConfigSchemaDefaults(c *gc.C) {
+	err := config.RegisterProviderSchema("schema-test", config.ProviderConfigSchema{
+		Fields: environschema.Fields{
+			"api-key":     {Type: environschema.Tstring, Secret: true},
+			"region-name": {Type: environschema.Tstring},
+			"project":     {Type: environschema.Tstring},
+		},
+		Defaults: schema.Defaults{
+			"api-key": "hunter2",
+			"project": schema.Omit,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer config.UnregisterProviderSchema("schema-test")
+	s.backend.cloud.Type = "schema-test"
+
+	results, err := s.api.ProviderConfigSchema(params.Entities{
+		Entities: []params.Entity{{Tag: "cloud-my-cloud"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Fields, jc.DeepEquals, []params.ConfigSchemaField{
+		{Name: "api-key", Type: "string", Secret: true},
+		{Name: "project", Type: "string"},
+		{Name: "region-name", Type: "string", Required: true},
+	})
+}
+
+func (s *cloudSuite) TestProviderConfigSchemaUnregistered(c *gc.C) {
+	s.backend.cloud.Type = "unregistered"
+	results, err := s.api.ProviderConfigSchema(params.Entities{
+		Entities: []params.Entity{{Tag: "cloud-my-cloud"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.DeepEquals, &params.Error{
+		Message: `config schema for provider "unregistered" not found`,
+		Code:    params.CodeNotFound,
+	})
+}
+
 func (s *cloudSuite) TestClouds(c *gc.C) {
 	result, err := s.api.Clouds()
 	c.Assert(err, jc.ErrorIsNil)
@@ -5,6 +5,7 @@ package cloud
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -35,13 +36,14 @@ func (g cloudEnvironConfigGetter) CloudSpec(tag names.ModelTag) (environs.CloudS
 
 // InstanceTypes returns instance type information for the cloud and region
 // in which the current model is deployed.
-func (api *CloudAPI) InstanceTypes(cons params.CloudInstanceTypesConstraints) (params.InstanceTypesResults, error) {
-	return instanceTypes(api, environs.GetEnviron, cons)
+func (api *CloudAPI) InstanceTypes(ctx context.Context, cons params.CloudInstanceTypesConstraints) (params.InstanceTypesResults, error) {
+	return instanceTypes(ctx, api, environs.GetEnviron, cons)
 }
 
 type environGetFunc func(st environs.EnvironConfigGetter, newEnviron environs.NewEnvironFunc) (environs.Environ, error)
 
-func instanceTypes(api *CloudAPI,
+func instanceTypes(ctx context.Context,
+	api *CloudAPI,
 	environGet environGetFunc,
 	cons params.CloudInstanceTypesConstraints,
 ) (params.InstanceTypesResults, error) {
@@ -83,7 +85,7 @@ func instanceTypes(api *CloudAPI,
 		}
 
 		itCons := common.NewInstanceTypeConstraints(env, value)
-		it, err := common.InstanceTypes(itCons)
+		it, err := common.InstanceTypes(ctx, itCons)
 		if err != nil {
 			result[i] = params.InstanceTypesResult{Error: common.ServerError(err)}
 			continue
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -65,7 +66,7 @@ func (p *instanceTypesSuite) TestInstanceTypes(c *gc.C) {
 	) (environs.Environ, error) {
 		return &env, nil
 	}
-	r, err := cloud.InstanceTypes(api, fakeEnvironGet, cons)
+	r, err := cloud.InstanceTypes(context.Background(), api, fakeEnvironGet, cons)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(r.Results, gc.HasLen, 3)
 	expected := []params.InstanceTypesResult{
@@ -80,7 +81,7 @@ func (p *instanceTypesSuite) TestInstanceTypes(c *gc.C) {
 		params.InstanceTypesResult{
 			Error: &params.Error{Message: "Instances matching constraint  not found", Code: "not found"}},
 		params.InstanceTypesResult{
-			Error: &params.Error{Message: "asking gce cloud information to aws cloud not valid", Code: ""}}}
+			Error: &params.Error{Message: "asking gce cloud information to aws cloud not valid", Code: "not valid"}}}
 	c.Assert(r.Results, gc.DeepEquals, expected)
 }
 
@@ -112,7 +113,7 @@ type mockEnviron struct {
 	results map[constraints.Value]instances.InstanceTypesWithCostMetadata
 }
 
-func (m *mockEnviron) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+func (m *mockEnviron) InstanceTypes(ctx context.Context, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
 	it, ok := m.results[c]
 	if !ok {
 		return instances.InstanceTypesWithCostMetadata{}, errors.NotFoundf("Instances matching constraint %v", c)
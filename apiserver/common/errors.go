@@ -98,6 +98,11 @@ var (
 	ErrBadRequest         = errors.New("invalid request")
 	ErrTryAgain           = errors.New("try again")
 	ErrActionNotAvailable = errors.New("action no longer available")
+	ErrRateLimitExceeded  = errors.New("API request rate limit exceeded, try again")
+	ErrServerShuttingDown = errors.New("API server shutting down, try again")
+	ErrAddressNotAllowed  = errors.New("connections from this address are not allowed")
+
+	ErrConcurrencyLimitExceeded = errors.New("too many concurrent API requests, try again")
 )
 
 // OperationBlockedError returns an error which signifies that
@@ -131,6 +136,10 @@ var singletonErrorCodes = map[error]string{
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
+	ErrRateLimitExceeded:         params.CodeTryAgain,
+	ErrServerShuttingDown:        params.CodeTryAgain,
+	ErrAddressNotAllowed:         params.CodeForbidden,
+	ErrConcurrencyLimitExceeded:  params.CodeTryAgain,
 }
 
 func singletonCode(err error) (string, bool) {
@@ -170,7 +179,8 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		params.CodeUserNotFound,
 		params.CodeModelNotFound:
 		status = http.StatusNotFound
-	case params.CodeBadRequest:
+	case params.CodeBadRequest,
+		params.CodeNotValid:
 		status = http.StatusBadRequest
 	case params.CodeMethodNotAllowed:
 		status = http.StatusMethodNotAllowed
@@ -225,10 +235,16 @@ func ServerError(err error) *params.Error {
 		code = params.CodeUpgradeInProgress
 	case state.IsHasAttachmentsError(err):
 		code = params.CodeMachineHasAttachedStorage
+	case state.IsQuotaExceededError(err):
+		code = params.CodeQuotaExceeded
 	case isUnknownModelError(err):
 		code = params.CodeModelNotFound
 	case errors.IsNotSupported(err):
 		code = params.CodeNotSupported
+	case errors.IsNotValid(err):
+		code = params.CodeNotValid
+	case errors.IsNotImplemented(err):
+		code = params.CodeNotImplemented
 	case errors.IsBadRequest(err):
 		code = params.CodeBadRequest
 	case errors.IsMethodNotAllowed(err):
@@ -324,6 +340,10 @@ func RestoreError(err error) error {
 		return err
 	case params.IsCodeNotSupported(err):
 		return errors.NewNotSupported(nil, msg)
+	case params.IsCodeNotValid(err):
+		return errors.NewNotValid(nil, msg)
+	case params.IsCodeNotImplemented(err):
+		return errors.NewNotImplemented(nil, msg)
 	case params.IsBadRequest(err):
 		return errors.NewBadRequest(nil, msg)
 	case params.IsMethodNotAllowed(err):
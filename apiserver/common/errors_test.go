@@ -133,6 +133,10 @@ var errorTransformTests = []struct {
 	code:       params.CodeTryAgain,
 	status:     http.StatusInternalServerError,
 	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:    common.ErrAddressNotAllowed,
+	code:   params.CodeForbidden,
+	status: http.StatusForbidden,
 }, {
 	err:        leadership.ErrClaimDenied,
 	code:       params.CodeLeadershipClaimDenied,
@@ -153,6 +157,16 @@ var errorTransformTests = []struct {
 	code:       params.CodeNotSupported,
 	status:     http.StatusInternalServerError,
 	helperFunc: params.IsCodeNotSupported,
+}, {
+	err:        errors.NotValidf("cursor %q", "bogus"),
+	code:       params.CodeNotValid,
+	status:     http.StatusBadRequest,
+	helperFunc: params.IsCodeNotValid,
+}, {
+	err:        errors.NotImplementedf("attaching storage"),
+	code:       params.CodeNotImplemented,
+	status:     http.StatusInternalServerError,
+	helperFunc: params.IsCodeNotImplemented,
 }, {
 	err:        errors.BadRequestf("something"),
 	code:       params.CodeBadRequest,
@@ -3,7 +3,12 @@
 
 package common
 
-import "github.com/juju/juju/apiserver/facade"
+import (
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/environs"
+)
 
 var (
 	MachineJobFromParams    = machineJobFromParams
@@ -12,8 +17,17 @@ var (
 	EnvtoolsFindTools       = &envtoolsFindTools
 	SendMetrics             = &sendMetrics
 	MockableDestroyMachines = destroyMachines
+	ToolsCacheExpiry        = toolsCacheExpiry
+	SignedToolsURLExpiry    = signedToolsURLExpiry
+	ToolsURLsForList        = toolsURLsForList
 )
 
+// NewToolsFinderWithClock returns a ToolsFinder whose cache uses the
+// given clock.
+func NewToolsFinderWithClock(c environs.EnvironConfigGetter, s ToolsStorageGetter, t ToolsURLGetter, clock clock.Clock) *ToolsFinder {
+	return &ToolsFinder{c, s, t, newToolsCache(clock)}
+}
+
 type Patcher interface {
 	PatchValue(dest, value interface{})
 }
@@ -5,6 +5,7 @@ package common
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
@@ -49,9 +50,9 @@ type instanceTypeConstraints struct {
 }
 
 // InstanceTypes returns a list of the available instance types in the provider according
-// to the passed constraints.
-func InstanceTypes(cons instanceTypeConstraints) (params.InstanceTypesResult, error) {
-	instanceTypes, err := cons.environ.InstanceTypes(cons.constraints)
+// to the passed constraints. The context is passed on to the provider.
+func InstanceTypes(ctx context.Context, cons instanceTypeConstraints) (params.InstanceTypesResult, error) {
+	instanceTypes, err := cons.environ.InstanceTypes(ctx, cons.constraints)
 	if err != nil {
 		return params.InstanceTypesResult{}, errors.Trace(err)
 	}
@@ -5,12 +5,16 @@ package common
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage"
 )
 
 // StateJobs translates a slice of multiwatcher jobs to their equivalents in state.
@@ -26,6 +30,54 @@ func StateJobs(jobs []multiwatcher.MachineJob) ([]state.MachineJob, error) {
 	return newJobs, nil
 }
 
+// MachineVolumeParams returns the parameters for creating and
+// attaching the volumes described by disks to a new machine.
+func MachineVolumeParams(disks []storage.Constraints) ([]state.MachineVolumeParams, error) {
+	volumes := make([]state.MachineVolumeParams, 0, len(disks))
+	for _, cons := range disks {
+		if cons.Count == 0 {
+			return nil, errors.Errorf("invalid volume params: count not specified")
+		}
+		// Pool and Size are validated by AddMachineX.
+		volumeParams := state.VolumeParams{
+			Pool: cons.Pool,
+			Size: cons.Size,
+		}
+		volumeAttachmentParams := state.VolumeAttachmentParams{}
+		for i := uint64(0); i < cons.Count; i++ {
+			volumes = append(volumes, state.MachineVolumeParams{
+				Volume:     volumeParams,
+				Attachment: volumeAttachmentParams,
+			})
+		}
+	}
+	return volumes, nil
+}
+
+// MachineSpacesConstraints returns cons with the named spaces added to
+// the spaces the machine must have access to.
+func MachineSpacesConstraints(cons constraints.Value, spaces []string) (constraints.Value, error) {
+	if len(spaces) == 0 {
+		return cons, nil
+	}
+	var all []string
+	if cons.Spaces != nil {
+		all = append(all, *cons.Spaces...)
+	}
+	seen := set.NewStrings(all...)
+	for _, space := range spaces {
+		if !names.IsValidSpace(space) {
+			return constraints.Value{}, errors.NotValidf("space name %q", space)
+		}
+		if !seen.Contains(space) {
+			seen.Add(space)
+			all = append(all, space)
+		}
+	}
+	cons.Spaces = &all
+	return cons, nil
+}
+
 // machineJobFromParams returns the job corresponding to multiwatcher.MachineJob.
 func machineJobFromParams(job multiwatcher.MachineJob) (state.MachineJob, error) {
 	switch job {
@@ -11,10 +11,12 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage"
 )
 
 type machineSuite struct{}
@@ -46,6 +48,37 @@ func (s *machineSuite) TestMachineJobFromParams(c *gc.C) {
 	}
 }
 
+func (s *machineSuite) TestMachineVolumeParams(c *gc.C) {
+	volumes, err := common.MachineVolumeParams([]storage.Constraints{
+		{Pool: "loop", Size: 1, Count: 2},
+		{Size: 2, Count: 1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, jc.DeepEquals, []state.MachineVolumeParams{
+		{Volume: state.VolumeParams{Pool: "loop", Size: 1}},
+		{Volume: state.VolumeParams{Pool: "loop", Size: 1}},
+		{Volume: state.VolumeParams{Size: 2}},
+	})
+
+	_, err = common.MachineVolumeParams([]storage.Constraints{{Size: 1}})
+	c.Assert(err, gc.ErrorMatches, "invalid volume params: count not specified")
+}
+
+func (s *machineSuite) TestMachineSpacesConstraints(c *gc.C) {
+	cons, err := common.MachineSpacesConstraints(constraints.MustParse("mem=4G"), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+
+	cons, err = common.MachineSpacesConstraints(
+		constraints.MustParse("mem=4G spaces=db,^dmz"), []string{"public", "db"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G spaces=db,^dmz,public"))
+
+	_, err = common.MachineSpacesConstraints(constraints.Value{}, []string{"-invalid"})
+	c.Assert(err, gc.ErrorMatches, `space name "-invalid" not valid`)
+}
+
 func (s *machineSuite) TestDestroyMachines(c *gc.C) {
 	st := mockState{
 		machines: map[string]*mockMachine{
@@ -59,6 +59,9 @@ type ModelManagerBackend interface {
 	LastModelConnection(user names.UserTag) (time.Time, error)
 	LatestMigration() (state.ModelMigration, error)
 	DumpAll() (map[string]interface{}, error)
+	ModelQuotas() (state.ModelQuotas, error)
+	SetModelQuotas(state.ModelQuotas) error
+	ModelQuotaUsage() (state.ModelQuotaUsage, error)
 	Close() error
 }
 
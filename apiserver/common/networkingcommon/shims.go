@@ -7,8 +7,8 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
-	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
 )
@@ -137,11 +137,11 @@ func (s *stateShim) AllSubnets() ([]BackingSubnet, error) {
 	return subnets, nil
 }
 
-func (s *stateShim) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (s *stateShim) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	// TODO(dimitern): Fix this to get them from state when available!
 	return nil, nil
 }
 
-func (s *stateShim) SetAvailabilityZones(zones []providercommon.AvailabilityZone) error {
+func (s *stateShim) SetAvailabilityZones(zones []environs.AvailabilityZone) error {
 	return nil
 }
@@ -18,7 +18,6 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	providercommon "github.com/juju/juju/provider/common"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.common.networkingcommon")
@@ -454,7 +453,7 @@ func networkingEnviron(getter environs.EnvironConfigGetter) (environs.Networking
 func AllZones(api NetworkBacking) (params.ZoneResults, error) {
 	var results params.ZoneResults
 
-	zonesAsString := func(zones []providercommon.AvailabilityZone) string {
+	zonesAsString := func(zones []environs.AvailabilityZone) string {
 		results := make([]string, len(zones))
 		for i, zone := range zones {
 			results[i] = zone.Name()
@@ -493,7 +492,7 @@ func AllZones(api NetworkBacking) (params.ZoneResults, error) {
 // updateZones attempts to retrieve all availability zones from the environment
 // provider (if supported) and then updates the persisted list of zones in
 // state, returning them as well on success.
-func updateZones(api NetworkBacking) ([]providercommon.AvailabilityZone, error) {
+func updateZones(api NetworkBacking) ([]environs.AvailabilityZone, error) {
 	zoned, err := zonedEnviron(api)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -509,15 +508,15 @@ func updateZones(api NetworkBacking) ([]providercommon.AvailabilityZone, error)
 	return zones, nil
 }
 
-// zonedEnviron returns a providercommon.ZonedEnviron instance from the current
+// zonedEnviron returns a environs.ZonedEnviron instance from the current
 // model config. If the model does not support zones, an error satisfying
 // errors.IsNotSupported() will be returned.
-func zonedEnviron(api NetworkBacking) (providercommon.ZonedEnviron, error) {
+func zonedEnviron(api NetworkBacking) (environs.ZonedEnviron, error) {
 	env, err := environs.GetEnviron(api, environs.New)
 	if err != nil {
 		return nil, errors.Annotate(err, "opening environment")
 	}
-	if zonedEnv, ok := env.(providercommon.ZonedEnviron); ok {
+	if zonedEnv, ok := env.(environs.ZonedEnviron); ok {
 		return zonedEnv, nil
 	}
 	return nil, errors.NotSupportedf("availability zones")
@@ -13,7 +13,6 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
-	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 )
 
@@ -104,11 +103,11 @@ type NetworkBacking interface {
 
 	// AvailabilityZones returns all cached availability zones (i.e.
 	// not from the provider, but in state).
-	AvailabilityZones() ([]providercommon.AvailabilityZone, error)
+	AvailabilityZones() ([]environs.AvailabilityZone, error)
 
 	// SetAvailabilityZones replaces the cached list of availability
 	// zones with the given zones.
-	SetAvailabilityZones([]providercommon.AvailabilityZone) error
+	SetAvailabilityZones([]environs.AvailabilityZone) error
 
 	// AddSpace creates a space
 	AddSpace(Name string, ProviderId network.Id, Subnets []string, Public bool) error
@@ -0,0 +1,90 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const (
+	// DefaultPageLimit is the number of results in a page when the
+	// caller does not specify a limit.
+	DefaultPageLimit = 100
+
+	// MaxPageLimit is the largest number of results in a page. Larger
+	// limits requested by callers are reduced to it.
+	MaxPageLimit = 1000
+)
+
+// cursorPrefix marks a cursor holding an offset into a list, so that
+// cursors of other kinds may be introduced later.
+const cursorPrefix = "offset:"
+
+// PageLimit returns the number of results in the page selected by
+// args, enforcing DefaultPageLimit and MaxPageLimit.
+func PageLimit(args params.Pagination) (int, error) {
+	switch {
+	case args.Limit < 0:
+		return 0, errors.NotValidf("negative page limit %d", args.Limit)
+	case args.Limit == 0:
+		return DefaultPageLimit, nil
+	case args.Limit > MaxPageLimit:
+		return MaxPageLimit, nil
+	}
+	return args.Limit, nil
+}
+
+// EncodeCursor returns an opaque cursor identifying the given offset
+// into a list of results.
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeCursor returns the offset identified by a cursor returned by
+// EncodeCursor. The empty cursor identifies the start of the list.
+func DecodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), cursorPrefix) {
+		return 0, errors.NotValidf("cursor %q", cursor)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(data), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, errors.NotValidf("cursor %q", cursor)
+	}
+	return offset, nil
+}
+
+// Paginate returns the bounds [start, end) of the page selected by args
+// within a list of n results, and the result describing the page. The
+// list must be in the same order on every call for the pages to be
+// consistent.
+func Paginate(n int, args params.Pagination) (start, end int, result params.PaginationResult, err error) {
+	limit, err := PageLimit(args)
+	if err != nil {
+		return 0, 0, result, errors.Trace(err)
+	}
+	start, err = DecodeCursor(args.Cursor)
+	if err != nil {
+		return 0, 0, result, errors.Trace(err)
+	}
+	if start > n {
+		start = n
+	}
+	end = start + limit
+	if end < n {
+		result.NextCursor = EncodeCursor(end)
+	} else {
+		end = n
+	}
+	return start, end, result, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type paginationSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&paginationSuite{})
+
+func (s *paginationSuite) TestPageLimit(c *gc.C) {
+	for i, test := range []struct {
+		limit    int
+		expected int
+		err      string
+	}{
+		{limit: 0, expected: common.DefaultPageLimit},
+		{limit: 10, expected: 10},
+		{limit: common.MaxPageLimit + 1, expected: common.MaxPageLimit},
+		{limit: -1, err: "negative page limit -1 not valid"},
+	} {
+		c.Logf("test %d: limit %d", i, test.limit)
+		limit, err := common.PageLimit(params.Pagination{Limit: test.limit})
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(limit, gc.Equals, test.expected)
+	}
+}
+
+func (s *paginationSuite) TestCursorRoundTrip(c *gc.C) {
+	for _, offset := range []int{0, 1, 1234} {
+		decoded, err := common.DecodeCursor(common.EncodeCursor(offset))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(decoded, gc.Equals, offset)
+	}
+	offset, err := common.DecodeCursor("")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(offset, gc.Equals, 0)
+}
+
+func (s *paginationSuite) TestDecodeInvalidCursor(c *gc.C) {
+	for _, cursor := range []string{"!!!", "MTIz", common.EncodeCursor(-1)} {
+		_, err := common.DecodeCursor(cursor)
+		c.Check(err, gc.ErrorMatches, `cursor ".*" not valid`)
+	}
+}
+
+func (s *paginationSuite) TestPaginate(c *gc.C) {
+	var pages [][2]int
+	args := params.Pagination{Limit: 4}
+	for {
+		start, end, result, err := common.Paginate(10, args)
+		c.Assert(err, jc.ErrorIsNil)
+		pages = append(pages, [2]int{start, end})
+		if result.NextCursor == "" {
+			break
+		}
+		args.Cursor = result.NextCursor
+	}
+	c.Assert(pages, jc.DeepEquals, [][2]int{{0, 4}, {4, 8}, {8, 10}})
+}
+
+func (s *paginationSuite) TestPaginateExactPages(c *gc.C) {
+	start, end, result, err := common.Paginate(4, params.Pagination{Limit: 4})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(start, gc.Equals, 0)
+	c.Check(end, gc.Equals, 4)
+	c.Check(result.NextCursor, gc.Equals, "")
+}
+
+func (s *paginationSuite) TestPaginatePastEnd(c *gc.C) {
+	start, end, result, err := common.Paginate(3, params.Pagination{Cursor: common.EncodeCursor(5)})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(start, gc.Equals, 3)
+	c.Check(end, gc.Equals, 3)
+	c.Check(result.NextCursor, gc.Equals, "")
+}
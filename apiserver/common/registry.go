@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
@@ -45,6 +46,45 @@ func RegisterFacadeForFeature(name string, version int, factory facade.Factory,
 	logger.Tracef("Registered facade %q v%d", name, version)
 }
 
+// RegisterFacadeTranslation registers the given version of the named
+// facade as a translation of the next version up, which must already
+// be registered. Calls against the older version are served by the
+// newer implementation, with params and results converted as described
+// by the translation, so that adding fields to params types does not
+// require a hand-written facade for each version.
+func RegisterFacadeTranslation(name string, version int, t facade.Translation) {
+	if err := Facades.RegisterTranslation(name, version, t); err != nil {
+		// This is meant to be called during init() so errors should be
+		// considered fatal.
+		panic(err)
+	}
+	logger.Tracef("Registered facade %q v%d as translation of v%d", name, version, version+1)
+}
+
+// DeprecateFacade marks the given version of the named facade, which
+// must already be registered, as deprecated. Clients are told of the
+// deprecation when they log in.
+func DeprecateFacade(name string, version int, d facade.Deprecation) {
+	if err := Facades.Deprecate(name, version, d); err != nil {
+		// This is meant to be called during init() so errors should be
+		// considered fatal.
+		panic(err)
+	}
+	logger.Tracef("Deprecated facade %q v%d", name, version)
+}
+
+// RequireFacadeAccess records that users need the given access to call
+// the named method of the named facade, which must already be
+// registered. See facade.Registry.RequireAccess.
+func RequireFacadeAccess(name, method string, access permission.Access) {
+	if err := Facades.RequireAccess(name, method, access); err != nil {
+		// This is meant to be called during init() so errors should be
+		// considered fatal.
+		panic(err)
+	}
+	logger.Tracef("Facade method %s.%s requires %s access", name, method, access)
+}
+
 type niceFactory func(facade.Context) (interface{}, error)
 
 type nastyFactory func(
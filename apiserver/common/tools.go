@@ -5,14 +5,19 @@ package common
 
 import (
 	"fmt"
+	"path"
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/storage"
 	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -20,7 +25,7 @@ import (
 	coretools "github.com/juju/juju/tools"
 )
 
-var envtoolsFindTools = envtools.FindTools
+var envtoolsFindTools = envtools.FindToolsWithSource
 
 // ToolsURLGetter is an interface providing the ToolsURL method.
 type ToolsURLGetter interface {
@@ -125,7 +130,7 @@ func (t *ToolsGetter) oneAgentTools(canRead AuthFunc, tag names.Tag, agentVersio
 		return nil, err
 	}
 	toolsFinder := NewToolsFinder(t.configGetter, t.toolsStorageGetter, t.urlGetter)
-	list, err := toolsFinder.findTools(params.FindToolsParams{
+	list, _, err := toolsFinder.findTools(params.FindToolsParams{
 		Number:       agentVersion,
 		MajorVersion: -1,
 		MinorVersion: -1,
@@ -194,91 +199,138 @@ type ToolsFinder struct {
 	configGetter       environs.EnvironConfigGetter
 	toolsStorageGetter ToolsStorageGetter
 	urlGetter          ToolsURLGetter
+	cache              *toolsCache
 }
 
 // NewToolsFinder returns a new ToolsFinder, returning tools
-// with their URLs pointing at the API server.
+// with their URLs pointing at the API server. The results of
+// searches are cached for a short while, or until tools are
+// added to tools storage.
 func NewToolsFinder(c environs.EnvironConfigGetter, s ToolsStorageGetter, t ToolsURLGetter) *ToolsFinder {
-	return &ToolsFinder{c, s, t}
+	return &ToolsFinder{c, s, t, newToolsCache(clock.WallClock)}
 }
 
 // FindTools returns a List containing all tools matching the given parameters.
 func (f *ToolsFinder) FindTools(args params.FindToolsParams) (params.FindToolsResult, error) {
 	result := params.FindToolsResult{}
-	list, err := f.findTools(args)
+	list, sources, err := f.findTools(args)
 	if err != nil {
 		result.Error = ServerError(err)
 	} else {
 		result.List = list
+		result.Sources = sources
 	}
 	return result, nil
 }
 
 // findTools calls findMatchingTools and then rewrites the URLs
-// using the provided ToolsURLGetter.
-func (f *ToolsFinder) findTools(args params.FindToolsParams) (coretools.List, error) {
-	list, err := f.findMatchingTools(args)
+// using the provided ToolsURLGetter. The returned sources describe
+// where each of the returned tools was found.
+func (f *ToolsFinder) findTools(args params.FindToolsParams) (coretools.List, []params.ToolsSource, error) {
+	list, sources, err := f.findMatchingTools(args)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// Rewrite the URLs so they point at the API servers. If the
 	// tools are not in tools storage, then the API server will
 	// download and cache them if the client requests that version.
+	allURLs, err := toolsURLsForList(f.urlGetter, list)
+	if err != nil {
+		return nil, nil, err
+	}
 	var fullList coretools.List
-	for _, baseTools := range list {
-		urls, err := f.urlGetter.ToolsURLs(baseTools.Version)
-		if err != nil {
-			return nil, err
-		}
-		for _, url := range urls {
+	var fullSources []params.ToolsSource
+	for i, baseTools := range list {
+		for _, url := range allURLs[i] {
 			tools := *baseTools
 			tools.URL = url
 			fullList = append(fullList, &tools)
+			fullSources = append(fullSources, sources[baseTools.Version])
 		}
 	}
-	return fullList, nil
+	return fullList, fullSources, nil
 }
 
-// findMatchingTools searches tools storage and simplestreams for tools matching the
+// findMatchingTools returns the tools matching the given parameters,
+// reusing the result of a recent identical search if there is one.
+func (f *ToolsFinder) findMatchingTools(args params.FindToolsParams) (coretools.List, toolsSources, error) {
+	cfg, err := f.configGetter.ModelConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	key := toolsCacheKey{
+		number:       args.Number,
+		majorVersion: args.MajorVersion,
+		minorVersion: args.MinorVersion,
+		series:       args.Series,
+		arch:         args.Arch,
+		stream:       envtools.PreferredStream(&args.Number, cfg.Development(), cfg.AgentStream()),
+	}
+	if list, sources, ok := f.cache.get(key); ok {
+		return list, sources, nil
+	}
+	generation := currentToolsGeneration()
+	list, sources, err := f.searchTools(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.cache.set(key, generation, list, sources)
+	return list, sources, nil
+}
+
+// toolsSources records where each of the tools found by a search
+// came from.
+type toolsSources map[version.Binary]params.ToolsSource
+
+// searchTools searches tools storage and simplestreams for tools matching the
 // given parameters. If an exact match is specified (number, series and arch)
 // and is found in tools storage, then simplestreams will not be searched.
-func (f *ToolsFinder) findMatchingTools(args params.FindToolsParams) (coretools.List, error) {
+func (f *ToolsFinder) searchTools(args params.FindToolsParams) (coretools.List, toolsSources, error) {
 	exactMatch := args.Number != version.Zero && args.Series != "" && args.Arch != ""
 	storageList, err := f.matchingStorageTools(args)
+	sources := make(toolsSources)
+	for _, tools := range storageList {
+		sources[tools.Version] = params.ToolsSource{InStorage: true}
+	}
 	if err == nil && exactMatch {
-		return storageList, nil
+		return storageList, sources, nil
 	} else if err != nil && err != coretools.ErrNoMatches {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Look for tools in simplestreams too, but don't replace
 	// any versions found in storage.
 	env, err := environs.GetEnviron(f.configGetter, environs.New)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	filter := toolsFilter(args)
 	cfg := env.Config()
 	stream := envtools.PreferredStream(&args.Number, cfg.Development(), cfg.AgentStream())
-	simplestreamsList, err := envtoolsFindTools(
+	simplestreamsList, resolveInfo, err := envtoolsFindTools(
 		env, args.MajorVersion, args.MinorVersion, stream, filter,
 	)
 	if len(storageList) == 0 && err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	list := storageList
-	found := make(map[version.Binary]bool)
-	for _, tools := range storageList {
-		found[tools.Version] = true
-	}
 	for _, tools := range simplestreamsList {
-		if !found[tools.Version] {
-			list = append(list, tools)
+		if _, ok := sources[tools.Version]; ok {
+			continue
 		}
+		source := params.ToolsSource{
+			Stream: stream,
+			URL:    tools.URL,
+		}
+		if resolveInfo != nil {
+			source.Signed = resolveInfo.Signed
+		}
+		sources[tools.Version] = source
+		list = append(list, tools)
 	}
 	sort.Sort(list)
-	return list, nil
+	return list, sources, nil
 }
 
 // matchingStorageTools returns a coretools.List, with an entry for each
@@ -333,18 +385,84 @@ func toolsFilter(args params.FindToolsParams) coretools.Filter {
 	}
 }
 
+// signedToolsURLExpiry is how long the pre-signed object storage URLs
+// returned by a ToolsURLGetter remain valid. It must be long enough for
+// a newly provisioned machine to boot and download its tools.
+const signedToolsURLExpiry = 6 * time.Hour
+
 type toolsURLGetter struct {
 	modelUUID          string
 	apiHostPortsGetter APIHostPortsGetter
+
+	// objectStorage, if non-nil, holds tools under stream, which
+	// are offered through pre-signed URLs.
+	objectStorage storage.StorageReader
+	stream        string
+	clock         clock.Clock
 }
 
 // NewToolsURLGetter creates a new ToolsURLGetter that
 // returns tools URLs pointing at an API server.
 func NewToolsURLGetter(modelUUID string, a APIHostPortsGetter) *toolsURLGetter {
-	return &toolsURLGetter{modelUUID, a}
+	return &toolsURLGetter{modelUUID: modelUUID, apiHostPortsGetter: a}
+}
+
+// NewSignedToolsURLGetter creates a new ToolsURLGetter that, for tools
+// found in the given object storage under the given stream, returns a
+// pre-signed URL through which they may be downloaded directly from the
+// storage, without going through the controller. URLs pointing at an
+// API server follow it, for use if the download from storage fails.
+//
+// If the storage cannot sign URLs, only API server URLs are returned.
+func NewSignedToolsURLGetter(
+	modelUUID string, a APIHostPortsGetter, stor storage.StorageReader, stream string, clock clock.Clock,
+) *toolsURLGetter {
+	return &toolsURLGetter{
+		modelUUID:          modelUUID,
+		apiHostPortsGetter: a,
+		objectStorage:      stor,
+		stream:             stream,
+		clock:              clock,
+	}
+}
+
+// bulkToolsURLGetter is implemented by ToolsURLGetters that can get
+// the URLs of many tools more cheaply at once than one at a time.
+type bulkToolsURLGetter interface {
+	toolsURLsForVersions(vs []version.Binary) ([][]string, error)
+}
+
+// toolsURLsForList returns the URLs of each of the tools in list.
+func toolsURLsForList(getter ToolsURLGetter, list coretools.List) ([][]string, error) {
+	if bulk, ok := getter.(bulkToolsURLGetter); ok {
+		vs := make([]version.Binary, len(list))
+		for i, tools := range list {
+			vs[i] = tools.Version
+		}
+		return bulk.toolsURLsForVersions(vs)
+	}
+	result := make([][]string, len(list))
+	for i, tools := range list {
+		urls, err := getter.ToolsURLs(tools.Version)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = urls
+	}
+	return result, nil
 }
 
 func (t *toolsURLGetter) ToolsURLs(v version.Binary) ([]string, error) {
+	urls, err := t.toolsURLsForVersions([]version.Binary{v})
+	if err != nil {
+		return nil, err
+	}
+	return urls[0], nil
+}
+
+// toolsURLsForVersions returns the URLs of each of the given tools,
+// listing the tools in the object storage only once.
+func (t *toolsURLGetter) toolsURLsForVersions(vs []version.Binary) ([][]string, error) {
 	addrs, err := apiAddresses(t.apiHostPortsGetter)
 	if err != nil {
 		return nil, err
@@ -352,13 +470,59 @@ func (t *toolsURLGetter) ToolsURLs(v version.Binary) ([]string, error) {
 	if len(addrs) == 0 {
 		return nil, errors.Errorf("no suitable API server address to pick from")
 	}
-	var urls []string
-	for _, addr := range addrs {
-		serverRoot := fmt.Sprintf("https://%s/model/%s", addr, t.modelUUID)
-		url := ToolsURL(serverRoot, v)
-		urls = append(urls, url)
+	stored, err := t.storedTools()
+	if err != nil {
+		// The tools can still be fetched through the API server.
+		logger.Warningf("cannot list tools in object storage: %v", err)
+	}
+	result := make([][]string, len(vs))
+	for i, v := range vs {
+		var urls []string
+		signedURL, err := t.signedToolsURL(v, stored)
+		if err != nil {
+			logger.Warningf("cannot get object storage URL for tools %v: %v", v, err)
+		} else if signedURL != "" {
+			urls = append(urls, signedURL)
+		}
+		for _, addr := range addrs {
+			serverRoot := fmt.Sprintf("https://%s/model/%s", addr, t.modelUUID)
+			url := ToolsURL(serverRoot, v)
+			urls = append(urls, url)
+		}
+		result[i] = urls
+	}
+	return result, nil
+}
+
+// storedTools returns the names of the tools held in the object storage
+// under the getter's stream, or nil if there is no object storage or it
+// cannot sign URLs.
+func (t *toolsURLGetter) storedTools() (set.Strings, error) {
+	if _, ok := t.objectStorage.(storage.SignedURLReader); !ok {
+		return nil, nil
+	}
+	prefix := path.Dir(envtools.StorageName(version.Binary{}, t.stream)) + "/"
+	names, err := t.objectStorage.List(prefix)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
-	return urls, nil
+	return set.NewStrings(names...), nil
+}
+
+// signedToolsURL returns a pre-signed URL for the given tools in the
+// object storage, or "" if the tools are not among those stored, or
+// the storage cannot sign URLs.
+func (t *toolsURLGetter) signedToolsURL(v version.Binary, stored set.Strings) (string, error) {
+	name := envtools.StorageName(v, t.stream)
+	if !stored.Contains(name) {
+		return "", nil
+	}
+	signer := t.objectStorage.(storage.SignedURLReader)
+	url, err := signer.SignedURL(name, t.clock.Now().Add(signedToolsURLExpiry))
+	if errors.IsNotSupported(err) {
+		return "", nil
+	}
+	return url, errors.Trace(err)
 }
 
 // ToolsURL returns a tools URL pointing the API server
@@ -5,8 +5,11 @@ package common_test
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/series"
@@ -18,6 +21,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/simplestreams"
+	envstorage "github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -164,6 +169,7 @@ func (s *toolsSuite) TestFindTools(c *gc.C) {
 		},
 		&coretools.Tools{
 			Version: version.MustParseBinary("123.456.1-win81-alpha"),
+			URL:     "https://streams.example.com/juju-123.456.1-win81-alpha.tgz",
 		},
 	}
 	storageMetadata := []binarystorage.Metadata{{
@@ -172,13 +178,13 @@ func (s *toolsSuite) TestFindTools(c *gc.C) {
 		SHA256:  "feedface",
 	}}
 
-	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (coretools.List, error) {
+	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (coretools.List, *simplestreams.ResolveInfo, error) {
 		c.Assert(major, gc.Equals, 123)
 		c.Assert(minor, gc.Equals, 456)
 		c.Assert(stream, gc.Equals, "released")
 		c.Assert(filter.Series, gc.Equals, "win81")
 		c.Assert(filter.Arch, gc.Equals, "alpha")
-		return envtoolsList, nil
+		return envtoolsList, &simplestreams.ResolveInfo{Signed: true}, nil
 	})
 	toolsFinder := common.NewToolsFinder(
 		stateenvirons.EnvironConfigGetter{s.State}, &mockToolsStorage{metadata: storageMetadata}, sprintfURLGetter("tools:%s"),
@@ -203,11 +209,15 @@ func (s *toolsSuite) TestFindTools(c *gc.C) {
 			URL:     "tools:123.456.1-win81-alpha",
 		},
 	})
+	c.Check(result.Sources, jc.DeepEquals, []params.ToolsSource{
+		{InStorage: true},
+		{Stream: "released", Signed: true, URL: "https://streams.example.com/juju-123.456.1-win81-alpha.tgz"},
+	})
 }
 
 func (s *toolsSuite) TestFindToolsNotFound(c *gc.C) {
-	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (list coretools.List, err error) {
-		return nil, errors.NotFoundf("tools")
+	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (list coretools.List, _ *simplestreams.ResolveInfo, err error) {
+		return nil, nil, errors.NotFoundf("tools")
 	})
 	toolsFinder := common.NewToolsFinder(stateenvirons.EnvironConfigGetter{s.State}, s.State, sprintfURLGetter("%s"))
 	result, err := toolsFinder.FindTools(params.FindToolsParams{})
@@ -241,7 +251,7 @@ func (s *toolsSuite) TestFindToolsExactNotInStorage(c *gc.C) {
 
 func (s *toolsSuite) testFindToolsExact(c *gc.C, t common.ToolsStorageGetter, inStorage bool, develVersion bool) {
 	var called bool
-	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (list coretools.List, err error) {
+	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (list coretools.List, _ *simplestreams.ResolveInfo, err error) {
 		called = true
 		c.Assert(filter.Number, gc.Equals, jujuversion.Current)
 		c.Assert(filter.Series, gc.Equals, series.MustHostSeries())
@@ -251,7 +261,7 @@ func (s *toolsSuite) testFindToolsExact(c *gc.C, t common.ToolsStorageGetter, in
 		} else {
 			c.Assert(stream, gc.Equals, "released")
 		}
-		return nil, errors.NotFoundf("tools")
+		return nil, nil, errors.NotFoundf("tools")
 	})
 	toolsFinder := common.NewToolsFinder(stateenvirons.EnvironConfigGetter{s.State}, t, sprintfURLGetter("tools:%s"))
 	result, err := toolsFinder.FindTools(params.FindToolsParams{
@@ -273,9 +283,9 @@ func (s *toolsSuite) testFindToolsExact(c *gc.C, t common.ToolsStorageGetter, in
 
 func (s *toolsSuite) TestFindToolsToolsStorageError(c *gc.C) {
 	var called bool
-	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (list coretools.List, err error) {
+	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (list coretools.List, _ *simplestreams.ResolveInfo, err error) {
 		called = true
-		return nil, errors.NotFoundf("tools")
+		return nil, nil, errors.NotFoundf("tools")
 	})
 	toolsFinder := common.NewToolsFinder(stateenvirons.EnvironConfigGetter{s.State}, &mockToolsStorage{
 		err: errors.New("AllMetadata failed"),
@@ -292,6 +302,48 @@ func (s *toolsSuite) TestFindToolsToolsStorageError(c *gc.C) {
 	c.Assert(called, jc.IsFalse)
 }
 
+func (s *toolsSuite) TestFindToolsCached(c *gc.C) {
+	var calls int
+	s.PatchValue(common.EnvtoolsFindTools, func(e environs.Environ, major, minor int, stream string, filter coretools.Filter) (coretools.List, *simplestreams.ResolveInfo, error) {
+		calls++
+		return coretools.List{
+			&coretools.Tools{Version: version.MustParseBinary("123.456.0-win81-alpha")},
+		}, nil, nil
+	})
+	clock := jujutesting.NewClock(time.Now())
+	toolsFinder := common.NewToolsFinderWithClock(
+		stateenvirons.EnvironConfigGetter{s.State}, &mockToolsStorage{}, sprintfURLGetter("tools:%s"), clock,
+	)
+	findTools := func(series string) {
+		result, err := toolsFinder.FindTools(params.FindToolsParams{
+			MajorVersion: 123,
+			MinorVersion: 456,
+			Series:       series,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result.Error, gc.IsNil)
+		c.Assert(result.List, gc.HasLen, 1)
+	}
+
+	findTools("win81")
+	findTools("win81")
+	c.Assert(calls, gc.Equals, 1)
+
+	// A different search is not answered from the cache.
+	findTools("")
+	c.Assert(calls, gc.Equals, 2)
+
+	// Uploading tools invalidates the cache.
+	common.InvalidateToolsCache()
+	findTools("win81")
+	c.Assert(calls, gc.Equals, 3)
+
+	// As does time passing.
+	clock.Advance(common.ToolsCacheExpiry)
+	findTools("win81")
+	c.Assert(calls, gc.Equals, 4)
+}
+
 func (s *toolsSuite) TestToolsURLGetterNoAPIHostPorts(c *gc.C) {
 	g := common.NewToolsURLGetter("my-uuid", mockAPIHostPortsGetter{})
 	_, err := g.ToolsURLs(current)
@@ -317,6 +369,105 @@ func (s *toolsSuite) TestToolsURLGetter(c *gc.C) {
 	})
 }
 
+func (s *toolsSuite) TestSignedToolsURLGetter(c *gc.C) {
+	stor := &signingToolsStorage{names: []string{
+		"tools/released/juju-" + current.String() + ".tgz",
+	}}
+	clock := jujutesting.NewClock(time.Now())
+	g := common.NewSignedToolsURLGetter("my-uuid", mockAPIHostPortsGetter{
+		hostPorts: [][]network.HostPort{
+			network.NewHostPorts(1234, "0.1.2.3"),
+		},
+	}, stor, "released", clock)
+	urls, err := g.ToolsURLs(current)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(urls, jc.DeepEquals, []string{
+		"https://storage.example.com/tools/released/juju-" + current.String() + ".tgz?signed",
+		"https://0.1.2.3:1234/model/my-uuid/tools/" + current.String(),
+	})
+	c.Check(stor.expires, gc.Equals, clock.Now().Add(common.SignedToolsURLExpiry))
+}
+
+func (s *toolsSuite) TestSignedToolsURLGetterListsOnce(c *gc.C) {
+	other := version.MustParseBinary("123.456.1-win81-alpha")
+	stor := &signingToolsStorage{names: []string{
+		"tools/released/juju-" + current.String() + ".tgz",
+		"tools/released/juju-" + other.String() + ".tgz",
+	}}
+	g := common.NewSignedToolsURLGetter("my-uuid", mockAPIHostPortsGetter{
+		hostPorts: [][]network.HostPort{
+			network.NewHostPorts(1234, "0.1.2.3"),
+		},
+	}, stor, "released", jujutesting.NewClock(time.Now()))
+	urls, err := common.ToolsURLsForList(g, coretools.List{
+		&coretools.Tools{Version: current},
+		&coretools.Tools{Version: other},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(urls, jc.DeepEquals, [][]string{{
+		"https://storage.example.com/tools/released/juju-" + current.String() + ".tgz?signed",
+		"https://0.1.2.3:1234/model/my-uuid/tools/" + current.String(),
+	}, {
+		"https://storage.example.com/tools/released/juju-" + other.String() + ".tgz?signed",
+		"https://0.1.2.3:1234/model/my-uuid/tools/" + other.String(),
+	}})
+	c.Check(stor.listCalls, gc.Equals, 1)
+}
+
+func (s *toolsSuite) TestSignedToolsURLGetterNotInStorage(c *gc.C) {
+	stor := &signingToolsStorage{}
+	g := common.NewSignedToolsURLGetter("my-uuid", mockAPIHostPortsGetter{
+		hostPorts: [][]network.HostPort{
+			network.NewHostPorts(1234, "0.1.2.3"),
+		},
+	}, stor, "released", jujutesting.NewClock(time.Now()))
+	urls, err := g.ToolsURLs(current)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(urls, jc.DeepEquals, []string{
+		"https://0.1.2.3:1234/model/my-uuid/tools/" + current.String(),
+	})
+}
+
+func (s *toolsSuite) TestSignedToolsURLGetterStorageError(c *gc.C) {
+	stor := &signingToolsStorage{err: errors.New("boom")}
+	g := common.NewSignedToolsURLGetter("my-uuid", mockAPIHostPortsGetter{
+		hostPorts: [][]network.HostPort{
+			network.NewHostPorts(1234, "0.1.2.3"),
+		},
+	}, stor, "released", jujutesting.NewClock(time.Now()))
+	urls, err := g.ToolsURLs(current)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(urls, jc.DeepEquals, []string{
+		"https://0.1.2.3:1234/model/my-uuid/tools/" + current.String(),
+	})
+}
+
+// signingToolsStorage is an object storage holding the named files,
+// which signs URLs for them.
+type signingToolsStorage struct {
+	envstorage.StorageReader
+	names     []string
+	err       error
+	expires   time.Time
+	listCalls int
+}
+
+func (s *signingToolsStorage) List(prefix string) ([]string, error) {
+	s.listCalls++
+	var names []string
+	for _, name := range s.names {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names, s.err
+}
+
+func (s *signingToolsStorage) SignedURL(name string, expires time.Time) (string, error) {
+	s.expires = expires
+	return "https://storage.example.com/" + name + "?signed", nil
+}
+
 type sprintfURLGetter string
 
 func (s sprintfURLGetter) ToolsURLs(v version.Binary) ([]string, error) {
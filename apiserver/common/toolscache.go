@@ -0,0 +1,96 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/utils/clock"
+	"github.com/juju/version"
+
+	coretools "github.com/juju/juju/tools"
+)
+
+// toolsCacheExpiry is how long the results of a tools search are
+// reused. It is short, so that tools newly published to simplestreams
+// are found soon, but long enough to cover a burst of provisioning.
+const toolsCacheExpiry = time.Minute
+
+// toolsGeneration is incremented whenever tools are added to tools
+// storage, invalidating every cached search result.
+var toolsGeneration uint64
+
+// InvalidateToolsCache discards the cached results of all ToolsFinder
+// searches. It should be called whenever tools are added to tools
+// storage.
+func InvalidateToolsCache() {
+	atomic.AddUint64(&toolsGeneration, 1)
+}
+
+func currentToolsGeneration() uint64 {
+	return atomic.LoadUint64(&toolsGeneration)
+}
+
+// toolsCacheKey identifies a tools search.
+type toolsCacheKey struct {
+	number       version.Number
+	majorVersion int
+	minorVersion int
+	series       string
+	arch         string
+	stream       string
+}
+
+type toolsCacheEntry struct {
+	list       coretools.List
+	sources    toolsSources
+	generation uint64
+	expires    time.Time
+}
+
+// toolsCache holds the results of recent tools searches.
+type toolsCache struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[toolsCacheKey]toolsCacheEntry
+}
+
+func newToolsCache(clock clock.Clock) *toolsCache {
+	return &toolsCache{
+		clock:   clock,
+		entries: make(map[toolsCacheKey]toolsCacheEntry),
+	}
+}
+
+// get returns the cached result of the search with the given key, and
+// whether there is one.
+func (c *toolsCache) get(key toolsCacheKey) (coretools.List, toolsSources, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	if entry.generation != currentToolsGeneration() || !c.clock.Now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, nil, false
+	}
+	return entry.list, entry.sources, true
+}
+
+// set records the result of the search with the given key, which was
+// started when the tools generation was as given.
+func (c *toolsCache) set(key toolsCacheKey, generation uint64, list coretools.List, sources toolsSources) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = toolsCacheEntry{
+		list:       list,
+		sources:    sources,
+		generation: generation,
+		expires:    c.clock.Now().Add(toolsCacheExpiry),
+	}
+}
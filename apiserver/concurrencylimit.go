@@ -0,0 +1,137 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// ConcurrencyLimitConfig holds the limit on the number of API requests
+// in flight at once over a single connection. A zero limit means that
+// the number of requests is not limited.
+type ConcurrencyLimitConfig struct {
+	// MaxRequests is the maximum number of requests that may run at
+	// once over the connection.
+	MaxRequests int
+
+	// Burst is the number of requests beyond MaxRequests that may
+	// wait for a running request to complete. Requests beyond that
+	// are rejected immediately.
+	Burst int
+}
+
+// enabled reports whether the limit is set.
+func (c ConcurrencyLimitConfig) enabled() bool {
+	return c.MaxRequests > 0
+}
+
+// concurrencyLimitExempt reports whether requests to the named facade
+// are never counted against the concurrency limit. Watcher requests
+// block until there is a change to report, and the rate limit exempt
+// facades are needed by agents to stay connected.
+func concurrencyLimitExempt(facadeName string) bool {
+	return strings.HasSuffix(facadeName, "Watcher") || rateLimitExemptFacades.Contains(facadeName)
+}
+
+// limitConcurrency wraps the provided root so that no more than the
+// configured number of requests are run at once.
+func limitConcurrency(root rpc.Root, config ConcurrencyLimitConfig) *concurrencyLimitedRoot {
+	return &concurrencyLimitedRoot{
+		Root:    root,
+		config:  config,
+		running: make(chan struct{}, config.MaxRequests),
+		dying:   make(chan struct{}),
+	}
+}
+
+type concurrencyLimitedRoot struct {
+	rpc.Root
+	config  ConcurrencyLimitConfig
+	running chan struct{}
+
+	mu       sync.Mutex
+	waiting  int
+	dying    chan struct{}
+	killOnce sync.Once
+}
+
+// FindMethod implements rpc.Root.
+func (r *concurrencyLimitedRoot) FindMethod(facadeName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.Root.FindMethod(facadeName, version, methodName)
+	if err != nil || concurrencyLimitExempt(facadeName) {
+		return caller, err
+	}
+	return &concurrencyLimitedCaller{
+		MethodCaller: caller,
+		root:         r,
+		facadeName:   facadeName,
+		methodName:   methodName,
+	}, nil
+}
+
+// Kill implements rpc.Root. Requests waiting to run are abandoned.
+func (r *concurrencyLimitedRoot) Kill() {
+	r.killOnce.Do(func() { close(r.dying) })
+	r.Root.Kill()
+}
+
+// acquire waits until a request may run, returning an error satisfying
+// params.IsCodeTryAgain if too many requests are already waiting.
+func (r *concurrencyLimitedRoot) acquire(facadeName, methodName string) error {
+	select {
+	case r.running <- struct{}{}:
+		return nil
+	default:
+	}
+	r.mu.Lock()
+	if r.waiting >= r.config.Burst {
+		r.mu.Unlock()
+		logger.Debugf("too many concurrent requests, rejecting %s.%s", facadeName, methodName)
+		return common.ErrConcurrencyLimitExceeded
+	}
+	r.waiting++
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.waiting--
+		r.mu.Unlock()
+	}()
+	select {
+	case r.running <- struct{}{}:
+		return nil
+	case <-r.dying:
+		return common.ErrConcurrencyLimitExceeded
+	}
+}
+
+// release frees the slot taken by a call to acquire.
+func (r *concurrencyLimitedRoot) release() {
+	<-r.running
+}
+
+// concurrencyLimitedCaller is a method caller that counts its calls
+// against the concurrency limit of its root.
+type concurrencyLimitedCaller struct {
+	rpcreflect.MethodCaller
+	root       *concurrencyLimitedRoot
+	facadeName string
+	methodName string
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *concurrencyLimitedCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	if err := c.root.acquire(c.facadeName, c.methodName); err != nil {
+		return reflect.Value{}, err
+	}
+	defer c.root.release()
+	return c.MethodCaller.Call(ctx, objId, arg)
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"reflect"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/testing"
+)
+
+type concurrencyLimitSuite struct {
+	testing.BaseSuite
+	root    *blockingRoot
+	limited rpc.Root
+}
+
+var _ = gc.Suite(&concurrencyLimitSuite{})
+
+func (s *concurrencyLimitSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.root = &blockingRoot{
+		started: make(chan struct{}, 10),
+		unblock: make(chan struct{}),
+	}
+	s.limited = apiserver.TestingConcurrencyLimitedRoot(s.root, apiserver.ConcurrencyLimitConfig{
+		MaxRequests: 1,
+		Burst:       1,
+	})
+}
+
+// call starts a call to the given facade and returns a channel on
+// which its error is sent.
+func (s *concurrencyLimitSuite) call(c *gc.C, facade string) <-chan error {
+	caller, err := s.limited.FindMethod(facade, 1, "Method")
+	c.Assert(err, jc.ErrorIsNil)
+	result := make(chan error, 1)
+	go func() {
+		_, err := caller.Call(context.Background(), "", reflect.Value{})
+		result <- err
+	}()
+	return result
+}
+
+func (s *concurrencyLimitSuite) waitStarted(c *gc.C) {
+	select {
+	case <-s.root.started:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for call to start")
+	}
+}
+
+func (s *concurrencyLimitSuite) assertNotStarted(c *gc.C) {
+	select {
+	case <-s.root.started:
+		c.Fatalf("call started unexpectedly")
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *concurrencyLimitSuite) waitResult(c *gc.C, result <-chan error) error {
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for call to complete")
+	}
+	panic("unreachable")
+}
+
+func (s *concurrencyLimitSuite) TestLimit(c *gc.C) {
+	first := s.call(c, "Client")
+	s.waitStarted(c)
+
+	// The second call waits for the first to complete.
+	second := s.call(c, "Client")
+	s.assertNotStarted(c)
+
+	// The third call exceeds the burst and is rejected.
+	err := s.waitResult(c, s.call(c, "Client"))
+	c.Assert(err, gc.Equals, common.ErrConcurrencyLimitExceeded)
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeTryAgain)
+
+	s.root.unblock <- struct{}{}
+	c.Assert(s.waitResult(c, first), jc.ErrorIsNil)
+	s.waitStarted(c)
+	s.root.unblock <- struct{}{}
+	c.Assert(s.waitResult(c, second), jc.ErrorIsNil)
+}
+
+func (s *concurrencyLimitSuite) TestWatchersExempt(c *gc.C) {
+	first := s.call(c, "Client")
+	s.waitStarted(c)
+	watcher := s.call(c, "NotifyWatcher")
+	s.waitStarted(c)
+
+	s.root.unblock <- struct{}{}
+	s.root.unblock <- struct{}{}
+	c.Assert(s.waitResult(c, first), jc.ErrorIsNil)
+	c.Assert(s.waitResult(c, watcher), jc.ErrorIsNil)
+}
+
+func (s *concurrencyLimitSuite) TestKillAbandonsWaiting(c *gc.C) {
+	first := s.call(c, "Client")
+	s.waitStarted(c)
+	second := s.call(c, "Client")
+	s.assertNotStarted(c)
+
+	s.limited.Kill()
+	err := s.waitResult(c, second)
+	c.Assert(err, gc.Equals, common.ErrConcurrencyLimitExceeded)
+	c.Assert(s.root.killed, jc.IsTrue)
+
+	s.root.unblock <- struct{}{}
+	c.Assert(s.waitResult(c, first), jc.ErrorIsNil)
+}
+
+// blockingRoot is an rpc.Root whose methods block until unblocked.
+type blockingRoot struct {
+	started chan struct{}
+	unblock chan struct{}
+	killed  bool
+}
+
+func (r *blockingRoot) FindMethod(facade string, version int, method string) (rpcreflect.MethodCaller, error) {
+	return blockingCaller{r}, nil
+}
+
+func (r *blockingRoot) Kill() {
+	r.killed = true
+}
+
+type blockingCaller struct {
+	root *blockingRoot
+}
+
+func (blockingCaller) ParamsType() reflect.Type {
+	return nil
+}
+
+func (blockingCaller) ResultType() reflect.Type {
+	return nil
+}
+
+func (c blockingCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	c.root.started <- struct{}{}
+	<-c.root.unblock
+	return reflect.Value{}, nil
+}
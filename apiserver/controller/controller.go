@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/permission"
@@ -32,7 +33,30 @@ import (
 var logger = loggo.GetLogger("juju.apiserver.controller")
 
 func init() {
-	common.RegisterStandardFacade("Controller", 3, NewControllerAPI)
+	common.RegisterStandardFacade("Controller", 6, NewControllerAPI)
+	// Version 5 is served by version 6, without the CA rotation
+	// methods.
+	common.RegisterFacadeTranslation("Controller", 5, facade.Translation{
+		Omit: caRotationMethods,
+	})
+	// Version 4 is served by version 5, without ModelLeases.
+	common.RegisterFacadeTranslation("Controller", 4, facade.Translation{
+		Omit: []string{"ModelLeases"},
+	})
+	// Version 3 is served by version 4, without AuditLog.
+	common.RegisterFacadeTranslation("Controller", 3, facade.Translation{
+		Omit: []string{"AuditLog"},
+	})
+}
+
+// caRotationMethods holds the names of the methods added in version 6
+// to rotate the controller's CA certificate.
+var caRotationMethods = []string{
+	"StageCARotation",
+	"CutOverCARotation",
+	"CompleteCARotation",
+	"AbortCARotation",
+	"CARotationStatus",
 }
 
 // Controller defines the methods on the controller API end point.
@@ -49,6 +73,13 @@ type Controller interface {
 	ModelStatus(params.Entities) (params.ModelStatusResults, error)
 	InitiateMigration(params.InitiateMigrationArgs) (params.InitiateMigrationResults, error)
 	ModifyControllerAccess(params.ModifyControllerAccessRequest) (params.ErrorResults, error)
+	AuditLog(params.AuditLogFilter) (params.AuditLogResults, error)
+	ModelLeases(params.Entities) (params.LeaseDetailsResults, error)
+	StageCARotation(params.StageCARotationArgs) error
+	CutOverCARotation() error
+	CompleteCARotation() error
+	AbortCARotation() error
+	CARotationStatus() (params.CARotationStatusResult, error)
 }
 
 // ControllerAPI implements the environment manager interface and is
@@ -450,6 +481,176 @@ func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string,
 	return mig.Id(), nil
 }
 
+// maxAuditLogEntries is the maximum number of entries returned by a
+// single AuditLog call.
+const maxAuditLogEntries = 1000
+
+// AuditLog returns the entries in the controller's audit log selected
+// by the filter, most recent first. At most maxAuditLogEntries
+// entries are returned. Callers must be controller administrators.
+func (c *ControllerAPI) AuditLog(args params.AuditLogFilter) (params.AuditLogResults, error) {
+	var result params.AuditLogResults
+	if err := c.checkHasAdmin(); err != nil {
+		return result, errors.Trace(err)
+	}
+	filter := audit.Filter{
+		Limit: args.Limit,
+	}
+	if filter.Limit <= 0 || filter.Limit > maxAuditLogEntries {
+		filter.Limit = maxAuditLogEntries
+	}
+	if args.ModelTag != "" {
+		modelTag, err := names.ParseModelTag(args.ModelTag)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		filter.ModelUUID = modelTag.Id()
+	}
+	if args.UserTag != "" {
+		userTag, err := names.ParseUserTag(args.UserTag)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		filter.OriginName = userTag.String()
+	}
+	if args.After != nil {
+		filter.After = *args.After
+	}
+	if args.Before != nil {
+		filter.Before = *args.Before
+	}
+	entries, err := c.state.AuditEntries(filter)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Entries = make([]params.AuditLogEntry, len(entries))
+	for i, entry := range entries {
+		result.Entries[i] = params.AuditLogEntry{
+			JujuServerVersion: entry.JujuServerVersion,
+			ModelTag:          names.NewModelTag(entry.ModelUUID).String(),
+			Timestamp:         entry.Timestamp,
+			RemoteAddress:     entry.RemoteAddress,
+			OriginType:        entry.OriginType,
+			OriginName:        entry.OriginName,
+			Operation:         entry.Operation,
+			Data:              entry.Data,
+		}
+	}
+	return result, nil
+}
+
+// ModelLeases returns the application leadership and singular
+// controller leases held in each of the specified models, including
+// how many times each lease has been extended since it was claimed.
+// Callers must be controller administrators.
+func (c *ControllerAPI) ModelLeases(args params.Entities) (params.LeaseDetailsResults, error) {
+	result := params.LeaseDetailsResults{
+		Results: make([]params.LeaseDetailsResult, len(args.Entities)),
+	}
+	if err := c.checkHasAdmin(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		leases, err := c.modelLeases(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Leases = leases
+	}
+	return result, nil
+}
+
+func (c *ControllerAPI) modelLeases(tag string) ([]params.LeaseDetails, error) {
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	st := c.state
+	if modelTag != c.state.ModelTag() {
+		st, err = c.state.ForModel(modelTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer st.Close()
+	}
+	details, err := st.LeaseDetails()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	leases := make([]params.LeaseDetails, len(details))
+	for i, d := range details {
+		leases[i] = params.LeaseDetails{
+			Namespace:  d.Namespace,
+			Name:       d.Name,
+			Holder:     d.Holder,
+			Expiry:     d.Expiry,
+			Extensions: d.Extensions,
+		}
+	}
+	return leases, nil
+}
+
+// StageCARotation starts replacing the controller's CA with the
+// supplied CA certificate and private key. Until the rotation is
+// completed, agents trust certificates signed by either CA. Callers
+// must be controller administrators.
+func (c *ControllerAPI) StageCARotation(args params.StageCARotationArgs) error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.state.StageCARotation(args.CACert, args.CAPrivateKey))
+}
+
+// CutOverCARotation replaces the controller's certificate with one
+// signed by the staged CA. Callers must be controller administrators.
+func (c *ControllerAPI) CutOverCARotation() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.state.CutOverCARotation())
+}
+
+// CompleteCARotation finishes a CA rotation that has been cut over, so
+// that agents trust only the new CA. Callers must be controller
+// administrators.
+func (c *ControllerAPI) CompleteCARotation() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.state.CompleteCARotation())
+}
+
+// AbortCARotation abandons a CA rotation that has been staged but not
+// cut over. Callers must be controller administrators.
+func (c *ControllerAPI) AbortCARotation() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.state.AbortCARotation())
+}
+
+// CARotationStatus returns the CA rotation in progress, if any.
+// Callers must be controller administrators.
+func (c *ControllerAPI) CARotationStatus() (params.CARotationStatusResult, error) {
+	var result params.CARotationStatusResult
+	if err := c.checkHasAdmin(); err != nil {
+		return result, errors.Trace(err)
+	}
+	rotation, err := c.state.CARotation()
+	if errors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Status = &params.CARotationStatus{
+		Phase:          string(rotation.Phase),
+		CACert:         rotation.CACert,
+		PreviousCACert: rotation.PreviousCACert,
+	}
+	return result, nil
+}
+
 // ModifyControllerAccess changes the model access granted to users.
 func (c *ControllerAPI) ModifyControllerAccess(args params.ModifyControllerAccessRequest) (params.ErrorResults, error) {
 	result := params.ErrorResults{
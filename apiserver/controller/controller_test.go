@@ -19,18 +19,22 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/controller"
+	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/facade/facadetest"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/permission"
+	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
+	jujuversion "github.com/juju/juju/version"
 )
 
 type controllerSuite struct {
@@ -235,6 +239,158 @@ func (s *controllerSuite) TestListBlockedModelsNoBlocks(c *gc.C) {
 	c.Assert(list.Models, gc.HasLen, 0)
 }
 
+func (s *controllerSuite) TestAuditLog(c *gc.C) {
+	put := s.State.PutAuditEntryFn()
+	base := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, origin := range []string{"user-bob", "user-mary", "user-bob"} {
+		err := put(audit.AuditEntry{
+			JujuServerVersion: jujuversion.Current,
+			ModelUUID:         s.State.ModelUUID(),
+			Timestamp:         base.Add(time.Duration(i) * time.Minute),
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        origin,
+			Operation:         "Application:v1 - Destroy",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	after := base.Add(-time.Hour)
+	result, err := s.controller.AuditLog(params.AuditLogFilter{
+		ModelTag: s.State.ModelTag().String(),
+		UserTag:  "user-bob",
+		After:    &after,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 2)
+	c.Check(result.Entries[0], jc.DeepEquals, params.AuditLogEntry{
+		JujuServerVersion: jujuversion.Current,
+		ModelTag:          s.State.ModelTag().String(),
+		Timestamp:         base.Add(2 * time.Minute),
+		RemoteAddress:     "10.0.0.1",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Application:v1 - Destroy",
+	})
+	c.Check(result.Entries[1].Timestamp, gc.Equals, base)
+
+	result, err = s.controller.AuditLog(params.AuditLogFilter{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	c.Check(result.Entries[0].Timestamp, gc.Equals, base.Add(2*time.Minute))
+}
+
+func (s *controllerSuite) TestAuditLogRequiresAdmin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	authorizer := &apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	}
+	endpoint, err := controller.NewControllerAPI(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      authorizer,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = endpoint.AuditLog(params.AuditLogFilter{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestModelLeases(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership("mysql", "mysql/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	hosted := s.Factory.MakeModel(c, nil)
+	defer hosted.Close()
+
+	result, err := s.controller.ModelLeases(params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.State.ModelTag().String()},
+			{Tag: hosted.ModelTag().String()},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Leases, gc.HasLen, 1)
+	lease := result.Results[0].Leases[0]
+	c.Check(lease.Namespace, gc.Equals, "application-leadership")
+	c.Check(lease.Name, gc.Equals, "mysql")
+	c.Check(lease.Holder, gc.Equals, "mysql/0")
+	c.Check(lease.Extensions, gc.Equals, 0)
+
+	c.Check(result.Results[1], jc.DeepEquals, params.LeaseDetailsResult{})
+	c.Check(result.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+}
+
+func (s *controllerSuite) TestModelLeasesRequiresAdmin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	authorizer := &apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	}
+	endpoint, err := controller.NewControllerAPI(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      authorizer,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = endpoint.ModelLeases(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestCARotation(c *gc.C) {
+	result, err := s.controller.CARotationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status, gc.IsNil)
+
+	err = s.controller.StageCARotation(params.StageCARotationArgs{
+		CACert:       testing.OtherCACert,
+		CAPrivateKey: testing.OtherCAKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.controller.CARotationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status, jc.DeepEquals, &params.CARotationStatus{
+		Phase:          "staged",
+		CACert:         testing.OtherCACert,
+		PreviousCACert: testing.CACert,
+	})
+
+	err = s.controller.CompleteCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot complete CA rotation: CA rotation is staged, not cut-over")
+
+	err = s.controller.AbortCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.controller.CARotationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status, gc.IsNil)
+}
+
+func (s *controllerSuite) TestCARotationRequiresAdmin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	authorizer := &apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	}
+	endpoint, err := controller.NewControllerAPI(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      authorizer,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	err = endpoint.StageCARotation(params.StageCARotationArgs{
+		CACert:       testing.OtherCACert,
+		CAPrivateKey: testing.OtherCAKey,
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = endpoint.CutOverCARotation()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = endpoint.CARotationStatus()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *controllerSuite) TestModelConfig(c *gc.C) {
 	env, err := s.controller.ModelConfig()
 	c.Assert(err, jc.ErrorIsNil)
@@ -843,3 +999,25 @@ func (s *controllerSuite) TestGetControllerAccessPermissions(c *gc.C) {
 		Message: "permission denied", Code: "unauthorized access",
 	})
 }
+
+func (s *controllerSuite) TestOlderVersionsOmitNewerMethods(c *gc.C) {
+	for _, t := range []struct {
+		version int
+		method  string
+	}{
+		{3, "AuditLog"},
+		{3, "ModelLeases"},
+		{4, "ModelLeases"},
+		{5, "StageCARotation"},
+		{5, "CutOverCARotation"},
+		{5, "CompleteCARotation"},
+		{5, "AbortCARotation"},
+		{5, "CARotationStatus"},
+		{3, "CARotationStatus"},
+	} {
+		translations, err := common.Facades.GetTranslations("Controller", t.version)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = facade.TranslateMethod(translations, t.method, rpcreflect.ObjMethod{})
+		c.Check(err, gc.Equals, rpcreflect.ErrMethodNotFound, gc.Commentf("v%d %s", t.version, t.method))
+	}
+}
@@ -364,6 +364,12 @@ func makeOfferFilterFromParams(filter params.OfferFilter) jujucrossmodel.Applica
 		ApplicationName:        filter.ApplicationName,
 		ApplicationDescription: filter.ApplicationDescription,
 	}
-	// TODO(wallyworld) - add support for Endpoint filter attribute
+	for _, ep := range filter.Endpoints {
+		offerFilter.Endpoints = append(offerFilter.Endpoints, jujucrossmodel.EndpointFilterTerm{
+			Name:      ep.Name,
+			Interface: ep.Interface,
+			Role:      ep.Role,
+		})
+	}
 	return offerFilter
 }
@@ -350,6 +350,36 @@ func (s *crossmodelSuite) TestFind(c *gc.C) {
 	s.applicationOffers.CheckCallNames(c, listOffersBackendCall)
 }
 
+func (s *crossmodelSuite) TestFindEndpointFilter(c *gc.C) {
+	s.setupOffers(c, "")
+	s.applicationOffers.listOffers = func(filters ...jujucrossmodel.ApplicationOfferFilter) ([]jujucrossmodel.ApplicationOffer, error) {
+		c.Assert(filters, gc.HasLen, 1)
+		c.Assert(filters[0], jc.DeepEquals, jujucrossmodel.ApplicationOfferFilter{
+			OfferName: "hosted-db2",
+			Endpoints: []jujucrossmodel.EndpointFilterTerm{{
+				Name:      "db",
+				Interface: "mysql",
+				Role:      charm.RoleProvider,
+			}},
+		})
+		return nil, nil
+	}
+	filter := params.OfferFilters{
+		Filters: []params.OfferFilter{{
+			OfferName: "hosted-db2",
+			Endpoints: []params.EndpointFilterAttributes{{
+				Name:      "db",
+				Interface: "mysql",
+				Role:      charm.RoleProvider,
+			}},
+		}},
+	}
+	found, err := s.api.FindApplicationOffers(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 0)
+	s.applicationOffers.CheckCallNames(c, listOffersBackendCall)
+}
+
 func (s *crossmodelSuite) TestFindMultiModel(c *gc.C) {
 	db2Offer := jujucrossmodel.ApplicationOffer{
 		OfferName:              "hosted-db2",
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"syscall"
 	"time"
@@ -59,6 +60,8 @@ func newDebugLogHandler(
 //   excludeEntity -> []string - lists entity tags to exclude from the response
 //      - as with include, it may finish with a '*'
 //   excludeModule -> []string - lists logging modules to exclude from the response
+//   messageRegexp -> string - a regular expression that messages in the
+//      - response must match
 //   limit -> uint - show *at most* this many lines
 //   backlog -> uint
 //      - go back this many lines from the end before starting to filter
@@ -154,6 +157,7 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	messageRegexp string
 }
 
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
@@ -214,5 +218,12 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 	params.includeModule = queryMap["includeModule"]
 	params.excludeModule = queryMap["excludeModule"]
 
+	if value := queryMap.Get("messageRegexp"); value != "" {
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, errors.Errorf("messageRegexp value %q is not a valid regular expression", value)
+		}
+		params.messageRegexp = value
+	}
+
 	return params, nil
 }
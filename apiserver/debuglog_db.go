@@ -64,6 +64,7 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 		ExcludeEntity: reqParams.excludeEntity,
 		IncludeModule: reqParams.includeModule,
 		ExcludeModule: reqParams.excludeModule,
+		MessageRegexp: reqParams.messageRegexp,
 	}
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
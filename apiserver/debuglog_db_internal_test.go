@@ -41,6 +41,7 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		includeModule: []string{"bar"},
 		excludeEntity: []string{"baz"},
 		excludeModule: []string{"qux"},
+		messageRegexp: "hook .* failed",
 	}
 
 	called := false
@@ -57,6 +58,7 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		c.Assert(params.IncludeModule, jc.DeepEquals, []string{"bar"})
 		c.Assert(params.ExcludeEntity, jc.DeepEquals, []string{"baz"})
 		c.Assert(params.ExcludeModule, jc.DeepEquals, []string{"qux"})
+		c.Assert(params.MessageRegexp, gc.Equals, "hook .* failed")
 
 		return newFakeLogTailer(), nil
 	})
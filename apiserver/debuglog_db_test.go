@@ -30,6 +30,12 @@ func (s *debugLogDBSuite) TestBadParams(c *gc.C) {
 	assertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogDBSuite) TestBadMessageRegexp(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"messageRegexp": {"hook ("}})
+	assertJSONError(c, reader, `messageRegexp value "hook \(" is not a valid regular expression`)
+	assertWebsocketClosed(c, reader)
+}
+
 func (s *debugLogDBSuite) TestWithHTTP(c *gc.C) {
 	uri := s.logURL(c, "http", nil).String()
 	s.sendRequest(c, httpRequestParams{
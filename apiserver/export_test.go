@@ -9,12 +9,14 @@ import (
 	"time"
 
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/rpc"
@@ -98,7 +100,7 @@ func TestingAPIHandler(c *gc.C, srvSt, st *state.State) (*apiHandler, *common.Re
 		state:    srvSt,
 		tag:      names.NewMachineTag("0"),
 	}
-	h, err := newAPIHandler(srv, st, nil, st.ModelUUID(), "testing.invalid:1234")
+	h, err := newAPIHandler(srv, st, nil, st.ModelUUID(), "testing.invalid:1234", "127.0.0.1:5678")
 	c.Assert(err, jc.ErrorIsNil)
 	return h, h.getResources()
 }
@@ -140,12 +142,49 @@ func TestingModelOnlyRoot() rpc.Root {
 	return restrictRoot(r, modelFacadesOnly)
 }
 
+// TestingMethodAccessRoot returns a srvRoot whose facade methods are
+// restricted to users with the access recorded for them in the global
+// facade registry.
+func TestingMethodAccessRoot(
+	authorizer facade.Authorizer,
+	controllerTag names.ControllerTag,
+	modelTag names.ModelTag,
+) rpc.Root {
+	r := TestingAPIRoot(nil)
+	return restrictRoot(r, methodAccessChecker(common.Facades, authorizer, controllerTag, modelTag))
+}
+
 // TestingRestrictedRoot returns a restricted srvRoot.
 func TestingRestrictedRoot(check func(string, string) error) rpc.Root {
 	r := TestingAPIRoot(nil)
 	return restrictRoot(r, check)
 }
 
+// TestingRateLimitedRoot returns a srvRoot whose requests are rate
+// limited according to the given config.
+func TestingRateLimitedRoot(config RateLimitConfig, clock clock.Clock) rpc.Root {
+	r := TestingAPIRoot(nil)
+	return restrictRoot(r, newRequestRateLimiter(config, clock).check)
+}
+
+// TestingConcurrencyLimitedRoot returns root wrapped so that its
+// requests are limited according to the given config.
+func TestingConcurrencyLimitedRoot(root rpc.Root, config ConcurrencyLimitConfig) rpc.Root {
+	return limitConcurrency(root, config)
+}
+
+// AllowsConnection reports whether config allows a connection from
+// remoteAddr.
+func AllowsConnection(config AllowedClientsConfig, remoteAddr string) bool {
+	return config.allowsConnection(remoteAddr)
+}
+
+// AllowsLogin reports whether config allows a user, if isUser is
+// true, or an agent to log in from remoteAddr.
+func AllowsLogin(config AllowedClientsConfig, remoteAddr string, isUser bool) bool {
+	return config.allowsLogin(remoteAddr, isUser)
+}
+
 func SetAdminAPIVersions(srv *Server, versions ...int) {
 	factories := make(map[int]adminAPIFactory)
 	for _, n := range versions {
@@ -187,6 +226,14 @@ func PatchGetControllerCACert(p Patcher, caCert string) {
 	})
 }
 
+// PatchGetAPIHostPortsBackend overrides the getAPIHostPortsBackend
+// function to support testing.
+func PatchGetAPIHostPortsBackend(p Patcher, st apiHostPortsBackend) {
+	p.PatchValue(&getAPIHostPortsBackend, func(*state.State) apiHostPortsBackend {
+		return st
+	})
+}
+
 // Patcher defines an interface that matches the PatchValue method on
 // CleanupSuite
 type Patcher interface {
@@ -10,6 +10,9 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/featureflag"
+	jujuversion "github.com/juju/version"
+
+	"github.com/juju/juju/permission"
 )
 
 // record represents an entry in a Registry.
@@ -23,6 +26,24 @@ type record struct {
 	// of global in the implementation of the Registry that itself
 	// only meaningfully exists as a global.
 	feature string
+	// translations holds the translations, oldest first, needed to
+	// serve this version using the factory of a newer version.
+	translations []Translation
+	// deprecation, if not nil, records that this version is
+	// deprecated.
+	deprecation *Deprecation
+}
+
+// Deprecation describes the deprecation of a facade version. Clients
+// should move to a newer version of the facade before Sunset.
+type Deprecation struct {
+	// Since is the version of Juju in which the facade version
+	// was deprecated.
+	Since jujuversion.Number
+
+	// Sunset, if not zero, is the version of Juju from which the
+	// facade version will no longer be served.
+	Sunset jujuversion.Number
 }
 
 // versions is our internal structure for tracking specific versions of a
@@ -36,6 +57,10 @@ type versions map[int]record
 // pass it into the apiserver.
 type Registry struct {
 	facades map[string]versions
+
+	// access holds the access users need to call facade methods,
+	// keyed by facade name and then by method name.
+	access map[string]map[string]permission.Access
 }
 
 // Register adds a single named facade at a given version to the registry.
@@ -64,6 +89,79 @@ func (f *Registry) Register(name string, version int, factory Factory, facadeTyp
 	return nil
 }
 
+// RegisterTranslation registers a facade version that is served by the
+// already registered next version up, with calls translated as described
+// by t. Chains of translations are supported, so that version 1 may be
+// translated to version 2, which is itself translated to version 3.
+func (f *Registry) RegisterTranslation(name string, version int, t Translation) error {
+	newer, ok := f.facades[name][version+1]
+	if !ok {
+		return errors.NotFoundf("%s(%d)", name, version+1)
+	}
+	translations := append([]Translation{t}, newer.translations...)
+	if err := f.Register(name, version, newer.factory, newer.facadeType, newer.feature); err != nil {
+		return errors.Trace(err)
+	}
+	record := f.facades[name][version]
+	record.translations = translations
+	f.facades[name][version] = record
+	return nil
+}
+
+// Deprecate marks an already registered facade version as deprecated.
+// The version continues to be served; the deprecation is reported to
+// clients so that they can move to a newer version in good time.
+func (f *Registry) Deprecate(name string, version int, d Deprecation) error {
+	record, ok := f.facades[name][version]
+	if !ok {
+		return errors.NotFoundf("%s(%d)", name, version)
+	}
+	record.deprecation = &d
+	f.facades[name][version] = record
+	return nil
+}
+
+// RequireAccess records that users need the given access to call the
+// named method of any version of the named facade, which must already
+// be registered. SuperuserAccess is required on the controller; model
+// access levels are required on the model the user is connected to.
+func (f *Registry) RequireAccess(name, method string, access permission.Access) error {
+	vers, ok := f.facades[name]
+	if !ok {
+		return errors.NotFoundf("facade %q", name)
+	}
+	found := false
+	for _, record := range vers {
+		if _, ok := record.facadeType.MethodByName(method); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.NotFoundf("method %s.%s", name, method)
+	}
+	if access != permission.SuperuserAccess {
+		if err := permission.ValidateModelAccess(access); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if f.access == nil {
+		f.access = make(map[string]map[string]permission.Access)
+	}
+	if f.access[name] == nil {
+		f.access[name] = make(map[string]permission.Access)
+	}
+	f.access[name][method] = access
+	return nil
+}
+
+// RequiredAccess returns the access users need to call the named
+// method of the named facade, and whether any has been recorded.
+func (f *Registry) RequiredAccess(name, method string) (permission.Access, bool) {
+	access, ok := f.access[name][method]
+	return access, ok
+}
+
 // lookup translates a facade name and version into a record.
 func (f *Registry) lookup(name string, version int) (record, error) {
 	if versions, ok := f.facades[name]; ok {
@@ -97,26 +195,47 @@ func (f *Registry) GetType(name string, version int) (reflect.Type, error) {
 	return record.facadeType, nil
 }
 
+// GetTranslations returns the translations, oldest first, needed to
+// serve the given facade version. It returns nil if the version is
+// implemented directly.
+func (f *Registry) GetTranslations(name string, version int) ([]Translation, error) {
+	record, err := f.lookup(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return record.translations, nil
+}
+
 // Description describes the name and what versions of a facade have been
-// registered.
+// registered, and which of those versions are deprecated.
 type Description struct {
-	Name     string
-	Versions []int
+	Name       string
+	Versions   []int
+	Deprecated map[int]Deprecation
 }
 
 // descriptionFromVersions aggregates the information in a versions map into a
 // more friendly form for List().
 func descriptionFromVersions(name string, vers versions) Description {
 	intVersions := make([]int, 0, len(vers))
+	var deprecated map[int]Deprecation
 	for version, record := range vers {
-		if featureflag.Enabled(record.feature) {
-			intVersions = append(intVersions, version)
+		if !featureflag.Enabled(record.feature) {
+			continue
+		}
+		intVersions = append(intVersions, version)
+		if record.deprecation != nil {
+			if deprecated == nil {
+				deprecated = make(map[int]Deprecation)
+			}
+			deprecated[version] = *record.deprecation
 		}
 	}
 	sort.Ints(intVersions)
 	return Description{
-		Name:     name,
-		Versions: intVersions,
+		Name:       name,
+		Versions:   intVersions,
+		Deprecated: deprecated,
 	}
 }
 
@@ -145,6 +264,7 @@ func (f *Registry) Discard(name string, version int) {
 		delete(versions, version)
 		if len(versions) == 0 {
 			delete(f.facades, name)
+			delete(f.access, name)
 		}
 	}
 }
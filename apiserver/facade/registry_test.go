@@ -8,9 +8,11 @@ import (
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/testing"
 )
 
@@ -175,6 +177,107 @@ func (*RegistrySuite) TestDiscardLeavesOtherVersions(c *gc.C) {
 	})
 }
 
+func (*RegistrySuite) TestRegisterTranslation(c *gc.C) {
+	registry := &facade.Registry{}
+	assertRegister(c, registry, "name", 2)
+	err := registry.RegisterTranslation("name", 1, facade.Translation{Omit: []string{"B"}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = registry.RegisterTranslation("name", 0, facade.Translation{Omit: []string{"A"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(registry.List(), jc.DeepEquals, []facade.Description{
+		{Name: "name", Versions: []int{0, 1, 2}},
+	})
+	typ, err := registry.GetType("name", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(typ, gc.Equals, intPtrType)
+
+	translations, err := registry.GetTranslations("name", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(translations, jc.DeepEquals, []facade.Translation{
+		{Omit: []string{"A"}},
+		{Omit: []string{"B"}},
+	})
+	translations, err = registry.GetTranslations("name", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(translations, gc.HasLen, 0)
+}
+
+func (*RegistrySuite) TestRegisterTranslationRequiresNewerVersion(c *gc.C) {
+	registry := &facade.Registry{}
+	assertRegister(c, registry, "name", 3)
+	err := registry.RegisterTranslation("name", 1, facade.Translation{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `name\(2\) not found`)
+}
+
+func (*RegistrySuite) TestDeprecate(c *gc.C) {
+	registry := &facade.Registry{}
+	assertRegister(c, registry, "name", 1)
+	assertRegister(c, registry, "name", 2)
+	d := facade.Deprecation{
+		Since:  version.MustParse("2.2.0"),
+		Sunset: version.MustParse("3.0.0"),
+	}
+	err := registry.Deprecate("name", 1, d)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(registry.List(), jc.DeepEquals, []facade.Description{{
+		Name:       "name",
+		Versions:   []int{1, 2},
+		Deprecated: map[int]facade.Deprecation{1: d},
+	}})
+}
+
+func (*RegistrySuite) TestDeprecateUnknownVersion(c *gc.C) {
+	registry := &facade.Registry{}
+	assertRegister(c, registry, "name", 2)
+	err := registry.Deprecate("name", 1, facade.Deprecation{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `name\(1\) not found`)
+}
+
+func (*RegistrySuite) TestRequireAccess(c *gc.C) {
+	registry := &facade.Registry{}
+	err := registry.Register("name", 1, validIdFactory, accessFacadeType, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, ok := registry.RequiredAccess("name", "Save")
+	c.Check(ok, jc.IsFalse)
+
+	err = registry.RequireAccess("name", "Save", permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, ok := registry.RequiredAccess("name", "Save")
+	c.Check(ok, jc.IsTrue)
+	c.Check(access, gc.Equals, permission.WriteAccess)
+
+	err = registry.RequireAccess("name", "Save", permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, _ = registry.RequiredAccess("name", "Save")
+	c.Check(access, gc.Equals, permission.SuperuserAccess)
+
+	registry.Discard("name", 1)
+	_, ok = registry.RequiredAccess("name", "Save")
+	c.Check(ok, jc.IsFalse)
+}
+
+func (*RegistrySuite) TestRequireAccessErrors(c *gc.C) {
+	registry := &facade.Registry{}
+	err := registry.Register("name", 1, validIdFactory, accessFacadeType, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = registry.RequireAccess("other", "Save", permission.ReadAccess)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `facade "other" not found`)
+
+	err = registry.RequireAccess("name", "Destroy", permission.ReadAccess)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `method name.Destroy not found`)
+
+	err = registry.RequireAccess("name", "Save", permission.LoginAccess)
+	c.Check(err, gc.ErrorMatches, `"login" model access not valid`)
+}
+
 func testFacade(facade.Context) (facade.Facade, error) {
 	return "myobject", nil
 }
@@ -187,6 +290,12 @@ func validIdFactory(facade.Context) (facade.Facade, error) {
 var intPtr = new(int)
 var intPtrType = reflect.TypeOf(&intPtr).Elem()
 
+type accessFacade struct{}
+
+func (accessFacade) Save() error { return nil }
+
+var accessFacadeType = reflect.TypeOf(accessFacade{})
+
 func assertRegister(c *gc.C, registry *facade.Registry, name string, version int) {
 	assertRegisterFlag(c, registry, name, version, "")
 }
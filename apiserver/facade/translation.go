@@ -0,0 +1,158 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package facade
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/juju/errors"
+	"golang.org/x/net/context"
+
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// Translation describes how an older version of a facade differs from
+// the next version up, so that calls made against the older version
+// can be served by the newer implementation.
+//
+// Methods not mentioned in a Translation are served unchanged.
+type Translation struct {
+	// Methods holds the translations for methods whose params or
+	// results differ between the two versions, keyed by method name.
+	Methods map[string]MethodTranslation
+
+	// Omit holds the names of methods that were added in the newer
+	// version, and so must not be exposed by the older one.
+	Omit []string
+}
+
+// MethodTranslation describes how a single method differs between
+// adjacent facade versions.
+type MethodTranslation struct {
+	// Params holds the params type of the method in the older
+	// version. If nil, the params type is unchanged.
+	Params reflect.Type
+
+	// Result holds the result type of the method in the older
+	// version. If nil, the result type is unchanged.
+	Result reflect.Type
+
+	// Up converts params of the older version into params of the
+	// newer version. If nil, ConvertJSON is used.
+	Up func(in interface{}, out interface{}) error
+
+	// Down converts a result of the newer version into a result of
+	// the older version. If nil, ConvertJSON is used.
+	Down func(in interface{}, out interface{}) error
+}
+
+// ConvertJSON converts in to out by way of their JSON encodings.
+// Since params are exchanged as JSON, this is exactly the translation
+// an older client would see if it were sent the newer value: fields
+// unknown to out are dropped, and fields unknown to in are left at
+// their zero values.
+func ConvertJSON(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(data, out))
+}
+
+func (t Translation) omits(method string) bool {
+	for _, name := range t.Omit {
+		if name == method {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslateMethod returns an ObjMethod that serves the named method
+// through the given chain of translations, ordered from the oldest
+// version to the newest. The supplied method is that of the newest
+// version.
+func TranslateMethod(chain []Translation, methodName string, method rpcreflect.ObjMethod) (rpcreflect.ObjMethod, error) {
+	for _, t := range chain {
+		if t.omits(methodName) {
+			return rpcreflect.ObjMethod{}, rpcreflect.ErrMethodNotFound
+		}
+	}
+	// Work out the params and result types at each step, from the
+	// newest version back to the oldest.
+	steps := make([]translationStep, len(chain))
+	paramsType, resultType := method.Params, method.Result
+	for i := len(chain) - 1; i >= 0; i-- {
+		mt := chain[i].Methods[methodName]
+		step := translationStep{
+			newParams: paramsType,
+			newResult: resultType,
+			up:        mt.Up,
+			down:      mt.Down,
+		}
+		if mt.Params != nil {
+			paramsType = mt.Params
+		}
+		if mt.Result != nil {
+			resultType = mt.Result
+		}
+		step.oldParams, step.oldResult = paramsType, resultType
+		steps[i] = step
+	}
+	call := method.Call
+	return rpcreflect.ObjMethod{
+		Params: paramsType,
+		Result: resultType,
+		Call: func(ctx context.Context, rcvr, arg reflect.Value) (reflect.Value, error) {
+			for _, step := range steps {
+				var err error
+				if arg, err = step.translateParams(arg); err != nil {
+					return reflect.Value{}, errors.Annotate(err, "translating params")
+				}
+			}
+			result, err := call(ctx, rcvr, arg)
+			if err != nil || !result.IsValid() {
+				return result, err
+			}
+			for i := len(steps) - 1; i >= 0; i-- {
+				if result, err = steps[i].translateResult(result); err != nil {
+					return reflect.Value{}, errors.Annotate(err, "translating result")
+				}
+			}
+			return result, nil
+		},
+	}, nil
+}
+
+// translationStep holds the types and conversions for a single step
+// between adjacent facade versions.
+type translationStep struct {
+	oldParams, newParams reflect.Type
+	oldResult, newResult reflect.Type
+	up, down             func(interface{}, interface{}) error
+}
+
+func (s translationStep) translateParams(arg reflect.Value) (reflect.Value, error) {
+	return convertValue(arg, s.oldParams, s.newParams, s.up)
+}
+
+func (s translationStep) translateResult(result reflect.Value) (reflect.Value, error) {
+	return convertValue(result, s.newResult, s.oldResult, s.down)
+}
+
+// convertValue converts v, of type from, into a new value of type to.
+func convertValue(v reflect.Value, from, to reflect.Type, convert func(interface{}, interface{}) error) (reflect.Value, error) {
+	if from == to || from == nil || to == nil {
+		return v, nil
+	}
+	if convert == nil {
+		convert = ConvertJSON
+	}
+	out := reflect.New(to)
+	if err := convert(v.Interface(), out.Interface()); err != nil {
+		return reflect.Value{}, errors.Trace(err)
+	}
+	return out.Elem(), nil
+}
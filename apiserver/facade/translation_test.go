@@ -0,0 +1,138 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package facade_test
+
+import (
+	"reflect"
+
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/testing"
+)
+
+type TranslationSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&TranslationSuite{})
+
+type argsV1 struct {
+	Name string `json:"name"`
+}
+
+type argsV2 struct {
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+type resultV1 struct {
+	Value string `json:"value"`
+}
+
+type resultV2 struct {
+	Value string `json:"value"`
+	Extra string `json:"extra"`
+}
+
+type translatedFacade struct {
+	called argsV2
+}
+
+func (f *translatedFacade) Get(args argsV2) (resultV2, error) {
+	f.called = args
+	return resultV2{Value: args.Name, Extra: "extra"}, nil
+}
+
+func (f *translatedFacade) New() error {
+	return nil
+}
+
+func (s *TranslationSuite) method(c *gc.C, name string) rpcreflect.ObjMethod {
+	method, err := rpcreflect.ObjTypeOf(reflect.TypeOf(&translatedFacade{})).Method(name)
+	c.Assert(err, jc.ErrorIsNil)
+	return method
+}
+
+func (s *TranslationSuite) TestTranslateMethodJSON(c *gc.C) {
+	chain := []facade.Translation{{
+		Methods: map[string]facade.MethodTranslation{
+			"Get": {
+				Params: reflect.TypeOf(argsV1{}),
+				Result: reflect.TypeOf(resultV1{}),
+			},
+		},
+	}}
+	method, err := facade.TranslateMethod(chain, "Get", s.method(c, "Get"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(method.Params, gc.Equals, reflect.TypeOf(argsV1{}))
+	c.Assert(method.Result, gc.Equals, reflect.TypeOf(resultV1{}))
+
+	f := &translatedFacade{}
+	result, err := method.Call(context.Background(), reflect.ValueOf(f), reflect.ValueOf(argsV1{Name: "foo"}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.called, jc.DeepEquals, argsV2{Name: "foo"})
+	c.Assert(result.Interface(), jc.DeepEquals, resultV1{Value: "foo"})
+}
+
+func (s *TranslationSuite) TestTranslateMethodCustom(c *gc.C) {
+	chain := []facade.Translation{{
+		Methods: map[string]facade.MethodTranslation{
+			"Get": {
+				Params: reflect.TypeOf(argsV1{}),
+				Up: func(in, out interface{}) error {
+					*out.(*argsV2) = argsV2{Name: in.(argsV1).Name, Count: 1}
+					return nil
+				},
+			},
+		},
+	}}
+	method, err := facade.TranslateMethod(chain, "Get", s.method(c, "Get"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(method.Result, gc.Equals, reflect.TypeOf(resultV2{}))
+
+	f := &translatedFacade{}
+	_, err = method.Call(context.Background(), reflect.ValueOf(f), reflect.ValueOf(argsV1{Name: "foo"}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.called, jc.DeepEquals, argsV2{Name: "foo", Count: 1})
+}
+
+func (s *TranslationSuite) TestTranslateMethodChain(c *gc.C) {
+	chain := []facade.Translation{{
+		Methods: map[string]facade.MethodTranslation{
+			"Get": {Params: reflect.TypeOf(argsV1{})},
+		},
+	}, {
+		Methods: map[string]facade.MethodTranslation{
+			"Get": {Result: reflect.TypeOf(resultV1{})},
+		},
+	}}
+	method, err := facade.TranslateMethod(chain, "Get", s.method(c, "Get"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(method.Params, gc.Equals, reflect.TypeOf(argsV1{}))
+	c.Assert(method.Result, gc.Equals, reflect.TypeOf(resultV1{}))
+
+	result, err := method.Call(context.Background(), reflect.ValueOf(&translatedFacade{}), reflect.ValueOf(argsV1{Name: "bar"}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Interface(), jc.DeepEquals, resultV1{Value: "bar"})
+}
+
+func (s *TranslationSuite) TestTranslateMethodOmitted(c *gc.C) {
+	chain := []facade.Translation{{Omit: []string{"New"}}}
+	_, err := facade.TranslateMethod(chain, "New", s.method(c, "New"))
+	c.Assert(err, gc.Equals, rpcreflect.ErrMethodNotFound)
+
+	_, err = facade.TranslateMethod(chain, "Get", s.method(c, "Get"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *TranslationSuite) TestConvertJSON(c *gc.C) {
+	var out resultV1
+	err := facade.ConvertJSON(resultV2{Value: "v", Extra: "e"}, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, resultV1{Value: "v"})
+}
@@ -101,6 +101,12 @@ func (ctxt *httpContext) stateForRequestAuthenticated(r *http.Request) (
 	if err != nil {
 		return nil, nil, nil, errors.NewUnauthorized(err, "")
 	}
+	kind, err := names.TagKind(req.AuthTag)
+	isUser := err != nil || kind == names.UserTagKind
+	if !ctxt.srv.allowedClients.allowsLogin(r.RemoteAddr, isUser) {
+		logger.Infof("refusing login of %q from %s", req.AuthTag, r.RemoteAddr)
+		return nil, nil, nil, common.ErrAddressNotAllowed
+	}
 	authenticator := ctxt.srv.authCtxt.authenticator(r.Host)
 	entity, _, err := checkCreds(st, req, true, authenticator)
 	if err != nil {
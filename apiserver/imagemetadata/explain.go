@@ -0,0 +1,194 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package imagemetadata
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+	"golang.org/x/net/context"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state/cloudimagemetadata"
+)
+
+// Explain simulates the image selection that would be made when
+// provisioning a machine with the given series, architectures,
+// region and constraints, and reports the selected image together
+// with every candidate considered, in order of preference.
+//
+// Unspecified criteria are filled in the same way as for provisioning:
+// series from the model's default series, region from the model's
+// cloud region and stream from the model's image-stream setting.
+// Only image metadata cached by the controller is considered. Images
+// are matched against the model provider's instance types using the
+// same selection as provisioning. Providers that do not report their
+// instance types have no instance types to reject images with, so the
+// most preferred image is reported as selected.
+func (api *API) Explain(ctx context.Context, args params.ImageMetadataExplainParams) (params.ImageMetadataExplainResult, error) {
+	if api.authorizer.AuthClient() {
+		admin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.metadata.ControllerTag())
+		if err != nil {
+			return params.ImageMetadataExplainResult{}, errors.Trace(err)
+		}
+		if !admin {
+			return params.ImageMetadataExplainResult{}, common.ServerError(common.ErrPerm)
+		}
+	}
+
+	filter, err := api.explainFilter(args)
+	if err != nil {
+		return params.ImageMetadataExplainResult{}, common.ServerError(err)
+	}
+	found, err := api.metadata.FindMetadata(cloudimagemetadata.MetadataFilter{
+		Region: filter.Region,
+		Series: filter.Series,
+		Arches: filter.Arches,
+		Stream: filter.Stream,
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return params.ImageMetadataExplainResult{}, common.ServerError(err)
+	}
+
+	var all []params.CloudImageMetadata
+	for _, ms := range found {
+		for _, m := range ms {
+			all = append(all, parseMetadataToParams(m))
+		}
+	}
+	result := params.ImageMetadataExplainResult{Filter: filter}
+	if len(all) == 0 {
+		return result, nil
+	}
+
+	env, err := api.newEnviron()
+	if err != nil {
+		return params.ImageMetadataExplainResult{}, common.ServerError(errors.Annotate(err, "getting environ"))
+	}
+	instanceTypes, err := env.InstanceTypes(ctx, args.Constraints)
+	if errors.IsNotSupported(err) {
+		sort.Stable(metadataList(all))
+		result.Candidates = make([]params.ImageMetadataCandidate, len(all))
+		for i, m := range all {
+			result.Candidates[i] = params.ImageMetadataCandidate{Metadata: m}
+		}
+		sort.Stable(byPreference(result.Candidates))
+		selected := result.Candidates[0].Metadata
+		result.Selected = &selected
+		return result, nil
+	}
+	if err != nil {
+		return params.ImageMetadataExplainResult{}, common.ServerError(errors.Annotate(err, "getting instance types"))
+	}
+	ic := &instances.InstanceConstraint{
+		Region:      filter.Region,
+		Series:      filter.Series[0],
+		Arches:      filter.Arches,
+		Constraints: args.Constraints,
+	}
+	sort.Stable(metadataList(all))
+	images := make([]instances.Image, len(all))
+	for i, m := range all {
+		images[i] = instances.Image{Id: m.ImageId, Arch: m.Arch, VirtType: m.VirtType}
+	}
+	result.Candidates = rankCandidates(all, images, ic, instanceTypes.InstanceTypes)
+	if spec, err := instances.FindInstanceSpec(images, ic, instanceTypes.InstanceTypes); err == nil {
+		for i, image := range images {
+			if image == spec.Image {
+				selected := all[i]
+				result.Selected = &selected
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// explainFilter returns the image metadata filter that provisioning
+// would use for the given hypothetical request.
+func (api *API) explainFilter(args params.ImageMetadataExplainParams) (params.ImageMetadataFilter, error) {
+	cfg, err := api.metadata.ModelConfig()
+	if err != nil {
+		return params.ImageMetadataFilter{}, errors.Annotate(err, "getting model config")
+	}
+	filter := params.ImageMetadataFilter{
+		Region: args.Region,
+		Stream: args.Stream,
+		Arches: args.Arches,
+	}
+	if args.Series != "" {
+		filter.Series = []string{args.Series}
+	} else {
+		filter.Series = []string{config.PreferredSeries(cfg)}
+	}
+	if filter.Stream == "" {
+		filter.Stream = cfg.ImageStream()
+	}
+	if args.Constraints.HasArch() {
+		filter.Arches = args.Constraints.Arches()
+	}
+	if filter.Region == "" {
+		model, err := api.metadata.Model()
+		if err != nil {
+			return params.ImageMetadataFilter{}, errors.Annotate(err, "getting model")
+		}
+		filter.Region = model.CloudRegion()
+	}
+	return filter, nil
+}
+
+// rankCandidates orders image metadata the way a provider sees it
+// when provisioning: by priority, then by architecture preference.
+// Candidates that no matching instance type can use are marked as
+// rejected, with the reason instances.FindInstanceSpec gives, and
+// placed after the acceptable ones.
+func rankCandidates(
+	all []params.CloudImageMetadata,
+	images []instances.Image,
+	ic *instances.InstanceConstraint,
+	instanceTypes []instances.InstanceType,
+) []params.ImageMetadataCandidate {
+	candidates := make([]params.ImageMetadataCandidate, len(all))
+	for i, m := range all {
+		candidates[i] = params.ImageMetadataCandidate{Metadata: m}
+		if _, err := instances.FindInstanceSpec(images[i:i+1], ic, instanceTypes); err != nil {
+			candidates[i].Rejected = err.Error()
+		}
+	}
+	sort.Stable(byPreference(candidates))
+	return candidates
+}
+
+// byPreference sorts acceptable candidates before rejected ones, and
+// otherwise prefers wider word-size architectures, then architecture
+// names alphabetically, as instances.FindInstanceSpec does.
+type byPreference []params.ImageMetadataCandidate
+
+func (b byPreference) Len() int {
+	return len(b)
+}
+
+func (b byPreference) Less(i, j int) bool {
+	iRejected, jRejected := b[i].Rejected != "", b[j].Rejected != ""
+	if iRejected != jRejected {
+		return !iRejected
+	}
+	iArchName := b[i].Metadata.Arch
+	jArchName := b[j].Metadata.Arch
+	iArch := arch.Info[iArchName]
+	jArch := arch.Info[jArchName]
+	if iArch.WordSize != jArch.WordSize {
+		return iArch.WordSize > jArch.WordSize
+	}
+	return iArchName < jArchName
+}
+
+func (b byPreference) Swap(i, j int) {
+	b[i], b[j] = b[j], b[i]
+}
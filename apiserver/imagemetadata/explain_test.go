@@ -0,0 +1,132 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package imagemetadata_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/series"
+	"golang.org/x/net/context"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/imagemetadata"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/state/cloudimagemetadata"
+)
+
+type explainSuite struct {
+	baseImageMetadataSuite
+}
+
+var _ = gc.Suite(&explainSuite{})
+
+func (s *explainSuite) TestExplainDefaults(c *gc.C) {
+	var filter cloudimagemetadata.MetadataFilter
+	s.state.findMetadata = func(f cloudimagemetadata.MetadataFilter) (map[string][]cloudimagemetadata.Metadata, error) {
+		filter = f
+		return nil, nil
+	}
+
+	result, err := s.api.Explain(context.Background(), params.ImageMetadataExplainParams{
+		Constraints: constraints.MustParse("arch=arm64"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Selected, gc.IsNil)
+	c.Assert(result.Candidates, gc.HasLen, 0)
+	c.Assert(result.Filter, jc.DeepEquals, params.ImageMetadataFilter{
+		Region: "meep",
+		Series: []string{series.LatestLts()},
+		Arches: []string{"arm64"},
+		Stream: "released",
+	})
+	c.Assert(filter, jc.DeepEquals, cloudimagemetadata.MetadataFilter{
+		Region: "meep",
+		Series: []string{series.LatestLts()},
+		Arches: []string{"arm64"},
+		Stream: "released",
+	})
+	s.assertCalls(c, "ControllerTag", environConfig, "Model", findMetadata)
+}
+
+func (s *explainSuite) TestExplainRanking(c *gc.C) {
+	s.state.findMetadata = func(f cloudimagemetadata.MetadataFilter) (map[string][]cloudimagemetadata.Metadata, error) {
+		return map[string][]cloudimagemetadata.Metadata{
+			"public": []cloudimagemetadata.Metadata{
+				{ImageId: "i386", Priority: 10, MetadataAttributes: cloudimagemetadata.MetadataAttributes{Arch: "i386"}},
+				{ImageId: "pv", Priority: 5, MetadataAttributes: cloudimagemetadata.MetadataAttributes{Arch: "amd64", VirtType: "pv"}},
+				{ImageId: "public", Priority: 10, MetadataAttributes: cloudimagemetadata.MetadataAttributes{Arch: "amd64", VirtType: "hvm"}},
+			},
+			"custom": []cloudimagemetadata.Metadata{
+				{ImageId: "custom", Priority: 50, MetadataAttributes: cloudimagemetadata.MetadataAttributes{Arch: "amd64"}},
+			},
+		}, nil
+	}
+
+	result, err := s.api.Explain(context.Background(), params.ImageMetadataExplainParams{
+		Series:      "trusty",
+		Arches:      []string{"amd64", "i386"},
+		Region:      "region",
+		Stream:      "daily",
+		Constraints: constraints.MustParse("virt-type=hvm"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var ids, rejected []string
+	for _, candidate := range result.Candidates {
+		ids = append(ids, candidate.Metadata.ImageId)
+		rejected = append(rejected, candidate.Rejected)
+	}
+	c.Assert(ids, jc.DeepEquals, []string{"public", "custom", "i386", "pv"})
+	c.Assert(rejected, jc.DeepEquals, []string{
+		"", "", "", `no "trusty" images in region matching instance types [m1]`,
+	})
+	c.Assert(result.Selected, gc.NotNil)
+	c.Assert(result.Selected.ImageId, gc.Equals, "public")
+	s.assertCalls(c, "ControllerTag", environConfig, findMetadata)
+}
+
+func (s *explainSuite) TestExplainWithoutInstanceTypes(c *gc.C) {
+	s.state.findMetadata = func(f cloudimagemetadata.MetadataFilter) (map[string][]cloudimagemetadata.Metadata, error) {
+		return map[string][]cloudimagemetadata.Metadata{
+			"public": []cloudimagemetadata.Metadata{
+				{ImageId: "i386", Priority: 10, MetadataAttributes: cloudimagemetadata.MetadataAttributes{Arch: "i386"}},
+				{ImageId: "pv", Priority: 5, MetadataAttributes: cloudimagemetadata.MetadataAttributes{Arch: "amd64", VirtType: "pv"}},
+			},
+			"custom": []cloudimagemetadata.Metadata{
+				{ImageId: "custom", Priority: 50, MetadataAttributes: cloudimagemetadata.MetadataAttributes{Arch: "amd64"}},
+			},
+		}, nil
+	}
+	api, err := imagemetadata.CreateAPI(s.state, func() (environs.Environ, error) {
+		return &noInstanceTypesEnviron{}, nil
+	}, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := api.Explain(context.Background(), params.ImageMetadataExplainParams{
+		Series: "trusty",
+		Arches: []string{"amd64", "i386"},
+		Region: "region",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []string
+	for _, candidate := range result.Candidates {
+		ids = append(ids, candidate.Metadata.ImageId)
+		c.Check(candidate.Rejected, gc.Equals, "")
+	}
+	c.Assert(ids, jc.DeepEquals, []string{"pv", "custom", "i386"})
+	c.Assert(result.Selected, gc.NotNil)
+	c.Assert(result.Selected.ImageId, gc.Equals, "pv")
+}
+
+// noInstanceTypesEnviron is an environment whose provider does not
+// report its instance types.
+type noInstanceTypesEnviron struct {
+	mockEnviron
+}
+
+func (e *noInstanceTypesEnviron) InstanceTypes(context.Context, constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+	return instances.InstanceTypesWithCostMetadata{}, errors.NotSupportedf("InstanceTypes")
+}
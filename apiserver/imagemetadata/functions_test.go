@@ -74,5 +74,7 @@ func (s *funcMetadataSuite) assertProcessErrorsNone(c *gc.C, errs []params.Error
 }
 
 func (s *funcMetadataSuite) assertProcessErrors(c *gc.C, errs []params.ErrorResult, expected string) {
-	c.Assert(process(errs), gc.ErrorMatches, expected)
+	err := process(errs)
+	c.Assert(err, gc.NotNil)
+	c.Assert(err.Error(), gc.Equals, expected)
 }
@@ -22,12 +22,20 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/state/stateenvirons"
+	"github.com/juju/juju/state/watcher"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.imagemetadata")
 
 func init() {
 	common.RegisterStandardFacade("ImageMetadata", 2, NewAPI)
+	// Version 3 adds Explain.
+	common.RegisterStandardFacade("ImageMetadata", 3, NewAPI)
+	// Version 4 adds Watch.
+	common.RegisterStandardFacade("ImageMetadata", 4, NewAPIv4)
+	common.RequireFacadeAccess("ImageMetadata", "Save", permission.SuperuserAccess)
+	common.RequireFacadeAccess("ImageMetadata", "Delete", permission.SuperuserAccess)
+	common.RequireFacadeAccess("ImageMetadata", "UpdateFromPublishedImages", permission.SuperuserAccess)
 }
 
 // API is the concrete implementation of the api end point
@@ -35,9 +43,16 @@ func init() {
 type API struct {
 	metadata   metadataAcess
 	newEnviron func() (environs.Environ, error)
+	resources  facade.Resources
 	authorizer facade.Authorizer
 }
 
+// APIv4 extends the image metadata API with the ability to watch
+// the stored cloud image metadata.
+type APIv4 struct {
+	*API
+}
+
 // createAPI returns a new image metadata API facade.
 func createAPI(
 	st metadataAcess,
@@ -52,6 +67,7 @@ func createAPI(
 	return &API{
 		metadata:   st,
 		newEnviron: newEnviron,
+		resources:  resources,
 		authorizer: authorizer,
 	}, nil
 }
@@ -68,9 +84,46 @@ func NewAPI(
 	return createAPI(getState(st), newEnviron, resources, authorizer)
 }
 
+// NewAPIv4 returns a new cloud image metadata API facade, version 4.
+func NewAPIv4(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*APIv4, error) {
+	api, err := NewAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &APIv4{api}, nil
+}
+
+// Watch returns a strings watcher that notifies of the ids of cloud
+// image metadata as they are added, changed or removed.
+func (api *APIv4) Watch() (params.StringsWatchResult, error) {
+	if api.authorizer.AuthClient() {
+		admin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.metadata.ControllerTag())
+		if err != nil {
+			return params.StringsWatchResult{}, errors.Trace(err)
+		}
+		if !admin {
+			return params.StringsWatchResult{}, common.ServerError(common.ErrPerm)
+		}
+	}
+	w := api.metadata.WatchCloudImageMetadata()
+	if changes, ok := <-w.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: api.resources.Register(w),
+			Changes:          changes,
+		}, nil
+	}
+	return params.StringsWatchResult{}, watcher.EnsureErr(w)
+}
+
 // List returns all found cloud image metadata that satisfy
 // given filter.
 // Returned list contains metadata ordered by priority.
+// If the filter selects a page of the results, only that page is
+// returned.
 func (api *API) List(filter params.ImageMetadataFilter) (params.ListCloudImageMetadataResult, error) {
 	if api.authorizer.AuthClient() {
 		admin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.metadata.ControllerTag())
@@ -106,7 +159,17 @@ func (api *API) List(filter params.ImageMetadataFilter) (params.ListCloudImageMe
 	}
 	sort.Sort(metadataList(all))
 
-	return params.ListCloudImageMetadataResult{Result: all}, nil
+	if filter.Pagination == nil {
+		return params.ListCloudImageMetadataResult{Result: all}, nil
+	}
+	start, end, page, err := common.Paginate(len(all), *filter.Pagination)
+	if err != nil {
+		return params.ListCloudImageMetadataResult{}, common.ServerError(err)
+	}
+	return params.ListCloudImageMetadataResult{
+		Result:     all[start:end],
+		Pagination: &page,
+	}, nil
 }
 
 // Save stores given cloud image metadata.
@@ -129,8 +192,12 @@ func (api *API) Save(metadata params.MetadataSaveParams) (params.ErrorResults, e
 	if err != nil {
 		return params.ErrorResults{}, errors.Annotatef(err, "getting model config")
 	}
+	model, err := api.metadata.Model()
+	if err != nil {
+		return params.ErrorResults{}, errors.Annotatef(err, "getting model")
+	}
 	for i, one := range metadata.Metadata {
-		md := api.parseMetadataListFromParams(one, modelCfg)
+		md := api.parseMetadataListFromParams(one, modelCfg, model.Cloud())
 		err := api.metadata.SaveMetadata(md)
 		all[i] = params.ErrorResult{Error: common.ServerError(err)}
 	}
@@ -174,12 +241,13 @@ func parseMetadataToParams(p cloudimagemetadata.Metadata) params.CloudImageMetad
 	return result
 }
 
-func (api *API) parseMetadataListFromParams(p params.CloudImageMetadataList, cfg *config.Config) []cloudimagemetadata.Metadata {
+func (api *API) parseMetadataListFromParams(p params.CloudImageMetadataList, cfg *config.Config, cloud string) []cloudimagemetadata.Metadata {
 	results := make([]cloudimagemetadata.Metadata, len(p.Metadata))
 	for i, metadata := range p.Metadata {
 		results[i] = cloudimagemetadata.Metadata{
 			MetadataAttributes: cloudimagemetadata.MetadataAttributes{
 				Stream:          metadata.Stream,
+				Cloud:           cloud,
 				Region:          metadata.Region,
 				Version:         metadata.Version,
 				Series:          metadata.Series,
@@ -317,7 +385,10 @@ func processErrors(errs []params.ErrorResult) error {
 }
 
 // metadataList is a convenience type enabling to sort
-// a collection of Metadata in order of priority.
+// a collection of Metadata in order of priority. Metadata of
+// equal priority are ordered by source, image id, region, series
+// and architecture, so that the order is the same on every call
+// and pages of results neither repeat nor skip entries.
 type metadataList []params.CloudImageMetadata
 
 // Len implements sort.Interface
@@ -325,9 +396,28 @@ func (m metadataList) Len() int {
 	return len(m)
 }
 
-// Less implements sort.Interface and sorts image metadata by priority.
+// Less implements sort.Interface.
 func (m metadataList) Less(i, j int) bool {
-	return m[i].Priority < m[j].Priority
+	a, b := m[i], m[j]
+	switch {
+	case a.Priority != b.Priority:
+		return a.Priority < b.Priority
+	case a.Source != b.Source:
+		return a.Source < b.Source
+	case a.ImageId != b.ImageId:
+		return a.ImageId < b.ImageId
+	case a.Region != b.Region:
+		return a.Region < b.Region
+	case a.Series != b.Series:
+		return a.Series < b.Series
+	case a.Arch != b.Arch:
+		return a.Arch < b.Arch
+	case a.Stream != b.Stream:
+		return a.Stream < b.Stream
+	case a.VirtType != b.VirtType:
+		return a.VirtType < b.VirtType
+	}
+	return a.RootStorageType < b.RootStorageType
 }
 
 // Swap implements sort.Interface
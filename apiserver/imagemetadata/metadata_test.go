@@ -8,7 +8,9 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/imagemetadata"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/cloudimagemetadata"
 )
 
@@ -57,7 +59,7 @@ func (s *metadataSuite) TestFindError(c *gc.C) {
 	}
 
 	found, err := s.api.List(params.ImageMetadataFilter{})
-	c.Assert(err, gc.ErrorMatches, msg)
+	c.Assert(err, jc.DeepEquals, &params.Error{Message: msg})
 	c.Assert(found.Result, gc.HasLen, 0)
 	s.assertCalls(c, "ControllerTag", findMetadata)
 }
@@ -95,6 +97,79 @@ func (s *metadataSuite) TestFindOrder(c *gc.C) {
 	s.assertCalls(c, "ControllerTag", findMetadata)
 }
 
+func (s *metadataSuite) TestFindPaginated(c *gc.C) {
+	s.state.findMetadata = func(f cloudimagemetadata.MetadataFilter) (map[string][]cloudimagemetadata.Metadata, error) {
+		return map[string][]cloudimagemetadata.Metadata{
+			"custom": []cloudimagemetadata.Metadata{
+				cloudimagemetadata.Metadata{ImageId: "image1", Priority: 10},
+				cloudimagemetadata.Metadata{ImageId: "image2", Priority: 20},
+				cloudimagemetadata.Metadata{ImageId: "image3", Priority: 30},
+			},
+		}, nil
+	}
+
+	found, err := s.api.List(params.ImageMetadataFilter{
+		Pagination: &params.Pagination{Limit: 2},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Result, jc.DeepEquals, []params.CloudImageMetadata{
+		{ImageId: "image1", Priority: 10},
+		{ImageId: "image2", Priority: 20},
+	})
+	c.Assert(found.Pagination, gc.NotNil)
+	c.Assert(found.Pagination.NextCursor, gc.Not(gc.Equals), "")
+
+	found, err = s.api.List(params.ImageMetadataFilter{
+		Pagination: &params.Pagination{Limit: 2, Cursor: found.Pagination.NextCursor},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Result, jc.DeepEquals, []params.CloudImageMetadata{
+		{ImageId: "image3", Priority: 30},
+	})
+	c.Assert(found.Pagination, jc.DeepEquals, &params.PaginationResult{})
+}
+
+func (s *metadataSuite) TestFindPaginatedEqualPriority(c *gc.C) {
+	s.state.findMetadata = func(f cloudimagemetadata.MetadataFilter) (map[string][]cloudimagemetadata.Metadata, error) {
+		return map[string][]cloudimagemetadata.Metadata{
+			"public": []cloudimagemetadata.Metadata{
+				cloudimagemetadata.Metadata{ImageId: "image2", Source: "public", Priority: 10},
+				cloudimagemetadata.Metadata{ImageId: "image1", Source: "public", Priority: 10},
+			},
+			"custom": []cloudimagemetadata.Metadata{
+				cloudimagemetadata.Metadata{ImageId: "image3", Source: "custom", Priority: 10},
+			},
+		}, nil
+	}
+
+	var all []params.CloudImageMetadata
+	pagination := params.Pagination{Limit: 1}
+	for {
+		found, err := s.api.List(params.ImageMetadataFilter{Pagination: &pagination})
+		c.Assert(err, jc.ErrorIsNil)
+		all = append(all, found.Result...)
+		if found.Pagination.NextCursor == "" {
+			break
+		}
+		pagination.Cursor = found.Pagination.NextCursor
+	}
+	c.Assert(all, jc.DeepEquals, []params.CloudImageMetadata{
+		{ImageId: "image3", Source: "custom", Priority: 10},
+		{ImageId: "image1", Source: "public", Priority: 10},
+		{ImageId: "image2", Source: "public", Priority: 10},
+	})
+}
+
+func (s *metadataSuite) TestFindInvalidCursor(c *gc.C) {
+	_, err := s.api.List(params.ImageMetadataFilter{
+		Pagination: &params.Pagination{Cursor: "bogus"},
+	})
+	c.Assert(err, jc.DeepEquals, &params.Error{
+		Message: `cursor "bogus" not valid`,
+		Code:    params.CodeNotValid,
+	})
+}
+
 func (s *metadataSuite) TestSaveEmpty(c *gc.C) {
 	errs, err := s.api.Save(params.MetadataSaveParams{})
 	c.Assert(err, jc.ErrorIsNil)
@@ -117,6 +192,8 @@ func (s *metadataSuite) TestSave(c *gc.C) {
 		// Bug# 1616295
 		// Ensure empty stream is changed to release
 		c.Assert(m[0].Stream, gc.DeepEquals, "released")
+		// The model's cloud is recorded with its image metadata.
+		c.Assert(m[0].Cloud, gc.Equals, "dummy")
 		if saveCalls == 1 {
 			// don't err on first call
 			return nil
@@ -134,8 +211,8 @@ func (s *metadataSuite) TestSave(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(errs.Results, gc.HasLen, 2)
 	c.Assert(errs.Results[0].Error, gc.IsNil)
-	c.Assert(errs.Results[1].Error, gc.ErrorMatches, msg)
-	s.assertCalls(c, "ControllerTag", environConfig, saveMetadata, saveMetadata)
+	c.Assert(errs.Results[1].Error, jc.DeepEquals, &params.Error{Message: msg})
+	s.assertCalls(c, "ControllerTag", environConfig, "Model", saveMetadata, saveMetadata)
 }
 
 func (s *metadataSuite) TestDeleteEmpty(c *gc.C) {
@@ -161,6 +238,20 @@ func (s *metadataSuite) TestDelete(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(errs.Results, gc.HasLen, 2)
 	c.Assert(errs.Results[0].Error, gc.IsNil)
-	c.Assert(errs.Results[1].Error, gc.ErrorMatches, msg)
+	c.Assert(errs.Results[1].Error, jc.DeepEquals, &params.Error{Message: msg})
 	s.assertCalls(c, "ControllerTag", deleteMetadata, deleteMetadata)
 }
+
+func (s *metadataSuite) TestWatch(c *gc.C) {
+	s.state.watchMetadata = func() state.StringsWatcher {
+		return newMockStringsWatcher("image-1", "image-2")
+	}
+	api := &imagemetadata.APIv4{API: s.api}
+	result, err := api.Watch()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.StringsWatcherId, gc.Equals, "1")
+	c.Assert(result.Changes, jc.DeepEquals, []string{"image-1", "image-2"})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	s.assertCalls(c, "ControllerTag", "WatchCloudImageMetadata")
+}
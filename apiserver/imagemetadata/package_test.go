@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	imagetesting "github.com/juju/juju/environs/imagemetadata/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/cloudimagemetadata"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -45,7 +46,7 @@ func (s *baseImageMetadataSuite) SetUpTest(c *gc.C) {
 	s.resources = common.NewResources()
 	s.authorizer = testing.FakeAuthorizer{Tag: names.NewUserTag("testuser"), Controller: true, AdminTag: names.NewUserTag("testuser")}
 
-	s.state = s.constructState(testConfig(c), &mockModel{"meep"})
+	s.state = s.constructState(testConfig(c), &mockModel{cloud: "dummy", cloudRegion: "meep"})
 
 	var err error
 	s.api, err = imagemetadata.CreateAPI(s.state, func() (environs.Environ, error) {
@@ -86,6 +87,9 @@ func (s *baseImageMetadataSuite) constructState(cfg *config.Config, model imagem
 		controllerTag: func() names.ControllerTag {
 			return names.NewControllerTag("deadbeef-2f18-4fd2-967d-db9663db7bea")
 		},
+		watchMetadata: func() state.StringsWatcher {
+			return newMockStringsWatcher()
+		},
 	}
 }
 
@@ -98,6 +102,7 @@ type mockState struct {
 	environConfig  func() (*config.Config, error)
 	model          func() (imagemetadata.Model, error)
 	controllerTag  func() names.ControllerTag
+	watchMetadata  func() state.StringsWatcher
 }
 
 func (st *mockState) FindMetadata(f cloudimagemetadata.MetadataFilter) (map[string][]cloudimagemetadata.Metadata, error) {
@@ -130,10 +135,39 @@ func (st *mockState) ControllerTag() names.ControllerTag {
 	return st.controllerTag()
 }
 
+func (st *mockState) WatchCloudImageMetadata() state.StringsWatcher {
+	st.Stub.MethodCall(st, "WatchCloudImageMetadata")
+	return st.watchMetadata()
+}
+
+type mockStringsWatcher struct {
+	state.StringsWatcher
+	changes chan []string
+}
+
+func newMockStringsWatcher(initial ...string) *mockStringsWatcher {
+	w := &mockStringsWatcher{changes: make(chan []string, 1)}
+	w.changes <- initial
+	return w
+}
+
+func (w *mockStringsWatcher) Changes() <-chan []string {
+	return w.changes
+}
+
+func (w *mockStringsWatcher) Stop() error {
+	return nil
+}
+
 type mockModel struct {
+	cloud       string
 	cloudRegion string
 }
 
+func (m *mockModel) Cloud() string {
+	return m.cloud
+}
+
 func (m *mockModel) CloudRegion() string {
 	return m.cloudRegion
 }
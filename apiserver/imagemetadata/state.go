@@ -18,9 +18,11 @@ type metadataAcess interface {
 	Model() (Model, error)
 	ModelConfig() (*config.Config, error)
 	ControllerTag() names.ControllerTag
+	WatchCloudImageMetadata() state.StringsWatcher
 }
 
 type Model interface {
+	Cloud() string
 	CloudRegion() string
 }
 
@@ -32,8 +34,13 @@ type stateShim struct {
 	*state.State
 }
 
+// FindMetadata is only used to list and explain the stored metadata,
+// which can be large, so the query is served by a mongo secondary when
+// one is available.
 func (s stateShim) FindMetadata(f cloudimagemetadata.MetadataFilter) (map[string][]cloudimagemetadata.Metadata, error) {
-	return s.State.CloudImageMetadataStorage.FindMetadata(f)
+	st, closer := s.State.ReadSecondaryPreferred()
+	defer closer()
+	return st.CloudImageMetadataStorage.FindMetadata(f)
 }
 
 func (s stateShim) SaveMetadata(m []cloudimagemetadata.Metadata) error {
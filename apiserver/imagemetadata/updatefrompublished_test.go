@@ -10,12 +10,15 @@ import (
 	"path/filepath"
 
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
 	imagetesting "github.com/juju/juju/environs/imagemetadata/testing"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	"github.com/juju/juju/juju/keys"
@@ -196,6 +199,20 @@ func (e mockEnviron) Config() *config.Config {
 	return cfg
 }
 
+// InstanceTypes is specified in the InstanceTypesFetcher interface.
+func (e *mockEnviron) InstanceTypes(context.Context, constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+	hvm := "hvm"
+	return instances.InstanceTypesWithCostMetadata{
+		InstanceTypes: []instances.InstanceType{{
+			Name:     "m1",
+			Arches:   []string{"amd64", "i386"},
+			CpuCores: 1,
+			Mem:      2048,
+			VirtType: &hvm,
+		}},
+	}, nil
+}
+
 // Region is specified in the HasRegion interface.
 func (e *mockEnviron) Region() (simplestreams.CloudSpec, error) {
 	return simplestreams.CloudSpec{
@@ -247,6 +264,7 @@ func (s *regionMetadataSuite) setExpectations(c *gc.C) {
 				VirtType:        "pv",
 				Arch:            "amd64",
 				Series:          "trusty",
+				Cloud:           "dummy",
 				Region:          "dummy_region",
 				Source:          "default cloud images",
 				Stream:          "released"},
@@ -259,6 +277,7 @@ func (s *regionMetadataSuite) setExpectations(c *gc.C) {
 				VirtType:        "pv",
 				Arch:            "amd64",
 				Series:          "precise",
+				Cloud:           "dummy",
 				Region:          "dummy_region",
 				Source:          "default cloud images",
 				Stream:          "released"},
@@ -282,7 +301,7 @@ func (s *regionMetadataSuite) setExpectations(c *gc.C) {
 func (s *regionMetadataSuite) checkStoredPublished(c *gc.C) {
 	err := s.api.UpdateFromPublishedImages()
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertCalls(c, "ControllerTag", "ControllerTag", environConfig, saveMetadata)
+	s.assertCalls(c, "ControllerTag", "ControllerTag", environConfig, "Model", saveMetadata)
 	c.Assert(s.saved, jc.SameContents, s.expected)
 }
 
@@ -398,7 +417,7 @@ func (s *regionMetadataSuite) TestUpdateFromPublishedImagesMultipleDS(c *gc.C) {
 
 	err = s.api.UpdateFromPublishedImages()
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertCalls(c, "ControllerTag", "ControllerTag", environConfig, saveMetadata, "ControllerTag", environConfig, saveMetadata)
+	s.assertCalls(c, "ControllerTag", "ControllerTag", environConfig, "Model", saveMetadata, "ControllerTag", environConfig, "Model", saveMetadata)
 	c.Assert(s.saved, jc.SameContents, s.expected)
 }
 
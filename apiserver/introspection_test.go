@@ -74,3 +74,29 @@ func (s *introspectionSuite) TestAccessDenied(c *gc.C) {
 	defer resp.Body.Close()
 	c.Assert(resp.StatusCode, gc.Equals, http.StatusForbidden)
 }
+
+func (s *introspectionSuite) TestMetrics(c *gc.C) {
+	url := s.baseURL(c)
+	url.Path = "/metrics"
+	resp := s.sendRequest(c, httpRequestParams{
+		method:   "GET",
+		url:      url.String(),
+		tag:      "user-admin",
+		password: "dummy-secret",
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+}
+
+func (s *introspectionSuite) TestMetricsAccessDenied(c *gc.C) {
+	url := s.baseURL(c)
+	url.Path = "/metrics"
+	resp := s.sendRequest(c, httpRequestParams{
+		method:   "GET",
+		url:      url.String(),
+		tag:      "user-bob",
+		password: "hunter2",
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusForbidden)
+}
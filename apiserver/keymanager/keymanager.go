@@ -26,6 +26,9 @@ var logger = loggo.GetLogger("juju.apiserver.keymanager")
 
 func init() {
 	common.RegisterStandardFacade("KeyManager", 1, NewKeyManagerAPI)
+	common.RequireFacadeAccess("KeyManager", "AddKeys", permission.AdminAccess)
+	common.RequireFacadeAccess("KeyManager", "ImportKeys", permission.AdminAccess)
+	common.RequireFacadeAccess("KeyManager", "DeleteKeys", permission.AdminAccess)
 }
 
 // The comment values used by juju internal ssh keys.
@@ -184,7 +187,7 @@ func (api *KeyManagerAPI) writeSSHKeys(sshKeys []string) error {
 	// TODO(waigani) 2014-03-17 bug #1293324
 	// Pass in validation to ensure SSH keys
 	// have not changed underfoot
-	err := api.state.UpdateModelConfig(attrs, nil, nil)
+	err := api.state.UpdateModelConfigAs(api.authorizer.GetAuthTag(), attrs, nil, nil)
 	if err != nil {
 		return fmt.Errorf("writing environ config: %v", err)
 	}
@@ -15,6 +15,7 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Logger", 1, NewLoggerAPI)
+	common.RegisterStandardFacade("Logger", 2, NewLoggerAPI)
 }
 
 // Logger defines the methods on the logger API end point.  Unfortunately, the
@@ -24,6 +25,7 @@ func init() {
 type Logger interface {
 	WatchLoggingConfig(args params.Entities) params.NotifyWatchResults
 	LoggingConfig(args params.Entities) params.StringResults
+	AgentSettings(args params.Entities) params.AgentSettingsResults
 }
 
 // LoggerAPI implements the Logger interface and is the concrete
@@ -94,7 +96,7 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 		err = common.ErrPerm
 		if api.authorizer.AuthOwner(tag) {
 			if configErr == nil {
-				results[i].Result = config.LoggingConfig()
+				results[i].Result = config.AgentLoggingConfig()
 				err = nil
 			} else {
 				err = configErr
@@ -104,3 +106,38 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 	}
 	return params.StringResults{Results: results}
 }
+
+// AgentSettings reports the model-level agent settings, such as the
+// logging configuration and log file size limit, for the agents
+// specified. Changes to these settings are reported by the
+// WatchLoggingConfig watcher.
+func (api *LoggerAPI) AgentSettings(arg params.Entities) params.AgentSettingsResults {
+	results := make([]params.AgentSettingsResult, len(arg.Entities))
+	if len(arg.Entities) == 0 {
+		return params.AgentSettingsResults{Results: results}
+	}
+	config, configErr := api.state.ModelConfig()
+	for i, entity := range arg.Entities {
+		tag, err := names.ParseTag(entity.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = common.ErrPerm
+		if api.authorizer.AuthOwner(tag) {
+			if configErr == nil {
+				settings := &params.AgentSettings{
+					LoggingConfig: config.AgentLoggingConfig(),
+				}
+				settings.DataDir, _ = config.AgentDataDir()
+				settings.MaxLogSize, _ = config.AgentMaxLogSize()
+				results[i].Result = settings
+				err = nil
+			} else {
+				err = configErr
+			}
+		}
+		results[i].Error = common.ServerError(err)
+	}
+	return params.AgentSettingsResults{Results: results}
+}
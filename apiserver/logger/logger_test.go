@@ -144,3 +144,47 @@ func (s *loggerSuite) TestLoggingConfigForAgent(c *gc.C) {
 	c.Assert(result.Error, gc.IsNil)
 	c.Assert(result.Result, gc.Equals, newLoggingConfig)
 }
+
+func (s *loggerSuite) TestLoggingConfigPrefersAgentLoggingConfig(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"logging-config":       "<root>=WARN;unit=INFO",
+		"agent-logging-config": "<root>=DEBUG;unit=INFO",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results := s.logger.LoggingConfig(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result, gc.Equals, "<root>=DEBUG;unit=INFO")
+}
+
+func (s *loggerSuite) TestAgentSettings(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"agent-logging-config": "<root>=DEBUG;unit=INFO",
+		"agent-datadir":        "/srv/juju",
+		"agent-max-log-size":   50,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.rawMachine.Tag().String()},
+			{Tag: "machine-12354"},
+		},
+	}
+	results := s.logger.AgentSettings(args)
+	c.Assert(results, jc.DeepEquals, params.AgentSettingsResults{
+		Results: []params.AgentSettingsResult{{
+			Result: &params.AgentSettings{
+				LoggingConfig: "<root>=DEBUG;unit=INFO",
+				DataDir:       "/srv/juju",
+				MaxLogSize:    50,
+			},
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}},
+	})
+}
@@ -27,4 +27,7 @@ func NewMachineManagerTestingAPI(st stateInterface, authorizer facade.Authorizer
 	}
 }
 
-var InstanceTypes = instanceTypes
+var (
+	InstanceTypes = instanceTypes
+	EstimateCosts = estimateCosts
+)
@@ -5,6 +5,7 @@ package machinemanager
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -16,19 +17,17 @@ import (
 
 // InstanceTypes returns instance type information for the cloud and region
 // in which the current model is deployed.
-func (mm *MachineManagerAPI) InstanceTypes(cons params.ModelInstanceTypesConstraints) (params.InstanceTypesResults, error) {
-	return instanceTypes(mm, environs.GetEnviron, cons)
+func (mm *MachineManagerAPI) InstanceTypes(ctx context.Context, cons params.ModelInstanceTypesConstraints) (params.InstanceTypesResults, error) {
+	return instanceTypes(ctx, mm, environs.GetEnviron, cons)
 }
 
 type environGetFunc func(st environs.EnvironConfigGetter, newEnviron environs.NewEnvironFunc) (environs.Environ, error)
 
-func instanceTypes(mm *MachineManagerAPI,
-	getEnviron environGetFunc,
-	cons params.ModelInstanceTypesConstraints,
-) (params.InstanceTypesResults, error) {
+// modelEnviron returns the Environ of the current model.
+func modelEnviron(mm *MachineManagerAPI, getEnviron environGetFunc) (environs.Environ, error) {
 	model, err := mm.st.GetModel(mm.st.ModelTag())
 	if err != nil {
-		return params.InstanceTypesResults{}, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	cloudSpec := func(tag names.ModelTag) (environs.CloudSpec, error) {
@@ -41,8 +40,18 @@ func instanceTypes(mm *MachineManagerAPI,
 		CloudSpecFunc:   cloudSpec,
 		ModelConfigFunc: model.Config,
 	}
+	return getEnviron(backend, environs.New)
+}
 
-	env, err := getEnviron(backend, environs.New)
+func instanceTypes(ctx context.Context,
+	mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	cons params.ModelInstanceTypesConstraints,
+) (params.InstanceTypesResults, error) {
+	env, err := modelEnviron(mm, getEnviron)
+	if err != nil {
+		return params.InstanceTypesResults{}, errors.Trace(err)
+	}
 	result := make([]params.InstanceTypesResult, len(cons.Constraints))
 	// TODO(perrito666) Cache the results to avoid excessive querying of the cloud.
 	for i, c := range cons.Constraints {
@@ -51,7 +60,7 @@ func instanceTypes(mm *MachineManagerAPI,
 			value = *c.Value
 		}
 		itCons := common.NewInstanceTypeConstraints(env, value)
-		it, err := common.InstanceTypes(itCons)
+		it, err := common.InstanceTypes(ctx, itCons)
 		if err != nil {
 			it = params.InstanceTypesResult{Error: common.ServerError(err)}
 		}
@@ -60,3 +69,40 @@ func instanceTypes(mm *MachineManagerAPI,
 
 	return params.InstanceTypesResults{Results: result}, nil
 }
+
+// EstimateCosts returns, for each of the given constraints, the
+// estimated cost of an instance satisfying them in the cloud and region
+// in which the current model is deployed. Costs can only be estimated
+// for providers that implement environs.CostEstimator.
+func (mm *MachineManagerAPI) EstimateCosts(ctx context.Context, args params.CostEstimateParams) (params.CostEstimateResults, error) {
+	return estimateCosts(ctx, mm, environs.GetEnviron, args)
+}
+
+func estimateCosts(ctx context.Context,
+	mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	args params.CostEstimateParams,
+) (params.CostEstimateResults, error) {
+	env, err := modelEnviron(mm, getEnviron)
+	if err != nil {
+		return params.CostEstimateResults{}, errors.Trace(err)
+	}
+	estimator, ok := env.(environs.CostEstimator)
+	if !ok {
+		return params.CostEstimateResults{}, errors.NotSupportedf("estimating costs")
+	}
+	result := make([]params.CostEstimateResult, len(args.Constraints))
+	for i, cons := range args.Constraints {
+		estimate, err := estimator.EstimateCost(ctx, cons)
+		if err != nil {
+			result[i].Error = common.ServerError(err)
+			continue
+		}
+		result[i] = params.CostEstimateResult{
+			InstanceType: estimate.InstanceType,
+			HourlyCost:   estimate.HourlyCost,
+			Currency:     estimate.Currency,
+		}
+	}
+	return params.CostEstimateResults{Results: result}, nil
+}
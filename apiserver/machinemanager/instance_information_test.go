@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/rpc"
 )
 
 type instanceTypesSuite struct{}
@@ -56,9 +58,13 @@ func (p *instanceTypesSuite) TestInstanceTypes(c *gc.C) {
 	) (environs.Environ, error) {
 		return &env, nil
 	}
-	r, err := machinemanager.InstanceTypes(&api, fakeEnvironGet, cons)
+	ctx := rpc.WithCorrelationId(context.Background(), "0123456789abcdef")
+	r, err := machinemanager.InstanceTypes(ctx, &api, fakeEnvironGet, cons)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(r.Results, gc.HasLen, 3)
+	c.Assert(env.correlationIds, jc.DeepEquals, []string{
+		"0123456789abcdef", "0123456789abcdef", "0123456789abcdef",
+	})
 	expected := []params.InstanceTypesResult{
 		params.InstanceTypesResult{
 			InstanceTypes: []params.InstanceType{
@@ -78,6 +84,51 @@ func (p *instanceTypesSuite) TestInstanceTypes(c *gc.C) {
 	c.Assert(r.Results, gc.DeepEquals, expected)
 }
 
+func (p *instanceTypesSuite) TestEstimateCosts(c *gc.C) {
+	backend := mockBackend{}
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUserTag("admin"),
+		Controller: true}
+	itCons := constraints.Value{CpuCores: &over9kCPUCores}
+	env := mockCostEstimator{
+		results: map[constraints.Value]environs.CostEstimate{
+			itCons: {InstanceType: "instancetype-1", HourlyCost: 0.5, Currency: "USD"},
+		},
+	}
+	api := machinemanager.NewMachineManagerTestingAPI(&backend, authorizer)
+	fakeEnvironGet := func(st environs.EnvironConfigGetter,
+		newEnviron environs.NewEnvironFunc,
+	) (environs.Environ, error) {
+		return &env, nil
+	}
+	r, err := machinemanager.EstimateCosts(context.Background(), &api, fakeEnvironGet, params.CostEstimateParams{
+		Constraints: []constraints.Value{itCons, {}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, gc.DeepEquals, []params.CostEstimateResult{{
+		InstanceType: "instancetype-1",
+		HourlyCost:   0.5,
+		Currency:     "USD",
+	}, {
+		Error: &params.Error{Message: "cost of  not found", Code: "not found"},
+	}})
+}
+
+func (p *instanceTypesSuite) TestEstimateCostsNotSupported(c *gc.C) {
+	backend := mockBackend{}
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUserTag("admin"),
+		Controller: true}
+	api := machinemanager.NewMachineManagerTestingAPI(&backend, authorizer)
+	fakeEnvironGet := func(st environs.EnvironConfigGetter,
+		newEnviron environs.NewEnvironFunc,
+	) (environs.Environ, error) {
+		return &mockEnviron{}, nil
+	}
+	_, err := machinemanager.EstimateCosts(context.Background(), &api, fakeEnvironGet, params.CostEstimateParams{
+		Constraints: []constraints.Value{{}},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 type mockBackend struct {
 	jujutesting.Stub
 	machinemanager.StateInterface
@@ -122,10 +173,12 @@ type mockEnviron struct {
 	machinemanager.StateInterface
 	jujutesting.Stub
 
-	results map[constraints.Value]instances.InstanceTypesWithCostMetadata
+	results        map[constraints.Value]instances.InstanceTypesWithCostMetadata
+	correlationIds []string
 }
 
-func (m *mockEnviron) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+func (m *mockEnviron) InstanceTypes(ctx context.Context, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+	m.correlationIds = append(m.correlationIds, rpc.CorrelationId(ctx))
 	it, ok := m.results[c]
 	if !ok {
 		return instances.InstanceTypesWithCostMetadata{}, errors.NotFoundf("Instances matching constraint %v", c)
@@ -133,6 +186,20 @@ func (m *mockEnviron) InstanceTypes(c constraints.Value) (instances.InstanceType
 	return it, nil
 }
 
+type mockCostEstimator struct {
+	mockEnviron
+
+	results map[constraints.Value]environs.CostEstimate
+}
+
+func (m *mockCostEstimator) EstimateCost(ctx context.Context, c constraints.Value) (environs.CostEstimate, error) {
+	estimate, ok := m.results[c]
+	if !ok {
+		return environs.CostEstimate{}, errors.NotFoundf("cost of %v", c)
+	}
+	return estimate, nil
+}
+
 type mockModel struct {
 	machinemanager.Model
 }
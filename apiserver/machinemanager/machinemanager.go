@@ -23,6 +23,14 @@ func init() {
 	common.RegisterStandardFacade("MachineManager", 2, NewMachineManagerAPI)
 	// Version 3 adds DestroyMachine and ForceDestroyMachine.
 	common.RegisterStandardFacade("MachineManager", 3, NewMachineManagerAPI)
+	// Version 4 adds EstimateCosts.
+	common.RegisterStandardFacade("MachineManager", 4, NewMachineManagerAPI)
+	// Version 5 adds SetTags.
+	common.RegisterStandardFacade("MachineManager", 5, NewMachineManagerAPI)
+	common.RequireFacadeAccess("MachineManager", "AddMachines", permission.WriteAccess)
+	common.RequireFacadeAccess("MachineManager", "DestroyMachine", permission.WriteAccess)
+	common.RequireFacadeAccess("MachineManager", "ForceDestroyMachine", permission.WriteAccess)
+	common.RequireFacadeAccess("MachineManager", "SetTags", permission.WriteAccess)
 }
 
 // MachineManagerAPI provides access to the MachineManager API facade.
@@ -136,22 +144,13 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		placementDirective = p.Placement.Directive
 	}
 
-	volumes := make([]state.MachineVolumeParams, 0, len(p.Disks))
-	for _, cons := range p.Disks {
-		if cons.Count == 0 {
-			return nil, errors.Errorf("invalid volume params: count not specified")
-		}
-		// Pool and Size are validated by AddMachineX.
-		volumeParams := state.VolumeParams{
-			Pool: cons.Pool,
-			Size: cons.Size,
-		}
-		volumeAttachmentParams := state.VolumeAttachmentParams{}
-		for i := uint64(0); i < cons.Count; i++ {
-			volumes = append(volumes, state.MachineVolumeParams{
-				volumeParams, volumeAttachmentParams,
-			})
-		}
+	volumes, err := common.MachineVolumeParams(p.Disks)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cons, err := common.MachineSpacesConstraints(p.Constraints, p.Spaces)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
 
 	jobs, err := common.StateJobs(p.Jobs)
@@ -160,7 +159,7 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	}
 	template := state.MachineTemplate{
 		Series:      p.Series,
-		Constraints: p.Constraints,
+		Constraints: cons,
 		Volumes:     volumes,
 		InstanceId:  p.InstanceId,
 		Jobs:        jobs,
@@ -183,6 +182,34 @@ func (mm *MachineManagerAPI) DestroyMachine(args params.Entities) (params.Destro
 	return mm.destroyMachine(args, false)
 }
 
+// SetTags replaces the user-defined tags on each of the given machines.
+func (mm *MachineManagerAPI) SetTags(args params.SetResourceTags) (params.ErrorResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		machineTag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := mm.st.Machine(machineTag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = machine.SetTags(arg.Tags)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
 // ForceDestroyMachine forcibly removes a set of machines from the model.
 func (mm *MachineManagerAPI) ForceDestroyMachine(args params.Entities) (params.DestroyMachineResults, error) {
 	return mm.destroyMachine(args, true)
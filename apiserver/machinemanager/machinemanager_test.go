@@ -141,10 +141,32 @@ func (s *MachineManagerSuite) TestDestroyMachine(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestSetTags(c *gc.C) {
+	results, err := s.api.SetTags(params.SetResourceTags{
+		Entities: []params.EntityResourceTags{
+			{Tag: "machine-0", Tags: map[string]string{"team": "ops"}},
+			{Tag: "machine-1"},
+			{Tag: "unit-foo-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "machine 1 not found"}},
+			{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+		},
+	})
+	c.Assert(s.st.machineTags, jc.DeepEquals, map[string]map[string]string{
+		"0": {"team": "ops"},
+	})
+}
+
 type mockState struct {
-	calls    int
-	machines []state.MachineTemplate
-	err      error
+	calls       int
+	machines    []state.MachineTemplate
+	machineTags map[string]map[string]string
+	err         error
 }
 
 func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machine, error) {
@@ -199,7 +221,10 @@ func (st *mockState) GetModel(t names.ModelTag) (machinemanager.Model, error) {
 }
 
 func (st *mockState) Machine(id string) (machinemanager.Machine, error) {
-	return &mockMachine{}, nil
+	if id == "1" {
+		return nil, errors.New("machine 1 not found")
+	}
+	return &mockMachine{id: id, st: st}, nil
 }
 
 func (st *mockState) StorageInstance(tag names.StorageTag) (state.StorageInstance, error) {
@@ -240,7 +265,10 @@ func (st *mockBlock) ModelUUID() string {
 	return "uuid"
 }
 
-type mockMachine struct{}
+type mockMachine struct {
+	id string
+	st *mockState
+}
 
 func (m *mockMachine) Destroy() error {
 	return nil
@@ -250,6 +278,14 @@ func (m *mockMachine) ForceDestroy() error {
 	return nil
 }
 
+func (m *mockMachine) SetTags(tags map[string]string) error {
+	if m.st.machineTags == nil {
+		m.st.machineTags = make(map[string]map[string]string)
+	}
+	m.st.machineTags[m.id] = tags
+	return nil
+}
+
 func (m *mockMachine) Units() ([]machinemanager.Unit, error) {
 	return []machinemanager.Unit{
 		&mockUnit{names.NewUnitTag("foo/0")},
@@ -90,6 +90,7 @@ type Model interface {
 type Machine interface {
 	Destroy() error
 	ForceDestroy() error
+	SetTags(map[string]string) error
 	Units() ([]Unit, error)
 }
 
@@ -15,11 +15,13 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
 func init() {
 	common.RegisterStandardFacade("MetricsDebug", 2, NewMetricsDebugAPI)
+	common.RequireFacadeAccess("MetricsDebug", "SetMeterStatus", permission.WriteAccess)
 }
 
 type metricsDebug interface {
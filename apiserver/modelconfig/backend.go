@@ -18,7 +18,9 @@ type Backend interface {
 	ControllerTag() names.ControllerTag
 	ModelTag() names.ModelTag
 	ModelConfigValues() (config.ConfigValues, error)
-	UpdateModelConfig(map[string]interface{}, []string, state.ValidateConfigFunc) error
+	UpdateModelConfigAs(names.Tag, map[string]interface{}, []string, state.ValidateConfigFunc) error
+	ModelConfigHistory(limit int) ([]state.ModelConfigHistoryEntry, error)
+	ValidateModelConfigUpdate(map[string]interface{}, []string, state.ValidateConfigFunc) (*config.Config, *config.Config, error)
 }
 
 type stateShim struct {
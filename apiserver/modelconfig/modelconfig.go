@@ -15,7 +15,17 @@ import (
 )
 
 func init() {
-	common.RegisterStandardFacade("ModelConfig", 1, newFacade)
+	common.RegisterStandardFacade("ModelConfig", 3, newFacade)
+	// Version 2 is served by version 3, without ModelConfigHistory.
+	common.RegisterFacadeTranslation("ModelConfig", 2, facade.Translation{
+		Omit: []string{"ModelConfigHistory"},
+	})
+	// Version 1 is served by version 2, without ModelConfigDiff.
+	common.RegisterFacadeTranslation("ModelConfig", 1, facade.Translation{
+		Omit: []string{"ModelConfigDiff"},
+	})
+	common.RequireFacadeAccess("ModelConfig", "ModelSet", permission.WriteAccess)
+	common.RequireFacadeAccess("ModelConfig", "ModelUnset", permission.WriteAccess)
 }
 
 func newFacade(st *state.State, _ facade.Resources, auth facade.Authorizer) (*ModelConfigAPI, error) {
@@ -85,6 +95,11 @@ func (c *ModelConfigAPI) ModelGet() (params.ModelConfigResults, error) {
 		if attr == config.AuthorizedKeysKey {
 			continue
 		}
+		// Secrets, such as object storage credentials, are only
+		// for the controller's use.
+		if config.IsSecretAttribute(attr) {
+			continue
+		}
 		result.Config[attr] = params.ConfigValue{
 			Value:  val.Value,
 			Source: val.Source,
@@ -103,19 +118,20 @@ func (c *ModelConfigAPI) ModelSet(args params.ModelSet) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
-	// Make sure we don't allow changing agent-version.
-	checkAgentVersion := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
-		if v, found := updateAttrs["agent-version"]; found {
-			oldVersion, _ := oldConfig.AgentVersion()
-			if v != oldVersion.String() {
-				return errors.New("agent-version cannot be changed")
-			}
-		}
-		return nil
-	}
 	// Replace any deprecated attributes with their new values.
 	attrs := config.ProcessDeprecatedAttributes(args.Config)
-	return c.backend.UpdateModelConfig(attrs, nil, checkAgentVersion)
+	return c.backend.UpdateModelConfigAs(c.auth.GetAuthTag(), attrs, nil, checkAgentVersion)
+}
+
+// checkAgentVersion makes sure we don't allow changing agent-version.
+func checkAgentVersion(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
+	if v, found := updateAttrs["agent-version"]; found {
+		oldVersion, _ := oldConfig.AgentVersion()
+		if v != oldVersion.String() {
+			return errors.New("agent-version cannot be changed")
+		}
+	}
+	return nil
 }
 
 // ModelUnset implements the server-side part of the
@@ -127,5 +143,82 @@ func (c *ModelConfigAPI) ModelUnset(args params.ModelUnset) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
-	return c.backend.UpdateModelConfig(nil, args.Keys, nil)
+	return c.backend.UpdateModelConfigAs(c.auth.GetAuthTag(), nil, args.Keys, nil)
+}
+
+// ModelConfigDiff validates a proposed change to the model config and
+// reports its effect, without applying it.
+func (c *ModelConfigAPI) ModelConfigDiff(args params.ModelConfigDiffArgs) (params.ModelConfigDiffResult, error) {
+	var result params.ModelConfigDiffResult
+	if err := c.checkCanWrite(); err != nil {
+		return result, err
+	}
+	attrs := config.ProcessDeprecatedAttributes(args.Config)
+	oldConfig, newConfig, err := c.backend.ValidateModelConfigUpdate(attrs, args.Unset, checkAgentVersion)
+	if err != nil {
+		if oldConfig == nil {
+			// The model's current config could not be read.
+			return result, errors.Trace(err)
+		}
+		result.Error = common.ServerError(err)
+		if immutableErr, ok := errors.Cause(err).(*config.ImmutableChangeError); ok {
+			for _, change := range immutableErr.Changes {
+				result.ImmutableViolations = append(result.ImmutableViolations, configChange(change.Attr, change.Old, change.New))
+			}
+		}
+	}
+	if newConfig != nil {
+		for _, change := range config.Diff(oldConfig, newConfig) {
+			result.Changes = append(result.Changes, configChange(change.Attr, change.Old, change.New))
+		}
+	}
+	return result, nil
+}
+
+// redactedValue replaces the values of secret attributes in reported
+// config changes.
+const redactedValue = "<redacted>"
+
+// configChange returns the reported change to the given attribute,
+// with the values of secret attributes redacted.
+func configChange(attr string, old, new interface{}) params.ConfigChange {
+	if config.IsSecretAttribute(attr) {
+		if old != nil {
+			old = redactedValue
+		}
+		if new != nil {
+			new = redactedValue
+		}
+	}
+	return params.ConfigChange{
+		Attr: attr,
+		Old:  old,
+		New:  new,
+	}
+}
+
+// ModelConfigHistory returns the changes that have been made to the
+// model config, most recent first.
+func (c *ModelConfigAPI) ModelConfigHistory(args params.ModelConfigHistoryArgs) (params.ModelConfigHistoryResult, error) {
+	var result params.ModelConfigHistoryResult
+	if err := c.checkCanWrite(); err != nil {
+		return result, err
+	}
+	history, err := c.backend.ModelConfigHistory(args.Limit)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Entries = make([]params.ModelConfigHistoryEntry, len(history))
+	for i, entry := range history {
+		changes := make([]params.ConfigChange, len(entry.Changes))
+		for j, change := range entry.Changes {
+			changes[j] = configChange(change.Key, change.OldValue, change.NewValue)
+		}
+		result.Entries[i] = params.ModelConfigHistoryEntry{
+			Time:    entry.Time,
+			Author:  entry.Author,
+			Changes: changes,
+		}
+	}
+	return result, nil
 }
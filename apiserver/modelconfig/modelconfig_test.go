@@ -4,18 +4,23 @@
 package modelconfig_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/modelconfig"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/dummy"
 	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
@@ -58,6 +63,22 @@ func (s *modelconfigSuite) TestModelGet(c *gc.C) {
 	})
 }
 
+func (s *modelconfigSuite) TestModelGetOmitsSecrets(c *gc.C) {
+	s.backend.cfg["s3-storage-access-key"] = config.ConfigValue{"access", "model"}
+	s.backend.cfg["s3-storage-secret-key"] = config.ConfigValue{"secret", "model"}
+	s.backend.cfg["swift-storage-password"] = config.ConfigValue{"password", "model"}
+	s.backend.cfg["azure-storage-account-key"] = config.ConfigValue{"key", "model"}
+	s.backend.cfg["gcs-storage-credentials"] = config.ConfigValue{"{}", "model"}
+	result, err := s.api.ModelGet()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config, jc.DeepEquals, map[string]params.ConfigValue{
+		"type":                  {"dummy", "model"},
+		"ftp-proxy":             {"http://proxy", "model"},
+		"agent-version":         {Value: "1.2.3.4", Source: "model"},
+		"s3-storage-access-key": {"access", "model"},
+	})
+}
+
 func (s *modelconfigSuite) assertConfigValue(c *gc.C, key string, expected interface{}) {
 	value, found := s.backend.cfg[key]
 	c.Assert(found, jc.IsTrue)
@@ -153,18 +174,167 @@ func (s *modelconfigSuite) TestModelUnsetMissing(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *modelconfigSuite) setOldConfig(c *gc.C) {
+	old, err := config.New(config.UseDefaults, dummy.SampleConfig().Merge(testing.Attrs{
+		"agent-version": "1.2.3.4",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.old = old
+}
+
+func (s *modelconfigSuite) TestModelConfigDiff(c *gc.C) {
+	s.setOldConfig(c)
+	result, err := s.api.ModelConfigDiff(params.ModelConfigDiffArgs{
+		Config: map[string]interface{}{"apt-mirror": "http://mirror.invalid"},
+		Unset:  []string{"secret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.ImmutableViolations, gc.HasLen, 0)
+	c.Assert(result.Changes, jc.DeepEquals, []params.ConfigChange{{
+		Attr: "apt-mirror",
+		Old:  "",
+		New:  "http://mirror.invalid",
+	}, {
+		Attr: "secret",
+		Old:  "pork",
+	}})
+
+	// Nothing was changed.
+	s.assertConfigValueMissing(c, "apt-mirror")
+}
+
+func (s *modelconfigSuite) TestModelConfigDiffRedactsSecrets(c *gc.C) {
+	s.setOldConfig(c)
+	result, err := s.api.ModelConfigDiff(params.ModelConfigDiffArgs{
+		Config: map[string]interface{}{
+			"s3-storage-bucket":     "juju",
+			"s3-storage-region":     "us-east-1",
+			"s3-storage-access-key": "access",
+			"s3-storage-secret-key": "secret",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	changes := make(map[string]params.ConfigChange)
+	for _, change := range result.Changes {
+		changes[change.Attr] = change
+	}
+	c.Assert(changes["s3-storage-access-key"].New, gc.Equals, "access")
+	c.Assert(changes["s3-storage-secret-key"].New, gc.Equals, "<redacted>")
+}
+
+func (s *modelconfigSuite) TestModelConfigDiffImmutable(c *gc.C) {
+	s.setOldConfig(c)
+	result, err := s.api.ModelConfigDiff(params.ModelConfigDiffArgs{
+		Config: map[string]interface{}{"name": "renamed"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, `cannot change name from "only" to "renamed"`)
+	c.Assert(result.ImmutableViolations, jc.DeepEquals, []params.ConfigChange{{
+		Attr: "name",
+		Old:  "only",
+		New:  "renamed",
+	}})
+	c.Assert(result.Changes, jc.DeepEquals, result.ImmutableViolations)
+}
+
+func (s *modelconfigSuite) TestModelConfigDiffCannotChangeAgentVersion(c *gc.C) {
+	s.setOldConfig(c)
+	result, err := s.api.ModelConfigDiff(params.ModelConfigDiffArgs{
+		Config: map[string]interface{}{"agent-version": "9.9.9"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "agent-version cannot be changed")
+	c.Assert(result.Changes, gc.HasLen, 0)
+}
+
+func (s *modelconfigSuite) TestModelSetRecordsAuthor(c *gc.C) {
+	err := s.api.ModelSet(params.ModelSet{Config: map[string]interface{}{"some-key": "value"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.backend.author, gc.Equals, names.NewUserTag("bruce@local"))
+}
+
+func (s *modelconfigSuite) TestModelConfigHistory(c *gc.C) {
+	changed := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.backend.history = []state.ModelConfigHistoryEntry{{
+		Time:   changed.Add(time.Hour),
+		Author: "user-bruce",
+		Changes: []state.ItemChange{
+			{Type: state.ItemModified, Key: "ftp-proxy", OldValue: "http://old", NewValue: "http://proxy"},
+		},
+	}, {
+		Time: changed,
+		Changes: []state.ItemChange{
+			{Type: state.ItemDeleted, Key: "apt-mirror", OldValue: "http://mirror"},
+		},
+	}}
+	result, err := s.api.ModelConfigHistory(params.ModelConfigHistoryArgs{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, jc.DeepEquals, []params.ModelConfigHistoryEntry{{
+		Time:   changed.Add(time.Hour),
+		Author: "user-bruce",
+		Changes: []params.ConfigChange{
+			{Attr: "ftp-proxy", Old: "http://old", New: "http://proxy"},
+		},
+	}})
+}
+
+func (s *modelconfigSuite) TestModelConfigHistoryRedactsSecrets(c *gc.C) {
+	s.backend.history = []state.ModelConfigHistoryEntry{{
+		Time:   time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC),
+		Author: "user-bruce",
+		Changes: []state.ItemChange{
+			{Type: state.ItemAdded, Key: "s3-storage-access-key", NewValue: "access"},
+			{Type: state.ItemModified, Key: "s3-storage-secret-key", OldValue: "old", NewValue: "secret"},
+		},
+	}}
+	result, err := s.api.ModelConfigHistory(params.ModelConfigHistoryArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	c.Assert(result.Entries[0].Changes, jc.DeepEquals, []params.ConfigChange{
+		{Attr: "s3-storage-access-key", New: "access"},
+		{Attr: "s3-storage-secret-key", Old: "<redacted>", New: "<redacted>"},
+	})
+}
+
+func (s *modelconfigSuite) TestModelConfigHistoryNoAccess(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("charlie@local")
+	_, err := s.api.ModelConfigHistory(params.ModelConfigHistoryArgs{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *modelconfigSuite) TestOlderVersionsOmitNewerMethods(c *gc.C) {
+	for _, t := range []struct {
+		version int
+		method  string
+	}{
+		{1, "ModelConfigDiff"},
+		{1, "ModelConfigHistory"},
+		{2, "ModelConfigHistory"},
+	} {
+		translations, err := common.Facades.GetTranslations("ModelConfig", t.version)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = facade.TranslateMethod(translations, t.method, rpcreflect.ObjMethod{})
+		c.Check(err, gc.Equals, rpcreflect.ErrMethodNotFound, gc.Commentf("v%d %s", t.version, t.method))
+	}
+}
+
 type mockBackend struct {
-	cfg config.ConfigValues
-	old *config.Config
-	b   state.BlockType
-	msg string
+	cfg     config.ConfigValues
+	old     *config.Config
+	b       state.BlockType
+	msg     string
+	author  names.Tag
+	history []state.ModelConfigHistoryEntry
 }
 
 func (m *mockBackend) ModelConfigValues() (config.ConfigValues, error) {
 	return m.cfg, nil
 }
 
-func (m *mockBackend) UpdateModelConfig(update map[string]interface{}, remove []string, validate state.ValidateConfigFunc) error {
+func (m *mockBackend) UpdateModelConfigAs(author names.Tag, update map[string]interface{}, remove []string, validate state.ValidateConfigFunc) error {
+	m.author = author
 	if validate != nil {
 		err := validate(update, remove, m.old)
 		if err != nil {
@@ -180,6 +350,30 @@ func (m *mockBackend) UpdateModelConfig(update map[string]interface{}, remove []
 	return nil
 }
 
+func (m *mockBackend) ModelConfigHistory(limit int) ([]state.ModelConfigHistoryEntry, error) {
+	if limit > 0 && limit < len(m.history) {
+		return m.history[:limit], nil
+	}
+	return m.history, nil
+}
+
+func (m *mockBackend) ValidateModelConfigUpdate(update map[string]interface{}, remove []string, validate state.ValidateConfigFunc) (*config.Config, *config.Config, error) {
+	if validate != nil {
+		if err := validate(update, remove, m.old); err != nil {
+			return m.old, nil, err
+		}
+	}
+	cfg, err := m.old.Apply(update)
+	if err != nil {
+		return m.old, nil, err
+	}
+	cfg, err = cfg.Remove(remove)
+	if err != nil {
+		return m.old, nil, err
+	}
+	return m.old, cfg, config.Validate(cfg, m.old)
+}
+
 func (m *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
 	if m.b == t {
 		return &mockBlock{t: t, m: m.msg}, true, nil
@@ -368,6 +368,8 @@ type mockState struct {
 	blockMsg        string
 	block           state.BlockType
 	migration       *mockMigration
+	quotas          state.ModelQuotas
+	quotaUsage      state.ModelQuotaUsage
 }
 
 type fakeModelDescription struct {
@@ -605,6 +607,25 @@ func (st *mockState) DumpAll() (map[string]interface{}, error) {
 	}, st.NextErr()
 }
 
+func (st *mockState) ModelQuotas() (state.ModelQuotas, error) {
+	st.MethodCall(st, "ModelQuotas")
+	return st.quotas, st.NextErr()
+}
+
+func (st *mockState) SetModelQuotas(quotas state.ModelQuotas) error {
+	st.MethodCall(st, "SetModelQuotas", quotas)
+	if err := st.NextErr(); err != nil {
+		return err
+	}
+	st.quotas = quotas
+	return nil
+}
+
+func (st *mockState) ModelQuotaUsage() (state.ModelQuotaUsage, error) {
+	st.MethodCall(st, "ModelQuotaUsage")
+	return st.quotaUsage, st.NextErr()
+}
+
 func (st *mockState) LatestMigration() (state.ModelMigration, error) {
 	st.MethodCall(st, "LatestMigration")
 	if st.migration == nil {
@@ -37,7 +37,12 @@ import (
 var logger = loggo.GetLogger("juju.apiserver.modelmanager")
 
 func init() {
-	common.RegisterStandardFacade("ModelManager", 2, newFacade)
+	common.RegisterStandardFacade("ModelManager", 3, newFacade)
+	// Version 2 is served by version 3, without ModelQuotas and
+	// SetModelQuotas.
+	common.RegisterFacadeTranslation("ModelManager", 2, facade.Translation{
+		Omit: []string{"ModelQuotas", "SetModelQuotas"},
+	})
 }
 
 // ModelManager defines the methods on the modelmanager API endpoint.
@@ -47,6 +52,8 @@ type ModelManager interface {
 	DumpModelsDB(args params.Entities) params.MapResults
 	ListModels(user params.Entity) (params.UserModelList, error)
 	DestroyModels(args params.Entities) (params.ErrorResults, error)
+	ModelQuotas(args params.Entities) (params.ModelQuotasResults, error)
+	SetModelQuotas(args params.SetModelQuotas) (params.ErrorResults, error)
 }
 
 // ModelManagerAPI implements the model manager interface and is
@@ -891,6 +898,112 @@ func (m *ModelManagerAPI) UnsetModelDefaults(args params.UnsetModelDefaults) (pa
 	return results, nil
 }
 
+// ModelQuotas returns the quotas of the specified models, and their
+// use of the resources the quotas limit. The user needs to either be a
+// controller admin, or have admin privileges on the model itself.
+func (m *ModelManagerAPI) ModelQuotas(args params.Entities) (params.ModelQuotasResults, error) {
+	results := params.ModelQuotasResults{
+		Results: make([]params.ModelQuotasResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		quotas, usage, err := m.modelQuotas(entity)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Quotas = &params.ModelQuotas{
+			MaxMachines:  quotas.MaxMachines,
+			MaxUnits:     quotas.MaxUnits,
+			MaxStorageGB: quotas.MaxStorageGB,
+		}
+		results.Results[i].Usage = &params.ModelQuotaUsage{
+			Machines:   usage.Machines,
+			Units:      usage.Units,
+			StorageMiB: usage.StorageMiB,
+		}
+	}
+	return results, nil
+}
+
+func (m *ModelManagerAPI) modelQuotas(args params.Entity) (state.ModelQuotas, state.ModelQuotaUsage, error) {
+	modelTag, err := names.ParseModelTag(args.Tag)
+	if err != nil {
+		return state.ModelQuotas{}, state.ModelQuotaUsage{}, errors.Trace(err)
+	}
+	isModelAdmin, err := m.authorizer.HasPermission(permission.AdminAccess, modelTag)
+	if err != nil {
+		return state.ModelQuotas{}, state.ModelQuotaUsage{}, errors.Trace(err)
+	}
+	if !isModelAdmin && !m.isAdmin {
+		return state.ModelQuotas{}, state.ModelQuotaUsage{}, common.ErrPerm
+	}
+	st, release, err := m.modelState(modelTag)
+	if err != nil {
+		return state.ModelQuotas{}, state.ModelQuotaUsage{}, errors.Trace(err)
+	}
+	defer release()
+
+	quotas, err := st.ModelQuotas()
+	if err != nil {
+		return state.ModelQuotas{}, state.ModelQuotaUsage{}, errors.Trace(err)
+	}
+	usage, err := st.ModelQuotaUsage()
+	if err != nil {
+		return state.ModelQuotas{}, state.ModelQuotaUsage{}, errors.Trace(err)
+	}
+	return quotas, usage, nil
+}
+
+// SetModelQuotas sets the quotas of the specified models. Only
+// controller admins may set quotas.
+func (m *ModelManagerAPI) SetModelQuotas(args params.SetModelQuotas) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Models)),
+	}
+	if !m.isAdmin {
+		return results, common.ErrPerm
+	}
+	if err := m.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Models {
+		results.Results[i].Error = common.ServerError(m.setModelQuotas(arg))
+	}
+	return results, nil
+}
+
+func (m *ModelManagerAPI) setModelQuotas(arg params.ModelQuotasParams) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	st, release, err := m.modelState(modelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+	return st.SetModelQuotas(state.ModelQuotas{
+		MaxMachines:  arg.Quotas.MaxMachines,
+		MaxUnits:     arg.Quotas.MaxUnits,
+		MaxStorageGB: arg.Quotas.MaxStorageGB,
+	})
+}
+
+// modelState returns the backend for the specified model, and a
+// function that releases it.
+func (m *ModelManagerAPI) modelState(modelTag names.ModelTag) (common.ModelManagerBackend, func(), error) {
+	if m.state.ModelTag() == modelTag {
+		return m.state, func() {}, nil
+	}
+	st, err := m.state.ForModel(modelTag)
+	if errors.IsNotFound(err) {
+		return nil, nil, errors.Trace(common.ErrBadId)
+	} else if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return st, func() { st.Close() }, nil
+}
+
 // makeRegionSpec is a helper method for methods that call
 // state.UpdateModelConfigDefaultValues.
 func (m *ModelManagerAPI) makeRegionSpec(cloudTag, r string) (*environs.RegionSpec, error) {
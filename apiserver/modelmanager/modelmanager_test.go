@@ -17,6 +17,7 @@ import (
 
 	// Register the providers for the field check test
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/modelmanager"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
@@ -31,6 +32,7 @@ import (
 	_ "github.com/juju/juju/provider/joyent"
 	_ "github.com/juju/juju/provider/maas"
 	_ "github.com/juju/juju/provider/openstack"
+	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/status"
@@ -609,6 +611,70 @@ func (s *modelManagerSuite) TestDumpModelsDBUsers(c *gc.C) {
 	}
 }
 
+func (s *modelManagerSuite) TestModelQuotas(c *gc.C) {
+	s.st.quotas = state.ModelQuotas{MaxMachines: 5}
+	s.st.quotaUsage = state.ModelQuotaUsage{Machines: 2, Units: 3, StorageMiB: 1024}
+	results, err := s.api.ModelQuotas(params.Entities{[]params.Entity{{
+		Tag: "application-foo",
+	}, {
+		Tag: s.st.ModelTag().String(),
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error.Message, gc.Equals, `"application-foo" is not a valid model tag`)
+	c.Check(results.Results[1], jc.DeepEquals, params.ModelQuotasResult{
+		Quotas: &params.ModelQuotas{MaxMachines: 5},
+		Usage:  &params.ModelQuotaUsage{Machines: 2, Units: 3, StorageMiB: 1024},
+	})
+}
+
+func (s *modelManagerSuite) TestModelQuotasUsers(c *gc.C) {
+	models := params.Entities{[]params.Entity{{Tag: s.st.ModelTag().String()}}}
+	for _, user := range []names.UserTag{
+		names.NewUserTag("otheruser"),
+		names.NewUserTag("unknown"),
+	} {
+		s.setAPIUser(c, user)
+		results, err := s.api.ModelQuotas(models)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(results.Results, gc.HasLen, 1)
+		c.Check(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+	}
+}
+
+func (s *modelManagerSuite) TestSetModelQuotas(c *gc.C) {
+	results, err := s.api.SetModelQuotas(params.SetModelQuotas{
+		Models: []params.ModelQuotasParams{{
+			ModelTag: s.st.ModelTag().String(),
+			Quotas:   params.ModelQuotas{MaxUnits: 10, MaxStorageGB: 100},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(s.st.quotas, gc.Equals, state.ModelQuotas{MaxUnits: 10, MaxStorageGB: 100})
+}
+
+func (s *modelManagerSuite) TestSetModelQuotasAsNormalUser(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("otheruser"))
+	_, err := s.api.SetModelQuotas(params.SetModelQuotas{
+		Models: []params.ModelQuotasParams{{
+			ModelTag: s.st.ModelTag().String(),
+			Quotas:   params.ModelQuotas{MaxUnits: 10},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.st.quotas, gc.Equals, state.ModelQuotas{})
+}
+
+func (s *modelManagerSuite) TestVersion2OmitsQuotas(c *gc.C) {
+	translations, err := common.Facades.GetTranslations("ModelManager", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, method := range []string{"ModelQuotas", "SetModelQuotas"} {
+		_, err = facade.TranslateMethod(translations, method, rpcreflect.ObjMethod{})
+		c.Check(err, gc.Equals, rpcreflect.ErrMethodNotFound, gc.Commentf(method))
+	}
+}
+
 func (s *modelManagerSuite) TestAddModelCanCreateModel(c *gc.C) {
 	addModelUser := names.NewUserTag("add-model")
 	userAccess := permission.UserAccess{
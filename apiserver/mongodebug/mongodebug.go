@@ -0,0 +1,75 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package mongodebug implements the API facade through which
+// controller administrators inspect the controller's use of mongo.
+package mongodebug
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("MongoDebug", 1, newFacade)
+}
+
+// API implements the MongoDebug facade.
+type API struct {
+	controllerTag names.ControllerTag
+	auth          facade.Authorizer
+	getStats      func() state.MongoSessionStats
+}
+
+func newFacade(st *state.State, _ facade.Resources, auth facade.Authorizer) (*API, error) {
+	return NewAPI(st.ControllerTag(), auth, state.GetMongoSessionStats)
+}
+
+// NewAPI returns a new MongoDebug facade, which reports the session
+// statistics returned by getStats. Only controller superusers may
+// connect to it.
+func NewAPI(
+	controllerTag names.ControllerTag,
+	auth facade.Authorizer,
+	getStats func() state.MongoSessionStats,
+) (*API, error) {
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	isAdmin, err := auth.HasPermission(permission.SuperuserAccess, controllerTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		controllerTag: controllerTag,
+		auth:          auth,
+		getStats:      getStats,
+	}, nil
+}
+
+// SessionStats returns the current statistics about the controller's
+// mongo sessions and sockets. Query latencies are reported through
+// the controller's prometheus metrics.
+func (api *API) SessionStats() (params.MongoSessionStats, error) {
+	stats := api.getStats()
+	return params.MongoSessionStats{
+		Clusters:     stats.Clusters,
+		MasterConns:  stats.MasterConns,
+		SlaveConns:   stats.SlaveConns,
+		SentOps:      stats.SentOps,
+		ReceivedOps:  stats.ReceivedOps,
+		ReceivedDocs: stats.ReceivedDocs,
+		SocketsAlive: stats.SocketsAlive,
+		SocketsInUse: stats.SocketsInUse,
+		SocketRefs:   stats.SocketRefs,
+	}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongodebug_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/mongodebug"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type mongoDebugSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&mongoDebugSuite{})
+
+var controllerTag = names.NewControllerTag("deadbeef-0bad-400d-8000-4b1d0d06f00d")
+
+func getStats() state.MongoSessionStats {
+	return state.MongoSessionStats{
+		Clusters:     1,
+		MasterConns:  2,
+		SlaveConns:   3,
+		SentOps:      4,
+		ReceivedOps:  5,
+		ReceivedDocs: 6,
+		SocketsAlive: 7,
+		SocketsInUse: 8,
+		SocketRefs:   9,
+	}
+}
+
+func (s *mongoDebugSuite) TestSessionStats(c *gc.C) {
+	api, err := mongodebug.NewAPI(controllerTag, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("superuser-bob"),
+	}, getStats)
+	c.Assert(err, jc.ErrorIsNil)
+	stats, err := api.SessionStats()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats, jc.DeepEquals, params.MongoSessionStats{
+		Clusters:     1,
+		MasterConns:  2,
+		SlaveConns:   3,
+		SentOps:      4,
+		ReceivedOps:  5,
+		ReceivedDocs: 6,
+		SocketsAlive: 7,
+		SocketsInUse: 8,
+		SocketRefs:   9,
+	})
+}
+
+func (s *mongoDebugSuite) TestRequiresSuperuser(c *gc.C) {
+	_, err := mongodebug.NewAPI(controllerTag, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin-bob"),
+	}, getStats)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *mongoDebugSuite) TestRefusesAgents(c *gc.C) {
+	_, err := mongodebug.NewAPI(controllerTag, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}, getStats)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -5,6 +5,9 @@ package observer
 import (
 	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -87,7 +90,8 @@ func (a *Audit) RPCObserver() rpc.Observer {
 }
 
 // AuditRPCObserver is an observer which will log RPC requests using
-// the function provided.
+// the function provided. Only requests to methods that may change
+// state are logged, along with their outcome.
 type AuditRPCObserver struct {
 	jujuServerVersion version.Number
 	modelUUID         string
@@ -95,25 +99,45 @@ type AuditRPCObserver struct {
 	handleAuditEntry  audit.AuditEntrySinkFn
 	authenticatedTag  string
 	remoteAddress     string
+
+	// pending holds the entry for the request being served, or
+	// nil if the request is not audited.
+	pending *audit.AuditEntry
 }
 
 // ServerRequest implements Observer.
 func (a *AuditRPCObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
+	if isReadOnlyRequest(hdr.Request) {
+		a.pending = nil
+		return
+	}
 	auditEntry := a.boilerplateAuditEntry()
 	auditEntry.OriginName = a.authenticatedTag
 
 	auditEntry.OriginType = "API request"
 	auditEntry.Operation = rpcRequestToOperation(hdr.Request)
-	auditEntry.Data = map[string]interface{}{"request-body": body}
+	auditEntry.Data = map[string]interface{}{
+		"request-args":   summarizeArgs(body),
+		"correlation-id": hdr.CorrelationId,
+	}
+	a.pending = &auditEntry
+}
+
+// ServerReply implements Observer.
+func (a *AuditRPCObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	if a.pending == nil {
+		return
+	}
+	auditEntry := *a.pending
+	a.pending = nil
+	auditEntry.Data["error-code"] = hdr.ErrorCode
+	auditEntry.Data["error"] = hdr.Error
 	err := a.handleAuditEntry(auditEntry)
 	if err != nil {
 		a.errorHandler(errors.Trace(err))
 	}
 }
 
-// ServerReply implements Observer.
-func (a *AuditRPCObserver) ServerReply(rpc.Request, *rpc.Header, interface{}) {}
-
 func (a *AuditRPCObserver) boilerplateAuditEntry() audit.AuditEntry {
 	return audit.AuditEntry{
 		JujuServerVersion: a.jujuServerVersion,
@@ -124,6 +148,124 @@ func (a *AuditRPCObserver) boilerplateAuditEntry() audit.AuditEntry {
 	}
 }
 
+// summarizeArgs returns a summary of the arguments of an API request
+// for the audit log. The arguments themselves are not recorded, since
+// they may hold passwords, credentials or secrets: only their type,
+// the number of items in each of their fields, and the entity tags
+// they refer to are.
+func summarizeArgs(body interface{}) map[string]interface{} {
+	summary := map[string]interface{}{
+		"type": fmt.Sprintf("%T", body),
+	}
+	v := reflect.Indirect(reflect.ValueOf(body))
+	if !v.IsValid() {
+		return summary
+	}
+	shape := make(map[string]int)
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.PkgPath == "" {
+				addShape(shape, field.Name, v.Field(i))
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			addShape(shape, fmt.Sprint(key.Interface()), v.MapIndex(key))
+		}
+	}
+	summary["shape"] = shape
+	if tags := collectTags(v, "", nil); len(tags) > 0 {
+		sort.Strings(tags)
+		summary["tags"] = tags
+	}
+	return summary
+}
+
+// addShape records the number of items in a field of the arguments,
+// or 1 for a field with a single value.
+func addShape(shape map[string]int, name string, v reflect.Value) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+	case reflect.Slice, reflect.Array, reflect.Map:
+		shape[name] = v.Len()
+	default:
+		shape[name] = 1
+	}
+}
+
+// collectTags returns the values of the string fields and map entries
+// in v that hold entity tags, appended to tags.
+func collectTags(v reflect.Value, name string, tags []string) []string {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if !v.IsNil() {
+			tags = collectTags(v.Elem(), name, tags)
+		}
+	case reflect.String:
+		if isTagName(name) {
+			if _, err := names.ParseTag(v.String()); err == nil {
+				tags = append(tags, v.String())
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.PkgPath == "" {
+				tags = collectTags(v.Field(i), field.Name, tags)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			tags = collectTags(v.Index(i), name, tags)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			tags = collectTags(v.MapIndex(key), fmt.Sprint(key.Interface()), tags)
+		}
+	}
+	return tags
+}
+
+// isTagName reports whether the named field or map entry holds a tag,
+// as in Tag and ModelTag, or "tag" and "model-tag".
+func isTagName(name string) bool {
+	return name == "tag" || strings.HasSuffix(name, "Tag") || strings.HasSuffix(name, "-tag")
+}
+
 func rpcRequestToOperation(req rpc.Request) string {
 	return fmt.Sprintf("%s:v%d - %s", req.Type, req.Version, req.Action)
 }
+
+// readOnlyMethodPrefixes and readOnlyMethodSuffixes match the names
+// of API methods that only read state, by convention.
+var (
+	readOnlyMethodPrefixes = []string{
+		"Describe", "Find", "FullStatus", "Get", "List", "Ping", "Show", "Status", "Watch",
+	}
+	readOnlyMethodSuffixes = []string{"Get", "Info"}
+)
+
+// isReadOnlyRequest reports whether req is to a method that does not
+// change state, and so need not be audited.
+func isReadOnlyRequest(req rpc.Request) bool {
+	if req.Type == "Pinger" || strings.HasSuffix(req.Type, "Watcher") {
+		return true
+	}
+	if strings.HasPrefix(req.Action, "Set") {
+		return false
+	}
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(req.Action, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range readOnlyMethodSuffixes {
+		if strings.HasSuffix(req.Action, suffix) {
+			return true
+		}
+	}
+	return false
+}
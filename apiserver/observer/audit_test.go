@@ -0,0 +1,131 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package observer_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/rpc"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditSuite struct {
+	testing.IsolationSuite
+	entries []audit.AuditEntry
+	errors  []error
+	audit   *observer.Audit
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.entries = nil
+	s.errors = nil
+	s.audit = observer.NewAudit(
+		&observer.AuditContext{
+			JujuServerVersion: version.MustParse("2.2.0"),
+			ModelUUID:         coretesting.ModelTag.Id(),
+		},
+		func(entry audit.AuditEntry) error {
+			s.entries = append(s.entries, entry)
+			return nil
+		},
+		func(err error) {
+			s.errors = append(s.errors, err)
+		},
+	)
+	s.audit.Join(&http.Request{RemoteAddr: "10.0.0.1:1234"}, 1)
+	s.audit.Login(names.NewUserTag("bob"), coretesting.ModelTag, false, "")
+}
+
+func (s *auditSuite) call(facade, method string, args interface{}, errorCode, errorMessage string) {
+	req := rpc.Request{Type: facade, Version: 1, Action: method}
+	o := s.audit.RPCObserver()
+	o.ServerRequest(&rpc.Header{Request: req, CorrelationId: "0123456789abcdef"}, args)
+	o.ServerReply(req, &rpc.Header{Request: req, ErrorCode: errorCode, Error: errorMessage}, nil)
+}
+
+func (s *auditSuite) TestMutatingCallAudited(c *gc.C) {
+	args := map[string]interface{}{"application": "mysql"}
+	s.call("Application", "Destroy", args, "not found", `application "mysql" not found`)
+
+	c.Assert(s.errors, gc.HasLen, 0)
+	c.Assert(s.entries, gc.HasLen, 1)
+	entry := s.entries[0]
+	c.Check(entry.Validate(), jc.ErrorIsNil)
+	c.Check(entry.OriginName, gc.Equals, "user-bob")
+	c.Check(entry.RemoteAddress, gc.Equals, "10.0.0.1:1234")
+	c.Check(entry.Operation, gc.Equals, "Application:v1 - Destroy")
+	c.Check(entry.Data, jc.DeepEquals, map[string]interface{}{
+		"request-args": map[string]interface{}{
+			"type":  "map[string]interface {}",
+			"shape": map[string]int{"application": 1},
+		},
+		"correlation-id": "0123456789abcdef",
+		"error-code":     "not found",
+		"error":          `application "mysql" not found`,
+	})
+}
+
+func (s *auditSuite) TestArgsNotRecorded(c *gc.C) {
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: "user-bob", Password: "sekrit"},
+			{Tag: "machine-0", Password: "sekrit"},
+		},
+	}
+	s.call("UserManager", "SetPassword", args, "", "")
+
+	c.Assert(s.entries, gc.HasLen, 1)
+	data := s.entries[0].Data
+	c.Check(fmt.Sprint(data), gc.Not(jc.Contains), "sekrit")
+	c.Check(data["request-args"], jc.DeepEquals, map[string]interface{}{
+		"type":  "params.EntityPasswords",
+		"shape": map[string]int{"Changes": 2},
+		"tags":  []string{"machine-0", "user-bob"},
+	})
+}
+
+func (s *auditSuite) TestReadOnlyCallsNotAudited(c *gc.C) {
+	s.call("Client", "FullStatus", nil, "", "")
+	s.call("Application", "Get", nil, "", "")
+	s.call("ModelConfig", "ModelGet", nil, "", "")
+	s.call("Client", "ModelInfo", nil, "", "")
+	s.call("AllWatcher", "Next", nil, "", "")
+	s.call("Pinger", "Ping", nil, "", "")
+	c.Assert(s.entries, gc.HasLen, 0)
+
+	s.call("Machiner", "SetInstanceInfo", nil, "", "")
+	c.Assert(s.entries, gc.HasLen, 1)
+}
+
+func (s *auditSuite) TestSinkError(c *gc.C) {
+	s.audit = observer.NewAudit(
+		&observer.AuditContext{
+			JujuServerVersion: version.MustParse("2.2.0"),
+			ModelUUID:         coretesting.ModelTag.Id(),
+		},
+		func(audit.AuditEntry) error {
+			return errors.New("disk full")
+		},
+		func(err error) {
+			s.errors = append(s.errors, err)
+		},
+	)
+	s.call("Application", "Destroy", nil, "", "")
+	c.Assert(s.errors, gc.HasLen, 1)
+	c.Assert(s.errors[0], gc.ErrorMatches, "disk full")
+}
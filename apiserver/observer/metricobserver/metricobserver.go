@@ -74,6 +74,15 @@ func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
 		Help:      "Latency of Juju API requests in seconds.",
 	}, metricLabelNames)
 
+	// The histogram complements the summary: its buckets can be
+	// aggregated across controllers, which quantiles cannot.
+	apiRequestDurationHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "juju",
+		Subsystem: "api",
+		Name:      "request_duration_histogram_seconds",
+		Help:      "Histogram of Juju API request latencies in seconds.",
+	}, metricLabelNames)
+
 	config.PrometheusRegisterer.Unregister(apiRequestsTotal)
 	if err := config.PrometheusRegisterer.Register(apiRequestsTotal); err != nil {
 		return nil, errors.Trace(err)
@@ -84,14 +93,20 @@ func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
 		return nil, errors.Trace(err)
 	}
 
+	config.PrometheusRegisterer.Unregister(apiRequestDurationHistogram)
+	if err := config.PrometheusRegisterer.Register(apiRequestDurationHistogram); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Observer is currently stateless, so we return the same one for each
 	// API connection. Individual RPC requests still get their own RPC
 	// observers.
 	o := &Observer{
 		clock: config.Clock,
 		metrics: metrics{
-			apiRequestDuration: apiRequestDuration,
-			apiRequestsTotal:   apiRequestsTotal,
+			apiRequestDuration:          apiRequestDuration,
+			apiRequestDurationHistogram: apiRequestDurationHistogram,
+			apiRequestsTotal:            apiRequestsTotal,
 		},
 	}
 	return func() observer.Observer {
@@ -106,8 +121,9 @@ type Observer struct {
 }
 
 type metrics struct {
-	apiRequestDuration *prometheus.SummaryVec
-	apiRequestsTotal   *prometheus.CounterVec
+	apiRequestDuration          *prometheus.SummaryVec
+	apiRequestDurationHistogram *prometheus.HistogramVec
+	apiRequestsTotal            *prometheus.CounterVec
 }
 
 // Login is part of the observer.Observer interface.
@@ -148,5 +164,6 @@ func (o *rpcObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interfa
 	}
 	duration := o.clock.Now().Sub(o.requestStart)
 	o.metrics.apiRequestDuration.With(labels).Observe(duration.Seconds())
+	o.metrics.apiRequestDurationHistogram.With(labels).Observe(duration.Seconds())
 	o.metrics.apiRequestsTotal.With(labels).Inc()
 }
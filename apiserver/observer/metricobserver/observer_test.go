@@ -86,8 +86,23 @@ func (s *observerSuite) TestRPCObserver(c *gc.C) {
 
 	metricFamilies, err := s.registry.Gather()
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(metricFamilies, gc.HasLen, 2)
-	c.Assert(metricFamilies, jc.DeepEquals, []*dto.MetricFamily{{
+	c.Assert(metricFamilies, gc.HasLen, 3)
+
+	histogram := metricFamilies[0]
+	c.Assert(histogram.GetName(), gc.Equals, "juju_api_request_duration_histogram_seconds")
+	c.Assert(histogram.GetType(), gc.Equals, dto.MetricType_HISTOGRAM)
+	c.Assert(histogram.Metric, gc.HasLen, 1)
+	c.Assert(histogram.Metric[0].Label, jc.DeepEquals, labels)
+	c.Assert(histogram.Metric[0].Histogram.GetSampleCount(), gc.Equals, uint64(3))
+	c.Assert(histogram.Metric[0].Histogram.GetSampleSum(), gc.Equals, 4.5)
+	buckets := make(map[float64]uint64)
+	for _, bucket := range histogram.Metric[0].Histogram.Bucket {
+		buckets[bucket.GetUpperBound()] = bucket.GetCumulativeCount()
+	}
+	c.Assert(buckets[1], gc.Equals, uint64(1))
+	c.Assert(buckets[2.5], gc.Equals, uint64(3))
+
+	c.Assert(metricFamilies[1:], jc.DeepEquals, []*dto.MetricFamily{{
 		Name: stringptr("juju_api_request_duration_seconds"),
 		Help: stringptr("Latency of Juju API requests in seconds."),
 		Type: metricTypePtr(dto.MetricType_SUMMARY),
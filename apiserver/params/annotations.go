@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // AnnotationsGetResult holds entity annotations or retrieval error.
 type AnnotationsGetResult struct {
 	EntityTag   string            `json:"entity"`
@@ -25,3 +27,47 @@ type EntityAnnotations struct {
 	EntityTag   string            `json:"entity"`
 	Annotations map[string]string `json:"annotations"`
 }
+
+// OperatorMetadata holds typed metadata recorded by operators against
+// an entity.
+type OperatorMetadata struct {
+	Owner      string     `json:"owner,omitempty"`
+	CostCenter string     `json:"cost-center,omitempty"`
+	Expiry     *time.Time `json:"expiry,omitempty"`
+}
+
+// EntityOperatorMetadata holds the operator metadata to set on an
+// entity.
+type EntityOperatorMetadata struct {
+	EntityTag string           `json:"entity"`
+	Metadata  OperatorMetadata `json:"metadata"`
+}
+
+// SetOperatorMetadataArgs holds the arguments for setting the operator
+// metadata of entities.
+type SetOperatorMetadataArgs struct {
+	Metadata []EntityOperatorMetadata `json:"metadata"`
+}
+
+// OperatorMetadataResult holds the operator metadata of an entity, or
+// an error.
+type OperatorMetadataResult struct {
+	EntityTag string           `json:"entity"`
+	Metadata  OperatorMetadata `json:"metadata"`
+	Error     *Error           `json:"error,omitempty"`
+}
+
+// OperatorMetadataResults holds the operator metadata of a number of
+// entities.
+type OperatorMetadataResults struct {
+	Results []OperatorMetadataResult `json:"results"`
+}
+
+// OperatorMetadataFilter selects entities by their operator metadata.
+// Fields left empty match every entity.
+type OperatorMetadataFilter struct {
+	Kind          string     `json:"kind,omitempty"`
+	Owner         string     `json:"owner,omitempty"`
+	CostCenter    string     `json:"cost-center,omitempty"`
+	ExpiresBefore *time.Time `json:"expires-before,omitempty"`
+}
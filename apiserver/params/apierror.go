@@ -88,12 +88,14 @@ const (
 	CodeLeadershipClaimDenied     = "leadership claim denied"
 	CodeLeaseClaimDenied          = "lease claim denied"
 	CodeNotSupported              = "not supported"
+	CodeNotValid                  = "not valid"
 	CodeBadRequest                = "bad request"
 	CodeMethodNotAllowed          = "method not allowed"
 	CodeForbidden                 = "forbidden"
 	CodeDischargeRequired         = "macaroon discharge required"
 	CodeRedirect                  = "redirection required"
 	CodeRetry                     = "retry"
+	CodeQuotaExceeded             = "quota exceeded"
 )
 
 // ErrCode returns the error code associated with
@@ -188,6 +190,10 @@ func IsCodeMachineHasAttachedStorage(err error) bool {
 	return ErrCode(err) == CodeMachineHasAttachedStorage
 }
 
+func IsCodeQuotaExceeded(err error) bool {
+	return ErrCode(err) == CodeQuotaExceeded
+}
+
 func IsCodeNotProvisioned(err error) bool {
 	return ErrCode(err) == CodeNotProvisioned
 }
@@ -228,6 +234,10 @@ func IsCodeNotSupported(err error) bool {
 	return ErrCode(err) == CodeNotSupported
 }
 
+func IsCodeNotValid(err error) bool {
+	return ErrCode(err) == CodeNotValid
+}
+
 func IsBadRequest(err error) bool {
 	return ErrCode(err) == CodeBadRequest
 }
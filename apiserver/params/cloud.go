@@ -32,6 +32,30 @@ type CloudResults struct {
 	Results []CloudResult `json:"results,omitempty"`
 }
 
+// ConfigSchemaField describes a model config attribute specific to a
+// cloud's provider.
+type ConfigSchemaField struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Secret      bool          `json:"secret,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Values      []interface{} `json:"values,omitempty"`
+}
+
+// ProviderConfigSchemaResult contains the provider specific model
+// config attributes of a cloud, or an error.
+type ProviderConfigSchemaResult struct {
+	Fields []ConfigSchemaField `json:"fields,omitempty"`
+	Error  *Error              `json:"error,omitempty"`
+}
+
+// ProviderConfigSchemaResults contains a set of ProviderConfigSchemaResults.
+type ProviderConfigSchemaResults struct {
+	Results []ProviderConfigSchemaResult `json:"results,omitempty"`
+}
+
 // CloudsResult contains a set of Clouds.
 type CloudsResult struct {
 	// Clouds is a map of clouds, keyed by cloud tag.
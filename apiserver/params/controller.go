@@ -3,6 +3,12 @@
 
 package params
 
+import (
+	"time"
+
+	"github.com/juju/version"
+)
+
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
 	// DestroyModels specifies whether or not the hosted models
@@ -87,3 +93,87 @@ const (
 	GrantControllerAccess  ControllerAction = "grant"
 	RevokeControllerAccess ControllerAction = "revoke"
 )
+
+// AuditLogFilter selects the entries returned by the AuditLog call.
+// Zero-valued fields match every entry.
+type AuditLogFilter struct {
+	ModelTag string     `json:"model-tag,omitempty"`
+	UserTag  string     `json:"user-tag,omitempty"`
+	After    *time.Time `json:"after,omitempty"`
+	Before   *time.Time `json:"before,omitempty"`
+	Limit    int        `json:"limit,omitempty"`
+}
+
+// AuditLogEntry holds a single entry from the audit log of API calls
+// that may change state.
+type AuditLogEntry struct {
+	JujuServerVersion version.Number         `json:"juju-server-version"`
+	ModelTag          string                 `json:"model-tag"`
+	Timestamp         time.Time              `json:"timestamp"`
+	RemoteAddress     string                 `json:"remote-address"`
+	OriginType        string                 `json:"origin-type"`
+	OriginName        string                 `json:"origin-name"`
+	Operation         string                 `json:"operation"`
+	Data              map[string]interface{} `json:"data,omitempty"`
+}
+
+// AuditLogResults holds the results of an AuditLog call, most recent
+// first.
+type AuditLogResults struct {
+	Entries []AuditLogEntry `json:"entries"`
+}
+
+// LeaseDetails describes a lease held in a model.
+type LeaseDetails struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Holder     string    `json:"holder"`
+	Expiry     time.Time `json:"expiry"`
+	Extensions int       `json:"extensions"`
+}
+
+// LeaseDetailsResult holds the leases held in a single model, or an
+// error.
+type LeaseDetailsResult struct {
+	Leases []LeaseDetails `json:"leases,omitempty"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
+// LeaseDetailsResults holds the results of a ModelLeases call.
+type LeaseDetailsResults struct {
+	Results []LeaseDetailsResult `json:"results"`
+}
+
+// StageCARotationArgs holds the CA certificate and private key that
+// are to replace the controller's CA.
+type StageCARotationArgs struct {
+	CACert       string `json:"ca-cert"`
+	CAPrivateKey string `json:"ca-private-key"`
+}
+
+// CARotationStatus describes a CA rotation in progress.
+type CARotationStatus struct {
+	Phase          string `json:"phase"`
+	CACert         string `json:"ca-cert"`
+	PreviousCACert string `json:"previous-ca-cert"`
+}
+
+// CARotationStatusResult holds the result of a CARotationStatus call.
+// Status is nil if no rotation is in progress.
+type CARotationStatusResult struct {
+	Status *CARotationStatus `json:"status,omitempty"`
+}
+
+// MongoSessionStats holds statistics about the mongo connections made
+// by a controller.
+type MongoSessionStats struct {
+	Clusters     int `json:"clusters"`
+	MasterConns  int `json:"master-conns"`
+	SlaveConns   int `json:"slave-conns"`
+	SentOps      int `json:"sent-ops"`
+	ReceivedOps  int `json:"received-ops"`
+	ReceivedDocs int `json:"received-docs"`
+	SocketsAlive int `json:"sockets-alive"`
+	SocketsInUse int `json:"sockets-in-use"`
+	SocketRefs   int `json:"socket-refs"`
+}
@@ -3,6 +3,10 @@
 
 package params
 
+import (
+	"github.com/juju/juju/constraints"
+)
+
 // ImageMetadataFilter holds filter properties used to search for image metadata.
 // It amalgamates both simplestreams.MetadataLookupParams and simplestreams.LookupParams
 // and adds additional properties to satisfy existing and new use cases.
@@ -26,6 +30,10 @@ type ImageMetadataFilter struct {
 
 	// RootStorageType stores storage type.
 	RootStorageType string `json:"root-storage-type,omitempty"`
+
+	// Pagination, if set, selects a page of the results. If it is
+	// not set, all results are returned.
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
 // CloudImageMetadata holds cloud image metadata properties.
@@ -59,6 +67,7 @@ type CloudImageMetadata struct {
 	RootStorageSize *uint64 `json:"root-storage-size,omitempty"`
 
 	// Source describes where this image is coming from: is it public? custom?
+	// Images pinned by an image-id constraint have source ImageSourcePinned.
 	Source string `json:"source"`
 
 	// Priority is an importance factor for image metadata.
@@ -67,9 +76,18 @@ type CloudImageMetadata struct {
 	Priority int `json:"priority"`
 }
 
+// ImageSourcePinned is the source of image metadata describing an
+// image that was chosen explicitly with an image-id constraint, rather
+// than found in any image metadata.
+const ImageSourcePinned = "pinned"
+
 // ListCloudImageMetadataResult holds the results of querying cloud image metadata.
 type ListCloudImageMetadataResult struct {
 	Result []CloudImageMetadata `json:"result"`
+
+	// Pagination describes the page of results returned, if a page
+	// was requested.
+	Pagination *PaginationResult `json:"pagination,omitempty"`
 }
 
 // MetadataSaveParams holds lists of cloud image metadata to save. Each list
@@ -87,3 +105,50 @@ type CloudImageMetadataList struct {
 type MetadataImageIds struct {
 	Ids []string `json:"image-ids"`
 }
+
+// ImageMetadataExplainParams describes a hypothetical provisioning
+// request for which image selection should be explained.
+type ImageMetadataExplainParams struct {
+	// Series is the series of the machine. If empty, the model's
+	// default series is used.
+	Series string `json:"series,omitempty"`
+
+	// Arches holds the acceptable architectures. An arch constraint
+	// takes precedence.
+	Arches []string `json:"arches,omitempty"`
+
+	// Region is the cloud region. If empty, the model's region is used.
+	Region string `json:"region,omitempty"`
+
+	// Stream is the image stream. If empty, the model's image-stream
+	// is used.
+	Stream string `json:"stream,omitempty"`
+
+	// Constraints holds the machine constraints.
+	Constraints constraints.Value `json:"constraints"`
+}
+
+// ImageMetadataCandidate holds image metadata considered during
+// image selection.
+type ImageMetadataCandidate struct {
+	// Metadata is the image metadata.
+	Metadata CloudImageMetadata `json:"metadata"`
+
+	// Rejected holds the reason the image could not be selected,
+	// and is empty for acceptable images.
+	Rejected string `json:"rejected,omitempty"`
+}
+
+// ImageMetadataExplainResult holds the outcome of a simulated image
+// selection.
+type ImageMetadataExplainResult struct {
+	// Filter is the filter used to look up image metadata, after
+	// defaults have been applied.
+	Filter ImageMetadataFilter `json:"filter"`
+
+	// Selected is the image that would be chosen, if any.
+	Selected *CloudImageMetadata `json:"selected,omitempty"`
+
+	// Candidates holds all matching images in order of preference.
+	Candidates []ImageMetadataCandidate `json:"candidates"`
+}
@@ -72,3 +72,31 @@ type InstanceType struct {
 	Deprecated   bool     `json:"deprecated,omitempty"`
 	Cost         int      `json:"cost,omitempty"`
 }
+
+// CostEstimateParams holds the constraints for which to estimate the
+// cost of an instance.
+type CostEstimateParams struct {
+	Constraints []constraints.Value `json:"constraints"`
+}
+
+// CostEstimateResults holds the bulk result of estimating instance costs.
+type CostEstimateResults struct {
+	Results []CostEstimateResult `json:"results"`
+}
+
+// CostEstimateResult holds the estimated cost of an instance satisfying
+// a set of constraints.
+type CostEstimateResult struct {
+	// InstanceType is the name of the instance type on which the
+	// estimate is based.
+	InstanceType string `json:"instance-type,omitempty"`
+
+	// HourlyCost is the estimated cost of running the instance for
+	// an hour, expressed in Currency.
+	HourlyCost float64 `json:"hourly-cost,omitempty"`
+
+	// Currency is the currency in which HourlyCost is expressed.
+	Currency string `json:"currency,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
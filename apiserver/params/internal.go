@@ -75,6 +75,32 @@ type StringResults struct {
 	Results []StringResult `json:"results"`
 }
 
+// AgentSettings holds the model-level settings applied to an agent.
+type AgentSettings struct {
+	// LoggingConfig is the loggo configuration string for the agent.
+	LoggingConfig string `json:"logging-config"`
+
+	// DataDir is the agent data directory configured for newly
+	// provisioned machines, if any.
+	DataDir string `json:"data-dir,omitempty"`
+
+	// MaxLogSize is the size in megabytes at which the agent's log
+	// file is rotated, or zero if the default should be used.
+	MaxLogSize int `json:"max-log-size,omitempty"`
+}
+
+// AgentSettingsResult holds agent settings or an error.
+type AgentSettingsResult struct {
+	Error  *Error         `json:"error,omitempty"`
+	Result *AgentSettings `json:"result,omitempty"`
+}
+
+// AgentSettingsResults holds the bulk operation result of an API
+// call that returns agent settings.
+type AgentSettingsResults struct {
+	Results []AgentSettingsResult `json:"results"`
+}
+
 // MapResult holds a generic map or an error.
 type MapResult struct {
 	Result map[string]interface{} `json:"result"`
@@ -616,6 +642,15 @@ type ProvisioningInfo struct {
 	ImageMetadata    []CloudImageMetadata      `json:"image-metadata,omitempty"`
 	EndpointBindings map[string]string         `json:"endpoint-bindings,omitempty"`
 	ControllerConfig map[string]interface{}    `json:"controller-config,omitempty"`
+	NetworkBindings  []NetworkBinding          `json:"network-bindings,omitempty"`
+}
+
+// NetworkBinding describes a network interface, in addition to its
+// primary one, that a new machine needs to reach a space.
+type NetworkBinding struct {
+	InterfaceName string `json:"interface-name"`
+	Space         string `json:"space"`
+	SubnetCIDR    string `json:"subnet-cidr"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -629,6 +664,40 @@ type ProvisioningInfoResults struct {
 	Results []ProvisioningInfoResult `json:"results"`
 }
 
+// BulkProvisioningInfoArgs holds the arguments to the Provisioner
+// facade's BulkProvisioningInfo method.
+type BulkProvisioningInfoArgs struct {
+	Entities []Entity `json:"entities"`
+
+	// AgentVersion is the version of the tools to find for each
+	// machine. If it is zero, no tools are found.
+	AgentVersion version.Number `json:"agent-version"`
+
+	// Arch, if set, is the architecture of the tools to find,
+	// overriding any architecture constraints of the machines.
+	Arch string `json:"arch,omitempty"`
+}
+
+// BulkProvisioningInfoResult holds the provisioning info for a
+// machine, along with the tools it may be started with.
+type BulkProvisioningInfoResult struct {
+	Error  *Error            `json:"error,omitempty"`
+	Result *ProvisioningInfo `json:"result,omitempty"`
+
+	// Tools holds the tools matching the machine's series and
+	// architecture constraints.
+	Tools tools.List `json:"tools,omitempty"`
+
+	// ToolsError holds any error encountered finding the tools.
+	ToolsError *Error `json:"tools-error,omitempty"`
+}
+
+// BulkProvisioningInfoResults holds the results of a call to the
+// Provisioner facade's BulkProvisioningInfo method.
+type BulkProvisioningInfoResults struct {
+	Results []BulkProvisioningInfoResult `json:"results"`
+}
+
 // Metric holds a single metric.
 type Metric struct {
 	Key   string    `json:"key"`
@@ -72,6 +72,53 @@ type ModelUnset struct {
 	Keys []string `json:"keys"`
 }
 
+// ModelConfigDiffArgs contains the arguments for the ModelConfigDiff
+// client API call: a proposed change to the model config.
+type ModelConfigDiffArgs struct {
+	Config map[string]interface{} `json:"config,omitempty"`
+	Unset  []string               `json:"unset,omitempty"`
+}
+
+// ConfigChange describes how a single config attribute would change.
+// Old and New are nil when the attribute is unset.
+type ConfigChange struct {
+	Attr string      `json:"attr"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// ModelConfigDiffResult contains the result of the ModelConfigDiff
+// client API call. Changes holds the effective changes the proposed
+// config change would make, including any derived defaults.
+// ImmutableViolations holds the changes to attributes that may not be
+// changed. Error is set if the proposed change is not valid.
+type ModelConfigDiffResult struct {
+	Changes             []ConfigChange `json:"changes,omitempty"`
+	ImmutableViolations []ConfigChange `json:"immutable-violations,omitempty"`
+	Error               *Error         `json:"error,omitempty"`
+}
+
+// ModelConfigHistoryArgs contains the arguments for the
+// ModelConfigHistory client API call. If Limit is positive, no more
+// than that many entries are returned.
+type ModelConfigHistoryArgs struct {
+	Limit int `json:"limit,omitempty"`
+}
+
+// ModelConfigHistoryEntry describes a change that was made to the
+// model config, and when and by whom it was made.
+type ModelConfigHistoryEntry struct {
+	Time    time.Time      `json:"time"`
+	Author  string         `json:"author,omitempty"`
+	Changes []ConfigChange `json:"changes"`
+}
+
+// ModelConfigHistoryResult contains the result of the
+// ModelConfigHistory client API call, most recent change first.
+type ModelConfigHistoryResult struct {
+	Entries []ModelConfigHistoryEntry `json:"entries"`
+}
+
 // SetModelDefaults contains the arguments for SetModelDefaults
 // client API call.
 type SetModelDefaults struct {
@@ -106,6 +153,60 @@ type SetModelAgentVersion struct {
 	Version version.Number `json:"version"`
 }
 
+// DesiredAgentVersion holds the version of juju that the agent of a
+// machine, or the agents of the machines hosting an application's
+// units, should run ahead of the model's agent-version.
+type DesiredAgentVersion struct {
+	Tag     string         `json:"tag"`
+	Version version.Number `json:"version"`
+}
+
+// SetDesiredAgentVersions contains the arguments for
+// SetDesiredAgentVersions client API call.
+type SetDesiredAgentVersions struct {
+	Entities []DesiredAgentVersion `json:"entities"`
+}
+
+// ModelQuotas holds the limits on the resources a model may use. A
+// zero limit means that the resource is not limited.
+type ModelQuotas struct {
+	MaxMachines  int    `json:"max-machines,omitempty"`
+	MaxUnits     int    `json:"max-units,omitempty"`
+	MaxStorageGB uint64 `json:"max-storage-gb,omitempty"`
+}
+
+// ModelQuotaUsage describes the use a model makes of the resources
+// limited by its quotas.
+type ModelQuotaUsage struct {
+	Machines   int    `json:"machines"`
+	Units      int    `json:"units"`
+	StorageMiB uint64 `json:"storage-mib"`
+}
+
+// ModelQuotasResult holds the quotas of a model and its use of the
+// resources they limit, or an error.
+type ModelQuotasResult struct {
+	Quotas *ModelQuotas     `json:"quotas,omitempty"`
+	Usage  *ModelQuotaUsage `json:"usage,omitempty"`
+	Error  *Error           `json:"error,omitempty"`
+}
+
+// ModelQuotasResults holds the results of a ModelQuotas call.
+type ModelQuotasResults struct {
+	Results []ModelQuotasResult `json:"results"`
+}
+
+// ModelQuotasParams holds the quotas to set on a model.
+type ModelQuotasParams struct {
+	ModelTag string      `json:"model-tag"`
+	Quotas   ModelQuotas `json:"quotas"`
+}
+
+// SetModelQuotas holds the arguments for a SetModelQuotas call.
+type SetModelQuotas struct {
+	Models []ModelQuotasParams `json:"models"`
+}
+
 // ModelMigrationStatus holds information about the progress of a (possibly
 // failed) migration.
 type ModelMigrationStatus struct {
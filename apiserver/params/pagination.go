@@ -0,0 +1,25 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// Pagination holds the arguments selecting one page of the results of
+// a call that may return a long list. The zero value selects the first
+// page, of the server's default size.
+type Pagination struct {
+	// Limit is the maximum number of results to return. If it is
+	// zero, the server's default is used. The server may return
+	// fewer results than requested.
+	Limit int `json:"limit,omitempty"`
+
+	// Cursor, if set, is the NextCursor returned with the previous
+	// page of results.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// PaginationResult describes a page of results.
+type PaginationResult struct {
+	// NextCursor, if set, is passed as Pagination.Cursor to get the
+	// next page of results. It is empty on the last page.
+	NextCursor string `json:"next-cursor,omitempty"`
+}
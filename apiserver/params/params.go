@@ -154,6 +154,10 @@ type AddMachineParams struct {
 	// the machine when it is provisioned.
 	Disks []storage.Constraints `json:"disks,omitempty"`
 
+	// Spaces holds the names of spaces the machine must have access
+	// to, in addition to any specified in Constraints.
+	Spaces []string `json:"spaces,omitempty"`
+
 	// If Placement is non-nil, it contains a placement directive
 	// that will be used to decide how to instantiate the machine.
 	Placement *instance.Placement `json:"placement,omitempty"`
@@ -339,6 +343,18 @@ type ApplicationMetricCredentials struct {
 	Creds []ApplicationMetricCredential `json:"creds"`
 }
 
+// EntityResourceTags holds the user-defined tags to set on a machine
+// or application.
+type EntityResourceTags struct {
+	Tag  string            `json:"tag"`
+	Tags map[string]string `json:"tags"`
+}
+
+// SetResourceTags holds the parameters for the SetTags call.
+type SetResourceTags struct {
+	Entities []EntityResourceTags `json:"entities"`
+}
+
 // PublicAddress holds parameters for the PublicAddress call.
 type PublicAddress struct {
 	Target string `json:"target"`
@@ -503,6 +519,9 @@ type StateServingInfo struct {
 	// The controller cert and corresponding private key.
 	Cert       string `json:"cert"`
 	PrivateKey string `json:"private-key"`
+	// Any intermediate CA certs to be served along with the
+	// controller cert.
+	CertChain string `json:"cert-chain,omitempty"`
 	// The private key for the CA cert so that a new controller
 	// cert can be generated when needed.
 	CAPrivateKey string `json:"ca-private-key"`
@@ -564,6 +583,10 @@ type ProvisioningScriptParams struct {
 	// provisioner to ensure that all the packages required by Juju
 	// are available.
 	DisablePackageCommands bool `json:"disable-package-commands"`
+
+	// AgentVersion, if set, overrides the model's agent-version for
+	// the tools installed on the machine.
+	AgentVersion *version.Number `json:"agent-version,omitempty"`
 }
 
 // ProvisioningScriptResult contains the result of the
@@ -608,6 +631,24 @@ type DistributionGroupResults struct {
 type FacadeVersions struct {
 	Name     string `json:"name"`
 	Versions []int  `json:"versions"`
+
+	// Deprecated describes those of Versions that are deprecated,
+	// ordered by version.
+	Deprecated []FacadeDeprecation `json:"deprecated,omitempty"`
+}
+
+// FacadeDeprecation describes a deprecated version of a facade.
+type FacadeDeprecation struct {
+	// Version is the deprecated facade version.
+	Version int `json:"version"`
+
+	// Since is the version of Juju in which the facade version
+	// was deprecated.
+	Since string `json:"since"`
+
+	// Sunset, if set, is the version of Juju from which the facade
+	// version will no longer be served.
+	Sunset string `json:"sunset,omitempty"`
 }
 
 // RedirectInfoResult holds the result of a RedirectInfo call.
@@ -748,9 +789,30 @@ type FindToolsParams struct {
 }
 
 // FindToolsResult holds a list of tools from FindTools and any error.
+// Sources, if set, describes where each entry in List was found;
+// Sources[i] corresponds to List[i].
 type FindToolsResult struct {
-	List  tools.List `json:"list"`
-	Error *Error     `json:"error,omitempty"`
+	List    tools.List    `json:"list"`
+	Sources []ToolsSource `json:"sources,omitempty"`
+	Error   *Error        `json:"error,omitempty"`
+}
+
+// ToolsSource describes where tools returned by FindTools were found.
+type ToolsSource struct {
+	// InStorage is true if the tools were found in the controller's
+	// tools storage rather than in simplestreams.
+	InStorage bool `json:"in-storage,omitempty"`
+
+	// Stream is the simplestreams stream the tools were found in.
+	Stream string `json:"stream,omitempty"`
+
+	// Signed is true if the simplestreams metadata the tools were
+	// found in was signed.
+	Signed bool `json:"signed,omitempty"`
+
+	// URL is the URL the tools were found at, before being rewritten
+	// to point at the API server.
+	URL string `json:"url,omitempty"`
 }
 
 // ImageFilterParams holds the parameters used to specify images to delete.
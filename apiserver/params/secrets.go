@@ -0,0 +1,74 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// CreateSecretArgs holds the secrets to create.
+type CreateSecretArgs struct {
+	Args []CreateSecretArg `json:"args"`
+}
+
+// CreateSecretArg holds the name, description and initial value of a
+// secret to create.
+type CreateSecretArg struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Data        map[string]string `json:"data"`
+}
+
+// SecretNames holds the names of secrets.
+type SecretNames struct {
+	Names []string `json:"names"`
+}
+
+// SecretDetails describes a secret, without its value.
+type SecretDetails struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Revision    int    `json:"revision"`
+
+	// Readers holds the tags of the applications and units that may
+	// read the secret.
+	Readers []string `json:"readers"`
+}
+
+// ListSecretsResults holds the results of a ListSecrets call.
+type ListSecretsResults struct {
+	Results []SecretDetails `json:"results"`
+}
+
+// SecretAccessArgs holds the changes to make to the entities that may
+// read secrets.
+type SecretAccessArgs struct {
+	Args []SecretAccessArg `json:"args"`
+}
+
+// SecretAccessArg identifies a secret and an application or unit to
+// grant access to, or revoke access from.
+type SecretAccessArg struct {
+	Name string `json:"name"`
+	Tag  string `json:"tag"`
+}
+
+// RotateSecretArgs holds the new values of secrets.
+type RotateSecretArgs struct {
+	Args []RotateSecretArg `json:"args"`
+}
+
+// RotateSecretArg holds the new value of a secret.
+type RotateSecretArg struct {
+	Name string            `json:"name"`
+	Data map[string]string `json:"data"`
+}
+
+// SecretValueResults holds the results of a GetSecretValues call.
+type SecretValueResults struct {
+	Results []SecretValueResult `json:"results"`
+}
+
+// SecretValueResult holds the latest value of a secret, or an error.
+type SecretValueResult struct {
+	Revision int               `json:"revision,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+	Error    *Error            `json:"error,omitempty"`
+}
@@ -94,6 +94,9 @@ type MachineStatus struct {
 	// hardware specification datum.
 	Hardware string `json:"hardware"`
 
+	// Tags holds the user-defined tags set on the machine.
+	Tags map[string]string `json:"tags,omitempty"`
+
 	Jobs      []multiwatcher.MachineJob `json:"jobs"`
 	HasVote   bool                      `json:"has-vote"`
 	WantsVote bool                      `json:"wants-vote"`
@@ -113,6 +116,7 @@ type ApplicationStatus struct {
 	MeterStatuses   map[string]MeterStatus `json:"meter-statuses"`
 	Status          DetailedStatus         `json:"status"`
 	WorkloadVersion string                 `json:"workload-version"`
+	Tags            map[string]string      `json:"tags,omitempty"`
 }
 
 // RemoteApplicationStatus holds status info about a remote application.
@@ -204,6 +208,11 @@ type StatusHistoryRequest struct {
 	Size   int                 `json:"size"`
 	Filter StatusHistoryFilter `json:"filter"`
 	Tag    string              `json:"tag"`
+
+	// Pagination, if set, selects a page of the history, which is
+	// ordered oldest first. If it is not set, all of the history
+	// selected by the filter is returned.
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
 // StatusHistoryRequests holds a slice of StatusHistoryArgs.
@@ -215,6 +224,10 @@ type StatusHistoryRequests struct {
 type StatusHistoryResult struct {
 	History History `json:"history"`
 	Error   *Error  `json:"error,omitempty"`
+
+	// Pagination describes the page of history returned, if a page
+	// was requested.
+	Pagination *PaginationResult `json:"pagination,omitempty"`
 }
 
 // StatusHistoryResults holds a slice of StatusHistoryResult.
@@ -11,11 +11,13 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/payload/api"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
 func init() {
 	common.RegisterStandardFacade("Payloads", 1, NewFacade)
+	common.RequireFacadeAccess("Payloads", "List", permission.ReadAccess)
 }
 
 // NewFacade returns a new handler for the "Payloads" facade. It is used for facade registration.
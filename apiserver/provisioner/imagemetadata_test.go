@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/provisioner"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	imagetesting "github.com/juju/juju/environs/imagemetadata/testing"
 	sstesting "github.com/juju/juju/environs/simplestreams/testing"
@@ -88,6 +89,10 @@ func (s *ImageMetadataSuite) TestMetadataNotInStateButInDataSources(c *gc.C) {
 	saved, err := s.State.CloudImageMetadataStorage.FindMetadata(criteria)
 	c.Assert(err, jc.ErrorIsNil)
 	stateExpected := s.convertCloudImageMetadata(expected[0])
+	for i := range stateExpected {
+		// The metadata is recorded against the model's cloud.
+		stateExpected[i].Cloud = "dummy"
+	}
 	if len(saved["default cloud images"]) == len(stateExpected) {
 		for i, image := range saved["default cloud images"] {
 			stateExpected[i].DateCreated = image.DateCreated
@@ -119,6 +124,50 @@ func (s *ImageMetadataSuite) TestMetadataFromState(c *gc.C) {
 	s.assertImageMetadataResults(c, result, expected...)
 }
 
+func (s *ImageMetadataSuite) TestMetadataFilteredByVirtType(c *gc.C) {
+	expected := s.expectedDataSoureImageMetadata()
+	for _, m := range s.convertCloudImageMetadata(expected[0]) {
+		err := s.State.CloudImageMetadataStorage.SaveMetadata(
+			[]cloudimagemetadata.Metadata{m},
+		)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// All the stored images are paravirtual.
+	err := s.machines[0].SetConstraints(constraints.MustParse("virt-type=hvm"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machines[1].SetConstraints(constraints.MustParse("virt-type=pv"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	api, err := provisioner.NewProvisionerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.ProvisioningInfo(params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.machines[0].Tag().String()},
+			{Tag: s.machines[1].Tag().String()},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertImageMetadataResults(c, result, nil, expected[1])
+}
+
+func (s *ImageMetadataSuite) TestPinnedImageNeedsRegion(c *gc.C) {
+	// The dummy provider has no notion of regions, so an image
+	// cannot be pinned.
+	err := s.machines[0].SetConstraints(constraints.MustParse("image-id=ami-12345"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	api, err := provisioner.NewProvisionerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.ProvisioningInfo(params.Entities{
+		Entities: []params.Entity{{Tag: s.machines[0].Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches,
+		`cannot get available image metadata: specifying image-id for "dummy" provider not supported`)
+}
+
 func (s *ImageMetadataSuite) getTestMachinesTags(c *gc.C) params.Entities {
 
 	testMachines := make([]params.Entity, len(s.machines))
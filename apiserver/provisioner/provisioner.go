@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
@@ -19,6 +20,9 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/modelstorage"
+	envstorage "github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/network/containerizer"
@@ -33,7 +37,27 @@ import (
 var logger = loggo.GetLogger("juju.apiserver.provisioner")
 
 func init() {
-	common.RegisterStandardFacade("Provisioner", 3, NewProvisionerAPI)
+	common.RegisterStandardFacade("Provisioner", 4, NewProvisionerAPI)
+	// Version 3 is served by version 4, without BulkProvisioningInfo.
+	common.RegisterFacadeTranslation("Provisioner", 3, facade.Translation{
+		Omit: []string{"BulkProvisioningInfo"},
+	})
+}
+
+// toolsURLGetter returns the ToolsURLGetter used to tell newly
+// provisioned machines where to download their tools. If the model
+// keeps its files in object storage, tools found there are downloaded
+// directly from the storage, sparing the controller's bandwidth when
+// many machines are provisioned at once.
+func toolsURLGetter(modelUUID string, st *state.State, cfg *config.Config) common.ToolsURLGetter {
+	stor, err := modelstorage.Open(cfg, envstorage.NewLoggingObserver(logger))
+	if errors.IsNotFound(err) {
+		return common.NewToolsURLGetter(modelUUID, st)
+	} else if err != nil {
+		logger.Warningf("cannot open object storage for tools: %v", err)
+		return common.NewToolsURLGetter(modelUUID, st)
+	}
+	return common.NewSignedToolsURLGetter(modelUUID, st, stor, cfg.AgentStream(), clock.WallClock)
 }
 
 // ProvisionerAPI provides access to the Provisioner API facade.
@@ -113,7 +137,7 @@ func NewProvisionerAPI(st *state.State, resources facade.Resources, authorizer f
 	if err != nil {
 		return nil, err
 	}
-	urlGetter := common.NewToolsURLGetter(model.UUID(), st)
+	urlGetter := toolsURLGetter(model.UUID(), st, env.Config())
 	storageProviderRegistry := stateenvirons.NewStorageProviderRegistry(env)
 	return &ProvisionerAPI{
 		Remover:                 common.NewRemover(st, false, getAuthFunc),
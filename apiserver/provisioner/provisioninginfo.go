@@ -9,14 +9,17 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
 	"github.com/juju/utils/series"
 	"github.com/juju/utils/set"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
@@ -25,6 +28,7 @@ import (
 	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/storage"
+	coretools "github.com/juju/juju/tools"
 )
 
 // ProvisioningInfo returns the provisioning information for each given machine entity.
@@ -51,6 +55,85 @@ func (p *ProvisionerAPI) ProvisioningInfo(args params.Entities) (params.Provisio
 	return result, nil
 }
 
+// BulkProvisioningInfo returns the provisioning information for each
+// given machine, along with the tools it may be started with, so that
+// a batch of machines can be started without further API calls.
+func (p *ProvisionerAPI) BulkProvisioningInfo(args params.BulkProvisioningInfoArgs) (params.BulkProvisioningInfoResults, error) {
+	result := params.BulkProvisioningInfoResults{
+		Results: make([]params.BulkProvisioningInfoResult, len(args.Entities)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result, err = p.getProvisioningInfo(machine)
+		}
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if args.AgentVersion != version.Zero {
+			tools, err := p.machineTools(result.Results[i].Result, args.AgentVersion, args.Arch)
+			result.Results[i].Tools = tools
+			result.Results[i].ToolsError = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// machineTools returns the tools of the given version that a machine
+// with the given provisioning info may be started with. If arch is
+// empty, the tools are those matching the machine's architecture
+// constraints, if any.
+func (p *ProvisionerAPI) machineTools(info *params.ProvisioningInfo, agentVersion version.Number, arch string) (coretools.List, error) {
+	// If several arches are acceptable, find tools for all
+	// arches and keep only those for the acceptable ones.
+	var arches []string
+	if arch == "" {
+		arches = info.Constraints.Arches()
+		if len(arches) == 1 {
+			arch = arches[0]
+		}
+	}
+	result, err := p.ToolsFinder.FindTools(params.FindToolsParams{
+		Number:       agentVersion,
+		MajorVersion: -1,
+		MinorVersion: -1,
+		Series:       info.Series,
+		Arch:         arch,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(arches) <= 1 {
+		return result.List, nil
+	}
+	var list coretools.List
+	for _, tools := range result.List {
+		for _, arch := range arches {
+			if tools.Version.Arch == arch {
+				list = append(list, tools)
+				break
+			}
+		}
+	}
+	if len(list) == 0 {
+		return nil, errors.NotFoundf("tools for arches %q", arches)
+	}
+	return list, nil
+}
+
 func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.ProvisioningInfo, error) {
 	cons, err := m.Constraints()
 	if err != nil {
@@ -89,6 +172,10 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get controller configuration")
 	}
+	networkBindings, err := p.machineNetworkBindings(m)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot determine machine network bindings")
+	}
 
 	return &params.ProvisioningInfo{
 		Constraints:      cons,
@@ -101,6 +188,7 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 		EndpointBindings: endpointBindings,
 		ImageMetadata:    imageMetadata,
 		ControllerConfig: controllerCfg,
+		NetworkBindings:  networkBindings,
 	}, nil
 }
 
@@ -186,13 +274,26 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 		return nil, errors.Trace(err)
 	}
 	unitNames := make([]string, 0, len(units))
+	userTags := make(map[string]string)
 	for _, unit := range units {
 		if !unit.IsPrincipal() {
 			continue
 		}
 		unitNames = append(unitNames, unit.Name())
+		application, err := unit.Application()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for k, v := range application.Tags() {
+			userTags[k] = v
+		}
 	}
 	sort.Strings(unitNames)
+	// Tags set on the machine itself take precedence over those set
+	// on the applications deployed to it.
+	for k, v := range m.Tags() {
+		userTags[k] = v
+	}
 
 	cfg, err := p.st.ModelConfig()
 	if err != nil {
@@ -202,7 +303,13 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	machineTags := instancecfg.InstanceTags(cfg.UUID(), controllerCfg.ControllerUUID(), cfg, jobs)
+	machineTags := instancecfg.InstanceTags(cfg.UUID(), controllerCfg.ControllerUUID(), m.Id(), cfg, jobs)
+	// User-defined tags never carry the juju- prefix, so they cannot
+	// replace juju's own tags; they do override the model's
+	// resource-tags.
+	for k, v := range userTags {
+		machineTags[k] = v
+	}
 	if len(unitNames) > 0 {
 		machineTags[tags.JujuUnitsDeployed] = strings.Join(unitNames, " ")
 	}
@@ -329,6 +436,93 @@ func (p *ProvisionerAPI) machineEndpointBindings(m *state.Machine) (map[string]s
 	return combinedBindings, nil
 }
 
+// machineNetworkBindings returns the network interfaces, beyond its
+// primary one, that the machine needs to reach every space included in
+// its constraints or bound to an endpoint of its principal units. The
+// primary interface, which the provider configures, reaches the first
+// space; each other space is reached by configuring the next interface
+// using DHCP on the first of the space's subnets.
+func (p *ProvisionerAPI) machineNetworkBindings(m *state.Machine) ([]params.NetworkBinding, error) {
+	mcons, err := m.Constraints()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get machine constraints")
+	}
+	spaceNames := mcons.IncludeSpaces()
+	boundSpaceNames, err := p.machineBoundSpaceNames(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seen := set.NewStrings(spaceNames...)
+	for _, name := range boundSpaceNames.SortedValues() {
+		if !seen.Contains(name) {
+			spaceNames = append(spaceNames, name)
+			seen.Add(name)
+		}
+	}
+	if len(spaceNames) < 2 {
+		return nil, nil
+	}
+	var bindings []params.NetworkBinding
+	for _, name := range spaceNames[1:] {
+		space, err := p.st.Space(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		subnets, err := space.Subnets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var cidrs []string
+		for _, subnet := range subnets {
+			if subnet.CIDR() != "" {
+				cidrs = append(cidrs, subnet.CIDR())
+			}
+		}
+		if len(cidrs) == 0 {
+			logger.Warningf("not configuring an interface in space %q for machine %q: no subnets", name, m.Id())
+			continue
+		}
+		sort.Strings(cidrs)
+		bindings = append(bindings, params.NetworkBinding{
+			InterfaceName: fmt.Sprintf("eth%d", len(bindings)+1),
+			Space:         name,
+			SubnetCIDR:    cidrs[0],
+		})
+	}
+	return bindings, nil
+}
+
+// machineBoundSpaceNames returns the names of the spaces bound to
+// endpoints of the applications of the machine's principal units.
+func (p *ProvisionerAPI) machineBoundSpaceNames(m *state.Machine) (set.Strings, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaceNames := set.NewStrings()
+	processedServicesSet := set.NewStrings()
+	for _, unit := range units {
+		if !unit.IsPrincipal() || processedServicesSet.Contains(unit.ApplicationName()) {
+			continue
+		}
+		service, err := unit.Application()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		processedServicesSet.Add(service.Name())
+		bindings, err := service.EndpointBindings()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, spaceName := range bindings {
+			if spaceName != "" {
+				spaceNames.Add(spaceName)
+			}
+		}
+	}
+	return spaceNames, nil
+}
+
 func (p *ProvisionerAPI) allSpaceNamesToProviderIds() (map[string]string, error) {
 	allSpaces, err := p.st.AllSpaces()
 	if err != nil {
@@ -355,6 +549,14 @@ func (p *ProvisionerAPI) allSpaceNamesToProviderIds() (map[string]string, error)
 // availableImageMetadata returns all image metadata available to this machine
 // or an error fetching them.
 func (p *ProvisionerAPI) availableImageMetadata(m *state.Machine) ([]params.CloudImageMetadata, error) {
+	mcons, err := m.Constraints()
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get machine constraints for machine %v", m.MachineTag().Id())
+	}
+	if mcons.HasImageID() {
+		return p.pinnedImageMetadata(m, mcons)
+	}
+
 	imageConstraint, env, err := p.constructImageConstraint(m)
 	if err != nil {
 		return nil, errors.Annotate(err, "could not construct image constraint")
@@ -365,11 +567,70 @@ func (p *ProvisionerAPI) availableImageMetadata(m *state.Machine) ([]params.Clou
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if mcons.HasVirtType() {
+		data = filterImageMetadataByVirtType(data, *mcons.VirtType)
+	}
 	sort.Sort(metadataList(data))
 	logger.Debugf("available image metadata for provisioning: %v", data)
 	return data, nil
 }
 
+// filterImageMetadataByVirtType returns the image metadata whose
+// virtualisation type matches the given one. Metadata that does not
+// specify a virtualisation type is kept, as the image may still be
+// suitable.
+func filterImageMetadataByVirtType(all []params.CloudImageMetadata, virtType string) []params.CloudImageMetadata {
+	var result []params.CloudImageMetadata
+	for _, m := range all {
+		if m.VirtType == "" || m.VirtType == virtType {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// pinnedImageMetadata returns metadata for the image named by the
+// machine's image-id constraint. No image metadata is consulted, so
+// the image is assumed to suit the machine's series and architecture.
+func (p *ProvisionerAPI) pinnedImageMetadata(m *state.Machine, cons constraints.Value) ([]params.CloudImageMetadata, error) {
+	cloud, env, err := p.obtainEnvCloudConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cloud == nil {
+		// We only support specifying image IDs for providers
+		// that use simplestreams.
+		return nil, errors.NotSupportedf("specifying image-id for %q provider", env.Config().Type())
+	}
+	seriesVersion, err := series.SeriesVersion(m.Series())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Without an arch constraint there is no way of knowing the
+	// image's architecture, so assume the most common one.
+	imageArch := arch.AMD64
+	if cons.HasArch() {
+		imageArch = cons.Arches()[0]
+	}
+	// The metadata does not have information about the storage
+	// type. Any provider that wants to filter on it should allow
+	// for an empty value.
+	metadata := params.CloudImageMetadata{
+		ImageId: *cons.ImageID,
+		Stream:  env.Config().ImageStream(),
+		Region:  cloud.Region,
+		Version: seriesVersion,
+		Series:  m.Series(),
+		Arch:    imageArch,
+		Source:  params.ImageSourcePinned,
+	}
+	if cons.HasVirtType() {
+		metadata.VirtType = *cons.VirtType
+	}
+	logger.Debugf("using pinned image %q for machine %v", metadata.ImageId, m.Id())
+	return []params.CloudImageMetadata{metadata}, nil
+}
+
 // constructImageConstraint returns model-specific criteria used to look for image metadata.
 func (p *ProvisionerAPI) constructImageConstraint(m *state.Machine) (*imagemetadata.ImageConstraint, environs.Environ, error) {
 	// If we can determine current region,
@@ -389,8 +650,8 @@ func (p *ProvisionerAPI) constructImageConstraint(m *state.Machine) (*imagemetad
 		return nil, nil, errors.Annotatef(err, "cannot get machine constraints for machine %v", m.MachineTag().Id())
 	}
 
-	if mcons.Arch != nil {
-		lookup.Arches = []string{*mcons.Arch}
+	if mcons.HasArch() {
+		lookup.Arches = mcons.Arches()
 	}
 	if cloud != nil {
 		lookup.CloudSpec = *cloud
@@ -498,9 +759,14 @@ func (p *ProvisionerAPI) imageMetadataFromDataSources(env environs.Environ, cons
 	}
 
 	cfg := env.Config()
+	model, err := p.st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	toModel := func(m *imagemetadata.ImageMetadata, mSeries string, source string, priority int) cloudimagemetadata.Metadata {
 		result := cloudimagemetadata.Metadata{
 			MetadataAttributes: cloudimagemetadata.MetadataAttributes{
+				Cloud:           model.Cloud(),
 				Region:          m.RegionName,
 				Arch:            m.Arch,
 				VirtType:        m.VirtType,
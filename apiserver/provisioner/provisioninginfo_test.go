@@ -5,8 +5,11 @@ package provisioner_test
 
 import (
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/provisioner"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
@@ -18,6 +21,7 @@ import (
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/storage/poolmanager"
 	coretesting "github.com/juju/juju/testing"
+	jujuversion "github.com/juju/juju/version"
 )
 
 func (s *withoutControllerSuite) TestProvisioningInfoWithStorage(c *gc.C) {
@@ -58,6 +62,7 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithStorage(c *gc.C) {
 				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
+					tags.JujuMachine:    s.machines[0].Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
 				},
 			}},
@@ -69,6 +74,7 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithStorage(c *gc.C) {
 				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
+					tags.JujuMachine:    placementMachine.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
 				},
 				Volumes: []params.VolumeParams{{
@@ -113,6 +119,39 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithStorage(c *gc.C) {
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoWithResourceTags(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetTags(map[string]string{"team": "web"})
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err = application.SetTags(map[string]string{"team": "blog", "purpose": "frontend"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := application.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: machine.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+
+	// Tags set on the machine take precedence over those set on the
+	// applications deployed to it.
+	c.Assert(result.Results[0].Result.Tags, jc.DeepEquals, map[string]string{
+		tags.JujuController:    coretesting.ControllerTag.Id(),
+		tags.JujuModel:         coretesting.ModelTag.Id(),
+		tags.JujuMachine:       machine.Id(),
+		tags.JujuUnitsDeployed: unit.Name(),
+		"team":                 "web",
+		"purpose":              "frontend",
+	})
+}
+
 func (s *withoutControllerSuite) TestProvisioningInfoWithSingleNegativeAndPositiveSpaceInConstraints(c *gc.C) {
 	s.addSpacesAndSubnets(c)
 
@@ -146,6 +185,7 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithSingleNegativeAndPositi
 				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
+					tags.JujuMachine:    placementMachine.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
 				},
 				SubnetsToZones: map[string][]string{
@@ -214,6 +254,7 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.
 				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController:    coretesting.ControllerTag.Id(),
+					tags.JujuMachine:       wordpressMachine.Id(),
 					tags.JujuModel:         coretesting.ModelTag.Id(),
 					tags.JujuUnitsDeployed: wordpressUnit.Name(),
 				},
@@ -224,11 +265,55 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.
 					"url": "first space id", // has provider ID
 					// We expect none of the unspecified bindings in the result.
 				},
+				// The primary interface reaches space1, so another
+				// is needed for space2.
+				NetworkBindings: []params.NetworkBinding{{
+					InterfaceName: "eth1",
+					Space:         "space2",
+					SubnetCIDR:    "10.10.1.0/24",
+				}},
 			},
 		}}}
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoNetworkBindings(c *gc.C) {
+	s.addSpacesAndSubnets(c)
+	_, err := s.State.AddSpace("space3", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The space in the constraints is reached by the primary
+	// interface, and the bound spaces by further interfaces, with
+	// space3 skipped as it has no subnets.
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("spaces=space2"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wordpressCharm := s.AddTestingCharm(c, "wordpress")
+	wordpressService := s.AddTestingServiceWithBindings(c, "wordpress", wordpressCharm, map[string]string{
+		"url": "space1",
+		"db":  "space3",
+	})
+	wordpressUnit, err := wordpressService.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpressUnit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.provisioner.ProvisioningInfo(params.Entities{Entities: []params.Entity{
+		{Tag: machine.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.NetworkBindings, jc.DeepEquals, []params.NetworkBinding{{
+		InterfaceName: "eth1",
+		Space:         "space1",
+		SubnetCIDR:    "10.10.0.0/24",
+	}})
+}
+
 func (s *withoutControllerSuite) TestProvisioningInfoWithUnsuitableSpacesConstraints(c *gc.C) {
 	// Add an empty space.
 	_, err := s.State.AddSpace("empty", "", nil, true)
@@ -302,6 +387,7 @@ func (s *withoutControllerSuite) TestStorageProviderFallbackToType(c *gc.C) {
 				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
+					tags.JujuMachine:    placementMachine.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
 				},
 				Volumes: []params.VolumeParams{{
@@ -355,6 +441,7 @@ func (s *withoutControllerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
+					tags.JujuMachine:    s.machines[0].Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
 				},
 			}},
@@ -365,3 +452,59 @@ func (s *withoutControllerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 		},
 	})
 }
+
+func (s *withoutControllerSuite) TestBulkProvisioningInfo(c *gc.C) {
+	args := params.BulkProvisioningInfoArgs{
+		Entities: []params.Entity{
+			{Tag: s.machines[0].Tag().String()},
+			{Tag: "machine-42"},
+			{Tag: "application-bar"},
+		},
+		AgentVersion: jujuversion.Current,
+		Arch:         arch.HostArch(),
+	}
+	result, err := s.provisioner.BulkProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+
+	info, err := s.provisioner.ProvisioningInfo(params.Entities{
+		Entities: []params.Entity{{Tag: s.machines[0].Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Results[0].Error, gc.IsNil)
+	c.Check(result.Results[0].Result, jc.DeepEquals, info.Results[0].Result)
+	c.Check(result.Results[0].ToolsError, gc.IsNil)
+	c.Assert(result.Results[0].Tools, gc.Not(gc.HasLen), 0)
+	for _, tools := range result.Results[0].Tools {
+		c.Check(tools.Version.Number, gc.Equals, jujuversion.Current)
+		c.Check(tools.Version.Series, gc.Equals, "quantal")
+		c.Check(tools.Version.Arch, gc.Equals, arch.HostArch())
+	}
+
+	c.Check(result.Results[1], jc.DeepEquals, params.BulkProvisioningInfoResult{
+		Error: apiservertesting.NotFoundError("machine 42"),
+	})
+	c.Check(result.Results[2], jc.DeepEquals, params.BulkProvisioningInfoResult{
+		Error: apiservertesting.ErrUnauthorized,
+	})
+}
+
+func (s *withoutControllerSuite) TestVersion3Translation(c *gc.C) {
+	translations, err := common.Facades.GetTranslations("Provisioner", 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(translations, jc.DeepEquals, []facade.Translation{{
+		Omit: []string{"BulkProvisioningInfo"},
+	}})
+}
+
+func (s *withoutControllerSuite) TestBulkProvisioningInfoWithoutTools(c *gc.C) {
+	result, err := s.provisioner.BulkProvisioningInfo(params.BulkProvisioningInfoArgs{
+		Entities: []params.Entity{{Tag: s.machines[0].Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Check(result.Results[0].Error, gc.IsNil)
+	c.Check(result.Results[0].Result, gc.NotNil)
+	c.Check(result.Results[0].Tools, gc.HasLen, 0)
+	c.Check(result.Results[0].ToolsError, gc.IsNil)
+}
@@ -109,9 +109,9 @@ func (s *ProxyUpdaterSuite) TestProxyConfig(c *gc.C) {
 
 	r := params.ProxyConfigResult{
 		ProxySettings: params.ProxyConfig{
-			HTTP: "http proxy", HTTPS: "https proxy", FTP: "", NoProxy: noProxy},
+			HTTP: "http-proxy", HTTPS: "https-proxy", FTP: "", NoProxy: noProxy},
 		APTProxySettings: params.ProxyConfig{
-			HTTP: "http://http proxy", HTTPS: "https://https proxy", FTP: "", NoProxy: ""},
+			HTTP: "http://http-proxy", HTTPS: "https://https-proxy", FTP: "", NoProxy: ""},
 	}
 	c.Assert(cfg.Results[0], jc.DeepEquals, r)
 }
@@ -119,8 +119,8 @@ func (s *ProxyUpdaterSuite) TestProxyConfig(c *gc.C) {
 func (s *ProxyUpdaterSuite) TestProxyConfigExtendsExisting(c *gc.C) {
 	// Check that the ProxyConfig combines data from ModelConfig and APIHostPorts
 	s.state.SetModelConfig(coretesting.Attrs{
-		"http-proxy":  "http proxy",
-		"https-proxy": "https proxy",
+		"http-proxy":  "http-proxy",
+		"https-proxy": "https-proxy",
 		"no-proxy":    "9.9.9.9",
 	})
 	cfg := s.facade.ProxyConfig(s.oneEntity())
@@ -133,17 +133,17 @@ func (s *ProxyUpdaterSuite) TestProxyConfigExtendsExisting(c *gc.C) {
 
 	c.Assert(cfg.Results[0], jc.DeepEquals, params.ProxyConfigResult{
 		ProxySettings: params.ProxyConfig{
-			HTTP: "http proxy", HTTPS: "https proxy", FTP: "", NoProxy: expectedNoProxy},
+			HTTP: "http-proxy", HTTPS: "https-proxy", FTP: "", NoProxy: expectedNoProxy},
 		APTProxySettings: params.ProxyConfig{
-			HTTP: "http://http proxy", HTTPS: "https://https proxy", FTP: "", NoProxy: ""},
+			HTTP: "http://http-proxy", HTTPS: "https://https-proxy", FTP: "", NoProxy: ""},
 	})
 }
 
 func (s *ProxyUpdaterSuite) TestProxyConfigNoDuplicates(c *gc.C) {
 	// Check that the ProxyConfig combines data from ModelConfig and APIHostPorts
 	s.state.SetModelConfig(coretesting.Attrs{
-		"http-proxy":  "http proxy",
-		"https-proxy": "https proxy",
+		"http-proxy":  "http-proxy",
+		"https-proxy": "https-proxy",
 		"no-proxy":    "0.1.2.3",
 	})
 	cfg := s.facade.ProxyConfig(s.oneEntity())
@@ -156,9 +156,9 @@ func (s *ProxyUpdaterSuite) TestProxyConfigNoDuplicates(c *gc.C) {
 
 	c.Assert(cfg.Results[0], jc.DeepEquals, params.ProxyConfigResult{
 		ProxySettings: params.ProxyConfig{
-			HTTP: "http proxy", HTTPS: "https proxy", FTP: "", NoProxy: expectedNoProxy},
+			HTTP: "http-proxy", HTTPS: "https-proxy", FTP: "", NoProxy: expectedNoProxy},
 		APTProxySettings: params.ProxyConfig{
-			HTTP: "http://http proxy", HTTPS: "https://https proxy", FTP: "", NoProxy: ""},
+			HTTP: "http://http-proxy", HTTPS: "https://https-proxy", FTP: "", NoProxy: ""},
 	})
 }
 
@@ -176,8 +176,8 @@ func (sb *stubBackend) SetUp(c *gc.C) {
 	sb.Stub = &testing.Stub{}
 	sb.c = c
 	sb.configAttrs = coretesting.Attrs{
-		"http-proxy":  "http proxy",
-		"https-proxy": "https proxy",
+		"http-proxy":  "http-proxy",
+		"https-proxy": "https-proxy",
 	}
 	sb.hpWatcher = workertest.NewFakeWatcher(1, 1)
 	sb.confWatcher = workertest.NewFakeWatcher(1, 1)
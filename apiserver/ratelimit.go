@@ -0,0 +1,148 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// RateLimitConfig holds the limits on the rate of API requests made
+// over a single connection. A zero limit means that the rate is not
+// limited.
+type RateLimitConfig struct {
+	// PerConnection is the maximum sustained number of requests
+	// per second accepted over the connection.
+	PerConnection int
+
+	// PerFacade is the maximum sustained number of requests per
+	// second accepted for any one facade over the connection.
+	PerFacade int
+}
+
+// enabled reports whether any limit is set.
+func (c RateLimitConfig) enabled() bool {
+	return c.PerConnection > 0 || c.PerFacade > 0
+}
+
+// rateLimitExemptFacades holds the names of the facades that agents
+// rely on to stay connected and healthy, and of the watcher facades.
+// Requests to them are never rate limited, so that a busy agent cannot
+// be cut off by its own traffic to other facades, and so that a
+// rejected Next call cannot make a client miss watcher events.
+var rateLimitExemptFacades = set.NewStrings(
+	"Agent",
+	"LeadershipService",
+	"MigrationMinion",
+	"Pinger",
+	"Singular",
+	"Upgrader",
+
+	"AllModelWatcher",
+	"AllWatcher",
+	"APIHostPortsWatcher",
+	"EntityWatcher",
+	"FilesystemAttachmentsWatcher",
+	"MigrationStatusWatcher",
+	"NotifyWatcher",
+	"RelationUnitsWatcher",
+	"StringsWatcher",
+	"VolumeAttachmentsWatcher",
+)
+
+// requestRateLimiter limits the rate of the requests made over a single
+// API connection, both in total and to each facade.
+type requestRateLimiter struct {
+	clock  clock.Clock
+	config RateLimitConfig
+
+	mu      sync.Mutex
+	conn    *tokenBucket
+	facades map[string]*tokenBucket
+}
+
+func newRequestRateLimiter(config RateLimitConfig, clock clock.Clock) *requestRateLimiter {
+	l := &requestRateLimiter{
+		clock:   clock,
+		config:  config,
+		facades: make(map[string]*tokenBucket),
+	}
+	if config.PerConnection > 0 {
+		l.conn = newTokenBucket(config.PerConnection, clock.Now())
+	}
+	return l
+}
+
+// check is suitable for passing to restrictRoot. It returns an error
+// satisfying params.IsCodeTryAgain if the request would exceed a limit.
+func (l *requestRateLimiter) check(facadeName, methodName string) error {
+	if rateLimitExemptFacades.Contains(facadeName) {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	var facade *tokenBucket
+	if l.config.PerFacade > 0 {
+		facade = l.facades[facadeName]
+		if facade == nil {
+			facade = newTokenBucket(l.config.PerFacade, now)
+			l.facades[facadeName] = facade
+		}
+	}
+	// Only take tokens once both limits are known to allow the
+	// request, so that rejected requests count against neither.
+	if !l.conn.available(now) || !facade.available(now) {
+		logger.Debugf("rate limiting request to %s.%s", facadeName, methodName)
+		return common.ErrRateLimitExceeded
+	}
+	l.conn.take()
+	facade.take()
+	return nil
+}
+
+// tokenBucket allows a sustained number of events per second, with
+// bursts of up to a second's worth. A nil *tokenBucket allows every
+// event.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   now,
+	}
+}
+
+// available refills the bucket for the time elapsed since it was last
+// refilled, and reports whether it holds a token.
+func (b *tokenBucket) available(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+		b.last = now
+	}
+	return b.tokens >= 1
+}
+
+// take removes a token from the bucket.
+func (b *tokenBucket) take() {
+	if b != nil {
+		b.tokens--
+	}
+}
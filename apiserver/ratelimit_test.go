@@ -0,0 +1,111 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type rateLimitSuite struct {
+	testing.BaseSuite
+	clock *jujutesting.Clock
+}
+
+var _ = gc.Suite(&rateLimitSuite{})
+
+func (s *rateLimitSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = jujutesting.NewClock(time.Now())
+}
+
+func (s *rateLimitSuite) assertAllowed(c *gc.C, root rpc.Root, facade string, version int, method string) {
+	caller, err := root.FindMethod(facade, version, method)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (s *rateLimitSuite) assertLimited(c *gc.C, root rpc.Root, facade string, version int, method string) {
+	_, err := root.FindMethod(facade, version, method)
+	c.Assert(err, gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeTryAgain)
+}
+
+func (s *rateLimitSuite) TestPerConnection(c *gc.C) {
+	root := apiserver.TestingRateLimitedRoot(apiserver.RateLimitConfig{PerConnection: 2}, s.clock)
+	s.assertAllowed(c, root, "Client", 1, "FullStatus")
+	s.assertAllowed(c, root, "Client", 1, "WatchAll")
+	s.assertLimited(c, root, "Client", 1, "FullStatus")
+
+	s.clock.Advance(500 * time.Millisecond)
+	s.assertAllowed(c, root, "Client", 1, "FullStatus")
+	s.assertLimited(c, root, "Client", 1, "FullStatus")
+}
+
+func (s *rateLimitSuite) TestPerFacade(c *gc.C) {
+	root := apiserver.TestingRateLimitedRoot(apiserver.RateLimitConfig{PerFacade: 1}, s.clock)
+	s.assertAllowed(c, root, "Client", 1, "FullStatus")
+	s.assertLimited(c, root, "Client", 1, "FullStatus")
+
+	// Other facades have their own limits.
+	s.assertAllowed(c, root, "ModelConfig", 2, "ModelGet")
+
+	s.clock.Advance(time.Second)
+	s.assertAllowed(c, root, "Client", 1, "FullStatus")
+}
+
+func (s *rateLimitSuite) TestRejectedRequestsNotCounted(c *gc.C) {
+	root := apiserver.TestingRateLimitedRoot(apiserver.RateLimitConfig{
+		PerConnection: 2,
+		PerFacade:     1,
+	}, s.clock)
+	s.assertAllowed(c, root, "Client", 1, "FullStatus")
+	s.assertLimited(c, root, "Client", 1, "FullStatus")
+	s.assertLimited(c, root, "Client", 1, "FullStatus")
+
+	// The rejected requests did not use up the connection's limit.
+	s.assertAllowed(c, root, "ModelConfig", 2, "ModelGet")
+}
+
+func (s *rateLimitSuite) TestExemptFacades(c *gc.C) {
+	root := apiserver.TestingRateLimitedRoot(apiserver.RateLimitConfig{PerConnection: 1}, s.clock)
+	s.assertAllowed(c, root, "Client", 1, "FullStatus")
+	s.assertLimited(c, root, "Client", 1, "FullStatus")
+	for i := 0; i < 5; i++ {
+		s.assertAllowed(c, root, "Pinger", 1, "Ping")
+	}
+}
+
+func (s *rateLimitSuite) TestExemptWatcherFacades(c *gc.C) {
+	root := apiserver.TestingRateLimitedRoot(apiserver.RateLimitConfig{
+		PerConnection: 1,
+		PerFacade:     1,
+	}, s.clock)
+	s.assertAllowed(c, root, "Client", 1, "FullStatus")
+	s.assertLimited(c, root, "Client", 1, "FullStatus")
+	for _, facade := range []struct {
+		name    string
+		version int
+	}{
+		{"AllWatcher", 1},
+		{"NotifyWatcher", 1},
+		{"StringsWatcher", 1},
+		{"RelationUnitsWatcher", 1},
+		{"EntityWatcher", 2},
+		{"MigrationStatusWatcher", 1},
+	} {
+		for i := 0; i < 3; i++ {
+			s.assertAllowed(c, root, facade.name, facade.version, "Next")
+		}
+	}
+}
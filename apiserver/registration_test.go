@@ -140,8 +140,8 @@ func (s *registrationSuite) TestRegisterInvalidUserTag(c *gc.C) {
 
 func (s *registrationSuite) TestRegisterInvalidNonce(c *gc.C) {
 	s.testInvalidRequest(
-		c, `{"user": "user-bob", "nonce": ""}`, `nonce not valid`, "",
-		http.StatusInternalServerError,
+		c, `{"user": "user-bob", "nonce": ""}`, `nonce not valid`, params.CodeNotValid,
+		http.StatusBadRequest,
 	)
 }
 
@@ -151,8 +151,8 @@ func (s *registrationSuite) TestRegisterInvalidCiphertext(c *gc.C) {
 		fmt.Sprintf(
 			`{"user": "user-bob", "nonce": "%s"}`,
 			base64.StdEncoding.EncodeToString(validNonce),
-		), `secret key not valid`, "",
-		http.StatusInternalServerError,
+		), `secret key not valid`, params.CodeNotValid,
+		http.StatusBadRequest,
 	)
 }
 
@@ -107,7 +107,7 @@ func (s *resourcesUploadSuite) TestPOSTRequiresUserAuth(c *gc.C) {
 		nonce:       "noncy",
 		contentType: "foo/bar",
 	})
-	s.assertErrorResponse(c, resp, http.StatusInternalServerError, ".*tag kind machine not valid$")
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, ".*tag kind machine not valid$")
 
 	// Now try a user login.
 	resp = s.authRequest(c, httpRequestParams{method: "POST", url: s.resourcesURI(c, "")})
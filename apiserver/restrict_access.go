@@ -0,0 +1,46 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/permission"
+)
+
+// methodAccessChecker returns a function suitable for use with
+// restrictRoot which rejects calls to facade methods when the
+// authorized user lacks the access recorded for them in registry.
+// Methods with no recorded access are allowed.
+func methodAccessChecker(
+	registry *facade.Registry,
+	authorizer facade.Authorizer,
+	controllerTag names.ControllerTag,
+	modelTag names.ModelTag,
+) func(facadeName, methodName string) error {
+	return func(facadeName, methodName string) error {
+		access, ok := registry.RequiredAccess(facadeName, methodName)
+		if !ok {
+			return nil
+		}
+		// Controller superusers may call any method.
+		allowed, err := authorizer.HasPermission(permission.SuperuserAccess, controllerTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !allowed && access != permission.SuperuserAccess {
+			allowed, err = authorizer.HasPermission(access, modelTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if !allowed {
+			return common.ErrPerm
+		}
+		return nil
+	}
+}
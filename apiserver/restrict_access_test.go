@@ -0,0 +1,76 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type restrictAccessSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restrictAccessSuite{})
+
+func (s *restrictAccessSuite) rootFor(user string) rpc.Root {
+	return apiserver.TestingMethodAccessRoot(
+		apiservertesting.FakeAuthorizer{Tag: names.NewUserTag(user)},
+		testing.ControllerTag,
+		testing.ModelTag,
+	)
+}
+
+func (s *restrictAccessSuite) assertAllowed(c *gc.C, root rpc.Root, facade string, version int, method string) {
+	caller, err := root.FindMethod(facade, version, method)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (s *restrictAccessSuite) assertDenied(c *gc.C, root rpc.Root, facade string, version int, method string) {
+	caller, err := root.FindMethod(facade, version, method)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (s *restrictAccessSuite) TestSuperuserMethods(c *gc.C) {
+	s.assertAllowed(c, s.rootFor("superuser"), "ImageMetadata", 3, "Save")
+	s.assertDenied(c, s.rootFor("write"), "ImageMetadata", 3, "Save")
+	s.assertDenied(c, s.rootFor("read"), "ImageMetadata", 3, "Delete")
+}
+
+func (s *restrictAccessSuite) TestModelAccessMethods(c *gc.C) {
+	s.assertAllowed(c, s.rootFor("read"), "Payloads", 1, "List")
+	s.assertAllowed(c, s.rootFor("superuser"), "Payloads", 1, "List")
+	s.assertDenied(c, s.rootFor("nobody"), "Payloads", 1, "List")
+}
+
+func (s *restrictAccessSuite) TestWriteMethods(c *gc.C) {
+	s.assertAllowed(c, s.rootFor("write"), "Application", 4, "Deploy")
+	s.assertDenied(c, s.rootFor("read"), "Application", 4, "Deploy")
+	s.assertDenied(c, s.rootFor("read"), "Client", 1, "AddMachines")
+	s.assertDenied(c, s.rootFor("read"), "ModelConfig", 3, "ModelSet")
+	s.assertAllowed(c, s.rootFor("read"), "ModelConfig", 3, "ModelGet")
+}
+
+func (s *restrictAccessSuite) TestAdminMethods(c *gc.C) {
+	s.assertAllowed(c, s.rootFor("admin"), "Action", 2, "Run")
+	s.assertDenied(c, s.rootFor("write"), "Action", 2, "Run")
+	s.assertAllowed(c, s.rootFor("write"), "Action", 2, "Enqueue")
+	s.assertDenied(c, s.rootFor("write"), "KeyManager", 1, "AddKeys")
+}
+
+func (s *restrictAccessSuite) TestUndeclaredMethodsAllowed(c *gc.C) {
+	s.assertAllowed(c, s.rootFor("read"), "ImageMetadata", 3, "List")
+	s.assertAllowed(c, s.rootFor("nobody"), "Client", 1, "FullStatus")
+}
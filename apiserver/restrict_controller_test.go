@@ -30,8 +30,9 @@ func (s *restrictControllerSuite) SetUpSuite(c *gc.C) {
 func (s *restrictControllerSuite) TestAllowed(c *gc.C) {
 	s.assertMethod(c, "AllModelWatcher", 2, "Next")
 	s.assertMethod(c, "AllModelWatcher", 2, "Stop")
-	s.assertMethod(c, "ModelManager", 2, "CreateModel")
-	s.assertMethod(c, "ModelManager", 2, "ListModels")
+	s.assertMethod(c, "ModelManager", 3, "CreateModel")
+	s.assertMethod(c, "ModelManager", 3, "ListModels")
+	s.assertMethod(c, "MongoDebug", 1, "SessionStats")
 	s.assertMethod(c, "Pinger", 1, "Ping")
 	s.assertMethod(c, "Bundle", 1, "GetChanges")
 	s.assertMethod(c, "HighAvailability", 2, "EnableHA")
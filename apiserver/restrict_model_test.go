@@ -32,7 +32,7 @@ func (s *restrictModelSuite) TestAllowed(c *gc.C) {
 }
 
 func (s *restrictModelSuite) TestBlocked(c *gc.C) {
-	caller, err := s.root.FindMethod("ModelManager", 2, "ListModels")
+	caller, err := s.root.FindMethod("ModelManager", 3, "ListModels")
 	c.Assert(err, gc.ErrorMatches, `facade "ModelManager" not supported for model API connection`)
 	c.Assert(errors.IsNotSupported(err), jc.IsTrue)
 	c.Assert(caller, gc.IsNil)
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/version"
+	"golang.org/x/net/context"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -58,18 +60,22 @@ type apiHandler struct {
 	// serverHost is the host:port of the API server that the client
 	// connected to.
 	serverHost string
+
+	// remoteAddr is the address of the client.
+	remoteAddr string
 }
 
 var _ = (*apiHandler)(nil)
 
 // newAPIHandler returns a new apiHandler.
-func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID string, serverHost string) (*apiHandler, error) {
+func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID, serverHost, remoteAddr string) (*apiHandler, error) {
 	r := &apiHandler{
 		state:      st,
 		resources:  common.NewResources(),
 		rpcConn:    rpcConn,
 		modelUUID:  modelUUID,
 		serverHost: serverHost,
+		remoteAddr: remoteAddr,
 	}
 	if err := r.resources.RegisterNamed("machineID", common.StringResource(srv.tag.Id())); err != nil {
 		return nil, errors.Trace(err)
@@ -121,12 +127,12 @@ func (s *srvCaller) ResultType() reflect.Type {
 
 // Call takes the object Id and an instance of ParamsType to create an object and place
 // a call on its method. It then returns an instance of ResultType.
-func (s *srvCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+func (s *srvCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
 	objVal, err := s.creator(objId)
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	return s.objMethod.Call(objVal, arg)
+	return s.objMethod.Call(ctx, objVal, arg)
 }
 
 // apiRoot implements basic method dispatching to the facade registry.
@@ -287,6 +293,9 @@ func lookupMethod(rootName string, version int, methodName string) (reflect.Type
 	}
 	rpcType := rpcreflect.ObjTypeOf(goType)
 	objMethod, err := rpcType.Method(methodName)
+	if err == nil {
+		objMethod, err = translateMethod(rootName, version, methodName, objMethod)
+	}
 	if err != nil {
 		if err == rpcreflect.ErrMethodNotFound {
 			return nil, noMethod, &rpcreflect.CallNotImplementedError{
@@ -300,6 +309,16 @@ func lookupMethod(rootName string, version int, methodName string) (reflect.Type
 	return goType, objMethod, nil
 }
 
+// translateMethod returns the method to call for the given facade
+// version, translating calls if that version is served by a newer one.
+func translateMethod(rootName string, version int, methodName string, objMethod rpcreflect.ObjMethod) (rpcreflect.ObjMethod, error) {
+	translations, err := common.Facades.GetTranslations(rootName, version)
+	if err != nil || len(translations) == 0 {
+		return objMethod, err
+	}
+	return facade.TranslateMethod(translations, methodName, objMethod)
+}
+
 // AnonRoot dispatches API calls to those available to an anonymous connection
 // which has not logged in.
 type anonRoot struct {
@@ -398,6 +417,20 @@ func DescribeFacades() []params.FacadeVersions {
 	for i, facade := range facades {
 		result[i].Name = facade.Name
 		result[i].Versions = facade.Versions
+		for _, v := range facade.Versions {
+			d, ok := facade.Deprecated[v]
+			if !ok {
+				continue
+			}
+			deprecation := params.FacadeDeprecation{
+				Version: v,
+				Since:   d.Since.String(),
+			}
+			if d.Sunset != version.Zero {
+				deprecation.Sunset = d.Sunset.String()
+			}
+			result[i].Deprecated = append(result[i].Deprecated, deprecation)
+		}
 	}
 	return result
 }
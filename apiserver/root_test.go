@@ -11,12 +11,15 @@ import (
 
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -164,20 +167,20 @@ func (r *rootSuite) TestFindMethodEnsuresTypeMatch(c *gc.C) {
 	// fine
 	caller, err := srvRoot.FindMethod("my-testing-facade", 1, "Exposed")
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = caller.Call("", reflect.Value{})
+	_, err = caller.Call(context.Background(), "", reflect.Value{})
 	c.Check(err, gc.ErrorMatches, "Exposed was bogus")
 	// However, myBadFacade returns the wrong type, so trying to access it
 	// should create an error
 	caller, err = srvRoot.FindMethod("my-testing-facade", 0, "Exposed")
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = caller.Call("", reflect.Value{})
+	_, err = caller.Call(context.Background(), "", reflect.Value{})
 	c.Check(err, gc.ErrorMatches,
 		`internal error, my-testing-facade\(0\) claimed to return \*apiserver_test.testingType but returned \*apiserver_test.badType`)
 	// myErrFacade had the permissions change, so calling it returns an
 	// error, but that shouldn't trigger the type checking code.
 	caller, err = srvRoot.FindMethod("my-testing-facade", 2, "Exposed")
 	c.Assert(err, jc.ErrorIsNil)
-	res, err := caller.Call("", reflect.Value{})
+	res, err := caller.Call(context.Background(), "", reflect.Value{})
 	c.Check(err, gc.ErrorMatches, `you shall not pass`)
 	c.Check(res.IsValid(), jc.IsFalse)
 }
@@ -200,7 +203,7 @@ func (ct *countingType) AltCount() stringVar {
 }
 
 func assertCallResult(c *gc.C, caller rpcreflect.MethodCaller, id string, expected string) {
-	v, err := caller.Call(id, reflect.Value{})
+	v, err := caller.Call(context.Background(), id, reflect.Value{})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(v.Interface(), gc.Equals, stringVar{expected})
 }
@@ -288,10 +291,10 @@ func (r *rootSuite) TestFindMethodCacheRaceSafe(c *gc.C) {
 	// This is designed to trigger the race detector
 	var wg sync.WaitGroup
 	wg.Add(4)
-	go func() { caller.Call("first", reflect.Value{}); wg.Done() }()
-	go func() { caller.Call("second", reflect.Value{}); wg.Done() }()
-	go func() { caller.Call("first", reflect.Value{}); wg.Done() }()
-	go func() { caller.Call("second", reflect.Value{}); wg.Done() }()
+	go func() { caller.Call(context.Background(), "first", reflect.Value{}); wg.Done() }()
+	go func() { caller.Call(context.Background(), "second", reflect.Value{}); wg.Done() }()
+	go func() { caller.Call(context.Background(), "first", reflect.Value{}); wg.Done() }()
+	go func() { caller.Call(context.Background(), "second", reflect.Value{}); wg.Done() }()
 	wg.Wait()
 	// Once we're done, we should have only instantiated 2 different
 	// objects. If we pass a different Id, we should be at 3 total count.
@@ -374,6 +377,36 @@ func (r *rootSuite) TestDescribeFacades(c *gc.C) {
 	c.Check(clientVersions[0], gc.Equals, 1)
 }
 
+func (r *rootSuite) TestDescribeFacadesDeprecated(c *gc.C) {
+	defer common.Facades.Discard("my-testing-facade", 1)
+	defer common.Facades.Discard("my-testing-facade", 2)
+	myFacade := func(facade.Context) (facade.Facade, error) {
+		return &testingType{}, nil
+	}
+	expectedType := reflect.TypeOf((*testingType)(nil))
+	common.RegisterFacade("my-testing-facade", 1, myFacade, expectedType)
+	common.RegisterFacade("my-testing-facade", 2, myFacade, expectedType)
+	common.DeprecateFacade("my-testing-facade", 1, facade.Deprecation{
+		Since: version.MustParse("2.2.0"),
+	})
+
+	for _, facade := range apiserver.DescribeFacades() {
+		if facade.Name != "my-testing-facade" {
+			continue
+		}
+		c.Check(facade, jc.DeepEquals, params.FacadeVersions{
+			Name:     "my-testing-facade",
+			Versions: []int{1, 2},
+			Deprecated: []params.FacadeDeprecation{{
+				Version: 1,
+				Since:   "2.2.0",
+			}},
+		})
+		return
+	}
+	c.Fatalf("my-testing-facade not described")
+}
+
 type stubStateEntity struct{ tag names.Tag }
 
 func (e *stubStateEntity) Tag() names.Tag { return e.tag }
@@ -0,0 +1,238 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets implements the API facade through which operators
+// manage the secrets stored in a model, and through which units read
+// the secrets they have been granted access to.
+package secrets
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Secrets", 1, newFacade)
+}
+
+// Backend contains the state.State methods used in this package.
+type Backend interface {
+	common.BlockGetter
+	ModelTag() names.ModelTag
+	AddSecret(state.SecretParams) (*state.Secret, error)
+	Secret(name string) (*state.Secret, error)
+	AllSecrets() ([]*state.Secret, error)
+}
+
+// API implements the Secrets facade.
+type API struct {
+	backend Backend
+	auth    facade.Authorizer
+	check   *common.BlockChecker
+}
+
+func newFacade(st *state.State, _ facade.Resources, auth facade.Authorizer) (*API, error) {
+	return NewAPI(st, auth)
+}
+
+// NewAPI returns a new Secrets facade. Users and unit agents may
+// connect to it.
+func NewAPI(backend Backend, auth facade.Authorizer) (*API, error) {
+	if !auth.AuthClient() && !auth.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend: backend,
+		auth:    auth,
+		check:   common.NewBlockChecker(backend),
+	}, nil
+}
+
+// checkAccess returns an error unless the caller is a user with the
+// given access to the model.
+func (api *API) checkAccess(access permission.Access) error {
+	if !api.auth.AuthClient() {
+		return common.ErrPerm
+	}
+	ok, err := api.auth.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// checkCanChange returns an error unless the caller may change the
+// model's secrets.
+func (api *API) checkCanChange() error {
+	if err := api.checkAccess(permission.AdminAccess); err != nil {
+		return errors.Trace(err)
+	}
+	return api.check.ChangeAllowed()
+}
+
+// CreateSecrets creates the given secrets. Callers must be model
+// administrators.
+func (api *API) CreateSecrets(args params.CreateSecretArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if err := api.checkCanChange(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		_, err := api.backend.AddSecret(state.SecretParams{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Data:        arg.Data,
+		})
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListSecrets describes the model's secrets, without their values.
+// Callers must have read access to the model.
+func (api *API) ListSecrets() (params.ListSecretsResults, error) {
+	var results params.ListSecretsResults
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return results, errors.Trace(err)
+	}
+	secrets, err := api.backend.AllSecrets()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	results.Results = make([]params.SecretDetails, len(secrets))
+	for i, secret := range secrets {
+		readers, err := secret.Readers()
+		if err != nil {
+			return params.ListSecretsResults{}, errors.Trace(err)
+		}
+		details := params.SecretDetails{
+			Name:        secret.Name(),
+			Description: secret.Description(),
+			Revision:    secret.Revision(),
+			Readers:     make([]string, len(readers)),
+		}
+		for j, reader := range readers {
+			details.Readers[j] = reader.String()
+		}
+		results.Results[i] = details
+	}
+	return results, nil
+}
+
+// GrantSecrets allows the given applications and units to read the
+// given secrets. Callers must be model administrators.
+func (api *API) GrantSecrets(args params.SecretAccessArgs) (params.ErrorResults, error) {
+	return api.changeAccess(args, (*state.Secret).Grant)
+}
+
+// RevokeSecrets stops the given applications and units from reading
+// the given secrets. Callers must be model administrators.
+func (api *API) RevokeSecrets(args params.SecretAccessArgs) (params.ErrorResults, error) {
+	return api.changeAccess(args, (*state.Secret).Revoke)
+}
+
+func (api *API) changeAccess(args params.SecretAccessArgs, change func(*state.Secret, names.Tag) error) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if err := api.checkCanChange(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		err := api.changeOneAccess(arg, change)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) changeOneAccess(arg params.SecretAccessArg, change func(*state.Secret, names.Tag) error) error {
+	tag, err := names.ParseTag(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	secret, err := api.backend.Secret(arg.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return change(secret, tag)
+}
+
+// RotateSecrets replaces the values of the given secrets. Callers
+// must be model administrators.
+func (api *API) RotateSecrets(args params.RotateSecretArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if err := api.checkCanChange(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		secret, err := api.backend.Secret(arg.Name)
+		if err == nil {
+			err = secret.Rotate(arg.Data)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RemoveSecrets removes the given secrets. Callers must be model
+// administrators.
+func (api *API) RemoveSecrets(args params.SecretNames) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	if err := api.checkCanChange(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, name := range args.Names {
+		secret, err := api.backend.Secret(name)
+		if err == nil {
+			err = secret.Remove()
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// GetSecretValues returns the latest values of the given secrets.
+// Unit agents may read only the secrets that they, or their
+// applications, have been granted access to; users must be model
+// administrators.
+func (api *API) GetSecretValues(args params.SecretNames) (params.SecretValueResults, error) {
+	results := params.SecretValueResults{
+		Results: make([]params.SecretValueResult, len(args.Names)),
+	}
+	isAgent := !api.auth.AuthClient()
+	if !isAgent {
+		if err := api.checkAccess(permission.AdminAccess); err != nil {
+			return results, errors.Trace(err)
+		}
+	}
+	for i, name := range args.Names {
+		secret, err := api.backend.Secret(name)
+		if isAgent && (errors.IsNotFound(err) || err == nil && !secret.CanRead(api.auth.GetAuthTag())) {
+			// Don't reveal to agents which secrets exist
+			// unless they may read them.
+			err = common.ErrPerm
+		}
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Revision = secret.Revision()
+		results.Results[i].Data = secret.Data()
+	}
+	return results, nil
+}
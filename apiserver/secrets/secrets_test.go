@@ -0,0 +1,259 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/secrets"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type secretsSuite struct {
+	jujutesting.JujuConnSuite
+
+	api  *secrets.API
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.api = s.newAPI(c, s.AdminUserTag(c))
+	s.unit = s.Factory.MakeUnit(c, nil)
+
+	results, err := s.api.CreateSecrets(params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			Name:        "db-password",
+			Description: "database password",
+			Data:        map[string]string{"password": "sekrit"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) newAPI(c *gc.C, tag names.Tag) *secrets.API {
+	api, err := secrets.NewAPI(s.State, apiservertesting.FakeAuthorizer{Tag: tag})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *secretsSuite) TestNewAPIRefusesMachineAgent(c *gc.C) {
+	_, err := secrets.NewAPI(s.State, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsSuite) TestCreateSecrets(c *gc.C) {
+	results, err := s.api.CreateSecrets(params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			Name: "api-key",
+			Data: map[string]string{"key": "abc"},
+		}, {
+			Name: "db-password",
+			Data: map[string]string{"password": "other"},
+		}, {
+			Name: "Invalid",
+			Data: map[string]string{"key": "abc"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `secret "db-password" already exists`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `cannot add secret: secret name "Invalid" not valid`)
+
+	secret, err := s.State.Secret("api-key")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Data(), jc.DeepEquals, map[string]string{"key": "abc"})
+}
+
+func (s *secretsSuite) TestCreateSecretsRequiresAdmin(c *gc.C) {
+	api := s.newAPI(c, names.NewUserTag("read"))
+	_, err := api.CreateSecrets(params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			Name: "api-key",
+			Data: map[string]string{"key": "abc"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsSuite) TestCreateSecretsBlocked(c *gc.C) {
+	err := s.State.SwitchBlockOn(state.ChangeBlock, "TestCreateSecretsBlocked")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.api.CreateSecrets(params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			Name: "api-key",
+			Data: map[string]string{"key": "abc"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "TestCreateSecretsBlocked")
+}
+
+func (s *secretsSuite) TestListSecrets(c *gc.C) {
+	results, err := s.api.GrantSecrets(params.SecretAccessArgs{
+		Args: []params.SecretAccessArg{{
+			Name: "db-password",
+			Tag:  "application-" + s.unit.ApplicationName(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	api := s.newAPI(c, names.NewUserTag("read"))
+	list, err := api.ListSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(list, jc.DeepEquals, params.ListSecretsResults{
+		Results: []params.SecretDetails{{
+			Name:        "db-password",
+			Description: "database password",
+			Revision:    1,
+			Readers:     []string{"application-" + s.unit.ApplicationName()},
+		}},
+	})
+}
+
+func (s *secretsSuite) TestListSecretsRequiresAccess(c *gc.C) {
+	api := s.newAPI(c, names.NewUserTag("nobody"))
+	_, err := api.ListSecrets()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsSuite) TestGrantSecretsErrors(c *gc.C) {
+	results, err := s.api.GrantSecrets(params.SecretAccessArgs{
+		Args: []params.SecretAccessArg{{
+			Name: "missing",
+			Tag:  s.unit.Tag().String(),
+		}, {
+			Name: "db-password",
+			Tag:  "machine-0",
+		}, {
+			Name: "db-password",
+			Tag:  "invalid",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `secret "missing" not found`)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot grant access to secret "db-password": secret reader "machine-0" not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"invalid" is not a valid tag`)
+}
+
+func (s *secretsSuite) TestGetSecretValuesAsUnit(c *gc.C) {
+	api := s.newAPI(c, s.unit.Tag())
+	args := params.SecretNames{Names: []string{"db-password", "missing"}}
+	results, err := api.GetSecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	granted, err := s.api.GrantSecrets(params.SecretAccessArgs{
+		Args: []params.SecretAccessArg{{
+			Name: "db-password",
+			Tag:  s.unit.Tag().String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(granted.OneError(), jc.ErrorIsNil)
+	rotated, err := s.api.RotateSecrets(params.RotateSecretArgs{
+		Args: []params.RotateSecretArg{{
+			Name: "db-password",
+			Data: map[string]string{"password": "new"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotated.OneError(), jc.ErrorIsNil)
+
+	results, err = api.GetSecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0], jc.DeepEquals, params.SecretValueResult{
+		Revision: 2,
+		Data:     map[string]string{"password": "new"},
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	// Units may not change secrets.
+	_, err = api.RotateSecrets(params.RotateSecretArgs{
+		Args: []params.RotateSecretArg{{
+			Name: "db-password",
+			Data: map[string]string{"password": "mine"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsSuite) TestGetSecretValuesAsUnitOfGrantedApplication(c *gc.C) {
+	other := s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "other"}),
+	})
+	granted, err := s.api.GrantSecrets(params.SecretAccessArgs{
+		Args: []params.SecretAccessArg{{
+			Name: "db-password",
+			Tag:  "application-" + s.unit.ApplicationName(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(granted.OneError(), jc.ErrorIsNil)
+
+	args := params.SecretNames{Names: []string{"db-password"}}
+	results, err := s.newAPI(c, s.unit.Tag()).GetSecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Data, jc.DeepEquals, map[string]string{"password": "sekrit"})
+
+	results, err = s.newAPI(c, other.Tag()).GetSecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsSuite) TestRevokeSecrets(c *gc.C) {
+	access := params.SecretAccessArgs{
+		Args: []params.SecretAccessArg{{
+			Name: "db-password",
+			Tag:  s.unit.Tag().String(),
+		}},
+	}
+	results, err := s.api.GrantSecrets(access)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	results, err = s.api.RevokeSecrets(access)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	secret, err := s.State.Secret("db-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.CanRead(s.unit.Tag()), jc.IsFalse)
+}
+
+func (s *secretsSuite) TestRemoveSecrets(c *gc.C) {
+	results, err := s.api.RemoveSecrets(params.SecretNames{
+		Names: []string{"db-password"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	_, err = s.State.Secret("db-password")
+	c.Assert(err, gc.ErrorMatches, `secret "db-password" not found`)
+}
+
+func (s *secretsSuite) TestGetSecretValuesAsUserRequiresAdmin(c *gc.C) {
+	args := params.SecretNames{Names: []string{"db-password"}}
+	results, err := s.api.GetSecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Data, jc.DeepEquals, map[string]string{"password": "sekrit"})
+
+	_, err = s.newAPI(c, names.NewUserTag("read")).GetSecretValues(args)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -95,6 +95,49 @@ func (s *serverSuite) TestStop(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *serverSuite) TestStopAbortsWatchersWithRetryableError(c *gc.C) {
+	info, srv := newServer(c, s.State)
+	defer assertStop(c, srv)
+
+	machine, password := s.Factory.MakeMachineReturningPassword(
+		c, &factory.MachineParams{Nonce: "fake_nonce"})
+	info.Tag = machine.Tag()
+	info.Password = password
+	info.Nonce = "fake_nonce"
+	info.ModelTag = s.State.ModelTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	var results params.NotifyWatchResults
+	err = st.APICall("Machiner", st.BestFacadeVersion("Machiner"), "", "Watch", params.Entities{
+		Entities: []params.Entity{{Tag: machine.Tag().String()}},
+	}, &results)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	watcherId := results.Results[0].NotifyWatcherId
+
+	nextErr := make(chan error, 1)
+	go func() {
+		nextErr <- st.APICall("NotifyWatcher", st.BestFacadeVersion("NotifyWatcher"), watcherId, "Next", nil, nil)
+	}()
+	select {
+	case err := <-nextErr:
+		c.Fatalf("Next returned early: %v", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	srv.Kill()
+	select {
+	case err := <-nextErr:
+		c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+		c.Assert(err, gc.ErrorMatches, "API server shutting down, try again.*")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for Next to return")
+	}
+}
+
 func (s *serverSuite) TestAPIServerCanListenOnBothIPv4AndIPv6(c *gc.C) {
 	err := s.State.SetAPIHostPorts(nil)
 	c.Assert(err, jc.ErrorIsNil)
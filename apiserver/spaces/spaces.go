@@ -16,6 +16,7 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Spaces", 2, NewAPI)
+	common.RequireFacadeAccess("Spaces", "CreateSpaces", permission.WriteAccess)
 }
 
 // API defines the methods the Spaces API facade implements.
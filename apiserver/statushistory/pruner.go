@@ -4,9 +4,14 @@
 package statushistory
 
 import (
+	"time"
+
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 )
 
@@ -30,10 +35,35 @@ func NewAPI(st *state.State, _ facade.Resources, auth facade.Authorizer) (*API,
 
 // Prune endpoint removes status history entries until
 // only the ones newer than now - p.MaxHistoryTime remain and
-// the history is smaller than p.MaxHistoryMB.
+// the history is smaller than p.MaxHistoryMB. The status history
+// retention settings in the controller config take precedence
+// over p.MaxHistoryTime, and may further limit the number of
+// entries kept for each entity and downsample old entries.
 func (api *API) Prune(p params.StatusHistoryPruneArgs) error {
 	if !api.authorizer.AuthController() {
 		return common.ErrPerm
 	}
-	return state.PruneStatusHistory(api.st, p.MaxHistoryTime, p.MaxHistoryMB)
+	controllerConfig, err := api.st.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read controller config")
+	}
+	policy := prunePolicy(controllerConfig.StatusHistoryPolicy(), p.MaxHistoryTime)
+	return state.PruneStatusHistoryByPolicy(api.st, policy, p.MaxHistoryMB)
+}
+
+// prunePolicy returns the policy with maxHistoryTime in place of any
+// unset age limits.
+func prunePolicy(policy controller.StatusHistoryPolicy, maxHistoryTime time.Duration) controller.StatusHistoryPolicy {
+	if policy.Default.MaxAge == 0 {
+		policy.Default.MaxAge = maxHistoryTime
+	}
+	kinds := make(map[string]controller.StatusHistoryRetention)
+	for kind, retention := range policy.Kinds {
+		if retention.MaxAge == 0 {
+			retention.MaxAge = maxHistoryTime
+		}
+		kinds[kind] = retention
+	}
+	policy.Kinds = kinds
+	return policy
 }
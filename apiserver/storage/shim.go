@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/storage/poolmanager"
@@ -22,6 +23,11 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Storage", 3, newAPI)
+	common.RequireFacadeAccess("Storage", "CreatePool", permission.WriteAccess)
+	common.RequireFacadeAccess("Storage", "AddToUnit", permission.WriteAccess)
+	common.RequireFacadeAccess("Storage", "Destroy", permission.WriteAccess)
+	common.RequireFacadeAccess("Storage", "Detach", permission.WriteAccess)
+	common.RequireFacadeAccess("Storage", "Attach", permission.WriteAccess)
 }
 
 func newAPI(
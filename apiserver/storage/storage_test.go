@@ -334,9 +334,9 @@ func (s *storageSuite) TestDestroy(c *gc.C) {
 	c.Assert(results.Results, gc.HasLen, 4)
 	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
 		{Error: &params.Error{Message: "cannae do it"}},
-		{Error: &params.Error{Message: `tag kind "volume" not valid`}},
-		{Error: &params.Error{Message: `tag kind "filesystem" not valid`}},
-		{Error: &params.Error{Message: `tag kind "machine" not valid`}},
+		{Error: &params.Error{Message: `tag kind "volume" not valid`, Code: params.CodeNotValid}},
+		{Error: &params.Error{Message: `tag kind "filesystem" not valid`, Code: params.CodeNotValid}},
+		{Error: &params.Error{Message: `tag kind "machine" not valid`, Code: params.CodeNotValid}},
 	})
 	s.assertCalls(c, []string{
 		getBlockForTypeCall, // Remove
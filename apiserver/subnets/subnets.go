@@ -17,6 +17,7 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Subnets", 2, NewAPI)
+	common.RequireFacadeAccess("Subnets", "AddSubnets", permission.WriteAccess)
 }
 
 // SubnetsAPI defines the methods the Subnets API facade implements.
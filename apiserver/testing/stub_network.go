@@ -20,7 +20,6 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	providercommon "github.com/juju/juju/provider/common"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -55,7 +54,7 @@ func (s StubNetwork) SetUpSuite(c *gc.C) {
 		}
 	}
 
-	ProviderInstance.Zones = []providercommon.AvailabilityZone{
+	ProviderInstance.Zones = []environs.AvailabilityZone{
 		&FakeZone{"zone1", true},
 		&FakeZone{"zone2", false},
 		&FakeZone{"zone3", true},
@@ -266,13 +265,13 @@ func CheckMethodCalls(c *gc.C, stub *testing.Stub, calls ...StubMethodCall) {
 	}
 }
 
-// FakeZone implements providercommon.AvailabilityZone for testing.
+// FakeZone implements environs.AvailabilityZone for testing.
 type FakeZone struct {
 	ZoneName      string
 	ZoneAvailable bool
 }
 
-var _ providercommon.AvailabilityZone = (*FakeZone)(nil)
+var _ environs.AvailabilityZone = (*FakeZone)(nil)
 
 func (f *FakeZone) Name() string {
 	return f.ZoneName
@@ -340,7 +339,7 @@ type StubBacking struct {
 	EnvConfig *config.Config
 	Cloud     environs.CloudSpec
 
-	Zones   []providercommon.AvailabilityZone
+	Zones   []environs.AvailabilityZone
 	Spaces  []networkingcommon.BackingSpace
 	Subnets []networkingcommon.BackingSubnet
 }
@@ -378,9 +377,9 @@ func (sb *StubBacking) SetUp(c *gc.C, envName string, withZones, withSpaces, wit
 		IdentityEndpoint: "identity-endpoint",
 		StorageEndpoint:  "storage-endpoint",
 	}
-	sb.Zones = []providercommon.AvailabilityZone{}
+	sb.Zones = []environs.AvailabilityZone{}
 	if withZones {
-		sb.Zones = make([]providercommon.AvailabilityZone, len(ProviderInstance.Zones))
+		sb.Zones = make([]environs.AvailabilityZone, len(ProviderInstance.Zones))
 		copy(sb.Zones, ProviderInstance.Zones)
 	}
 	sb.Spaces = []networkingcommon.BackingSpace{}
@@ -448,7 +447,7 @@ func (sb *StubBacking) CloudSpec(names.ModelTag) (environs.CloudSpec, error) {
 	return sb.Cloud, nil
 }
 
-func (sb *StubBacking) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (sb *StubBacking) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	sb.MethodCall(sb, "AvailabilityZones")
 	if err := sb.NextErr(); err != nil {
 		return nil, err
@@ -456,7 +455,7 @@ func (sb *StubBacking) AvailabilityZones() ([]providercommon.AvailabilityZone, e
 	return sb.Zones, nil
 }
 
-func (sb *StubBacking) SetAvailabilityZones(zones []providercommon.AvailabilityZone) error {
+func (sb *StubBacking) SetAvailabilityZones(zones []environs.AvailabilityZone) error {
 	sb.MethodCall(sb, "SetAvailabilityZones", zones)
 	return sb.NextErr()
 }
@@ -529,7 +528,7 @@ func (se *StubBacking) GoString() string {
 type StubProvider struct {
 	*testing.Stub
 
-	Zones   []providercommon.AvailabilityZone
+	Zones   []environs.AvailabilityZone
 	Subnets []network.SubnetInfo
 
 	environs.EnvironProvider // panic on any not implemented method call.
@@ -574,17 +573,17 @@ func (se *StubEnviron) GoString() string {
 	return "&StubEnviron{}"
 }
 
-// StubZonedEnviron is used in tests where providercommon.ZonedEnviron
+// StubZonedEnviron is used in tests where environs.ZonedEnviron
 // is needed.
 type StubZonedEnviron struct {
 	*testing.Stub
 
-	providercommon.ZonedEnviron // panic on any not implemented method call
+	environs.ZonedEnviron // panic on any not implemented method call
 }
 
-var _ providercommon.ZonedEnviron = (*StubZonedEnviron)(nil)
+var _ environs.ZonedEnviron = (*StubZonedEnviron)(nil)
 
-func (se *StubZonedEnviron) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (se *StubZonedEnviron) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	se.MethodCall(se, "AvailabilityZones")
 	if err := se.NextErr(); err != nil {
 		return nil, err
@@ -629,13 +628,13 @@ func (se *StubNetworkingEnviron) GoString() string {
 }
 
 // StubZonedNetworkingEnviron is used in tests where features from
-// both environs.Networking and providercommon.ZonedEnviron are
+// both environs.Networking and environs.ZonedEnviron are
 // needed.
 type StubZonedNetworkingEnviron struct {
 	*testing.Stub
 
 	// panic on any not implemented method call
-	providercommon.ZonedEnviron
+	environs.ZonedEnviron
 	environs.Networking
 }
 
@@ -660,7 +659,7 @@ func (se *StubZonedNetworkingEnviron) Subnets(instId instance.Id, subIds []netwo
 	return ProviderInstance.Subnets, nil
 }
 
-func (se *StubZonedNetworkingEnviron) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+func (se *StubZonedNetworkingEnviron) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	se.MethodCall(se, "AvailabilityZones")
 	if err := se.NextErr(); err != nil {
 		return nil, err
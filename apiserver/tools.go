@@ -11,7 +11,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -19,6 +21,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/state"
@@ -50,7 +53,7 @@ func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	switch r.Method {
 	case "GET":
-		tarball, err := h.processGet(r, st)
+		tarball, sha256, err := h.processGet(r, st)
 		if err != nil {
 			logger.Errorf("GET(%s) failed: %v", r.URL, err)
 			if err := sendError(w, errors.NewBadRequest(err, "")); err != nil {
@@ -58,9 +61,7 @@ func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			}
 			return
 		}
-		if err := h.sendTools(w, http.StatusOK, tarball); err != nil {
-			logger.Errorf("%v", err)
-		}
+		h.sendTools(w, r, tarball, sha256)
 	default:
 		if err := sendError(w, errors.MethodNotAllowedf("unsupported method: %q", r.Method)); err != nil {
 			logger.Errorf("%v", err)
@@ -102,58 +103,59 @@ func (h *toolsUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// processGet handles a tools GET request.
-func (h *toolsDownloadHandler) processGet(r *http.Request, st *state.State) ([]byte, error) {
+// processGet handles a tools GET request, returning the tools
+// tarball and its SHA-256 hash.
+func (h *toolsDownloadHandler) processGet(r *http.Request, st *state.State) ([]byte, string, error) {
 	version, err := version.ParseBinary(r.URL.Query().Get(":version"))
 	if err != nil {
-		return nil, errors.Annotate(err, "error parsing version")
+		return nil, "", errors.Annotate(err, "error parsing version")
 	}
 	storage, err := st.ToolsStorage()
 	if err != nil {
-		return nil, errors.Annotate(err, "error getting tools storage")
+		return nil, "", errors.Annotate(err, "error getting tools storage")
 	}
 	defer storage.Close()
-	_, reader, err := storage.Open(version.String())
+	metadata, reader, err := storage.Open(version.String())
 	if errors.IsNotFound(err) {
 		// Tools could not be found in tools storage,
 		// so look for them in simplestreams, fetch
 		// them and cache in tools storage.
 		logger.Infof("%v tools not found locally, fetching", version)
-		reader, err = h.fetchAndCacheTools(version, storage, st)
+		metadata, reader, err = h.fetchAndCacheTools(version, storage, st)
 		if err != nil {
 			err = errors.Annotate(err, "error fetching tools")
 		}
 	}
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer reader.Close()
 	data, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return nil, errors.Annotate(err, "failed to read tools tarball")
+		return nil, "", errors.Annotate(err, "failed to read tools tarball")
 	}
-	return data, nil
+	return data, metadata.SHA256, nil
 }
 
 // fetchAndCacheTools fetches tools with the specified version by searching for a URL
 // in simplestreams and GETting it, caching the result in tools storage before returning
 // to the caller.
-func (h *toolsDownloadHandler) fetchAndCacheTools(v version.Binary, stor binarystorage.Storage, st *state.State) (io.ReadCloser, error) {
+func (h *toolsDownloadHandler) fetchAndCacheTools(v version.Binary, stor binarystorage.Storage, st *state.State) (binarystorage.Metadata, io.ReadCloser, error) {
 	newEnviron := stateenvirons.GetNewEnvironFunc(environs.New)
 	env, err := newEnviron(st)
 	if err != nil {
-		return nil, err
+		return binarystorage.Metadata{}, nil, err
 	}
 	tools, err := envtools.FindExactTools(env, v.Number, v.Series, v.Arch)
 	if err != nil {
-		return nil, err
+		return binarystorage.Metadata{}, nil, err
 	}
 
 	// No need to verify the server's identity because we verify the SHA-256 hash.
 	logger.Infof("fetching %v tools from %v", v, tools.URL)
 	resp, err := utils.GetNonValidatingHTTPClient().Get(tools.URL)
 	if err != nil {
-		return nil, err
+		return binarystorage.Metadata{}, nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
@@ -161,17 +163,17 @@ func (h *toolsDownloadHandler) fetchAndCacheTools(v version.Binary, stor binarys
 		if body, err := ioutil.ReadAll(resp.Body); err == nil {
 			msg += fmt.Sprintf(" (%s)", bytes.TrimSpace(body))
 		}
-		return nil, errors.New(msg)
+		return binarystorage.Metadata{}, nil, errors.New(msg)
 	}
 	data, sha256, err := readAndHash(resp.Body)
 	if err != nil {
-		return nil, err
+		return binarystorage.Metadata{}, nil, err
 	}
 	if int64(len(data)) != tools.Size {
-		return nil, errors.Errorf("size mismatch for %s", tools.URL)
+		return binarystorage.Metadata{}, nil, errors.Errorf("size mismatch for %s", tools.URL)
 	}
 	if sha256 != tools.SHA256 {
-		return nil, errors.Errorf("hash mismatch for %s", tools.URL)
+		return binarystorage.Metadata{}, nil, errors.Errorf("hash mismatch for %s", tools.URL)
 	}
 
 	// Cache tarball in tools storage before returning.
@@ -181,23 +183,20 @@ func (h *toolsDownloadHandler) fetchAndCacheTools(v version.Binary, stor binarys
 		SHA256:  tools.SHA256,
 	}
 	if err := stor.Add(bytes.NewReader(data), metadata); err != nil {
-		return nil, errors.Annotate(err, "error caching tools")
+		return binarystorage.Metadata{}, nil, errors.Annotate(err, "error caching tools")
 	}
-	return ioutil.NopCloser(bytes.NewReader(data)), nil
+	return metadata, ioutil.NopCloser(bytes.NewReader(data)), nil
 }
 
-// sendTools streams the tools tarball to the client.
-func (h *toolsDownloadHandler) sendTools(w http.ResponseWriter, statusCode int, tarball []byte) error {
+// sendTools streams the tools tarball to the client. The tarball's
+// SHA-256 hash is used as its entity tag, and Range requests are
+// honoured so that interrupted downloads can be resumed.
+func (h *toolsDownloadHandler) sendTools(w http.ResponseWriter, r *http.Request, tarball []byte, sha256 string) {
 	w.Header().Set("Content-Type", "application/x-tar-gz")
-	w.Header().Set("Content-Length", fmt.Sprint(len(tarball)))
-	w.WriteHeader(statusCode)
-	if _, err := w.Write(tarball); err != nil {
-		return errors.Trace(sendError(
-			w,
-			errors.NewBadRequest(errors.Annotatef(err, "failed to write tools"), ""),
-		))
-	}
-	return nil
+	if sha256 != "" {
+		w.Header().Set("ETag", fmt.Sprintf("%q", sha256))
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(tarball))
 }
 
 // processPost handles a tools upload POST request after authentication.
@@ -241,6 +240,9 @@ func (h *toolsUploadHandler) processPost(r *http.Request, st *state.State) (*too
 			toolsVersions = append(toolsVersions, v)
 		}
 	}
+	if err := limitUpload(r, st, controller.Config.ToolsUploadMaxSize); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return h.handleUpload(r.Body, toolsVersions, serverRoot, st)
 }
 
@@ -269,34 +271,49 @@ func (h *toolsUploadHandler) handleUpload(r io.Reader, toolsVersions []version.B
 	}
 	defer storage.Close()
 
-	// Read the tools tarball from the request, calculating the sha256 along the way.
-	data, sha256, err := readAndHash(r)
+	// Stream the tools tarball from the request into a temporary
+	// file, calculating the sha256 along the way, so that large
+	// tarballs are not held in memory.
+	tempFile, err := ioutil.TempFile("", "tools")
 	if err != nil {
-		return nil, err
+		return nil, errors.Annotate(err, "creating temp file")
 	}
-	if len(data) == 0 {
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tempFile, hash), r)
+	if err != nil {
+		return nil, errors.Annotate(err, "error processing file upload")
+	}
+	if size == 0 {
 		return nil, errors.BadRequestf("no tools uploaded")
 	}
+	sha256hex := fmt.Sprintf("%x", hash.Sum(nil))
 
 	// TODO(wallyworld): check integrity of tools tarball.
 
-	// Store tools and metadata in tools storage.
+	// Store tools and metadata in tools storage, making sure the
+	// new tools are found by subsequent searches.
+	defer common.InvalidateToolsCache()
 	for _, v := range toolsVersions {
 		metadata := binarystorage.Metadata{
 			Version: v.String(),
-			Size:    int64(len(data)),
-			SHA256:  sha256,
+			Size:    size,
+			SHA256:  sha256hex,
 		}
 		logger.Debugf("uploading tools %+v to storage", metadata)
-		if err := storage.Add(bytes.NewReader(data), metadata); err != nil {
+		if _, err := tempFile.Seek(0, os.SEEK_SET); err != nil {
+			return nil, errors.Annotate(err, "rewinding tools tarball")
+		}
+		if err := storage.Add(tempFile, metadata); err != nil {
 			return nil, err
 		}
 	}
 
 	tools := &tools.Tools{
 		Version: toolsVersions[0],
-		Size:    int64(len(data)),
-		SHA256:  sha256,
+		Size:    size,
+		SHA256:  sha256hex,
 		URL:     common.ToolsURL(serverRoot, toolsVersions[0]),
 	}
 	return tools, nil
@@ -28,6 +28,7 @@ import (
 	apitesting "github.com/juju/juju/api/testing"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
@@ -430,6 +431,73 @@ func (s *toolsSuite) TestDownloadTopLevelPath(c *gc.C) {
 	s.testDownload(c, tools, "")
 }
 
+func (s *toolsSuite) storeABCTools(c *gc.C) *coretools.Tools {
+	v := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	}
+	return s.storeFakeTools(c, s.State, "abc", binarystorage.Metadata{
+		Version: v.String(),
+		Size:    3,
+		SHA256:  "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	})
+}
+
+func (s *toolsSuite) downloadRequestWithHeaders(c *gc.C, tools *coretools.Tools, headers map[string]string) *http.Response {
+	url := s.toolsURL(c, "")
+	url.Path = fmt.Sprintf("/tools/%s", tools.Version)
+	return s.sendRequest(c, httpRequestParams{
+		method:       "GET",
+		url:          url.String(),
+		extraHeaders: headers,
+	})
+}
+
+func (s *toolsSuite) TestDownloadSetsETag(c *gc.C) {
+	tools := s.storeABCTools(c)
+	resp := s.downloadRequestWithHeaders(c, tools, nil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("ETag"), gc.Equals, `"`+tools.SHA256+`"`)
+	c.Assert(resp.Header.Get("Accept-Ranges"), gc.Equals, "bytes")
+}
+
+func (s *toolsSuite) TestDownloadRange(c *gc.C) {
+	tools := s.storeABCTools(c)
+	resp := s.downloadRequestWithHeaders(c, tools, map[string]string{
+		"Range": "bytes=1-",
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusPartialContent)
+	c.Assert(resp.Header.Get("Content-Range"), gc.Equals, "bytes 1-2/3")
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "bc")
+}
+
+func (s *toolsSuite) TestDownloadIfRangeMismatch(c *gc.C) {
+	tools := s.storeABCTools(c)
+	resp := s.downloadRequestWithHeaders(c, tools, map[string]string{
+		"Range":    "bytes=1-",
+		"If-Range": `"not-the-hash"`,
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "abc")
+}
+
+func (s *toolsSuite) TestDownloadIfNoneMatch(c *gc.C) {
+	tools := s.storeABCTools(c)
+	resp := s.downloadRequestWithHeaders(c, tools, map[string]string{
+		"If-None-Match": `"` + tools.SHA256 + `"`,
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNotModified)
+}
+
 func (s *toolsSuite) TestDownloadFetchesAndCaches(c *gc.C) {
 	// The tools are not in binarystorage, so the download request causes
 	// the API server to search for the tools in simplestreams, fetch
@@ -640,3 +708,25 @@ func (s *toolsWithMacaroonsSuite) TestCanPostWithLocalLogin(c *gc.C) {
 func (s *toolsWithMacaroonsSuite) doer() func(*http.Request) (*http.Response, error) {
 	return bakeryDo(nil, bakeryGetError)
 }
+
+type toolsUploadLimitSuite struct {
+	toolsCommonSuite
+}
+
+var _ = gc.Suite(&toolsUploadLimitSuite{})
+
+func (s *toolsUploadLimitSuite) SetUpTest(c *gc.C) {
+	s.ControllerConfigAttrs = map[string]interface{}{
+		controller.ToolsUploadMaxSizeKey: "1M",
+	}
+	s.toolsCommonSuite.SetUpTest(c)
+}
+
+func (s *toolsUploadLimitSuite) TestUploadTooLarge(c *gc.C) {
+	toolsPath := path.Join(c.MkDir(), "tools.tar.gz")
+	err := ioutil.WriteFile(toolsPath, make([]byte, 2*1024*1024), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.uploadRequest(c, s.toolsURI(c, "?binaryVersion=1.18.0-quantal-amd64"), "application/x-tar-gz", toolsPath)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, ".*upload exceeds the limit of 1048576 bytes$")
+}
@@ -107,23 +107,36 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.st.WatchForModelConfigChanges()
-			// Consume the initial event. Technically, API
-			// calls to Watch 'transmit' the initial event
-			// in the Watch response. But NotifyWatchers
-			// have no state to transmit.
-			if _, ok := <-watch.Changes(); ok {
-				result.Results[i].NotifyWatcherId = u.resources.Register(watch)
-				err = nil
-			} else {
-				err = watcher.EnsureErr(watch)
-			}
+			result.Results[i].NotifyWatcherId, err = u.watchAPIVersion(tag)
 		}
 		result.Results[i].Error = common.ServerError(err)
 	}
 	return result, nil
 }
 
+func (u *UpgraderAPI) watchAPIVersion(tag names.Tag) (string, error) {
+	var machine *state.Machine
+	if machineTag, ok := tag.(names.MachineTag); ok {
+		var err error
+		if machine, err = u.st.Machine(machineTag.Id()); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	var watch state.NotifyWatcher = u.st.WatchForModelConfigChanges()
+	if machine != nil {
+		// The machine's desired agent version may also change.
+		watch = common.NewMultiNotifyWatcher(watch, machine.Watch())
+	}
+	// Consume the initial event. Technically, API
+	// calls to Watch 'transmit' the initial event
+	// in the Watch response. But NotifyWatchers
+	// have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
 func (u *UpgraderAPI) getGlobalAgentVersion() (version.Number, *config.Config, error) {
 	// Get the Agent Version requested in the Environment Config
 	cfg, err := u.st.ModelConfig()
@@ -137,6 +150,26 @@ func (u *UpgraderAPI) getGlobalAgentVersion() (version.Number, *config.Config, e
 	return agentVersion, cfg, nil
 }
 
+// desiredAgentVersion returns the version that the agent with the given
+// tag should run: the version staged for its machine, if any, and the
+// model's agent-version otherwise. A staged version may be older than
+// agent-version, so that a canary can be moved back.
+func (u *UpgraderAPI) desiredAgentVersion(tag names.Tag, agentVersion version.Number) version.Number {
+	machineTag, ok := tag.(names.MachineTag)
+	if !ok {
+		return agentVersion
+	}
+	machine, err := u.st.Machine(machineTag.Id())
+	if err != nil {
+		logger.Warningf("cannot get desired agent version for %s: %v", tag, err)
+		return agentVersion
+	}
+	if desired, ok := machine.DesiredAgentVersion(); ok {
+		return desired
+	}
+	return agentVersion
+}
+
 type hasIsManager interface {
 	IsManager() bool
 }
@@ -163,8 +196,6 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	if err != nil {
 		return params.VersionResults{}, common.ServerError(err)
 	}
-	// Is the desired version greater than the current API server version?
-	isNewerVersion := agentVersion.Compare(jujuversion.Current) > 0
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -173,20 +204,22 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			// Only return the globally desired agent version if the
-			// asking entity is a machine agent with JobManageModel or
-			// if this API server is running the globally desired agent
-			// version. Otherwise report this API server's current
+			desiredVersion := u.desiredAgentVersion(tag, agentVersion)
+			// Only return the desired agent version if the asking
+			// entity is a machine agent with JobManageModel or if
+			// this API server is running the desired agent version
+			// or newer. Otherwise report this API server's current
 			// agent version.
 			//
 			// This ensures that state machine agents will upgrade
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
+			isNewerVersion := desiredVersion.Compare(jujuversion.Current) > 0
 			if !isNewerVersion || u.entityIsManager(tag) {
-				results[i].Version = &agentVersion
+				results[i].Version = &desiredVersion
 			} else {
-				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, jujuversion.Current)
+				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", desiredVersion, jujuversion.Current)
 				results[i].Version = &jujuversion.Current
 			}
 			err = nil
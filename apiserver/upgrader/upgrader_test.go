@@ -99,6 +99,28 @@ func (s *upgraderSuite) TestWatchAPIVersion(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *upgraderSuite) TestWatchAPIVersionNoticesDesiredAgentVersion(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.upgrader.WatchAPIVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	resource := s.resources.Get(results.Results[0].NotifyWatcherId)
+	c.Assert(resource, gc.NotNil)
+
+	w := resource.(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	err = s.rawMachine.SetDesiredAgentVersion(version.MustParse("3.4.567.8"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *upgraderSuite) TestUpgraderAPIRefusesNonMachineAgent(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewUnitTag("ubuntu/1")
@@ -341,3 +363,36 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, jujuversion.Current)
 }
+
+func (s *upgraderSuite) TestDesiredVersionStagedForMachine(c *gc.C) {
+	staged := jujuversion.Current
+	staged.Patch++
+	err := s.apiMachine.SetDesiredAgentVersion(staged)
+	c.Assert(err, jc.ErrorIsNil)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.apiMachine.Tag(),
+	}
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: s.apiMachine.Tag().String()}}}
+	results, err := upgraderAPI.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, staged)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHonoursOlderStagedVersion(c *gc.C) {
+	staged := jujuversion.Current
+	staged.Minor--
+	err := s.rawMachine.SetDesiredAgentVersion(staged)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, staged)
+}
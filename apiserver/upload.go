@@ -0,0 +1,67 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+)
+
+// limitUpload arranges for the body of r to be read no further than
+// the limit returned by maxSize for the controller config, so that
+// uploads are streamed without being allowed to grow unbounded. A
+// request whose declared length is already too large is rejected
+// before any of its body is read.
+func limitUpload(r *http.Request, st *state.State, maxSize func(controller.Config) int64) error {
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read controller config")
+	}
+	limit := maxSize(cfg)
+	if limit <= 0 {
+		return nil
+	}
+	if r.ContentLength > limit {
+		return uploadTooLargeError(limit)
+	}
+	r.Body = &limitedBody{ReadCloser: r.Body, remaining: limit, limit: limit}
+	return nil
+}
+
+func uploadTooLargeError(limit int64) error {
+	return errors.BadRequestf("upload exceeds the limit of %d bytes", limit)
+}
+
+// limitedBody is a request body that fails once more than limit
+// bytes have been read from it.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+	exceeded  bool
+}
+
+// Read is part of the io.Reader interface.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, uploadTooLargeError(b.limit)
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		b.exceeded = true
+		return n, uploadTooLargeError(b.limit)
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
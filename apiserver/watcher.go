@@ -59,6 +59,10 @@ func init() {
 		"MigrationStatusWatcher", 1, newMigrationStatusWatcher,
 		reflect.TypeOf((*srvMigrationStatusWatcher)(nil)),
 	)
+	common.RegisterFacade(
+		"APIHostPortsWatcher", 1, newAPIHostPortsWatcher,
+		reflect.TypeOf((*srvAPIHostPortsWatcher)(nil)),
+	)
 }
 
 // NewAllWatcher returns a new API server endpoint for interacting
@@ -504,3 +508,63 @@ var getControllerCACert = func(st migrationBackend) (string, error) {
 	}
 	return cacert, nil
 }
+
+// apiHostPortsBackend is the subset of *state.State used to report
+// the addresses of the API servers.
+type apiHostPortsBackend interface {
+	APIHostPorts() ([][]network.HostPort, error)
+}
+
+// getAPIHostPortsBackend is a shim to allow the watcher to be tested
+// without a working State.
+var getAPIHostPortsBackend = func(st *state.State) apiHostPortsBackend {
+	return st
+}
+
+func newAPIHostPortsWatcher(context facade.Context) (facade.Facade, error) {
+	id := context.ID()
+	auth := context.Auth()
+	resources := context.Resources()
+
+	if !isAgent(auth) {
+		return nil, common.ErrPerm
+	}
+	w, ok := resources.Get(id).(state.NotifyWatcher)
+	if !ok {
+		return nil, common.ErrUnknownWatcher
+	}
+	return &srvAPIHostPortsWatcher{
+		watcherCommon: newWatcherCommon(context),
+		watcher:       w,
+		st:            getAPIHostPortsBackend(context.State()),
+	}, nil
+}
+
+// srvAPIHostPortsWatcher wraps the notify watcher created by a
+// WatchAPIHostPorts call, sending agents the addresses of the API
+// servers along with each change so that they can update their
+// configuration without having to ask for them.
+type srvAPIHostPortsWatcher struct {
+	watcherCommon
+	watcher state.NotifyWatcher
+	st      apiHostPortsBackend
+}
+
+// Next returns when the addresses of the API servers change, along
+// with the current addresses.
+func (w *srvAPIHostPortsWatcher) Next() (params.APIHostPortsResult, error) {
+	if _, ok := <-w.watcher.Changes(); !ok {
+		err := w.watcher.Err()
+		if err == nil {
+			err = common.ErrStoppedWatcher
+		}
+		return params.APIHostPortsResult{}, err
+	}
+	servers, err := w.st.APIHostPorts()
+	if err != nil {
+		return params.APIHostPortsResult{}, errors.Annotate(err, "retrieving API addresses")
+	}
+	return params.APIHostPortsResult{
+		Servers: params.FromNetworkHostsPorts(servers),
+	}, nil
+}
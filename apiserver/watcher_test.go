@@ -154,6 +154,38 @@ func (s *watcherSuite) TestMigrationStatusWatcherNotAgent(c *gc.C) {
 	c.Assert(err, gc.Equals, common.ErrPerm)
 }
 
+func (s *watcherSuite) TestAPIHostPortsWatcher(c *gc.C) {
+	w := apiservertesting.NewFakeNotifyWatcher()
+	id := s.resources.Register(w)
+	s.authorizer.Tag = names.NewMachineTag("12")
+	apiserver.PatchGetAPIHostPortsBackend(s, new(fakeMigrationBackend))
+
+	facade := s.getFacade(c, "APIHostPortsWatcher", 1, id, nopDispose).(apiHostPortsWatcher)
+	defer c.Check(facade.Stop(), jc.ErrorIsNil)
+	result, err := facade.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.APIHostPortsResult{
+		Servers: params.FromNetworkHostsPorts([][]network.HostPort{
+			MustParseHostPorts("1.2.3.4:5", "2.3.4.5:6"),
+			MustParseHostPorts("3.4.5.6:7"),
+		}),
+	})
+}
+
+func (s *watcherSuite) TestAPIHostPortsWatcherNotAgent(c *gc.C) {
+	id := s.resources.Register(apiservertesting.NewFakeNotifyWatcher())
+	s.authorizer.Tag = names.NewUserTag("frogdog")
+
+	factory := getFacadeFactory(c, "APIHostPortsWatcher", 1)
+	_, err := factory(s.facadeContext(id, nopDispose))
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+type apiHostPortsWatcher interface {
+	Next() (params.APIHostPortsResult, error)
+	Stop() error
+}
+
 type machineStorageIdsWatcher interface {
 	Next() (params.MachineStorageIdsWatchResult, error)
 }
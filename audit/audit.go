@@ -78,3 +78,24 @@ func (e AuditEntry) Validate() error {
 
 	return nil
 }
+
+// Filter selects audit entries. Zero-valued fields match every entry.
+type Filter struct {
+	// ModelUUID, if set, selects entries recorded on the model with
+	// this UUID.
+	ModelUUID string
+
+	// OriginName, if set, selects entries triggered by the origin
+	// with this name.
+	OriginName string
+
+	// After, if set, selects entries recorded after this time.
+	After time.Time
+
+	// Before, if set, selects entries recorded before this time.
+	Before time.Time
+
+	// Limit, if positive, is the maximum number of entries to
+	// select. The most recent entries are selected first.
+	Limit int
+}
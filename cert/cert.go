@@ -5,7 +5,9 @@ package cert
 
 import (
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -13,27 +15,85 @@ import (
 )
 
 // Verify verifies that the given server certificate is valid with
-// respect to the given CA certificate at the given time.
+// respect to the given CA certificate at the given time. The server
+// certificate may be followed by intermediate certificates, and the
+// CA certificate may be a bundle of several trusted certificates.
 func Verify(srvCertPEM, caCertPEM string, when time.Time) error {
-	caCert, err := cert.ParseCert(caCertPEM)
+	pool, err := NewCertPool(caCertPEM)
 	if err != nil {
 		return errors.Annotate(err, "cannot parse CA certificate")
 	}
-	srvCert, err := cert.ParseCert(srvCertPEM)
+	srvCerts, err := ParseCertificates(srvCertPEM)
 	if err != nil {
 		return errors.Annotate(err, "cannot parse server certificate")
 	}
-	pool := x509.NewCertPool()
-	pool.AddCert(caCert)
+	intermediates := x509.NewCertPool()
+	for _, c := range srvCerts[1:] {
+		intermediates.AddCert(c)
+	}
 	opts := x509.VerifyOptions{
-		DNSName:     "anyServer",
-		Roots:       pool,
-		CurrentTime: when,
+		DNSName:       "anyServer",
+		Roots:         pool,
+		Intermediates: intermediates,
+		CurrentTime:   when,
 	}
-	_, err = srvCert.Verify(opts)
+	_, err = srvCerts[0].Verify(opts)
 	return err
 }
 
+// ParseCertificates parses all the certificates in the given PEM
+// data, in the order they appear. Blocks other than certificates
+// are ignored, but at least one certificate must be present.
+func ParseCertificates(pemData string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	data := []byte(pemData)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certs, nil
+}
+
+// NewCertPool returns a certificate pool holding all the certificates
+// in the given PEM data. This allows a CA certificate to be supplied
+// together with the intermediate CA certificates that issued the
+// controller's certificate.
+func NewCertPool(caCertPEM string) (*x509.CertPool, error) {
+	certs, err := ParseCertificates(caCertPEM)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pool := x509.NewCertPool()
+	for _, c := range certs {
+		pool.AddCert(c)
+	}
+	return pool, nil
+}
+
+// JoinChain returns the given certificate followed by the given
+// chain of intermediate certificates, in the form expected by TLS
+// servers.
+func JoinChain(certPEM, chainPEM string) string {
+	if chainPEM == "" {
+		return certPEM
+	}
+	return strings.TrimRight(certPEM, "\n") + "\n" + chainPEM
+}
+
 // NewLeafKeyBits is the number of bits used for the cert.NewLeaf call.
 var NewLeafKeyBits = 2048
 
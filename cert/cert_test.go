@@ -5,9 +5,12 @@ package cert_test
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"io"
 	"io/ioutil"
 	"math/big"
@@ -313,3 +316,70 @@ XUrBZO5UPZab66lJWXyseA==
 -----END CERTIFICATE-----
 `
 )
+
+// newIntermediateCA returns a CA certificate and key signed by the
+// given CA.
+func newIntermediateCA(c *gc.C, caCertPEM, caKeyPEM string, expiry time.Time) (certPEM, keyPEM string) {
+	caCert, caKey, err := utilscert.ParseCertAndKey(caCertPEM, caKeyPEM)
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "intermediate"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              expiry,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	c.Assert(err, jc.ErrorIsNil)
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	return certPEM, keyPEM
+}
+
+func (certSuite) TestVerifyWithIntermediate(c *gc.C) {
+	now := time.Now()
+	rootCert, rootKey, err := cert.NewCA("foo", "1", now.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	interCert, interKey := newIntermediateCA(c, rootCert, rootKey, now.Add(time.Hour))
+
+	var noHostnames []string
+	srvCert, _, err := cert.NewServer(interCert, interKey, now.Add(time.Hour), noHostnames)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Without the intermediate, the server certificate cannot be
+	// traced back to the root.
+	err = cert.Verify(srvCert, rootCert, now)
+	c.Check(err, gc.ErrorMatches, "x509: certificate signed by unknown authority.*")
+
+	// The intermediate may be served with the certificate...
+	err = cert.Verify(cert.JoinChain(srvCert, interCert), rootCert, now)
+	c.Check(err, jc.ErrorIsNil)
+
+	// ... or distributed with the CA certificate.
+	err = cert.Verify(srvCert, cert.JoinChain(rootCert, interCert), now)
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (certSuite) TestParseCertificates(c *gc.C) {
+	now := time.Now()
+	rootCert, rootKey, err := cert.NewCA("foo", "1", now.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	interCert, _ := newIntermediateCA(c, rootCert, rootKey, now.Add(time.Hour))
+
+	certs, err := cert.ParseCertificates(cert.JoinChain(rootCert, interCert) + rootKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs, gc.HasLen, 2)
+	c.Assert(certs[1].Subject.CommonName, gc.Equals, "intermediate")
+
+	_, err = cert.ParseCertificates(rootKey)
+	c.Assert(err, gc.ErrorMatches, "no certificates found")
+}
+
+func (certSuite) TestJoinChain(c *gc.C) {
+	c.Assert(cert.JoinChain("a\n", ""), gc.Equals, "a\n")
+	c.Assert(cert.JoinChain("a\n\n", "b\n"), gc.Equals, "a\nb\n")
+}
@@ -4,3 +4,8 @@
 package cloudconfig
 
 var ToolsDownloadCommand = toolsDownloadCommand
+
+var (
+	RenderENIConfig     = renderENIConfig
+	RenderNetplanConfig = renderNetplanConfig
+)
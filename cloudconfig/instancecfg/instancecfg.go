@@ -15,7 +15,9 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	jujuos "github.com/juju/utils/os"
 	"github.com/juju/utils/proxy"
+	"github.com/juju/utils/series"
 	"github.com/juju/utils/shell"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
@@ -45,7 +47,10 @@ var logger = loggo.GetLogger("juju.cloudconfig.instancecfg")
 // InstanceConfig represents initialization information for a new juju instance.
 type InstanceConfig struct {
 	// Tags is a set of tags to set on the instance, if supported. This
-	// should be populated using the InstanceTags method in this package.
+	// should be populated using the InstanceTags method in this package,
+	// and includes any user-specified tags from the "resource-tags"
+	// model config. Providers that support tagging should apply all of
+	// them to the instances they start.
 	Tags map[string]string
 
 	// Bootstrap contains bootstrap-specific configuration. If this is set,
@@ -137,6 +142,11 @@ type InstanceConfig struct {
 	// override the default APT sources.
 	AptMirror string
 
+	// CloudInitUserData holds extra cloud-init user-data, taken from
+	// the model's cloudinit-userdata config, to be merged into that
+	// generated by juju.
+	CloudInitUserData map[string]interface{}
+
 	// The type of Simple Stream to download and deploy on this instance.
 	ImageStream string
 
@@ -154,6 +164,16 @@ type InstanceConfig struct {
 	// ifup when bridging bonded interfaces. See bugs #1594855 and
 	// #1269921.
 	NetBondReconfigureDelay int
+
+	// AgentResourceLimits optionally limits the CPU and memory
+	// available to the machine agent, and to any unit agents it
+	// deploys, so that runaway agents cannot starve workloads.
+	AgentResourceLimits service.AgentResourceLimits
+
+	// NetworkBindings optionally describes the network interfaces
+	// of the instance, bound to subnets in spaces, which cloud-init
+	// configures before the agent is installed.
+	NetworkBindings []NetworkBinding
 }
 
 // ControllerConfig represents controller-specific initialization information
@@ -340,11 +360,34 @@ func (p *StateInitializationParams) Unmarshal(data []byte) error {
 }
 
 func (cfg *InstanceConfig) agentInfo() service.AgentInfo {
-	return service.NewMachineAgentInfo(
+	info := service.NewMachineAgentInfo(
 		cfg.MachineId,
 		cfg.DataDir,
 		cfg.LogDir,
 	)
+	info.Limits = cfg.AgentResourceLimits
+	return info
+}
+
+// agentValues returns the values to record in the agent config,
+// including any agent resource limits so that they can be applied
+// to deployed unit agents.
+func (cfg *InstanceConfig) agentValues() map[string]string {
+	limits := cfg.AgentResourceLimits
+	if limits.IsZero() {
+		return cfg.AgentEnvironment
+	}
+	values := make(map[string]string)
+	for k, v := range cfg.AgentEnvironment {
+		values[k] = v
+	}
+	if limits.CPUQuota > 0 {
+		values[agent.AgentCPUQuota] = strconv.Itoa(limits.CPUQuota)
+	}
+	if limits.MemoryLimit > 0 {
+		values[agent.AgentMemoryLimit] = strconv.FormatUint(limits.MemoryLimit, 10)
+	}
+	return values
 }
 
 func (cfg *InstanceConfig) ToolsDir(renderer shell.Renderer) string {
@@ -392,7 +435,7 @@ func (cfg *InstanceConfig) AgentConfig(
 		StateAddresses:    cfg.stateHostAddrs(),
 		APIAddresses:      cfg.APIHostAddrs(),
 		CACert:            cacert,
-		Values:            cfg.AgentEnvironment,
+		Values:            cfg.agentValues(),
 		Controller:        cfg.ControllerTag,
 		Model:             cfg.APIInfo.ModelTag,
 	}
@@ -543,6 +586,19 @@ func (cfg *InstanceConfig) VerifyConfig() (err error) {
 	if cfg.MachineNonce == "" {
 		return errors.New("missing machine nonce")
 	}
+	if err := cfg.AgentResourceLimits.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	seenInterfaces := make(map[string]bool)
+	for _, binding := range cfg.NetworkBindings {
+		if err := binding.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		if seenInterfaces[binding.InterfaceName] {
+			return errors.Errorf("duplicate network binding for interface %q", binding.InterfaceName)
+		}
+		seenInterfaces[binding.InterfaceName] = true
+	}
 	if cfg.Controller != nil {
 		if err := cfg.verifyControllerConfig(); err != nil {
 			return errors.Trace(err)
@@ -750,6 +806,20 @@ func PopulateInstanceConfig(icfg *InstanceConfig,
 	return nil
 }
 
+// dataDirSuitsSeries reports whether the agent data directory, which the
+// model config has already validated as absolute in either Unix or Windows
+// form, can be used on a machine running the given series.
+func dataDirSuitsSeries(dataDir, machineSeries string) bool {
+	osType, err := series.GetOSFromSeries(machineSeries)
+	if err != nil {
+		return false
+	}
+	if osType == jujuos.Windows {
+		return !path.IsAbs(dataDir)
+	}
+	return path.IsAbs(dataDir)
+}
+
 // FinishInstanceConfig sets fields on a InstanceConfig that can be determined by
 // inspecting a plain config.Config and the machine constraints at the last
 // moment before creating the user-data. It assumes that the supplied Config comes
@@ -781,18 +851,41 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 		logger.Debugf("Setting numa ctl preference to %v", icfg.Controller.Config.NUMACtlPreference())
 		// Unfortunately, AgentEnvironment can only take strings as values
 		icfg.AgentEnvironment[agent.NUMACtlPreference] = fmt.Sprintf("%v", icfg.Controller.Config.NUMACtlPreference())
+	} else if dataDir, ok := cfg.AgentDataDir(); ok {
+		// Controllers always use the default data directory.
+		if dataDirSuitsSeries(dataDir, icfg.Series) {
+			icfg.DataDir = dataDir
+		} else {
+			logger.Warningf("ignoring %s %q for series %q", config.AgentDataDirKey, dataDir, icfg.Series)
+		}
+	}
+	if loggingConfig := cfg.AgentLoggingConfig(); loggingConfig != "" {
+		icfg.AgentEnvironment[agent.LoggingConfig] = loggingConfig
 	}
+	if icfg.Controller == nil {
+		// Controller agents are never resource limited.
+		icfg.AgentResourceLimits.CPUQuota, _ = cfg.AgentCPUQuota()
+		icfg.AgentResourceLimits.MemoryLimit, _ = cfg.AgentMemoryLimit()
+	}
+	if maxLogSize, ok := cfg.AgentMaxLogSize(); ok {
+		icfg.AgentEnvironment[agent.MaxLogSize] = fmt.Sprintf("%d", maxLogSize)
+	}
+	icfg.CloudInitUserData = cfg.CloudInitUserData()
 	return nil
 }
 
 // InstanceTags returns the minimum set of tags that should be set on a
-// machine instance, if the provider supports them.
-func InstanceTags(modelUUID, controllerUUID string, tagger tags.ResourceTagger, jobs []multiwatcher.MachineJob) map[string]string {
+// machine instance, if the provider supports them. The machine ID tag
+// is omitted if machineId is empty.
+func InstanceTags(modelUUID, controllerUUID, machineId string, tagger tags.ResourceTagger, jobs []multiwatcher.MachineJob) map[string]string {
 	instanceTags := tags.ResourceTags(
 		names.NewModelTag(modelUUID),
 		names.NewControllerTag(controllerUUID),
 		tagger,
 	)
+	if machineId != "" {
+		instanceTags[tags.JujuMachine] = machineId
+	}
 	if multiwatcher.AnyJobNeedsState(jobs...) {
 		instanceTags[tags.JujuIsController] = "true"
 	}
@@ -8,8 +8,10 @@ import (
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
@@ -48,8 +50,18 @@ func (*instancecfgSuite) TestInstanceTagsUserSpecified(c *gc.C) {
 	})
 }
 
+func (*instancecfgSuite) TestInstanceTagsMachine(c *gc.C) {
+	cfg := testing.CustomModelConfig(c, testing.Attrs{})
+	tags := instancecfg.InstanceTags(testing.ModelTag.Id(), testing.ControllerTag.Id(), "42", cfg, nil)
+	c.Assert(tags, jc.DeepEquals, map[string]string{
+		"juju-model-uuid":      testing.ModelTag.Id(),
+		"juju-controller-uuid": testing.ControllerTag.Id(),
+		"juju-machine-id":      "42",
+	})
+}
+
 func testInstanceTags(c *gc.C, cfg *config.Config, jobs []multiwatcher.MachineJob, expectTags map[string]string) {
-	tags := instancecfg.InstanceTags(testing.ModelTag.Id(), testing.ControllerTag.Id(), cfg, jobs)
+	tags := instancecfg.InstanceTags(testing.ModelTag.Id(), testing.ControllerTag.Id(), "", cfg, jobs)
 	c.Assert(tags, jc.DeepEquals, expectTags)
 }
 
@@ -111,3 +123,51 @@ func (*instancecfgSuite) TestGUITools(c *gc.C) {
 	}
 	c.Assert(icfg.GUITools(), gc.Equals, "/path/to/datadir/gui")
 }
+
+func (*instancecfgSuite) TestFinishInstanceConfigAgentSettings(c *gc.C) {
+	cfg := testing.CustomModelConfig(c, testing.Attrs{
+		"agent-logging-config": "<root>=DEBUG",
+		"agent-datadir":        "/srv/juju",
+		"agent-max-log-size":   50,
+		"agent-cpu-quota":      80,
+		"agent-memory-limit":   512,
+		"cloudinit-userdata":   "packages: [jq]\n",
+	})
+	icfg, err := instancecfg.NewInstanceConfig(testing.ControllerTag, "1", "nonce", "released", "xenial", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = instancecfg.FinishInstanceConfig(icfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(icfg.DataDir, gc.Equals, "/srv/juju")
+	c.Assert(icfg.AgentEnvironment[agent.LoggingConfig], gc.Equals, "<root>=DEBUG")
+	c.Assert(icfg.AgentEnvironment[agent.MaxLogSize], gc.Equals, "50")
+	c.Assert(icfg.AgentResourceLimits, jc.DeepEquals, service.AgentResourceLimits{
+		CPUQuota:    80,
+		MemoryLimit: 512,
+	})
+	c.Assert(icfg.CloudInitUserData, jc.DeepEquals, map[string]interface{}{
+		"packages": []interface{}{"jq"},
+	})
+}
+
+func (*instancecfgSuite) TestFinishInstanceConfigAgentDataDirForSeries(c *gc.C) {
+	for i, test := range []struct {
+		series  string
+		dataDir string
+		expect  string
+	}{
+		{"xenial", "/srv/juju", "/srv/juju"},
+		{"xenial", `C:\Juju\lib`, "/var/lib/juju"},
+		{"win2012r2", `C:\Juju\lib`, `C:\Juju\lib`},
+		{"win2012r2", "/srv/juju", "C:/Juju/lib/juju"},
+	} {
+		c.Logf("test %d: %s on %s", i, test.dataDir, test.series)
+		cfg := testing.CustomModelConfig(c, testing.Attrs{
+			"agent-datadir": test.dataDir,
+		})
+		icfg, err := instancecfg.NewInstanceConfig(testing.ControllerTag, "1", "nonce", "released", test.series, nil)
+		c.Assert(err, jc.ErrorIsNil)
+		err = instancecfg.FinishInstanceConfig(icfg, cfg)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(icfg.DataDir, gc.Equals, test.expect)
+	}
+}
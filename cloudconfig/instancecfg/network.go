@@ -0,0 +1,85 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancecfg
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+)
+
+// NetworkBinding describes how a single network interface of a new
+// instance is connected, so that cloud-init can configure addressing
+// on machines with more than one NIC.
+type NetworkBinding struct {
+	// InterfaceName is the name of the interface, e.g. "eth1".
+	InterfaceName string
+
+	// MACAddress optionally holds the hardware address of the
+	// interface, used to match it where supported.
+	MACAddress string
+
+	// Space is the name of the space the interface is bound to.
+	Space string
+
+	// SubnetCIDR is the CIDR of the subnet the interface is on.
+	SubnetCIDR string
+
+	// Address holds the static address of the interface within the
+	// subnet. If empty, the interface is configured using DHCP.
+	Address string
+
+	// GatewayAddress optionally holds the default gateway to use
+	// via this interface.
+	GatewayAddress string
+
+	// DNSServers optionally holds the DNS servers to use via this
+	// interface.
+	DNSServers []string
+}
+
+// Validate returns an error if the binding is not valid.
+func (b NetworkBinding) Validate() error {
+	if b.InterfaceName == "" {
+		return errors.NotValidf("network binding with empty interface name")
+	}
+	_, subnet, err := net.ParseCIDR(b.SubnetCIDR)
+	if err != nil {
+		return errors.NotValidf("subnet %q for interface %q", b.SubnetCIDR, b.InterfaceName)
+	}
+	if b.Address != "" {
+		ip := net.ParseIP(b.Address)
+		if ip == nil || !subnet.Contains(ip) {
+			return errors.NotValidf("address %q for interface %q in subnet %q", b.Address, b.InterfaceName, b.SubnetCIDR)
+		}
+	}
+	if b.GatewayAddress != "" && net.ParseIP(b.GatewayAddress) == nil {
+		return errors.NotValidf("gateway address %q for interface %q", b.GatewayAddress, b.InterfaceName)
+	}
+	for _, server := range b.DNSServers {
+		if net.ParseIP(server) == nil {
+			return errors.NotValidf("DNS server %q for interface %q", server, b.InterfaceName)
+		}
+	}
+	return nil
+}
+
+// IsStatic reports whether the interface has a static address.
+func (b NetworkBinding) IsStatic() bool {
+	return b.Address != ""
+}
+
+// CIDRAddress returns the static address of the interface in CIDR
+// notation, e.g. "10.0.1.5/24", or "" if it is configured using DHCP.
+func (b NetworkBinding) CIDRAddress() string {
+	if b.Address == "" {
+		return ""
+	}
+	_, subnet, err := net.ParseCIDR(b.SubnetCIDR)
+	if err != nil {
+		return ""
+	}
+	ones, _ := subnet.Mask.Size()
+	return (&net.IPNet{IP: net.ParseIP(b.Address), Mask: net.CIDRMask(ones, len(subnet.Mask)*8)}).String()
+}
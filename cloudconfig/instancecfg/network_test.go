@@ -0,0 +1,65 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancecfg_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/testing"
+)
+
+type networkBindingSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&networkBindingSuite{})
+
+func (*networkBindingSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		binding instancecfg.NetworkBinding
+		err     string
+	}{{
+		binding: instancecfg.NetworkBinding{InterfaceName: "eth0", SubnetCIDR: "10.0.0.0/24"},
+	}, {
+		binding: instancecfg.NetworkBinding{
+			InterfaceName:  "eth1",
+			SubnetCIDR:     "10.0.1.0/24",
+			Address:        "10.0.1.5",
+			GatewayAddress: "10.0.1.1",
+			DNSServers:     []string{"10.0.1.2"},
+		},
+	}, {
+		binding: instancecfg.NetworkBinding{SubnetCIDR: "10.0.0.0/24"},
+		err:     "network binding with empty interface name not valid",
+	}, {
+		binding: instancecfg.NetworkBinding{InterfaceName: "eth0", SubnetCIDR: "bad"},
+		err:     `subnet "bad" for interface "eth0" not valid`,
+	}, {
+		binding: instancecfg.NetworkBinding{InterfaceName: "eth0", SubnetCIDR: "10.0.0.0/24", Address: "10.0.1.5"},
+		err:     `address "10.0.1.5" for interface "eth0" in subnet "10.0.0.0/24" not valid`,
+	}, {
+		binding: instancecfg.NetworkBinding{InterfaceName: "eth0", SubnetCIDR: "10.0.0.0/24", DNSServers: []string{"dns"}},
+		err:     `DNS server "dns" for interface "eth0" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := test.binding.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (*networkBindingSuite) TestCIDRAddress(c *gc.C) {
+	binding := instancecfg.NetworkBinding{InterfaceName: "eth0", SubnetCIDR: "10.0.1.0/24", Address: "10.0.1.5"}
+	c.Assert(binding.IsStatic(), jc.IsTrue)
+	c.Assert(binding.CIDRAddress(), gc.Equals, "10.0.1.5/24")
+
+	binding.Address = ""
+	c.Assert(binding.IsStatic(), jc.IsFalse)
+	c.Assert(binding.CIDRAddress(), gc.Equals, "")
+}
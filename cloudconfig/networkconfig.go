@@ -0,0 +1,161 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudconfig
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+)
+
+const (
+	// networkBindingsENIFile and networkBindingsNetplanFile are the
+	// names, relative to the data dir, of the rendered network
+	// configuration for the instance's network bindings. Only one of
+	// them is installed, depending on what the image supports.
+	networkBindingsENIFile     = "network-bindings.cfg"
+	networkBindingsNetplanFile = "network-bindings.yaml"
+
+	// networkBindingsENIPath and networkBindingsNetplanPath are where
+	// the rendered configuration is installed.
+	networkBindingsENIPath     = "/etc/network/interfaces.d/60-juju.cfg"
+	networkBindingsNetplanPath = "/etc/netplan/60-juju.yaml"
+)
+
+// addNetworkBindingsConfig adds commands to configure the interfaces
+// described by the instance's network bindings. Both netplan and
+// ENI configuration is written, and netplan is preferred when the
+// image has it.
+func (w *unixConfigure) addNetworkBindingsConfig() error {
+	bindings := w.icfg.NetworkBindings
+	if len(bindings) == 0 {
+		return nil
+	}
+	netplan, err := renderNetplanConfig(bindings)
+	if err != nil {
+		return err
+	}
+	eniFile := path.Join(w.icfg.DataDir, networkBindingsENIFile)
+	netplanFile := path.Join(w.icfg.DataDir, networkBindingsNetplanFile)
+	w.conf.AddRunTextFile(eniFile, renderENIConfig(bindings), 0644)
+	w.conf.AddRunTextFile(netplanFile, netplan, 0644)
+
+	names := make([]string, len(bindings))
+	for i, binding := range bindings {
+		names[i] = utils.ShQuote(binding.InterfaceName)
+	}
+	w.conf.AddScripts(
+		"if command -v netplan >/dev/null 2>&1; then " +
+			fmt.Sprintf("install -D -m 644 %s %s && netplan apply; ", netplanFile, networkBindingsNetplanPath) +
+			"else " +
+			fmt.Sprintf("install -D -m 644 %s %s && ", eniFile, networkBindingsENIPath) +
+			restartInterfacesScript(names) +
+			"fi",
+	)
+	return nil
+}
+
+// restartInterfacesScript returns a script that brings up each of the
+// given interfaces with its new configuration. The primary interface,
+// which carries the default route (and through which cloud-init and
+// the agent reach the controller), is never taken down, and
+// interfaces that the instance does not have are skipped.
+func restartInterfacesScript(quotedNames []string) string {
+	return `primary=$(ip route show default 2>/dev/null | awk '{for (i = 1; i < NF; i++) if ($i == "dev") {print $(i+1); exit}}'); ` +
+		`primary=${primary:-eth0}; ` +
+		fmt.Sprintf("for iface in %s; do ", strings.Join(quotedNames, " ")) +
+		`if [ "$iface" = "$primary" ] || [ ! -e "/sys/class/net/$iface" ]; then continue; fi; ` +
+		`ifdown "$iface" || true; ifup "$iface"; ` +
+		"done; "
+}
+
+// renderENIConfig returns /etc/network/interfaces stanzas for the
+// given network bindings.
+func renderENIConfig(bindings []instancecfg.NetworkBinding) string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "# Generated by juju from the machine's network bindings.")
+	for _, binding := range bindings {
+		name := binding.InterfaceName
+		fmt.Fprintf(&buf, "\nauto %s\n", name)
+		if !binding.IsStatic() {
+			fmt.Fprintf(&buf, "iface %s inet dhcp\n", name)
+			continue
+		}
+		fmt.Fprintf(&buf, "iface %s inet static\n", name)
+		fmt.Fprintf(&buf, "    address %s\n", binding.CIDRAddress())
+		if binding.GatewayAddress != "" {
+			fmt.Fprintf(&buf, "    gateway %s\n", binding.GatewayAddress)
+		}
+		if len(binding.DNSServers) > 0 {
+			fmt.Fprintf(&buf, "    dns-nameservers %s\n", strings.Join(binding.DNSServers, " "))
+		}
+	}
+	return buf.String()
+}
+
+type netplanConfig struct {
+	Network netplanNetwork `yaml:"network"`
+}
+
+type netplanNetwork struct {
+	Version   int                        `yaml:"version"`
+	Ethernets map[string]netplanEthernet `yaml:"ethernets"`
+}
+
+type netplanEthernet struct {
+	Match       *netplanMatch       `yaml:"match,omitempty"`
+	SetName     string              `yaml:"set-name,omitempty"`
+	DHCP4       bool                `yaml:"dhcp4"`
+	Addresses   []string            `yaml:"addresses,omitempty"`
+	Gateway4    string              `yaml:"gateway4,omitempty"`
+	Nameservers *netplanNameservers `yaml:"nameservers,omitempty"`
+}
+
+type netplanMatch struct {
+	MACAddress string `yaml:"macaddress"`
+}
+
+type netplanNameservers struct {
+	Addresses []string `yaml:"addresses"`
+}
+
+// renderNetplanConfig returns netplan YAML for the given network
+// bindings. Interfaces with a known MAC address are matched on it,
+// so they are named as expected regardless of probe order.
+func renderNetplanConfig(bindings []instancecfg.NetworkBinding) (string, error) {
+	config := netplanConfig{
+		Network: netplanNetwork{
+			Version:   2,
+			Ethernets: make(map[string]netplanEthernet),
+		},
+	}
+	for _, binding := range bindings {
+		var ethernet netplanEthernet
+		if binding.MACAddress != "" {
+			ethernet.Match = &netplanMatch{MACAddress: binding.MACAddress}
+			ethernet.SetName = binding.InterfaceName
+		}
+		if binding.IsStatic() {
+			ethernet.Addresses = []string{binding.CIDRAddress()}
+			ethernet.Gateway4 = binding.GatewayAddress
+		} else {
+			ethernet.DHCP4 = true
+		}
+		if len(binding.DNSServers) > 0 {
+			ethernet.Nameservers = &netplanNameservers{Addresses: binding.DNSServers}
+		}
+		config.Network.Ethernets[binding.InterfaceName] = ethernet
+	}
+	data, err := goyaml.Marshal(&config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
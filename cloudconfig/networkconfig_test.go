@@ -0,0 +1,72 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudconfig_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/testing"
+)
+
+type networkConfigSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&networkConfigSuite{})
+
+var testNetworkBindings = []instancecfg.NetworkBinding{{
+	InterfaceName: "eth0",
+	Space:         "default",
+	SubnetCIDR:    "10.0.0.0/24",
+}, {
+	InterfaceName:  "eth1",
+	MACAddress:     "aa:bb:cc:dd:ee:f1",
+	Space:          "storage",
+	SubnetCIDR:     "10.0.1.0/24",
+	Address:        "10.0.1.5",
+	GatewayAddress: "10.0.1.1",
+	DNSServers:     []string{"10.0.1.2", "10.0.1.3"},
+}}
+
+func (*networkConfigSuite) TestRenderENIConfig(c *gc.C) {
+	c.Assert(cloudconfig.RenderENIConfig(testNetworkBindings), gc.Equals, `
+# Generated by juju from the machine's network bindings.
+
+auto eth0
+iface eth0 inet dhcp
+
+auto eth1
+iface eth1 inet static
+    address 10.0.1.5/24
+    gateway 10.0.1.1
+    dns-nameservers 10.0.1.2 10.0.1.3
+`[1:])
+}
+
+func (*networkConfigSuite) TestRenderNetplanConfig(c *gc.C) {
+	out, err := cloudconfig.RenderNetplanConfig(testNetworkBindings)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+network:
+  version: 2
+  ethernets:
+    eth0:
+      dhcp4: true
+    eth1:
+      match:
+        macaddress: aa:bb:cc:dd:ee:f1
+      set-name: eth1
+      dhcp4: false
+      addresses:
+      - 10.0.1.5/24
+      gateway4: 10.0.1.1
+      nameservers:
+        addresses:
+        - 10.0.1.2
+        - 10.0.1.3
+`[1:])
+}
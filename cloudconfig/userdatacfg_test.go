@@ -1129,6 +1129,99 @@ func (s *cloudinitSuite) TestAptProxyNotWrittenIfNotSet(c *gc.C) {
 	c.Assert(cmds, gc.IsNil)
 }
 
+func (s *cloudinitSuite) TestCloudInitUserData(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"cloudinit-userdata": "packages: [jq]\npreruncmd:\n  - echo hello\n",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.ConfigureBasic()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(cloudcfg.Packages(), jc.DeepEquals, []string{"jq"})
+	data, err := cloudcfg.RenderYAML()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "preruncmd:\n- echo hello\n")
+}
+
+func (s *cloudinitSuite) TestAgentResourceLimits(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	environConfig, err := environConfig.Apply(map[string]interface{}{
+		"agent-cpu-quota":    80,
+		"agent-memory-limit": 512,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	apiInfo := jujutesting.FakeAPIInfo("42")
+	instanceCfg, err := instancecfg.NewInstanceConfig(testing.ControllerTag, "42", "fake-nonce", imagemetadata.ReleasedStream, "xenial", apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg.SetTools(tools.List{
+		&tools.Tools{
+			Version: version.MustParseBinary("2.3.4-xenial-amd64"),
+			URL:     "http://tools.testing.invalid/2.3.4-xenial-amd64.tgz",
+		},
+	})
+	err = instancecfg.FinishInstanceConfig(instanceCfg, environConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudcfg, err := cloudinit.New("xenial")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := cloudcfg.RenderYAML()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "Slice=juju-agents.slice")
+	c.Assert(string(data), jc.Contains, "CPUQuota=80%")
+	c.Assert(string(data), jc.Contains, "MemoryLimit=512M")
+}
+
+func (s *cloudinitSuite) TestNetworkBindings(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	apiInfo := jujutesting.FakeAPIInfo("42")
+	instanceCfg, err := instancecfg.NewInstanceConfig(testing.ControllerTag, "42", "fake-nonce", imagemetadata.ReleasedStream, "xenial", apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceCfg.SetTools(tools.List{
+		&tools.Tools{
+			Version: version.MustParseBinary("2.3.4-xenial-amd64"),
+			URL:     "http://tools.testing.invalid/2.3.4-xenial-amd64.tgz",
+		},
+	})
+	instanceCfg.NetworkBindings = []instancecfg.NetworkBinding{{
+		InterfaceName: "eth1",
+		Space:         "storage",
+		SubnetCIDR:    "10.0.1.0/24",
+	}}
+	err = instancecfg.FinishInstanceConfig(instanceCfg, environConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudcfg, err := cloudinit.New("xenial")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.ConfigureBasic()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := cloudcfg.RenderYAML()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "iface eth1 inet dhcp")
+	c.Assert(string(data), jc.Contains, "install -D -m 644 /var/lib/juju/network-bindings.yaml /etc/netplan/60-juju.yaml && netplan apply")
+	// The primary interface is never restarted.
+	var found bool
+	for _, cmd := range cloudcfg.RunCmds() {
+		if strings.Contains(cmd, "for iface in 'eth1'; do") {
+			found = true
+			c.Check(cmd, jc.Contains, `if [ "$iface" = "$primary" ]`)
+			c.Check(cmd, jc.Contains, `primary=${primary:-eth0}`)
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
 func (s *cloudinitSuite) TestAptProxyWritten(c *gc.C) {
 	environConfig := minimalModelConfig(c)
 	environConfig, err := environConfig.Apply(map[string]interface{}{
@@ -125,6 +125,16 @@ func (w *unixConfigure) ConfigureBasic() error {
 	}
 	SetUbuntuUser(w.conf, w.icfg.AuthorizedKeys)
 	w.conf.SetOutput(cloudinit.OutAll, "| tee -a "+w.icfg.CloudInitOutputLog, "")
+	if len(w.icfg.NetworkBindings) > 0 {
+		if w.os == os.Ubuntu {
+			if err := w.addNetworkBindingsConfig(); err != nil {
+				return errors.Annotate(err, "cannot render network bindings")
+			}
+		} else {
+			logger.Warningf("ignoring network bindings on %v", w.os)
+		}
+	}
+	w.addCloudInitUserData()
 	// Create a file in a well-defined location containing the machine's
 	// nonce. The presence and contents of this file will be verified
 	// during bootstrap.
@@ -137,6 +147,24 @@ func (w *unixConfigure) ConfigureBasic() error {
 	return nil
 }
 
+// addCloudInitUserData merges the extra user-data from the model's
+// cloudinit-userdata config into the cloud-init config. Sections juju
+// manages itself were rejected when the config was validated.
+func (w *unixConfigure) addCloudInitUserData() {
+	for key, value := range w.icfg.CloudInitUserData {
+		if key != "packages" {
+			w.conf.SetAttr(key, value)
+			continue
+		}
+		packages, _ := value.([]interface{})
+		for _, pkg := range packages {
+			if name, ok := pkg.(string); ok {
+				w.conf.AddPackage(name)
+			}
+		}
+	}
+}
+
 func (w *unixConfigure) addCleanShutdownJob(initSystem string) {
 	switch initSystem {
 	case service.InitSystemUpstart:
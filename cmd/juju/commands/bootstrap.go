@@ -91,6 +91,14 @@ use in all models for this controller. The full binary version is accepted
 (e.g.: 2.0.1-xenial-amd64) but only the numeric version (e.g.: 2.0.1) is
 used. Otherwise, by default, the version used is that of the client.
 
+To register the new controller with an external inventory, use
+'--post-bootstrap-webhook' and/or '--post-bootstrap-command'. Once the
+controller is ready, a JSON report holding the controller name and UUID,
+default model UUID, cloud, region, API endpoints and controller instance
+ID is POSTed to the webhook URL, and passed on standard input to the
+command. Failed hooks are retried, and then reported as warnings without
+failing the bootstrap.
+
 Examples:
     juju bootstrap
     juju bootstrap --clouds
@@ -101,6 +109,7 @@ Examples:
     juju bootstrap --config=~/config-rs.yaml rackspace joe-syd
     juju bootstrap --config agent-version=1.25.3 aws joe-us-east-1
     juju bootstrap --config bootstrap-timeout=1200 azure joe-eastus
+    juju bootstrap --post-bootstrap-webhook https://cmdb.example.com/juju aws
 
 See also:
     add-credentials
@@ -148,6 +157,8 @@ type bootstrapCommand struct {
 	Region              string
 	noGUI               bool
 	interactive         bool
+	postBootstrapURL    string
+	postBootstrapCmd    string
 }
 
 func (c *bootstrapCommand) Info() *cmd.Info {
@@ -181,6 +192,8 @@ func (c *bootstrapCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.noGUI, "no-gui", false, "Do not install the Juju GUI in the controller when bootstrapping")
 	f.BoolVar(&c.showClouds, "clouds", false, "Print the available clouds which can be used to bootstrap a Juju environment")
 	f.StringVar(&c.showRegionsForCloud, "regions", "", "Print the available regions for the specified cloud")
+	f.StringVar(&c.postBootstrapURL, "post-bootstrap-webhook", "", "URL to POST the bootstrap report to once the controller is ready")
+	f.StringVar(&c.postBootstrapCmd, "post-bootstrap-command", "", "Command to run with the bootstrap report on stdin once the controller is ready")
 }
 
 func (c *bootstrapCommand) Init(args []string) (err error) {
@@ -543,6 +556,10 @@ See `[1:] + "`juju kill-controller`" + `.`)
 		credentials.name = credentials.detectedName
 	}
 
+	caPrivateKey, err := config.bootstrap.ReadCAPrivateKey()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	bootstrapFuncs := getBootstrapFuncs()
 	err = bootstrapFuncs.Bootstrap(modelcmd.BootstrapContext(ctx), environ, bootstrap.BootstrapParams{
 		ModelConstraints:          c.Constraints,
@@ -564,7 +581,7 @@ See `[1:] + "`juju kill-controller`" + `.`)
 		HostedModelConfig:         hostedModelConfig,
 		GUIDataSourceBaseURL:      guiDataSourceBaseURL,
 		AdminSecret:               config.bootstrap.AdminSecret,
-		CAPrivateKey:              config.bootstrap.CAPrivateKey,
+		CAPrivateKey:              caPrivateKey,
 		DialOpts: environs.BootstrapDialOpts{
 			Timeout:        config.bootstrap.BootstrapTimeout,
 			RetryDelay:     config.bootstrap.BootstrapRetryDelay,
@@ -591,7 +608,28 @@ See `[1:] + "`juju kill-controller`" + `.`)
 	// To avoid race conditions when running scripted bootstraps, wait
 	// for the controller's machine agent to be ready to accept commands
 	// before exiting this bootstrap command.
-	return waitForAgentInitialisation(ctx, &c.ModelCommandBase, c.controllerName, c.hostedModelName)
+	if err := waitForAgentInitialisation(ctx, &c.ModelCommandBase, c.controllerName, c.hostedModelName); err != nil {
+		return errors.Trace(err)
+	}
+	c.runPostBootstrapHooks(environ, hostedModelUUID.String(), cloud.Name, region.Name)
+	return nil
+}
+
+// runPostBootstrapHooks reports the new controller to any configured
+// post-bootstrap hooks. Failures are logged as warnings, since the
+// controller itself was bootstrapped successfully.
+func (c *bootstrapCommand) runPostBootstrapHooks(environ environs.Environ, hostedModelUUID, cloud, region string) {
+	if c.postBootstrapURL == "" && c.postBootstrapCmd == "" {
+		return
+	}
+	report, err := newBootstrapReport(c.ClientStore(), c.controllerName, hostedModelUUID, cloud, region, environ)
+	if err != nil {
+		logger.Warningf("cannot run post-bootstrap hooks: %v", err)
+		return
+	}
+	for _, warning := range runPostBootstrapHooks(report, c.postBootstrapURL, c.postBootstrapCmd) {
+		logger.Warningf("%s", warning)
+	}
 }
 
 func (c *bootstrapCommand) handleCommandLineErrorsAndInfoRequests(ctx *cmd.Context) (bool, error) {
@@ -928,8 +966,12 @@ func (c *bootstrapCommand) bootstrapConfigs(
 	if err != nil {
 		return bootstrapConfigs{}, errors.Annotate(err, "constructing bootstrap config")
 	}
+	caCert, err := bootstrapConfig.ReadCACert()
+	if err != nil {
+		return bootstrapConfigs{}, errors.Trace(err)
+	}
 	controllerConfig, err := controller.NewConfig(
-		controllerUUID.String(), bootstrapConfig.CACert, controllerConfigAttrs,
+		controllerUUID.String(), caCert, controllerConfigAttrs,
 	)
 	if err != nil {
 		return bootstrapConfigs{}, errors.Annotate(err, "constructing controller config")
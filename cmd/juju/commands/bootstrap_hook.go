@@ -0,0 +1,148 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/jujuclient"
+)
+
+// bootstrapReport describes a newly bootstrapped controller. It is
+// passed, encoded as JSON, to any post-bootstrap hooks so that
+// external inventories can record the new controller.
+type bootstrapReport struct {
+	ControllerName   string   `json:"controller-name"`
+	ControllerUUID   string   `json:"controller-uuid"`
+	DefaultModelUUID string   `json:"default-model-uuid"`
+	Cloud            string   `json:"cloud"`
+	Region           string   `json:"region,omitempty"`
+	APIEndpoints     []string `json:"api-endpoints"`
+	InstanceId       string   `json:"instance-id"`
+}
+
+// postBootstrapHookAttempts is the strategy used to retry failed
+// post-bootstrap hooks.
+var postBootstrapHookAttempts = utils.AttemptStrategy{
+	Total: 1 * time.Minute,
+	Delay: 5 * time.Second,
+	Min:   3,
+}
+
+// postBootstrapWebhookClient is the HTTP client used to call the
+// post-bootstrap webhook. Its timeout ensures that a slow or
+// unresponsive endpoint cannot hang the bootstrap.
+var postBootstrapWebhookClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// newBootstrapReport gathers the details of the newly bootstrapped
+// controller from the client store and the environ.
+func newBootstrapReport(
+	store jujuclient.ControllerStore,
+	controllerName, defaultModelUUID, cloud, region string,
+	environ environs.Environ,
+) (*bootstrapReport, error) {
+	details, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids, err := environ.ControllerInstances(details.ControllerUUID)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting controller instances")
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("found no controller instances")
+	}
+	return &bootstrapReport{
+		ControllerName:   controllerName,
+		ControllerUUID:   details.ControllerUUID,
+		DefaultModelUUID: defaultModelUUID,
+		Cloud:            cloud,
+		Region:           region,
+		APIEndpoints:     details.APIEndpoints,
+		InstanceId:       string(ids[0]),
+	}, nil
+}
+
+// runPostBootstrapHooks sends the bootstrap report to the configured
+// webhook and command, retrying each on failure. Hook failures do not
+// fail the bootstrap, so they are returned as warnings instead.
+func runPostBootstrapHooks(report *bootstrapReport, webhookURL, command string) []string {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot encode bootstrap report: %v", err)}
+	}
+	var warnings []string
+	if webhookURL != "" {
+		err := retryPostBootstrapHook(func() error {
+			return postBootstrapWebhook(webhookURL, payload)
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("post-bootstrap webhook %q failed: %v", webhookURL, err))
+		}
+	}
+	if command != "" {
+		err := retryPostBootstrapHook(func() error {
+			return runPostBootstrapCommand(command, payload)
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("post-bootstrap command %q failed: %v", command, err))
+		}
+	}
+	return warnings
+}
+
+func retryPostBootstrapHook(hook func() error) error {
+	var err error
+	for a := postBootstrapHookAttempts.Start(); a.Next(); {
+		if err = hook(); err == nil {
+			return nil
+		}
+		logger.Debugf("post-bootstrap hook failed: %v", err)
+	}
+	return err
+}
+
+// postBootstrapWebhook POSTs the bootstrap report to the given URL.
+func postBootstrapWebhook(url string, payload []byte) error {
+	resp, err := postBootstrapWebhookClient.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := fmt.Sprintf("bad HTTP response: %v", resp.Status)
+		if body, err := ioutil.ReadAll(resp.Body); err == nil && len(body) > 0 {
+			msg += fmt.Sprintf(" (%s)", bytes.TrimSpace(body))
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// runPostBootstrapCommand runs the given shell command with the
+// bootstrap report on its standard input.
+func runPostBootstrapCommand(command string, payload []byte) error {
+	cmd := exec.Command("/bin/sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(payload)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if len(out) > 0 {
+			return errors.Errorf("%v (%s)", err, bytes.TrimSpace(out))
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+)
+
+type postBootstrapHookSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&postBootstrapHookSuite{})
+
+var testBootstrapReport = &bootstrapReport{
+	ControllerName:   "ctrl",
+	ControllerUUID:   coretesting.ControllerTag.Id(),
+	DefaultModelUUID: coretesting.ModelTag.Id(),
+	Cloud:            "aws",
+	Region:           "us-east-1",
+	APIEndpoints:     []string{"10.0.0.1:17070"},
+	InstanceId:       "i-0",
+}
+
+func (s *postBootstrapHookSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&postBootstrapHookAttempts, utils.AttemptStrategy{Min: 3})
+}
+
+func (s *postBootstrapHookSuite) TestWebhook(c *gc.C) {
+	var received []*bootstrapReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, gc.Equals, "POST")
+		c.Check(r.Header.Get("Content-Type"), gc.Equals, "application/json")
+		var report bootstrapReport
+		c.Check(json.NewDecoder(r.Body).Decode(&report), jc.ErrorIsNil)
+		received = append(received, &report)
+	}))
+	defer server.Close()
+
+	warnings := runPostBootstrapHooks(testBootstrapReport, server.URL, "")
+	c.Assert(warnings, gc.HasLen, 0)
+	c.Assert(received, jc.DeepEquals, []*bootstrapReport{testBootstrapReport})
+}
+
+func (s *postBootstrapHookSuite) TestWebhookRetries(c *gc.C) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	warnings := runPostBootstrapHooks(testBootstrapReport, server.URL, "")
+	c.Assert(warnings, gc.HasLen, 0)
+	c.Assert(calls, gc.Equals, 3)
+}
+
+func (s *postBootstrapHookSuite) TestWebhookFailureIsWarning(c *gc.C) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	warnings := runPostBootstrapHooks(testBootstrapReport, server.URL, "")
+	c.Assert(calls, gc.Equals, 3)
+	c.Assert(warnings, gc.HasLen, 1)
+	c.Assert(warnings[0], gc.Matches, `post-bootstrap webhook ".*" failed: bad HTTP response: 500 Internal Server Error \(boom\)`)
+}
+
+func (s *postBootstrapHookSuite) TestWebhookTimeout(c *gc.C) {
+	s.PatchValue(&postBootstrapWebhookClient, &http.Client{Timeout: 10 * time.Millisecond})
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	warnings := runPostBootstrapHooks(testBootstrapReport, server.URL, "")
+	c.Assert(warnings, gc.HasLen, 1)
+	c.Assert(warnings[0], gc.Matches, `post-bootstrap webhook ".*" failed: .*Client.Timeout exceeded.*`)
+}
+
+func (s *postBootstrapHookSuite) TestCommand(c *gc.C) {
+	out := filepath.Join(c.MkDir(), "report.json")
+	warnings := runPostBootstrapHooks(testBootstrapReport, "", "cat > "+out)
+	c.Assert(warnings, gc.HasLen, 0)
+
+	data, err := ioutil.ReadFile(out)
+	c.Assert(err, jc.ErrorIsNil)
+	var report bootstrapReport
+	err = json.Unmarshal(data, &report)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(&report, jc.DeepEquals, testBootstrapReport)
+}
+
+func (s *postBootstrapHookSuite) TestCommandFailureIsWarning(c *gc.C) {
+	warnings := runPostBootstrapHooks(testBootstrapReport, "", "echo oops; exit 1")
+	c.Assert(warnings, jc.DeepEquals, []string{
+		`post-bootstrap command "echo oops; exit 1" failed: exit status 1 (oops)`,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/juju/ansiterm"
@@ -49,13 +50,17 @@ The '--include-module' and '--exclude-module' options filter by (dotted)
 logging module name. The module name can be truncated such that all loggers
 with the prefix will match.
 
+The '--match' option shows only messages matching a regular expression.
+Filtering is done by the controller, so messages that do not match are
+never sent to the client.
+
 The filtering options combine as follows:
 * All --include options are logically ORed together.
 * All --exclude options are logically ORed together.
 * All --include-module options are logically ORed together.
 * All --exclude-module options are logically ORed together.
-* The combined --include, --exclude, --include-module and --exclude-module
-  selections are logically ANDed to form the complete filter.
+* The combined --include, --exclude, --include-module, --exclude-module
+  and --match selections are logically ANDed to form the complete filter.
 
 Examples:
 
@@ -89,6 +94,10 @@ machine-3 or machine-4, and then stop:
         --exclude machine-3 \
         --exclude machine-4 
 
+Show all messages mentioning a hook failure, and then stop:
+
+    juju debug-log --replay --no-tail --match 'hook ".*" failed'
+
 To see all WARNING and ERROR messages and then continue showing any
 new WARNING and ERROR messages as they are logged:
 
@@ -141,6 +150,7 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeEntity), "exclude", "Do not show log messages for these entities")
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeModule), "include-module", "Only show log messages for these logging modules")
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeModule), "exclude-module", "Do not show log messages for these logging modules")
+	f.StringVar(&c.params.MessageRegexp, "match", "", "Only show log messages matching this regular expression")
 
 	f.StringVar(&c.level, "l", "", "Log level to show, one of [TRACE, DEBUG, INFO, WARNING, ERROR]")
 	f.StringVar(&c.level, "level", "", "")
@@ -169,6 +179,11 @@ func (c *debugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	if c.params.MessageRegexp != "" {
+		if _, err := regexp.Compile(c.params.MessageRegexp); err != nil {
+			return errors.Annotate(err, "invalid --match regular expression")
+		}
+	}
 	if c.tail && c.notail {
 		return errors.NotValidf("setting --tail and --no-tail")
 	}
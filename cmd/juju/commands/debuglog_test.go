@@ -71,6 +71,15 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				ExcludeModule: []string{"juju.foo", "unit"},
 				Backlog:       10,
 			},
+		}, {
+			args: []string{"--match", "hook .* failed"},
+			expected: common.DebugLogParams{
+				MessageRegexp: "hook .* failed",
+				Backlog:       10,
+			},
+		}, {
+			args:     []string{"--match", "hook ("},
+			errMatch: `invalid --match regular expression: .*`,
 		}, {
 			args: []string{"--replay"},
 			expected: common.DebugLogParams{
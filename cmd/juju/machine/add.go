@@ -13,6 +13,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/utils/winrm"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/machinemanager"
@@ -71,6 +72,8 @@ Examples:
    juju add-machine --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju add-machine ssh:user@10.10.0.3   (manually provisions machine with ssh)
    juju add-machine winrm:user@10.10.0.3 (manually provisions machine with winrm)
+   juju add-machine ssh:10.10.0.3 --agent-version 2.2.1
+                                         (manually provisions a canary machine running juju 2.2.1)
    juju add-machine zone=us-east-1a      (start a machine in zone us-east-1a on AWS)
    juju add-machine maas2.name           (acquire machine maas2.name on MAAS)
 
@@ -114,6 +117,11 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// If specified, manually provisioned machines are given this version
+	// of juju rather than the model's agent-version.
+	AgentVersion *version.Number
+	// agentVersionStr holds the value of the --agent-version flag.
+	agentVersionStr string
 }
 
 func (c *addCommand) Info() *cmd.Info {
@@ -131,6 +139,7 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Additional machine constraints")
 	f.Var(disksFlag{&c.Disks}, "disks", "Constraints for disks to attach to the machine")
+	f.StringVar(&c.agentVersionStr, "agent-version", "", "The version of juju to install on a manually provisioned machine")
 }
 
 func (c *addCommand) Init(args []string) error {
@@ -152,6 +161,16 @@ func (c *addCommand) Init(args []string) error {
 	if c.NumMachines > 1 && c.Placement != nil && c.Placement.Directive != "" {
 		return errors.New("cannot use -n when specifying a placement directive")
 	}
+	if c.agentVersionStr != "" {
+		if c.Placement == nil || (c.Placement.Scope != sshScope && c.Placement.Scope != winrmScope) {
+			return errors.New("--agent-version can only be used with ssh: or winrm: placement")
+		}
+		agentVersion, err := version.Parse(c.agentVersionStr)
+		if err != nil {
+			return errors.Annotate(err, "invalid --agent-version")
+		}
+		c.AgentVersion = &agentVersion
+	}
 	return nil
 }
 
@@ -371,6 +390,7 @@ func (c *addCommand) tryManualProvision(client AddMachineAPI, config *config.Con
 		Stdout:         ctx.Stdout,
 		Stderr:         ctx.Stderr,
 		AuthorizedKeys: authKeys,
+		AgentVersion:   c.AgentVersion,
 		UpdateBehavior: &params.UpdateBehavior{
 			EnableOSRefreshUpdate: config.EnableOSRefreshUpdate(),
 			EnableOSUpgrade:       config.EnableOSUpgrade(),
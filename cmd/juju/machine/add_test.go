@@ -10,6 +10,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
@@ -38,12 +39,13 @@ func (s *AddMachineSuite) SetUpTest(c *gc.C) {
 
 func (s *AddMachineSuite) TestInit(c *gc.C) {
 	for i, test := range []struct {
-		args        []string
-		series      string
-		constraints string
-		placement   string
-		count       int
-		errorString string
+		args         []string
+		series       string
+		constraints  string
+		placement    string
+		count        int
+		agentVersion string
+		errorString  string
 	}{
 		{
 			count: 1,
@@ -89,6 +91,17 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"something:special"},
 			count:     1,
 			placement: "something:special",
+		}, {
+			args:         []string{"ssh:user@10.10.0.3", "--agent-version", "2.2.1"},
+			count:        1,
+			placement:    "ssh:user@10.10.0.3",
+			agentVersion: "2.2.1",
+		}, {
+			args:        []string{"lxd", "--agent-version", "2.2.1"},
+			errorString: "--agent-version can only be used with ssh: or winrm: placement",
+		}, {
+			args:        []string{"ssh:user@10.10.0.3", "--agent-version", "x"},
+			errorString: `invalid --agent-version: invalid version "x"`,
 		},
 	} {
 		c.Logf("test %d", i)
@@ -104,6 +117,11 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 				c.Check("", gc.Equals, test.placement)
 			}
 			c.Check(addCmd.NumMachines, gc.Equals, test.count)
+			if addCmd.AgentVersion != nil {
+				c.Check(addCmd.AgentVersion.String(), gc.Equals, test.agentVersion)
+			} else {
+				c.Check("", gc.Equals, test.agentVersion)
+			}
 		} else {
 			c.Check(err, gc.ErrorMatches, test.errorString)
 		}
@@ -146,6 +164,18 @@ func (s *AddMachineSuite) TestSSHPlacement(c *gc.C) {
 	c.Assert(testing.Stderr(context), gc.Equals, "created machine 42\n")
 }
 
+func (s *AddMachineSuite) TestSSHPlacementAgentVersion(c *gc.C) {
+	var agentVersion *version.Number
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		agentVersion = args.AgentVersion
+		return "42", nil
+	})
+	_, err := s.run(c, "ssh:10.1.2.3", "--agent-version", "2.2.1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(agentVersion, gc.NotNil)
+	c.Assert(*agentVersion, gc.Equals, version.MustParse("2.2.1"))
+}
+
 func (s *AddMachineSuite) TestSSHPlacementError(c *gc.C) {
 	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
 		return "", errors.New("failed to initialize warp core")
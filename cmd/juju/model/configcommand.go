@@ -261,6 +261,11 @@ func (c *configCommand) setConfig(client configCommandAPI, ctx *cmd.Context) err
 	if err != nil {
 		return err
 	}
+	values, warnings := config.MigrateDeprecatedAttributes(c.values)
+	for _, warning := range warnings {
+		logger.Warningf("%s", warning)
+	}
+	c.values = values
 	for key := range c.values {
 		if _, exists := envAttrs[key]; !exists {
 			logger.Warningf("key %q is not defined in the current model configuration: possible misspelling", key)
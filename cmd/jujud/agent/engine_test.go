@@ -47,6 +47,7 @@ var (
 		"migration-fortress",
 		"migration-inactive-flag",
 		"migration-master",
+		"object-storage-sweeper",
 		"application-scaler",
 		"space-importer",
 		"state-cleaner",
@@ -80,6 +81,7 @@ var (
 	}
 	notMigratingUnitWorkers = []string{
 		"api-address-updater",
+		"ca-cert-updater",
 		"charm-dir",
 		"hook-retry-strategy",
 		"leadership-tracker",
@@ -110,6 +112,7 @@ var (
 	}
 	notMigratingMachineWorkers = []string{
 		"api-address-updater",
+		"ca-cert-updater",
 		"disk-manager",
 		// "host-key-reporter", not stable, exits when done
 		"log-sender",
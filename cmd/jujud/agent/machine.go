@@ -35,6 +35,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
 	"gopkg.in/natefinch/lumberjack.v2"
 	"gopkg.in/tomb.v1"
 
@@ -71,7 +72,9 @@ import (
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/lease"
 	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/state/sessionmetrics"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/state/statemetrics"
 	"github.com/juju/juju/storage/looputil"
@@ -224,7 +227,7 @@ func (a *machineAgentCmd) Init(args []string) error {
 	// the context's stderr is set as the loggo writer in github.com/juju/cmd/logging.go
 	a.ctx.Stderr = &lumberjack.Logger{
 		Filename:   agent.LogFilename(a.currentConfig.CurrentConfig()),
-		MaxSize:    300, // megabytes
+		MaxSize:    agent.MaxLogSizeMB(a.currentConfig.CurrentConfig()), // megabytes
 		MaxBackups: 2,
 	}
 
@@ -309,6 +312,8 @@ func NewMachineAgent(
 		newIntrospectionSocketName:  newIntrospectionSocketName,
 		prometheusRegistry:          prometheusRegistry,
 		txnmetricsCollector:         txnmetrics.New(),
+		leaseMetricsCollector:       state.NewLeaseMetricsCollector(),
+		sessionMetricsCollector:     sessionmetrics.New(state.GetMongoSessionStats),
 		preUpgradeSteps:             preUpgradeSteps,
 		statePool:                   &statePoolHolder{},
 	}
@@ -320,9 +325,23 @@ func NewMachineAgent(
 	if err := a.prometheusRegistry.Register(a.txnmetricsCollector); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := a.prometheusRegistry.Register(a.leaseMetricsCollector); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := a.prometheusRegistry.Register(a.sessionMetricsCollector); err != nil {
+		return nil, errors.Trace(err)
+	}
+	state.EnableMongoSessionStats()
 	return a, nil
 }
 
+// afterRunTransaction is the state.RunTransactionObserverFunc that
+// updates the machine agent's transaction and lease metrics.
+func (a *MachineAgent) afterRunTransaction(dbName, modelUUID string, ops []txn.Op, err error) {
+	a.txnmetricsCollector.AfterRunTransaction(dbName, modelUUID, ops, err)
+	a.leaseMetricsCollector.AfterRunTransaction(dbName, modelUUID, ops, err)
+}
+
 // MachineAgent is responsible for tying together all functionality
 // needed to orchestrate a Jujud instance which controls a machine.
 type MachineAgent struct {
@@ -355,6 +374,8 @@ type MachineAgent struct {
 	newIntrospectionSocketName func(names.Tag) string
 	prometheusRegistry         *prometheus.Registry
 	txnmetricsCollector        *txnmetrics.Collector
+	leaseMetricsCollector      *lease.MetricsCollector
+	sessionMetricsCollector    *sessionmetrics.Collector
 	preUpgradeSteps            upgrades.PreUpgradeStepsFunc
 
 	// Only API servers have hubs. This is temporary until the apiserver and
@@ -446,7 +467,7 @@ func upgradeCertificateDNSNames(config agent.ConfigSetter) error {
 	if err != nil {
 		return err
 	}
-	if err := mongo.UpdateSSLKey(config.DataDir(), si.Cert, si.PrivateKey); err != nil {
+	if err := mongo.UpdateSSLKey(config.DataDir(), cert.JoinChain(si.Cert, si.CertChain), si.PrivateKey); err != nil {
 		return err
 	}
 	config.SetStateServingInfo(si)
@@ -460,6 +481,7 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 	if err := a.ReadConfig(a.Tag().String()); err != nil {
 		return errors.Errorf("cannot read agent configuration: %v", err)
 	}
+	agent.ConfigureLogging(a.CurrentConfig())
 
 	logger.Infof("machine agent %v start (%s [%s])", a.Tag(), jujuversion.Current, runtime.Compiler)
 	if flags := featureflag.String(); flags != "" {
@@ -853,7 +875,8 @@ func (a *MachineAgent) openStateForUpgrade() (*state.State, error) {
 		NewPolicy: stateenvirons.GetNewPolicyFunc(
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
-		RunTransactionObserver: a.txnmetricsCollector.AfterRunTransaction,
+		RunTransactionObserver: a.afterRunTransaction,
+		QueryObserver:          a.sessionMetricsCollector.ObserveQuery,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -951,7 +974,8 @@ func (a *MachineAgent) initState(agentConfig agent.Config) (*state.State, error)
 	st, _, err := openState(
 		agentConfig,
 		stateWorkerDialOpts,
-		a.txnmetricsCollector.AfterRunTransaction,
+		a.afterRunTransaction,
+		a.sessionMetricsCollector.ObserveQuery,
 	)
 	if err != nil {
 		return nil, err
@@ -1051,7 +1075,8 @@ func (a *MachineAgent) startStateWorkers(
 				st, _, err := openState(
 					agentConfig,
 					stateWorkerDialOpts,
-					a.txnmetricsCollector.AfterRunTransaction,
+					a.afterRunTransaction,
+					a.sessionMetricsCollector.ObserveQuery,
 				)
 				return st, err
 			}
@@ -1072,16 +1097,40 @@ func (a *MachineAgent) startStateWorkers(
 					}
 				})
 			}
+			var caCertSetter certupdater.CACertSetter = func(caCert string) error {
+				return a.ChangeConfig(func(config agent.ConfigSetter) error {
+					config.SetCACert(caCert)
+					return nil
+				})
+			}
 			a.startWorkerAfterUpgrade(runner, "certupdater", func() (worker.Worker, error) {
-				return newCertificateUpdater(m, agentConfig, st, st, stateServingSetter), nil
+				return newCertificateUpdater(m, agentConfig, st, st, st, stateServingSetter, caCertSetter), nil
 			})
 
 			a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
-				return dblogpruner.New(st, dblogpruner.NewLogPruneParams()), nil
+				controllerConfig, err := st.ControllerConfig()
+				if err != nil {
+					return nil, errors.Annotate(err, "cannot read controller config")
+				}
+				pruneParams := dblogpruner.NewLogPruneParams()
+				pruneParams.MaxAuditLogAge = controllerConfig.AuditLogMaxAge()
+				pruneParams.MaxModelConfigHistoryAge = controllerConfig.ModelConfigHistoryMaxAge()
+				return dblogpruner.New(st, pruneParams), nil
 			})
 
 			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
-				return txnpruner.New(st, time.Hour*2, clock.WallClock), nil
+				controllerConfig, err := st.ControllerConfig()
+				if err != nil {
+					return nil, errors.Annotate(err, "cannot read controller config")
+				}
+				return txnpruner.New(txnpruner.Config{
+					Pruner:               st,
+					Interval:             time.Hour * 2,
+					Clock:                clock.WallClock,
+					MaxAge:               controllerConfig.TxnPruneMaxAge(),
+					MaxSize:              controllerConfig.TxnPruneMaxSize(),
+					PrometheusRegisterer: a.prometheusRegistry,
+				})
 			})
 		default:
 			return nil, errors.Errorf("unknown job type %q", job)
@@ -1115,10 +1164,13 @@ func (a *MachineAgent) startModelWorkers(controllerUUID, modelUUID string) (work
 		Clock:                       clock.WallClock,
 		RunFlagDuration:             time.Minute,
 		CharmRevisionUpdateInterval: 24 * time.Hour,
+		ObjectStorageSweepInterval:  time.Hour,
 		InstPollerAggregationDelay:  3 * time.Second,
 		// TODO(perrito666) the status history pruning numbers need
 		// to be adjusting, after collecting user data from large install
 		// bases, to numbers allowing a rich and useful back history.
+		// The status-history-* controller config settings take
+		// precedence over the max history time when set.
 		StatusHistoryPrunerMaxHistoryTime: 336 * time.Hour, // 2 weeks
 		StatusHistoryPrunerMaxHistoryMB:   5120,            // 5G
 		StatusHistoryPrunerInterval:       5 * time.Minute,
@@ -1222,6 +1274,18 @@ func (a *MachineAgent) newAPIserverWorker(
 			}, f)
 	}
 
+	rateLimit := apiserver.RateLimitConfig{
+		PerConnection: controllerConfig.APIRateLimitConnection(),
+		PerFacade:     controllerConfig.APIRateLimitFacade(),
+	}
+	concurrencyLimit := apiserver.ConcurrencyLimitConfig{
+		MaxRequests: controllerConfig.APIMaxConcurrentRequests(),
+		Burst:       controllerConfig.APIConcurrentRequestsBurst(),
+	}
+	allowedClients := apiserver.AllowedClientsConfig{
+		Users:  controllerConfig.APIAllowedUserCIDRs(),
+		Agents: controllerConfig.APIAllowedAgentCIDRs(),
+	}
 	server, err := apiserver.NewServer(st, listener, apiserver.ServerConfig{
 		Clock:                         clock.WallClock,
 		Cert:                          cert,
@@ -1232,12 +1296,18 @@ func (a *MachineAgent) newAPIserverWorker(
 		Validator:                     a.limitLogins,
 		Hub:                           a.centralHub,
 		CertChanged:                   certChanged,
+		CertChain:                     info.CertChain,
 		AutocertURL:                   controllerConfig.AutocertURL(),
 		AutocertDNSName:               controllerConfig.AutocertDNSName(),
 		AllowModelAccess:              controllerConfig.AllowModelAccess(),
+		RateLimit:                     rateLimit,
+		ConcurrencyLimit:              concurrencyLimit,
+		AllowedClients:                allowedClients,
+		ShutdownTimeout:               controllerConfig.APIShutdownTimeout(),
 		NewObserver:                   newObserver,
 		StatePool:                     statePool,
 		RegisterIntrospectionHandlers: registerIntrospectionHandlers,
+		PrometheusGatherer:            a.prometheusRegistry,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start api server worker")
@@ -1441,6 +1511,7 @@ func openState(
 	agentConfig agent.Config,
 	dialOpts mongo.DialOpts,
 	runTransactionObserver state.RunTransactionObserverFunc,
+	queryObserver state.QueryObserverFunc,
 ) (_ *state.State, _ *state.Machine, err error) {
 	info, ok := agentConfig.MongoInfo()
 	if !ok {
@@ -1456,6 +1527,7 @@ func openState(
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
 		RunTransactionObserver: runTransactionObserver,
+		QueryObserver:          queryObserver,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -1465,6 +1537,9 @@ func openState(
 			st.Close()
 		}
 	}()
+	if err := st.ConfigureMongoSession(); err != nil {
+		return nil, nil, errors.Annotate(err, "configuring mongo session")
+	}
 	m0, err := st.FindEntity(agentConfig.Tag())
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/deployer"
@@ -398,6 +399,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent
+		// config as the controller's CA certificate changes, so that
+		// the agent keeps trusting the controllers while their CA is
+		// rotated.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
 		// The machiner Worker will wait for the identified machine to become
 		// Dying and make it Dead; or until the machine becomes Dead by other
 		// means.
@@ -529,6 +539,7 @@ const (
 	diskManagerName          = "disk-manager"
 	proxyConfigUpdater       = "proxy-config-updater"
 	apiAddressUpdaterName    = "api-address-updater"
+	caCertUpdaterName        = "ca-cert-updater"
 	machinerName             = "machiner"
 	logSenderName            = "log-sender"
 	deployerName             = "unit-agent-deployer"
@@ -46,6 +46,7 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"api-address-updater",
 		"api-caller",
 		"api-config-watcher",
+		"ca-cert-updater",
 		"central-hub",
 		"disk-manager",
 		"host-key-reporter",
@@ -1004,7 +1004,8 @@ func (s *MachineSuite) TestMachineAgentRunsMachineStorageWorker(c *gc.C) {
 func (s *MachineSuite) TestMachineAgentRunsCertificateUpdateWorkerForController(c *gc.C) {
 	started := newSignal()
 	newUpdater := func(certupdater.AddressWatcher, certupdater.StateServingInfoGetter, certupdater.ControllerConfigGetter,
-		certupdater.APIHostPortsGetter, certupdater.StateServingInfoSetter,
+		certupdater.APIHostPortsGetter, certupdater.StateServingInfoSource, certupdater.StateServingInfoSetter,
+		certupdater.CACertSetter,
 	) worker.Worker {
 		started.trigger()
 		return jworker.NewNoOpWorker()
@@ -1022,7 +1023,8 @@ func (s *MachineSuite) TestMachineAgentRunsCertificateUpdateWorkerForController(
 func (s *MachineSuite) TestMachineAgentDoesNotRunsCertificateUpdateWorkerForNonController(c *gc.C) {
 	started := newSignal()
 	newUpdater := func(certupdater.AddressWatcher, certupdater.StateServingInfoGetter, certupdater.ControllerConfigGetter,
-		certupdater.APIHostPortsGetter, certupdater.StateServingInfoSetter,
+		certupdater.APIHostPortsGetter, certupdater.StateServingInfoSource, certupdater.StateServingInfoSetter,
+		certupdater.CACertSetter,
 	) worker.Worker {
 		started.trigger()
 		return jworker.NewNoOpWorker()
@@ -1096,7 +1098,8 @@ func (s *MachineSuite) testCertificateDNSUpdated(c *gc.C, a *MachineAgent) {
 	// Disable the certificate worker so that the certificate could
 	// only have been updated during agent startup.
 	newUpdater := func(certupdater.AddressWatcher, certupdater.StateServingInfoGetter, certupdater.ControllerConfigGetter,
-		certupdater.APIHostPortsGetter, certupdater.StateServingInfoSetter,
+		certupdater.APIHostPortsGetter, certupdater.StateServingInfoSource, certupdater.StateServingInfoSetter,
+		certupdater.CACertSetter,
 	) worker.Worker {
 		return jworker.NewNoOpWorker()
 	}
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/modelstorage"
 	"github.com/juju/juju/feature"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agent"
@@ -38,6 +39,7 @@ import (
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/migrationflag"
 	"github.com/juju/juju/worker/migrationmaster"
+	"github.com/juju/juju/worker/objectstoragesweeper"
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/remoterelations"
 	"github.com/juju/juju/worker/singular"
@@ -82,6 +84,10 @@ type ManifoldsConfig struct {
 	// revision worker will check for new revisions of known charms.
 	CharmRevisionUpdateInterval time.Duration
 
+	// ObjectStorageSweepInterval determines how often expired files
+	// are removed from the model's object storage.
+	ObjectStorageSweepInterval time.Duration
+
 	// StatusHistoryPruner* values control status-history pruning
 	// behaviour.
 	StatusHistoryPrunerMaxHistoryTime time.Duration
@@ -301,6 +307,13 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			EnvironName:   environTrackerName,
 			NewWorker:     machineundertaker.NewWorker,
 		})),
+		objectStorageSweeperName: ifNotMigrating(objectstoragesweeper.Manifold(objectstoragesweeper.ManifoldConfig{
+			EnvironName: environTrackerName,
+			ClockName:   clockName,
+			Period:      config.ObjectStorageSweepInterval,
+			OpenStorage: modelstorage.Open,
+			NewWorker:   objectstoragesweeper.NewWorker,
+		})),
 	}
 	if featureflag.Enabled(feature.CrossModelRelations) {
 		result[remoteRelationsName] = ifNotMigrating(remoterelations.Manifold(remoterelations.ManifoldConfig{
@@ -404,5 +417,6 @@ const (
 	stateCleanerName         = "state-cleaner"
 	statusHistoryPrunerName  = "status-history-pruner"
 	machineUndertakerName    = "machine-undertaker"
+	objectStorageSweeperName = "object-storage-sweeper"
 	remoteRelationsName      = "remote-relations"
 )
@@ -50,6 +50,7 @@ func (s *ManifoldsSuite) TestNames(c *gc.C) {
 		"migration-master",
 		"not-alive-flag",
 		"not-dead-flag",
+		"object-storage-sweeper",
 		"space-importer",
 		"spaces-imported-gate",
 		"state-cleaner",
@@ -159,6 +160,7 @@ func (s *ManifoldsCrossModelSuite) TestNames(c *gc.C) {
 		"migration-master",
 		"not-alive-flag",
 		"not-dead-flag",
+		"object-storage-sweeper",
 		"remote-relations",
 		"space-importer",
 		"spaces-imported-gate",
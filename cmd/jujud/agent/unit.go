@@ -116,7 +116,7 @@ func (a *UnitAgent) Init(args []string) error {
 		// the writer in ctx.stderr gets set as the loggo writer in github.com/juju/cmd/logging.go
 		a.ctx.Stderr = &lumberjack.Logger{
 			Filename:   agent.LogFilename(agentConfig),
-			MaxSize:    300, // megabytes
+			MaxSize:    agent.MaxLogSizeMB(agentConfig), // megabytes
 			MaxBackups: 2,
 		}
 
@@ -137,6 +137,7 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 	if err := a.ReadConfig(a.Tag().String()); err != nil {
 		return err
 	}
+	agent.ConfigureLogging(a.CurrentConfig())
 	agentLogger.Infof("unit agent %v start (%s [%s])", a.Tag().String(), jujuversion.Current, runtime.Compiler)
 	if flags := featureflag.String(); flags != "" {
 		logger.Warningf("developer feature flags enabled: %s", flags)
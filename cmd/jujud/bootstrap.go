@@ -32,7 +32,9 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/modelstorage"
 	"github.com/juju/juju/environs/simplestreams"
+	envstorage "github.com/juju/juju/environs/storage"
 	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
@@ -51,6 +53,7 @@ var (
 	initiateMongoServer  = peergrouper.InitiateMongoServer
 	agentInitializeState = agentbootstrap.InitializeState
 	sshGenerateKey       = ssh.GenerateKey
+	openModelStorage     = modelstorage.Open
 	minSocketTimeout     = 1 * time.Minute
 	logger               = loggo.GetLogger("juju.cmd.jujud")
 )
@@ -428,6 +431,30 @@ func (c *BootstrapCommand) populateTools(st *state.State, env environs.Environ)
 			return errors.Trace(err)
 		}
 	}
+	if err := publishObjectStorageTools(env.Config(), tools, data, toolsVersions); err != nil {
+		// Machines fall back to downloading tools from the controller.
+		logger.Warningf("cannot publish tools to object storage: %v", err)
+	}
+	return nil
+}
+
+// publishObjectStorageTools puts the bootstrap tools into the model's
+// object storage, if it has any, so that provisioned machines may
+// download them directly from the storage.
+func publishObjectStorageTools(cfg *config.Config, agentTools *tools.Tools, data []byte, toolsVersions []version.Binary) error {
+	stor, err := openModelStorage(cfg, envstorage.NewLoggingObserver(logger))
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, toolsVersion := range toolsVersions {
+		name := envtools.StorageName(toolsVersion, cfg.AgentStream())
+		err := envstorage.PutHashed(stor, name, agentTools.SHA256, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return errors.Annotatef(err, "putting %v", toolsVersion)
+		}
+	}
 	return nil
 }
 
@@ -478,11 +505,16 @@ func storeImageMetadataInState(st *state.State, env environs.Environ, source str
 		return nil
 	}
 	cfg := env.Config()
+	model, err := st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	metadataState := make([]cloudimagemetadata.Metadata, len(existingMetadata))
 	for i, one := range existingMetadata {
 		m := cloudimagemetadata.Metadata{
 			MetadataAttributes: cloudimagemetadata.MetadataAttributes{
 				Stream:          one.Stream,
+				Cloud:           model.Cloud(),
 				Region:          one.RegionName,
 				Arch:            one.Arch,
 				VirtType:        one.VirtType,
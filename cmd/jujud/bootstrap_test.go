@@ -786,6 +786,50 @@ func (s *BootstrapSuite) testToolsMetadata(c *gc.C, exploded bool) {
 	}
 }
 
+func (s *BootstrapSuite) TestToolsPublishedToObjectStorage(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	var puts []string
+	s.PatchValue(&openModelStorage, func(cfg *config.Config, observer storage.Observer) (storage.Storage, error) {
+		return storage.NewObservedStorage(stor, storage.ObserverFunc(
+			func(op storage.Operation, name string, bytes int64, duration time.Duration, err error) {
+				c.Check(op, gc.Equals, storage.OpPut)
+				c.Check(err, jc.ErrorIsNil)
+				puts = append(puts, name)
+				observer.StorageOperation(op, name, bytes, duration, err)
+			},
+		)), nil
+	})
+
+	_, cmd, err := s.initBootstrapCommand(c, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	current := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	}
+	name := envtools.StorageName(current, "released")
+	c.Assert(puts, jc.DeepEquals, []string{name})
+	names, err := storage.List(stor, name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{name})
+}
+
+func (s *BootstrapSuite) TestToolsObjectStorageErrorIgnored(c *gc.C) {
+	s.PatchValue(&openModelStorage, func(*config.Config, storage.Observer) (storage.Storage, error) {
+		return nil, errors.New("boom")
+	})
+
+	_, cmd, err := s.initBootstrapCommand(c, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, "cannot publish tools to object storage: boom")
+}
+
 func createImageMetadata() []*imagemetadata.ImageMetadata {
 	return []*imagemetadata.ImageMetadata{{
 		Id:         "imageId",
@@ -842,6 +886,7 @@ func (s *BootstrapSuite) TestStructuredImageMetadataStored(c *gc.C) {
 	// This metadata should have also been written to state...
 	expect := cloudimagemetadata.Metadata{
 		MetadataAttributes: cloudimagemetadata.MetadataAttributes{
+			Cloud:           "dummy",
 			Region:          "region",
 			Arch:            "amd64",
 			Version:         "14.04",
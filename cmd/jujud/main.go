@@ -180,6 +180,7 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	jujud.Register(unitAgent)
 
 	jujud.Register(NewUpgradeMongoCommand())
+	jujud.Register(NewMigrateSchemaCommand())
 
 	code = cmd.Main(jujud, ctx, args[1:])
 	return code, nil
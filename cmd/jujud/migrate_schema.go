@@ -0,0 +1,135 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	jujudagent "github.com/juju/juju/cmd/jujud/agent"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+)
+
+// NewMigrateSchemaCommand returns a new command which brings the
+// schema of the local controller's database up to date, or reports
+// the changes that would be made.
+func NewMigrateSchemaCommand() cmd.Command {
+	return &migrateSchemaCommand{
+		agentConfig: jujudagent.NewAgentConf(""),
+	}
+}
+
+type migrateSchemaCommand struct {
+	cmd.CommandBase
+	agentConfig jujudagent.AgentConf
+	machineId   string
+	dryRun      bool
+}
+
+// Info implements cmd.Command.
+func (c *migrateSchemaCommand) Info() *cmd.Info {
+	doc := `
+Runs any schema migrations, and creates any collection indexes, that
+the Juju database is missing. This happens automatically when a
+controller is upgraded; use --dry-run to see what would change without
+changing anything.
+
+It must be run on a Juju controller, using the local machine agent's
+configuration to connect to the database. The --data-dir and/or
+--machine-id options may be required if the agent configuration can't
+be found automatically.
+`[1:]
+	return &cmd.Info{
+		Name:    "migrate-schema",
+		Purpose: "bring the local Juju database schema up to date",
+		Doc:     doc,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *migrateSchemaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.agentConfig.AddFlags(f)
+	f.StringVar(&c.machineId, "machine-id", "", "id of the machine on this host (optional)")
+	f.BoolVar(&c.dryRun, "dry-run", false, "report the changes without making them")
+}
+
+// Init implements cmd.Command.
+func (c *migrateSchemaCommand) Init(args []string) error {
+	if err := c.agentConfig.CheckArgs(args); err != nil {
+		return errors.Trace(err)
+	}
+	if c.machineId == "" {
+		machineId, err := findMachineId(c.agentConfig.DataDir())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.machineId = machineId
+	} else if !names.IsValidMachine(c.machineId) {
+		return errors.New("--machine-id option expects a non-negative integer")
+	}
+	return errors.Trace(c.agentConfig.ReadConfig(names.NewMachineTag(c.machineId).String()))
+}
+
+// Run implements cmd.Command.
+func (c *migrateSchemaCommand) Run(ctx *cmd.Context) error {
+	config := c.agentConfig.CurrentConfig()
+	info, ok := config.MongoInfo()
+	if !ok {
+		return errors.New("no database connection info available (is this a controller host?)")
+	}
+	st, err := state.Open(state.OpenParams{
+		Clock:              clock.WallClock,
+		ControllerTag:      config.Controller(),
+		ControllerModelTag: config.Model(),
+		MongoInfo:          info,
+		MongoDialOpts:      mongo.DefaultDialOpts(),
+	})
+	if err != nil {
+		return errors.Annotate(err, "failed to connect to database")
+	}
+	defer st.Close()
+
+	steps, err := st.MigrateSchema(state.MigrateSchemaParams{
+		DryRun: c.dryRun,
+		Progress: func(step state.SchemaMigrationStep) {
+			if step.Documents > 0 {
+				ctx.Infof("%d: %s: %s (%d documents)", step.Version, step.Description, step.Action, step.Documents)
+			} else {
+				ctx.Infof("%d: %s: %s", step.Version, step.Description, step.Action)
+			}
+		},
+	})
+	if err != nil {
+		return errors.Annotate(err, "migrating database schema")
+	}
+	if len(steps) == 0 {
+		ctx.Infof("database schema is up to date")
+	}
+	return nil
+}
+
+// findMachineId returns the id of the first machine agent configured
+// in the given data directory.
+func findMachineId(dataDir string) (string, error) {
+	entries, err := ioutil.ReadDir(agent.BaseDir(dataDir))
+	if err != nil {
+		return "", errors.Annotate(err, "failed to read agent configuration base directory")
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			tag, err := names.ParseMachineTag(entry.Name())
+			if err == nil {
+				return tag.Id(), nil
+			}
+		}
+	}
+	return "", errors.New("no machine agent configuration found")
+}
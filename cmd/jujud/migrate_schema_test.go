@@ -0,0 +1,34 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type MigrateSchemaCommandSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&MigrateSchemaCommandSuite{})
+
+func (s *MigrateSchemaCommandSuite) TestInitRejectsInvalidMachineId(c *gc.C) {
+	command := NewMigrateSchemaCommand()
+	err := testing.InitCommand(command, []string{"--machine-id", "foo", "--data-dir", c.MkDir()})
+	c.Assert(err, gc.ErrorMatches, "--machine-id option expects a non-negative integer")
+}
+
+func (s *MigrateSchemaCommandSuite) TestInitNoAgentConfig(c *gc.C) {
+	command := NewMigrateSchemaCommand()
+	err := testing.InitCommand(command, []string{"--dry-run", "--data-dir", c.MkDir()})
+	c.Assert(err, gc.ErrorMatches, "failed to read agent configuration base directory: .*")
+}
+
+func (s *MigrateSchemaCommandSuite) TestInitExtraArgs(c *gc.C) {
+	command := NewMigrateSchemaCommand()
+	err := testing.InitCommand(command, []string{"--data-dir", c.MkDir(), "foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
@@ -216,6 +216,7 @@ func NewEnsureServerParams(agentConfig agent.Config) (mongo.EnsureServerParams,
 		APIPort:        si.APIPort,
 		StatePort:      si.StatePort,
 		Cert:           si.Cert,
+		CertChain:      si.CertChain,
 		PrivateKey:     si.PrivateKey,
 		CAPrivateKey:   si.CAPrivateKey,
 		SharedSecret:   si.SharedSecret,
@@ -268,6 +269,7 @@ func ParamsStateServingInfoToStateStateServingInfo(i params.StateServingInfo) st
 		StatePort:      i.StatePort,
 		Cert:           i.Cert,
 		PrivateKey:     i.PrivateKey,
+		CertChain:      i.CertChain,
 		CAPrivateKey:   i.CAPrivateKey,
 		SharedSecret:   i.SharedSecret,
 		SystemIdentity: i.SystemIdentity,
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -14,7 +15,10 @@ import (
 	"github.com/juju/gnuflag"
 	"github.com/juju/loggo"
 
+	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/sync"
 )
 
 func newSignMetadataCommand() cmd.Command {
@@ -28,6 +32,12 @@ corresponding .sjson file is procduced.
 
 The specified keyring file is expected to contain an amored private key. If the key
 is encrypted, then the specified passphrase is used to decrypt the key.
+
+If --manifest-stream is specified, the directory is expected to be a metadata
+directory as written by generate-image, and a manifest recording the checksum
+of every image metadata file is also signed and written to images/manifests.
+The manifest is verified when the directory is used as a bootstrap
+--metadata-source.
 `
 
 // signMetadataCommand is used to sign simplestreams metadata json files.
@@ -36,6 +46,8 @@ type signMetadataCommand struct {
 	dir        string
 	keyFile    string
 	passphrase string
+
+	manifestStream string
 }
 
 func (c *signMetadataCommand) Info() *cmd.Info {
@@ -51,6 +63,7 @@ func (c *signMetadataCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.dir, "d", "", "directory in which to look for metadata")
 	f.StringVar(&c.keyFile, "k", "", "file containing the amored private signing key")
 	f.StringVar(&c.passphrase, "p", "", "passphrase used to decrypt the private key")
+	f.StringVar(&c.manifestStream, "manifest-stream", "", "write a signed manifest of the image metadata for this stream")
 }
 
 func (c *signMetadataCommand) Init(args []string) error {
@@ -74,7 +87,36 @@ func (c *signMetadataCommand) Run(context *cmd.Context) error {
 		return err
 	}
 	dir := context.AbsPath(c.dir)
-	return process(dir, string(keyData), c.passphrase)
+	if err := process(dir, string(keyData), c.passphrase); err != nil {
+		return err
+	}
+	if c.manifestStream == "" {
+		return nil
+	}
+	return writeImageManifest(dir, c.manifestStream, string(keyData), c.passphrase)
+}
+
+// writeImageManifest signs a manifest of the image metadata in the
+// metadata directory dir, and writes it alongside the metadata.
+func writeImageManifest(dir, stream, key, passphrase string) error {
+	stor, err := filestorage.NewFileStorageWriter(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m, err := sync.BuildManifest(stor, storage.BaseImagesPath+"/streams/", stream)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(m.Entries) == 0 {
+		return errors.Errorf("no image metadata found in %q", dir)
+	}
+	data, err := sync.SignManifest(m, key, passphrase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name := sync.ImageManifestPath(stream, m.Timestamp)
+	logger.Infof("writing image metadata manifest %q", name)
+	return errors.Trace(stor.Put(name, bytes.NewReader(data), int64(len(data))))
 }
 
 func process(dir, key, passphrase string) error {
@@ -15,8 +15,10 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/simplestreams"
 	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/environs/sync"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -90,6 +92,35 @@ func (s *SignMetadataSuite) TestSignMetadata(c *gc.C) {
 	assertSignedFiles(c, topLevel)
 }
 
+func (s *SignMetadataSuite) TestSignMetadataWritesImageManifest(c *gc.C) {
+	topLevel := c.MkDir()
+	keyfile := filepath.Join(c.MkDir(), "privatekey.asc")
+	err := ioutil.WriteFile(keyfile, []byte(sstesting.SignedMetadataPrivateKey), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	streamsDir := filepath.Join(topLevel, "images", "streams", "v1")
+	err = os.MkdirAll(streamsDir, 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(streamsDir, "index.json"), []byte("hello world"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runSignMetadata(c, "-d", topLevel, "-k", keyfile, "-p", sstesting.PrivateKeyPassphrase, "--manifest-stream", "released")
+	c.Assert(err, jc.ErrorIsNil)
+
+	stor, err := filestorage.NewFileStorageReader(topLevel)
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := sync.ReadLatestImageManifest(stor, "released", sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	var paths []string
+	for _, entry := range m.Entries {
+		paths = append(paths, entry.Path)
+	}
+	c.Assert(paths, jc.DeepEquals, []string{
+		"images/streams/v1/index.json",
+		"images/streams/v1/index.sjson",
+	})
+	c.Assert(sync.VerifyManifest(m, stor), jc.ErrorIsNil)
+}
+
 func runSignMetadata(c *gc.C, args ...string) error {
 	_, err := coretesting.RunCommand(c, newSignMetadataCommand(), args...)
 	return err
@@ -4,6 +4,7 @@
 package constraints
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -20,28 +21,52 @@ import (
 // The following constants list the supported constraint attribute names, as defined
 // by the fields in the Value struct.
 const (
-	Arch      = "arch"
-	Container = "container"
+	AllocatePublicIP = "allocate-public-ip"
+	Arch             = "arch"
+	Container        = "container"
 	// cpuCores is an alias for Cores.
-	cpuCores     = "cpu-cores"
-	Cores        = "cores"
-	CpuPower     = "cpu-power"
-	Mem          = "mem"
-	RootDisk     = "root-disk"
-	Tags         = "tags"
-	InstanceType = "instance-type"
-	Spaces       = "spaces"
-	VirtType     = "virt-type"
+	cpuCores       = "cpu-cores"
+	Cores          = "cores"
+	CpuPower       = "cpu-power"
+	GPUs           = "gpus"
+	GPUType        = "gpu-type"
+	ImageID        = "image-id"
+	Mem            = "mem"
+	Profile        = "profile"
+	RootDisk       = "root-disk"
+	RootDiskSource = "root-disk-source"
+	Tags           = "tags"
+	InstanceType   = "instance-type"
+	Spaces         = "spaces"
+	SpotPrice      = "spot-price"
+	Spread         = "spread"
+	VirtType       = "virt-type"
+
+	// maxCores and maxMem hold the upper bounds of the cores and mem
+	// constraints when they are specified as ranges.
+	maxCores = "max-cores"
+	maxMem   = "max-mem"
 )
 
+// SpreadZone is the value of the spread constraint preferring machines
+// to be spread across availability zones.
+const SpreadZone = "zone"
+
 // Value describes a user's requirements of the hardware on which units
 // of a service will run. Constraints are used to choose an existing machine
 // onto which a unit will be deployed, or to provision a new machine if no
 // existing one satisfies the requirements.
 type Value struct {
 
+	// AllocatePublicIP, if not nil, indicates whether a machine must be
+	// allocated a public IP address. Only valid for clouds where public
+	// addresses are optional.
+	AllocatePublicIP *bool `json:"allocate-public-ip,omitempty" yaml:"allocate-public-ip,omitempty"`
+
 	// Arch, if not nil or empty, indicates that a machine must run the named
-	// architecture.
+	// architecture. Several architectures may be named, separated by commas,
+	// in which case a machine running any of them is acceptable; the first
+	// is preferred where a single architecture must be chosen.
 	Arch *string `json:"arch,omitempty" yaml:"arch,omitempty"`
 
 	// Container, if not nil, indicates that a machine must be the specified container type.
@@ -51,15 +76,34 @@ type Value struct {
 	// number of effective cores available.
 	CpuCores *uint64 `json:"cores,omitempty" yaml:"cores,omitempty"`
 
+	// MaxCpuCores, if not nil, indicates that a machine must have at most
+	// that number of effective cores available. It is specified together
+	// with CpuCores as a range, e.g. "cores=2..8".
+	MaxCpuCores *uint64 `json:"max-cores,omitempty" yaml:"max-cores,omitempty"`
+
 	// CpuPower, if not nil, indicates that a machine must have at least that
 	// amount of CPU power available, where 100 CpuPower is considered to be
 	// equivalent to 1 Amazon ECU (or, roughly, a single 2007-era Xeon).
 	CpuPower *uint64 `json:"cpu-power,omitempty" yaml:"cpu-power,omitempty"`
 
+	// GPUs, if not nil, indicates that a machine must have at least that
+	// many GPUs available.
+	GPUs *uint64 `json:"gpus,omitempty" yaml:"gpus,omitempty"`
+
+	// GPUType, if not nil or empty, indicates that the GPUs of a machine
+	// must be of the named type. The supported GPU types are defined by
+	// each provider.
+	GPUType *string `json:"gpu-type,omitempty" yaml:"gpu-type,omitempty"`
+
 	// Mem, if not nil, indicates that a machine must have at least that many
 	// megabytes of RAM.
 	Mem *uint64 `json:"mem,omitempty" yaml:"mem,omitempty"`
 
+	// MaxMem, if not nil, indicates that a machine must have at most that
+	// many megabytes of RAM. It is specified together with Mem as a range,
+	// e.g. "mem=4G..16G".
+	MaxMem *uint64 `json:"max-mem,omitempty" yaml:"max-mem,omitempty"`
+
 	// RootDisk, if not nil, indicates that a machine must have at least
 	// that many megabytes of disk space available in the root disk. In
 	// providers where the root disk is configurable at instance startup
@@ -67,6 +111,12 @@ type Value struct {
 	// disk might be requested.
 	RootDisk *uint64 `json:"root-disk,omitempty" yaml:"root-disk,omitempty"`
 
+	// RootDiskSource, if not nil or empty, indicates where a machine's
+	// root disk must come from. The meaning of the value is defined by
+	// each provider: an EBS volume type on ec2, a persistent disk type
+	// on gce and a block device tag on maas.
+	RootDiskSource *string `json:"root-disk-source,omitempty" yaml:"root-disk-source,omitempty"`
+
 	// Tags, if not nil, indicates tags that the machine must have applied to it.
 	// An empty list is treated the same as a nil (unspecified) list, except an
 	// empty list will override any default tags, where a nil list will not.
@@ -76,12 +126,37 @@ type Value struct {
 	// be used. Only valid for clouds which support instance types.
 	InstanceType *string `json:"instance-type,omitempty" yaml:"instance-type,omitempty"`
 
+	// ImageID, if not nil or empty, indicates that a machine must be
+	// started from the cloud image with the given ID, rather than one
+	// found in the image metadata. Only valid for clouds that use
+	// image metadata.
+	ImageID *string `json:"image-id,omitempty" yaml:"image-id,omitempty"`
+
+	// Profile, if not nil or empty, names a constraint profile defined
+	// in model config. The attributes of the profile are used for any
+	// attributes not otherwise specified; see ExpandProfile.
+	Profile *string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
 	// Spaces, if not nil, holds a list of juju network spaces that
 	// should be available (or not) on the machine. Positive and
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Spaces *[]string `json:"spaces,omitempty" yaml:"spaces,omitempty"`
 
+	// SpotPrice, if not nil and positive, requests that a machine be
+	// started using the provider's spot, or preemptible, capacity,
+	// paying no more than the given hourly price in the provider's
+	// currency. Such machines are cheaper than on-demand ones, but the
+	// provider may evict them at any time.
+	SpotPrice *float64 `json:"spot-price,omitempty" yaml:"spot-price,omitempty"`
+
+	// Spread, if not nil or empty, indicates how machines of the same
+	// application should be spread. The only supported value is "zone",
+	// which prefers to start each machine in the availability zone
+	// holding the fewest machines of the application, falling back to
+	// other zones if it cannot be started there.
+	Spread *string `json:"spread,omitempty" yaml:"spread,omitempty"`
+
 	// VirtType, if not nil or empty, indicates that a machine must run the named
 	// virtual type. Only valid for clouds with multi-hypervisor support.
 	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
@@ -105,11 +180,26 @@ func IsEmpty(v *Value) bool {
 	return v.String() == ""
 }
 
+// HasAllocatePublicIP returns true if the constraints.Value specifies
+// whether to allocate a public IP address.
+func (v *Value) HasAllocatePublicIP() bool {
+	return v.AllocatePublicIP != nil
+}
+
 // HasArch returns true if the constraints.Value specifies an architecture.
 func (v *Value) HasArch() bool {
 	return v.Arch != nil && *v.Arch != ""
 }
 
+// Arches returns the architectures named by the constraints.Value, in
+// order of preference, or nil if none are specified.
+func (v *Value) Arches() []string {
+	if !v.HasArch() {
+		return nil
+	}
+	return strings.Split(*v.Arch, ",")
+}
+
 // HasMem returns true if the constraints.Value specifies a minimum amount
 // of memory.
 func (v *Value) HasMem() bool {
@@ -128,11 +218,91 @@ func (v *Value) HasCpuCores() bool {
 	return v.CpuCores != nil && *v.CpuCores > 0
 }
 
+// HasGPUs returns true if the constraints.Value specifies a minimum number
+// of GPUs.
+func (v *Value) HasGPUs() bool {
+	return v.GPUs != nil && *v.GPUs > 0
+}
+
+// HasGPUType returns true if the constraints.Value specifies a GPU type.
+func (v *Value) HasGPUType() bool {
+	return v.GPUType != nil && *v.GPUType != ""
+}
+
+// HasRootDiskSource returns true if the constraints.Value specifies
+// where the root disk should come from.
+func (v *Value) HasRootDiskSource() bool {
+	return v.RootDiskSource != nil && *v.RootDiskSource != ""
+}
+
+// HasImageID returns true if the constraints.Value specifies an image ID.
+func (v *Value) HasImageID() bool {
+	return v.ImageID != nil && *v.ImageID != ""
+}
+
+// HasProfile returns true if the constraints.Value specifies a
+// constraint profile.
+func (v *Value) HasProfile() bool {
+	return v.Profile != nil && *v.Profile != ""
+}
+
+// HasSpotPrice returns true if the constraints.Value requests spot
+// capacity.
+func (v *Value) HasSpotPrice() bool {
+	return v.SpotPrice != nil && *v.SpotPrice > 0
+}
+
+// HasSpread returns true if the constraints.Value specifies how
+// machines should be spread.
+func (v *Value) HasSpread() bool {
+	return v.Spread != nil && *v.Spread != ""
+}
+
 // HasInstanceType returns true if the constraints.Value specifies an instance type.
 func (v *Value) HasInstanceType() bool {
 	return v.InstanceType != nil && *v.InstanceType != ""
 }
 
+// HasInstanceTypePattern returns true if the constraints.Value specifies
+// a family of instance types, using "*" wildcards, rather than a single
+// instance type.
+func (v *Value) HasInstanceTypePattern() bool {
+	return v.HasInstanceType() && strings.Contains(*v.InstanceType, "*")
+}
+
+// MatchesInstanceType returns true if the named instance type satisfies
+// the instance-type constraint, or if none is specified. Each "*" in the
+// constraint matches any sequence of characters, so that for example
+// "instance-type=m4.*" matches every instance type in the m4 family.
+func (v *Value) MatchesInstanceType(name string) bool {
+	if !v.HasInstanceType() {
+		return true
+	}
+	return wildcardMatch(*v.InstanceType, name)
+}
+
+// wildcardMatch returns true if s matches pattern, in which each "*"
+// matches any sequence of characters.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[last])
+}
+
 // extractItems returns the list of entries in the given field which
 // are either positive (included) or negative (!included; with prefix
 // "^").
@@ -183,6 +353,9 @@ func (v *Value) HasVirtType() bool {
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
+	if v.AllocatePublicIP != nil {
+		strs = append(strs, "allocate-public-ip="+strconv.FormatBool(*v.AllocatePublicIP))
+	}
 	if v.Arch != nil {
 		strs = append(strs, "arch="+*v.Arch)
 	}
@@ -190,19 +363,38 @@ func (v Value) String() string {
 		strs = append(strs, "container="+string(*v.Container))
 	}
 	if v.CpuCores != nil {
-		strs = append(strs, "cores="+uintStr(*v.CpuCores))
+		s := uintStr(*v.CpuCores)
+		if v.MaxCpuCores != nil {
+			s += fmt.Sprintf("..%d", *v.MaxCpuCores)
+		}
+		strs = append(strs, "cores="+s)
 	}
 	if v.CpuPower != nil {
 		strs = append(strs, "cpu-power="+uintStr(*v.CpuPower))
 	}
+	if v.GPUs != nil {
+		strs = append(strs, "gpus="+uintStr(*v.GPUs))
+	}
+	if v.GPUType != nil {
+		strs = append(strs, "gpu-type="+*v.GPUType)
+	}
+	if v.ImageID != nil {
+		strs = append(strs, "image-id="+*v.ImageID)
+	}
 	if v.InstanceType != nil {
 		strs = append(strs, "instance-type="+string(*v.InstanceType))
 	}
+	if v.Profile != nil {
+		strs = append(strs, "profile="+*v.Profile)
+	}
 	if v.Mem != nil {
 		s := uintStr(*v.Mem)
 		if s != "" {
 			s += "M"
 		}
+		if v.MaxMem != nil {
+			s += fmt.Sprintf("..%dM", *v.MaxMem)
+		}
 		strs = append(strs, "mem="+s)
 	}
 	if v.RootDisk != nil {
@@ -212,6 +404,9 @@ func (v Value) String() string {
 		}
 		strs = append(strs, "root-disk="+s)
 	}
+	if v.RootDiskSource != nil {
+		strs = append(strs, "root-disk-source="+*v.RootDiskSource)
+	}
 	if v.Tags != nil {
 		s := strings.Join(*v.Tags, ",")
 		strs = append(strs, "tags="+s)
@@ -220,6 +415,12 @@ func (v Value) String() string {
 		s := strings.Join(*v.Spaces, ",")
 		strs = append(strs, "spaces="+s)
 	}
+	if v.SpotPrice != nil {
+		strs = append(strs, "spot-price="+floatStr(*v.SpotPrice))
+	}
+	if v.Spread != nil {
+		strs = append(strs, "spread="+*v.Spread)
+	}
 	if v.VirtType != nil {
 		strs = append(strs, "virt-type="+string(*v.VirtType))
 	}
@@ -230,24 +431,48 @@ func (v Value) String() string {
 // package, especially when nested inside other types.
 func (v Value) GoString() string {
 	var values []string
+	if v.AllocatePublicIP != nil {
+		values = append(values, fmt.Sprintf("AllocatePublicIP: %v", *v.AllocatePublicIP))
+	}
 	if v.Arch != nil {
 		values = append(values, fmt.Sprintf("Arch: %q", *v.Arch))
 	}
 	if v.CpuCores != nil {
 		values = append(values, fmt.Sprintf("Cores: %v", *v.CpuCores))
 	}
+	if v.MaxCpuCores != nil {
+		values = append(values, fmt.Sprintf("MaxCores: %v", *v.MaxCpuCores))
+	}
 	if v.CpuPower != nil {
 		values = append(values, fmt.Sprintf("CpuPower: %v", *v.CpuPower))
 	}
+	if v.GPUs != nil {
+		values = append(values, fmt.Sprintf("GPUs: %v", *v.GPUs))
+	}
+	if v.GPUType != nil {
+		values = append(values, fmt.Sprintf("GPUType: %q", *v.GPUType))
+	}
 	if v.Mem != nil {
 		values = append(values, fmt.Sprintf("Mem: %v", *v.Mem))
 	}
+	if v.MaxMem != nil {
+		values = append(values, fmt.Sprintf("MaxMem: %v", *v.MaxMem))
+	}
 	if v.RootDisk != nil {
 		values = append(values, fmt.Sprintf("RootDisk: %v", *v.RootDisk))
 	}
+	if v.RootDiskSource != nil {
+		values = append(values, fmt.Sprintf("RootDiskSource: %q", *v.RootDiskSource))
+	}
 	if v.InstanceType != nil {
 		values = append(values, fmt.Sprintf("InstanceType: %q", *v.InstanceType))
 	}
+	if v.ImageID != nil {
+		values = append(values, fmt.Sprintf("ImageID: %q", *v.ImageID))
+	}
+	if v.Profile != nil {
+		values = append(values, fmt.Sprintf("Profile: %q", *v.Profile))
+	}
 	if v.Container != nil {
 		values = append(values, fmt.Sprintf("Container: %q", *v.Container))
 	}
@@ -261,6 +486,12 @@ func (v Value) GoString() string {
 	} else if v.Spaces != nil {
 		values = append(values, "Spaces: (*[]string)(nil)")
 	}
+	if v.SpotPrice != nil {
+		values = append(values, fmt.Sprintf("SpotPrice: %v", *v.SpotPrice))
+	}
+	if v.Spread != nil {
+		values = append(values, fmt.Sprintf("Spread: %q", *v.Spread))
+	}
 	if v.VirtType != nil {
 		values = append(values, fmt.Sprintf("VirtType: %q", *v.VirtType))
 	}
@@ -274,6 +505,13 @@ func uintStr(i uint64) string {
 	return fmt.Sprintf("%d", i)
 }
 
+func floatStr(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 // Parse constructs a constraints.Value from the supplied arguments,
 // each of which must contain only spaces and name=value pairs. If any
 // name is specified more than once, an error is returned.
@@ -400,6 +638,8 @@ func splitRaw(s string) (name, val string, err error) {
 func (v *Value) setRaw(name, str string) error {
 	var err error
 	switch resolveAlias(name) {
+	case AllocatePublicIP:
+		err = v.setAllocatePublicIP(str)
 	case Arch:
 		err = v.setArch(str)
 	case Container:
@@ -408,16 +648,30 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setCpuCores(str)
 	case CpuPower:
 		err = v.setCpuPower(str)
+	case GPUs:
+		err = v.setGPUs(str)
+	case GPUType:
+		err = v.setGPUType(str)
 	case Mem:
 		err = v.setMem(str)
 	case RootDisk:
 		err = v.setRootDisk(str)
+	case RootDiskSource:
+		err = v.setRootDiskSource(str)
 	case Tags:
 		err = v.setTags(str)
 	case InstanceType:
 		err = v.setInstanceType(str)
+	case ImageID:
+		err = v.setImageID(str)
+	case Profile:
+		err = v.setProfile(str)
 	case Spaces:
 		err = v.setSpaces(str)
+	case SpotPrice:
+		err = v.setSpotPrice(str)
+	case Spread:
+		err = v.setSpread(str)
 	case VirtType:
 		err = v.setVirtType(str)
 	default:
@@ -429,67 +683,144 @@ func (v *Value) setRaw(name, str string) error {
 	return nil
 }
 
+// valueFields has the fields of Value but none of its methods, so that
+// it is marshalled according to the struct tags of Value.
+type valueFields Value
+
+// MarshalJSON is defined on json.Marshaler. A Value is marshalled as an
+// object holding each specified attribute under its canonical name.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(valueFields(v))
+}
+
+// UnmarshalJSON is defined on json.Unmarshaler. It accepts the form
+// produced by MarshalJSON, as well as attribute aliases and list-valued
+// attributes given as comma-delimited strings. Unknown attributes are
+// ignored, so that values written by newer versions can still be read.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var attrs map[string]interface{}
+	if err := decoder.Decode(&attrs); err != nil {
+		return errors.Trace(err)
+	}
+	return v.setAttributes(attrs, true)
+}
+
+// MarshalYAML is defined on yaml.Marshaler. A Value is marshalled in
+// the same form as by MarshalJSON.
+func (v Value) MarshalYAML() (interface{}, error) {
+	return valueFields(v), nil
+}
+
 // UnmarshalYAML is required to unmarshal a constraints.Value object
 // to ensure the container attribute is correctly handled when it is empty.
 // Because ContainerType is an alias for string, Go's reflect logic used in the
 // YAML decode determines that *string and *ContainerType are not assignable so
 // the container value of "" in the YAML is ignored.
+//
+// As with UnmarshalJSON, attribute aliases and comma-delimited lists are
+// accepted, but unknown attributes are an error.
 func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	values := map[interface{}]interface{}{}
 	err := unmarshal(&values)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	canonicals := map[string]string{}
+	attrs := make(map[string]interface{}, len(values))
 	for k, val := range values {
-		vstr := fmt.Sprintf("%v", val)
 		key, ok := k.(string)
 		if !ok {
 			return errors.Errorf("unexpected non-string key: %#v", k)
 		}
+		attrs[key] = val
+	}
+	return v.setAttributes(attrs, false)
+}
+
+// setAttributes sets v to hold the given attributes, keyed by constraint
+// name or alias. Attributes with null values are left unspecified, and
+// sizes may be given with an M/G/T/P suffix as in the string form.
+func (v *Value) setAttributes(attrs map[string]interface{}, ignoreUnknown bool) error {
+	var result Value
+	canonicals := map[string]string{}
+	for key, val := range attrs {
+		if val == nil {
+			continue
+		}
+		vstr := fmt.Sprintf("%v", val)
 		canonical := resolveAlias(key)
-		if v, ok := canonicals[canonical]; ok {
+		if other, ok := canonicals[canonical]; ok {
 			// duplicate entry
-			return errors.Errorf("constraint %q duplicates constraint %q", key, v)
+			return errors.Errorf("constraint %q duplicates constraint %q", key, other)
 		}
 		canonicals[canonical] = key
+		var err error
 		switch canonical {
+		case AllocatePublicIP:
+			result.AllocatePublicIP, err = parseBool(vstr)
 		case Arch:
-			v.Arch = &vstr
+			var arches *[]string
+			if arches, err = parseStrings("arch", val); err == nil {
+				joined := strings.Join(*arches, ",")
+				result.Arch = &joined
+			}
 		case Container:
 			ctype := instance.ContainerType(vstr)
-			v.Container = &ctype
+			result.Container = &ctype
 		case InstanceType:
-			v.InstanceType = &vstr
+			result.InstanceType = &vstr
+		case ImageID:
+			result.ImageID = &vstr
+		case Profile:
+			result.Profile = &vstr
 		case Cores:
-			v.CpuCores, err = parseUint64(vstr)
+			result.CpuCores, err = parseUint64(vstr)
+		case maxCores:
+			result.MaxCpuCores, err = parseUint64(vstr)
 		case CpuPower:
-			v.CpuPower, err = parseUint64(vstr)
+			result.CpuPower, err = parseUint64(vstr)
+		case GPUs:
+			result.GPUs, err = parseUint64(vstr)
+		case GPUType:
+			result.GPUType = &vstr
 		case Mem:
-			v.Mem, err = parseUint64(vstr)
+			result.Mem, err = parseSize(vstr)
+		case maxMem:
+			result.MaxMem, err = parseSize(vstr)
 		case RootDisk:
-			v.RootDisk, err = parseUint64(vstr)
+			result.RootDisk, err = parseSize(vstr)
+		case RootDiskSource:
+			result.RootDiskSource = &vstr
 		case Tags:
-			v.Tags, err = parseYamlStrings("tags", val)
+			result.Tags, err = parseStrings("tags", val)
 		case Spaces:
 			var spaces *[]string
-			spaces, err = parseYamlStrings("spaces", val)
+			spaces, err = parseStrings("spaces", val)
 			if err != nil {
 				return errors.Trace(err)
 			}
-			err = v.validateSpaces(spaces)
+			err = result.validateSpaces(spaces)
 			if err == nil {
-				v.Spaces = spaces
+				result.Spaces = spaces
 			}
+		case SpotPrice:
+			result.SpotPrice, err = parseFloat64(vstr)
+		case Spread:
+			err = result.setSpread(vstr)
 		case VirtType:
-			v.VirtType = &vstr
+			result.VirtType = &vstr
 		default:
-			return errors.Errorf("unknown constraint value: %v", k)
+			if ignoreUnknown {
+				continue
+			}
+			return errors.Errorf("unknown constraint value: %v", key)
 		}
 		if err != nil {
 			return errors.Trace(err)
 		}
 	}
+	*v = result
 	return nil
 }
 
@@ -515,12 +846,24 @@ func (v *Value) HasContainer() bool {
 	return v.Container != nil && *v.Container != "" && *v.Container != instance.NONE
 }
 
+func (v *Value) setAllocatePublicIP(str string) (err error) {
+	if v.AllocatePublicIP != nil {
+		return errors.Errorf("already set")
+	}
+	v.AllocatePublicIP, err = parseBool(str)
+	return
+}
+
 func (v *Value) setArch(str string) error {
 	if v.Arch != nil {
 		return errors.Errorf("already set")
 	}
-	if str != "" && !arch.IsSupportedArch(str) {
-		return errors.Errorf("%q not recognized", str)
+	if str != "" {
+		for _, a := range strings.Split(str, ",") {
+			if !arch.IsSupportedArch(a) {
+				return errors.Errorf("%q not recognized", a)
+			}
+		}
 	}
 	v.Arch = &str
 	return nil
@@ -530,8 +873,20 @@ func (v *Value) setCpuCores(str string) (err error) {
 	if v.CpuCores != nil {
 		return errors.Errorf("already set")
 	}
-	v.CpuCores, err = parseUint64(str)
-	return
+	min, max, isRange, err := splitRange(str)
+	if err != nil {
+		return err
+	}
+	if v.CpuCores, err = parseUint64(min); err != nil {
+		return err
+	}
+	if !isRange {
+		return nil
+	}
+	if v.MaxCpuCores, err = parseUint64(max); err != nil {
+		return err
+	}
+	return checkRange(*v.CpuCores, *v.MaxCpuCores)
 }
 
 func (v *Value) setCpuPower(str string) (err error) {
@@ -542,6 +897,38 @@ func (v *Value) setCpuPower(str string) (err error) {
 	return
 }
 
+func (v *Value) setGPUs(str string) (err error) {
+	if v.GPUs != nil {
+		return errors.Errorf("already set")
+	}
+	v.GPUs, err = parseUint64(str)
+	return
+}
+
+func (v *Value) setGPUType(str string) error {
+	if v.GPUType != nil {
+		return errors.Errorf("already set")
+	}
+	v.GPUType = &str
+	return nil
+}
+
+func (v *Value) setImageID(str string) error {
+	if v.ImageID != nil {
+		return errors.Errorf("already set")
+	}
+	v.ImageID = &str
+	return nil
+}
+
+func (v *Value) setProfile(str string) error {
+	if v.Profile != nil {
+		return errors.Errorf("already set")
+	}
+	v.Profile = &str
+	return nil
+}
+
 func (v *Value) setInstanceType(str string) error {
 	if v.InstanceType != nil {
 		return errors.Errorf("already set")
@@ -554,8 +941,20 @@ func (v *Value) setMem(str string) (err error) {
 	if v.Mem != nil {
 		return errors.Errorf("already set")
 	}
-	v.Mem, err = parseSize(str)
-	return
+	min, max, isRange, err := splitRange(str)
+	if err != nil {
+		return err
+	}
+	if v.Mem, err = parseSize(min); err != nil {
+		return err
+	}
+	if !isRange {
+		return nil
+	}
+	if v.MaxMem, err = parseSize(max); err != nil {
+		return err
+	}
+	return checkRange(*v.Mem, *v.MaxMem)
 }
 
 func (v *Value) setRootDisk(str string) (err error) {
@@ -566,6 +965,14 @@ func (v *Value) setRootDisk(str string) (err error) {
 	return
 }
 
+func (v *Value) setRootDiskSource(str string) error {
+	if v.RootDiskSource != nil {
+		return errors.Errorf("already set")
+	}
+	v.RootDiskSource = &str
+	return nil
+}
+
 func (v *Value) setTags(str string) error {
 	if v.Tags != nil {
 		return errors.Errorf("already set")
@@ -599,6 +1006,25 @@ func (v *Value) validateSpaces(spaces *[]string) error {
 	return nil
 }
 
+func (v *Value) setSpotPrice(str string) (err error) {
+	if v.SpotPrice != nil {
+		return errors.Errorf("already set")
+	}
+	v.SpotPrice, err = parseFloat64(str)
+	return
+}
+
+func (v *Value) setSpread(str string) error {
+	if v.Spread != nil {
+		return errors.Errorf("already set")
+	}
+	if str != "" && str != SpreadZone {
+		return errors.Errorf("%q not recognized", str)
+	}
+	v.Spread = &str
+	return nil
+}
+
 func (v *Value) setVirtType(str string) error {
 	if v.VirtType != nil {
 		return errors.Errorf("already set")
@@ -607,6 +1033,43 @@ func (v *Value) setVirtType(str string) error {
 	return nil
 }
 
+func parseBool(str string) (*bool, error) {
+	var value bool
+	if str != "" {
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, errors.Errorf("must be 'true' or 'false'")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
+// splitRange splits a constraint value of the form "min..max" into its
+// bounds. If the value is not a range, it is returned as min.
+func splitRange(str string) (min, max string, isRange bool, err error) {
+	parts := strings.Split(str, "..")
+	switch len(parts) {
+	case 1:
+		return str, "", false, nil
+	case 2:
+		if parts[1] == "" {
+			return "", "", false, errors.Errorf("range %q has no maximum", str)
+		}
+		return parts[0], parts[1], true, nil
+	}
+	return "", "", false, errors.Errorf("malformed range %q", str)
+}
+
+// checkRange returns an error if the lower bound of a range is greater
+// than its upper bound.
+func checkRange(min, max uint64) error {
+	if min > max {
+		return errors.Errorf("range minimum exceeds maximum")
+	}
+	return nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -619,6 +1082,18 @@ func parseUint64(str string) (*uint64, error) {
 	return &value, nil
 }
 
+func parseFloat64(str string) (*float64, error) {
+	var value float64
+	if str != "" {
+		val, err := strconv.ParseFloat(str, 64)
+		if err != nil || val < 0 || math.IsInf(val, 0) || math.IsNaN(val) {
+			return nil, errors.Errorf("must be a non-negative number")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
 func parseSize(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -647,7 +1122,12 @@ func parseCommaDelimited(s string) *[]string {
 	return &t
 }
 
-func parseYamlStrings(entityName string, val interface{}) (*[]string, error) {
+// parseStrings returns the items in the value val, which may be either
+// a list of strings or a comma-delimited string.
+func parseStrings(entityName string, val interface{}) (*[]string, error) {
+	if s, ok := val.(string); ok {
+		return parseCommaDelimited(s), nil
+	}
 	ifcs, ok := val.([]interface{})
 	if !ok {
 		return nil, errors.Errorf("unexpected type passed to %s: %T", entityName, val)
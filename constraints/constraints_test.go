@@ -90,6 +90,17 @@ var parseConstraintsTests = []struct {
 		summary: "set nonsense arch 2",
 		args:    []string{"arch=123.45"},
 		err:     `bad "arch" constraint: "123.45" not recognized`,
+	}, {
+		summary: "set multiple arches",
+		args:    []string{"arch=amd64,arm64"},
+	}, {
+		summary: "set multiple arches with nonsense",
+		args:    []string{"arch=amd64,cheese"},
+		err:     `bad "arch" constraint: "cheese" not recognized`,
+	}, {
+		summary: "set multiple arches with empty arch",
+		args:    []string{"arch=amd64,"},
+		err:     `bad "arch" constraint: "" not recognized`,
 	}, {
 		summary: "double set arch together",
 		args:    []string{"arch=amd64 arch=amd64"},
@@ -130,6 +141,28 @@ var parseConstraintsTests = []struct {
 		summary: "double set cores separately",
 		args:    []string{"cores=128", "cores=1"},
 		err:     `bad "cores" constraint: already set`,
+	}, {
+		summary: "set cores range",
+		args:    []string{"cores=2..8"},
+	}, {
+		summary: "set cores range without minimum",
+		args:    []string{"cores=..8"},
+	}, {
+		summary: "set cores range without maximum",
+		args:    []string{"cores=2.."},
+		err:     `bad "cores" constraint: range "2.." has no maximum`,
+	}, {
+		summary: "set inverted cores range",
+		args:    []string{"cores=8..2"},
+		err:     `bad "cores" constraint: range minimum exceeds maximum`,
+	}, {
+		summary: "set malformed cores range",
+		args:    []string{"cores=2..4..8"},
+		err:     `bad "cores" constraint: malformed range "2..4..8"`,
+	}, {
+		summary: "set nonsense cores range",
+		args:    []string{"cores=2..lots"},
+		err:     `bad "cores" constraint: must be a non-negative integer`,
 	},
 
 	// "cpu-cores"
@@ -208,6 +241,20 @@ var parseConstraintsTests = []struct {
 		summary: "double set mem separately",
 		args:    []string{"mem=1G", "mem=2G"},
 		err:     `bad "mem" constraint: already set`,
+	}, {
+		summary: "set mem range",
+		args:    []string{"mem=4G..16G"},
+	}, {
+		summary: "set mem range in megabytes",
+		args:    []string{"mem=512M..2048"},
+	}, {
+		summary: "set inverted mem range",
+		args:    []string{"mem=16G..4G"},
+		err:     `bad "mem" constraint: range minimum exceeds maximum`,
+	}, {
+		summary: "set nonsense mem range",
+		args:    []string{"mem=4G..cheese"},
+		err:     `bad "mem" constraint: must be a non-negative float with optional M/G/T/P suffix`,
 	},
 
 	// "root-disk" in detail.
@@ -254,6 +301,19 @@ var parseConstraintsTests = []struct {
 		err:     `bad "root-disk" constraint: already set`,
 	},
 
+	// "root-disk-source" in detail.
+	{
+		summary: "set root-disk-source empty",
+		args:    []string{"root-disk-source="},
+	}, {
+		summary: "set root-disk-source",
+		args:    []string{"root-disk-source=ssd-pool"},
+	}, {
+		summary: "double set root-disk-source separately",
+		args:    []string{"root-disk-source=volume", "root-disk-source=local"},
+		err:     `bad "root-disk-source" constraint: already set`,
+	},
+
 	// tags
 	{
 		summary: "single tag",
@@ -313,6 +373,123 @@ var parseConstraintsTests = []struct {
 		err:     `bad "virt-type" constraint: already set`,
 	},
 
+	// "gpus" in detail.
+	{
+		summary: "set gpus empty",
+		args:    []string{"gpus="},
+	}, {
+		summary: "set gpus",
+		args:    []string{"gpus=2"},
+	}, {
+		summary: "set nonsense gpus",
+		args:    []string{"gpus=lots"},
+		err:     `bad "gpus" constraint: must be a non-negative integer`,
+	}, {
+		summary: "double set gpus separately",
+		args:    []string{"gpus=2", "gpus=4"},
+		err:     `bad "gpus" constraint: already set`,
+	},
+
+	// "gpu-type" in detail.
+	{
+		summary: "set gpu-type empty",
+		args:    []string{"gpu-type="},
+	}, {
+		summary: "set gpu-type",
+		args:    []string{"gpu-type=nvidia-k80"},
+	}, {
+		summary: "double set gpu-type together",
+		args:    []string{"gpu-type=nvidia-k80 gpu-type=nvidia-k520"},
+		err:     `bad "gpu-type" constraint: already set`,
+	},
+
+	// "image-id" in detail.
+	{
+		summary: "set image-id",
+		args:    []string{"image-id=ami-12345"},
+	}, {
+		summary: "set image-id empty",
+		args:    []string{"image-id="},
+	}, {
+		summary: "double set image-id",
+		args:    []string{"image-id=ami-12345", "image-id=ami-67890"},
+		err:     `bad "image-id" constraint: already set`,
+	},
+
+	// "profile" in detail.
+	{
+		summary: "set profile",
+		args:    []string{"profile=small"},
+	}, {
+		summary: "set profile empty",
+		args:    []string{"profile="},
+	}, {
+		summary: "double set profile",
+		args:    []string{"profile=small", "profile=large"},
+		err:     `bad "profile" constraint: already set`,
+	},
+
+	// "allocate-public-ip" in detail.
+	{
+		summary: "set allocate-public-ip empty",
+		args:    []string{"allocate-public-ip="},
+	}, {
+		summary: "set allocate-public-ip true",
+		args:    []string{"allocate-public-ip=true"},
+	}, {
+		summary: "set allocate-public-ip false",
+		args:    []string{"allocate-public-ip=false"},
+	}, {
+		summary: "set nonsense allocate-public-ip",
+		args:    []string{"allocate-public-ip=maybe"},
+		err:     `bad "allocate-public-ip" constraint: must be 'true' or 'false'`,
+	}, {
+		summary: "double set allocate-public-ip separately",
+		args:    []string{"allocate-public-ip=true", "allocate-public-ip=false"},
+		err:     `bad "allocate-public-ip" constraint: already set`,
+	},
+
+	// "spread" in detail.
+	{
+		summary: "set spread empty",
+		args:    []string{"spread="},
+	}, {
+		summary: "set spread zone",
+		args:    []string{"spread=zone"},
+	}, {
+		summary: "set unknown spread",
+		args:    []string{"spread=rack"},
+		err:     `bad "spread" constraint: "rack" not recognized`,
+	}, {
+		summary: "double set spread separately",
+		args:    []string{"spread=zone", "spread="},
+		err:     `bad "spread" constraint: already set`,
+	},
+
+	// "spot-price" in detail.
+	{
+		summary: "set spot-price empty",
+		args:    []string{"spot-price="},
+	}, {
+		summary: "set spot-price",
+		args:    []string{"spot-price=0.05"},
+	}, {
+		summary: "set spot-price integer",
+		args:    []string{"spot-price=2"},
+	}, {
+		summary: "set negative spot-price",
+		args:    []string{"spot-price=-0.5"},
+		err:     `bad "spot-price" constraint: must be a non-negative number`,
+	}, {
+		summary: "set nonsense spot-price",
+		args:    []string{"spot-price=cheap"},
+		err:     `bad "spot-price" constraint: must be a non-negative number`,
+	}, {
+		summary: "double set spot-price separately",
+		args:    []string{"spot-price=0.1", "spot-price="},
+		err:     `bad "spot-price" constraint: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -391,6 +568,29 @@ func (s *ConstraintsSuite) TestMerge(c *gc.C) {
 	c.Assert(merged, jc.DeepEquals, constraints.Value{})
 }
 
+func (s *ConstraintsSuite) TestParseRanges(c *gc.C) {
+	cons := constraints.MustParse("mem=4G..16G cores=2..8")
+	c.Check(*cons.Mem, gc.Equals, uint64(4096))
+	c.Check(*cons.MaxMem, gc.Equals, uint64(16384))
+	c.Check(*cons.CpuCores, gc.Equals, uint64(2))
+	c.Check(*cons.MaxCpuCores, gc.Equals, uint64(8))
+	c.Check(cons.String(), gc.Equals, "cores=2..8 mem=4096M..16384M")
+
+	cons = constraints.MustParse("cpu-cores=2..8")
+	c.Check(*cons.MaxCpuCores, gc.Equals, uint64(8))
+}
+
+func (s *ConstraintsSuite) TestArches(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	c.Check(cons.Arches(), gc.IsNil)
+	cons = constraints.MustParse("arch=")
+	c.Check(cons.Arches(), gc.IsNil)
+	cons = constraints.MustParse("arch=amd64")
+	c.Check(cons.Arches(), jc.DeepEquals, []string{"amd64"})
+	cons = constraints.MustParse("arch=arm64,amd64")
+	c.Check(cons.Arches(), jc.DeepEquals, []string{"arm64", "amd64"})
+}
+
 func (s *ConstraintsSuite) TestParseMissingTagsAndSpaces(c *gc.C) {
 	con := constraints.MustParse("arch=amd64 mem=4G cores=1 root-disk=8G")
 	c.Check(con.Tags, gc.IsNil)
@@ -469,6 +669,14 @@ func uint64p(i uint64) *uint64 {
 	return &i
 }
 
+func boolp(b bool) *bool {
+	return &b
+}
+
+func floatp(f float64) *float64 {
+	return &f
+}
+
 func strp(s string) *string {
 	return &s
 }
@@ -502,6 +710,8 @@ var constraintsRoundtripTests = []roundTrip{
 	{"RootDisk1", constraints.Value{RootDisk: nil}},
 	{"RootDisk2", constraints.Value{RootDisk: uint64p(0)}},
 	{"RootDisk2", constraints.Value{RootDisk: uint64p(109876)}},
+	{"RootDiskSource1", constraints.Value{RootDiskSource: strp("")}},
+	{"RootDiskSource2", constraints.Value{RootDiskSource: strp("volume")}},
 	{"Tags1", constraints.Value{Tags: nil}},
 	{"Tags2", constraints.Value{Tags: &[]string{}}},
 	{"Tags3", constraints.Value{Tags: &[]string{"foo", "bar"}}},
@@ -510,11 +720,31 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Spaces3", constraints.Value{Spaces: &[]string{"space1", "^space2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"GPUs1", constraints.Value{GPUs: uint64p(0)}},
+	{"GPUs2", constraints.Value{GPUs: uint64p(4)}},
+	{"GPUType1", constraints.Value{GPUType: strp("")}},
+	{"GPUType2", constraints.Value{GPUType: strp("nvidia-k80")}},
+	{"ImageID1", constraints.Value{ImageID: strp("")}},
+	{"ImageID2", constraints.Value{ImageID: strp("ami-12345")}},
+	{"MemRange1", constraints.Value{Mem: uint64p(4096), MaxMem: uint64p(16384)}},
+	{"MemRange2", constraints.Value{Mem: uint64p(0), MaxMem: uint64p(0)}},
+	{"CoresRange1", constraints.Value{CpuCores: uint64p(2), MaxCpuCores: uint64p(8)}},
+	{"CoresRange2", constraints.Value{CpuCores: uint64p(0), MaxCpuCores: uint64p(8)}},
+	{"Profile1", constraints.Value{Profile: strp("")}},
+	{"Profile2", constraints.Value{Profile: strp("small")}},
+	{"AllocatePublicIP1", constraints.Value{AllocatePublicIP: boolp(false)}},
+	{"AllocatePublicIP2", constraints.Value{AllocatePublicIP: boolp(true)}},
+	{"SpotPrice1", constraints.Value{SpotPrice: floatp(0)}},
+	{"SpotPrice2", constraints.Value{SpotPrice: floatp(0.125)}},
+	{"Spread1", constraints.Value{Spread: strp("")}},
+	{"Spread2", constraints.Value{Spread: strp("zone")}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxd"),
 		CpuCores:     uint64p(4096),
 		CpuPower:     uint64p(9001),
+		GPUs:         uint64p(2),
+		GPUType:      strp("nvidia-k80"),
 		Mem:          uint64p(18000000000),
 		RootDisk:     uint64p(24000000000),
 		Tags:         &[]string{"foo", "bar"},
@@ -567,6 +797,71 @@ func (s *ConstraintsSuite) TestRoundtripYaml(c *gc.C) {
 	}
 }
 
+func (s *ConstraintsSuite) TestMarshalJSON(c *gc.C) {
+	data, err := json.Marshal(constraints.MustParse("arch=amd64,i386 mem=4G..8G tags=foo,bar spread=zone"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals,
+		`{"arch":"amd64,i386","mem":4096,"max-mem":8192,"tags":["foo","bar"],"spread":"zone"}`)
+}
+
+func (s *ConstraintsSuite) TestUnmarshalJSON(c *gc.C) {
+	var cons constraints.Value
+	err := json.Unmarshal([]byte(`{
+		"arch": ["amd64", "i386"],
+		"cpu-cores": 4,
+		"mem": "4G",
+		"root-disk": 1048576,
+		"tags": "foo,bar",
+		"spaces": ["space1", "^space2"],
+		"image-id": null,
+		"not-yet-invented": "whatever"
+	}`), &cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse(
+		"arch=amd64,i386 cores=4 mem=4G root-disk=1T tags=foo,bar spaces=space1,^space2",
+	))
+}
+
+func (s *ConstraintsSuite) TestUnmarshalJSONErrors(c *gc.C) {
+	for i, t := range []struct {
+		data string
+		err  string
+	}{{
+		data: `{"cores": -1}`,
+		err:  "must be a non-negative integer",
+	}, {
+		data: `{"cores": 2, "cpu-cores": 4}`,
+		err:  `constraint "(cores|cpu-cores)" duplicates constraint "(cores|cpu-cores)"`,
+	}, {
+		data: `{"spread": "rack"}`,
+		err:  `"rack" not recognized`,
+	}, {
+		data: `{"tags": [1, 2]}`,
+		err:  "unexpected type passed as in tags: json.Number",
+	}} {
+		c.Logf("test %d: %s", i, t.data)
+		var cons constraints.Value
+		err := json.Unmarshal([]byte(t.data), &cons)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *ConstraintsSuite) TestUnmarshalYAML(c *gc.C) {
+	var cons constraints.Value
+	err := goyaml.Unmarshal([]byte(`
+arch: amd64
+cpu-cores: 2
+mem: 2G
+tags: foo,bar
+spaces: [space1]
+`), &cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("arch=amd64 cores=2 mem=2G tags=foo,bar spaces=space1"))
+
+	err = goyaml.Unmarshal([]byte("not-yet-invented: whatever"), &cons)
+	c.Assert(err, gc.ErrorMatches, "unknown constraint value: not-yet-invented")
+}
+
 var hasContainerTests = []struct {
 	constraints  string
 	hasContainer bool
@@ -597,6 +892,79 @@ func (s *ConstraintsSuite) TestHasInstanceType(c *gc.C) {
 	c.Check(cons.HasInstanceType(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestHasGPUs(c *gc.C) {
+	cons := constraints.MustParse("gpus=")
+	c.Check(cons.HasGPUs(), jc.IsFalse)
+	c.Check(cons.HasGPUType(), jc.IsFalse)
+	cons = constraints.MustParse("gpus=2 gpu-type=nvidia-k80")
+	c.Check(cons.HasGPUs(), jc.IsTrue)
+	c.Check(cons.HasGPUType(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasImageID(c *gc.C) {
+	cons := constraints.MustParse("image-id=")
+	c.Check(cons.HasImageID(), jc.IsFalse)
+	cons = constraints.MustParse("image-id=ami-12345")
+	c.Check(cons.HasImageID(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasAllocatePublicIP(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasAllocatePublicIP(), jc.IsFalse)
+	cons = constraints.MustParse("allocate-public-ip=false")
+	c.Check(cons.HasAllocatePublicIP(), jc.IsTrue)
+	c.Check(*cons.AllocatePublicIP, jc.IsFalse)
+}
+
+func (s *ConstraintsSuite) TestMatchesInstanceType(c *gc.C) {
+	for i, t := range []struct {
+		cons    string
+		name    string
+		pattern bool
+		matches bool
+	}{
+		{"", "m4.large", false, true},
+		{"instance-type=m4.large", "m4.large", false, true},
+		{"instance-type=m4.large", "m4.xlarge", false, false},
+		{"instance-type=m4.*", "m4.xlarge", true, true},
+		{"instance-type=m4.*", "m3.xlarge", true, false},
+		{"instance-type=Standard_D*", "Standard_D2_v2", true, true},
+		{"instance-type=Standard_D*_v2", "Standard_D2_v2", true, true},
+		{"instance-type=Standard_D*_v2", "Standard_D2", true, false},
+		{"instance-type=*.micro", "t2.micro", true, true},
+	} {
+		c.Logf("test %d: %s %s", i, t.cons, t.name)
+		cons := constraints.MustParse(t.cons)
+		c.Check(cons.HasInstanceTypePattern(), gc.Equals, t.pattern)
+		c.Check(cons.MatchesInstanceType(t.name), gc.Equals, t.matches)
+	}
+}
+
+func (s *ConstraintsSuite) TestHasRootDiskSource(c *gc.C) {
+	cons := constraints.MustParse("root-disk-source=")
+	c.Check(cons.HasRootDiskSource(), jc.IsFalse)
+	cons = constraints.MustParse("root-disk-source=ssd")
+	c.Check(cons.HasRootDiskSource(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasSpotPrice(c *gc.C) {
+	cons := constraints.MustParse("spot-price=")
+	c.Check(cons.HasSpotPrice(), jc.IsFalse)
+	cons = constraints.MustParse("spot-price=0")
+	c.Check(cons.HasSpotPrice(), jc.IsFalse)
+	cons = constraints.MustParse("spot-price=0.03")
+	c.Check(cons.HasSpotPrice(), jc.IsTrue)
+	c.Check(*cons.SpotPrice, gc.Equals, 0.03)
+	c.Check(cons.String(), gc.Equals, "spot-price=0.03")
+}
+
+func (s *ConstraintsSuite) TestHasSpread(c *gc.C) {
+	cons := constraints.MustParse("spread=")
+	c.Check(cons.HasSpread(), jc.IsFalse)
+	cons = constraints.MustParse("spread=zone")
+	c.Check(cons.HasSpread(), jc.IsTrue)
+}
+
 const initialWithoutCons = "root-disk=8G mem=4G arch=amd64 cpu-power=1000 cores=4 spaces=space1,^space2 tags=foo container=lxd instance-type=bar"
 
 var withoutTests = []struct {
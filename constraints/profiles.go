@@ -0,0 +1,52 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package constraints
+
+import (
+	"github.com/juju/errors"
+)
+
+// ParseProfiles parses named constraint profiles, as defined in model
+// config, and returns them keyed by name. Profiles may not refer to
+// other profiles.
+func ParseProfiles(profiles map[string]string) (map[string]Value, error) {
+	result := make(map[string]Value, len(profiles))
+	for name, args := range profiles {
+		if name == "" {
+			return nil, errors.NotValidf("empty constraint profile name")
+		}
+		cons, err := Parse(args)
+		if err != nil {
+			return nil, errors.Annotatef(err, "constraint profile %q", name)
+		}
+		if cons.Profile != nil {
+			return nil, errors.Errorf("constraint profile %q cannot refer to another profile", name)
+		}
+		result[name] = cons
+	}
+	return result, nil
+}
+
+// ExpandProfile returns cons with the constraint profile it names, if
+// any, expanded. The attributes of the profile are used for those not
+// specified in cons, and attributes in cons override any that conflict
+// with them in the profile, as defined by the given validator.
+//
+// If the named profile does not exist, an error satisfying
+// errors.IsNotFound is returned.
+func ExpandProfile(validator Validator, profiles map[string]Value, cons Value) (Value, error) {
+	if cons.Profile == nil {
+		return cons, nil
+	}
+	name := *cons.Profile
+	cons.Profile = nil
+	if name == "" {
+		return cons, nil
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return Value{}, errors.NotFoundf("constraint profile %q", name)
+	}
+	return validator.Merge(profile, cons)
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package constraints_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+)
+
+type profilesSuite struct{}
+
+var _ = gc.Suite(&profilesSuite{})
+
+func (s *profilesSuite) TestParseProfiles(c *gc.C) {
+	profiles, err := constraints.ParseProfiles(map[string]string{
+		"small":    "mem=2G cores=1",
+		"gpu-node": "gpus=1 instance-type=p2.xlarge",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, jc.DeepEquals, map[string]constraints.Value{
+		"small":    constraints.MustParse("mem=2G cores=1"),
+		"gpu-node": constraints.MustParse("gpus=1 instance-type=p2.xlarge"),
+	})
+}
+
+func (s *profilesSuite) TestParseProfilesInvalid(c *gc.C) {
+	_, err := constraints.ParseProfiles(map[string]string{"small": "mem=lots"})
+	c.Assert(err, gc.ErrorMatches, `constraint profile "small": bad "mem" constraint: .*`)
+}
+
+func (s *profilesSuite) TestParseProfilesNested(c *gc.C) {
+	_, err := constraints.ParseProfiles(map[string]string{"big": "profile=small mem=8G"})
+	c.Assert(err, gc.ErrorMatches, `constraint profile "big" cannot refer to another profile`)
+}
+
+func (s *profilesSuite) TestExpandProfileNone(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	expanded, err := constraints.ExpandProfile(constraints.NewValidator(), nil, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expanded, jc.DeepEquals, cons)
+}
+
+func (s *profilesSuite) TestExpandProfile(c *gc.C) {
+	profiles := map[string]constraints.Value{
+		"small": constraints.MustParse("mem=2G cores=1 arch=amd64"),
+	}
+	cons := constraints.MustParse("profile=small mem=4G")
+	expanded, err := constraints.ExpandProfile(constraints.NewValidator(), profiles, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expanded, jc.DeepEquals, constraints.MustParse("mem=4G cores=1 arch=amd64"))
+}
+
+func (s *profilesSuite) TestExpandProfileConflicts(c *gc.C) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts([]string{constraints.InstanceType}, []string{constraints.Mem})
+	profiles := map[string]constraints.Value{
+		"gpu-node": constraints.MustParse("gpus=1 instance-type=p2.xlarge"),
+	}
+	cons := constraints.MustParse("profile=gpu-node mem=64G")
+	expanded, err := constraints.ExpandProfile(validator, profiles, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expanded, jc.DeepEquals, constraints.MustParse("gpus=1 mem=64G"))
+}
+
+func (s *profilesSuite) TestExpandProfileNotFound(c *gc.C) {
+	cons := constraints.MustParse("profile=missing")
+	_, err := constraints.ExpandProfile(constraints.NewValidator(), nil, cons)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `constraint profile "missing" not found`)
+}
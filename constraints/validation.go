@@ -6,6 +6,7 @@ package constraints
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/juju/utils/set"
 )
@@ -27,6 +28,10 @@ type Validator interface {
 	// RegisterUnsupported records attributes which are not supported by a constraints Value.
 	RegisterUnsupported(unsupported []string)
 
+	// RegisterUnsupportedRanges records attributes which may not be specified
+	// as ranges, because the upper bounds of the ranges cannot be honoured.
+	RegisterUnsupportedRanges(attributeNames []string)
+
 	// RegisterVocabulary records allowed values for the specified constraint attribute.
 	// allowedValues is expected to be a slice/array but is declared as interface{} so
 	// that vocabs of different types can be passed in.
@@ -57,9 +62,10 @@ func NewValidator() Validator {
 }
 
 type validator struct {
-	unsupported set.Strings
-	conflicts   map[string]set.Strings
-	vocab       map[string][]interface{}
+	unsupported       set.Strings
+	unsupportedRanges set.Strings
+	conflicts         map[string]set.Strings
+	vocab             map[string][]interface{}
 }
 
 // RegisterConflicts is defined on Validator.
@@ -77,6 +83,14 @@ func (v *validator) RegisterUnsupported(unsupported []string) {
 	v.unsupported = set.NewStrings(unsupported...)
 }
 
+// RegisterUnsupportedRanges is defined on Validator.
+func (v *validator) RegisterUnsupportedRanges(attributeNames []string) {
+	v.unsupportedRanges = make(set.Strings)
+	for _, attributeName := range attributeNames {
+		v.unsupportedRanges.Add(resolveAlias(attributeName))
+	}
+}
+
 // RegisterVocabulary is defined on Validator.
 func (v *validator) RegisterVocabulary(attributeName string, allowedValues interface{}) {
 	v.vocab[resolveAlias(attributeName)] = convertToSlice(allowedValues)
@@ -140,7 +154,13 @@ func (v *validator) checkConflicts(cons Value) error {
 	attrValues := cons.attributesWithValues()
 	attrSet := make(set.Strings)
 	for attrTag := range attrValues {
-		attrSet.Add(attrTag)
+		attrSet.Add(rangeLowerBound(attrTag))
+	}
+	if cons.HasInstanceTypePattern() {
+		// A family of instance types does not determine the
+		// hardware, so it may be combined with the constraints
+		// that conflict with a single instance type.
+		attrSet.Remove(InstanceType)
 	}
 	for _, attrTag := range attrSet.SortedValues() {
 		conflicts, ok := v.conflicts[attrTag]
@@ -166,6 +186,24 @@ func (v *validator) checkUnsupported(cons Value) []string {
 // registered for it.
 func (v *validator) checkValidValues(cons Value) error {
 	for attrTag, attrValue := range cons.attributesWithValues() {
+		if attrTag == Arch && cons.HasArch() {
+			// Several architectures may be specified, and
+			// each of them must be valid.
+			for _, a := range cons.Arches() {
+				if err := v.checkInVocab(attrTag, a); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if attrTag == InstanceType && cons.HasInstanceTypePattern() {
+			// A family of instance types is valid if it
+			// matches any of the valid instance types.
+			if err := v.checkPatternInVocab(cons); err != nil {
+				return err
+			}
+			continue
+		}
 		k := reflect.TypeOf(attrValue).Kind()
 		if k == reflect.Slice || k == reflect.Array {
 			// For slices we check that all values are valid.
@@ -196,8 +234,82 @@ func (v *validator) checkInVocab(attributeName string, attributeValue interface{
 			return nil
 		}
 	}
-	return fmt.Errorf(
+	msg := fmt.Sprintf(
 		"invalid constraint value: %v=%v\nvalid values are: %v", attributeName, attributeValue, validValues)
+	if name, ok := attributeValue.(string); ok && resolveAlias(attributeName) == InstanceType {
+		if suggestion := closestValue(name, validValues); suggestion != "" {
+			msg += fmt.Sprintf("\ndid you mean %q?", suggestion)
+		}
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// closestValue returns the string value in validValues that is most
+// similar to value, for suggesting a correction to a mistyped value.
+// It returns "" if none of the values is similar enough to be a
+// plausible correction.
+func closestValue(value string, validValues []interface{}) string {
+	value = strings.ToLower(value)
+	// Allow roughly one edit for every three characters.
+	best, bestDistance := "", len(value)/3+1
+	for _, validValue := range validValues {
+		name, ok := validValue.(string)
+		if !ok {
+			continue
+		}
+		if d := editDistance(value, strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+// checkPatternInVocab returns an error if the instance type pattern in
+// cons matches none of the values in the vocab which may have been
+// registered for instance types.
+func (v *validator) checkPatternInVocab(cons Value) error {
+	validValues, ok := v.vocab[InstanceType]
+	if !ok {
+		return nil
+	}
+	for _, validValue := range validValues {
+		if name, ok := validValue.(string); ok && cons.MatchesInstanceType(name) {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid constraint value: %v=%v\nvalid values are: %v", InstanceType, *cons.InstanceType, validValues)
 }
 
 // coerce returns v in a format that allows constraint values to be easily
@@ -250,12 +362,57 @@ func withFallbacks(v Value, vFallback Value) Value {
 	return fromAttributes(vAttr)
 }
 
+// rangeUpperBounds maps the attributes of constraints that may be
+// specified as ranges to the attributes holding their upper bounds.
+var rangeUpperBounds = map[string]string{
+	Cores: maxCores,
+	Mem:   maxMem,
+}
+
+// rangeLowerBound returns the attribute holding the lower bound of the
+// range for which attrTag holds the upper bound, or attrTag otherwise.
+func rangeLowerBound(attrTag string) string {
+	for lower, upper := range rangeUpperBounds {
+		if attrTag == upper {
+			return lower
+		}
+	}
+	return attrTag
+}
+
+// checkRanges returns an error if the constraints value specifies a
+// range for an attribute which may not be specified as a range.
+func (v *validator) checkRanges(cons Value) error {
+	attrValues := cons.attributesWithValues()
+	for _, attrTag := range v.unsupportedRanges.SortedValues() {
+		if _, ok := attrValues[rangeUpperBounds[attrTag]]; ok {
+			return fmt.Errorf("%s ranges are not supported", attrTag)
+		}
+	}
+	return nil
+}
+
+// withRangeUpperBounds returns attrTags together with the upper bound
+// attributes of any range constraints among them.
+func withRangeUpperBounds(attrTags []string) []string {
+	result := append([]string(nil), attrTags...)
+	for _, attrTag := range attrTags {
+		if upper, ok := rangeUpperBounds[resolveAlias(attrTag)]; ok {
+			result = append(result, upper)
+		}
+	}
+	return result
+}
+
 // Validate is defined on Validator.
 func (v *validator) Validate(cons Value) ([]string, error) {
 	unsupported := v.checkUnsupported(cons)
 	if err := v.checkConflicts(cons); err != nil {
 		return unsupported, err
 	}
+	if err := v.checkRanges(cons); err != nil {
+		return unsupported, err
+	}
 	if err := v.checkValidValues(cons); err != nil {
 		return unsupported, err
 	}
@@ -276,12 +433,19 @@ func (v *validator) Merge(consFallback, cons Value) (Value, error) {
 	attrValues := cons.attributesWithValues()
 	var fallbackConflicts []string
 	for attrTag := range attrValues {
+		// A range constraint in cons overrides both bounds
+		// of the same constraint in consFallback.
+		attrTag = rangeLowerBound(attrTag)
+		fallbackConflicts = append(fallbackConflicts, attrTag)
+		if attrTag == InstanceType && cons.HasInstanceTypePattern() {
+			continue
+		}
 		fallbackConflicts = append(fallbackConflicts, v.conflicts[attrTag].Values()...)
 	}
 	// Null out the conflicting consFallback attribute values because
 	// cons takes priority. We can't error here because we
 	// know that aConflicts contains valid attr names.
-	consFallbackMinusConflicts := consFallback.without(fallbackConflicts...)
+	consFallbackMinusConflicts := consFallback.without(withRangeUpperBounds(fallbackConflicts)...)
 	// The result is cons with fallbacks coming from any
 	// non conflicting consFallback attributes.
 	return withFallbacks(cons, consFallbackMinusConflicts), nil
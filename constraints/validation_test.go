@@ -17,13 +17,14 @@ type validationSuite struct{}
 var _ = gc.Suite(&validationSuite{})
 
 var validationTests = []struct {
-	desc        string
-	cons        string
-	unsupported []string
-	vocab       map[string][]interface{}
-	reds        []string
-	blues       []string
-	err         string
+	desc              string
+	cons              string
+	unsupported       []string
+	unsupportedRanges []string
+	vocab             map[string][]interface{}
+	reds              []string
+	blues             []string
+	err               string
 }{
 	{
 		desc: "base good",
@@ -116,12 +117,35 @@ var validationTests = []struct {
 		vocab: map[string][]interface{}{"instance-type": {"bar"}},
 		err:   "invalid constraint value: instance-type=foo\nvalid values are:.*",
 	},
+	{
+		desc:  "mistyped instance-type vocab",
+		cons:  "instance-type=m3.larg",
+		vocab: map[string][]interface{}{"instance-type": {"m3.medium", "m3.large", "m3.xlarge"}},
+		err:   "invalid constraint value: instance-type=m3.larg\nvalid values are:.*\ndid you mean \"m3.large\"\\?",
+	},
+	{
+		desc:  "mistyped instance-type vocab ignoring case",
+		cons:  "instance-type=standard_d1",
+		vocab: map[string][]interface{}{"instance-type": {"Standard_A1", "Standard_D1"}},
+		err:   "invalid constraint value: instance-type=standard_d1\nvalid values are:.*\ndid you mean \"Standard_D1\"\\?",
+	},
 	{
 		desc:  "invalid tags vocab",
 		cons:  "mem=4G tags=foo,other",
 		vocab: map[string][]interface{}{"tags": {"foo", "bar", "another"}},
 		err:   "invalid constraint value: tags=other\nvalid values are:.*",
 	},
+	{
+		desc:  "multiple arch vocab",
+		cons:  "arch=amd64,i386 mem=4G",
+		vocab: map[string][]interface{}{"arch": {"amd64", "i386"}},
+	},
+	{
+		desc:  "invalid multiple arch vocab",
+		cons:  "arch=amd64,armhf mem=4G",
+		vocab: map[string][]interface{}{"arch": {"amd64", "i386"}},
+		err:   "invalid constraint value: arch=armhf\nvalid values are:.*",
+	},
 	{
 		desc: "instance-type and arch",
 		cons: "arch=i386 mem=4G instance-type=foo",
@@ -134,6 +158,34 @@ var validationTests = []struct {
 		cons:  "virt-type=bar",
 		vocab: map[string][]interface{}{"virt-type": {"bar"}},
 	},
+	{
+		desc:  "instance-type family vocab",
+		cons:  "instance-type=m4.*",
+		vocab: map[string][]interface{}{"instance-type": {"m3.large", "m4.large"}},
+	},
+	{
+		desc:  "invalid instance-type family vocab",
+		cons:  "instance-type=m5.*",
+		vocab: map[string][]interface{}{"instance-type": {"m3.large", "m4.large"}},
+		err:   `invalid constraint value: instance-type=m5\.\*\nvalid values are:.*`,
+	},
+	{
+		desc:              "unsupported range",
+		cons:              "mem=4G..8G cores=2",
+		unsupportedRanges: []string{"mem", "cores"},
+		err:               "mem ranges are not supported",
+	},
+	{
+		desc:              "unsupported range with single value",
+		cons:              "mem=4G cores=2",
+		unsupportedRanges: []string{"mem", "cores"},
+	},
+	{
+		desc:  "instance-type family does not conflict",
+		cons:  "mem=4G cores=2 instance-type=m4.*",
+		reds:  []string{"mem", "cores"},
+		blues: []string{"instance-type"},
+	},
 }
 
 func (s *validationSuite) TestValidation(c *gc.C) {
@@ -141,6 +193,7 @@ func (s *validationSuite) TestValidation(c *gc.C) {
 		c.Logf("test %d: %s", i, t.desc)
 		validator := constraints.NewValidator()
 		validator.RegisterUnsupported(t.unsupported)
+		validator.RegisterUnsupportedRanges(t.unsupportedRanges)
 		validator.RegisterConflicts(t.reds, t.blues)
 		for a, v := range t.vocab {
 			validator.RegisterVocabulary(a, v)
@@ -336,6 +389,25 @@ var mergeTests = []struct {
 		reds:         []string{"mem", "arch"},
 		blues:        []string{"instance-type"},
 		expected:     "root-disk=8G cores=4 arch=amd64 mem=4G",
+	}, {
+		desc:         "range overrides fallback range",
+		consFallback: "mem=4G..16G cores=2..8",
+		cons:         "mem=8G",
+		expected:     "mem=8G cores=2..8",
+	}, {
+		desc:         "range masked from fallback by conflict",
+		consFallback: "root-disk=8G mem=4G..16G",
+		cons:         "instance-type=bar",
+		reds:         []string{"mem", "arch"},
+		blues:        []string{"instance-type"},
+		expected:     "root-disk=8G instance-type=bar",
+	}, {
+		desc:         "instance type family keeps fallback conflicts",
+		consFallback: "root-disk=8G mem=4G",
+		cons:         "instance-type=m4.*",
+		reds:         []string{"mem", "arch"},
+		blues:        []string{"instance-type"},
+		expected:     "root-disk=8G mem=4G instance-type=m4.*",
 	},
 }
 
@@ -364,6 +436,13 @@ func (s *validationSuite) TestMergeError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
 }
 
+func (s *validationSuite) TestRangeConflicts(c *gc.C) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts([]string{"instance-type"}, []string{"mem"})
+	_, err := validator.Validate(constraints.MustParse("instance-type=foo mem=4G..16G"))
+	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
+}
+
 func (s *validationSuite) TestUpdateVocabulary(c *gc.C) {
 	validator := constraints.NewValidator()
 	attributeName := "arch"
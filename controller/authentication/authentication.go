@@ -7,7 +7,6 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
-	"github.com/juju/utils"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
@@ -22,27 +21,80 @@ type TaggedPasswordChanger interface {
 	Tag() names.Tag
 }
 
-// NewAuthenticator returns a simpleAuth populated with connectionInfo and apiInfo
+// NewAuthenticator returns a simpleAuth populated with connectionInfo and apiInfo.
+// If connectionInfo is nil, only API connection info will be provided
+// to machines, which is sufficient for all but controller machines.
 func NewAuthenticator(connectionInfo *mongo.MongoInfo, apiInfo *api.Info) AuthenticationProvider {
+	return NewAuthenticatorWithBackend(connectionInfo, apiInfo, randomPasswordBackend{})
+}
+
+// NewAuthenticatorWithBackend is like NewAuthenticator, but issues
+// machine passwords using the given Backend.
+func NewAuthenticatorWithBackend(connectionInfo *mongo.MongoInfo, apiInfo *api.Info, backend Backend) AuthenticationProvider {
 	return &simpleAuth{
 		stateInfo: connectionInfo,
 		apiInfo:   apiInfo,
+		backend:   backend,
 	}
 }
 
 // AuthenticationProvider defines the single method that the provisioner
 // task needs to set up authentication for a machine.
+// The returned mongo info will be nil if the provider was
+// created without it.
 type AuthenticationProvider interface {
 	SetupAuthentication(machine TaggedPasswordChanger) (*mongo.MongoInfo, *api.Info, error)
 }
 
 // NewAPIAuthenticator gets the state and api info once from the
-// provisioner API.
+// provisioner API. Machine passwords are issued by the backend named
+// in the controller's machine-auth-backend setting.
 func NewAPIAuthenticator(st *apiprovisioner.State) (AuthenticationProvider, error) {
 	stateAddresses, err := st.StateAddresses()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	apiInfo, err := apiInfoFromProvisioner(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	backend, err := backendFromProvisioner(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stateInfo := &mongo.MongoInfo{
+		Info: mongo.Info{
+			Addrs:  stateAddresses,
+			CACert: apiInfo.CACert,
+		},
+	}
+	return NewAuthenticatorWithBackend(stateInfo, apiInfo, backend), nil
+}
+
+// NewAPIOnlyAuthenticator gets the api info once from the provisioner
+// API. The returned AuthenticationProvider never provides mongo
+// connection info, so it cannot be used to set up controller machines.
+func NewAPIOnlyAuthenticator(st *apiprovisioner.State) (AuthenticationProvider, error) {
+	apiInfo, err := apiInfoFromProvisioner(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	backend, err := backendFromProvisioner(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAuthenticatorWithBackend(nil, apiInfo, backend), nil
+}
+
+func backendFromProvisioner(st *apiprovisioner.State) (Backend, error) {
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return GetBackend(controllerConfig.MachineAuthBackend())
+}
+
+func apiInfoFromProvisioner(st *apiprovisioner.State) (*api.Info, error) {
 	apiAddresses, err := st.APIAddresses()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -55,38 +107,36 @@ func NewAPIAuthenticator(st *apiprovisioner.State) (AuthenticationProvider, erro
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	stateInfo := &mongo.MongoInfo{
-		Info: mongo.Info{
-			Addrs:  stateAddresses,
-			CACert: caCert,
-		},
-	}
-	apiInfo := &api.Info{
+	return &api.Info{
 		Addrs:    apiAddresses,
 		CACert:   caCert,
 		ModelTag: names.NewModelTag(modelUUID),
-	}
-	return &simpleAuth{stateInfo, apiInfo}, nil
+	}, nil
 }
 
 type simpleAuth struct {
 	stateInfo *mongo.MongoInfo
 	apiInfo   *api.Info
+	backend   Backend
 }
 
 func (auth *simpleAuth) SetupAuthentication(machine TaggedPasswordChanger) (*mongo.MongoInfo, *api.Info, error) {
-	password, err := utils.RandomPassword()
+	password, err := auth.backend.NewPassword(machine.Tag())
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot make password for machine %v: %v", machine, err)
 	}
 	if err := machine.SetPassword(password); err != nil {
 		return nil, nil, fmt.Errorf("cannot set API password for machine %v: %v", machine, err)
 	}
-	stateInfo := *auth.stateInfo
-	stateInfo.Tag = machine.Tag()
-	stateInfo.Password = password
+	var stateInfo *mongo.MongoInfo
+	if auth.stateInfo != nil {
+		info := *auth.stateInfo
+		info.Tag = machine.Tag()
+		info.Password = password
+		stateInfo = &info
+	}
 	apiInfo := *auth.apiInfo
 	apiInfo.Tag = machine.Tag()
 	apiInfo.Password = password
-	return &stateInfo, &apiInfo, nil
+	return stateInfo, &apiInfo, nil
 }
@@ -0,0 +1,78 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v2"
+)
+
+// DefaultBackendName is the name of the built-in Backend, which
+// generates random passwords locally.
+const DefaultBackendName = "random"
+
+// Backend issues the credentials with which newly provisioned machines
+// authenticate to the controller. Sites may register their own
+// backends, for example to have passwords issued by an external
+// secrets store.
+type Backend interface {
+	// NewPassword returns a new password for the machine with
+	// the given tag.
+	NewPassword(tag names.Tag) (string, error)
+}
+
+var (
+	backendsMutex sync.Mutex
+	backends      = map[string]Backend{
+		DefaultBackendName: randomPasswordBackend{},
+	}
+)
+
+// RegisterBackend makes the given Backend available under the given
+// name, for selection with the machine-auth-backend controller setting.
+// It is intended to be called during init().
+func RegisterBackend(name string, backend Backend) error {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+	if _, ok := backends[name]; ok {
+		return errors.AlreadyExistsf("authentication backend %q", name)
+	}
+	backends[name] = backend
+	return nil
+}
+
+// UnregisterBackend removes the named Backend. It is intended for
+// use in tests.
+func UnregisterBackend(name string) {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+	delete(backends, name)
+}
+
+// GetBackend returns the Backend registered with the given name. An
+// empty name selects the default backend.
+func GetBackend(name string) (Backend, error) {
+	if name == "" {
+		name = DefaultBackendName
+	}
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+	backend, ok := backends[name]
+	if !ok {
+		return nil, errors.NotFoundf("authentication backend %q", name)
+	}
+	return backend, nil
+}
+
+// randomPasswordBackend is the default Backend, generating random
+// passwords locally.
+type randomPasswordBackend struct{}
+
+// NewPassword is part of the Backend interface.
+func (randomPasswordBackend) NewPassword(names.Tag) (string, error) {
+	return utils.RandomPassword()
+}
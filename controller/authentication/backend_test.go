@@ -0,0 +1,98 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/controller/authentication"
+	"github.com/juju/juju/mongo"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type backendSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&backendSuite{})
+
+type fixedBackend struct {
+	password string
+}
+
+func (b fixedBackend) NewPassword(tag names.Tag) (string, error) {
+	return b.password + "-" + tag.Id(), nil
+}
+
+type fakeMachine struct {
+	tag      names.Tag
+	password string
+}
+
+func (m *fakeMachine) Tag() names.Tag {
+	return m.tag
+}
+
+func (m *fakeMachine) SetPassword(password string) error {
+	m.password = password
+	return nil
+}
+
+func (s *backendSuite) TestDefaultBackend(c *gc.C) {
+	backend, err := authentication.GetBackend("")
+	c.Assert(err, jc.ErrorIsNil)
+	password, err := backend.NewPassword(names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(password, gc.Not(gc.Equals), "")
+
+	named, err := authentication.GetBackend(authentication.DefaultBackendName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(named, gc.Equals, backend)
+}
+
+func (s *backendSuite) TestRegisterBackend(c *gc.C) {
+	err := authentication.RegisterBackend("fixed", fixedBackend{"secret"})
+	c.Assert(err, jc.ErrorIsNil)
+	defer authentication.UnregisterBackend("fixed")
+
+	err = authentication.RegisterBackend("fixed", fixedBackend{"other"})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	backend, err := authentication.GetBackend("fixed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(backend, gc.Equals, fixedBackend{"secret"})
+}
+
+func (s *backendSuite) TestGetBackendNotFound(c *gc.C) {
+	_, err := authentication.GetBackend("vault")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `authentication backend "vault" not found`)
+}
+
+func (s *backendSuite) TestSetupAuthenticationUsesBackend(c *gc.C) {
+	auth := authentication.NewAuthenticatorWithBackend(
+		&mongo.MongoInfo{Info: mongo.Info{Addrs: []string{"0.1.2.3:37017"}}},
+		&api.Info{Addrs: []string{"0.1.2.3:17070"}},
+		fixedBackend{"secret"},
+	)
+	machine := &fakeMachine{tag: names.NewMachineTag("42")}
+	stateInfo, apiInfo, err := auth.SetupAuthentication(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.password, gc.Equals, "secret-42")
+	c.Assert(apiInfo.Password, gc.Equals, "secret-42")
+	c.Assert(apiInfo.Tag, gc.Equals, machine.tag)
+	c.Assert(stateInfo.Password, gc.Equals, "secret-42")
+}
+
+func (s *backendSuite) TestSetupAuthenticationAPIOnly(c *gc.C) {
+	auth := authentication.NewAuthenticatorWithBackend(nil, &api.Info{}, fixedBackend{"secret"})
+	stateInfo, apiInfo, err := auth.SetupAuthentication(&fakeMachine{tag: names.NewMachineTag("1")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateInfo, gc.IsNil)
+	c.Assert(apiInfo.Password, gc.Equals, "secret-1")
+}
@@ -4,13 +4,16 @@
 package controller
 
 import (
+	"net"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
-	utilscert "github.com/juju/utils/cert"
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
@@ -23,6 +26,13 @@ const (
 	MongoProfLow = "low"
 	// MongoProfDefault represents the mongo memory profile shipped by default.
 	MongoProfDefault = "default"
+
+	// MongoWriteConcernMajority acknowledges writes once they have
+	// been replicated to a majority of the replica set members.
+	MongoWriteConcernMajority = "majority"
+	// MongoWriteConcernOne acknowledges writes once the primary
+	// alone has written them.
+	MongoWriteConcernOne = "1"
 )
 
 const (
@@ -73,6 +83,135 @@ const (
 	// detault
 	MongoMemoryProfile = "mongo-memory-profile"
 
+	// MachineAuthBackendKey names the authentication backend used to
+	// issue credentials for newly provisioned machines. If unset, the
+	// built-in backend generating random passwords is used.
+	MachineAuthBackendKey = "machine-auth-backend"
+
+	// APIRateLimitConnectionKey sets the maximum sustained number of
+	// API requests per second accepted over a single connection. If
+	// unset or zero, the rate is not limited. Requests made by agents
+	// to the facades they need to stay connected are not limited.
+	APIRateLimitConnectionKey = "api-rate-limit-connection"
+
+	// APIRateLimitFacadeKey sets the maximum sustained number of API
+	// requests per second accepted for any one facade over a single
+	// connection. If unset or zero, the rate is not limited.
+	APIRateLimitFacadeKey = "api-rate-limit-facade"
+
+	// APIMaxConcurrentRequestsKey sets the maximum number of API
+	// requests that may run at once over a single connection. If
+	// unset or zero, the number is not limited. Watcher requests and
+	// requests agents need to stay connected are not counted.
+	APIMaxConcurrentRequestsKey = "api-max-concurrent-requests"
+
+	// APIConcurrentRequestsBurstKey sets the number of API requests
+	// beyond api-max-concurrent-requests that may wait for a running
+	// request on the same connection to complete before further
+	// requests are rejected.
+	APIConcurrentRequestsBurstKey = "api-concurrent-requests-burst"
+
+	// APIShutdownTimeoutKey sets how long the API server waits for
+	// outstanding requests to complete when it is stopped, as a
+	// duration such as "30s", before closing their connections. If
+	// unset or zero, the API server default is used.
+	APIShutdownTimeoutKey = "api-shutdown-timeout"
+
+	// AuditLogMaxAgeKey sets how long entries are kept in the audit
+	// log, as a duration such as "2160h". If unset or zero, entries
+	// are never removed.
+	AuditLogMaxAgeKey = "audit-log-max-age"
+
+	// ModelConfigHistoryMaxAgeKey sets how long previous versions of
+	// model config are kept, as a duration such as "2160h". If unset
+	// or zero, they are never removed.
+	ModelConfigHistoryMaxAgeKey = "model-config-history-max-age"
+
+	// TxnPruneMaxAgeKey sets how long completed transactions are kept
+	// before they are pruned, as a duration such as "72h". If unset
+	// or zero, every completed transaction that is no longer needed
+	// is pruned.
+	TxnPruneMaxAgeKey = "txn-prune-max-age"
+
+	// TxnPruneMaxSizeKey sets the size, such as "2G", beyond which
+	// the transactions collection is pruned of every completed
+	// transaction that is no longer needed, regardless of its age.
+	// If unset or zero, the size of the collection is not limited.
+	TxnPruneMaxSizeKey = "txn-prune-max-size"
+
+	// CharmUploadMaxSizeKey sets the largest charm archive accepted
+	// by the API server, as a size such as "512M". If unset,
+	// DefaultCharmUploadMaxSize is used; zero removes the limit.
+	CharmUploadMaxSizeKey = "charm-upload-max-size"
+
+	// ToolsUploadMaxSizeKey sets the largest tools tarball accepted
+	// by the API server, as a size such as "512M". If unset,
+	// DefaultToolsUploadMaxSize is used; zero removes the limit.
+	ToolsUploadMaxSizeKey = "tools-upload-max-size"
+
+	// BackupUploadMaxSizeKey sets the largest backup archive accepted
+	// by the API server, as a size such as "10G". If unset or zero,
+	// the size of backup uploads is not limited.
+	BackupUploadMaxSizeKey = "backup-upload-max-size"
+
+	// APIAllowedUserCIDRsKey sets the networks, as a comma-separated
+	// list of CIDRs, from which users may connect to the API. If
+	// unset, users may connect from any address.
+	APIAllowedUserCIDRsKey = "api-allowed-user-cidrs"
+
+	// APIAllowedAgentCIDRsKey sets the networks, as a comma-separated
+	// list of CIDRs, from which agents may connect to the API. If
+	// unset, agents may connect from any address. When set, it must
+	// include the addresses of the controller machines themselves.
+	APIAllowedAgentCIDRsKey = "api-allowed-agent-cidrs"
+
+	// MongoWriteConcernKey sets when the controller's writes to mongo
+	// are acknowledged: MongoWriteConcernMajority or
+	// MongoWriteConcernOne. If unset, DefaultMongoWriteConcern is
+	// used. Majority write concern only applies once mongo is
+	// running as a replica set.
+	MongoWriteConcernKey = "mongo-write-concern"
+
+	// MongoSocketTimeoutKey sets how long the controller waits for
+	// mongo to respond before a connection is considered dead, as a
+	// duration such as "1m". If unset or zero, the mongo package
+	// default is used.
+	MongoSocketTimeoutKey = "mongo-socket-timeout"
+
+	// MongoMaxPoolSizeKey sets the largest number of connections
+	// each controller agent keeps open to each mongo server. If
+	// unset or zero, the mgo driver's default is used.
+	MongoMaxPoolSizeKey = "mongo-max-pool-size"
+
+	// StatusHistoryMaxAgeKey sets how long status history entries
+	// are kept, as a duration such as "336h". If unset or zero, the
+	// status history pruner's own limit applies.
+	StatusHistoryMaxAgeKey = "status-history-max-age"
+
+	// StatusHistoryMaxEntriesKey sets the largest number of status
+	// history entries kept for each entity. If unset or zero, the
+	// number of entries is not limited.
+	StatusHistoryMaxEntriesKey = "status-history-max-entries"
+
+	// StatusHistoryRetentionKey overrides the status history limits
+	// for kinds of entity, as a comma-separated list of
+	// kind:max-age:max-entries triples, such as "unit:72h:500". An
+	// empty max-age or max-entries leaves that limit unchanged. See
+	// StatusHistoryKinds for the kinds of entity.
+	StatusHistoryRetentionKey = "status-history-retention"
+
+	// StatusHistoryDownsampleAgeKey sets the age, as a duration such
+	// as "24h", beyond which status history entries are downsampled
+	// so that each entity keeps at most one entry for every
+	// status-history-downsample-interval. If unset or zero, entries
+	// are not downsampled.
+	StatusHistoryDownsampleAgeKey = "status-history-downsample-age"
+
+	// StatusHistoryDownsampleIntervalKey sets the interval, as a
+	// duration such as "1h", to which old status history entries
+	// are downsampled.
+	StatusHistoryDownsampleIntervalKey = "status-history-downsample-interval"
+
 	// Attribute Defaults
 
 	// DefaultAuditingEnabled contains the default value for the
@@ -91,6 +230,18 @@ const (
 
 	// DefaultMongoMemoryProfile is the default profile used by mongo.
 	DefaultMongoMemoryProfile = MongoProfLow
+
+	// DefaultCharmUploadMaxSize is the default limit, in MiB, on the
+	// size of uploaded charm archives.
+	DefaultCharmUploadMaxSize = 1024
+
+	// DefaultToolsUploadMaxSize is the default limit, in MiB, on the
+	// size of uploaded tools tarballs.
+	DefaultToolsUploadMaxSize = 1024
+
+	// DefaultMongoWriteConcern is the default write concern used by
+	// the controller.
+	DefaultMongoWriteConcern = MongoWriteConcernMajority
 )
 
 // ControllerOnlyConfigAttributes are attributes which are only relevant
@@ -107,6 +258,39 @@ var ControllerOnlyConfigAttributes = []string{
 	SetNUMAControlPolicyKey,
 	StatePort,
 	MongoMemoryProfile,
+	MachineAuthBackendKey,
+	APIRateLimitConnectionKey,
+	APIRateLimitFacadeKey,
+	APIMaxConcurrentRequestsKey,
+	APIConcurrentRequestsBurstKey,
+	APIShutdownTimeoutKey,
+	AuditLogMaxAgeKey,
+	ModelConfigHistoryMaxAgeKey,
+	TxnPruneMaxAgeKey,
+	TxnPruneMaxSizeKey,
+	CharmUploadMaxSizeKey,
+	ToolsUploadMaxSizeKey,
+	BackupUploadMaxSizeKey,
+	APIAllowedUserCIDRsKey,
+	APIAllowedAgentCIDRsKey,
+	MongoWriteConcernKey,
+	MongoSocketTimeoutKey,
+	MongoMaxPoolSizeKey,
+	StatusHistoryMaxAgeKey,
+	StatusHistoryMaxEntriesKey,
+	StatusHistoryRetentionKey,
+	StatusHistoryDownsampleAgeKey,
+	StatusHistoryDownsampleIntervalKey,
+}
+
+// StatusHistoryKinds holds the kinds of entity whose status history
+// limits may be set with StatusHistoryRetentionKey.
+var StatusHistoryKinds = []string{
+	"application",
+	"filesystem",
+	"machine",
+	"unit",
+	"volume",
 }
 
 // ControllerOnlyAttribute returns true if the specified attribute name
@@ -160,6 +344,17 @@ func (c Config) mustInt(name string) int {
 	return value
 }
 
+// asInt returns the given named attribute as an int, returning 0 if
+// it isn't found.
+func (c Config) asInt(name string) int {
+	// Values obtained over the api are encoded as float64.
+	if value, ok := c[name].(float64); ok {
+		return int(value)
+	}
+	value, _ := c[name].(int)
+	return value
+}
+
 // asString is a private helper method to keep the ugly string casting
 // in once place. It returns the given named attribute as a string,
 // returning "" if it isn't found.
@@ -253,6 +448,270 @@ func (c Config) MongoMemoryProfile() string {
 	return MongoProfLow
 }
 
+// MachineAuthBackend returns the name of the authentication backend
+// used to issue machine credentials, or "" for the default.
+func (c Config) MachineAuthBackend() string {
+	return c.asString(MachineAuthBackendKey)
+}
+
+// APIRateLimitConnection returns the maximum sustained number of API
+// requests per second accepted over a single connection, or zero if
+// the rate is not limited.
+func (c Config) APIRateLimitConnection() int {
+	return c.asInt(APIRateLimitConnectionKey)
+}
+
+// APIRateLimitFacade returns the maximum sustained number of API
+// requests per second accepted for any one facade over a single
+// connection, or zero if the rate is not limited.
+func (c Config) APIRateLimitFacade() int {
+	return c.asInt(APIRateLimitFacadeKey)
+}
+
+// APIMaxConcurrentRequests returns the maximum number of API requests
+// that may run at once over a single connection, or zero if the number
+// is not limited.
+func (c Config) APIMaxConcurrentRequests() int {
+	return c.asInt(APIMaxConcurrentRequestsKey)
+}
+
+// APIConcurrentRequestsBurst returns the number of API requests beyond
+// APIMaxConcurrentRequests that may wait to run over a connection.
+func (c Config) APIConcurrentRequestsBurst() int {
+	return c.asInt(APIConcurrentRequestsBurstKey)
+}
+
+// AuditLogMaxAge returns how long entries are kept in the audit log,
+// or zero if they are kept indefinitely.
+func (c Config) AuditLogMaxAge() time.Duration {
+	// Validate has already checked that the value parses.
+	d, _ := time.ParseDuration(c.asString(AuditLogMaxAgeKey))
+	return d
+}
+
+// CharmUploadMaxSize returns the largest charm archive, in bytes,
+// accepted by the API server, or zero if the size is not limited.
+func (c Config) CharmUploadMaxSize() int64 {
+	return c.sizeBytes(CharmUploadMaxSizeKey, DefaultCharmUploadMaxSize)
+}
+
+// ToolsUploadMaxSize returns the largest tools tarball, in bytes,
+// accepted by the API server, or zero if the size is not limited.
+func (c Config) ToolsUploadMaxSize() int64 {
+	return c.sizeBytes(ToolsUploadMaxSizeKey, DefaultToolsUploadMaxSize)
+}
+
+// BackupUploadMaxSize returns the largest backup archive, in bytes,
+// accepted by the API server, or zero if the size is not limited.
+func (c Config) BackupUploadMaxSize() int64 {
+	return c.sizeBytes(BackupUploadMaxSizeKey, 0)
+}
+
+func (c Config) sizeBytes(key string, defaultMiB uint64) int64 {
+	size := defaultMiB
+	if v, ok := c[key].(string); ok {
+		// Validate has already checked that the value parses.
+		size, _ = utils.ParseSize(v)
+	}
+	return int64(size) * 1024 * 1024
+}
+
+// APIAllowedUserCIDRs returns the networks from which users may
+// connect to the API, or nil if users may connect from any address.
+func (c Config) APIAllowedUserCIDRs() []*net.IPNet {
+	// Validate has already checked that the value parses.
+	nets, _ := parseCIDRs(c.asString(APIAllowedUserCIDRsKey))
+	return nets
+}
+
+// APIAllowedAgentCIDRs returns the networks from which agents may
+// connect to the API, or nil if agents may connect from any address.
+func (c Config) APIAllowedAgentCIDRs() []*net.IPNet {
+	// Validate has already checked that the value parses.
+	nets, _ := parseCIDRs(c.asString(APIAllowedAgentCIDRsKey))
+	return nets
+}
+
+// parseCIDRs parses a comma-separated list of CIDRs.
+func parseCIDRs(value string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, cidr := range strings.Split(value, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// ModelConfigHistoryMaxAge returns how long previous versions of model
+// config are kept, or zero if they are kept indefinitely.
+func (c Config) ModelConfigHistoryMaxAge() time.Duration {
+	// Validate has already checked that the value parses.
+	d, _ := time.ParseDuration(c.asString(ModelConfigHistoryMaxAgeKey))
+	return d
+}
+
+// TxnPruneMaxAge returns how long completed transactions are kept
+// before they are pruned, or zero if they are pruned as soon as they
+// are no longer needed.
+func (c Config) TxnPruneMaxAge() time.Duration {
+	// Validate has already checked that the value parses.
+	d, _ := time.ParseDuration(c.asString(TxnPruneMaxAgeKey))
+	return d
+}
+
+// TxnPruneMaxSize returns the size, in bytes, beyond which the
+// transactions collection is pruned regardless of the age of the
+// transactions, or zero if the size is not limited.
+func (c Config) TxnPruneMaxSize() int64 {
+	return c.sizeBytes(TxnPruneMaxSizeKey, 0)
+}
+
+// MongoWriteConcern returns when the controller's writes to mongo are
+// acknowledged, either MongoWriteConcernMajority or
+// MongoWriteConcernOne.
+func (c Config) MongoWriteConcern() string {
+	if v, ok := c[MongoWriteConcernKey].(string); ok && v != "" {
+		return v
+	}
+	return DefaultMongoWriteConcern
+}
+
+// APIShutdownTimeout returns how long the API server waits for
+// outstanding requests when it is stopped, or zero if the default
+// should be used.
+func (c Config) APIShutdownTimeout() time.Duration {
+	// Validate has already checked that the value parses.
+	d, _ := time.ParseDuration(c.asString(APIShutdownTimeoutKey))
+	return d
+}
+
+// MongoSocketTimeout returns how long the controller waits for mongo
+// to respond, or zero if the default should be used.
+func (c Config) MongoSocketTimeout() time.Duration {
+	// Validate has already checked that the value parses.
+	d, _ := time.ParseDuration(c.asString(MongoSocketTimeoutKey))
+	return d
+}
+
+// MongoMaxPoolSize returns the largest number of connections kept
+// open to each mongo server, or zero if the default should be used.
+func (c Config) MongoMaxPoolSize() int {
+	return c.asInt(MongoMaxPoolSizeKey)
+}
+
+// StatusHistoryRetention limits the status history kept for an entity.
+// Zero values mean that the corresponding limit does not apply.
+type StatusHistoryRetention struct {
+	// MaxAge is how long entries are kept.
+	MaxAge time.Duration
+
+	// MaxEntries is the largest number of entries kept.
+	MaxEntries int
+}
+
+// StatusHistoryPolicy describes how status history is pruned.
+type StatusHistoryPolicy struct {
+	// Default holds the limits for entities whose kind has no
+	// limits of its own.
+	Default StatusHistoryRetention
+
+	// Kinds holds the limits for kinds of entity, keyed by one of
+	// StatusHistoryKinds.
+	Kinds map[string]StatusHistoryRetention
+
+	// DownsampleAge is the age beyond which entries are downsampled,
+	// or zero if they are not.
+	DownsampleAge time.Duration
+
+	// DownsampleInterval is the interval in which each entity keeps
+	// at most one downsampled entry.
+	DownsampleInterval time.Duration
+}
+
+// Retention returns the limits on the status history kept for the
+// given kind of entity.
+func (p StatusHistoryPolicy) Retention(kind string) StatusHistoryRetention {
+	if r, ok := p.Kinds[kind]; ok {
+		return r
+	}
+	return p.Default
+}
+
+// StatusHistoryPolicy returns the policy used to prune status history.
+func (c Config) StatusHistoryPolicy() StatusHistoryPolicy {
+	// Validate has already checked that the values parse.
+	policy := StatusHistoryPolicy{
+		Default: StatusHistoryRetention{
+			MaxEntries: c.asInt(StatusHistoryMaxEntriesKey),
+		},
+	}
+	policy.Default.MaxAge, _ = time.ParseDuration(c.asString(StatusHistoryMaxAgeKey))
+	policy.DownsampleAge, _ = time.ParseDuration(c.asString(StatusHistoryDownsampleAgeKey))
+	policy.DownsampleInterval, _ = time.ParseDuration(c.asString(StatusHistoryDownsampleIntervalKey))
+	policy.Kinds, _ = parseStatusHistoryRetention(c.asString(StatusHistoryRetentionKey), policy.Default)
+	return policy
+}
+
+// parseStatusHistoryRetention parses a comma-separated list of
+// kind:max-age:max-entries triples, filling in empty limits from
+// defaults.
+func parseStatusHistoryRetention(value string, defaults StatusHistoryRetention) (map[string]StatusHistoryRetention, error) {
+	kinds := make(map[string]StatusHistoryRetention)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		if len(fields) != 3 {
+			return nil, errors.Errorf("expected kind:max-age:max-entries, got %q", item)
+		}
+		kind := fields[0]
+		if !isStatusHistoryKind(kind) {
+			return nil, errors.NotValidf("entity kind %q", kind)
+		}
+		if _, ok := kinds[kind]; ok {
+			return nil, errors.Errorf("entity kind %q specified more than once", kind)
+		}
+		retention := defaults
+		if fields[1] != "" {
+			d, err := time.ParseDuration(fields[1])
+			if err != nil {
+				return nil, errors.Annotatef(err, "%s max-age", kind)
+			}
+			if d < 0 {
+				return nil, errors.Errorf("%s max-age: expected a non-negative duration, got %q", kind, fields[1])
+			}
+			retention.MaxAge = d
+		}
+		if fields[2] != "" {
+			n, err := strconv.Atoi(fields[2])
+			if err != nil || n < 0 {
+				return nil, errors.Errorf("%s max-entries: expected a non-negative number, got %q", kind, fields[2])
+			}
+			retention.MaxEntries = n
+		}
+		kinds[kind] = retention
+	}
+	return kinds, nil
+}
+
+func isStatusHistoryKind(kind string) bool {
+	for _, k := range StatusHistoryKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
 // NUMACtlPreference returns if numactl is preferred.
 func (c Config) NUMACtlPreference() bool {
 	if numa, ok := c[SetNUMAControlPolicyKey]; ok {
@@ -296,7 +755,7 @@ func Validate(c Config) error {
 	if !caCertOK {
 		return errors.Errorf("missing CA certificate")
 	}
-	if _, err := utilscert.ParseCert(caCert); err != nil {
+	if _, err := cert.ParseCertificates(caCert); err != nil {
 		return errors.Annotate(err, "bad CA certificate in configuration")
 	}
 
@@ -310,6 +769,73 @@ func Validate(c Config) error {
 		}
 	}
 
+	for _, key := range []string{APIRateLimitConnectionKey, APIRateLimitFacadeKey} {
+		if _, ok := c[key]; ok && c.asInt(key) < 0 {
+			return errors.Errorf("%s: expected a non-negative number of requests per second, got %v", key, c[key])
+		}
+	}
+
+	for _, key := range []string{APIMaxConcurrentRequestsKey, APIConcurrentRequestsBurstKey} {
+		if _, ok := c[key]; ok && c.asInt(key) < 0 {
+			return errors.Errorf("%s: expected a non-negative number of requests, got %v", key, c[key])
+		}
+	}
+
+	if v, ok := c[MongoWriteConcernKey].(string); ok {
+		if v != MongoWriteConcernMajority && v != MongoWriteConcernOne {
+			return errors.Errorf("%s: expected one of %q or %q, got %q", MongoWriteConcernKey, MongoWriteConcernMajority, MongoWriteConcernOne, v)
+		}
+	}
+
+	if _, ok := c[MongoMaxPoolSizeKey]; ok && c.asInt(MongoMaxPoolSizeKey) < 0 {
+		return errors.Errorf("%s: expected a non-negative number of connections, got %v", MongoMaxPoolSizeKey, c[MongoMaxPoolSizeKey])
+	}
+
+	if _, ok := c[StatusHistoryMaxEntriesKey]; ok && c.asInt(StatusHistoryMaxEntriesKey) < 0 {
+		return errors.Errorf("%s: expected a non-negative number of entries, got %v", StatusHistoryMaxEntriesKey, c[StatusHistoryMaxEntriesKey])
+	}
+
+	for _, key := range []string{
+		AuditLogMaxAgeKey, ModelConfigHistoryMaxAgeKey, TxnPruneMaxAgeKey, MongoSocketTimeoutKey, APIShutdownTimeoutKey,
+		StatusHistoryMaxAgeKey, StatusHistoryDownsampleAgeKey, StatusHistoryDownsampleIntervalKey,
+	} {
+		if v, ok := c[key].(string); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return errors.Annotatef(err, "%s", key)
+			}
+			if d < 0 {
+				return errors.Errorf("%s: expected a non-negative duration, got %q", key, v)
+			}
+		}
+	}
+
+	for _, key := range []string{CharmUploadMaxSizeKey, ToolsUploadMaxSizeKey, BackupUploadMaxSizeKey, TxnPruneMaxSizeKey} {
+		if v, ok := c[key].(string); ok {
+			if _, err := utils.ParseSize(v); err != nil {
+				return errors.Annotatef(err, "%s", key)
+			}
+		}
+	}
+
+	for _, key := range []string{APIAllowedUserCIDRsKey, APIAllowedAgentCIDRsKey} {
+		if v, ok := c[key].(string); ok {
+			if _, err := parseCIDRs(v); err != nil {
+				return errors.Annotatef(err, "%s", key)
+			}
+		}
+	}
+
+	if v, ok := c[StatusHistoryRetentionKey].(string); ok {
+		if _, err := parseStatusHistoryRetention(v, StatusHistoryRetention{}); err != nil {
+			return errors.Annotatef(err, "%s", StatusHistoryRetentionKey)
+		}
+	}
+
+	if policy := c.StatusHistoryPolicy(); policy.DownsampleAge > 0 && policy.DownsampleInterval <= 0 {
+		return errors.Errorf("%s must be set when %s is set", StatusHistoryDownsampleIntervalKey, StatusHistoryDownsampleAgeKey)
+	}
+
 	return nil
 }
 
@@ -320,25 +846,71 @@ func GenerateControllerCertAndKey(caCert, caKey string, hostAddresses []string)
 }
 
 var configChecker = schema.FieldMap(schema.Fields{
-	AuditingEnabled:         schema.Bool(),
-	APIPort:                 schema.ForceInt(),
-	StatePort:               schema.ForceInt(),
-	IdentityURL:             schema.String(),
-	IdentityPublicKey:       schema.String(),
-	SetNUMAControlPolicyKey: schema.Bool(),
-	AutocertURLKey:          schema.String(),
-	AutocertDNSNameKey:      schema.String(),
-	AllowModelAccessKey:     schema.Bool(),
-	MongoMemoryProfile:      schema.String(),
+	AuditingEnabled:                    schema.Bool(),
+	APIPort:                            schema.ForceInt(),
+	StatePort:                          schema.ForceInt(),
+	IdentityURL:                        schema.String(),
+	IdentityPublicKey:                  schema.String(),
+	SetNUMAControlPolicyKey:            schema.Bool(),
+	AutocertURLKey:                     schema.String(),
+	AutocertDNSNameKey:                 schema.String(),
+	AllowModelAccessKey:                schema.Bool(),
+	MongoMemoryProfile:                 schema.String(),
+	MachineAuthBackendKey:              schema.String(),
+	APIRateLimitConnectionKey:          schema.ForceInt(),
+	APIRateLimitFacadeKey:              schema.ForceInt(),
+	APIMaxConcurrentRequestsKey:        schema.ForceInt(),
+	APIConcurrentRequestsBurstKey:      schema.ForceInt(),
+	APIShutdownTimeoutKey:              schema.String(),
+	AuditLogMaxAgeKey:                  schema.String(),
+	ModelConfigHistoryMaxAgeKey:        schema.String(),
+	TxnPruneMaxAgeKey:                  schema.String(),
+	TxnPruneMaxSizeKey:                 schema.String(),
+	CharmUploadMaxSizeKey:              schema.String(),
+	ToolsUploadMaxSizeKey:              schema.String(),
+	BackupUploadMaxSizeKey:             schema.String(),
+	APIAllowedUserCIDRsKey:             schema.String(),
+	APIAllowedAgentCIDRsKey:            schema.String(),
+	MongoWriteConcernKey:               schema.String(),
+	MongoSocketTimeoutKey:              schema.String(),
+	MongoMaxPoolSizeKey:                schema.ForceInt(),
+	StatusHistoryMaxAgeKey:             schema.String(),
+	StatusHistoryMaxEntriesKey:         schema.ForceInt(),
+	StatusHistoryRetentionKey:          schema.String(),
+	StatusHistoryDownsampleAgeKey:      schema.String(),
+	StatusHistoryDownsampleIntervalKey: schema.String(),
 }, schema.Defaults{
-	APIPort:                 DefaultAPIPort,
-	AuditingEnabled:         DefaultAuditingEnabled,
-	StatePort:               DefaultStatePort,
-	IdentityURL:             schema.Omit,
-	IdentityPublicKey:       schema.Omit,
-	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
-	AutocertURLKey:          schema.Omit,
-	AutocertDNSNameKey:      schema.Omit,
-	AllowModelAccessKey:     schema.Omit,
-	MongoMemoryProfile:      schema.Omit,
+	APIPort:                            DefaultAPIPort,
+	AuditingEnabled:                    DefaultAuditingEnabled,
+	StatePort:                          DefaultStatePort,
+	IdentityURL:                        schema.Omit,
+	IdentityPublicKey:                  schema.Omit,
+	SetNUMAControlPolicyKey:            DefaultNUMAControlPolicy,
+	AutocertURLKey:                     schema.Omit,
+	AutocertDNSNameKey:                 schema.Omit,
+	AllowModelAccessKey:                schema.Omit,
+	MongoMemoryProfile:                 schema.Omit,
+	MachineAuthBackendKey:              schema.Omit,
+	APIRateLimitConnectionKey:          schema.Omit,
+	APIRateLimitFacadeKey:              schema.Omit,
+	APIMaxConcurrentRequestsKey:        schema.Omit,
+	APIConcurrentRequestsBurstKey:      schema.Omit,
+	APIShutdownTimeoutKey:              schema.Omit,
+	AuditLogMaxAgeKey:                  schema.Omit,
+	ModelConfigHistoryMaxAgeKey:        schema.Omit,
+	TxnPruneMaxAgeKey:                  schema.Omit,
+	TxnPruneMaxSizeKey:                 schema.Omit,
+	CharmUploadMaxSizeKey:              schema.Omit,
+	ToolsUploadMaxSizeKey:              schema.Omit,
+	BackupUploadMaxSizeKey:             schema.Omit,
+	APIAllowedUserCIDRsKey:             schema.Omit,
+	APIAllowedAgentCIDRsKey:            schema.Omit,
+	MongoWriteConcernKey:               schema.Omit,
+	MongoSocketTimeoutKey:              schema.Omit,
+	MongoMaxPoolSizeKey:                schema.Omit,
+	StatusHistoryMaxAgeKey:             schema.Omit,
+	StatusHistoryMaxEntriesKey:         schema.Omit,
+	StatusHistoryRetentionKey:          schema.Omit,
+	StatusHistoryDownsampleAgeKey:      schema.Omit,
+	StatusHistoryDownsampleIntervalKey: schema.Omit,
 })
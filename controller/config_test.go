@@ -121,6 +121,197 @@ var validateTests = []struct {
 		controller.CACertKey:         testing.CACert,
 	},
 	expectError: `invalid identity public key: wrong length for base64 key, got 3 want 32`,
+}, {
+	about: "API rate limits OK",
+	config: controller.Config{
+		controller.APIRateLimitConnectionKey: 100,
+		controller.APIRateLimitFacadeKey:     float64(20),
+		controller.CACertKey:                 testing.CACert,
+	},
+}, {
+	about: "negative API rate limit",
+	config: controller.Config{
+		controller.APIRateLimitFacadeKey: -1,
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `api-rate-limit-facade: expected a non-negative number of requests per second, got -1`,
+}, {
+	about: "audit log max age OK",
+	config: controller.Config{
+		controller.AuditLogMaxAgeKey: "2160h",
+		controller.CACertKey:         testing.CACert,
+	},
+}, {
+	about: "invalid audit log max age",
+	config: controller.Config{
+		controller.AuditLogMaxAgeKey: "three months",
+		controller.CACertKey:         testing.CACert,
+	},
+	expectError: `audit-log-max-age: time: invalid duration three months`,
+}, {
+	about: "negative audit log max age",
+	config: controller.Config{
+		controller.AuditLogMaxAgeKey: "-1h",
+		controller.CACertKey:         testing.CACert,
+	},
+	expectError: `audit-log-max-age: expected a non-negative duration, got "-1h"`,
+}, {
+	about: "upload max sizes OK",
+	config: controller.Config{
+		controller.CharmUploadMaxSizeKey:  "512M",
+		controller.ToolsUploadMaxSizeKey:  "0",
+		controller.BackupUploadMaxSizeKey: "10G",
+		controller.CACertKey:              testing.CACert,
+	},
+}, {
+	about: "invalid charm upload max size",
+	config: controller.Config{
+		controller.CharmUploadMaxSizeKey: "big",
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `charm-upload-max-size: .*`,
+}, {
+	about: "concurrent request limits OK",
+	config: controller.Config{
+		controller.APIMaxConcurrentRequestsKey:   10,
+		controller.APIConcurrentRequestsBurstKey: 5,
+		controller.CACertKey:                     testing.CACert,
+	},
+}, {
+	about: "negative concurrent request limit",
+	config: controller.Config{
+		controller.APIMaxConcurrentRequestsKey: -1,
+		controller.CACertKey:                   testing.CACert,
+	},
+	expectError: `api-max-concurrent-requests: expected a non-negative number of requests, got -1`,
+}, {
+	about: "API shutdown timeout OK",
+	config: controller.Config{
+		controller.APIShutdownTimeoutKey: "1m",
+		controller.CACertKey:             testing.CACert,
+	},
+}, {
+	about: "invalid API shutdown timeout",
+	config: controller.Config{
+		controller.APIShutdownTimeoutKey: "-1s",
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `api-shutdown-timeout: expected a non-negative duration, got "-1s"`,
+}, {
+	about: "allowed CIDRs OK",
+	config: controller.Config{
+		controller.APIAllowedUserCIDRsKey:  "10.0.0.0/8, 2001:db8::/32",
+		controller.APIAllowedAgentCIDRsKey: "192.168.0.0/16",
+		controller.CACertKey:               testing.CACert,
+	},
+}, {
+	about: "invalid allowed CIDRs",
+	config: controller.Config{
+		controller.APIAllowedUserCIDRsKey: "10.0.0.0/8,10.1.2.3",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `api-allowed-user-cidrs: invalid CIDR address: 10.1.2.3`,
+}, {
+	about: "model config history max age OK",
+	config: controller.Config{
+		controller.ModelConfigHistoryMaxAgeKey: "720h",
+		controller.CACertKey:                   testing.CACert,
+	},
+}, {
+	about: "negative model config history max age",
+	config: controller.Config{
+		controller.ModelConfigHistoryMaxAgeKey: "-1h",
+		controller.CACertKey:                   testing.CACert,
+	},
+	expectError: `model-config-history-max-age: expected a non-negative duration, got "-1h"`,
+}, {
+	about: "txn prune limits OK",
+	config: controller.Config{
+		controller.TxnPruneMaxAgeKey:  "72h",
+		controller.TxnPruneMaxSizeKey: "2G",
+		controller.CACertKey:          testing.CACert,
+	},
+}, {
+	about: "invalid txn prune max size",
+	config: controller.Config{
+		controller.TxnPruneMaxSizeKey: "lots",
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `txn-prune-max-size: .*`,
+}, {
+	about: "mongo session settings OK",
+	config: controller.Config{
+		controller.MongoWriteConcernKey:  "1",
+		controller.MongoSocketTimeoutKey: "30s",
+		controller.MongoMaxPoolSizeKey:   100,
+		controller.CACertKey:             testing.CACert,
+	},
+}, {
+	about: "invalid mongo write concern",
+	config: controller.Config{
+		controller.MongoWriteConcernKey: "2",
+		controller.CACertKey:            testing.CACert,
+	},
+	expectError: `mongo-write-concern: expected one of "majority" or "1", got "2"`,
+}, {
+	about: "invalid mongo socket timeout",
+	config: controller.Config{
+		controller.MongoSocketTimeoutKey: "-1s",
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `mongo-socket-timeout: expected a non-negative duration, got "-1s"`,
+}, {
+	about: "negative mongo max pool size",
+	config: controller.Config{
+		controller.MongoMaxPoolSizeKey: -1,
+		controller.CACertKey:           testing.CACert,
+	},
+	expectError: `mongo-max-pool-size: expected a non-negative number of connections, got -1`,
+}, {
+	about: "status history settings OK",
+	config: controller.Config{
+		controller.StatusHistoryMaxAgeKey:             "336h",
+		controller.StatusHistoryMaxEntriesKey:         1000,
+		controller.StatusHistoryRetentionKey:          "unit:72h:500, machine::100",
+		controller.StatusHistoryDownsampleAgeKey:      "24h",
+		controller.StatusHistoryDownsampleIntervalKey: "1h",
+		controller.CACertKey:                          testing.CACert,
+	},
+}, {
+	about: "negative status history max entries",
+	config: controller.Config{
+		controller.StatusHistoryMaxEntriesKey: -1,
+		controller.CACertKey:                  testing.CACert,
+	},
+	expectError: `status-history-max-entries: expected a non-negative number of entries, got -1`,
+}, {
+	about: "unknown status history kind",
+	config: controller.Config{
+		controller.StatusHistoryRetentionKey: "charm:72h:",
+		controller.CACertKey:                 testing.CACert,
+	},
+	expectError: `status-history-retention: entity kind "charm" not valid`,
+}, {
+	about: "malformed status history retention",
+	config: controller.Config{
+		controller.StatusHistoryRetentionKey: "unit:72h",
+		controller.CACertKey:                 testing.CACert,
+	},
+	expectError: `status-history-retention: expected kind:max-age:max-entries, got "unit:72h"`,
+}, {
+	about: "status history kind specified twice",
+	config: controller.Config{
+		controller.StatusHistoryRetentionKey: "unit:72h:,unit::5",
+		controller.CACertKey:                 testing.CACert,
+	},
+	expectError: `status-history-retention: entity kind "unit" specified more than once`,
+}, {
+	about: "status history downsample age without interval",
+	config: controller.Config{
+		controller.StatusHistoryDownsampleAgeKey: "24h",
+		controller.CACertKey:                     testing.CACert,
+	},
+	expectError: `status-history-downsample-interval must be set when status-history-downsample-age is set`,
 }}
 
 func (s *ConfigSuite) TestValidate(c *gc.C) {
@@ -134,3 +325,106 @@ func (s *ConfigSuite) TestValidate(c *gc.C) {
 		}
 	}
 }
+
+func (s *ConfigSuite) TestUploadMaxSizes(c *gc.C) {
+	cfg := controller.Config{}
+	c.Check(cfg.CharmUploadMaxSize(), gc.Equals, int64(controller.DefaultCharmUploadMaxSize*1024*1024))
+	c.Check(cfg.ToolsUploadMaxSize(), gc.Equals, int64(controller.DefaultToolsUploadMaxSize*1024*1024))
+	c.Check(cfg.BackupUploadMaxSize(), gc.Equals, int64(0))
+
+	cfg = controller.Config{
+		controller.CharmUploadMaxSizeKey:  "2M",
+		controller.ToolsUploadMaxSizeKey:  "0",
+		controller.BackupUploadMaxSizeKey: "1G",
+	}
+	c.Check(cfg.CharmUploadMaxSize(), gc.Equals, int64(2*1024*1024))
+	c.Check(cfg.ToolsUploadMaxSize(), gc.Equals, int64(0))
+	c.Check(cfg.BackupUploadMaxSize(), gc.Equals, int64(1024*1024*1024))
+}
+
+func (s *ConfigSuite) TestTxnPruneLimits(c *gc.C) {
+	cfg := controller.Config{}
+	c.Check(cfg.TxnPruneMaxAge(), gc.Equals, time.Duration(0))
+	c.Check(cfg.TxnPruneMaxSize(), gc.Equals, int64(0))
+
+	cfg = controller.Config{
+		controller.TxnPruneMaxAgeKey:  "72h",
+		controller.TxnPruneMaxSizeKey: "2G",
+	}
+	c.Check(cfg.TxnPruneMaxAge(), gc.Equals, 72*time.Hour)
+	c.Check(cfg.TxnPruneMaxSize(), gc.Equals, int64(2*1024*1024*1024))
+}
+
+func (s *ConfigSuite) TestMongoSessionSettings(c *gc.C) {
+	cfg := controller.Config{}
+	c.Check(cfg.MongoWriteConcern(), gc.Equals, controller.MongoWriteConcernMajority)
+	c.Check(cfg.MongoSocketTimeout(), gc.Equals, time.Duration(0))
+	c.Check(cfg.MongoMaxPoolSize(), gc.Equals, 0)
+
+	cfg = controller.Config{
+		controller.MongoWriteConcernKey:  "1",
+		controller.MongoSocketTimeoutKey: "30s",
+		controller.MongoMaxPoolSizeKey:   100,
+	}
+	c.Check(cfg.MongoWriteConcern(), gc.Equals, controller.MongoWriteConcernOne)
+	c.Check(cfg.MongoSocketTimeout(), gc.Equals, 30*time.Second)
+	c.Check(cfg.MongoMaxPoolSize(), gc.Equals, 100)
+}
+
+func (s *ConfigSuite) TestAPIShutdownTimeout(c *gc.C) {
+	cfg := controller.Config{}
+	c.Check(cfg.APIShutdownTimeout(), gc.Equals, time.Duration(0))
+
+	cfg = controller.Config{controller.APIShutdownTimeoutKey: "1m"}
+	c.Check(cfg.APIShutdownTimeout(), gc.Equals, time.Minute)
+}
+
+func (s *ConfigSuite) TestStatusHistoryPolicy(c *gc.C) {
+	cfg := controller.Config{}
+	policy := cfg.StatusHistoryPolicy()
+	c.Check(policy.Default, gc.Equals, controller.StatusHistoryRetention{})
+	c.Check(policy.Retention("unit"), gc.Equals, controller.StatusHistoryRetention{})
+	c.Check(policy.DownsampleAge, gc.Equals, time.Duration(0))
+
+	cfg = controller.Config{
+		controller.StatusHistoryMaxAgeKey:             "336h",
+		controller.StatusHistoryMaxEntriesKey:         1000,
+		controller.StatusHistoryRetentionKey:          "unit:72h:500, machine::100",
+		controller.StatusHistoryDownsampleAgeKey:      "24h",
+		controller.StatusHistoryDownsampleIntervalKey: "1h",
+	}
+	policy = cfg.StatusHistoryPolicy()
+	c.Check(policy.Retention("application"), gc.Equals, controller.StatusHistoryRetention{
+		MaxAge:     336 * time.Hour,
+		MaxEntries: 1000,
+	})
+	c.Check(policy.Retention("unit"), gc.Equals, controller.StatusHistoryRetention{
+		MaxAge:     72 * time.Hour,
+		MaxEntries: 500,
+	})
+	c.Check(policy.Retention("machine"), gc.Equals, controller.StatusHistoryRetention{
+		MaxAge:     336 * time.Hour,
+		MaxEntries: 100,
+	})
+	c.Check(policy.DownsampleAge, gc.Equals, 24*time.Hour)
+	c.Check(policy.DownsampleInterval, gc.Equals, time.Hour)
+}
+
+func (s *ConfigSuite) TestAPIAllowedCIDRs(c *gc.C) {
+	cfg := controller.Config{}
+	c.Check(cfg.APIAllowedUserCIDRs(), gc.HasLen, 0)
+	c.Check(cfg.APIAllowedAgentCIDRs(), gc.HasLen, 0)
+
+	cfg = controller.Config{
+		controller.APIAllowedUserCIDRsKey:  "10.0.0.0/8, 2001:db8::/32",
+		controller.APIAllowedAgentCIDRsKey: "192.168.1.0/24",
+	}
+	var users []string
+	for _, n := range cfg.APIAllowedUserCIDRs() {
+		users = append(users, n.String())
+	}
+	c.Check(users, jc.DeepEquals, []string{"10.0.0.0/8", "2001:db8::/32"})
+	agents := cfg.APIAllowedAgentCIDRs()
+	c.Assert(agents, gc.HasLen, 1)
+	c.Check(agents[0].String(), gc.Equals, "192.168.1.0/24")
+}
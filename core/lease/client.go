@@ -51,6 +51,10 @@ type Info struct {
 	// be valid. Attempting to expire the lease before this time will fail.
 	Expiry time.Time
 
+	// Extensions is the number of times the lease has been extended
+	// since it was last claimed.
+	Extensions int
+
 	// Trapdoor exposes the originating Client's persistence substrate, if the
 	// substrate exposes any such capability. It's useful specifically for
 	// integrating mgo/txn-based components: which thus get a mechanism for
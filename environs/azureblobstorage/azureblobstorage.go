@@ -0,0 +1,518 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package azureblobstorage provides an implementation of storage.Storage
+// backed by an Azure Blob storage container.
+//
+// The Blob service REST API is used directly, since the vendored Azure
+// SDK client requires an account key, and this storage may instead be
+// given a shared access signature.
+package azureblobstorage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+)
+
+// apiVersion is the version of the Blob service REST API used.
+const apiVersion = "2016-05-31"
+
+// NewFromModelConfig returns a storage.Storage using the Azure storage
+// settings in the given model config. If Azure storage is not
+// configured, an error satisfying errors.IsNotFound is returned.
+func NewFromModelConfig(cfg *config.Config) (storage.Storage, error) {
+	settings, ok := cfg.AzureStorage()
+	if !ok {
+		return nil, errors.NotFoundf("Azure storage settings")
+	}
+	stor, err := New(settings, storage.HTTPClient)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stor.(*blobStorage).cfg = cfg
+	return stor, nil
+}
+
+// New returns a storage.Storage that keeps its files as block blobs in
+// the container described by the given settings. The container is
+// created when the first file is put, if it does not already exist;
+// this requires that a shared access signature grant account-level
+// access.
+func New(settings config.AzureStorageSettings, client *http.Client) (storage.Storage, error) {
+	if settings.Account == "" {
+		return nil, errors.NotValidf("empty storage account name")
+	}
+	if settings.Container == "" {
+		return nil, errors.NotValidf("empty container name")
+	}
+	endpoint := settings.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", settings.Account)
+	}
+	baseURL, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing endpoint")
+	}
+	var sas url.Values
+	if settings.SASToken != "" {
+		sas, err = url.ParseQuery(strings.TrimPrefix(settings.SASToken, "?"))
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing shared access signature")
+		}
+	} else if settings.AccountKey == "" {
+		return nil, errors.NotValidf("missing account key and shared access signature")
+	}
+	stor := &blobStorage{
+		baseURL:   baseURL,
+		account:   settings.Account,
+		container: settings.Container,
+		sas:       sas,
+		client:    client,
+	}
+	if settings.AccountKey != "" {
+		stor.signer, err = newSharedKeySigner(settings.Account, settings.AccountKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return stor, nil
+}
+
+// blobStorage implements storage.Storage on an Azure Blob container.
+type blobStorage struct {
+	baseURL   *url.URL
+	account   string
+	container string
+	client    *http.Client
+
+	// Exactly one of sas and signer is set.
+	sas    url.Values
+	signer *sharedKeySigner
+
+	// cfg holds the model config the storage was created from,
+	// if any.
+	cfg *config.Config
+
+	mu            sync.Mutex
+	madeContainer bool
+}
+
+// url returns the URL for the given blob, or of the container if
+// name is empty, with the given query.
+func (s *blobStorage) url(name string, query url.Values) *url.URL {
+	u := *s.baseURL
+	u.Path += "/" + s.container
+	if name != "" {
+		u.Path += "/" + name
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	for k, v := range s.sas {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return &u
+}
+
+// do sends an authorized request for the given blob, or the container
+// if name is empty.
+func (s *blobStorage) do(method, name string, query url.Values, header http.Header, body io.Reader, length int64) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.url(name, query).String(), body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = length
+	req.Header.Set("x-ms-version", apiVersion)
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	if s.signer != nil {
+		s.signer.sign(req)
+	}
+	resp, err := s.client.Do(req)
+	return resp, errors.Trace(err)
+}
+
+// makeContainer makes the container in which blobs are stored. To
+// avoid two round trips on every PUT operation, we do this only once.
+func (s *blobStorage) makeContainer() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.madeContainer {
+		return nil
+	}
+	resp, err := s.do("PUT", "", url.Values{"restype": {"container"}}, nil, nil, 0)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	// A conflict means that the container already exists.
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusConflict {
+		return errors.New(storage.HTTPResponseError(resp))
+	}
+	s.madeContainer = true
+	return nil
+}
+
+// Put is specified in the StorageWriter interface.
+func (s *blobStorage) Put(name string, r io.Reader, length int64) error {
+	return s.put(name, r, length, nil)
+}
+
+// sha256Header is the blob metadata header holding the hash of a blob
+// written by PutHashed.
+const sha256Header = "X-Ms-Meta-Sha256"
+
+// PutHashed is specified in the storage.HashedWriter interface. The
+// hash is kept in the blob's metadata, so blobs written by Put are
+// always uploaded again. Large blobs are uploaded in blocks, as by
+// storage.PutMultipart.
+func (s *blobStorage) PutHashed(name, sha256 string, r io.Reader, length int64) error {
+	resp, err := s.do("HEAD", name, nil, nil, nil, 0)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && resp.Header.Get(sha256Header) == sha256 && resp.ContentLength == length {
+		return nil
+	}
+	header := http.Header{sha256Header: {sha256}}
+	return storage.PutMultipart(&headerWriter{s, header}, name, r, length)
+}
+
+// headerWriter is a blobStorage that sets the given headers on every
+// blob it writes, whether in one request or in blocks.
+type headerWriter struct {
+	*blobStorage
+	header http.Header
+}
+
+// Put is specified in the StorageWriter interface.
+func (w *headerWriter) Put(name string, r io.Reader, length int64) error {
+	return w.put(name, r, length, w.header)
+}
+
+// InitMultipart is specified in the storage.MultipartWriter interface.
+func (w *headerWriter) InitMultipart(name string) (storage.MultipartUpload, error) {
+	if err := w.makeContainer(); err != nil {
+		return nil, errors.Annotatef(err, "cannot make Azure container %q", w.container)
+	}
+	return &blockUpload{storage: w.blobStorage, name: name, header: w.header}, nil
+}
+
+func (s *blobStorage) put(name string, r io.Reader, length int64, header http.Header) error {
+	if err := s.makeContainer(); err != nil {
+		return errors.Annotatef(err, "cannot make Azure container %q", s.container)
+	}
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("X-Ms-Blob-Type", "BlockBlob")
+	resp, err := s.do("PUT", name, nil, header, r, length)
+	if err != nil {
+		return errors.Annotatef(err, "cannot write file %q to Azure container %q", name, s.container)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return errors.Errorf(
+			"cannot write file %q to Azure container %q: %s",
+			name, s.container, storage.HTTPResponseError(resp),
+		)
+	}
+	return nil
+}
+
+// PutWithOptions is specified in the storage.OptionsWriter interface.
+// Azure Storage always encrypts blobs at rest with Microsoft-managed
+// keys, so EncryptionSSE requires nothing more. Customer-managed keys
+// are configured for the whole storage account, and cannot be chosen
+// per blob.
+func (s *blobStorage) PutWithOptions(name string, r io.Reader, length int64, opts storage.PutOptions) error {
+	if opts.Encryption == storage.EncryptionSSEKMS {
+		return errors.NotSupportedf("per-blob KMS encryption")
+	}
+	return s.Put(name, r, length)
+}
+
+// InitMultipart is specified in the storage.MultipartWriter interface.
+// Parts are uploaded as uncommitted blocks of a block blob, and
+// committed together when the upload is completed.
+func (s *blobStorage) InitMultipart(name string) (storage.MultipartUpload, error) {
+	if err := s.makeContainer(); err != nil {
+		return nil, errors.Annotatef(err, "cannot make Azure container %q", s.container)
+	}
+	return &blockUpload{storage: s, name: name}, nil
+}
+
+// blockUpload implements storage.MultipartUpload with Put Block and
+// Put Block List requests. The header, if any, is sent with the Put
+// Block List request that commits the blob.
+type blockUpload struct {
+	storage *blobStorage
+	name    string
+	header  http.Header
+	parts   []int
+}
+
+// blockID returns the block ID for the given part number. All block
+// IDs within a blob must have the same length.
+func blockID(n int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%06d", n)))
+}
+
+// PutPart is specified in the storage.MultipartUpload interface.
+func (u *blockUpload) PutPart(n int, r io.ReadSeeker, length int64) error {
+	query := url.Values{
+		"comp":    {"block"},
+		"blockid": {blockID(n)},
+	}
+	resp, err := u.storage.do("PUT", u.name, query, nil, r, length)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return errors.New(storage.HTTPResponseError(resp))
+	}
+	for _, part := range u.parts {
+		if part == n {
+			return nil
+		}
+	}
+	u.parts = append(u.parts, n)
+	return nil
+}
+
+// blockList is the body of a Put Block List request.
+type blockList struct {
+	XMLName xml.Name `xml:"BlockList"`
+	Latest  []string `xml:"Latest"`
+}
+
+// Complete is specified in the storage.MultipartUpload interface.
+func (u *blockUpload) Complete() error {
+	sort.Ints(u.parts)
+	var list blockList
+	for _, n := range u.parts {
+		list.Latest = append(list.Latest, blockID(n))
+	}
+	body, err := xml.Marshal(list)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := url.Values{"comp": {"blocklist"}}
+	resp, err := u.storage.do("PUT", u.name, query, u.header, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return errors.New(storage.HTTPResponseError(resp))
+	}
+	return nil
+}
+
+// Abort is specified in the storage.MultipartUpload interface. There
+// is no way to delete uncommitted blocks; the Blob service discards
+// them after a week.
+func (u *blockUpload) Abort() error {
+	return nil
+}
+
+// Get is specified in the StorageReader interface.
+func (s *blobStorage) Get(name string) (io.ReadCloser, error) {
+	resp, err := s.do("GET", name, nil, nil, nil, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	defer resp.Body.Close()
+	return nil, errors.Errorf("cannot read file %q: %s", name, storage.HTTPResponseError(resp))
+}
+
+// GetRange is specified in the storage.RangeReader interface.
+func (s *blobStorage) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	header := http.Header{"Range": {storage.HTTPRange(offset, length)}}
+	resp, err := s.do("GET", name, nil, header, nil, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusOK:
+		// The range was ignored, so the whole file is returned.
+		return storage.LimitRange(resp.Body, offset, length)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The offset is at or beyond the end of the file.
+		resp.Body.Close()
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	defer resp.Body.Close()
+	return nil, errors.Errorf("cannot read file %q: %s", name, storage.HTTPResponseError(resp))
+}
+
+// listBlobsResponse is the response to a List Blobs request.
+type listBlobsResponse struct {
+	Blobs struct {
+		Blob []struct {
+			Name string `xml:"Name"`
+		} `xml:"Blob"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// List is specified in the StorageReader interface.
+func (s *blobStorage) List(prefix string) ([]string, error) {
+	return storage.ListPages(s, prefix)
+}
+
+// ListPage is specified in the storage.PagedLister interface.
+func (s *blobStorage) ListPage(prefix, token string, maxItems int) ([]string, string, error) {
+	query := url.Values{
+		"restype": {"container"},
+		"comp":    {"list"},
+	}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if token != "" {
+		query.Set("marker", token)
+	}
+	if maxItems > 0 {
+		query.Set("maxresults", fmt.Sprint(maxItems))
+	}
+	resp, err := s.do("GET", "", query, nil, nil, 0)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	// The container is only created when the first file is
+	// put, so a missing container is not an error.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.Errorf("cannot list files: %s", storage.HTTPResponseError(resp))
+	}
+	var result listBlobsResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", errors.Annotate(err, "decoding file list")
+	}
+	names := make([]string, len(result.Blobs.Blob))
+	for i, blob := range result.Blobs.Blob {
+		names[i] = blob.Name
+	}
+	return names, result.NextMarker, nil
+}
+
+// URL is specified in the StorageReader interface. With an account key,
+// a read-only shared access signature for the blob is generated; with
+// a shared access signature, the signature itself is used.
+func (s *blobStorage) URL(name string) (string, error) {
+	if s.signer == nil {
+		return s.url(name, nil).String(), nil
+	}
+	return s.SignedURL(name, storage.URLExpiry())
+}
+
+// SignedURL is specified in the storage.SignedURLReader interface. A
+// shared access signature can only be generated with an account key.
+func (s *blobStorage) SignedURL(name string, expires time.Time) (string, error) {
+	if s.signer == nil {
+		return "", errors.NotSupportedf("signed URLs without an account key")
+	}
+	query := s.signer.blobSAS(s.container, name, expires)
+	return s.url(name, query).String(), nil
+}
+
+// DefaultConsistencyStrategy is specified in the StorageReader interface.
+func (s *blobStorage) DefaultConsistencyStrategy() utils.AttemptStrategy {
+	return storage.HTTPAttempt
+}
+
+// Consistency is specified in the storage.ConsistencyReporter interface.
+func (s *blobStorage) Consistency() storage.Consistency {
+	return storage.StrongConsistency
+}
+
+// RetryStrategy is specified in the storage.OperationRetrier interface.
+// The retry budgets in the model config the storage was created from,
+// if any, override the defaults.
+func (s *blobStorage) RetryStrategy(op config.StorageOperation) utils.AttemptStrategy {
+	return storage.RetryStrategy(s, op, s.cfg)
+}
+
+// ShouldRetry is specified in the StorageReader interface. Azure Blob
+// storage is strongly consistent, so errors are never retried.
+func (s *blobStorage) ShouldRetry(err error) bool {
+	return false
+}
+
+// Remove is specified in the StorageWriter interface.
+func (s *blobStorage) Remove(name string) error {
+	resp, err := s.do("DELETE", name, nil, nil, nil, 0)
+	if err != nil {
+		return errors.Annotatef(err, "cannot remove file %q", name)
+	}
+	defer resp.Body.Close()
+	// If the blob or container does not exist, then we don't care.
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNotFound {
+		return errors.Errorf("cannot remove file %q: %s", name, storage.HTTPResponseError(resp))
+	}
+	return nil
+}
+
+// RemoveAll is specified in the StorageWriter interface.
+func (s *blobStorage) RemoveAll() error {
+	names, err := storage.List(s, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range names {
+		if err := s.Remove(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.madeContainer = false
+	resp, err := s.do("DELETE", "", url.Values{"restype": {"container"}}, nil, nil, 0)
+	if err != nil {
+		return errors.Annotatef(err, "cannot remove Azure container %q", s.container)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNotFound {
+		return errors.Errorf("cannot remove Azure container %q: %s", s.container, storage.HTTPResponseError(resp))
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azureblobstorage_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/azureblobstorage"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+// fakeBlobService is a minimal Azure Blob service, serving a single
+// storage account.
+type fakeBlobService struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	requests   []*http.Request
+	containers map[string]map[string][]byte
+	blocks     map[string][]byte
+	hashes     map[string]string
+	puts       int
+	blockLists int
+}
+
+func newFakeBlobService() *fakeBlobService {
+	f := &fakeBlobService{
+		containers: make(map[string]map[string][]byte),
+		blocks:     make(map[string][]byte),
+		hashes:     make(map[string]string),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+type enumerationResults struct {
+	XMLName xml.Name `xml:"EnumerationResults"`
+	Blobs   []string `xml:"Blobs>Blob>Name"`
+}
+
+func (f *fakeBlobService) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	parts := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)
+	container := f.containers[parts[0]]
+	query := req.URL.Query()
+	if len(parts) == 1 {
+		switch req.Method {
+		case "PUT":
+			if container != nil {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			f.containers[parts[0]] = make(map[string][]byte)
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			if container == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(f.containers, parts[0])
+			w.WriteHeader(http.StatusAccepted)
+		case "GET":
+			if container == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var result enumerationResults
+			for name := range container {
+				if strings.HasPrefix(name, query.Get("prefix")) {
+					result.Blobs = append(result.Blobs, name)
+				}
+			}
+			sort.Strings(result.Blobs)
+			xml.NewEncoder(w).Encode(result)
+		}
+		return
+	}
+	if container == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch req.Method {
+	case "PUT":
+		data, _ := ioutil.ReadAll(req.Body)
+		switch query.Get("comp") {
+		case "block":
+			f.blocks[parts[1]+"/"+query.Get("blockid")] = data
+		case "blocklist":
+			var list struct {
+				Latest []string `xml:"Latest"`
+			}
+			xml.Unmarshal(data, &list)
+			var blob []byte
+			for _, id := range list.Latest {
+				blob = append(blob, f.blocks[parts[1]+"/"+id]...)
+			}
+			container[parts[1]] = blob
+			f.hashes[parts[1]] = req.Header.Get("X-Ms-Meta-Sha256")
+			f.blockLists++
+		default:
+			container[parts[1]] = data
+			f.hashes[parts[1]] = req.Header.Get("X-Ms-Meta-Sha256")
+			f.puts++
+		}
+		w.WriteHeader(http.StatusCreated)
+	case "HEAD":
+		data, ok := container[parts[1]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if hash := f.hashes[parts[1]]; hash != "" {
+			w.Header().Set("X-Ms-Meta-Sha256", hash)
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	case "GET":
+		data, ok := container[parts[1]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r := req.Header.Get("Range"); r != "" {
+			start, end := 0, len(data)-1
+			fmt.Sscanf(r, "bytes=%d-%d", &start, &end)
+			if start >= len(data) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			if end >= len(data) {
+				end = len(data) - 1
+			}
+			w.WriteHeader(http.StatusPartialContent)
+			data = data[start : end+1]
+		}
+		w.Write(data)
+	case "DELETE":
+		if _, ok := container[parts[1]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(container, parts[1])
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+type azureblobstorageSuite struct {
+	testing.BaseSuite
+	service *fakeBlobService
+	storage storage.Storage
+}
+
+var _ = gc.Suite(&azureblobstorageSuite{})
+
+func (s *azureblobstorageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.service = newFakeBlobService()
+	s.AddCleanup(func(*gc.C) { s.service.Close() })
+	var err error
+	s.storage, err = azureblobstorage.New(config.AzureStorageSettings{
+		Account:   "account",
+		Container: "juju-test",
+		Endpoint:  s.service.URL,
+		SASToken:  "?sv=2016-05-31&sig=abc",
+	}, http.DefaultClient)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *azureblobstorageSuite) TestPutGet(c *gc.C) {
+	data := []byte("hello")
+	err := s.storage.Put("a/b", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	r, err := s.storage.Get("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, data)
+
+	for _, req := range s.service.requests {
+		c.Check(req.URL.Query().Get("sig"), gc.Equals, "abc")
+		c.Check(req.Header.Get("x-ms-version"), gc.Equals, "2016-05-31")
+		c.Check(req.Header.Get("Authorization"), gc.Equals, "")
+	}
+	c.Assert(s.service.requests[1].Header.Get("x-ms-blob-type"), gc.Equals, "BlockBlob")
+}
+
+func (s *azureblobstorageSuite) TestMultipart(c *gc.C) {
+	upload, err := s.storage.(storage.MultipartWriter).InitMultipart("a")
+	c.Assert(err, jc.ErrorIsNil)
+	for _, n := range []int{2, 1, 2} {
+		part := strings.Repeat(fmt.Sprint(n), 3)
+		err := upload.PutPart(n, strings.NewReader(part), int64(len(part)))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = upload.Complete()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(s.service.containers["juju-test"]["a"]), gc.Equals, "111222")
+}
+
+func (s *azureblobstorageSuite) TestGetRange(c *gc.C) {
+	data := []byte("0123456789")
+	err := s.storage.Put("a", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, test := range []struct {
+		offset, length int64
+		expect         string
+	}{
+		{3, 4, "3456"},
+		{7, -1, "789"},
+		{12, -1, ""},
+	} {
+		r, err := storage.GetRange(s.storage, "a", test.offset, test.length)
+		c.Assert(err, jc.ErrorIsNil)
+		got, err := ioutil.ReadAll(r)
+		r.Close()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(string(got), gc.Equals, test.expect)
+	}
+
+	_, err = storage.GetRange(s.storage, "missing", 3, 4)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *azureblobstorageSuite) TestPutHashed(c *gc.C) {
+	data := []byte("hello")
+	for i := 0; i < 2; i++ {
+		err := storage.PutHashed(s.storage, "a", "abc", bytes.NewReader(data), int64(len(data)))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(s.service.puts, gc.Equals, 1)
+
+	err := storage.PutHashed(s.storage, "a", "def", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.puts, gc.Equals, 2)
+}
+
+func (s *azureblobstorageSuite) TestPutHashedLargeUsesBlocks(c *gc.C) {
+	// Files larger than a single part are uploaded in blocks.
+	data := bytes.Repeat([]byte("x"), 16*1024*1024+1)
+	err := storage.PutHashed(s.storage, "a", "abc", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.puts, gc.Equals, 0)
+	c.Assert(s.service.blockLists, gc.Equals, 1)
+	c.Assert(s.service.containers["juju-test"]["a"], jc.DeepEquals, data)
+
+	// The hash is recorded when the blocks are committed.
+	err = storage.PutHashed(s.storage, "a", "abc", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.blockLists, gc.Equals, 1)
+}
+
+func (s *azureblobstorageSuite) TestPutHashedAfterPut(c *gc.C) {
+	data := []byte("hello")
+	err := s.storage.Put("a", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	err = storage.PutHashed(s.storage, "a", "abc", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.puts, gc.Equals, 2)
+}
+
+func (s *azureblobstorageSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.storage.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *azureblobstorageSuite) TestList(c *gc.C) {
+	names, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+
+	for _, name := range []string{"b/c", "a", "b/d", "c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	names, err = s.storage.List("b/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"b/c", "b/d"})
+}
+
+func (s *azureblobstorageSuite) TestRemoveAll(c *gc.C) {
+	for _, name := range []string{"a", "b/c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storage.RemoveAll()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.containers, gc.HasLen, 0)
+}
+
+func (s *azureblobstorageSuite) TestURLWithSAS(c *gc.C) {
+	u, err := s.storage.URL("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u, gc.Equals, s.service.URL+"/juju-test/a/b?sig=abc&sv=2016-05-31")
+}
+
+func (s *azureblobstorageSuite) TestSignedURLWithSAS(c *gc.C) {
+	// A shared access signature cannot be used to sign further URLs.
+	_, err := s.storage.(storage.SignedURLReader).SignedURL("a/b", time.Now().Add(time.Hour))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *azureblobstorageSuite) TestAccountKey(c *gc.C) {
+	stor, err := azureblobstorage.New(config.AzureStorageSettings{
+		Account:    "account",
+		Container:  "juju-test",
+		Endpoint:   s.service.URL,
+		AccountKey: "a2V5",
+	}, http.DefaultClient)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stor.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.requests, gc.HasLen, 1)
+	c.Assert(s.service.requests[0].Header.Get("Authorization"), gc.Matches, "SharedKey account:.+")
+
+	u, err := stor.URL("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	parsed, err := url.Parse(u)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parsed.Path, gc.Equals, "/juju-test/a/b")
+	query := parsed.Query()
+	c.Assert(query.Get("sp"), gc.Equals, "r")
+	c.Assert(query.Get("sr"), gc.Equals, "b")
+	c.Assert(query.Get("sig"), gc.Not(gc.Equals), "")
+}
+
+func (s *azureblobstorageSuite) TestNewInvalidAccountKey(c *gc.C) {
+	_, err := azureblobstorage.New(config.AzureStorageSettings{
+		Account:    "account",
+		Container:  "juju-test",
+		AccountKey: "not base64!",
+	}, http.DefaultClient)
+	c.Assert(err, gc.ErrorMatches, "decoding account key: .*")
+}
+
+func (s *azureblobstorageSuite) TestNewFromModelConfigNotConfigured(c *gc.C) {
+	_, err := azureblobstorage.NewFromModelConfig(testing.ModelConfig(c))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -26,12 +26,14 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
+	jujucert "github.com/juju/juju/cert"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/gui"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
@@ -214,14 +216,17 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 
 	var bootstrapSeries *string
 	if args.BootstrapSeries != "" {
+		if err := environs.CheckSeriesSupported(environ, args.BootstrapSeries); err != nil {
+			return errors.Trace(err)
+		}
 		bootstrapSeries = &args.BootstrapSeries
 	}
 
 	var bootstrapArchForImageSearch string
-	if args.BootstrapConstraints.Arch != nil {
-		bootstrapArchForImageSearch = *args.BootstrapConstraints.Arch
-	} else if args.ModelConstraints.Arch != nil {
-		bootstrapArchForImageSearch = *args.ModelConstraints.Arch
+	if args.BootstrapConstraints.HasArch() {
+		bootstrapArchForImageSearch = args.BootstrapConstraints.Arches()[0]
+	} else if args.ModelConstraints.HasArch() {
+		bootstrapArchForImageSearch = args.ModelConstraints.Arches()[0]
 	} else {
 		bootstrapArchForImageSearch = arch.HostArch()
 		// We no longer support i386.
@@ -277,8 +282,10 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	// on an AMD64 client, we're going to look for only AMD64 tools,
 	// limiting what the provider can bootstrap anyway.
 	var bootstrapArch string
-	if bootstrapConstraints.Arch != nil {
-		bootstrapArch = *bootstrapConstraints.Arch
+	if bootstrapConstraints.HasArch() {
+		// If several arches are specified, bootstrap
+		// on the preferred one.
+		bootstrapArch = bootstrapConstraints.Arches()[0]
 	} else {
 		// If no arch is specified as a constraint, we'll bootstrap
 		// on the same arch as the client used to bootstrap.
@@ -454,11 +461,20 @@ func finalizeInstanceBootstrapConfig(
 	if err != nil {
 		return errors.Annotate(err, "cannot generate controller certificate")
 	}
+	// If the CA certificate was issued by an intermediate CA, it is
+	// followed by the certificates up to the root. These are served
+	// with the controller certificate so that clients need only trust
+	// the root.
+	certChain, err := controllerCertChain(caCert)
+	if err != nil {
+		return errors.Annotate(err, "cannot parse CA certificate")
+	}
 	icfg.Bootstrap.StateServingInfo = params.StateServingInfo{
 		StatePort:    controllerCfg.StatePort(),
 		APIPort:      controllerCfg.APIPort(),
 		Cert:         string(cert),
 		PrivateKey:   string(key),
+		CertChain:    certChain,
 		CAPrivateKey: args.CAPrivateKey,
 	}
 	if _, ok := cfg.AgentVersion(); !ok {
@@ -660,6 +676,9 @@ func isCompatibleVersion(v1, v2 version.Number) bool {
 // the contents.
 func setPrivateMetadataSources(metadataDir string) ([]*imagemetadata.ImageMetadata, error) {
 	logger.Infof("Setting default tools and image metadata sources: %s", metadataDir)
+	if err := verifyMetadataManifests(metadataDir); err != nil {
+		return nil, errors.Trace(err)
+	}
 	tools.DefaultBaseURL = metadataDir
 
 	imageMetadataDir := filepath.Join(metadataDir, storage.BaseImagesPath)
@@ -689,6 +708,39 @@ func setPrivateMetadataSources(metadataDir string) ([]*imagemetadata.ImageMetada
 	return existingMetadata, nil
 }
 
+// verifyMetadataManifests verifies the latest signed tools and image
+// metadata manifests for each stream found in the metadata directory,
+// using the public key named by JUJU_STREAMS_PUBLICKEY_FILE. Metadata
+// directories without manifests are not verified.
+func verifyMetadataManifests(metadataDir string) error {
+	stor, err := filestorage.NewFileStorageReader(metadataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, dir := range []string{sync.ManifestDir, sync.ImageManifestDir} {
+		names, err := stor.List(dir + "/")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		publicKey, err := userPublicSigningKey()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if publicKey == "" {
+			return errors.Errorf("cannot verify manifests in %q: JUJU_STREAMS_PUBLICKEY_FILE not set", dir)
+		}
+		count, err := sync.VerifyLatestManifests(stor, dir, publicKey)
+		if err != nil {
+			return errors.Annotate(err, "cannot verify metadata source")
+		}
+		logger.Infof("verified %d manifest(s) in %q", count, dir)
+	}
+	return nil
+}
+
 // guiArchive returns information on the GUI archive that will be uploaded
 // to the controller. Possible errors in retrieving the GUI archive information
 // do not prevent the model to be bootstrapped. If dataSourceBaseURL is
@@ -794,3 +846,18 @@ func hashAndSize(path string) (hash string, size int64, err error) {
 	}
 	return fmt.Sprintf("%x", h.Sum(nil)), size, nil
 }
+
+// controllerCertChain returns the chain of CA certificates to serve
+// with a controller certificate signed by the given CA certificate.
+// If the CA certificate is a single certificate, there is no chain;
+// otherwise the whole bundle, starting with the signing CA, is served.
+func controllerCertChain(caCert string) (string, error) {
+	certs, err := jujucert.ParseCertificates(caCert)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(certs) == 1 {
+		return "", nil
+	}
+	return caCert, nil
+}
@@ -4,6 +4,7 @@
 package bootstrap_test
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"io"
@@ -175,6 +176,23 @@ func (s *bootstrapSuite) TestBootstrapSpecifiedBootstrapSeries(c *gc.C) {
 	c.Check(env.args.AvailableTools.AllSeries(), jc.SameContents, []string{"trusty"})
 }
 
+func (s *bootstrapSuite) TestBootstrapUnsupportedBootstrapSeries(c *gc.C) {
+	env := bootstrapEnvironWithSeries{
+		bootstrapEnviron: newEnviron("foo", useDefaultKeys, nil),
+		series:           []string{"trusty", "xenial"},
+	}
+	s.setDummyStorage(c, env.bootstrapEnviron)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+		BootstrapSeries:  "win2012r2",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `provider "dummy" does not support Windows series "win2012r2"`)
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
 func (s *bootstrapSuite) TestBootstrapSpecifiedPlacement(c *gc.C) {
 	env := newEnviron("foo", useDefaultKeys, nil)
 	s.setDummyStorage(c, env)
@@ -930,6 +948,63 @@ func (s *bootstrapSuite) TestBootstrapMetadata(c *gc.C) {
 	c.Assert(env.instanceConfig.Bootstrap.CustomImageMetadata[0], gc.DeepEquals, metadata[0])
 }
 
+func (s *bootstrapSuite) writeImageManifest(c *gc.C, metadataDir string) {
+	stor, err := filestorage.NewFileStorageWriter(metadataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := sync.BuildManifest(stor, "images/streams/", "released")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Entries, gc.Not(gc.HasLen), 0)
+	data, err := sync.SignManifest(m, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+	err = stor.Put(sync.ImageManifestPath("released", m.Timestamp), bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := filepath.Join(c.MkDir(), "key")
+	err = ioutil.WriteFile(path, []byte(sstesting.SignedMetadataPublicKey), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchEnvironment("JUJU_STREAMS_PUBLICKEY_FILE", path)
+}
+
+func (s *bootstrapSuite) TestBootstrapMetadataManifestVerified(c *gc.C) {
+	environs.UnregisterImageDataSourceFunc("bootstrap metadata")
+
+	metadataDir, _ := createImageMetadata(c)
+	s.writeImageManifest(c, metadataDir)
+
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+		MetadataDir:      metadataDir,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.bootstrapCount, gc.Equals, 1)
+	c.Assert(env.instanceConfig.Bootstrap.CustomImageMetadata, gc.HasLen, 1)
+}
+
+func (s *bootstrapSuite) TestBootstrapMetadataManifestMismatch(c *gc.C) {
+	environs.UnregisterImageDataSourceFunc("bootstrap metadata")
+
+	metadataDir, _ := createImageMetadata(c)
+	s.writeImageManifest(c, metadataDir)
+	indexFile := filepath.Join(metadataDir, "images", "streams", "v1", "index.json")
+	err := ioutil.WriteFile(indexFile, []byte("{}"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	err = bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdminSecret:      "admin-secret",
+		CAPrivateKey:     coretesting.CAKey,
+		MetadataDir:      metadataDir,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot verify metadata source: verifying "released" manifest: "images/streams/v1/index.json": SHA-256 hash mismatch .*`)
+	c.Assert(env.bootstrapCount, gc.Equals, 0)
+}
+
 func (s *bootstrapSuite) TestBootstrapCloudCredential(c *gc.C) {
 	env := newEnviron("foo", useDefaultKeys, nil)
 	s.setDummyStorage(c, env)
@@ -1274,6 +1349,15 @@ func (e bootstrapEnvironWithRegion) Region() (simplestreams.CloudSpec, error) {
 	return e.region, nil
 }
 
+type bootstrapEnvironWithSeries struct {
+	*bootstrapEnviron
+	series []string
+}
+
+func (e bootstrapEnvironWithSeries) SupportedSeries() ([]string, error) {
+	return e.series, nil
+}
+
 type bootstrapEnvironNoExplicitArchitectures struct {
 	*bootstrapEnvironWithRegion
 }
@@ -31,6 +31,14 @@ const (
 	// CAPrivateKeyKey is the key for the controller's CA certificate private key.
 	CAPrivateKeyKey = "ca-private-key"
 
+	// CACertPathKey is the attribute key for the path of a file
+	// holding the controller's CA certificate.
+	CACertPathKey = CACertKey + "-path"
+
+	// CAPrivateKeyPathKey is the attribute key for the path of a file
+	// holding the controller's CA certificate private key.
+	CAPrivateKeyPathKey = CAPrivateKeyKey + "-path"
+
 	// BootstrapTimeoutKey is the attribute key for the amount of time to wait
 	// for bootstrap to complete.
 	BootstrapTimeoutKey = "bootstrap-timeout"
@@ -68,6 +76,8 @@ var BootstrapConfigAttributes = []string{
 	AdminSecretKey,
 	CACertKey,
 	CAPrivateKeyKey,
+	CACertPathKey,
+	CAPrivateKeyPathKey,
 	BootstrapTimeoutKey,
 	BootstrapRetryDelayKey,
 	BootstrapAddressesDelayKey,
@@ -92,6 +102,41 @@ type Config struct {
 	BootstrapTimeout        time.Duration
 	BootstrapRetryDelay     time.Duration
 	BootstrapAddressesDelay time.Duration
+
+	// CACertPath and CAPrivateKeyPath, if set, hold the paths of
+	// files from which the CA certificate and private key are read
+	// when CACert and CAPrivateKey are empty. The files are read
+	// each time they are needed, so that replacing them takes
+	// effect without recreating the config. Relative paths are
+	// relative to $JUJU_DATA.
+	CACertPath       string
+	CAPrivateKeyPath string
+}
+
+// ReadCACert returns the CA certificate, reading it from CACertPath
+// if CACert is not set.
+func (c Config) ReadCACert() (string, error) {
+	if c.CACert != "" || c.CACertPath == "" {
+		return c.CACert, nil
+	}
+	caCert, err := readFileAttr(CACertKey, c.CACertPath)
+	if err != nil {
+		return "", errors.Annotatef(err, "reading %q from file", CACertKey)
+	}
+	return caCert, nil
+}
+
+// ReadCAPrivateKey returns the CA private key, reading it from
+// CAPrivateKeyPath if CAPrivateKey is not set.
+func (c Config) ReadCAPrivateKey() (string, error) {
+	if c.CAPrivateKey != "" || c.CAPrivateKeyPath == "" {
+		return c.CAPrivateKey, nil
+	}
+	caPrivateKey, err := readFileAttr(CAPrivateKeyKey, c.CAPrivateKeyPath)
+	if err != nil {
+		return "", errors.Annotatef(err, "reading %q from file", CAPrivateKeyKey)
+	}
+	return caPrivateKey, nil
 }
 
 // Validate validates the controller configuration.
@@ -99,7 +144,15 @@ func (c Config) Validate() error {
 	if c.AdminSecret == "" {
 		return errors.NotValidf("empty " + AdminSecretKey)
 	}
-	if _, err := tls.X509KeyPair([]byte(c.CACert), []byte(c.CAPrivateKey)); err != nil {
+	caCert, err := c.ReadCACert()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	caPrivateKey, err := c.ReadCAPrivateKey()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := tls.X509KeyPair([]byte(caCert), []byte(caPrivateKey)); err != nil {
 		return errors.Annotatef(err, "validating %s and %s", CACertKey, CAPrivateKeyKey)
 	}
 	if c.BootstrapTimeout <= 0 {
@@ -118,11 +171,11 @@ func (c Config) Validate() error {
 // Default values will be used where defaults are available.
 //
 // If ca-cert or ca-private-key are not set, then we will check
-// if ca-cert-path or ca-private-key-path are set, and read the
-// contents. If none of those are set, we will look for files
-// in well-defined locations: $JUJU_DATA/ca-cert.pem, and
-// $JUJU_DATA/ca-private-key.pem. If none of these are set, an
-// error is returned.
+// if ca-cert-path or ca-private-key-path are set, and record the
+// paths so that the contents are read when used. If none of those
+// are set, we will read files in well-defined locations:
+// $JUJU_DATA/ca-cert.pem, and $JUJU_DATA/ca-private-key.pem. If none
+// of these are set, a new CA certificate and key are generated.
 func NewConfig(attrs map[string]interface{}) (Config, error) {
 	coerced, err := configChecker.Coerce(attrs, nil)
 	if err != nil {
@@ -148,27 +201,28 @@ func NewConfig(attrs map[string]interface{}) (Config, error) {
 
 	if caCert, ok := attrs[CACertKey].(string); ok {
 		config.CACert = caCert
+	} else if path, ok := attrs[CACertPathKey].(string); ok {
+		config.CACertPath = path
 	} else {
-		var userSpecified bool
-		var err error
-		config.CACert, userSpecified, err = readFileAttr(attrs, CACertKey, CACertKey+".pem")
-		if err != nil && (userSpecified || !os.IsNotExist(errors.Cause(err))) {
+		config.CACert, err = readFileAttr(CACertKey, CACertKey+".pem")
+		if err != nil && !os.IsNotExist(errors.Cause(err)) {
 			return Config{}, errors.Annotatef(err, "reading %q from file", CACertKey)
 		}
 	}
 
 	if caPrivateKey, ok := attrs[CAPrivateKeyKey].(string); ok {
 		config.CAPrivateKey = caPrivateKey
+	} else if path, ok := attrs[CAPrivateKeyPathKey].(string); ok {
+		config.CAPrivateKeyPath = path
 	} else {
-		var userSpecified bool
-		var err error
-		config.CAPrivateKey, userSpecified, err = readFileAttr(attrs, CAPrivateKeyKey, CAPrivateKeyKey+".pem")
-		if err != nil && (userSpecified || !os.IsNotExist(errors.Cause(err))) {
+		config.CAPrivateKey, err = readFileAttr(CAPrivateKeyKey, CAPrivateKeyKey+".pem")
+		if err != nil && !os.IsNotExist(errors.Cause(err)) {
 			return Config{}, errors.Annotatef(err, "reading %q from file", CAPrivateKeyKey)
 		}
 	}
 
-	if config.CACert == "" && config.CAPrivateKey == "" {
+	noCAFiles := config.CACertPath == "" && config.CAPrivateKeyPath == ""
+	if config.CACert == "" && config.CAPrivateKey == "" && noCAFiles {
 		// Generate a new CA certificate and private key.
 		// TODO(perrito666) 2016-05-02 lp:1558657
 		expiry := time.Now().UTC().AddDate(10, 0, 0)
@@ -187,48 +241,41 @@ func NewConfig(attrs map[string]interface{}) (Config, error) {
 	return config, config.Validate()
 }
 
-// readFileAttr reads the contents of an attribute from a file, if the
-// corresponding "-path" attribute is set, or otherwise from a default
-// path.
-func readFileAttr(attrs map[string]interface{}, key, defaultPath string) (content string, userSpecified bool, _ error) {
-	path, ok := attrs[key+"-path"].(string)
-	if ok {
-		userSpecified = true
-	} else {
-		path = defaultPath
-	}
+// readFileAttr reads the contents of an attribute from the file at
+// the given path, which is relative to $JUJU_DATA if not absolute.
+func readFileAttr(key, path string) (string, error) {
 	absPath, err := utils.NormalizePath(path)
 	if err != nil {
-		return "", userSpecified, errors.Trace(err)
+		return "", errors.Trace(err)
 	}
 	if !filepath.IsAbs(absPath) {
 		absPath = osenv.JujuXDGDataHomePath(absPath)
 	}
 	data, err := ioutil.ReadFile(absPath)
 	if err != nil {
-		return "", userSpecified, errors.Annotatef(err, "%q not set, and could not read from %q", key, path)
+		return "", errors.Annotatef(err, "%q not set, and could not read from %q", key, path)
 	}
 	if len(data) == 0 {
-		return "", userSpecified, errors.Errorf("file %q is empty", path)
+		return "", errors.Errorf("file %q is empty", path)
 	}
-	return string(data), userSpecified, nil
+	return string(data), nil
 }
 
 var configChecker = schema.FieldMap(schema.Fields{
 	AdminSecretKey:             schema.String(),
 	CACertKey:                  schema.String(),
-	CACertKey + "-path":        schema.String(),
+	CACertPathKey:              schema.String(),
 	CAPrivateKeyKey:            schema.String(),
-	CAPrivateKeyKey + "-path":  schema.String(),
+	CAPrivateKeyPathKey:        schema.String(),
 	BootstrapTimeoutKey:        schema.ForceInt(),
 	BootstrapRetryDelayKey:     schema.ForceInt(),
 	BootstrapAddressesDelayKey: schema.ForceInt(),
 }, schema.Defaults{
 	AdminSecretKey:             schema.Omit,
 	CACertKey:                  schema.Omit,
-	CACertKey + "-path":        schema.Omit,
+	CACertPathKey:              schema.Omit,
 	CAPrivateKeyKey:            schema.Omit,
-	CAPrivateKeyKey + "-path":  schema.Omit,
+	CAPrivateKeyPathKey:        schema.Omit,
 	BootstrapTimeoutKey:        DefaultBootstrapSSHTimeout,
 	BootstrapRetryDelayKey:     DefaultBootstrapSSHRetryDelay,
 	BootstrapAddressesDelayKey: DefaultBootstrapSSHAddressesDelay,
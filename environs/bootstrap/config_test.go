@@ -89,8 +89,42 @@ func (s *ConfigSuite) TestConfigReadsCACertKeyFilesFromPaths(c *gc.C) {
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Assert(cfg.CACert, gc.Equals, testing.OtherCACert)
-	c.Assert(cfg.CAPrivateKey, gc.Equals, testing.OtherCAKey)
+	// The files are read when used, rather than inlined.
+	c.Assert(cfg.CACert, gc.Equals, "")
+	c.Assert(cfg.CAPrivateKey, gc.Equals, "")
+	c.Assert(cfg.CACertPath, gc.Equals, "ca-cert-2.pem")
+	c.Assert(cfg.CAPrivateKeyPath, gc.Equals, "ca-private-key-2.pem")
+	caCert, err := cfg.ReadCACert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caCert, gc.Equals, testing.OtherCACert)
+	caKey, err := cfg.ReadCAPrivateKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caKey, gc.Equals, testing.OtherCAKey)
+}
+
+func (s *ConfigSuite) TestConfigRereadsCACertKeyFiles(c *gc.C) {
+	s.addFiles(c, []gitjujutesting.TestFile{
+		{"ca-cert-2.pem", testing.CACert},
+		{"ca-private-key-2.pem", testing.CAKey},
+	}...)
+	cfg, err := bootstrap.NewConfig(map[string]interface{}{
+		"ca-cert-path":        "ca-cert-2.pem",
+		"ca-private-key-path": "ca-private-key-2.pem",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Rotating the files on disk is seen by the existing config.
+	s.addFiles(c, []gitjujutesting.TestFile{
+		{"ca-cert-2.pem", testing.OtherCACert},
+		{"ca-private-key-2.pem", testing.OtherCAKey},
+	}...)
+	caCert, err := cfg.ReadCACert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caCert, gc.Equals, testing.OtherCACert)
+	caKey, err := cfg.ReadCAPrivateKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caKey, gc.Equals, testing.OtherCAKey)
+	c.Assert(cfg.Validate(), jc.ErrorIsNil)
 }
 
 func (s *ConfigSuite) TestConfigNonExistentPath(c *gc.C) {
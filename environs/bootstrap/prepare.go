@@ -147,6 +147,9 @@ func prepare(
 	if err != nil {
 		return nil, details, errors.Trace(err)
 	}
+	for _, warning := range cfg.DeprecationWarnings() {
+		logger.Warningf("%s", warning)
+	}
 
 	cfg, err = p.PrepareConfig(environs.PrepareConfigParams{args.Cloud, cfg})
 	if err != nil {
@@ -115,3 +115,26 @@ func (*PrepareSuite) TestPrepare(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
 	c.Assert(err, gc.ErrorMatches, `controller "erewhemos" already exists`)
 }
+
+func (*PrepareSuite) TestPrepareDeprecationWarnings(c *gc.C) {
+	attrs := dummy.SampleConfig().Merge(testing.Attrs{
+		"controller":   false,
+		"name":         "erewhemos",
+		"test-mode":    true,
+		"tools-stream": "proposed",
+	}).Delete(
+		"admin-secret",
+	)
+	_, err := bootstrap.Prepare(envtesting.BootstrapContext(c), jujuclienttesting.NewMemStore(), bootstrap.PrepareParams{
+		ControllerConfig: controller.Config{
+			controller.ControllerUUIDKey: testing.ControllerTag.Id(),
+			controller.CACertKey:         testing.CACert,
+		},
+		ControllerName: "erewhemos",
+		ModelConfig:    attrs,
+		Cloud:          dummy.SampleCloudSpec(),
+		AdminSecret:    "admin-secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, `config attribute "tools-stream" is deprecated, use "agent-stream" instead`)
+}
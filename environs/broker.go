@@ -48,6 +48,11 @@ type StartInstanceParams struct {
 	// high availability.
 	DistributionGroup func() ([]instance.Id, error)
 
+	// Spot, if non-nil, requests that the instance be started using
+	// the provider's spot, or preemptible, capacity. It is only set
+	// for brokers that implement SpotInstancer.
+	Spot *SpotInstanceParams
+
 	// Volumes is a set of parameters for volumes that should be created.
 	//
 	// StartInstance need not check the value of the Attachment field,
@@ -83,6 +88,14 @@ type StartInstanceParams struct {
 	StatusCallback StatusCallbackFunc
 }
 
+// SpotInstanceParams holds the parameters for starting an instance
+// using spot, or preemptible, capacity.
+type SpotInstanceParams struct {
+	// MaxPrice is the maximum hourly price to pay for the instance,
+	// in the provider's currency.
+	MaxPrice float64
+}
+
 // StartInstanceResult holds the result of an
 // InstanceBroker.StartInstance method call.
 type StartInstanceResult struct {
@@ -5,8 +5,12 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -18,7 +22,9 @@ import (
 	"gopkg.in/juju/charmrepo.v2-unstable"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/yaml.v2"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
@@ -103,6 +109,60 @@ const (
 	// The default block storage source.
 	StorageDefaultBlockSourceKey = "storage-default-block-source"
 
+	// ConstraintProfilesKey stores the named constraint profiles that
+	// may be referred to by the "profile" constraint.
+	ConstraintProfilesKey = "constraint-profiles"
+
+	// S3StorageEndpointKey, S3StorageRegionKey, S3StorageBucketKey,
+	// S3StorageAccessKeyKey and S3StorageSecretKeyKey store the
+	// settings for keeping model storage in S3-compatible object
+	// storage.
+	S3StorageEndpointKey  = "s3-storage-endpoint"
+	S3StorageRegionKey    = "s3-storage-region"
+	S3StorageBucketKey    = "s3-storage-bucket"
+	S3StorageAccessKeyKey = "s3-storage-access-key"
+	S3StorageSecretKeyKey = "s3-storage-secret-key"
+
+	// The SwiftStorage keys store the settings for keeping model
+	// storage in OpenStack Swift, authenticating with Keystone v3.
+	SwiftStorageAuthURLKey                     = "swift-storage-auth-url"
+	SwiftStorageRegionKey                      = "swift-storage-region"
+	SwiftStorageContainerKey                   = "swift-storage-container"
+	SwiftStorageUsernameKey                    = "swift-storage-username"
+	SwiftStoragePasswordKey                    = "swift-storage-password"
+	SwiftStorageProjectNameKey                 = "swift-storage-project-name"
+	SwiftStorageUserDomainNameKey              = "swift-storage-user-domain-name"
+	SwiftStorageProjectDomainNameKey           = "swift-storage-project-domain-name"
+	SwiftStorageApplicationCredentialIDKey     = "swift-storage-application-credential-id"
+	SwiftStorageApplicationCredentialSecretKey = "swift-storage-application-credential-secret"
+
+	// The AzureStorage keys store the settings for keeping model
+	// storage in Azure Blob storage, authenticating with either an
+	// account key or a shared access signature.
+	AzureStorageAccountKey    = "azure-storage-account"
+	AzureStorageContainerKey  = "azure-storage-container"
+	AzureStorageEndpointKey   = "azure-storage-endpoint"
+	AzureStorageAccountKeyKey = "azure-storage-account-key"
+	AzureStorageSASTokenKey   = "azure-storage-sas-token"
+
+	// The GCSStorage keys store the settings for keeping model
+	// storage in Google Cloud Storage, authenticating with a
+	// service account.
+	GCSStorageBucketKey      = "gcs-storage-bucket"
+	GCSStorageProjectKey     = "gcs-storage-project"
+	GCSStorageCredentialsKey = "gcs-storage-credentials"
+	GCSStorageEndpointKey    = "gcs-storage-endpoint"
+
+	// The StorageRetry keys store the retry budget for each class of
+	// model storage operation: the minimum number of attempts, and
+	// the time in seconds after which no more attempts are made.
+	StorageGetRetryAttemptsKey  = "storage-get-retry-attempts"
+	StorageGetRetryTimeoutKey   = "storage-get-retry-timeout"
+	StorageListRetryAttemptsKey = "storage-list-retry-attempts"
+	StorageListRetryTimeoutKey  = "storage-list-retry-timeout"
+	StoragePutRetryAttemptsKey  = "storage-put-retry-attempts"
+	StoragePutRetryTimeoutKey   = "storage-put-retry-timeout"
+
 	// ResourceTagsKey is an optional list or space-separated string
 	// of k=v pairs, defining the tags for ResourceTags.
 	ResourceTagsKey = "resource-tags"
@@ -137,6 +197,31 @@ const (
 	// is stored against the model.
 	ExtraInfoKey = "extra-info"
 
+	// AgentLoggingConfigKey is the key for the logging configuration
+	// used by agents, overriding logging-config when set.
+	AgentLoggingConfigKey = "agent-logging-config"
+
+	// AgentDataDirKey is the key for the data directory used by
+	// agents on newly provisioned, non-controller machines.
+	AgentDataDirKey = "agent-datadir"
+
+	// AgentMaxLogSizeKey is the key for the maximum size, in
+	// megabytes, of an agent's log file before it is rotated.
+	AgentMaxLogSizeKey = "agent-max-log-size"
+
+	// AgentCPUQuotaKey is the key for the CPU time, as a percentage
+	// of a single CPU, available to agents on newly provisioned,
+	// non-controller machines.
+	AgentCPUQuotaKey = "agent-cpu-quota"
+
+	// AgentMemoryLimitKey is the key for the memory, in megabytes,
+	// available to agents on newly provisioned, non-controller machines.
+	AgentMemoryLimitKey = "agent-memory-limit"
+
+	// CloudInitUserDataKey is the key for extra cloud-init user-data,
+	// in YAML, to be merged into that generated for new machines.
+	CloudInitUserDataKey = "cloudinit-userdata"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -230,6 +315,17 @@ type Config struct {
 	// unknown holds the other attributes that are passed in (aka UnknownAttrs).
 	// the union of these two are AllAttrs
 	defined, unknown map[string]interface{}
+
+	// deprecationWarnings holds a warning for each deprecated
+	// attribute migrated when the config was created.
+	deprecationWarnings []DeprecationWarning
+}
+
+// DeprecationWarnings returns a warning for each deprecated attribute
+// that was migrated or discarded when the config was created. New does
+// not report them; it is up to the caller to tell the user.
+func (c *Config) DeprecationWarnings() []DeprecationWarning {
+	return c.deprecationWarnings
 }
 
 // Defaulting is a value that specifies whether a configuration
@@ -261,13 +357,15 @@ func New(withDefaults Defaulting, attrs map[string]interface{}) (*Config, error)
 	if withDefaults {
 		checker = withDefaultsChecker
 	}
+	attrs, warnings := MigrateDeprecatedAttributes(attrs)
 	defined, err := checker.Coerce(attrs, nil)
 	if err != nil {
 		return nil, err
 	}
 	c := &Config{
-		defined: defined.(map[string]interface{}),
-		unknown: make(map[string]interface{}),
+		defined:             defined.(map[string]interface{}),
+		unknown:             make(map[string]interface{}),
+		deprecationWarnings: warnings,
 	}
 	if err := c.ensureUnitLogging(); err != nil {
 		return nil, err
@@ -378,11 +476,7 @@ func (c *Config) ensureUnitLogging() error {
 // Ths ensures that older versions of Juju which require that deprecated
 // attribute values still be used will work as expected.
 func ProcessDeprecatedAttributes(attrs map[string]interface{}) map[string]interface{} {
-	processedAttrs := make(map[string]interface{}, len(attrs))
-	for k, v := range attrs {
-		processedAttrs[k] = v
-	}
-	// No deprecated attributes at the moment.
+	processedAttrs, _ := MigrateDeprecatedAttributes(attrs)
 	return processedAttrs
 }
 
@@ -462,6 +556,33 @@ func Validate(cfg, old *Config) error {
 			return err
 		}
 	}
+	if v, ok := cfg.defined[AgentLoggingConfigKey].(string); ok {
+		if _, err := loggo.ParseConfigString(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", AgentLoggingConfigKey)
+		}
+	}
+
+	if v, ok := cfg.defined[AgentDataDirKey].(string); ok && v != "" {
+		if !path.IsAbs(v) && !windowsAbsPath.MatchString(v) {
+			return errors.Errorf("%s: expected an absolute path, got %q", AgentDataDirKey, v)
+		}
+	}
+
+	if v, ok := cfg.defined[AgentMaxLogSizeKey].(int); ok && v < 0 {
+		return errors.Errorf("%s: expected a non-negative number of megabytes, got %d", AgentMaxLogSizeKey, v)
+	}
+
+	if v, ok := cfg.defined[AgentCPUQuotaKey].(int); ok && v < 0 {
+		return errors.Errorf("%s: expected a non-negative percentage, got %d", AgentCPUQuotaKey, v)
+	}
+
+	if v, ok := cfg.defined[AgentMemoryLimitKey].(int); ok && v < 0 {
+		return errors.Errorf("%s: expected a non-negative number of megabytes, got %d", AgentMemoryLimitKey, v)
+	}
+
+	if _, err := cfg.cloudInitUserData(); err != nil {
+		return errors.Trace(err)
+	}
 
 	if lfCfg, ok := cfg.LogFwdSyslog(); ok {
 		if err := lfCfg.Validate(); err != nil {
@@ -473,6 +594,36 @@ func Validate(cfg, old *Config) error {
 		return errors.Errorf("uuid: expected UUID, got string(%q)", uuid)
 	}
 
+	if _, err := cfg.constraintProfiles(); err != nil {
+		return errors.Annotate(err, "validating constraint profiles")
+	}
+
+	if err := cfg.validateS3Storage(); err != nil {
+		return errors.Annotate(err, "validating S3 storage settings")
+	}
+
+	if err := cfg.validateSwiftStorage(); err != nil {
+		return errors.Annotate(err, "validating Swift storage settings")
+	}
+
+	if err := cfg.validateAzureStorage(); err != nil {
+		return errors.Annotate(err, "validating Azure storage settings")
+	}
+
+	if err := cfg.validateGCSStorage(); err != nil {
+		return errors.Annotate(err, "validating GCS storage settings")
+	}
+
+	if err := cfg.validateProxies(); err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, key := range storageRetryKeys {
+		if v, ok := cfg.defined[key].(int); ok && v < 0 {
+			return errors.Errorf("%s: expected a non-negative number, got %d", key, v)
+		}
+	}
+
 	// Ensure the resource tags have the expected k=v format.
 	if _, err := cfg.resourceTags(); err != nil {
 		return errors.Annotate(err, "validating resource tags")
@@ -480,14 +631,8 @@ func Validate(cfg, old *Config) error {
 
 	// Check the immutable config values.  These can't change
 	if old != nil {
-		for _, attr := range immutableAttributes {
-			oldv, ok := old.defined[attr]
-			if !ok {
-				continue
-			}
-			if newv := cfg.defined[attr]; newv != oldv {
-				return fmt.Errorf("cannot change %s from %#v to %#v", attr, oldv, newv)
-			}
+		if changes := immutableChanges(cfg, old); len(changes) > 0 {
+			return &ImmutableChangeError{Changes: changes}
 		}
 		if _, oldFound := old.AgentVersion(); oldFound {
 			if _, newFound := cfg.AgentVersion(); !newFound {
@@ -664,6 +809,43 @@ func (c *Config) AptFTPProxy() string {
 	return addSchemeIfMissing("ftp", c.getWithFallback(AptFTPProxyKey, FTPProxyKey))
 }
 
+// proxySchemes holds the default scheme for each proxy attribute, used
+// when the configured value does not specify one.
+var proxySchemes = map[string]string{
+	HTTPProxyKey:     "http",
+	HTTPSProxyKey:    "https",
+	FTPProxyKey:      "ftp",
+	AptHTTPProxyKey:  "http",
+	AptHTTPSProxyKey: "https",
+	AptFTPProxyKey:   "ftp",
+}
+
+// validateProxies checks that every proxy attribute that is set holds
+// a URL with a host, and that no-proxy holds a comma-separated list of
+// host names, addresses or address ranges. Values are checked here so
+// that a bad proxy is reported when the config is set, rather than
+// when cloud-init, the agents or the simplestreams fetchers try to
+// use it.
+func (c *Config) validateProxies() error {
+	for key, scheme := range proxySchemes {
+		value := c.asString(key)
+		if value == "" {
+			continue
+		}
+		u, err := url.Parse(addSchemeIfMissing(scheme, value))
+		if err != nil || u.Host == "" {
+			return errors.Errorf("%s: expected a proxy URL, got %q", key, value)
+		}
+	}
+	for _, host := range strings.Split(c.NoProxy(), ",") {
+		host = strings.TrimSpace(host)
+		if strings.Contains(host, "://") || strings.ContainsAny(host, " \t") {
+			return errors.Errorf("%s: expected a host name or address, got %q", NoProxyKey, host)
+		}
+	}
+	return nil
+}
+
 // AptMirror sets the apt mirror for the environment.
 func (c *Config) AptMirror() string {
 	return c.asString("apt-mirror")
@@ -781,6 +963,100 @@ func (c *Config) LoggingConfig() string {
 	return c.asString("logging-config")
 }
 
+// AgentLoggingConfig returns the configuration string for agent
+// loggers. It falls back to LoggingConfig if agent-logging-config
+// has not been set.
+func (c *Config) AgentLoggingConfig() string {
+	return c.getWithFallback(AgentLoggingConfigKey, "logging-config")
+}
+
+// AgentDataDir returns the data directory to use for agents on newly
+// provisioned machines, and whether it has been set.
+func (c *Config) AgentDataDir() (string, bool) {
+	dataDir := c.asString(AgentDataDirKey)
+	return dataDir, dataDir != ""
+}
+
+// AgentMaxLogSize returns the maximum size in megabytes of an agent's
+// log file before it is rotated, and whether it has been set.
+func (c *Config) AgentMaxLogSize() (int, bool) {
+	size, _ := c.defined[AgentMaxLogSizeKey].(int)
+	return size, size > 0
+}
+
+// AgentCPUQuota returns the CPU time, as a percentage of a single CPU,
+// available to agents on newly provisioned machines, and whether it
+// has been set.
+func (c *Config) AgentCPUQuota() (int, bool) {
+	quota, _ := c.defined[AgentCPUQuotaKey].(int)
+	return quota, quota > 0
+}
+
+// AgentMemoryLimit returns the memory in megabytes available to agents
+// on newly provisioned machines, and whether it has been set.
+func (c *Config) AgentMemoryLimit() (uint64, bool) {
+	limit, _ := c.defined[AgentMemoryLimitKey].(int)
+	return uint64(limit), limit > 0
+}
+
+// reservedCloudInitUserDataKeys holds the cloud-init user-data sections
+// that juju writes itself, and which therefore may not be set in
+// cloudinit-userdata.
+var reservedCloudInitUserDataKeys = []string{
+	"apt_mirror",
+	"apt_proxy",
+	"apt_sources",
+	"bootcmd",
+	"disable_root",
+	"mounts",
+	"output",
+	"package_update",
+	"package_upgrade",
+	"runcmd",
+	"ssh_authorized_keys",
+	"users",
+}
+
+// CloudInitUserData returns the extra cloud-init user-data to be
+// merged into that generated for newly provisioned machines, or nil
+// if none is set. Any packages listed are installed in addition to
+// those juju installs.
+func (c *Config) CloudInitUserData() map[string]interface{} {
+	// The value was checked by Validate.
+	userData, _ := c.cloudInitUserData()
+	return userData
+}
+
+func (c *Config) cloudInitUserData() (map[string]interface{}, error) {
+	value := c.asString(CloudInitUserDataKey)
+	if value == "" {
+		return nil, nil
+	}
+	var userData map[string]interface{}
+	if err := yaml.Unmarshal([]byte(value), &userData); err != nil {
+		return nil, errors.Errorf("%s: expected a YAML map: %v", CloudInitUserDataKey, err)
+	}
+	for _, key := range reservedCloudInitUserDataKeys {
+		if _, ok := userData[key]; ok {
+			return nil, errors.Errorf("%s: %q is managed by juju and may not be set", CloudInitUserDataKey, key)
+		}
+	}
+	// Packages are added to those juju installs, so they must be
+	// listed by name.
+	if packages, ok := userData["packages"]; ok {
+		list, ok := packages.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s: packages: expected list, got %T", CloudInitUserDataKey, packages)
+		}
+		for _, pkg := range list {
+			if _, ok := pkg.(string); !ok {
+				return nil, errors.Errorf("%s: packages: expected package name, got %v", CloudInitUserDataKey, pkg)
+			}
+		}
+	}
+	return userData, nil
+}
+
 // AutomaticallyRetryHooks returns whether we should automatically retry hooks.
 // By default this should be true.
 func (c *Config) AutomaticallyRetryHooks() bool {
@@ -893,6 +1169,315 @@ func (c *Config) resourceTags() (map[string]string, error) {
 	return v, nil
 }
 
+// ConstraintProfiles returns the named constraint profiles defined
+// for the model, keyed by name.
+func (c *Config) ConstraintProfiles() map[string]constraints.Value {
+	profiles, err := c.constraintProfiles()
+	if err != nil {
+		panic(err) // should be prevented by Validate
+	}
+	return profiles
+}
+
+func (c *Config) constraintProfiles() (map[string]constraints.Value, error) {
+	v, ok := c.defined[ConstraintProfilesKey].(map[string]string)
+	if !ok {
+		return nil, nil
+	}
+	return constraints.ParseProfiles(v)
+}
+
+// S3StorageSettings holds the settings for keeping model storage in
+// S3-compatible object storage, such as AWS S3, Ceph RGW or MinIO.
+type S3StorageSettings struct {
+	// Endpoint is the URL of the S3 API endpoint. If empty, the
+	// AWS endpoint for Region is used.
+	Endpoint string
+
+	// Region is the name of the region in which Bucket is held.
+	Region string
+
+	// Bucket is the name of the bucket in which files are stored.
+	Bucket string
+
+	// AccessKey and SecretKey are the credentials used to access
+	// the bucket.
+	AccessKey string
+	SecretKey string
+}
+
+// S3Storage returns the settings for keeping model storage in
+// S3-compatible object storage, and whether they have been set.
+func (c *Config) S3Storage() (S3StorageSettings, bool) {
+	settings := S3StorageSettings{
+		Endpoint:  c.asString(S3StorageEndpointKey),
+		Region:    c.asString(S3StorageRegionKey),
+		Bucket:    c.asString(S3StorageBucketKey),
+		AccessKey: c.asString(S3StorageAccessKeyKey),
+		SecretKey: c.asString(S3StorageSecretKeyKey),
+	}
+	return settings, settings.Bucket != ""
+}
+
+func (c *Config) validateS3Storage() error {
+	settings, ok := c.S3Storage()
+	if !ok {
+		if settings != (S3StorageSettings{}) {
+			return errors.Errorf("%s must be set", S3StorageBucketKey)
+		}
+		return nil
+	}
+	if settings.Endpoint == "" && settings.Region == "" {
+		return errors.Errorf("one of %s or %s must be set", S3StorageEndpointKey, S3StorageRegionKey)
+	}
+	if (settings.AccessKey == "") != (settings.SecretKey == "") {
+		return errors.Errorf("%s and %s must be set together", S3StorageAccessKeyKey, S3StorageSecretKeyKey)
+	}
+	return nil
+}
+
+// SwiftStorageSettings holds the settings for keeping model storage
+// in OpenStack Swift. Authentication is with Keystone v3, using
+// either a username and password scoped to a project, or an
+// application credential.
+type SwiftStorageSettings struct {
+	// AuthURL is the URL of the Keystone v3 identity endpoint.
+	AuthURL string
+
+	// Region is the region whose object-store endpoint is used.
+	// If empty, the first object-store endpoint is used.
+	Region string
+
+	// Container is the name of the container in which files
+	// are stored.
+	Container string
+
+	// Username, Password and ProjectName are used for password
+	// authentication. UserDomainName and ProjectDomainName name the
+	// domains of the user and project, and default to "Default".
+	Username          string
+	Password          string
+	ProjectName       string
+	UserDomainName    string
+	ProjectDomainName string
+
+	// ApplicationCredentialID and ApplicationCredentialSecret are
+	// used for application credential authentication, in place of
+	// the password settings.
+	ApplicationCredentialID     string
+	ApplicationCredentialSecret string
+}
+
+// SwiftStorage returns the settings for keeping model storage in
+// OpenStack Swift, and whether they have been set.
+func (c *Config) SwiftStorage() (SwiftStorageSettings, bool) {
+	settings := SwiftStorageSettings{
+		AuthURL:                     c.asString(SwiftStorageAuthURLKey),
+		Region:                      c.asString(SwiftStorageRegionKey),
+		Container:                   c.asString(SwiftStorageContainerKey),
+		Username:                    c.asString(SwiftStorageUsernameKey),
+		Password:                    c.asString(SwiftStoragePasswordKey),
+		ProjectName:                 c.asString(SwiftStorageProjectNameKey),
+		UserDomainName:              c.asString(SwiftStorageUserDomainNameKey),
+		ProjectDomainName:           c.asString(SwiftStorageProjectDomainNameKey),
+		ApplicationCredentialID:     c.asString(SwiftStorageApplicationCredentialIDKey),
+		ApplicationCredentialSecret: c.asString(SwiftStorageApplicationCredentialSecretKey),
+	}
+	return settings, settings.Container != ""
+}
+
+func (c *Config) validateSwiftStorage() error {
+	settings, ok := c.SwiftStorage()
+	if !ok {
+		if settings != (SwiftStorageSettings{}) {
+			return errors.Errorf("%s must be set", SwiftStorageContainerKey)
+		}
+		return nil
+	}
+	if settings.AuthURL == "" {
+		return errors.Errorf("%s must be set", SwiftStorageAuthURLKey)
+	}
+	if settings.ApplicationCredentialID != "" || settings.ApplicationCredentialSecret != "" {
+		if settings.ApplicationCredentialID == "" || settings.ApplicationCredentialSecret == "" {
+			return errors.Errorf(
+				"%s and %s must be set together",
+				SwiftStorageApplicationCredentialIDKey,
+				SwiftStorageApplicationCredentialSecretKey,
+			)
+		}
+		if settings.Username != "" || settings.Password != "" {
+			return errors.Errorf(
+				"%s cannot be used with %s",
+				SwiftStorageApplicationCredentialIDKey,
+				SwiftStorageUsernameKey,
+			)
+		}
+		return nil
+	}
+	if settings.Username == "" || settings.Password == "" || settings.ProjectName == "" {
+		return errors.Errorf(
+			"either %s, %s and %s, or %s and %s must be set",
+			SwiftStorageUsernameKey,
+			SwiftStoragePasswordKey,
+			SwiftStorageProjectNameKey,
+			SwiftStorageApplicationCredentialIDKey,
+			SwiftStorageApplicationCredentialSecretKey,
+		)
+	}
+	return nil
+}
+
+// AzureStorageSettings holds the settings for keeping model storage
+// in Azure Blob storage.
+type AzureStorageSettings struct {
+	// Account is the name of the storage account.
+	Account string
+
+	// Container is the name of the container in which blobs
+	// are stored.
+	Container string
+
+	// Endpoint is the URL of the blob service. If empty, the
+	// public Azure endpoint for Account is used.
+	Endpoint string
+
+	// AccountKey is the storage account key used to sign requests.
+	AccountKey string
+
+	// SASToken is a shared access signature, used in place of
+	// AccountKey.
+	SASToken string
+}
+
+// AzureStorage returns the settings for keeping model storage in
+// Azure Blob storage, and whether they have been set.
+func (c *Config) AzureStorage() (AzureStorageSettings, bool) {
+	settings := AzureStorageSettings{
+		Account:    c.asString(AzureStorageAccountKey),
+		Container:  c.asString(AzureStorageContainerKey),
+		Endpoint:   c.asString(AzureStorageEndpointKey),
+		AccountKey: c.asString(AzureStorageAccountKeyKey),
+		SASToken:   c.asString(AzureStorageSASTokenKey),
+	}
+	return settings, settings.Container != ""
+}
+
+func (c *Config) validateAzureStorage() error {
+	settings, ok := c.AzureStorage()
+	if !ok {
+		if settings != (AzureStorageSettings{}) {
+			return errors.Errorf("%s must be set", AzureStorageContainerKey)
+		}
+		return nil
+	}
+	if settings.Account == "" {
+		return errors.Errorf("%s must be set", AzureStorageAccountKey)
+	}
+	if (settings.AccountKey == "") == (settings.SASToken == "") {
+		return errors.Errorf("exactly one of %s or %s must be set", AzureStorageAccountKeyKey, AzureStorageSASTokenKey)
+	}
+	return nil
+}
+
+// GCSStorageSettings holds the settings for keeping model storage in
+// Google Cloud Storage.
+type GCSStorageSettings struct {
+	// Bucket is the name of the bucket in which objects are stored.
+	Bucket string
+
+	// Project is the ID of the project in which Bucket is created,
+	// if it does not already exist.
+	Project string
+
+	// Credentials holds the contents of a service account's JSON
+	// key file.
+	Credentials string
+
+	// Endpoint is the URL of the Cloud Storage JSON API. If empty,
+	// the public Google endpoint is used.
+	Endpoint string
+}
+
+// GCSStorage returns the settings for keeping model storage in Google
+// Cloud Storage, and whether they have been set.
+func (c *Config) GCSStorage() (GCSStorageSettings, bool) {
+	settings := GCSStorageSettings{
+		Bucket:      c.asString(GCSStorageBucketKey),
+		Project:     c.asString(GCSStorageProjectKey),
+		Credentials: c.asString(GCSStorageCredentialsKey),
+		Endpoint:    c.asString(GCSStorageEndpointKey),
+	}
+	return settings, settings.Bucket != ""
+}
+
+func (c *Config) validateGCSStorage() error {
+	settings, ok := c.GCSStorage()
+	if !ok {
+		if settings != (GCSStorageSettings{}) {
+			return errors.Errorf("%s must be set", GCSStorageBucketKey)
+		}
+		return nil
+	}
+	if settings.Credentials == "" {
+		return errors.Errorf("%s must be set", GCSStorageCredentialsKey)
+	}
+	return nil
+}
+
+// StorageOperation identifies a class of model storage operation.
+type StorageOperation string
+
+const (
+	StorageGet  StorageOperation = "get"
+	StorageList StorageOperation = "list"
+	StoragePut  StorageOperation = "put"
+)
+
+// storageRetryKeys holds the keys of the retry budget settings for
+// each class of storage operation.
+var storageRetryKeys = []string{
+	StorageGetRetryAttemptsKey,
+	StorageGetRetryTimeoutKey,
+	StorageListRetryAttemptsKey,
+	StorageListRetryTimeoutKey,
+	StoragePutRetryAttemptsKey,
+	StoragePutRetryTimeoutKey,
+}
+
+// StorageRetrySettings holds the retry budget for a class of model
+// storage operation.
+type StorageRetrySettings struct {
+	// Attempts is the minimum number of attempts made.
+	Attempts int
+
+	// Timeout is the time after which no more attempts are made,
+	// once the minimum number have been.
+	Timeout time.Duration
+}
+
+// StorageRetry returns the retry budget for the given class of model
+// storage operation, and whether one has been set.
+func (c *Config) StorageRetry(op StorageOperation) (StorageRetrySettings, bool) {
+	var attemptsKey, timeoutKey string
+	switch op {
+	case StorageGet:
+		attemptsKey, timeoutKey = StorageGetRetryAttemptsKey, StorageGetRetryTimeoutKey
+	case StorageList:
+		attemptsKey, timeoutKey = StorageListRetryAttemptsKey, StorageListRetryTimeoutKey
+	case StoragePut:
+		attemptsKey, timeoutKey = StoragePutRetryAttemptsKey, StoragePutRetryTimeoutKey
+	default:
+		return StorageRetrySettings{}, false
+	}
+	attempts, _ := c.defined[attemptsKey].(int)
+	timeout, _ := c.defined[timeoutKey].(int)
+	settings := StorageRetrySettings{
+		Attempts: attempts,
+		Timeout:  time.Duration(timeout) * time.Second,
+	}
+	return settings, attempts > 0 || timeout > 0
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -958,6 +1543,13 @@ var alwaysOptional = schema.Defaults{
 	AuthorizedKeysKey: schema.Omit,
 	ExtraInfoKey:      schema.Omit,
 
+	AgentLoggingConfigKey: schema.Omit,
+	AgentDataDirKey:       schema.Omit,
+	AgentMaxLogSizeKey:    schema.Omit,
+	AgentCPUQuotaKey:      schema.Omit,
+	AgentMemoryLimitKey:   schema.Omit,
+	CloudInitUserDataKey:  schema.Omit,
+
 	LogForwardEnabled:      schema.Omit,
 	LogFwdSyslogHost:       schema.Omit,
 	LogFwdSyslogCACert:     schema.Omit,
@@ -968,6 +1560,43 @@ var alwaysOptional = schema.Defaults{
 	// Environ providers will specify their own defaults.
 	StorageDefaultBlockSourceKey: schema.Omit,
 
+	ConstraintProfilesKey: schema.Omit,
+
+	S3StorageEndpointKey:  schema.Omit,
+	S3StorageRegionKey:    schema.Omit,
+	S3StorageBucketKey:    schema.Omit,
+	S3StorageAccessKeyKey: schema.Omit,
+	S3StorageSecretKeyKey: schema.Omit,
+
+	SwiftStorageAuthURLKey:                     schema.Omit,
+	SwiftStorageRegionKey:                      schema.Omit,
+	SwiftStorageContainerKey:                   schema.Omit,
+	SwiftStorageUsernameKey:                    schema.Omit,
+	SwiftStoragePasswordKey:                    schema.Omit,
+	SwiftStorageProjectNameKey:                 schema.Omit,
+	SwiftStorageUserDomainNameKey:              schema.Omit,
+	SwiftStorageProjectDomainNameKey:           schema.Omit,
+	SwiftStorageApplicationCredentialIDKey:     schema.Omit,
+	SwiftStorageApplicationCredentialSecretKey: schema.Omit,
+
+	AzureStorageAccountKey:    schema.Omit,
+	AzureStorageContainerKey:  schema.Omit,
+	AzureStorageEndpointKey:   schema.Omit,
+	AzureStorageAccountKeyKey: schema.Omit,
+	AzureStorageSASTokenKey:   schema.Omit,
+
+	GCSStorageBucketKey:      schema.Omit,
+	GCSStorageProjectKey:     schema.Omit,
+	GCSStorageCredentialsKey: schema.Omit,
+	GCSStorageEndpointKey:    schema.Omit,
+
+	StorageGetRetryAttemptsKey:  schema.Omit,
+	StorageGetRetryTimeoutKey:   schema.Omit,
+	StorageListRetryAttemptsKey: schema.Omit,
+	StorageListRetryTimeoutKey:  schema.Omit,
+	StoragePutRetryAttemptsKey:  schema.Omit,
+	StoragePutRetryTimeoutKey:   schema.Omit,
+
 	"firewall-mode":              schema.Omit,
 	"logging-config":             schema.Omit,
 	ProvisionerHarvestModeKey:    schema.Omit,
@@ -1024,7 +1653,9 @@ func allDefaults() schema.Defaults {
 
 // immutableAttributes holds those attributes
 // which are not allowed to change in the lifetime
-// of an environment.
+// of an environment. Providers may add to these
+// by marking fields immutable in their registered
+// config schema.
 var immutableAttributes = []string{
 	NameKey,
 	TypeKey,
@@ -1131,10 +1762,52 @@ func Schema(extra environschema.Fields) (environschema.Fields, error) {
 	return fields, nil
 }
 
+// IsSecretAttribute reports whether the named model config attribute
+// holds a secret, such as an object storage credential, which must not
+// be revealed to model users.
+func IsSecretAttribute(name string) bool {
+	field, ok := configSchema[name]
+	return ok && field.Secret
+}
+
+// windowsAbsPath matches absolute Windows paths, such as C:\Juju\lib,
+// which are valid agent data directories for Windows machines.
+var windowsAbsPath = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+
 // configSchema holds information on all the fields defined by
 // the config package.
 // TODO(rog) make this available to external packages.
 var configSchema = environschema.Fields{
+	AgentDataDirKey: {
+		Description: "The data directory used by agents on newly provisioned machines",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	CloudInitUserDataKey: {
+		Description: "Extra cloud-init user-data, in YAML, for newly provisioned machines",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	AgentLoggingConfigKey: {
+		Description: `The configuration string to use when configuring Juju agent logging, overriding logging-config (see http://godoc.org/github.com/juju/loggo#ParseConfigurationString for details)`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	AgentMaxLogSizeKey: {
+		Description: "The maximum size in megabytes of an agent log file before it is rotated (default 300)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	AgentCPUQuotaKey: {
+		Description: "The CPU time, as a percentage of a single CPU, available to agents on newly provisioned machines",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	AgentMemoryLimitKey: {
+		Description: "The memory in megabytes available to agents on newly provisioned machines",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
 	AgentMetadataURLKey: {
 		Description: "URL of private stream",
 		Type:        environschema.Tstring,
@@ -1180,6 +1853,41 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	AzureStorageAccountKey: {
+		Description: "The storage account in which to keep Azure Blob model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	AzureStorageAccountKeyKey: {
+		Description: "The storage account key used to access Azure Blob model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	AzureStorageContainerKey: {
+		Description: `The container in which to keep model storage in Azure Blob storage;
+if unset, model storage is provider-specific`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	AzureStorageEndpointKey: {
+		Description: `The URL of the blob service used for Azure Blob model storage;
+if unset, the public Azure endpoint for azure-storage-account is used`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	AzureStorageSASTokenKey: {
+		Description: "The shared access signature used to access Azure Blob model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	ConstraintProfilesKey: {
+		Description: `Named constraint profiles, each mapping a profile name to
+constraints, that may be referred to with the "profile" constraint`,
+		Type:  environschema.Tattrs,
+		Group: environschema.EnvironGroup,
+	},
 	"default-series": {
 		Description: "The default series of Ubuntu to use for deploying charms",
 		Type:        environschema.Tstring,
@@ -1237,6 +1945,29 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	GCSStorageBucketKey: {
+		Description: `The bucket in which to keep model storage in Google Cloud Storage;
+if unset, model storage is provider-specific`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	GCSStorageCredentialsKey: {
+		Description: "The JSON key of the service account used to access Google Cloud Storage model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	GCSStorageEndpointKey: {
+		Description: `The URL of the Cloud Storage API used for Google Cloud Storage model storage;
+if unset, the public Google endpoint is used`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	GCSStorageProjectKey: {
+		Description: "The project in which the Google Cloud Storage model storage bucket is created",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	HTTPProxyKey: {
 		Description: "The HTTP proxy value to configure on instances, in the HTTP_PROXY environment variable",
 		Type:        environschema.Tstring,
@@ -1317,6 +2048,89 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	S3StorageAccessKeyKey: {
+		Description: "The access key used to access S3 model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	S3StorageBucketKey: {
+		Description: `The bucket in which to keep model storage in S3-compatible
+object storage; if unset, model storage is provider-specific`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	S3StorageEndpointKey: {
+		Description: `The URL of the S3-compatible API endpoint for model storage;
+if unset, the AWS endpoint for s3-storage-region is used`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	S3StorageRegionKey: {
+		Description: "The region in which the S3 model storage bucket is held",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	S3StorageSecretKeyKey: {
+		Description: "The secret key used to access S3 model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	SwiftStorageApplicationCredentialIDKey: {
+		Description: "The ID of the Keystone application credential used to access Swift model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SwiftStorageApplicationCredentialSecretKey: {
+		Description: "The secret of the Keystone application credential used to access Swift model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	SwiftStorageAuthURLKey: {
+		Description: "The URL of the Keystone v3 identity endpoint used to access Swift model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SwiftStorageContainerKey: {
+		Description: `The container in which to keep model storage in OpenStack Swift;
+if unset, model storage is provider-specific`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	SwiftStoragePasswordKey: {
+		Description: "The password used to access Swift model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	SwiftStorageProjectDomainNameKey: {
+		Description: `The domain of the project used to access Swift model storage
+(default "Default")`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	SwiftStorageProjectNameKey: {
+		Description: "The project used to access Swift model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SwiftStorageRegionKey: {
+		Description: "The region whose object-store endpoint is used for Swift model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	SwiftStorageUserDomainNameKey: {
+		Description: `The domain of the user used to access Swift model storage
+(default "Default")`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	SwiftStorageUsernameKey: {
+		Description: "The username used to access Swift model storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"ssl-hostname-verification": {
 		Description: "Whether SSL hostname verification is enabled (default true)",
 		Type:        environschema.Tbool,
@@ -1327,6 +2141,36 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	StorageGetRetryAttemptsKey: {
+		Description: "The minimum number of attempts made to read a file from model storage",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	StorageGetRetryTimeoutKey: {
+		Description: "The time in seconds after which reading a file from model storage is no longer retried",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	StorageListRetryAttemptsKey: {
+		Description: "The minimum number of attempts made to list files in model storage",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	StorageListRetryTimeoutKey: {
+		Description: "The time in seconds after which listing files in model storage is no longer retried",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	StoragePutRetryAttemptsKey: {
+		Description: "The minimum number of attempts made to upload each part of a file to model storage",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	StoragePutRetryTimeoutKey: {
+		Description: "The time in seconds after which uploading a part of a file to model storage is no longer retried",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
 	"test-mode": {
 		Description: `Whether the model is intended for testing.
 If true, accessing the charm store does not affect statistical
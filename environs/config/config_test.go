@@ -21,6 +21,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/testing"
@@ -369,6 +370,75 @@ var configTests = []configTest{
 			"logging-config": "foo=bar",
 		}),
 		err: `unknown severity level "bar"`,
+	}, {
+		about:       "Invalid agent logging configuration",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"agent-logging-config": "foo=bar",
+		}),
+		err: `invalid agent-logging-config: unknown severity level "bar"`,
+	}, {
+		about:       "Relative agent data directory",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"agent-datadir": "var/lib/juju",
+		}),
+		err: `agent-datadir: expected an absolute path, got "var/lib/juju"`,
+	}, {
+		about:       "Windows agent data directory",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"agent-datadir": `C:\Juju\lib`,
+		}),
+	}, {
+		about:       "Relative Windows agent data directory",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"agent-datadir": `Juju\lib`,
+		}),
+		err: `agent-datadir: expected an absolute path, got "Juju\\\\lib"`,
+	}, {
+		about:       "Negative agent max log size",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"agent-max-log-size": -1,
+		}),
+		err: `agent-max-log-size: expected a non-negative number of megabytes, got -1`,
+	}, {
+		about:       "Negative agent CPU quota",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"agent-cpu-quota": -1,
+		}),
+		err: `agent-cpu-quota: expected a non-negative percentage, got -1`,
+	}, {
+		about:       "Negative agent memory limit",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"agent-memory-limit": -1,
+		}),
+		err: `agent-memory-limit: expected a non-negative number of megabytes, got -1`,
+	}, {
+		about:       "cloudinit-userdata not a map",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"cloudinit-userdata": "just a string",
+		}),
+		err: `cloudinit-userdata: expected a YAML map: .*`,
+	}, {
+		about:       "cloudinit-userdata with a section managed by juju",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"cloudinit-userdata": "runcmd:\n  - touch /tmp/foo\n",
+		}),
+		err: `cloudinit-userdata: "runcmd" is managed by juju and may not be set`,
+	}, {
+		about:       "cloudinit-userdata with invalid packages",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"cloudinit-userdata": "packages:\n  - {name: foo}\n",
+		}),
+		err: `cloudinit-userdata: packages: expected package name, got map\[name:foo\]`,
 	}, {
 		about:       "Sample configuration",
 		useDefaults: config.UseDefaults,
@@ -550,6 +620,37 @@ var configTests = []configTest{
 			"syslog-client-cert": testing.ServerCert,
 			"syslog-client-key":  testing.ServerKey,
 		}),
+	}, {
+		about:       "Valid proxy values",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"http-proxy":     "http://user@10.0.0.1:3128",
+			"https-proxy":    "10.0.0.1:3128",
+			"apt-ftp-proxy":  "ftp://[::1]:21",
+			"apt-http-proxy": "apt-cacher.internal",
+			"no-proxy":       "localhost, 10.0.0.0/8,.internal",
+		}),
+	}, {
+		about:       "Invalid http-proxy",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"http-proxy": "http://",
+		}),
+		err: `http-proxy: expected a proxy URL, got "http://"`,
+	}, {
+		about:       "Invalid apt-https-proxy",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"apt-https-proxy": "proxy host",
+		}),
+		err: `apt-https-proxy: expected a proxy URL, got "proxy host"`,
+	}, {
+		about:       "Invalid no-proxy",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"no-proxy": "localhost,http://10.0.0.1",
+		}),
+		err: `no-proxy: expected a host name or address, got "http://10.0.0.1"`,
 	},
 }
 
@@ -982,6 +1083,65 @@ func (s *ConfigSuite) TestLoggingConfigFromEnvironment(c *gc.C) {
 	c.Assert(config.LoggingConfig(), gc.Equals, "<root>=INFO;unit=DEBUG")
 }
 
+func (s *ConfigSuite) TestAgentLoggingConfigFallback(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{
+		"logging-config": "<root>=WARNING;unit=INFO"})
+	c.Assert(config.AgentLoggingConfig(), gc.Equals, "<root>=WARNING;unit=INFO")
+}
+
+func (s *ConfigSuite) TestAgentLoggingConfig(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{
+		"logging-config":       "<root>=WARNING;unit=INFO",
+		"agent-logging-config": "<root>=DEBUG"})
+	c.Assert(config.AgentLoggingConfig(), gc.Equals, "<root>=DEBUG")
+}
+
+func (s *ConfigSuite) TestAgentSettings(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	_, ok := config.AgentDataDir()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = config.AgentMaxLogSize()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = config.AgentCPUQuota()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = config.AgentMemoryLimit()
+	c.Assert(ok, jc.IsFalse)
+
+	config = newTestConfig(c, testing.Attrs{
+		"agent-datadir":      "/srv/juju",
+		"agent-max-log-size": 50,
+		"agent-cpu-quota":    80,
+		"agent-memory-limit": 512,
+	})
+	dataDir, ok := config.AgentDataDir()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(dataDir, gc.Equals, "/srv/juju")
+	size, ok := config.AgentMaxLogSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, 50)
+	quota, ok := config.AgentCPUQuota()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(quota, gc.Equals, 80)
+	limit, ok := config.AgentMemoryLimit()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(limit, gc.Equals, uint64(512))
+}
+
+func (s *ConfigSuite) TestCloudInitUserData(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.CloudInitUserData(), gc.IsNil)
+
+	config = newTestConfig(c, testing.Attrs{
+		"cloudinit-userdata": "packages:\n  - jq\npreruncmd:\n  - echo hello\n",
+	})
+	c.Assert(config.CloudInitUserData(), jc.DeepEquals, map[string]interface{}{
+		"packages":  []interface{}{"jq"},
+		"preruncmd": []interface{}{"echo hello"},
+	})
+}
+
 func (s *ConfigSuite) TestAutoHookRetryDefault(c *gc.C) {
 	config := newTestConfig(c, testing.Attrs{})
 	c.Assert(config.AutomaticallyRetryHooks(), gc.Equals, true)
@@ -1069,15 +1229,15 @@ func (s *ConfigSuite) TestProxyConfigMap(c *gc.C) {
 	s.addJujuFiles(c)
 	cfg := newTestConfig(c, testing.Attrs{})
 	proxySettings := proxy.Settings{
-		Http:    "http proxy",
-		Https:   "https proxy",
-		Ftp:     "ftp proxy",
-		NoProxy: "no proxy",
+		Http:    "http-proxy",
+		Https:   "https-proxy",
+		Ftp:     "ftp-proxy",
+		NoProxy: "no-proxy",
 	}
 	expectedProxySettings := proxy.Settings{
-		Http:    "http://http proxy",
-		Https:   "https://https proxy",
-		Ftp:     "ftp://ftp proxy",
+		Http:    "http://http-proxy",
+		Https:   "https://https-proxy",
+		Ftp:     "ftp://ftp-proxy",
 		NoProxy: "",
 	}
 	cfg, err := cfg.Apply(config.ProxyConfigMap(proxySettings))
@@ -1162,6 +1322,257 @@ func (s *ConfigSuite) TestCoerceForStorage(c *gc.C) {
 	c.Assert(tagsMap, gc.DeepEquals, expectedTags)
 }
 
+func (s *ConfigSuite) TestConstraintProfiles(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"constraint-profiles": map[string]interface{}{
+			"small":    "mem=2G cores=1",
+			"gpu-node": "gpus=1",
+		},
+	})
+	c.Assert(cfg.ConstraintProfiles(), jc.DeepEquals, map[string]constraints.Value{
+		"small":    constraints.MustParse("mem=2G cores=1"),
+		"gpu-node": constraints.MustParse("gpus=1"),
+	})
+}
+
+func (s *ConfigSuite) TestConstraintProfilesInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid": testing.ModelTag.Id(),
+		"constraint-profiles": map[string]interface{}{
+			"small": "mem=lots",
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `validating constraint profiles: constraint profile "small": bad "mem" constraint: .*`)
+}
+
+func (s *ConfigSuite) TestS3Storage(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"s3-storage-endpoint":   "https://minio.example.com:9000",
+		"s3-storage-bucket":     "juju",
+		"s3-storage-access-key": "access",
+		"s3-storage-secret-key": "secret",
+	})
+	settings, ok := cfg.S3Storage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(settings, jc.DeepEquals, config.S3StorageSettings{
+		Endpoint:  "https://minio.example.com:9000",
+		Bucket:    "juju",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+}
+
+func (s *ConfigSuite) TestS3StorageNotSet(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.S3Storage()
+	c.Assert(ok, jc.IsFalse)
+}
+
+var invalidS3StorageTests = []struct {
+	attrs testing.Attrs
+	err   string
+}{{
+	attrs: testing.Attrs{"s3-storage-region": "us-east-1"},
+	err:   "s3-storage-bucket must be set",
+}, {
+	attrs: testing.Attrs{"s3-storage-bucket": "juju"},
+	err:   "one of s3-storage-endpoint or s3-storage-region must be set",
+}, {
+	attrs: testing.Attrs{
+		"s3-storage-bucket":     "juju",
+		"s3-storage-region":     "us-east-1",
+		"s3-storage-access-key": "access",
+	},
+	err: "s3-storage-access-key and s3-storage-secret-key must be set together",
+}}
+
+func (s *ConfigSuite) TestS3StorageInvalid(c *gc.C) {
+	for i, test := range invalidS3StorageTests {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Assert(err, gc.ErrorMatches, "validating S3 storage settings: "+test.err)
+	}
+}
+
+func (s *ConfigSuite) TestSwiftStorage(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"swift-storage-auth-url":                      "https://keystone.example.com:5000/v3",
+		"swift-storage-container":                     "juju",
+		"swift-storage-application-credential-id":     "id",
+		"swift-storage-application-credential-secret": "secret",
+	})
+	settings, ok := cfg.SwiftStorage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(settings, jc.DeepEquals, config.SwiftStorageSettings{
+		AuthURL:                     "https://keystone.example.com:5000/v3",
+		Container:                   "juju",
+		ApplicationCredentialID:     "id",
+		ApplicationCredentialSecret: "secret",
+	})
+}
+
+var invalidSwiftStorageTests = []struct {
+	attrs testing.Attrs
+	err   string
+}{{
+	attrs: testing.Attrs{"swift-storage-username": "user"},
+	err:   "swift-storage-container must be set",
+}, {
+	attrs: testing.Attrs{"swift-storage-container": "juju"},
+	err:   "swift-storage-auth-url must be set",
+}, {
+	attrs: testing.Attrs{
+		"swift-storage-container": "juju",
+		"swift-storage-auth-url":  "https://keystone.example.com:5000/v3",
+		"swift-storage-username":  "user",
+	},
+	err: "either swift-storage-username, swift-storage-password and swift-storage-project-name, " +
+		"or swift-storage-application-credential-id and swift-storage-application-credential-secret must be set",
+}, {
+	attrs: testing.Attrs{
+		"swift-storage-container":                 "juju",
+		"swift-storage-auth-url":                  "https://keystone.example.com:5000/v3",
+		"swift-storage-application-credential-id": "id",
+	},
+	err: "swift-storage-application-credential-id and swift-storage-application-credential-secret must be set together",
+}}
+
+func (s *ConfigSuite) TestSwiftStorageInvalid(c *gc.C) {
+	for i, test := range invalidSwiftStorageTests {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Assert(err, gc.ErrorMatches, "validating Swift storage settings: "+test.err)
+	}
+}
+
+func (s *ConfigSuite) TestAzureStorage(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"azure-storage-account":   "account",
+		"azure-storage-container": "juju",
+		"azure-storage-sas-token": "sv=2016-05-31&sig=abc",
+	})
+	settings, ok := cfg.AzureStorage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(settings, jc.DeepEquals, config.AzureStorageSettings{
+		Account:   "account",
+		Container: "juju",
+		SASToken:  "sv=2016-05-31&sig=abc",
+	})
+}
+
+var invalidAzureStorageTests = []struct {
+	attrs testing.Attrs
+	err   string
+}{{
+	attrs: testing.Attrs{"azure-storage-account": "account"},
+	err:   "azure-storage-container must be set",
+}, {
+	attrs: testing.Attrs{"azure-storage-container": "juju"},
+	err:   "azure-storage-account must be set",
+}, {
+	attrs: testing.Attrs{
+		"azure-storage-account":   "account",
+		"azure-storage-container": "juju",
+	},
+	err: "exactly one of azure-storage-account-key or azure-storage-sas-token must be set",
+}, {
+	attrs: testing.Attrs{
+		"azure-storage-account":     "account",
+		"azure-storage-container":   "juju",
+		"azure-storage-account-key": "a2V5",
+		"azure-storage-sas-token":   "sig=abc",
+	},
+	err: "exactly one of azure-storage-account-key or azure-storage-sas-token must be set",
+}}
+
+func (s *ConfigSuite) TestAzureStorageInvalid(c *gc.C) {
+	for i, test := range invalidAzureStorageTests {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Assert(err, gc.ErrorMatches, "validating Azure storage settings: "+test.err)
+	}
+}
+
+func (s *ConfigSuite) TestGCSStorage(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"gcs-storage-bucket":      "juju",
+		"gcs-storage-project":     "project",
+		"gcs-storage-credentials": `{"type": "service_account"}`,
+	})
+	settings, ok := cfg.GCSStorage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(settings, jc.DeepEquals, config.GCSStorageSettings{
+		Bucket:      "juju",
+		Project:     "project",
+		Credentials: `{"type": "service_account"}`,
+	})
+}
+
+func (s *ConfigSuite) TestGCSStorageInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"gcs-storage-project": "project"},
+		err:   "gcs-storage-bucket must be set",
+	}, {
+		attrs: testing.Attrs{"gcs-storage-bucket": "juju"},
+		err:   "gcs-storage-credentials must be set",
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid": testing.ModelTag.Id(),
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Assert(err, gc.ErrorMatches, "validating GCS storage settings: "+test.err)
+	}
+}
+
+func (s *ConfigSuite) TestStorageRetry(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"storage-get-retry-attempts": 5,
+		"storage-get-retry-timeout":  30,
+		"storage-put-retry-timeout":  60,
+	})
+	settings, ok := cfg.StorageRetry(config.StorageGet)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(settings, jc.DeepEquals, config.StorageRetrySettings{
+		Attempts: 5,
+		Timeout:  30 * time.Second,
+	})
+	settings, ok = cfg.StorageRetry(config.StoragePut)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(settings, jc.DeepEquals, config.StorageRetrySettings{
+		Timeout: time.Minute,
+	})
+	_, ok = cfg.StorageRetry(config.StorageList)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestStorageRetryInvalid(c *gc.C) {
+	attrs := testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":                        testing.ModelTag.Id(),
+		"storage-list-retry-attempts": -1,
+	}
+	_, err := config.New(config.UseDefaults, attrs)
+	c.Assert(err, gc.ErrorMatches, "storage-list-retry-attempts: expected a non-negative number, got -1")
+}
+
 var specializeCharmRepoTests = []struct {
 	about    string
 	testMode bool
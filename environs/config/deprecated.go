@@ -0,0 +1,145 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// DeprecatedAttr describes a config attribute that is no longer used.
+type DeprecatedAttr struct {
+	// Name is the name of the deprecated attribute.
+	Name string
+
+	// Replacement is the name of the attribute that replaces it, to
+	// which its value is migrated. If empty, the attribute has been
+	// removed and its value is discarded.
+	Replacement string
+
+	// Message, if not empty, explains the deprecation to the user.
+	Message string
+}
+
+// DeprecationWarning describes a deprecated attribute found in config.
+type DeprecationWarning struct {
+	DeprecatedAttr
+
+	// Ignored is true if the attribute's value was discarded, either
+	// because it has no replacement, or because the replacement was
+	// also set.
+	Ignored bool
+}
+
+// String returns the warning as it should be shown to the user.
+func (w DeprecationWarning) String() string {
+	var s string
+	switch {
+	case w.Replacement == "":
+		s = fmt.Sprintf("config attribute %q is deprecated and ignored", w.Name)
+	case w.Ignored:
+		s = fmt.Sprintf("config attribute %q is deprecated and ignored because %q is also set", w.Name, w.Replacement)
+	default:
+		s = fmt.Sprintf("config attribute %q is deprecated, use %q instead", w.Name, w.Replacement)
+	}
+	if w.Message != "" {
+		s += ": " + w.Message
+	}
+	return s
+}
+
+var deprecatedAttrs = struct {
+	sync.Mutex
+	attrs map[string]DeprecatedAttr
+}{attrs: make(map[string]DeprecatedAttr)}
+
+func init() {
+	for _, attr := range []DeprecatedAttr{{
+		Name:        "tools-url",
+		Replacement: AgentMetadataURLKey,
+	}, {
+		Name:        "tools-metadata-url",
+		Replacement: AgentMetadataURLKey,
+	}, {
+		Name:        "tools-stream",
+		Replacement: AgentStreamKey,
+	}} {
+		if err := RegisterDeprecatedAttr(attr); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterDeprecatedAttr records that the given attribute is deprecated,
+// so that config using it is migrated to its replacement. It returns an
+// error if the attribute is already registered or is still in use, or
+// if its replacement is itself deprecated.
+func RegisterDeprecatedAttr(attr DeprecatedAttr) error {
+	if attr.Name == "" {
+		return errors.NotValidf("empty attribute name")
+	}
+	if _, ok := configSchema[attr.Name]; ok {
+		return errors.Errorf("cannot deprecate config attribute %q that is still in use", attr.Name)
+	}
+	deprecatedAttrs.Lock()
+	defer deprecatedAttrs.Unlock()
+	if _, ok := deprecatedAttrs.attrs[attr.Name]; ok {
+		return errors.AlreadyExistsf("deprecated config attribute %q", attr.Name)
+	}
+	if _, ok := deprecatedAttrs.attrs[attr.Replacement]; ok {
+		return errors.Errorf("cannot replace %q with deprecated config attribute %q", attr.Name, attr.Replacement)
+	}
+	deprecatedAttrs.attrs[attr.Name] = attr
+	return nil
+}
+
+// DeprecatedAttrs returns all registered deprecated attributes, sorted
+// by name.
+func DeprecatedAttrs() []DeprecatedAttr {
+	deprecatedAttrs.Lock()
+	defer deprecatedAttrs.Unlock()
+	names := make([]string, 0, len(deprecatedAttrs.attrs))
+	for name := range deprecatedAttrs.attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]DeprecatedAttr, len(names))
+	for i, name := range names {
+		result[i] = deprecatedAttrs.attrs[name]
+	}
+	return result
+}
+
+// MigrateDeprecatedAttributes returns a copy of attrs in which any
+// deprecated attributes have been replaced by their replacements,
+// along with a warning for each, sorted by attribute name. Where both
+// a deprecated attribute and its replacement are set, the
+// replacement's value is kept.
+func MigrateDeprecatedAttributes(attrs map[string]interface{}) (map[string]interface{}, []DeprecationWarning) {
+	migrated := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		migrated[k] = v
+	}
+	var warnings []DeprecationWarning
+	for _, attr := range DeprecatedAttrs() {
+		value, ok := migrated[attr.Name]
+		if !ok {
+			continue
+		}
+		delete(migrated, attr.Name)
+		warning := DeprecationWarning{DeprecatedAttr: attr}
+		if attr.Replacement == "" {
+			warning.Ignored = true
+		} else if _, ok := migrated[attr.Replacement]; ok {
+			warning.Ignored = true
+		} else {
+			migrated[attr.Replacement] = value
+		}
+		warnings = append(warnings, warning)
+	}
+	return migrated, warnings
+}
@@ -0,0 +1,98 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type DeprecatedSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&DeprecatedSuite{})
+
+func (s *DeprecatedSuite) TestMigrate(c *gc.C) {
+	attrs := map[string]interface{}{
+		"tools-url": "http://example.com/tools",
+		"name":      "foo",
+	}
+	migrated, warnings := config.MigrateDeprecatedAttributes(attrs)
+	c.Assert(migrated, jc.DeepEquals, map[string]interface{}{
+		"agent-metadata-url": "http://example.com/tools",
+		"name":               "foo",
+	})
+	c.Assert(warnings, gc.HasLen, 1)
+	c.Assert(warnings[0].Name, gc.Equals, "tools-url")
+	c.Assert(warnings[0].Ignored, jc.IsFalse)
+	c.Assert(warnings[0].String(), gc.Equals, `config attribute "tools-url" is deprecated, use "agent-metadata-url" instead`)
+
+	// The original attributes are left alone.
+	c.Assert(attrs["tools-url"], gc.Equals, "http://example.com/tools")
+}
+
+func (s *DeprecatedSuite) TestMigrateReplacementSet(c *gc.C) {
+	migrated, warnings := config.MigrateDeprecatedAttributes(map[string]interface{}{
+		"tools-stream": "devel",
+		"agent-stream": "proposed",
+	})
+	c.Assert(migrated, jc.DeepEquals, map[string]interface{}{
+		"agent-stream": "proposed",
+	})
+	c.Assert(warnings, gc.HasLen, 1)
+	c.Assert(warnings[0].Ignored, jc.IsTrue)
+	c.Assert(warnings[0].String(), gc.Equals, `config attribute "tools-stream" is deprecated and ignored because "agent-stream" is also set`)
+}
+
+func (s *DeprecatedSuite) TestMigrateNothingDeprecated(c *gc.C) {
+	migrated, warnings := config.MigrateDeprecatedAttributes(map[string]interface{}{"name": "foo"})
+	c.Assert(migrated, jc.DeepEquals, map[string]interface{}{"name": "foo"})
+	c.Assert(warnings, gc.HasLen, 0)
+}
+
+func (s *DeprecatedSuite) TestRegister(c *gc.C) {
+	err := config.RegisterDeprecatedAttr(config.DeprecatedAttr{
+		Name:    "deprecated-test-attr",
+		Message: "it never did anything",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { config.UnregisterDeprecatedAttr("deprecated-test-attr") })
+
+	_, warnings := config.MigrateDeprecatedAttributes(map[string]interface{}{
+		"deprecated-test-attr": true,
+	})
+	c.Assert(warnings, gc.HasLen, 1)
+	c.Assert(warnings[0].String(), gc.Equals, `config attribute "deprecated-test-attr" is deprecated and ignored: it never did anything`)
+
+	err = config.RegisterDeprecatedAttr(config.DeprecatedAttr{Name: "deprecated-test-attr"})
+	c.Assert(err, gc.ErrorMatches, `deprecated config attribute "deprecated-test-attr" already exists`)
+}
+
+func (s *DeprecatedSuite) TestRegisterInvalid(c *gc.C) {
+	err := config.RegisterDeprecatedAttr(config.DeprecatedAttr{Name: "agent-stream"})
+	c.Assert(err, gc.ErrorMatches, `cannot deprecate config attribute "agent-stream" that is still in use`)
+
+	err = config.RegisterDeprecatedAttr(config.DeprecatedAttr{
+		Name:        "deprecated-test-attr",
+		Replacement: "tools-url",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot replace "deprecated-test-attr" with deprecated config attribute "tools-url"`)
+}
+
+func (s *DeprecatedSuite) TestNewMigrates(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, testing.FakeConfig().Merge(testing.Attrs{
+		"tools-metadata-url": "http://example.com/tools",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	url, ok := cfg.AgentMetadataURL()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(url, gc.Equals, "http://example.com/tools")
+	_, ok = cfg.AllAttrs()["tools-metadata-url"]
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.DeprecationWarnings(), gc.HasLen, 1)
+}
@@ -0,0 +1,52 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// AttrChange describes how a single attribute differs between two
+// configurations. Old is nil if the attribute was not set in the old
+// configuration, and New is nil if it is not set in the new one.
+type AttrChange struct {
+	Attr string
+	Old  interface{}
+	New  interface{}
+}
+
+// Diff returns the attributes that differ between old and new, sorted
+// by name. Attributes filled in with defaults when new was created are
+// included, so the result describes the effective change.
+func Diff(old, new *Config) []AttrChange {
+	oldAttrs := old.AllAttrs()
+	newAttrs := new.AllAttrs()
+	names := make(map[string]bool)
+	for name := range oldAttrs {
+		names[name] = true
+	}
+	for name := range newAttrs {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []AttrChange
+	for _, name := range sorted {
+		oldv, newv := oldAttrs[name], newAttrs[name]
+		if reflect.DeepEqual(oldv, newv) {
+			continue
+		}
+		changes = append(changes, AttrChange{
+			Attr: name,
+			Old:  oldv,
+			New:  newv,
+		})
+	}
+	return changes
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type DiffSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+}
+
+var _ = gc.Suite(&DiffSuite{})
+
+func (s *DiffSuite) TestDiff(c *gc.C) {
+	old := newTestConfig(c, testing.Attrs{
+		"apt-mirror":    "http://mirror.invalid",
+		"arbitrary-key": "shazam!",
+	})
+	cfg, err := old.Apply(map[string]interface{}{
+		"apt-mirror": "http://other-mirror.invalid",
+		"other-key":  "eggs",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = cfg.Remove([]string{"arbitrary-key"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(config.Diff(old, cfg), jc.DeepEquals, []config.AttrChange{{
+		Attr: "apt-mirror",
+		Old:  "http://mirror.invalid",
+		New:  "http://other-mirror.invalid",
+	}, {
+		Attr: "arbitrary-key",
+		Old:  "shazam!",
+	}, {
+		Attr: "other-key",
+		New:  "eggs",
+	}})
+}
+
+func (s *DiffSuite) TestDiffNoChanges(c *gc.C) {
+	cfg := newTestConfig(c, nil)
+	c.Assert(config.Diff(cfg, cfg), gc.HasLen, 0)
+}
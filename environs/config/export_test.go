@@ -6,3 +6,11 @@ package config
 var (
 	ConfigSchema = configSchema
 )
+
+// UnregisterDeprecatedAttr removes the named deprecated attribute
+// registered by a test.
+func UnregisterDeprecatedAttr(name string) {
+	deprecatedAttrs.Lock()
+	defer deprecatedAttrs.Unlock()
+	delete(deprecatedAttrs.attrs, name)
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ImmutableChange records an attempt to change an immutable attribute.
+type ImmutableChange struct {
+	Attr string
+	Old  interface{}
+	New  interface{}
+}
+
+// String returns a description of the change.
+func (c ImmutableChange) String() string {
+	return fmt.Sprintf("cannot change %s from %#v to %#v", c.Attr, c.Old, c.New)
+}
+
+// ImmutableChangeError is returned when a config change alters
+// immutable attributes. Changes holds every such alteration, sorted
+// by attribute name.
+type ImmutableChangeError struct {
+	Changes []ImmutableChange
+}
+
+// Error is part of the error interface.
+func (e *ImmutableChangeError) Error() string {
+	msgs := make([]string, len(e.Changes))
+	for i, change := range e.Changes {
+		msgs[i] = change.String()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// IsImmutableChangeError reports whether the cause of err is an
+// *ImmutableChangeError.
+func IsImmutableChangeError(err error) bool {
+	_, ok := errors.Cause(err).(*ImmutableChangeError)
+	return ok
+}
+
+// ImmutableAttributes returns the sorted names of the attributes that
+// may not be changed in a model of the given provider type: those
+// common to all models, and those marked as immutable in the config
+// schema registered for the provider, if any.
+func ImmutableAttributes(providerType string) []string {
+	names := append([]string(nil), immutableAttributes...)
+	if s, err := ProviderSchema(providerType); err == nil {
+		for name, attr := range s.Fields {
+			if attr.Immutable {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// immutableChanges returns the changes made to immutable attributes
+// between old and cfg. Attributes not set in old may be set freely.
+func immutableChanges(cfg, old *Config) []ImmutableChange {
+	var changes []ImmutableChange
+	for _, attr := range ImmutableAttributes(old.Type()) {
+		oldv, ok := old.getAttr(attr)
+		if !ok {
+			continue
+		}
+		if newv, _ := cfg.getAttr(attr); !reflect.DeepEqual(newv, oldv) {
+			changes = append(changes, ImmutableChange{
+				Attr: attr,
+				Old:  oldv,
+				New:  newv,
+			})
+		}
+	}
+	return changes
+}
+
+// getAttr returns the value of the named attribute, whether known or
+// not, and whether it is set.
+func (c *Config) getAttr(name string) (interface{}, bool) {
+	if v, ok := c.defined[name]; ok {
+		return v, true
+	}
+	v, ok := c.unknown[name]
+	return v, ok
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type ImmutableSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+}
+
+var _ = gc.Suite(&ImmutableSuite{})
+
+func (s *ImmutableSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	err := config.RegisterProviderSchema("my-type", config.ProviderConfigSchema{
+		Fields: environschema.Fields{
+			"network-id": {
+				Type:      environschema.Tstring,
+				Immutable: true,
+			},
+			"region-name": {
+				Type: environschema.Tstring,
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { config.UnregisterProviderSchema("my-type") })
+}
+
+func (s *ImmutableSuite) TestImmutableAttributes(c *gc.C) {
+	c.Assert(config.ImmutableAttributes("my-type"), jc.DeepEquals, []string{
+		"firewall-mode", "name", "network-id", "type", "uuid",
+	})
+	c.Assert(config.ImmutableAttributes("unregistered"), jc.DeepEquals, []string{
+		"firewall-mode", "name", "type", "uuid",
+	})
+}
+
+func (s *ImmutableSuite) TestValidateReportsAllChanges(c *gc.C) {
+	old := newTestConfig(c, testing.Attrs{
+		"firewall-mode": config.FwGlobal,
+		"network-id":    "net-1",
+		"region-name":   "north",
+	})
+	cfg, err := old.Apply(map[string]interface{}{
+		"firewall-mode": config.FwInstance,
+		"network-id":    "net-2",
+		"region-name":   "south",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = config.Validate(cfg, old)
+	c.Assert(err, jc.Satisfies, config.IsImmutableChangeError)
+	c.Assert(err, gc.ErrorMatches, `cannot change firewall-mode from "global" to "instance"; cannot change network-id from "net-1" to "net-2"`)
+	c.Assert(errors.Cause(err).(*config.ImmutableChangeError).Changes, jc.DeepEquals, []config.ImmutableChange{{
+		Attr: "firewall-mode",
+		Old:  "global",
+		New:  "instance",
+	}, {
+		Attr: "network-id",
+		Old:  "net-1",
+		New:  "net-2",
+	}})
+}
+
+func (s *ImmutableSuite) TestValidateAllowsSettingUnsetAttribute(c *gc.C) {
+	old := newTestConfig(c, nil)
+	cfg, err := old.Apply(map[string]interface{}{"network-id": "net-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = config.Validate(cfg, old)
+	c.Assert(err, jc.ErrorIsNil)
+}
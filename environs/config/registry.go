@@ -0,0 +1,212 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
+)
+
+// ProviderConfigSchema describes the config attributes specific to a
+// provider type.
+type ProviderConfigSchema struct {
+	// Fields holds the provider's attributes. Attributes holding
+	// credentials or other secrets should have Secret set.
+	Fields environschema.Fields
+
+	// Defaults holds the default values of the attributes in
+	// Fields. Attributes with no default are required.
+	Defaults schema.Defaults
+}
+
+// Validate returns an error if the schema is invalid.
+func (s ProviderConfigSchema) Validate() error {
+	if _, err := Schema(s.Fields); err != nil {
+		return errors.Trace(err)
+	}
+	for name := range s.Defaults {
+		if _, ok := s.Fields[name]; !ok {
+			return errors.Errorf("default given for undeclared field %q", name)
+		}
+	}
+	return nil
+}
+
+// SecretAttrs returns the sorted names of the schema's attributes that
+// hold secrets.
+func (s ProviderConfigSchema) SecretAttrs() []string {
+	var names []string
+	for name, attr := range s.Fields {
+		if attr.Secret {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+var providerSchemas = struct {
+	sync.Mutex
+	schemas map[string]ProviderConfigSchema
+}{schemas: make(map[string]ProviderConfigSchema)}
+
+// RegisterProviderSchema records the config schema of the given
+// provider type. It is conventionally called from the provider's init
+// function, alongside environs.RegisterProvider.
+func RegisterProviderSchema(providerType string, s ProviderConfigSchema) error {
+	if err := s.Validate(); err != nil {
+		return errors.Annotatef(err, "invalid config schema for provider %q", providerType)
+	}
+	providerSchemas.Lock()
+	defer providerSchemas.Unlock()
+	if _, ok := providerSchemas.schemas[providerType]; ok {
+		return errors.AlreadyExistsf("config schema for provider %q", providerType)
+	}
+	providerSchemas.schemas[providerType] = s
+	return nil
+}
+
+// UnregisterProviderSchema removes the config schema of the given
+// provider type, if any. It is intended for use in tests.
+func UnregisterProviderSchema(providerType string) {
+	providerSchemas.Lock()
+	defer providerSchemas.Unlock()
+	delete(providerSchemas.schemas, providerType)
+}
+
+// ProviderSchema returns the config schema registered for the given
+// provider type. If none has been registered, an error satisfying
+// errors.IsNotFound is returned.
+func ProviderSchema(providerType string) (ProviderConfigSchema, error) {
+	providerSchemas.Lock()
+	defer providerSchemas.Unlock()
+	s, ok := providerSchemas.schemas[providerType]
+	if !ok {
+		return ProviderConfigSchema{}, errors.NotFoundf("config schema for provider %q", providerType)
+	}
+	return s, nil
+}
+
+// RegisteredProviderSchemas returns the sorted provider types for
+// which config schemas have been registered.
+func RegisteredProviderSchemas() []string {
+	providerSchemas.Lock()
+	defer providerSchemas.Unlock()
+	types := make([]string, 0, len(providerSchemas.schemas))
+	for providerType := range providerSchemas.schemas {
+		types = append(types, providerType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ValidateProviderAttrs checks the unknown attributes of the config
+// against the schema registered for its provider type, as
+// ValidateUnknownAttrs does, but reporting each invalid attribute
+// by name, as in "vpc-id must be a string, got bool". If no schema
+// has been registered for the provider type, an error satisfying
+// errors.IsNotFound is returned.
+func (cfg *Config) ValidateProviderAttrs() (map[string]interface{}, error) {
+	s, err := ProviderSchema(cfg.Type())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attrs := cfg.UnknownAttrs()
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := attrs[name]
+		if !ok || value == nil {
+			continue
+		}
+		if err := checkAttr(name, s.Fields[name], value); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	fields, _, err := s.Fields.ValidationSchema()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg.ValidateUnknownAttrs(fields, s.Defaults)
+}
+
+// checkAttr returns an error naming the attribute if the value cannot
+// be coerced to the attribute's type, or is not one of its allowed
+// values. The value is coerced through the schema first, so that
+// values given as strings on the command line, such as "true" for a
+// bool, are accepted.
+func checkAttr(name string, attr environschema.Attr, value interface{}) error {
+	typeAttr := attr
+	typeAttr.Values = nil
+	checker, err := typeAttr.Checker()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	coerced, err := checker.Coerce(value, []string{name})
+	if err != nil {
+		return typeError(name, attr.Type, value)
+	}
+	if len(attr.Values) == 0 {
+		return nil
+	}
+	allowed := make([]string, len(attr.Values))
+	for i, v := range attr.Values {
+		if fmt.Sprint(v) == fmt.Sprint(coerced) {
+			return nil
+		}
+		allowed[i] = fmt.Sprint(v)
+	}
+	return errors.NewNotValid(nil, fmt.Sprintf("%s must be one of %s, got %v", name, strings.Join(allowed, ", "), value))
+}
+
+// typeError returns an error naming the attribute, reporting that the
+// value cannot be coerced to the given type.
+func typeError(name string, t environschema.FieldType, value interface{}) error {
+	got := describeType(value)
+	switch t {
+	case environschema.Tstring:
+		return errors.NewNotValid(nil, fmt.Sprintf("%s must be a string, got %s", name, got))
+	case environschema.Tbool:
+		return errors.NewNotValid(nil, fmt.Sprintf("%s must be a bool, got %s", name, got))
+	case environschema.Tint:
+		if got == "string" {
+			return errors.NewNotValid(nil, fmt.Sprintf("%s must be an integer, got %q", name, value))
+		}
+		return errors.NewNotValid(nil, fmt.Sprintf("%s must be an integer, got %s", name, got))
+	case environschema.Tattrs:
+		return errors.NewNotValid(nil, fmt.Sprintf("%s must be a map, got %s", name, got))
+	}
+	return errors.NewNotValid(nil, fmt.Sprintf("%s must be a %s, got %s", name, t, got))
+}
+
+// describeType returns a short description of the value's type for
+// use in error messages.
+func describeType(value interface{}) string {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Map:
+		return "map"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,164 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type RegistrySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&RegistrySuite{})
+
+var testProviderSchema = config.ProviderConfigSchema{
+	Fields: environschema.Fields{
+		"region-name": {
+			Description: "The region to use",
+			Type:        environschema.Tstring,
+		},
+		"api-key": {
+			Description: "The key used to access the API",
+			Type:        environschema.Tstring,
+			Secret:      true,
+		},
+		"use-floating-ip": {
+			Description: "Whether to use floating IPs",
+			Type:        environschema.Tbool,
+		},
+		"max-retries": {
+			Description: "The number of times to retry API calls",
+			Type:        environschema.Tint,
+		},
+		"network-mode": {
+			Description: "How to configure networking",
+			Type:        environschema.Tstring,
+			Values:      []interface{}{"flat", "vlan"},
+		},
+	},
+	Defaults: schema.Defaults{
+		"region-name":     "",
+		"api-key":         "",
+		"use-floating-ip": false,
+		"max-retries":     3,
+		"network-mode":    "flat",
+	},
+}
+
+func (s *RegistrySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	err := config.RegisterProviderSchema("registry-test", testProviderSchema)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { config.UnregisterProviderSchema("registry-test") })
+}
+
+func (s *RegistrySuite) newConfig(c *gc.C, attrs testing.Attrs) *config.Config {
+	cfg, err := config.New(config.UseDefaults, testing.FakeConfig().Merge(testing.Attrs{
+		"type": "registry-test",
+	}).Merge(attrs))
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func (s *RegistrySuite) TestProviderSchema(c *gc.C) {
+	schema, err := config.ProviderSchema("registry-test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schema.SecretAttrs(), jc.DeepEquals, []string{"api-key"})
+	c.Assert(config.RegisteredProviderSchemas(), jc.Contains, "registry-test")
+
+	_, err = config.ProviderSchema("unregistered")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RegistrySuite) TestRegisterTwice(c *gc.C) {
+	err := config.RegisterProviderSchema("registry-test", testProviderSchema)
+	c.Assert(err, gc.ErrorMatches, `config schema for provider "registry-test" already exists`)
+}
+
+func (s *RegistrySuite) TestRegisterInvalid(c *gc.C) {
+	err := config.RegisterProviderSchema("invalid", config.ProviderConfigSchema{
+		Fields: environschema.Fields{
+			"name": {Type: environschema.Tstring},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid config schema for provider "invalid": config field "name" clashes with global config`)
+
+	err = config.RegisterProviderSchema("invalid", config.ProviderConfigSchema{
+		Defaults: schema.Defaults{"region-name": ""},
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid config schema for provider "invalid": default given for undeclared field "region-name"`)
+}
+
+func (s *RegistrySuite) TestValidateProviderAttrs(c *gc.C) {
+	attrs, err := s.newConfig(c, testing.Attrs{
+		"region-name": "north",
+		"max-retries": 5,
+	}).ValidateProviderAttrs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attrs["region-name"], gc.Equals, "north")
+	c.Assert(attrs["max-retries"], gc.Equals, 5)
+	c.Assert(attrs["use-floating-ip"], gc.Equals, false)
+	c.Assert(attrs["network-mode"], gc.Equals, "flat")
+}
+
+func (s *RegistrySuite) TestValidateProviderAttrsCoercesStrings(c *gc.C) {
+	// Values set on the command line arrive as strings.
+	attrs, err := s.newConfig(c, testing.Attrs{
+		"use-floating-ip": "true",
+		"max-retries":     "7",
+	}).ValidateProviderAttrs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attrs["use-floating-ip"], gc.Equals, true)
+	c.Assert(attrs["max-retries"], gc.Equals, 7)
+}
+
+var invalidProviderAttrsTests = []struct {
+	about string
+	attrs testing.Attrs
+	err   string
+}{{
+	about: "string given a bool",
+	attrs: testing.Attrs{"region-name": true},
+	err:   "region-name must be a string, got bool",
+}, {
+	about: "bool given a string",
+	attrs: testing.Attrs{"use-floating-ip": "yes"},
+	err:   "use-floating-ip must be a bool, got string",
+}, {
+	about: "int given a non-numeric string",
+	attrs: testing.Attrs{"max-retries": "many"},
+	err:   `max-retries must be an integer, got "many"`,
+}, {
+	about: "int given a list",
+	attrs: testing.Attrs{"max-retries": []interface{}{1}},
+	err:   "max-retries must be an integer, got list",
+}, {
+	about: "value not allowed",
+	attrs: testing.Attrs{"network-mode": "mesh"},
+	err:   "network-mode must be one of flat, vlan, got mesh",
+}}
+
+func (s *RegistrySuite) TestValidateProviderAttrsInvalid(c *gc.C) {
+	for i, test := range invalidProviderAttrsTests {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.newConfig(c, test.attrs).ValidateProviderAttrs()
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *RegistrySuite) TestValidateProviderAttrsUnregistered(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, testing.FakeConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cfg.ValidateProviderAttrs()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,358 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package filestorage
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs/storage"
+)
+
+// encryptionKeySize is the size of the AES-256 encryption key.
+const encryptionKeySize = 32
+
+// encryptedDir is the directory, relative to the storage directory,
+// in which files encrypted at rest are kept. Whether a file is
+// encrypted is recorded by where it is kept, never by its content.
+const encryptedDir = ".encrypted"
+
+// encryptedMagic identifies the format of an encrypted file. It is
+// checked when the file is decrypted.
+const encryptedMagic = "JUJUENC1"
+
+// An encrypted file is laid out as follows:
+//
+//	magic    8 bytes, encryptedMagic
+//	prefix   7 bytes, random, chosen when the file is written
+//	segment  repeated; the plaintext is split into segments of
+//	         segmentSize bytes, the last of which may be shorter
+//	         (or empty, if the plaintext is), and each is sealed
+//	         with AES-256-GCM into len(plaintext)+16 bytes
+//
+// This is the STREAM construction of Hoang, Reyhanitabar, Rogaway
+// and Vizár ("Online Authenticated-Encryption and its Nonce-Reuse
+// Misuse-Resistance", CRYPTO 2015), as used by Tink's streaming AEAD
+// and age. The 12 byte nonce of segment n is the prefix, followed by
+// n as a big-endian uint32, followed by a byte that is 1 for the
+// final segment and 0 otherwise. Sealing each segment with its
+// position means a segment moved elsewhere in the file fails to
+// open, and marking the final segment means a file cut at a segment
+// boundary fails to open too, since its new last segment was not
+// sealed as final. A file may therefore hold at most 2^32 segments,
+// or 256TiB.
+const (
+	segmentSize     = 64 * 1024
+	noncePrefixSize = 7
+	nonceSize       = noncePrefixSize + 4 + 1
+	headerSize      = len(encryptedMagic) + noncePrefixSize
+)
+
+// NewEncryptingFileStorageWriter returns a new read/write storage for
+// a directory inside the local file system, which encrypts files put
+// with storage.EncryptionSSE using the key in keyFile. The key file
+// is created if it does not exist; it must be outside the storage
+// directory, so that the key is not kept alongside the data.
+func NewEncryptingFileStorageWriter(path, keyFile string) (storage.Storage, error) {
+	stor, err := NewFileStorageWriter(path)
+	if err != nil {
+		return nil, err
+	}
+	w := stor.(*fileStorageWriter)
+	if w.keyFile, err = encryptionKeyPath(w.path, keyFile); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// NewDecryptingFileStorageReader returns a new storage reader for a
+// directory inside the local file system, which decrypts files that
+// were encrypted at rest using the key in keyFile.
+func NewDecryptingFileStorageReader(path, keyFile string) (storage.StorageReader, error) {
+	reader, err := NewFileStorageReader(path)
+	if err != nil {
+		return nil, err
+	}
+	r := reader.(*fileStorageReader)
+	if r.keyFile, err = encryptionKeyPath(r.path, keyFile); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return r, nil
+}
+
+// encryptionKeyPath returns the absolute path of the given key file,
+// which must not be inside the storage directory.
+func encryptionKeyPath(storageDir, keyFile string) (string, error) {
+	p, err := utils.NormalizePath(keyFile)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if p, err = filepath.Abs(p); err != nil {
+		return "", errors.Trace(err)
+	}
+	if rel, err := filepath.Rel(storageDir, p); err == nil && !strings.HasPrefix(rel, "..") {
+		return "", errors.NotValidf("encryption key file %q inside storage directory", keyFile)
+	}
+	return p, nil
+}
+
+// encryptionKey returns the encryption key, creating it if create is
+// true and it does not yet exist.
+func (f *fileStorageReader) encryptionKey(create bool) ([]byte, error) {
+	if f.keyFile == "" {
+		return nil, errors.NotSupportedf("encryption without a key file")
+	}
+	key, err := ioutil.ReadFile(f.keyFile)
+	if err == nil {
+		if len(key) != encryptionKeySize {
+			return nil, errors.Errorf("invalid encryption key in %q", f.keyFile)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return nil, errors.Annotate(err, "reading encryption key")
+	}
+	key = make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Annotate(err, "generating encryption key")
+	}
+	file, err := os.OpenFile(f.keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		// Another writer created the key first; use theirs.
+		return f.encryptionKey(false)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "creating encryption key")
+	}
+	_, err = file.Write(key)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.keyFile)
+		return nil, errors.Annotate(err, "writing encryption key")
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// segmentNonce returns the nonce for the given segment.
+func segmentNonce(prefix []byte, n uint32, final bool) []byte {
+	nonce := make([]byte, nonceSize)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], n)
+	if final {
+		nonce[nonceSize-1] = 1
+	}
+	return nonce
+}
+
+// encryptedLength returns the length of the encrypted form of a file
+// with the given plaintext length.
+func encryptedLength(length int64, overhead int) int64 {
+	segments := (length + segmentSize - 1) / segmentSize
+	if segments == 0 {
+		// An empty file is a single, empty, final segment.
+		segments = 1
+	}
+	return int64(headerSize) + length + segments*int64(overhead)
+}
+
+// encryptingReader reads the encrypted form of a plaintext of known
+// length.
+type encryptingReader struct {
+	gcm       cipher.AEAD
+	prefix    []byte
+	r         io.Reader
+	remaining int64
+	segment   uint32
+	plaintext []byte
+	buf       []byte
+	pending   []byte
+	done      bool
+}
+
+// newEncryptingReader returns a reader for the encrypted form of the
+// length bytes read from r, and the length of the encrypted form.
+func newEncryptingReader(key []byte, r io.Reader, length int64) (io.Reader, int64, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, 0, errors.Annotate(err, "generating nonce")
+	}
+	header := append([]byte(encryptedMagic), prefix...)
+	return &encryptingReader{
+		gcm:       gcm,
+		prefix:    prefix,
+		r:         r,
+		remaining: length,
+		pending:   header,
+		plaintext: make([]byte, segmentSize),
+	}, encryptedLength(length, gcm.Overhead()), nil
+}
+
+// Read is specified in the io.Reader interface.
+func (e *encryptingReader) Read(p []byte) (int, error) {
+	if len(e.pending) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		n := int64(segmentSize)
+		if e.remaining < n {
+			n = e.remaining
+		}
+		plaintext := e.plaintext[:n]
+		if _, err := io.ReadFull(e.r, plaintext); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, errors.Trace(err)
+		}
+		e.remaining -= n
+		e.done = e.remaining == 0
+		nonce := segmentNonce(e.prefix, e.segment, e.done)
+		e.buf = e.gcm.Seal(e.buf[:0], nonce, plaintext, nil)
+		e.pending = e.buf
+		e.segment++
+	}
+	n := copy(p, e.pending)
+	e.pending = e.pending[n:]
+	return n, nil
+}
+
+// decryptingReader reads the plaintext of an encrypted file, checking
+// each segment as it is read.
+type decryptingReader struct {
+	gcm        cipher.AEAD
+	prefix     []byte
+	r          *bufio.Reader
+	closer     io.Closer
+	segment    uint32
+	ciphertext []byte
+	buf        []byte
+	pending    []byte
+	done       bool
+}
+
+// Read is specified in the io.Reader interface.
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(d.r, d.ciphertext)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			if err == io.EOF {
+				err = errors.New("encrypted file truncated")
+			}
+			return 0, errors.Trace(err)
+		}
+		if _, err := d.r.Peek(1); err == io.EOF {
+			d.done = true
+		}
+		nonce := segmentNonce(d.prefix, d.segment, d.done)
+		d.buf, err = d.gcm.Open(d.buf[:0], nonce, d.ciphertext[:n], nil)
+		if err != nil {
+			return 0, errors.Annotate(err, "decrypting file")
+		}
+		d.pending = d.buf
+		d.segment++
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// Close is specified in the io.Closer interface.
+func (d *decryptingReader) Close() error {
+	return d.closer.Close()
+}
+
+// encryptedPath returns the path of the named file when it is
+// encrypted at rest.
+func (f *fileStorageReader) encryptedPath(name string) string {
+	return filepath.Join(f.path, encryptedDir, name)
+}
+
+// decrypt returns a reader for the plaintext of the given file, which
+// was encrypted at rest, decrypting it as it is read.
+func (f *fileStorageReader) decrypt(file *os.File) (io.ReadCloser, error) {
+	key, err := f.encryptionKey(false)
+	if err != nil {
+		file.Close()
+		return nil, errors.Trace(err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		file.Close()
+		return nil, errors.Trace(err)
+	}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(file, header); err != nil {
+		file.Close()
+		return nil, errors.Annotate(err, "reading encrypted file header")
+	}
+	if string(header[:len(encryptedMagic)]) != encryptedMagic {
+		file.Close()
+		return nil, errors.New("unknown encrypted file format")
+	}
+	prefix := header[len(encryptedMagic):]
+	return &decryptingReader{
+		gcm:        gcm,
+		prefix:     prefix,
+		r:          bufio.NewReader(file),
+		closer:     file,
+		ciphertext: make([]byte, segmentSize+gcm.Overhead()),
+	}, nil
+}
+
+// PutWithOptions is specified in the storage.OptionsWriter interface.
+// With EncryptionSSE, the file is encrypted at rest with the key in
+// the writer's key file, and decrypted transparently by Get. Storage
+// without a key file does not support encryption.
+func (f *fileStorageWriter) PutWithOptions(name string, r io.Reader, length int64, opts storage.PutOptions) error {
+	switch opts.Encryption {
+	case storage.EncryptionNone:
+		return f.Put(name, r, length)
+	case storage.EncryptionSSE:
+	default:
+		return errors.NotSupportedf("%q encryption", opts.Encryption)
+	}
+	key, err := f.encryptionKey(true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isInternalPath(name) {
+		return &os.PathError{
+			Op:   "Put",
+			Path: name,
+			Err:  os.ErrPermission,
+		}
+	}
+	r, length, err = newEncryptingReader(key, r, length)
+	if err != nil {
+		return errors.Annotatef(err, "encrypting %q", name)
+	}
+	return f.put(name, r, length, true)
+}
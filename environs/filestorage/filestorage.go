@@ -4,6 +4,7 @@
 package filestorage
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,9 +12,13 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/mutex"
 	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/environs/storage"
 )
@@ -22,6 +27,10 @@ import (
 // by the local filesystem.
 type fileStorageReader struct {
 	path string
+
+	// keyFile holds the path of the key with which files are
+	// encrypted at rest, if encryption is enabled.
+	keyFile string
 }
 
 // NewFileStorageReader returns a new storage reader for
@@ -41,7 +50,7 @@ func NewFileStorageReader(path string) (reader storage.StorageReader, err error)
 	if !fi.Mode().IsDir() {
 		return nil, fmt.Errorf("specified source path is not a directory: %s", path)
 	}
-	return &fileStorageReader{p}, nil
+	return &fileStorageReader{path: p}, nil
 }
 
 func (f *fileStorageReader) fullPath(name string) string {
@@ -57,8 +66,26 @@ func (f *fileStorageReader) Get(name string) (io.ReadCloser, error) {
 			Err:  os.ErrNotExist,
 		}
 	}
-	filename := f.fullPath(name)
-	fi, err := os.Stat(filename)
+	// A file encrypted at rest is kept in the encrypted directory,
+	// rather than alongside unencrypted files.
+	file, err := openFile(f.encryptedPath(name), name)
+	if err == nil {
+		return f.decrypt(file)
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	file, err = openFile(f.fullPath(name), name)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// openFile opens the file at the given path, which holds the named
+// file. If there is no such file, an error satisfying
+// errors.IsNotFound is returned.
+func openFile(path, name string) (*os.File, error) {
+	fi, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			err = errors.NewNotFound(err, "")
@@ -67,48 +94,98 @@ func (f *fileStorageReader) Get(name string) (io.ReadCloser, error) {
 	} else if fi.IsDir() {
 		return nil, errors.NotFoundf("no such file with name %q", name)
 	}
-	file, err := os.Open(filename)
+	return os.Open(path)
+}
+
+// GetRange implements storage.RangeReader.GetRange.
+func (f *fileStorageReader) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	r, err := f.Get(name)
 	if err != nil {
 		return nil, err
 	}
-	return file, nil
+	file, ok := r.(*os.File)
+	if !ok {
+		// Encrypted files can only be decrypted from the start.
+		return storage.LimitRange(r, offset, length)
+	}
+	if _, err := file.Seek(offset, 0); err != nil {
+		file.Close()
+		return nil, errors.Trace(err)
+	}
+	return storage.LimitRange(file, 0, length)
 }
 
 // isInternalPath returns true if a path should be hidden from user visibility
-// filestorage uses ".tmp/" as a staging directory for uploads, so we don't
-// want it to be visible
+// filestorage uses ".tmp/" as a staging directory for uploads, and keeps
+// files encrypted at rest in ".encrypted/", so we don't want them to be
+// visible
 func isInternalPath(path string) bool {
 	// This blocks both ".tmp", ".tmp/foo" but also ".tmpdir", better to be
 	// overly restrictive to start with
-	return strings.HasPrefix(path, ".tmp")
+	return strings.HasPrefix(path, ".tmp") || strings.HasPrefix(path, encryptedDir)
 }
 
 // List implements storage.StorageReader.List.
 func (f *fileStorageReader) List(prefix string) ([]string, error) {
-	var names []string
 	if isInternalPath(prefix) {
+		return nil, nil
+	}
+	names, err := listFiles(f.path, prefix)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := listFiles(filepath.Join(f.path, encryptedDir), prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) == 0 {
+		sort.Strings(names)
 		return names, nil
 	}
-	prefix = filepath.Join(f.path, prefix)
+	return set.NewStrings(append(names, encrypted...)...).SortedValues(), nil
+}
+
+// listFiles returns the names, relative to root, of the files below
+// root whose names start with prefix. Internal directories are not
+// listed.
+func listFiles(root, prefix string) ([]string, error) {
+	var names []string
+	prefix = filepath.Join(root, prefix)
 	dir := filepath.Dir(prefix)
+	if prefix == root {
+		dir = root
+	}
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() && strings.HasPrefix(path, prefix) {
-			names = append(names, path[len(f.path)+1:])
+		if path == root || !strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return nil
+		}
+		name := path[len(root)+1:]
+		if info.IsDir() {
+			if isInternalPath(name) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(path, prefix) {
+			names = append(names, name)
 		}
 		return nil
 	})
 	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
-	sort.Strings(names)
 	return names, nil
 }
 
-// URL implements storage.StorageReader.URL.
+// URL implements storage.StorageReader.URL. Files encrypted at rest
+// can only be read through Get, so they have no URL.
 func (f *fileStorageReader) URL(name string) (string, error) {
+	if _, err := os.Stat(f.encryptedPath(name)); err == nil {
+		return "", errors.NotSupportedf("URL of encrypted file %q", name)
+	}
 	return utils.MakeFileURL(filepath.Join(f.path, name)), nil
 }
 
@@ -118,6 +195,11 @@ func (f *fileStorageReader) DefaultConsistencyStrategy() utils.AttemptStrategy {
 	return utils.AttemptStrategy{}
 }
 
+// Consistency implements storage.ConsistencyReporter.Consistency.
+func (f *fileStorageReader) Consistency() storage.Consistency {
+	return storage.StrongConsistency
+}
+
 // ShouldRetry is specified in the StorageReader interface.
 func (f *fileStorageReader) ShouldRetry(err error) bool {
 	return false
@@ -137,6 +219,31 @@ func NewFileStorageWriter(path string) (storage.Storage, error) {
 	return &fileStorageWriter{*reader.(*fileStorageReader)}, nil
 }
 
+// lockTimeout is how long a writer waits for another process writing
+// to the same storage directory.
+var lockTimeout = 30 * time.Second
+
+// acquireLock acquires the machine-wide lock held while writing to the
+// storage directory, so that writers in different processes do not
+// remove each other's staging directory.
+func (f *fileStorageWriter) acquireLock() (mutex.Releaser, error) {
+	hash := sha256.Sum256([]byte(f.path))
+	spec := mutex.Spec{
+		Name:    fmt.Sprintf("filestorage-%x", hash[:8]),
+		Clock:   clock.WallClock,
+		Delay:   20 * time.Millisecond,
+		Timeout: lockTimeout,
+	}
+	releaser, err := mutex.Acquire(spec)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot acquire storage lock")
+	}
+	return releaser, nil
+}
+
+// Put implements storage.StorageWriter.Put. The file is written to a
+// temporary file, which is flushed to disk before being renamed into
+// place, so that a crash never leaves a truncated file behind.
 func (f *fileStorageWriter) Put(name string, r io.Reader, length int64) error {
 	if isInternalPath(name) {
 		return &os.PathError{
@@ -145,7 +252,23 @@ func (f *fileStorageWriter) Put(name string, r io.Reader, length int64) error {
 			Err:  os.ErrPermission,
 		}
 	}
-	fullpath := f.fullPath(name)
+	return f.put(name, r, length, false)
+}
+
+// put writes the named file, in the encrypted directory if encrypted
+// is true, and then removes any copy of the file kept with the other
+// setting. A crash between the two leaves both copies, of which Get
+// reads the encrypted one; either way, a complete file is read.
+func (f *fileStorageWriter) put(name string, r io.Reader, length int64, encrypted bool) error {
+	releaser, err := f.acquireLock()
+	if err != nil {
+		return err
+	}
+	defer releaser.Release()
+	fullpath, otherpath := f.fullPath(name), f.encryptedPath(name)
+	if encrypted {
+		fullpath, otherpath = otherpath, fullpath
+	}
 	dir := filepath.Dir(fullpath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
@@ -161,21 +284,72 @@ func (f *fileStorageWriter) Put(name string, r io.Reader, length int64) error {
 		return err
 	}
 	_, err = io.CopyN(file, r, length)
+	if err == nil {
+		err = file.Sync()
+	}
 	file.Close()
 	if err != nil {
 		os.Remove(file.Name())
 		return err
 	}
-	return utils.ReplaceFile(file.Name(), fullpath)
+	if err := utils.ReplaceFile(file.Name(), fullpath); err != nil {
+		return err
+	}
+	syncDir(dir)
+	if err := os.Remove(otherpath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// syncDir flushes the given directory's entries to disk, so that a
+// file renamed into it survives a crash. Not all platforms can sync
+// directories, so failures are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// PutHashed implements storage.HashedWriter.PutHashed. The existing
+// file, if any, is hashed to decide whether it must be replaced.
+func (f *fileStorageWriter) PutHashed(name, hash string, r io.Reader, length int64) error {
+	if existing, err := f.hashFile(name); err == nil && existing == hash {
+		return nil
+	}
+	return f.Put(name, r, length)
+}
+
+// hashFile returns the hex-encoded SHA256 hash of the named file's
+// content.
+func (f *fileStorageWriter) hashFile(name string) (string, error) {
+	r, err := f.Get(name)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
 
 func (f *fileStorageWriter) Remove(name string) error {
-	fullpath := f.fullPath(name)
-	err := os.Remove(fullpath)
-	if os.IsNotExist(err) {
-		err = nil
+	releaser, err := f.acquireLock()
+	if err != nil {
+		return err
+	}
+	defer releaser.Release()
+	for _, path := range []string{f.encryptedPath(name), f.fullPath(name)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
-	return err
+	return nil
 }
 
 func (f *fileStorageWriter) RemoveAll() error {
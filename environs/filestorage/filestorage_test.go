@@ -9,6 +9,8 @@ package filestorage_test
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -145,6 +147,37 @@ func (s *filestorageSuite) TestGet(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *filestorageSuite) TestGetRange(c *gc.C) {
+	data := []byte("0123456789")
+	err := s.writer.Put("plain", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	err = storage.PutWithOptions(s.writer, "encrypted", bytes.NewReader(data), int64(len(data)), storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, name := range []string{"plain", "encrypted"} {
+		for _, test := range []struct {
+			offset, length int64
+			expect         string
+		}{
+			{0, -1, "0123456789"},
+			{3, 4, "3456"},
+			{7, -1, "789"},
+			{8, 10, "89"},
+			{12, -1, ""},
+		} {
+			c.Logf("%s: offset %d, length %d", name, test.offset, test.length)
+			r, err := storage.GetRange(s.reader, name, test.offset, test.length)
+			c.Assert(err, jc.ErrorIsNil)
+			b, err := ioutil.ReadAll(r)
+			r.Close()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(string(b), gc.Equals, test.expect)
+		}
+	}
+}
+
 func (s *filestorageSuite) TestGetRefusesTemp(c *gc.C) {
 	s.createFile(c, ".tmp/test-file")
 	_, err := storage.Get(s.reader, ".tmp/test-file")
@@ -165,6 +198,32 @@ func (s *filestorageSuite) TestPut(c *gc.C) {
 	c.Assert(b, gc.DeepEquals, data)
 }
 
+type failingReader struct {
+	data []byte
+}
+
+func (r *failingReader) Read(buf []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, fmt.Errorf("connection reset")
+	}
+	n := copy(buf, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (s *filestorageSuite) TestPutInterruptedLeavesOldFile(c *gc.C) {
+	err := s.writer.Put("test-write", strings.NewReader("old"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.writer.Put("test-write", &failingReader{[]byte("new, but tr")}, 20)
+	c.Assert(err, gc.ErrorMatches, "connection reset")
+
+	b, err := ioutil.ReadFile(filepath.Join(s.dir, "test-write"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(b), gc.Equals, "old")
+	_, err = os.Stat(filepath.Join(s.dir, ".tmp"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
 func (s *filestorageSuite) TestPutRefusesTmp(c *gc.C) {
 	data := []byte{1, 2, 3, 4, 5}
 	err := s.writer.Put(".tmp/test-write", bytes.NewReader(data), int64(len(data)))
@@ -179,6 +238,247 @@ func (s *filestorageSuite) TestPutRefusesTmp(c *gc.C) {
 	c.Assert(err, jc.Satisfies, os.IsNotExist)
 }
 
+type unreadable struct{}
+
+func (unreadable) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("unexpected read")
+}
+
+func (s *filestorageSuite) TestPutHashed(c *gc.C) {
+	data := []byte("hello")
+	hash := fmt.Sprintf("%x", sha256.Sum256(data))
+	err := storage.PutHashed(s.writer, "test-write", hash, bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The same content is not read again...
+	err = storage.PutHashed(s.writer, "test-write", hash, unreadable{}, int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// ...but different content is.
+	err = storage.PutHashed(s.writer, "test-write", "0123", bytes.NewReader([]byte("bye")), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	b, err := ioutil.ReadFile(filepath.Join(s.dir, "test-write"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(b), gc.Equals, "bye")
+}
+
+func (s *filestorageSuite) newEncryptingStorage(c *gc.C) (storage.Storage, string) {
+	keyFile := filepath.Join(c.MkDir(), "key")
+	stor, err := filestorage.NewEncryptingFileStorageWriter(s.dir, keyFile)
+	c.Assert(err, jc.ErrorIsNil)
+	return stor, keyFile
+}
+
+func (s *filestorageSuite) assertEncryptedPutGet(c *gc.C, data []byte) {
+	stor, keyFile := s.newEncryptingStorage(c)
+	err := storage.PutWithOptions(stor, "test-write", bytes.NewReader(data), int64(len(data)), storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The file is encrypted at rest, and kept apart from
+	// unencrypted files...
+	b, err := ioutil.ReadFile(filepath.Join(s.dir, ".encrypted", "test-write"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filepath.Join(s.dir, "test-write"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	if len(data) > 0 {
+		c.Assert(bytes.Contains(b, data), jc.IsFalse)
+	}
+	info, err := os.Stat(keyFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	// ...and decrypted when read with the key.
+	reader, err := filestorage.NewDecryptingFileStorageReader(s.dir, keyFile)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, reader := range []storage.StorageReader{stor, reader} {
+		r, err := storage.Get(reader, "test-write")
+		c.Assert(err, jc.ErrorIsNil)
+		b, err = ioutil.ReadAll(r)
+		r.Close()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(b, jc.DeepEquals, data)
+	}
+}
+
+func (s *filestorageSuite) TestPutWithEncryption(c *gc.C) {
+	s.assertEncryptedPutGet(c, []byte{1, 2, 3, 4, 5})
+}
+
+func (s *filestorageSuite) TestPutWithEncryptionEmpty(c *gc.C) {
+	s.assertEncryptedPutGet(c, []byte{})
+}
+
+func (s *filestorageSuite) TestPutWithEncryptionManySegments(c *gc.C) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 20000)
+	s.assertEncryptedPutGet(c, data)
+}
+
+func (s *filestorageSuite) TestGetRangeEncrypted(c *gc.C) {
+	stor, _ := s.newEncryptingStorage(c)
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+	err := storage.PutWithOptions(stor, "test-write", bytes.NewReader(data), int64(len(data)), storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	r, err := storage.GetRange(stor, "test-write", 70000, 5)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(b), gc.Equals, "01234")
+}
+
+func (s *filestorageSuite) TestGetEncryptedTruncated(c *gc.C) {
+	stor, _ := s.newEncryptingStorage(c)
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+	err := storage.PutWithOptions(stor, "test-write", bytes.NewReader(data), int64(len(data)), storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// Cut the file at the end of its first segment.
+	path := filepath.Join(s.dir, ".encrypted", "test-write")
+	err = os.Truncate(path, encryptedHeaderSize+encryptedSegmentSize)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDecryptFails(c, stor, "decrypting file: .*")
+
+	// Cut the file inside its first segment.
+	err = os.Truncate(path, encryptedHeaderSize+100)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDecryptFails(c, stor, "decrypting file: .*")
+
+	// Cut the file after its header.
+	err = os.Truncate(path, encryptedHeaderSize)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDecryptFails(c, stor, "encrypted file truncated")
+}
+
+// The sizes of the header of an encrypted file, and of each of its
+// full segments.
+const (
+	encryptedHeaderSize  = 8 + 7
+	encryptedSegmentSize = 64*1024 + 16
+)
+
+func (s *filestorageSuite) assertDecryptFails(c *gc.C, stor storage.StorageReader, expect string) {
+	r, err := storage.Get(stor, "test-write")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	_, err = ioutil.ReadAll(r)
+	c.Assert(err, gc.ErrorMatches, expect)
+}
+
+func (s *filestorageSuite) TestGetEncryptedReordered(c *gc.C) {
+	stor, _ := s.newEncryptingStorage(c)
+	data := bytes.Repeat([]byte("0123456789"), 20000)
+	err := storage.PutWithOptions(stor, "test-write", bytes.NewReader(data), int64(len(data)), storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	path := filepath.Join(s.dir, ".encrypted", "test-write")
+	b, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	segment := func(n int) []byte {
+		start := encryptedHeaderSize + n*encryptedSegmentSize
+		return b[start : start+encryptedSegmentSize]
+	}
+
+	// Swap the first two segments.
+	var swapped []byte
+	swapped = append(swapped, b[:encryptedHeaderSize]...)
+	swapped = append(swapped, segment(1)...)
+	swapped = append(swapped, segment(0)...)
+	swapped = append(swapped, b[encryptedHeaderSize+2*encryptedSegmentSize:]...)
+	err = ioutil.WriteFile(path, swapped, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDecryptFails(c, stor, "decrypting file: .*")
+
+	// Drop the second segment.
+	var dropped []byte
+	dropped = append(dropped, b[:encryptedHeaderSize]...)
+	dropped = append(dropped, segment(0)...)
+	dropped = append(dropped, b[encryptedHeaderSize+2*encryptedSegmentSize:]...)
+	err = ioutil.WriteFile(path, dropped, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDecryptFails(c, stor, "decrypting file: .*")
+}
+
+func (s *filestorageSuite) TestGetPlaintextWithEncryptedMagic(c *gc.C) {
+	stor, _ := s.newEncryptingStorage(c)
+	data := []byte("JUJUENC1 is not encrypted")
+	err := stor.Put("test-write", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	r, err := storage.Get(stor, "test-write")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(b, jc.DeepEquals, data)
+}
+
+func (s *filestorageSuite) TestPutReplacesEncrypted(c *gc.C) {
+	stor, _ := s.newEncryptingStorage(c)
+	err := storage.PutWithOptions(stor, "a/test-write", bytes.NewReader([]byte("secret")), 6, storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	names, err := storage.List(stor, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"a/test-write"})
+
+	err = stor.Put("a/test-write", bytes.NewReader([]byte("plain")), 5)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filepath.Join(s.dir, ".encrypted", "a", "test-write"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	r, err := storage.Get(stor, "a/test-write")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(b), gc.Equals, "plain")
+
+	err = storage.PutWithOptions(stor, "a/test-write", bytes.NewReader([]byte("secret")), 6, storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = stor.Remove("a/test-write")
+	c.Assert(err, jc.ErrorIsNil)
+	names, err = storage.List(stor, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
+
+func (s *filestorageSuite) TestEncryptedURLNotSupported(c *gc.C) {
+	stor, _ := s.newEncryptingStorage(c)
+	err := storage.PutWithOptions(stor, "test-write", bytes.NewReader([]byte("x")), 1, storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stor.URL("test-write")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *filestorageSuite) TestEncryptionKeyInsideStorageDir(c *gc.C) {
+	_, err := filestorage.NewEncryptingFileStorageWriter(s.dir, filepath.Join(s.dir, "key"))
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *filestorageSuite) TestPutWithEncryptionNoKeyFile(c *gc.C) {
+	err := storage.PutWithOptions(s.writer, "test-write", bytes.NewReader([]byte("x")), 1, storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *filestorageSuite) TestPutWithKMSEncryptionNotSupported(c *gc.C) {
+	err := storage.PutWithOptions(s.writer, "test-write", bytes.NewReader(nil), 0, storage.PutOptions{
+		Encryption: storage.EncryptionSSEKMS,
+		KMSKeyID:   "key",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *filestorageSuite) TestRemove(c *gc.C) {
 	expectedpath, _ := s.createFile(c, "test-file")
 	_, file := filepath.Split(expectedpath)
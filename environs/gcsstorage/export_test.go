@@ -0,0 +1,24 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gcsstorage
+
+import (
+	"net/http"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+)
+
+var UploadChunkSize = &uploadChunkSize
+
+// NewWithClient returns a storage.Storage that sends its requests
+// with the given client, and signs URLs for the service account with
+// the given email address and PEM-encoded private key.
+func NewWithClient(settings config.GCSStorageSettings, client *http.Client, email string, privateKey []byte) (storage.Storage, error) {
+	signer, err := newURLSigner(email, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return newStorage(settings, client, signer)
+}
@@ -0,0 +1,358 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package gcsstorage provides an implementation of storage.Storage
+// backed by a Google Cloud Storage bucket, authenticating with a
+// service account.
+package gcsstorage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	goauth2 "golang.org/x/oauth2/google"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+)
+
+var logger = loggo.GetLogger("juju.environs.gcsstorage")
+
+const (
+	defaultEndpoint = "https://www.googleapis.com"
+
+	// signedURLHost is the host used for signed URLs, which are
+	// served by the XML API rather than the JSON API.
+	signedURLHost = "https://storage.googleapis.com"
+
+	storageScope = "https://www.googleapis.com/auth/devstorage.full_control"
+)
+
+// NewFromModelConfig returns a storage.Storage using the GCS storage
+// settings in the given model config. If GCS storage is not configured,
+// an error satisfying errors.IsNotFound is returned.
+func NewFromModelConfig(cfg *config.Config) (storage.Storage, error) {
+	settings, ok := cfg.GCSStorage()
+	if !ok {
+		return nil, errors.NotFoundf("GCS storage settings")
+	}
+	stor, err := New(settings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stor.(*gcsStorage).cfg = cfg
+	return stor, nil
+}
+
+// New returns a storage.Storage that keeps its files in the bucket
+// described by the given settings, authenticating with the service
+// account in settings.Credentials. The bucket is created when the
+// first file is put, if it does not already exist.
+func New(settings config.GCSStorageSettings) (storage.Storage, error) {
+	jwtConfig, err := goauth2.JWTConfigFromJSON([]byte(settings.Credentials), storageScope)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing service account credentials")
+	}
+	signer, err := newURLSigner(jwtConfig.Email, jwtConfig.PrivateKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ctx := context.WithValue(oauth2.NoContext, oauth2.HTTPClient, storage.HTTPClient)
+	return newStorage(settings, jwtConfig.Client(ctx), signer)
+}
+
+func newStorage(settings config.GCSStorageSettings, client *http.Client, signer *urlSigner) (storage.Storage, error) {
+	if settings.Bucket == "" {
+		return nil, errors.NotValidf("empty bucket name")
+	}
+	endpoint := settings.Endpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	return &gcsStorage{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		bucket:   settings.Bucket,
+		project:  settings.Project,
+		client:   client,
+		signer:   signer,
+	}, nil
+}
+
+// gcsStorage implements storage.Storage on a Cloud Storage bucket.
+type gcsStorage struct {
+	endpoint string
+	bucket   string
+	project  string
+	client   *http.Client
+	signer   *urlSigner
+
+	// cfg holds the model config the storage was created from,
+	// if any.
+	cfg *config.Config
+
+	mu         sync.Mutex
+	madeBucket bool
+}
+
+// objectURL returns the JSON API URL of the given object.
+func (s *gcsStorage) objectURL(name string) string {
+	return fmt.Sprintf(
+		"%s/storage/v1/b/%s/o/%s",
+		s.endpoint, escapeSegment(s.bucket), escapeSegment(name),
+	)
+}
+
+func (s *gcsStorage) do(method, u string, header http.Header, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := s.client.Do(req)
+	return resp, errors.Trace(err)
+}
+
+// makeBucket makes the bucket in which objects are stored. To avoid
+// two round trips on every PUT operation, we do this only once.
+func (s *gcsStorage) makeBucket() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.madeBucket {
+		return nil
+	}
+	body, err := json.Marshal(map[string]string{"name": s.bucket})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	u := fmt.Sprintf("%s/storage/v1/b?project=%s", s.endpoint, url.QueryEscape(s.project))
+	header := http.Header{"Content-Type": {"application/json"}}
+	resp, err := s.do("POST", u, header, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	// A conflict means that the bucket already exists.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		return errors.New(storage.HTTPResponseError(resp))
+	}
+	s.madeBucket = true
+	return nil
+}
+
+// Put is specified in the StorageWriter interface. Files are uploaded
+// with resumable uploads, so that an interrupted upload of a large file
+// continues from the last chunk received rather than starting again.
+func (s *gcsStorage) Put(name string, r io.Reader, length int64) error {
+	if err := s.makeBucket(); err != nil {
+		return errors.Annotatef(err, "cannot make GCS bucket %q", s.bucket)
+	}
+	if err := s.resumableUpload(name, r, length, ""); err != nil {
+		return errors.Annotatef(err, "cannot write file %q to GCS bucket %q", name, s.bucket)
+	}
+	return nil
+}
+
+// PutWithOptions is specified in the storage.OptionsWriter interface.
+// Cloud Storage always encrypts objects at rest, so EncryptionSSE
+// requires nothing more; with EncryptionSSEKMS, the object is
+// encrypted with the given Cloud KMS key.
+func (s *gcsStorage) PutWithOptions(name string, r io.Reader, length int64, opts storage.PutOptions) error {
+	if err := s.makeBucket(); err != nil {
+		return errors.Annotatef(err, "cannot make GCS bucket %q", s.bucket)
+	}
+	kmsKeyName := ""
+	if opts.Encryption == storage.EncryptionSSEKMS {
+		kmsKeyName = opts.KMSKeyID
+	}
+	if err := s.resumableUpload(name, r, length, kmsKeyName); err != nil {
+		return errors.Annotatef(err, "cannot write file %q to GCS bucket %q", name, s.bucket)
+	}
+	return nil
+}
+
+// Get is specified in the StorageReader interface.
+func (s *gcsStorage) Get(name string) (io.ReadCloser, error) {
+	resp, err := s.do("GET", s.objectURL(name)+"?alt=media", nil, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	defer resp.Body.Close()
+	return nil, errors.Errorf("cannot read file %q: %s", name, storage.HTTPResponseError(resp))
+}
+
+// GetRange is specified in the storage.RangeReader interface.
+func (s *gcsStorage) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	header := http.Header{"Range": {storage.HTTPRange(offset, length)}}
+	resp, err := s.do("GET", s.objectURL(name)+"?alt=media", header, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusOK:
+		// The range was ignored, so the whole file is returned.
+		return storage.LimitRange(resp.Body, offset, length)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The offset is at or beyond the end of the file.
+		resp.Body.Close()
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	defer resp.Body.Close()
+	return nil, errors.Errorf("cannot read file %q: %s", name, storage.HTTPResponseError(resp))
+}
+
+// List is specified in the StorageReader interface.
+func (s *gcsStorage) List(prefix string) ([]string, error) {
+	return storage.ListPages(s, prefix)
+}
+
+// ListPage is specified in the storage.PagedLister interface.
+func (s *gcsStorage) ListPage(prefix, token string, maxItems int) ([]string, string, error) {
+	query := url.Values{"fields": {"items/name,nextPageToken"}}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if token != "" {
+		query.Set("pageToken", token)
+	}
+	if maxItems > 0 {
+		query.Set("maxResults", fmt.Sprint(maxItems))
+	}
+	u := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.endpoint, escapeSegment(s.bucket), query.Encode())
+	resp, err := s.do("GET", u, nil, nil)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	// The bucket is only created when the first file is put,
+	// so a missing bucket is not an error.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.Errorf("cannot list files: %s", storage.HTTPResponseError(resp))
+	}
+	var result struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+		NextPageToken string `json:"nextPageToken"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", errors.Annotate(err, "decoding file list")
+	}
+	names := make([]string, len(result.Items))
+	for i, item := range result.Items {
+		names[i] = item.Name
+	}
+	return names, result.NextPageToken, nil
+}
+
+// URL is specified in the StorageReader interface. The URL is signed
+// with the service account's key, so the object need not be public.
+func (s *gcsStorage) URL(name string) (string, error) {
+	return s.SignedURL(name, storage.URLExpiry())
+}
+
+// SignedURL is specified in the storage.SignedURLReader interface.
+func (s *gcsStorage) SignedURL(name string, expires time.Time) (string, error) {
+	u, err := s.signer.signedURL(s.bucket, name, expires)
+	return u, errors.Trace(err)
+}
+
+// DefaultConsistencyStrategy is specified in the StorageReader interface.
+func (s *gcsStorage) DefaultConsistencyStrategy() utils.AttemptStrategy {
+	return storage.HTTPAttempt
+}
+
+// Consistency is specified in the storage.ConsistencyReporter interface.
+func (s *gcsStorage) Consistency() storage.Consistency {
+	return storage.StrongConsistency
+}
+
+// RetryStrategy is specified in the storage.OperationRetrier interface.
+// The retry budgets in the model config the storage was created from,
+// if any, override the defaults.
+func (s *gcsStorage) RetryStrategy(op config.StorageOperation) utils.AttemptStrategy {
+	return storage.RetryStrategy(s, op, s.cfg)
+}
+
+// ShouldRetry is specified in the StorageReader interface. Cloud
+// Storage is strongly consistent for reads after writes, so errors
+// are never retried.
+func (s *gcsStorage) ShouldRetry(err error) bool {
+	return false
+}
+
+// Remove is specified in the StorageWriter interface.
+func (s *gcsStorage) Remove(name string) error {
+	resp, err := s.do("DELETE", s.objectURL(name), nil, nil)
+	if err != nil {
+		return errors.Annotatef(err, "cannot remove file %q", name)
+	}
+	defer resp.Body.Close()
+	// If the object or bucket does not exist, then we don't care.
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return errors.Errorf("cannot remove file %q: %s", name, storage.HTTPResponseError(resp))
+	}
+	return nil
+}
+
+// RemoveAll is specified in the StorageWriter interface.
+func (s *gcsStorage) RemoveAll() error {
+	names, err := storage.List(s, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range names {
+		if err := s.Remove(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.madeBucket = false
+	u := fmt.Sprintf("%s/storage/v1/b/%s", s.endpoint, escapeSegment(s.bucket))
+	resp, err := s.do("DELETE", u, nil, nil)
+	if err != nil {
+		return errors.Annotatef(err, "cannot remove GCS bucket %q", s.bucket)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return errors.Errorf("cannot remove GCS bucket %q: %s", s.bucket, storage.HTTPResponseError(resp))
+	}
+	return nil
+}
+
+// escapeSegment escapes s for use as a single URL path segment,
+// including any slashes.
+func escapeSegment(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
@@ -0,0 +1,326 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gcsstorage_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/gcsstorage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+// fakeGCS is a minimal Cloud Storage JSON API server, supporting a
+// single bucket.
+type fakeGCS struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	bucket    map[string][]byte
+	uploads   map[string]*fakeUpload
+	chunkPuts int
+
+	// failChunk, if positive, is the number of the chunk PUT that
+	// fails after storing only half of its data.
+	failChunk int
+
+	// partialChunk, if positive, is the number of the chunk PUT
+	// that persists only half of its data, reporting that with an
+	// incomplete upload response.
+	partialChunk int
+}
+
+type fakeUpload struct {
+	name       string
+	length     int64
+	kmsKeyName string
+	data       []byte
+}
+
+func newFakeGCS() *fakeGCS {
+	f := &fakeGCS{uploads: make(map[string]*fakeUpload)}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+func (f *fakeGCS) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := req.URL.EscapedPath()
+	switch {
+	case req.Method == "POST" && path == "/storage/v1/b":
+		if f.bucket != nil {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.bucket = make(map[string][]byte)
+	case req.Method == "POST" && strings.HasPrefix(path, "/upload/"):
+		id := fmt.Sprint(len(f.uploads))
+		length, _ := strconv.ParseInt(req.Header.Get("X-Upload-Content-Length"), 10, 64)
+		f.uploads[id] = &fakeUpload{
+			name:       req.URL.Query().Get("name"),
+			length:     length,
+			kmsKeyName: req.URL.Query().Get("kmsKeyName"),
+		}
+		w.Header().Set("Location", f.URL+"/session/"+id)
+	case req.Method == "PUT" && strings.HasPrefix(path, "/session/"):
+		f.serveChunk(w, req, f.uploads[strings.TrimPrefix(path, "/session/")])
+	case f.bucket == nil:
+		w.WriteHeader(http.StatusNotFound)
+	case req.Method == "DELETE" && path == "/storage/v1/b/bucket":
+		f.bucket = nil
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == "GET" && path == "/storage/v1/b/bucket/o":
+		var result struct {
+			Items         []map[string]string `json:"items"`
+			NextPageToken string              `json:"nextPageToken,omitempty"`
+		}
+		var names []string
+		for name := range f.bucket {
+			if strings.HasPrefix(name, req.URL.Query().Get("prefix")) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		// Page tokens are indexes into the sorted names.
+		start, _ := strconv.Atoi(req.URL.Query().Get("pageToken"))
+		end := len(names)
+		if max, _ := strconv.Atoi(req.URL.Query().Get("maxResults")); max > 0 && start+max < end {
+			end = start + max
+			result.NextPageToken = fmt.Sprint(end)
+		}
+		for _, name := range names[start:end] {
+			result.Items = append(result.Items, map[string]string{"name": name})
+		}
+		json.NewEncoder(w).Encode(result)
+	case strings.HasPrefix(path, "/storage/v1/b/bucket/o/"):
+		name, _ := url.QueryUnescape(strings.TrimPrefix(path, "/storage/v1/b/bucket/o/"))
+		data, ok := f.bucket[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Method == "DELETE" {
+			delete(f.bucket, name)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write(data)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (f *fakeGCS) serveChunk(w http.ResponseWriter, req *http.Request, upload *fakeUpload) {
+	data, _ := ioutil.ReadAll(req.Body)
+	if len(data) > 0 {
+		f.chunkPuts++
+		var start int
+		fmt.Sscanf(req.Header.Get("Content-Range"), "bytes %d-", &start)
+		if start != len(upload.data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if f.chunkPuts == f.failChunk {
+			upload.data = append(upload.data, data[:len(data)/2]...)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if f.chunkPuts == f.partialChunk {
+			data = data[:len(data)/2]
+		}
+		upload.data = append(upload.data, data...)
+	}
+	if int64(len(upload.data)) == upload.length {
+		f.bucket[upload.name] = upload.data
+		return
+	}
+	if len(upload.data) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(upload.data)-1))
+	}
+	w.WriteHeader(308)
+}
+
+type gcsstorageSuite struct {
+	testing.BaseSuite
+	gcs     *fakeGCS
+	storage storage.Storage
+}
+
+var _ = gc.Suite(&gcsstorageSuite{})
+
+func privateKeyPEM(c *gc.C) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, jc.ErrorIsNil)
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
+
+func (s *gcsstorageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.gcs = newFakeGCS()
+	s.AddCleanup(func(*gc.C) { s.gcs.Close() })
+	var err error
+	s.storage, err = gcsstorage.NewWithClient(config.GCSStorageSettings{
+		Bucket:   "bucket",
+		Project:  "project",
+		Endpoint: s.gcs.URL,
+	}, http.DefaultClient, "juju@project.iam.gserviceaccount.com", privateKeyPEM(c))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *gcsstorageSuite) assertPutGet(c *gc.C, name string, data []byte) {
+	err := s.storage.Put(name, bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	r, err := s.storage.Get(name)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, data)
+}
+
+func (s *gcsstorageSuite) TestPutGet(c *gc.C) {
+	s.assertPutGet(c, "a/b c", []byte("hello"))
+}
+
+func (s *gcsstorageSuite) TestPutEmpty(c *gc.C) {
+	s.assertPutGet(c, "empty", []byte{})
+}
+
+func (s *gcsstorageSuite) TestPutChunked(c *gc.C) {
+	s.PatchValue(gcsstorage.UploadChunkSize, 4)
+	s.assertPutGet(c, "a", []byte("0123456789"))
+	c.Assert(s.gcs.chunkPuts, gc.Equals, 3)
+}
+
+func (s *gcsstorageSuite) TestPutResumesFailedChunk(c *gc.C) {
+	s.PatchValue(gcsstorage.UploadChunkSize, 4)
+	s.gcs.failChunk = 2
+	s.assertPutGet(c, "a", []byte("0123456789"))
+	// The second chunk is sent twice: once in full, failing after
+	// half was received, then the remaining half.
+	c.Assert(s.gcs.chunkPuts, gc.Equals, 4)
+}
+
+func (s *gcsstorageSuite) TestPutResendsPartiallyPersistedChunk(c *gc.C) {
+	s.PatchValue(gcsstorage.UploadChunkSize, 4)
+	s.gcs.partialChunk = 2
+	s.assertPutGet(c, "a", []byte("0123456789"))
+	// Only half of the second chunk is persisted, so the rest is
+	// sent again from the last persisted byte.
+	c.Assert(s.gcs.chunkPuts, gc.Equals, 4)
+}
+
+func (s *gcsstorageSuite) TestPutWithKMSEncryption(c *gc.C) {
+	err := storage.PutWithOptions(s.storage, "a", bytes.NewReader(nil), 0, storage.PutOptions{
+		Encryption: storage.EncryptionSSEKMS,
+		KMSKeyID:   "projects/p/locations/global/keyRings/r/cryptoKeys/k",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.gcs.uploads["0"].kmsKeyName, gc.Equals, "projects/p/locations/global/keyRings/r/cryptoKeys/k")
+}
+
+func (s *gcsstorageSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.storage.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *gcsstorageSuite) TestList(c *gc.C) {
+	names, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+
+	for _, name := range []string{"b/c", "a", "b/d", "c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	names, err = s.storage.List("b/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"b/c", "b/d"})
+}
+
+func (s *gcsstorageSuite) TestListPage(c *gc.C) {
+	for _, name := range []string{"a", "b", "c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	names, next, err := storage.ListPage(s.storage, "", "", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"a", "b"})
+	c.Assert(next, gc.Not(gc.Equals), "")
+
+	names, next, err = storage.ListPage(s.storage, "", next, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"c"})
+	c.Assert(next, gc.Equals, "")
+}
+
+func (s *gcsstorageSuite) TestRemoveAll(c *gc.C) {
+	for _, name := range []string{"a", "b/c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storage.RemoveAll()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.gcs.bucket, gc.IsNil)
+}
+
+func (s *gcsstorageSuite) TestURL(c *gc.C) {
+	u, err := s.storage.URL("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	parsed, err := url.Parse(u)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parsed.Host, gc.Equals, "storage.googleapis.com")
+	c.Assert(parsed.Path, gc.Equals, "/bucket/a/b")
+	query := parsed.Query()
+	c.Assert(query.Get("GoogleAccessId"), gc.Equals, "juju@project.iam.gserviceaccount.com")
+	c.Assert(query.Get("Expires"), gc.Not(gc.Equals), "")
+	c.Assert(query.Get("Signature"), gc.Not(gc.Equals), "")
+}
+
+func (s *gcsstorageSuite) TestNewInvalidCredentials(c *gc.C) {
+	_, err := gcsstorage.New(config.GCSStorageSettings{
+		Bucket:      "bucket",
+		Credentials: "not json",
+	})
+	c.Assert(err, gc.ErrorMatches, "parsing service account credentials: .*")
+}
+
+func (s *gcsstorageSuite) TestNewFromModelConfigNotConfigured(c *gc.C) {
+	_, err := gcsstorage.NewFromModelConfig(testing.ModelConfig(c))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gcsstorage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// urlSigner signs URLs granting time-limited read access to objects,
+// using a service account's private key.
+type urlSigner struct {
+	email string
+	key   *rsa.PrivateKey
+}
+
+// newURLSigner returns a urlSigner for the service account with the
+// given email address and PEM-encoded private key.
+func newURLSigner(email string, privateKey []byte) (*urlSigner, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("invalid service account private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing service account private key")
+		}
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key is not an RSA key")
+	}
+	return &urlSigner{email: email, key: rsaKey}, nil
+}
+
+// signedURL returns a V2 signed URL for reading the given object.
+//
+// See https://cloud.google.com/storage/docs/access-control/signed-urls-v2
+func (s *urlSigner) signedURL(bucket, name string, expires time.Time) (string, error) {
+	path := fmt.Sprintf("/%s/%s", bucket, (&url.URL{Path: name}).EscapedPath())
+	stringToSign := fmt.Sprintf("GET\n\n\n%d\n%s", expires.Unix(), path)
+	digest := sha256.Sum256([]byte(stringToSign))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Annotate(err, "signing URL")
+	}
+	query := url.Values{
+		"GoogleAccessId": {s.email},
+		"Expires":        {fmt.Sprint(expires.Unix())},
+		"Signature":      {base64.StdEncoding.EncodeToString(sig)},
+	}
+	return signedURLHost + path + "?" + query.Encode(), nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gcsstorage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/storage"
+)
+
+// statusResumeIncomplete is the status returned by Cloud Storage for
+// a resumable upload that has not yet received all of its data.
+const statusResumeIncomplete = 308
+
+// uploadChunkSize is the size of the chunks in which files are
+// uploaded. It must be a multiple of 256KiB.
+var uploadChunkSize = 8 * 1024 * 1024
+
+// maxChunkAttempts is the number of times a chunk is sent before
+// the upload is abandoned.
+const maxChunkAttempts = 3
+
+// resumableUpload uploads the contents of r to the named object. If
+// kmsKeyName is not empty, the object is encrypted with that key.
+//
+// See https://cloud.google.com/storage/docs/json_api/v1/how-tos/resumable-upload
+func (s *gcsStorage) resumableUpload(name string, r io.Reader, length int64, kmsKeyName string) error {
+	session, err := s.startUpload(name, length, kmsKeyName)
+	if err != nil {
+		return errors.Annotate(err, "starting upload")
+	}
+	buf := make([]byte, uploadChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return errors.Annotate(err, "reading file")
+		}
+		done, err := s.uploadChunk(session, buf[:n], offset, length)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		offset += int64(n)
+		if done {
+			return nil
+		}
+		if n == 0 {
+			return errors.Errorf("upload incomplete after %d of %d bytes", offset, length)
+		}
+	}
+}
+
+// startUpload initiates a resumable upload, and returns the URI of
+// the upload session.
+func (s *gcsStorage) startUpload(name string, length int64, kmsKeyName string) (string, error) {
+	query := url.Values{
+		"uploadType": {"resumable"},
+		"name":       {name},
+	}
+	if kmsKeyName != "" {
+		query.Set("kmsKeyName", kmsKeyName)
+	}
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", s.endpoint, escapeSegment(s.bucket), query.Encode())
+	header := http.Header{
+		"X-Upload-Content-Type":   {"application/octet-stream"},
+		"X-Upload-Content-Length": {strconv.FormatInt(length, 10)},
+	}
+	resp, err := s.do("POST", u, header, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New(storage.HTTPResponseError(resp))
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", errors.New("no upload session URI in response")
+	}
+	return session, nil
+}
+
+// uploadChunk sends the chunk starting at the given offset, resending
+// whatever part of it was not persisted. It returns true if the upload
+// is complete.
+func (s *gcsStorage) uploadChunk(session string, chunk []byte, offset, length int64) (bool, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxChunkAttempts; {
+		if lastErr != nil {
+			// Find out how much of the chunk was received
+			// before the failure, and send the rest.
+			received, err := s.uploadStatus(session, length)
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			if received < 0 {
+				return true, nil
+			}
+			chunk, offset = skipReceived(chunk, offset, received)
+		}
+		header := http.Header{"Content-Range": {contentRange(offset, int64(len(chunk)), length)}}
+		resp, err := s.do("PUT", session, header, bytes.NewReader(chunk))
+		if err != nil {
+			logger.Debugf("uploading chunk at offset %d: %v", offset, err)
+			lastErr = err
+			attempt++
+			continue
+		}
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+			return true, nil
+		case resp.StatusCode == statusResumeIncomplete:
+			// Cloud Storage may persist only part of the
+			// chunk, so send again from the last byte it
+			// has, if it is within the chunk.
+			received, err := persistedLength(resp)
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			end := offset + int64(len(chunk))
+			if received >= end {
+				return false, nil
+			}
+			if received <= offset {
+				// No progress was made, so count this
+				// as a failed attempt.
+				attempt++
+			}
+			lastErr = nil
+			chunk, offset = skipReceived(chunk, offset, received)
+			logger.Debugf("upload persisted to %d of chunk ending at %d", received, end)
+			continue
+		case resp.StatusCode >= 500:
+			lastErr = errors.Errorf("bad HTTP response: %v", resp.Status)
+			logger.Debugf("uploading chunk at offset %d: %v", offset, lastErr)
+			attempt++
+			continue
+		}
+		return false, errors.Errorf("uploading chunk: bad HTTP response: %v", resp.Status)
+	}
+	if lastErr == nil {
+		lastErr = errors.New("chunk not persisted")
+	}
+	return false, errors.Annotatef(lastErr, "uploading chunk after %d attempts", maxChunkAttempts)
+}
+
+// skipReceived returns the part of the chunk starting at offset that
+// follows the given number of bytes received, and its offset.
+func skipReceived(chunk []byte, offset, received int64) ([]byte, int64) {
+	if received <= offset {
+		return chunk, offset
+	}
+	skip := received - offset
+	if skip > int64(len(chunk)) {
+		skip = int64(len(chunk))
+	}
+	return chunk[skip:], offset + skip
+}
+
+// uploadStatus returns the number of bytes received by the upload
+// session, or -1 if the upload is complete.
+func (s *gcsStorage) uploadStatus(session string, length int64) (int64, error) {
+	header := http.Header{"Content-Range": {fmt.Sprintf("bytes */%d", length)}}
+	resp, err := s.do("PUT", session, header, nil)
+	if err != nil {
+		return 0, errors.Annotate(err, "querying upload status")
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return -1, nil
+	case statusResumeIncomplete:
+	default:
+		return 0, errors.Errorf("querying upload status: bad HTTP response: %v", resp.Status)
+	}
+	return persistedLength(resp)
+}
+
+// persistedLength returns the number of bytes persisted by an upload
+// session, from the Range header of an incomplete upload response.
+// The header, if present, is of the form "bytes=0-N".
+func persistedLength(resp *http.Response) (int64, error) {
+	rng := resp.Header.Get("Range")
+	if rng == "" {
+		return 0, nil
+	}
+	i := strings.LastIndex(rng, "-")
+	last, err := strconv.ParseInt(rng[i+1:], 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid upload status range %q", rng)
+	}
+	return last + 1, nil
+}
+
+// contentRange returns the Content-Range header value for a chunk of
+// n bytes starting at offset, in a file of the given total length.
+func contentRange(offset, n, length int64) string {
+	if n == 0 {
+		return fmt.Sprintf("bytes */%d", length)
+	}
+	return fmt.Sprintf("bytes %d-%d/%d", offset, offset+n-1, length)
+}
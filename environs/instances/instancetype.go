@@ -6,6 +6,7 @@ package instances
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/juju/juju/constraints"
 )
@@ -24,6 +25,8 @@ type InstanceType struct {
 	CpuPower   *uint64
 	Tags       []string
 	Deprecated bool
+	GPUs       uint64
+	GPUType    string
 }
 
 // InstanceTypesWithCostMetadata holds an array of InstanceType and metadata
@@ -51,12 +54,13 @@ func CpuPower(power uint64) *uint64 {
 func (itype InstanceType) match(cons constraints.Value) (InstanceType, bool) {
 	nothing := InstanceType{}
 	if cons.Arch != nil {
-		itype.Arches = filterArches(itype.Arches, []string{*cons.Arch})
+		itype.Arches = filterArches(itype.Arches, strings.Split(*cons.Arch, ","))
 	}
-	if itype.Deprecated && !cons.HasInstanceType() {
+	// Deprecated instance types are only used when named exactly.
+	if itype.Deprecated && (!cons.HasInstanceType() || cons.HasInstanceTypePattern()) {
 		return nothing, false
 	}
-	if cons.HasInstanceType() && itype.Name != *cons.InstanceType {
+	if !cons.MatchesInstanceType(itype.Name) {
 		return nothing, false
 	}
 	if len(itype.Arches) == 0 {
@@ -65,12 +69,18 @@ func (itype InstanceType) match(cons constraints.Value) (InstanceType, bool) {
 	if cons.CpuCores != nil && itype.CpuCores < *cons.CpuCores {
 		return nothing, false
 	}
+	if cons.MaxCpuCores != nil && itype.CpuCores > *cons.MaxCpuCores {
+		return nothing, false
+	}
 	if cons.CpuPower != nil && itype.CpuPower != nil && *itype.CpuPower < *cons.CpuPower {
 		return nothing, false
 	}
 	if cons.Mem != nil && itype.Mem < *cons.Mem {
 		return nothing, false
 	}
+	if cons.MaxMem != nil && itype.Mem > *cons.MaxMem {
+		return nothing, false
+	}
 	if cons.RootDisk != nil && itype.RootDisk > 0 && itype.RootDisk < *cons.RootDisk {
 		return nothing, false
 	}
@@ -80,6 +90,12 @@ func (itype InstanceType) match(cons constraints.Value) (InstanceType, bool) {
 	if cons.HasVirtType() && (itype.VirtType == nil || *itype.VirtType != *cons.VirtType) {
 		return nothing, false
 	}
+	if cons.HasGPUs() && itype.GPUs < *cons.GPUs {
+		return nothing, false
+	}
+	if cons.HasGPUType() && itype.GPUType != *cons.GPUType {
+		return nothing, false
+	}
 	return itype, true
 }
 
@@ -120,11 +136,12 @@ func MatchingInstanceTypes(allInstanceTypes []InstanceType, region string, cons
 	// Rules used to select instance types:
 	// - non memory constraints like cores etc are always honoured
 	// - if no mem constraint specified and instance-type not specified,
-	//   try opinionated default with enough mem to run a server.
+	//   or specified as a family, try opinionated default with enough
+	//   mem to run a server.
 	// - if no matches and no mem constraint specified, try again and
 	//   return any matching instance with the largest memory
 	origCons := cons
-	if !cons.HasInstanceType() && cons.Mem == nil {
+	if (!cons.HasInstanceType() || cons.HasInstanceTypePattern()) && cons.Mem == nil {
 		minMem := uint64(minMemoryHeuristic)
 		cons.Mem = &minMem
 	}
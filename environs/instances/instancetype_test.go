@@ -151,6 +151,11 @@ var getInstanceTypesTest = []struct {
 		cons:           "cpu-power=100 arch=armhf",
 		expectedItypes: []string{"m1.small", "m1.medium", "c1.medium"},
 		arches:         []string{"armhf"},
+	}, {
+		about:          "arches filtered by multiple arches constraint",
+		cons:           "cpu-power=100 arch=armhf,i386",
+		expectedItypes: []string{"m1.small", "m1.medium", "c1.medium"},
+		arches:         []string{"armhf"},
 	},
 	{
 		about: "enough memory for mongodb if mem not specified",
@@ -221,6 +226,33 @@ var getInstanceTypesTest = []struct {
 		cons:           "virt-type=hvm",
 		expectedItypes: []string{"cc1.4xlarge", "cc2.8xlarge"},
 		itypesToUse:    nil,
+	}, {
+		about: "gpus filtered by constraint",
+		cons:  "gpus=2",
+		itypesToUse: []InstanceType{
+			{Id: "1", Name: "it-1", Arches: []string{"amd64"}, Mem: 2048},
+			{Id: "2", Name: "it-2", Arches: []string{"amd64"}, Mem: 2048, GPUs: 1, GPUType: "nvidia-k80", Cost: 50},
+			{Id: "3", Name: "it-3", Arches: []string{"amd64"}, Mem: 2048, GPUs: 8, GPUType: "nvidia-k80", Cost: 100},
+			{Id: "4", Name: "it-4", Arches: []string{"amd64"}, Mem: 2048, GPUs: 4, GPUType: "nvidia-k520", Cost: 80},
+		},
+		expectedItypes: []string{"it-4", "it-3"},
+	}, {
+		about: "gpu-type filtered by constraint",
+		cons:  "gpu-type=nvidia-k80",
+		itypesToUse: []InstanceType{
+			{Id: "1", Name: "it-1", Arches: []string{"amd64"}, Mem: 2048},
+			{Id: "2", Name: "it-2", Arches: []string{"amd64"}, Mem: 2048, GPUs: 1, GPUType: "nvidia-k80", Cost: 50},
+			{Id: "4", Name: "it-4", Arches: []string{"amd64"}, Mem: 2048, GPUs: 4, GPUType: "nvidia-k520", Cost: 80},
+		},
+		expectedItypes: []string{"it-2"},
+	}, {
+		about:          "instance-type family",
+		cons:           "instance-type=m1.*",
+		expectedItypes: []string{"m1.small", "m1.medium", "m1.large", "m1.xlarge"},
+	}, {
+		about:          "instance-type family filtered by other constraints",
+		cons:           "instance-type=m1.* cores=2",
+		expectedItypes: []string{"m1.large", "m1.xlarge"},
 	}, {
 		about:          "deprecated image type requested by name",
 		cons:           "instance-type=dep.small",
@@ -269,6 +301,9 @@ func (s *instanceTypeSuite) TestGetMatchingInstanceTypesErrors(c *gc.C) {
 
 	_, err = MatchingInstanceTypes(instanceTypes, "test", constraints.MustParse("instance-type=dep.medium mem=8G"))
 	c.Check(err, gc.ErrorMatches, `no instance types in test matching constraints "instance-type=dep.medium mem=8192M"`)
+
+	_, err = MatchingInstanceTypes(instanceTypes, "test", constraints.MustParse("instance-type=dep.*"))
+	c.Check(err, gc.ErrorMatches, `no instance types in test matching constraints "instance-type=dep.\*"`)
 }
 
 var instanceTypeMatchTests = []struct {
@@ -280,17 +315,26 @@ var instanceTypeMatchTests = []struct {
 	{"", "m1.large", []string{"amd64"}},
 	{"cpu-power=100", "m1.small", []string{"amd64", "armhf"}},
 	{"arch=amd64", "m1.small", []string{"amd64"}},
+	{"arch=amd64,armhf", "m1.small", []string{"amd64", "armhf"}},
+	{"arch=i386,armhf", "m1.small", []string{"armhf"}},
 	{"cores=3", "m1.xlarge", []string{"amd64"}},
 	{"cpu-power=", "t1.micro", []string{"amd64", "armhf"}},
 	{"cpu-power=500", "c1.medium", []string{"amd64", "armhf"}},
 	{"cpu-power=2000", "c1.xlarge", []string{"amd64"}},
 	{"cpu-power=2001", "cc1.4xlarge", []string{"amd64"}},
 	{"mem=2G", "m1.medium", []string{"amd64", "armhf"}},
+	{"mem=2G..4G", "m1.medium", []string{"amd64", "armhf"}},
+	{"cores=1..2", "m1.large", []string{"amd64"}},
+	{"instance-type=m1.*", "m1.large", []string{"amd64"}},
+	{"instance-type=*.micro", "t1.micro", []string{"amd64", "armhf"}},
 
 	{"arch=i386", "m1.small", nil},
 	{"cpu-power=100", "t1.micro", nil},
 	{"cpu-power=9001", "cc2.8xlarge", nil},
 	{"mem=1G", "t1.micro", nil},
+	{"mem=2G..4G", "m1.large", nil},
+	{"cores=2..3", "m1.xlarge", nil},
+	{"instance-type=c1.*", "m1.large", nil},
 	{"arch=armhf", "c1.xlarge", nil},
 }
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/jsonschema"
 	"github.com/juju/version"
+	"golang.org/x/net/context"
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/cloud"
@@ -391,7 +392,55 @@ type InstanceTagger interface {
 // InstanceTypesFetcher is an interface that allows for instance information from
 // a provider to be obtained.
 type InstanceTypesFetcher interface {
-	InstanceTypes(constraints.Value) (instances.InstanceTypesWithCostMetadata, error)
+	// InstanceTypes returns the instance types matching the given
+	// constraints. When called on behalf of an API request, ctx
+	// carries the request's correlation id; see rpc.CorrelationId.
+	InstanceTypes(context.Context, constraints.Value) (instances.InstanceTypesWithCostMetadata, error)
+}
+
+// CostEstimator is an interface that may be implemented by Environs that
+// can estimate the cost of the instances they start.
+type CostEstimator interface {
+	// EstimateCost returns the estimated cost of the instance type
+	// that would be chosen to satisfy the given constraints. The
+	// context is as for InstanceTypesFetcher.InstanceTypes.
+	EstimateCost(context.Context, constraints.Value) (CostEstimate, error)
+}
+
+// CostEstimate holds the estimated cost of an instance.
+type CostEstimate struct {
+	// InstanceType is the name of the instance type on which the
+	// estimate is based.
+	InstanceType string
+
+	// HourlyCost is the estimated cost of running the instance for an
+	// hour, expressed in Currency.
+	HourlyCost float64
+
+	// Currency is the currency in which HourlyCost is expressed.
+	Currency string
+}
+
+// SpotInstancer is an interface that may be implemented by instance
+// brokers that can start instances using spot, or preemptible, capacity:
+// spare capacity that is cheaper than on-demand instances, but which the
+// provider may reclaim at any time. Such brokers honour
+// StartInstanceParams.Spot, and report the instances that the provider
+// has evicted with status.Evicted from Instance.Status, which the
+// instance poller records as the machine's instance status.
+type SpotInstancer interface {
+	// CheckSpotInstanceParams returns an error if instances cannot be
+	// started with the given spot parameters.
+	CheckSpotInstanceParams(SpotInstanceParams) error
+}
+
+// SeriesSupporter is an interface that may be implemented by Environs
+// that can only start instances running some operating system series.
+// Environs that do not implement it are assumed to support all series.
+type SeriesSupporter interface {
+	// SupportedSeries returns the series of the instances that the
+	// Environ can start.
+	SupportedSeries() ([]string, error)
 }
 
 // Upgrader is an interface that can be used for upgrading Environs. If an
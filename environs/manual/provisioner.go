@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/loggo"
 	"github.com/juju/utils/winrm"
+	"github.com/juju/version"
 
 	"github.com/juju/juju/apiserver/params"
 )
@@ -54,6 +55,10 @@ type ProvisionMachineArgs struct {
 	// WinRM contains keys and client interface api with the remote windows machine
 	WinRM WinRMArgs
 
+	// AgentVersion, if set, overrides the model's agent-version for
+	// the tools installed on the machine.
+	AgentVersion *version.Number
+
 	*params.UpdateBehavior
 }
 
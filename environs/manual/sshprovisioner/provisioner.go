@@ -52,6 +52,7 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 		MachineId: machineId,
 		Nonce:     machineParams.Nonce,
 		DisablePackageCommands: !args.EnableOSRefreshUpdate && !args.EnableOSUpgrade,
+		AgentVersion:           args.AgentVersion,
 	})
 
 	if err != nil {
@@ -47,6 +47,7 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 		MachineId: machineId,
 		Nonce:     machineParams.Nonce,
 		DisablePackageCommands: true,
+		AgentVersion:           args.AgentVersion,
 	})
 
 	if err != nil {
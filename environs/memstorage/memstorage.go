@@ -0,0 +1,289 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package memstorage provides a storage.Storage that holds its files
+// in memory. It is useful when embedding juju components in other
+// programs, and for local development providers, where nothing need
+// outlive the process; the contents may be saved and restored with a
+// snapshot where they should.
+package memstorage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs/storage"
+)
+
+// ErrFull is the cause of the error returned when a put would take a
+// storage over its size limits.
+var ErrFull = errors.New("storage full")
+
+// Config holds the limits of a Storage.
+type Config struct {
+	// MaxSize is the total number of bytes of file content the
+	// storage may hold. If zero, the size is unlimited.
+	MaxSize int64
+
+	// MaxFileSize is the number of bytes a single file may hold.
+	// If zero, files are limited only by MaxSize.
+	MaxFileSize int64
+}
+
+// Validate returns an error if the config is invalid.
+func (cfg Config) Validate() error {
+	if cfg.MaxSize < 0 {
+		return errors.NotValidf("negative MaxSize")
+	}
+	if cfg.MaxFileSize < 0 {
+		return errors.NotValidf("negative MaxFileSize")
+	}
+	return nil
+}
+
+type file struct {
+	data []byte
+	hash string
+}
+
+// Storage is a storage.Storage that holds its files in memory. It
+// is safe for concurrent use.
+type Storage struct {
+	config Config
+
+	mu    sync.Mutex
+	files map[string]file
+	size  int64
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+// New returns a new, empty Storage with the given limits.
+func New(config Config) (*Storage, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Storage{
+		config: config,
+		files:  make(map[string]file),
+	}, nil
+}
+
+// Get implements storage.StorageReader.Get.
+func (s *Storage) Get(name string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[name]
+	if !ok {
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	// File contents are never modified in place, so the reader
+	// may share them.
+	return ioutil.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+// GetRange implements storage.RangeReader.GetRange.
+func (s *Storage) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	r, err := s.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return storage.LimitRange(r, offset, length)
+}
+
+// List implements storage.StorageReader.List.
+func (s *Storage) List(prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.files {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListPage implements storage.PagedLister.ListPage. The token is the
+// last name on the previous page.
+func (s *Storage) ListPage(prefix, token string, maxItems int) ([]string, string, error) {
+	names, _ := s.List(prefix)
+	start := sort.SearchStrings(names, token)
+	if start < len(names) && names[start] == token {
+		start++
+	}
+	names = names[start:]
+	if maxItems <= 0 || len(names) <= maxItems {
+		return names, "", nil
+	}
+	names = names[:maxItems]
+	return names, names[maxItems-1], nil
+}
+
+// URL implements storage.StorageReader.URL. Files held in memory
+// cannot be reached by URL, so an error satisfying
+// errors.IsNotSupported is always returned.
+func (s *Storage) URL(name string) (string, error) {
+	return "", errors.NotSupportedf("URLs for in-memory storage")
+}
+
+// DefaultConsistencyStrategy implements storage.StorageReader.DefaultConsistencyStrategy.
+func (s *Storage) DefaultConsistencyStrategy() utils.AttemptStrategy {
+	// TODO(katco): 2016-08-09: lp:1611427
+	return utils.AttemptStrategy{}
+}
+
+// Consistency implements storage.ConsistencyReporter.Consistency.
+func (s *Storage) Consistency() storage.Consistency {
+	return storage.StrongConsistency
+}
+
+// ShouldRetry implements storage.StorageReader.ShouldRetry.
+func (s *Storage) ShouldRetry(err error) bool {
+	return false
+}
+
+// Put implements storage.StorageWriter.Put. If the file would take
+// the storage over its limits, an error with cause ErrFull is
+// returned and the storage is left unchanged.
+func (s *Storage) Put(name string, r io.Reader, length int64) error {
+	if length < 0 {
+		return errors.NotValidf("negative length %d", length)
+	}
+	if err := s.checkSize(name, length); err != nil {
+		return err
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return errors.Annotatef(err, "reading %q", name)
+	}
+	hash := sha256.Sum256(data)
+	return s.put(name, file{data, fmt.Sprintf("%x", hash)})
+}
+
+// PutHashed implements storage.HashedWriter.PutHashed.
+func (s *Storage) PutHashed(name, hash string, r io.Reader, length int64) error {
+	s.mu.Lock()
+	existing, ok := s.files[name]
+	s.mu.Unlock()
+	if ok && existing.hash == hash {
+		return nil
+	}
+	return s.Put(name, r, length)
+}
+
+// checkSize returns an error if storing length bytes under the given
+// name would exceed the storage's limits.
+func (s *Storage) checkSize(name string, length int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config.MaxFileSize > 0 && length > s.config.MaxFileSize {
+		return errors.Annotatef(ErrFull, "%q is %d bytes, exceeding the %d byte file limit", name, length, s.config.MaxFileSize)
+	}
+	size := s.size - int64(len(s.files[name].data)) + length
+	if s.config.MaxSize > 0 && size > s.config.MaxSize {
+		return errors.Annotatef(ErrFull, "putting %q would take storage to %d bytes, exceeding the %d byte limit", name, size, s.config.MaxSize)
+	}
+	return nil
+}
+
+// put stores the file, checking the limits again in case the storage
+// changed while it was read.
+func (s *Storage) put(name string, f file) error {
+	if err := s.checkSize(name, int64(len(f.data))); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size += int64(len(f.data)) - int64(len(s.files[name].data))
+	s.files[name] = f
+	return nil
+}
+
+// Remove implements storage.StorageWriter.Remove.
+func (s *Storage) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size -= int64(len(s.files[name].data))
+	delete(s.files, name)
+	return nil
+}
+
+// RemoveAll implements storage.StorageWriter.RemoveAll.
+func (s *Storage) RemoveAll() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = make(map[string]file)
+	s.size = 0
+	return nil
+}
+
+// Size returns the total number of bytes of file content held by the
+// storage.
+func (s *Storage) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// snapshotVersion is the version of the snapshot format written by
+// Snapshot.
+const snapshotVersion = 1
+
+type snapshot struct {
+	Version int               `json:"version"`
+	Files   map[string][]byte `json:"files"`
+}
+
+// Snapshot writes the storage's contents to w, in a form that may
+// be restored with Restore.
+func (s *Storage) Snapshot(w io.Writer) error {
+	s.mu.Lock()
+	snap := snapshot{
+		Version: snapshotVersion,
+		Files:   make(map[string][]byte, len(s.files)),
+	}
+	for name, f := range s.files {
+		snap.Files[name] = f.data
+	}
+	s.mu.Unlock()
+	if err := json.NewEncoder(w).Encode(snap); err != nil {
+		return errors.Annotate(err, "writing snapshot")
+	}
+	return nil
+}
+
+// Restore returns a new Storage with the given limits, holding the
+// contents of the snapshot read from r. An error with cause ErrFull
+// is returned if the contents exceed the limits.
+func Restore(r io.Reader, config Config) (*Storage, error) {
+	var snap snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, errors.Annotate(err, "reading snapshot")
+	}
+	if snap.Version != snapshotVersion {
+		return nil, errors.NotSupportedf("snapshot version %d", snap.Version)
+	}
+	s, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, data := range snap.Files {
+		hash := sha256.Sum256(data)
+		if err := s.put(name, file{data, fmt.Sprintf("%x", hash)}); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package memstorage_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/memstorage"
+	"github.com/juju/juju/environs/storage"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type memstorageSuite struct {
+	storage *memstorage.Storage
+}
+
+var _ = gc.Suite(&memstorageSuite{})
+
+func (s *memstorageSuite) SetUpTest(c *gc.C) {
+	var err error
+	s.storage, err = memstorage.New(memstorage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *memstorageSuite) put(c *gc.C, stor storage.StorageWriter, name, data string) {
+	err := stor.Put(name, strings.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *memstorageSuite) assertContents(c *gc.C, stor storage.StorageReader, name, data string) {
+	r, err := stor.Get(name)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(got), gc.Equals, data)
+}
+
+func (s *memstorageSuite) TestPutGet(c *gc.C) {
+	s.put(c, s.storage, "a/b", "hello")
+	s.assertContents(c, s.storage, "a/b", "hello")
+	s.put(c, s.storage, "a/b", "bye")
+	s.assertContents(c, s.storage, "a/b", "bye")
+	c.Assert(s.storage.Size(), gc.Equals, int64(3))
+}
+
+func (s *memstorageSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.storage.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *memstorageSuite) TestGetRange(c *gc.C) {
+	s.put(c, s.storage, "a", "0123456789")
+	r, err := storage.GetRange(s.storage, "a", 2, 3)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(got), gc.Equals, "234")
+}
+
+func (s *memstorageSuite) TestList(c *gc.C) {
+	for _, name := range []string{"b/c", "a", "b/d", "c"} {
+		s.put(c, s.storage, name, "")
+	}
+	names, err := s.storage.List("b/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"b/c", "b/d"})
+
+	names, next, err := storage.ListPage(s.storage, "", "", 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"a", "b/c", "b/d"})
+	names, next, err = storage.ListPage(s.storage, "", next, 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"c"})
+	c.Assert(next, gc.Equals, "")
+}
+
+func (s *memstorageSuite) TestURLNotSupported(c *gc.C) {
+	_, err := s.storage.URL("a")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *memstorageSuite) TestPutHashed(c *gc.C) {
+	s.put(c, s.storage, "a", "hello")
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte("hello")))
+	// The reader is not consumed when the content is unchanged.
+	r := strings.NewReader("hello")
+	err := storage.PutHashed(s.storage, "a", hash, r, 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Len(), gc.Equals, 5)
+}
+
+func (s *memstorageSuite) TestRemove(c *gc.C) {
+	s.put(c, s.storage, "a", "hello")
+	s.put(c, s.storage, "b", "bye")
+	err := s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.storage.Size(), gc.Equals, int64(3))
+	err = s.storage.RemoveAll()
+	c.Assert(err, jc.ErrorIsNil)
+	names, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+	c.Assert(s.storage.Size(), gc.Equals, int64(0))
+}
+
+func (s *memstorageSuite) TestMaxSize(c *gc.C) {
+	stor, err := memstorage.New(memstorage.Config{MaxSize: 8})
+	c.Assert(err, jc.ErrorIsNil)
+	s.put(c, stor, "a", "hello")
+	err = stor.Put("b", strings.NewReader("world"), 5)
+	c.Assert(errors.Cause(err), gc.Equals, memstorage.ErrFull)
+	c.Assert(err, gc.ErrorMatches, `putting "b" would take storage to 10 bytes, exceeding the 8 byte limit: storage full`)
+
+	// Replacing a file only counts the difference in size.
+	s.put(c, stor, "a", "goodbye!")
+	c.Assert(stor.Size(), gc.Equals, int64(8))
+}
+
+func (s *memstorageSuite) TestMaxFileSize(c *gc.C) {
+	stor, err := memstorage.New(memstorage.Config{MaxFileSize: 4})
+	c.Assert(err, jc.ErrorIsNil)
+	err = stor.Put("a", strings.NewReader("hello"), 5)
+	c.Assert(errors.Cause(err), gc.Equals, memstorage.ErrFull)
+	_, err = stor.Get("a")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *memstorageSuite) TestInvalidConfig(c *gc.C) {
+	_, err := memstorage.New(memstorage.Config{MaxSize: -1})
+	c.Assert(err, gc.ErrorMatches, "negative MaxSize not valid")
+}
+
+func (s *memstorageSuite) TestSnapshotRestore(c *gc.C) {
+	s.put(c, s.storage, "a", "hello")
+	s.put(c, s.storage, "b/c", "")
+	var buf bytes.Buffer
+	err := s.storage.Snapshot(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	restored, err := memstorage.Restore(&buf, memstorage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	names, err := restored.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"a", "b/c"})
+	s.assertContents(c, restored, "a", "hello")
+	s.assertContents(c, restored, "b/c", "")
+	c.Assert(restored.Size(), gc.Equals, int64(5))
+}
+
+func (s *memstorageSuite) TestRestoreExceedsLimit(c *gc.C) {
+	s.put(c, s.storage, "a", "hello")
+	var buf bytes.Buffer
+	err := s.storage.Snapshot(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = memstorage.Restore(&buf, memstorage.Config{MaxSize: 4})
+	c.Assert(errors.Cause(err), gc.Equals, memstorage.ErrFull)
+}
+
+func (s *memstorageSuite) TestRestoreUnknownVersion(c *gc.C) {
+	_, err := memstorage.Restore(strings.NewReader(`{"version": 2}`), memstorage.Config{})
+	c.Assert(err, gc.ErrorMatches, "snapshot version 2 not supported")
+}
@@ -0,0 +1,57 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelstorage
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/tools"
+)
+
+var logger = loggo.GetLogger("juju.environs.modelstorage")
+
+// DataSourceId identifies the image and tools datasources reading
+// from the model's object storage.
+const DataSourceId = "model object storage"
+
+// signedURLExpiry is the lifetime of the signed URLs through which
+// metadata and tools are fetched from the object storage.
+const signedURLExpiry = time.Hour
+
+func init() {
+	environs.RegisterImageDataSourceFunc(DataSourceId, imageDataSource)
+	tools.RegisterToolsDataSourceFunc(DataSourceId, toolsDataSource)
+}
+
+// imageDataSource is an environs.ImageDataSourceFunc returning a
+// DataSource for the image metadata in the model's object storage.
+func imageDataSource(env environs.Environ) (simplestreams.DataSource, error) {
+	return dataSource(env, storage.BaseImagesPath)
+}
+
+// toolsDataSource is a tools.ToolsDataSourceFunc returning a
+// DataSource for the tools in the model's object storage.
+func toolsDataSource(env environs.Environ) (simplestreams.DataSource, error) {
+	return dataSource(env, storage.BaseToolsPath)
+}
+
+func dataSource(env environs.Environ, basePath string) (simplestreams.DataSource, error) {
+	stor, err := Open(env.Config(), storage.NewLoggingObserver(logger))
+	if errors.IsNotFound(err) {
+		return nil, errors.NotSupportedf("model without object storage")
+	} else if err != nil {
+		// Other datasources may still be used.
+		logger.Warningf("cannot read metadata from object storage: %v", err)
+		return nil, errors.NewNotSupported(err, "")
+	}
+	return storage.NewSignedStorageSimpleStreamsDataSource(
+		DataSourceId, stor, basePath, simplestreams.CUSTOM_CLOUD_DATA, false, signedURLExpiry,
+	), nil
+}
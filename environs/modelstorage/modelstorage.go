@@ -0,0 +1,43 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelstorage opens the object storage configured for a
+// model, whichever of the supported object stores it is kept in.
+package modelstorage
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/azureblobstorage"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/gcsstorage"
+	"github.com/juju/juju/environs/s3storage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/swiftstorage"
+)
+
+// openers holds the functions used to open each supported object
+// store, in the order they are tried.
+var openers = []func(*config.Config) (storage.Storage, error){
+	s3storage.NewFromModelConfig,
+	swiftstorage.NewFromModelConfig,
+	azureblobstorage.NewFromModelConfig,
+	gcsstorage.NewFromModelConfig,
+}
+
+// Open returns the object storage configured in the given model
+// config. Every operation on the storage is reported to the given
+// observer. If no object storage is configured, an error satisfying
+// errors.IsNotFound is returned.
+func Open(cfg *config.Config, observer storage.Observer) (storage.Storage, error) {
+	for _, open := range openers {
+		stor, err := open(cfg)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotate(err, "opening object storage")
+		}
+		return storage.NewObservedStorage(stor, observer), nil
+	}
+	return nil, errors.NotFoundf("object storage settings")
+}
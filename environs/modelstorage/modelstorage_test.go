@@ -0,0 +1,119 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelstorage_test
+
+import (
+	"bytes"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/s3/s3test"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/modelstorage"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type modelstorageSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&modelstorageSuite{})
+
+func (s *modelstorageSuite) TestOpenNotConfigured(c *gc.C) {
+	_, err := modelstorage.Open(testing.ModelConfig(c), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+// s3Config returns a model config keeping files in an S3 test server.
+func (s *modelstorageSuite) s3Config(c *gc.C) *config.Config {
+	srv, err := s3test.NewServer(&s3test.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { srv.Quit() })
+	cfg, err := testing.ModelConfig(c).Apply(map[string]interface{}{
+		config.S3StorageEndpointKey:  srv.URL(),
+		config.S3StorageBucketKey:    "juju-test",
+		config.S3StorageAccessKeyKey: "access",
+		config.S3StorageSecretKeyKey: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func (s *modelstorageSuite) TestOpenObserved(c *gc.C) {
+	cfg := s.s3Config(c)
+
+	var ops []storage.Operation
+	observer := storage.ObserverFunc(func(op storage.Operation, name string, bytes int64, _ time.Duration, err error) {
+		c.Check(name, gc.Equals, "a/b")
+		c.Check(bytes, gc.Equals, int64(5))
+		c.Check(err, jc.ErrorIsNil)
+		ops = append(ops, op)
+	})
+	stor, err := modelstorage.Open(cfg, observer)
+	c.Assert(err, jc.ErrorIsNil)
+	err = stor.Put("a/b", bytes.NewReader([]byte("hello")), 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ops, jc.DeepEquals, []storage.Operation{storage.OpPut})
+}
+
+type mockEnviron struct {
+	environs.Environ
+	cfg *config.Config
+}
+
+func (e *mockEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+// findDataSource returns the object storage datasource in sources.
+func findDataSource(sources []simplestreams.DataSource) simplestreams.DataSource {
+	for _, source := range sources {
+		if source.Description() == modelstorage.DataSourceId {
+			return source
+		}
+	}
+	return nil
+}
+
+func (s *modelstorageSuite) TestDataSourcesNotConfigured(c *gc.C) {
+	env := &mockEnviron{cfg: testing.ModelConfig(c)}
+	sources, err := tools.GetMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(findDataSource(sources), gc.IsNil)
+	sources, err = environs.ImageMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(findDataSource(sources), gc.IsNil)
+}
+
+func (s *modelstorageSuite) TestDataSources(c *gc.C) {
+	env := &mockEnviron{cfg: s.s3Config(c)}
+	sources, err := tools.GetMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	source := findDataSource(sources)
+	c.Assert(source, gc.NotNil)
+	c.Assert(source.Priority(), gc.Equals, simplestreams.CUSTOM_CLOUD_DATA)
+	url, err := source.URL("streams/v1/index.json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, jc.Contains, "/tools/streams/v1/index.json")
+
+	sources, err = environs.ImageMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	source = findDataSource(sources)
+	c.Assert(source, gc.NotNil)
+	url, err = source.URL("streams/v1/index.json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, jc.Contains, "/images/streams/v1/index.json")
+}
@@ -0,0 +1,403 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package s3storage provides an implementation of storage.Storage
+// backed by any object store speaking the S3 API, such as AWS S3,
+// Ceph RGW or MinIO.
+package s3storage
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+)
+
+// defaultRegion is the region used when a custom endpoint is
+// configured without a region; most S3-compatible stores ignore it.
+const defaultRegion = "us-east-1"
+
+// maxListKeys is the default number of keys requested per page.
+const maxListKeys = 1000
+
+// httpClient is used to make requests that the S3 client does not
+// support, such as ranged reads.
+var httpClient = http.DefaultClient
+
+// maxSignedURLExpiry is the longest time for which a URL signed with
+// signature version 4 may be valid.
+const maxSignedURLExpiry = 7 * 24 * time.Hour
+
+// NewFromModelConfig returns a storage.Storage using the S3 storage
+// settings in the given model config. If S3 storage is not configured,
+// an error satisfying errors.IsNotFound is returned.
+func NewFromModelConfig(cfg *config.Config) (storage.Storage, error) {
+	settings, ok := cfg.S3Storage()
+	if !ok {
+		return nil, errors.NotFoundf("S3 storage settings")
+	}
+	stor, err := New(settings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stor.(*s3Storage).cfg = cfg
+	return stor, nil
+}
+
+// New returns a storage.Storage that keeps its files in the bucket
+// described by the given settings. The bucket is created when the
+// first file is put, if it does not already exist.
+func New(settings config.S3StorageSettings) (storage.Storage, error) {
+	if settings.Bucket == "" {
+		return nil, errors.NotValidf("empty bucket name")
+	}
+	region, err := s3Region(settings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	auth := aws.Auth{
+		AccessKey: settings.AccessKey,
+		SecretKey: settings.SecretKey,
+	}
+	bucket, err := s3.New(auth, region).Bucket(settings.Bucket)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting bucket %q", settings.Bucket)
+	}
+	return &s3Storage{bucket: bucket}, nil
+}
+
+// s3Region returns the region to connect to for the given settings.
+// Without an endpoint, the region must be a known AWS region.
+func s3Region(settings config.S3StorageSettings) (aws.Region, error) {
+	if settings.Endpoint == "" {
+		region, ok := aws.Regions[settings.Region]
+		if !ok {
+			return aws.Region{}, errors.NotValidf("S3 region %q", settings.Region)
+		}
+		return region, nil
+	}
+	name := settings.Region
+	if name == "" {
+		name = defaultRegion
+	}
+	return aws.Region{
+		Name:       name,
+		S3Endpoint: settings.Endpoint,
+		Sign:       aws.SignV4Factory(name, "s3"),
+	}, nil
+}
+
+// s3Storage implements storage.Storage on an S3 bucket.
+type s3Storage struct {
+	sync.Mutex
+	madeBucket bool
+	bucket     *s3.Bucket
+
+	// cfg holds the model config the storage was created from,
+	// if any.
+	cfg *config.Config
+}
+
+// makeBucket makes the bucket in which files are stored. To avoid
+// two round trips on every PUT operation, we do this only once.
+func (s *s3Storage) makeBucket() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.madeBucket {
+		return nil
+	}
+	// PutBucket succeeds if the bucket already exists and is
+	// owned by us.
+	if err := s.bucket.PutBucket(s3.Private); err != nil {
+		return errors.Trace(err)
+	}
+	s.madeBucket = true
+	return nil
+}
+
+// Put is specified in the StorageWriter interface.
+func (s *s3Storage) Put(name string, r io.Reader, length int64) error {
+	if err := s.makeBucket(); err != nil {
+		return errors.Annotatef(err, "cannot make S3 bucket %q", s.bucket.Name)
+	}
+	err := s.bucket.PutReader(name, r, length, "binary/octet-stream", s3.Private)
+	if err != nil {
+		return errors.Annotatef(err, "cannot write file %q to S3 bucket %q", name, s.bucket.Name)
+	}
+	return nil
+}
+
+// PutWithOptions is specified in the storage.OptionsWriter interface.
+// Encryption is requested with the x-amz-server-side-encryption
+// headers.
+func (s *s3Storage) PutWithOptions(name string, r io.Reader, length int64, opts storage.PutOptions) error {
+	if err := s.makeBucket(); err != nil {
+		return errors.Annotatef(err, "cannot make S3 bucket %q", s.bucket.Name)
+	}
+	headers := map[string][]string{
+		"Content-Type": {"binary/octet-stream"},
+	}
+	switch opts.Encryption {
+	case storage.EncryptionSSE:
+		headers["x-amz-server-side-encryption"] = []string{"AES256"}
+	case storage.EncryptionSSEKMS:
+		headers["x-amz-server-side-encryption"] = []string{"aws:kms"}
+		headers["x-amz-server-side-encryption-aws-kms-key-id"] = []string{opts.KMSKeyID}
+	}
+	err := s.bucket.PutReaderHeader(name, r, length, headers, s3.Private)
+	if err != nil {
+		return errors.Annotatef(err, "cannot write file %q to S3 bucket %q", name, s.bucket.Name)
+	}
+	return nil
+}
+
+// InitMultipart is specified in the storage.MultipartWriter interface.
+func (s *s3Storage) InitMultipart(name string) (storage.MultipartUpload, error) {
+	if err := s.makeBucket(); err != nil {
+		return nil, errors.Annotatef(err, "cannot make S3 bucket %q", s.bucket.Name)
+	}
+	multi, err := s.bucket.InitMulti(name, "binary/octet-stream", s3.Private)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &multipartUpload{multi: multi, parts: make(map[int]s3.Part)}, nil
+}
+
+// multipartUpload implements storage.MultipartUpload with an S3
+// multipart upload.
+type multipartUpload struct {
+	multi *s3.Multi
+	parts map[int]s3.Part
+}
+
+// PutPart is specified in the storage.MultipartUpload interface.
+func (u *multipartUpload) PutPart(n int, r io.ReadSeeker, length int64) error {
+	part, err := u.multi.PutPart(n, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	u.parts[n] = part
+	return nil
+}
+
+// Complete is specified in the storage.MultipartUpload interface.
+func (u *multipartUpload) Complete() error {
+	parts := make([]s3.Part, 0, len(u.parts))
+	for _, part := range u.parts {
+		parts = append(parts, part)
+	}
+	sort.Sort(partsByNumber(parts))
+	return errors.Trace(u.multi.Complete(parts))
+}
+
+// Abort is specified in the storage.MultipartUpload interface.
+func (u *multipartUpload) Abort() error {
+	return errors.Trace(u.multi.Abort())
+}
+
+type partsByNumber []s3.Part
+
+func (p partsByNumber) Len() int           { return len(p) }
+func (p partsByNumber) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p partsByNumber) Less(i, j int) bool { return p[i].N < p[j].N }
+
+// Get is specified in the StorageReader interface.
+func (s *s3Storage) Get(name string) (io.ReadCloser, error) {
+	r, err := s.bucket.GetReader(name)
+	if err != nil {
+		return nil, maybeNotFound(err)
+	}
+	return r, nil
+}
+
+// rangeURLExpiry is how long the signed URL used to read part of a
+// file is valid for.
+const rangeURLExpiry = 15 * time.Minute
+
+// GetRange is specified in the storage.RangeReader interface. The
+// file is read from a signed URL, which allows the Range header to
+// be set on the request.
+func (s *s3Storage) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	signedURL, err := s.bucket.SignedURL(name, rangeURLExpiry)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req, err := http.NewRequest("GET", signedURL, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Range", storage.HTTPRange(offset, length))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusOK:
+		// The range was ignored, so the whole file is returned.
+		return storage.LimitRange(resp.Body, offset, length)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The offset is at or beyond the end of the file.
+		resp.Body.Close()
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	resp.Body.Close()
+	return nil, errors.Errorf("cannot read file %q from S3 bucket %q: %s", name, s.bucket.Name, resp.Status)
+}
+
+// List is specified in the StorageReader interface.
+func (s *s3Storage) List(prefix string) ([]string, error) {
+	return storage.ListPages(s, prefix)
+}
+
+// ListPage is specified in the storage.PagedLister interface. The
+// continuation token is the last key on the previous page.
+func (s *s3Storage) ListPage(prefix, token string, maxItems int) ([]string, string, error) {
+	if maxItems <= 0 {
+		maxItems = maxListKeys
+	}
+	resp, err := s.bucket.List(prefix, "", token, maxItems)
+	if err != nil {
+		// The bucket is only created when the first file
+		// is put, so a missing bucket is not an error.
+		if isNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", errors.Trace(err)
+	}
+	names := make([]string, len(resp.Contents))
+	for i, key := range resp.Contents {
+		names[i] = key.Key
+	}
+	if !resp.IsTruncated || len(names) == 0 {
+		return names, "", nil
+	}
+	return names, names[len(names)-1], nil
+}
+
+// URL is specified in the StorageReader interface. The URL is valid
+// for the longest time S3 allows.
+func (s *s3Storage) URL(name string) (string, error) {
+	return s.bucket.SignedURL(name, maxSignedURLExpiry)
+}
+
+// SignedURL is specified in the storage.SignedURLReader interface.
+// Expiry times further away than S3 allows are brought forward.
+func (s *s3Storage) SignedURL(name string, expires time.Time) (string, error) {
+	expiry := expires.Sub(time.Now())
+	if expiry > maxSignedURLExpiry {
+		expiry = maxSignedURLExpiry
+	}
+	return s.bucket.SignedURL(name, expiry)
+}
+
+var storageAttempt = utils.AttemptStrategy{
+	Total: 5 * time.Second,
+	Delay: 200 * time.Millisecond,
+}
+
+// DefaultConsistencyStrategy is specified in the StorageReader interface.
+func (s *s3Storage) DefaultConsistencyStrategy() utils.AttemptStrategy {
+	return storageAttempt
+}
+
+// Consistency is specified in the storage.ConsistencyReporter interface.
+func (s *s3Storage) Consistency() storage.Consistency {
+	return storage.EventualConsistency
+}
+
+// RetryStrategy is specified in the storage.OperationRetrier interface.
+// The retry budgets in the model config the storage was created from,
+// if any, override the defaults.
+func (s *s3Storage) RetryStrategy(op config.StorageOperation) utils.AttemptStrategy {
+	return storage.RetryStrategy(s, op, s.cfg)
+}
+
+// ShouldRetry is specified in the StorageReader interface.
+// S3 is eventually consistent, so files may not be visible
+// immediately after they are put.
+func (s *s3Storage) ShouldRetry(err error) bool {
+	err = errors.Cause(err)
+	if err == nil {
+		return false
+	}
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		return true
+	}
+	if errors.IsNotFound(err) || isNotFound(err) {
+		return true
+	}
+	switch err := err.(type) {
+	case *s3.Error:
+		return err.Code == "InternalError"
+	case net.Error:
+		return true
+	}
+	return false
+}
+
+// Remove is specified in the StorageWriter interface.
+func (s *s3Storage) Remove(name string) error {
+	err := s.bucket.Del(name)
+	// If we can't delete the object because the bucket doesn't
+	// exist, then we don't care.
+	if err != nil && !isNotFound(err) {
+		return errors.Annotatef(err, "cannot remove file %q from S3 bucket %q", name, s.bucket.Name)
+	}
+	return nil
+}
+
+// RemoveAll is specified in the StorageWriter interface.
+func (s *s3Storage) RemoveAll() error {
+	names, err := storage.List(s, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range names {
+		if err := s.Remove(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	// Even if DelBucket fails, it won't harm if we try again - the
+	// operation might have succeeded even if we get an error.
+	s.madeBucket = false
+	if err := s.bucket.DelBucket(); err != nil && !isNotFound(err) {
+		return errors.Annotatef(err, "cannot remove S3 bucket %q", s.bucket.Name)
+	}
+	return nil
+}
+
+// maybeNotFound returns an error satisfying errors.IsNotFound if
+// the specified error is due to a file or bucket not being found.
+func maybeNotFound(err error) error {
+	if isNotFound(err) {
+		return errors.NewNotFound(err, "")
+	}
+	return errors.Trace(err)
+}
+
+func isNotFound(err error) bool {
+	if err, ok := errors.Cause(err).(*s3.Error); ok {
+		return err.StatusCode == 404
+	}
+	return false
+}
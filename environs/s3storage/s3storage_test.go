@@ -0,0 +1,164 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package s3storage_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/s3/s3test"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/s3storage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type s3storageSuite struct {
+	testing.BaseSuite
+	srv     *s3test.Server
+	storage storage.Storage
+}
+
+var _ = gc.Suite(&s3storageSuite{})
+
+func (s *s3storageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	srv, err := s3test.NewServer(&s3test.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.srv = srv
+	s.AddCleanup(func(*gc.C) { srv.Quit() })
+
+	s.storage, err = s3storage.New(config.S3StorageSettings{
+		Endpoint:  srv.URL(),
+		Bucket:    "juju-test",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *s3storageSuite) TestPutGet(c *gc.C) {
+	data := []byte("hello")
+	err := s.storage.Put("a/b", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	r, err := s.storage.Get("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, data)
+}
+
+func (s *s3storageSuite) TestGetRange(c *gc.C) {
+	data := []byte("0123456789")
+	err := s.storage.Put("a", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.storage, gc.Implements, new(storage.RangeReader))
+	r, err := storage.GetRange(s.storage, "a", 3, 4)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(got), gc.Equals, "3456")
+}
+
+func (s *s3storageSuite) TestGetNotFound(c *gc.C) {
+	err := s.storage.Put("a", bytes.NewReader(nil), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storage.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *s3storageSuite) TestListNoBucket(c *gc.C) {
+	names, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
+
+func (s *s3storageSuite) TestList(c *gc.C) {
+	for _, name := range []string{"b/c", "a", "b/d", "c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	names, err := s.storage.List("b/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"b/c", "b/d"})
+}
+
+func (s *s3storageSuite) TestRemove(c *gc.C) {
+	err := s.storage.Put("a", bytes.NewReader(nil), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storage.Get("a")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing a file that does not exist is not an error.
+	err = s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *s3storageSuite) TestRemoveAll(c *gc.C) {
+	for _, name := range []string{"a", "b/c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.storage.RemoveAll()
+	c.Assert(err, jc.ErrorIsNil)
+	names, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
+
+func (s *s3storageSuite) assertURLExpiry(c *gc.C, rawURL, expires string) {
+	u, err := url.Parse(rawURL)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Query().Get("X-Amz-Expires"), gc.Equals, expires)
+}
+
+func (s *s3storageSuite) TestURLExpiryCapped(c *gc.C) {
+	rawURL, err := s.storage.URL("a")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertURLExpiry(c, rawURL, "604800")
+}
+
+func (s *s3storageSuite) TestSignedURLExpiryCapped(c *gc.C) {
+	signer := s.storage.(storage.SignedURLReader)
+	rawURL, err := signer.SignedURL("a", time.Now().AddDate(1, 0, 0))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertURLExpiry(c, rawURL, "604800")
+}
+
+func (s *s3storageSuite) TestShouldRetry(c *gc.C) {
+	c.Assert(s.storage.ShouldRetry(errors.NotFoundf("file")), jc.IsTrue)
+	c.Assert(s.storage.ShouldRetry(errors.New("boom")), jc.IsFalse)
+	c.Assert(s.storage.ShouldRetry(nil), jc.IsFalse)
+}
+
+func (s *s3storageSuite) TestNewUnknownRegion(c *gc.C) {
+	_, err := s3storage.New(config.S3StorageSettings{
+		Region: "nowhere",
+		Bucket: "juju-test",
+	})
+	c.Assert(err, gc.ErrorMatches, `S3 region "nowhere" not valid`)
+}
+
+func (s *s3storageSuite) TestNewFromModelConfigNotConfigured(c *gc.C) {
+	cfg := testing.ModelConfig(c)
+	_, err := s3storage.NewFromModelConfig(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,30 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+)
+
+// SpotInstanceParamsFromConstraints returns the spot parameters with
+// which broker should start an instance satisfying cons, or nil if cons
+// does not request spot capacity. If spot capacity is requested but
+// broker cannot provide it, an error satisfying errors.IsNotSupported
+// is returned.
+func SpotInstanceParamsFromConstraints(broker InstanceBroker, cons constraints.Value) (*SpotInstanceParams, error) {
+	if !cons.HasSpotPrice() {
+		return nil, nil
+	}
+	spotInstancer, ok := broker.(SpotInstancer)
+	if !ok {
+		return nil, errors.NotSupportedf("spot instances")
+	}
+	spot := SpotInstanceParams{MaxPrice: *cons.SpotPrice}
+	if err := spotInstancer.CheckSpotInstanceParams(spot); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &spot, nil
+}
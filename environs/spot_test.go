@@ -0,0 +1,62 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type spotSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&spotSuite{})
+
+type spotBroker struct {
+	environs.InstanceBroker
+	checked []environs.SpotInstanceParams
+	err     error
+}
+
+func (b *spotBroker) CheckSpotInstanceParams(spot environs.SpotInstanceParams) error {
+	b.checked = append(b.checked, spot)
+	return b.err
+}
+
+func (s *spotSuite) TestNoSpotPrice(c *gc.C) {
+	broker := &spotBroker{}
+	for _, cons := range []string{"", "mem=4G", "spot-price=", "spot-price=0"} {
+		spot, err := environs.SpotInstanceParamsFromConstraints(broker, constraints.MustParse(cons))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(spot, gc.IsNil)
+	}
+	c.Assert(broker.checked, gc.HasLen, 0)
+}
+
+func (s *spotSuite) TestSpotPrice(c *gc.C) {
+	broker := &spotBroker{}
+	spot, err := environs.SpotInstanceParamsFromConstraints(broker, constraints.MustParse("spot-price=0.05"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spot, jc.DeepEquals, &environs.SpotInstanceParams{MaxPrice: 0.05})
+	c.Assert(broker.checked, jc.DeepEquals, []environs.SpotInstanceParams{{MaxPrice: 0.05}})
+}
+
+func (s *spotSuite) TestSpotPriceRejected(c *gc.C) {
+	broker := &spotBroker{err: errors.New("too cheap")}
+	_, err := environs.SpotInstanceParamsFromConstraints(broker, constraints.MustParse("spot-price=0.001"))
+	c.Assert(err, gc.ErrorMatches, "too cheap")
+}
+
+func (s *spotSuite) TestSpotNotSupported(c *gc.C) {
+	var broker struct{ environs.InstanceBroker }
+	_, err := environs.SpotInstanceParamsFromConstraints(broker, constraints.MustParse("spot-price=0.05"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "spot instances not supported")
+}
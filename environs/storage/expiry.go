@@ -0,0 +1,89 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ExpiryPrefix is the prefix of the names of the files recording when
+// other files expire. The expiry of a file is recorded in the file
+// named by appending its name to ExpiryPrefix.
+const ExpiryPrefix = ".expiry/"
+
+// SetExpiry marks the named file as temporary, to be removed by
+// RemoveExpired once the given time has passed. The file need not
+// exist yet; marking a file before it is put ensures that it is
+// removed even if the put is interrupted.
+func SetExpiry(stor StorageWriter, name string, expiry time.Time) error {
+	data := []byte(expiry.UTC().Format(time.RFC3339))
+	if err := stor.Put(path.Join(ExpiryPrefix, name), bytes.NewReader(data), int64(len(data))); err != nil {
+		return errors.Annotatef(err, "setting expiry of %q", name)
+	}
+	return nil
+}
+
+// ClearExpiry unmarks the named file, so that it is not removed by
+// RemoveExpired.
+func ClearExpiry(stor StorageWriter, name string) error {
+	if err := stor.Remove(path.Join(ExpiryPrefix, name)); err != nil {
+		return errors.Annotatef(err, "clearing expiry of %q", name)
+	}
+	return nil
+}
+
+// RemoveExpired removes the files in stor whose expiry is before now,
+// along with their expiry records, and returns their names. A file
+// whose expiry cannot be read is left in place, and the error is
+// returned after the other files have been considered.
+func RemoveExpired(stor Storage, now time.Time) ([]string, error) {
+	markers, err := List(stor, ExpiryPrefix)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing expiry records")
+	}
+	var removed []string
+	var firstErr error
+	for _, marker := range markers {
+		name := strings.TrimPrefix(marker, ExpiryPrefix)
+		expiry, err := readExpiry(stor, marker)
+		if err != nil {
+			logger.Warningf("cannot read expiry of %q: %v", name, err)
+			if firstErr == nil {
+				firstErr = errors.Annotatef(err, "reading expiry of %q", name)
+			}
+			continue
+		}
+		if !expiry.Before(now) {
+			continue
+		}
+		if err := stor.Remove(name); err != nil {
+			return removed, errors.Annotatef(err, "removing expired file %q", name)
+		}
+		if err := stor.Remove(marker); err != nil {
+			return removed, errors.Annotatef(err, "removing expiry record of %q", name)
+		}
+		removed = append(removed, name)
+	}
+	return removed, firstErr
+}
+
+func readExpiry(stor StorageReader, marker string) (time.Time, error) {
+	r, err := Get(stor, marker)
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	expiry, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	return expiry, errors.Trace(err)
+}
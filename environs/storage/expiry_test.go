@@ -0,0 +1,99 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+)
+
+type expirySuite struct {
+	stor storage.Storage
+}
+
+var _ = gc.Suite(&expirySuite{})
+
+var epoch = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (s *expirySuite) SetUpTest(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.stor = stor
+	for _, name := range []string{"scripts/a", "scripts/b", "tools/c"} {
+		err := s.stor.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *expirySuite) assertExists(c *gc.C, name string, exists bool) {
+	_, err := s.stor.Get(name)
+	if exists {
+		c.Assert(err, jc.ErrorIsNil)
+	} else {
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	}
+}
+
+func (s *expirySuite) TestRemoveExpired(c *gc.C) {
+	err := storage.SetExpiry(s.stor, "scripts/a", epoch)
+	c.Assert(err, jc.ErrorIsNil)
+	err = storage.SetExpiry(s.stor, "scripts/b", epoch.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+
+	removed, err := storage.RemoveExpired(s.stor, epoch.Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.DeepEquals, []string{"scripts/a"})
+	s.assertExists(c, "scripts/a", false)
+	s.assertExists(c, "scripts/b", true)
+	s.assertExists(c, "tools/c", true)
+
+	removed, err = storage.RemoveExpired(s.stor, epoch.Add(2*time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.DeepEquals, []string{"scripts/b"})
+	s.assertExists(c, "scripts/b", false)
+
+	markers, err := storage.List(s.stor, storage.ExpiryPrefix)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(markers, gc.HasLen, 0)
+}
+
+func (s *expirySuite) TestRemoveExpiredMissingFile(c *gc.C) {
+	// A file marked before it was put, which was never put.
+	err := storage.SetExpiry(s.stor, "partial", epoch)
+	c.Assert(err, jc.ErrorIsNil)
+	removed, err := storage.RemoveExpired(s.stor, epoch.Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.DeepEquals, []string{"partial"})
+}
+
+func (s *expirySuite) TestClearExpiry(c *gc.C) {
+	err := storage.SetExpiry(s.stor, "scripts/a", epoch)
+	c.Assert(err, jc.ErrorIsNil)
+	err = storage.ClearExpiry(s.stor, "scripts/a")
+	c.Assert(err, jc.ErrorIsNil)
+	removed, err := storage.RemoveExpired(s.stor, epoch.Add(time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.HasLen, 0)
+	s.assertExists(c, "scripts/a", true)
+}
+
+func (s *expirySuite) TestRemoveExpiredInvalidRecord(c *gc.C) {
+	err := storage.SetExpiry(s.stor, "scripts/a", epoch)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.stor.Put(storage.ExpiryPrefix+"scripts/b", strings.NewReader("soon"), 4)
+	c.Assert(err, jc.ErrorIsNil)
+
+	removed, err := storage.RemoveExpired(s.stor, epoch.Add(time.Minute))
+	c.Assert(err, gc.ErrorMatches, `reading expiry of "scripts/b": .*`)
+	c.Assert(removed, jc.DeepEquals, []string{"scripts/a"})
+	s.assertExists(c, "scripts/b", true)
+}
@@ -0,0 +1,9 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+var (
+	MultipartPartSize = &multipartPartSize
+	PartAttempt       = &partAttempt
+)
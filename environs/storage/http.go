@@ -0,0 +1,56 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/juju/utils"
+)
+
+// HTTPClient is the client used by storage backends that talk to an
+// object store over HTTP. The timeouts bound connecting and waiting
+// for a response, but not transferring the body, so that large files
+// can still be copied.
+var HTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).Dial,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+	},
+}
+
+// HTTPAttempt is the default consistency strategy of storage backends
+// that talk to an object store over HTTP.
+var HTTPAttempt = utils.AttemptStrategy{
+	Total: 10 * time.Second,
+	Delay: 200 * time.Millisecond,
+}
+
+// HTTPResponseError describes an unexpected HTTP response, including
+// its body if there is one.
+func HTTPResponseError(resp *http.Response) string {
+	msg := fmt.Sprintf("bad HTTP response: %v", resp.Status)
+	if body, err := ioutil.ReadAll(resp.Body); err == nil && len(body) > 0 {
+		msg += fmt.Sprintf(" (%s)", bytes.TrimSpace(body))
+	}
+	return msg
+}
+
+// URLExpiry returns the expiry time of URLs returned by
+// StorageReader.URL from backends that can only hand out signed URLs.
+func URLExpiry() time.Time {
+	// 10 years should be good enough.
+	// TODO(perrito666) 2016-05-02 lp:1558657
+	return time.Now().AddDate(10, 0, 0)
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/testing"
+)
+
+type httpSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&httpSuite{})
+
+func (s *httpSuite) TestHTTPResponseError(c *gc.C) {
+	resp := &http.Response{
+		Status: "403 Forbidden",
+		Body:   ioutil.NopCloser(strings.NewReader("  access denied\n")),
+	}
+	c.Assert(storage.HTTPResponseError(resp), gc.Equals, "bad HTTP response: 403 Forbidden (access denied)")
+}
+
+func (s *httpSuite) TestHTTPResponseErrorNoBody(c *gc.C) {
+	resp := &http.Response{
+		Status: "500 Internal Server Error",
+		Body:   ioutil.NopCloser(strings.NewReader("")),
+	}
+	c.Assert(storage.HTTPResponseError(resp), gc.Equals, "bad HTTP response: 500 Internal Server Error")
+}
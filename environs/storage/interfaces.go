@@ -5,8 +5,12 @@ package storage
 
 import (
 	"io"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs/config"
 )
 
 // A StorageReader can retrieve and list files from a storage provider.
@@ -43,6 +47,70 @@ type StorageReader interface {
 	ShouldRetry(error) bool
 }
 
+// PagedLister is implemented by a StorageReader that can list files
+// a page at a time. Use ListPage rather than calling it directly.
+type PagedLister interface {
+	// ListPage lists, in alphabetical order, at most maxItems names
+	// in the storage with the given prefix, following those listed
+	// on the page that returned the given continuation token. An
+	// empty token lists the first page, and if maxItems is not
+	// positive, the storage's default page size is used. The token
+	// for the next page is returned, and is empty after the last
+	// page.
+	ListPage(prefix, token string, maxItems int) (names []string, next string, err error)
+}
+
+// RangeReader is implemented by a StorageReader that can read part
+// of a file without reading what precedes it. Use GetRange rather
+// than calling it directly.
+type RangeReader interface {
+	// GetRange is like Get, but the returned ReadCloser reads at
+	// most length bytes of the file, starting at the given offset.
+	// If length is negative, it reads to the end of the file.
+	GetRange(name string, offset, length int64) (io.ReadCloser, error)
+}
+
+// Consistency describes when files written to a storage provider
+// become visible to readers.
+type Consistency int
+
+const (
+	// EventualConsistency means that files may not be visible to
+	// Get or List for some time after they are written.
+	EventualConsistency Consistency = iota
+
+	// StrongConsistency means that files are visible to Get and
+	// List as soon as they have been written.
+	StrongConsistency
+)
+
+// ConsistencyReporter is implemented by a StorageReader that reports
+// its consistency. Storage that does not is assumed to be eventually
+// consistent.
+type ConsistencyReporter interface {
+	Consistency() Consistency
+}
+
+// OperationRetrier is implemented by storage whose retry budget may
+// differ for each class of operation, such as storage configured with
+// the retry settings in model config. The package's helper functions
+// use it in preference to DefaultConsistencyStrategy.
+type OperationRetrier interface {
+	// RetryStrategy returns the attempt strategy to use for the
+	// given class of operation.
+	RetryStrategy(op config.StorageOperation) utils.AttemptStrategy
+}
+
+// SignedURLReader is implemented by a StorageReader that can generate
+// URLs granting time-limited access to its files.
+type SignedURLReader interface {
+	// SignedURL returns a URL through which the given file may be
+	// read, without further authorization, until the given time.
+	// If the storage cannot sign URLs with its current credentials,
+	// it returns an error satisfying errors.IsNotSupported.
+	SignedURL(name string, expires time.Time) (string, error)
+}
+
 // A StorageWriter adds and removes files in a storage provider.
 type StorageWriter interface {
 	// Put reads from r and writes to the given storage file.
@@ -63,6 +131,97 @@ type StorageWriter interface {
 	RemoveAll() error
 }
 
+// EncryptionMode describes how a file is encrypted at rest by
+// the storage provider.
+type EncryptionMode string
+
+const (
+	// EncryptionNone leaves encryption to the storage provider's
+	// defaults.
+	EncryptionNone EncryptionMode = ""
+
+	// EncryptionSSE requests server-side encryption with keys
+	// managed by the storage provider.
+	EncryptionSSE EncryptionMode = "sse"
+
+	// EncryptionSSEKMS requests server-side encryption with the
+	// key management service key identified by PutOptions.KMSKeyID.
+	EncryptionSSEKMS EncryptionMode = "sse-kms"
+)
+
+// PutOptions holds optional metadata for a file being put.
+type PutOptions struct {
+	// Encryption is the requested encryption mode for the file.
+	Encryption EncryptionMode
+
+	// KMSKeyID identifies the key management service key with
+	// which to encrypt the file, when Encryption is EncryptionSSEKMS.
+	KMSKeyID string
+}
+
+// Validate returns an error if the options are not valid.
+func (o PutOptions) Validate() error {
+	switch o.Encryption {
+	case EncryptionNone, EncryptionSSE:
+		if o.KMSKeyID != "" {
+			return errors.NotValidf("KMS key ID without %q encryption", EncryptionSSEKMS)
+		}
+	case EncryptionSSEKMS:
+		if o.KMSKeyID == "" {
+			return errors.NotValidf("%q encryption without KMS key ID", EncryptionSSEKMS)
+		}
+	default:
+		return errors.NotValidf("encryption mode %q", o.Encryption)
+	}
+	return nil
+}
+
+// OptionsWriter is implemented by a StorageWriter that accepts
+// per-put options. Use PutWithOptions rather than calling it
+// directly.
+type OptionsWriter interface {
+	// PutWithOptions is like Put, but applies the given options
+	// to the file. If the storage cannot honour the options, it
+	// should return an error satisfying errors.IsNotSupported.
+	PutWithOptions(name string, r io.Reader, length int64, opts PutOptions) error
+}
+
+// HashedWriter is implemented by a StorageWriter that can skip
+// uploading a file whose content it already holds. Use PutHashed
+// rather than calling it directly.
+type HashedWriter interface {
+	// PutHashed is like Put, but is also given the hex-encoded
+	// SHA256 hash of the file's content, which must not be empty.
+	// If the file already exists with that content, it is left as
+	// it is, and r is not read.
+	PutHashed(name, sha256 string, r io.Reader, length int64) error
+}
+
+// MultipartWriter is implemented by a StorageWriter that can upload
+// a file in parts, each of which may be retried independently. Use
+// PutMultipart rather than calling it directly.
+type MultipartWriter interface {
+	// InitMultipart starts a multipart upload of the given file.
+	// If multipart uploads are not possible, it returns an error
+	// satisfying errors.IsNotSupported.
+	InitMultipart(name string) (MultipartUpload, error)
+}
+
+// MultipartUpload is a multipart upload in progress.
+type MultipartUpload interface {
+	// PutPart uploads the part with the given number, counting
+	// from 1. If it fails, it may be called again with the same
+	// number to retry the part.
+	PutPart(n int, r io.ReadSeeker, length int64) error
+
+	// Complete assembles the uploaded parts, in order of their
+	// numbers, into the file.
+	Complete() error
+
+	// Abort abandons the upload, discarding any uploaded parts.
+	Abort() error
+}
+
 // Storage represents storage that can be both
 // read and written.
 type Storage interface {
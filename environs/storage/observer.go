@@ -0,0 +1,300 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// Operation identifies a storage operation reported to an Observer.
+type Operation string
+
+const (
+	OpGet       Operation = "get"
+	OpList      Operation = "list"
+	OpPut       Operation = "put"
+	OpRemove    Operation = "remove"
+	OpRemoveAll Operation = "remove-all"
+)
+
+// Observer is notified of the operations performed on a storage
+// returned by NewObservedStorage.
+type Observer interface {
+	// StorageOperation is called when an operation has finished.
+	// The name is that of the file operated on, or the prefix for
+	// OpList. The number of bytes is that read or written, and
+	// err is the error the operation failed with, if any. A get
+	// finishes when the reader returned for it is closed.
+	StorageOperation(op Operation, name string, bytes int64, duration time.Duration, err error)
+}
+
+// ObserverFunc is an Observer implemented by a function.
+type ObserverFunc func(op Operation, name string, bytes int64, duration time.Duration, err error)
+
+// StorageOperation is specified in the Observer interface.
+func (f ObserverFunc) StorageOperation(op Operation, name string, bytes int64, duration time.Duration, err error) {
+	f(op, name, bytes, duration, err)
+}
+
+// NewLoggingObserver returns an Observer that logs each operation to
+// the given logger at debug level.
+func NewLoggingObserver(logger loggo.Logger) Observer {
+	return ObserverFunc(func(op Operation, name string, bytes int64, duration time.Duration, err error) {
+		if err != nil {
+			logger.Debugf("storage %s %q failed after %v: %v", op, name, duration, err)
+			return
+		}
+		logger.Debugf("storage %s %q: %d bytes in %v", op, name, bytes, duration)
+	})
+}
+
+// NewObservedStorage returns a Storage that reports each operation on
+// stor to the given observer. The optional interfaces implemented by
+// stor, such as RangeReader and OptionsWriter, remain available
+// through the package's helper functions.
+func NewObservedStorage(stor Storage, observer Observer) Storage {
+	return &observedStorage{stor: stor, observer: observer}
+}
+
+type observedStorage struct {
+	stor     Storage
+	observer Observer
+}
+
+func (s *observedStorage) report(op Operation, name string, bytes int64, start time.Time, err error) {
+	s.observer.StorageOperation(op, name, bytes, time.Since(start), err)
+}
+
+// observedReader reports a get when it is closed.
+type observedReader struct {
+	io.ReadCloser
+	storage *observedStorage
+	name    string
+	start   time.Time
+
+	mu     sync.Mutex
+	bytes  int64
+	err    error
+	closed bool
+}
+
+func (r *observedReader) Read(buf []byte) (int, error) {
+	n, err := r.ReadCloser.Read(buf)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bytes += int64(n)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+func (r *observedReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		r.storage.report(OpGet, r.name, r.bytes, r.start, r.err)
+	}
+	return err
+}
+
+func (s *observedStorage) observeGet(name string, get func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	start := time.Now()
+	r, err := get()
+	if err != nil {
+		s.report(OpGet, name, 0, start, err)
+		return nil, err
+	}
+	return &observedReader{ReadCloser: r, storage: s, name: name, start: start}, nil
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	io.Reader
+	bytes int64
+}
+
+func (r *countingReader) Read(buf []byte) (int, error) {
+	n, err := r.Reader.Read(buf)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (s *observedStorage) observePut(name string, r io.Reader, put func(io.Reader) error) error {
+	start := time.Now()
+	counter := &countingReader{Reader: r}
+	err := put(counter)
+	s.report(OpPut, name, counter.bytes, start, err)
+	return err
+}
+
+// Get is specified in the StorageReader interface.
+func (s *observedStorage) Get(name string) (io.ReadCloser, error) {
+	return s.observeGet(name, func() (io.ReadCloser, error) {
+		return s.stor.Get(name)
+	})
+}
+
+// GetRange is specified in the RangeReader interface.
+func (s *observedStorage) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	return s.observeGet(name, func() (io.ReadCloser, error) {
+		return GetRangeWithRetry(s.stor, name, offset, length, utils.AttemptStrategy{})
+	})
+}
+
+// List is specified in the StorageReader interface.
+func (s *observedStorage) List(prefix string) ([]string, error) {
+	start := time.Now()
+	names, err := s.stor.List(prefix)
+	s.report(OpList, prefix, 0, start, err)
+	return names, err
+}
+
+// ListPage is specified in the PagedLister interface.
+func (s *observedStorage) ListPage(prefix, token string, maxItems int) ([]string, string, error) {
+	start := time.Now()
+	var names []string
+	var next string
+	var err error
+	if lister, ok := s.stor.(PagedLister); ok {
+		names, next, err = lister.ListPage(prefix, token, maxItems)
+	} else {
+		names, next, err = listPage(s.stor, prefix, token, maxItems)
+	}
+	s.report(OpList, prefix, 0, start, err)
+	return names, next, err
+}
+
+// URL is specified in the StorageReader interface.
+func (s *observedStorage) URL(name string) (string, error) {
+	return s.stor.URL(name)
+}
+
+// SignedURL is specified in the SignedURLReader interface.
+func (s *observedStorage) SignedURL(name string, expires time.Time) (string, error) {
+	return SignedURL(s.stor, name, expires)
+}
+
+// DefaultConsistencyStrategy is specified in the StorageReader interface.
+func (s *observedStorage) DefaultConsistencyStrategy() utils.AttemptStrategy {
+	return s.stor.DefaultConsistencyStrategy()
+}
+
+// RetryStrategy is specified in the OperationRetrier interface.
+func (s *observedStorage) RetryStrategy(op config.StorageOperation) utils.AttemptStrategy {
+	if op == config.StoragePut {
+		if retrier, ok := s.stor.(OperationRetrier); ok {
+			return retrier.RetryStrategy(op)
+		}
+		return partAttempt
+	}
+	return operationStrategy(s.stor, op)
+}
+
+// ShouldRetry is specified in the StorageReader interface.
+func (s *observedStorage) ShouldRetry(err error) bool {
+	return s.stor.ShouldRetry(err)
+}
+
+// Consistency is specified in the ConsistencyReporter interface.
+func (s *observedStorage) Consistency() Consistency {
+	if reporter, ok := s.stor.(ConsistencyReporter); ok {
+		return reporter.Consistency()
+	}
+	return EventualConsistency
+}
+
+// Put is specified in the StorageWriter interface.
+func (s *observedStorage) Put(name string, r io.Reader, length int64) error {
+	return s.observePut(name, r, func(r io.Reader) error {
+		return s.stor.Put(name, r, length)
+	})
+}
+
+// PutWithOptions is specified in the OptionsWriter interface.
+func (s *observedStorage) PutWithOptions(name string, r io.Reader, length int64, opts PutOptions) error {
+	return s.observePut(name, r, func(r io.Reader) error {
+		return PutWithOptions(s.stor, name, r, length, opts)
+	})
+}
+
+// PutHashed is specified in the HashedWriter interface.
+func (s *observedStorage) PutHashed(name, sha256 string, r io.Reader, length int64) error {
+	writer, ok := s.stor.(HashedWriter)
+	if !ok {
+		return PutMultipart(s, name, r, length)
+	}
+	return s.observePut(name, r, func(r io.Reader) error {
+		return writer.PutHashed(name, sha256, r, length)
+	})
+}
+
+// InitMultipart is specified in the MultipartWriter interface. The
+// whole upload is reported as a single put when it completes.
+func (s *observedStorage) InitMultipart(name string) (MultipartUpload, error) {
+	writer, ok := s.stor.(MultipartWriter)
+	if !ok {
+		return nil, errors.NotSupportedf("multipart uploads")
+	}
+	start := time.Now()
+	upload, err := writer.InitMultipart(name)
+	if err != nil {
+		s.report(OpPut, name, 0, start, err)
+		return nil, err
+	}
+	return &observedUpload{MultipartUpload: upload, storage: s, name: name, start: start}, nil
+}
+
+type observedUpload struct {
+	MultipartUpload
+	storage *observedStorage
+	name    string
+	start   time.Time
+	bytes   int64
+}
+
+func (u *observedUpload) PutPart(n int, r io.ReadSeeker, length int64) error {
+	err := u.MultipartUpload.PutPart(n, r, length)
+	if err == nil {
+		u.bytes += length
+	}
+	return err
+}
+
+func (u *observedUpload) Complete() error {
+	err := u.MultipartUpload.Complete()
+	u.storage.report(OpPut, u.name, u.bytes, u.start, err)
+	return err
+}
+
+func (u *observedUpload) Abort() error {
+	u.storage.report(OpPut, u.name, u.bytes, u.start, errors.New("upload aborted"))
+	return u.MultipartUpload.Abort()
+}
+
+// Remove is specified in the StorageWriter interface.
+func (s *observedStorage) Remove(name string) error {
+	start := time.Now()
+	err := s.stor.Remove(name)
+	s.report(OpRemove, name, 0, start, err)
+	return err
+}
+
+// RemoveAll is specified in the StorageWriter interface.
+func (s *observedStorage) RemoveAll() error {
+	start := time.Now()
+	err := s.stor.RemoveAll()
+	s.report(OpRemoveAll, "", 0, start, err)
+	return err
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+)
+
+type observerSuite struct {
+	ops  []observedOp
+	stor storage.Storage
+}
+
+var _ = gc.Suite(&observerSuite{})
+
+type observedOp struct {
+	op    storage.Operation
+	name  string
+	bytes int64
+	err   bool
+}
+
+func (s *observerSuite) SetUpTest(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.ops = nil
+	s.stor = storage.NewObservedStorage(stor, storage.ObserverFunc(
+		func(op storage.Operation, name string, bytes int64, duration time.Duration, err error) {
+			c.Check(duration >= 0, jc.IsTrue)
+			s.ops = append(s.ops, observedOp{op, name, bytes, err != nil})
+		},
+	))
+}
+
+func (s *observerSuite) TestOperations(c *gc.C) {
+	err := s.stor.Put("a/b", bytes.NewReader([]byte("hello")), 5)
+	c.Assert(err, jc.ErrorIsNil)
+	r, err := s.stor.Get("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.ops, gc.HasLen, 1)
+	r.Close()
+	_, err = s.stor.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.stor.List("a/")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.stor.Remove("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.ops, jc.DeepEquals, []observedOp{
+		{storage.OpPut, "a/b", 5, false},
+		{storage.OpGet, "a/b", 5, false},
+		{storage.OpGet, "missing", 0, true},
+		{storage.OpList, "a/", 0, false},
+		{storage.OpRemove, "a/b", 0, false},
+	})
+}
+
+func (s *observerSuite) TestHelpersUseUnderlyingStorage(c *gc.C) {
+	err := storage.PutWithOptions(s.stor, "a", bytes.NewReader([]byte("hello")), 5, storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	r, err := storage.GetRange(s.stor, "a", 1, 3)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(r)
+	r.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "ell")
+
+	c.Assert(s.ops, jc.DeepEquals, []observedOp{
+		{storage.OpPut, "a", 5, false},
+		{storage.OpGet, "a", 3, false},
+	})
+}
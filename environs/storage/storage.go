@@ -4,15 +4,24 @@
 package storage
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"path"
+	"sort"
+	"time"
 
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/utils"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/simplestreams"
 )
 
+var logger = loggo.GetLogger("juju.environs.storage")
+
 // RemoveAll is a default implementation for StorageWriter.RemoveAll.
 // Providers may have more efficient implementations, or better error handling,
 // or safeguards against races with other users of the same storage medium.
@@ -33,9 +42,154 @@ func RemoveAll(stor Storage) error {
 	return err
 }
 
+// PutWithOptions puts the file with the given options, if stor
+// supports them. If it does not, and any options are specified, an
+// error satisfying errors.IsNotSupported is returned.
+func PutWithOptions(stor StorageWriter, name string, r io.Reader, length int64, opts PutOptions) error {
+	if err := opts.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if writer, ok := stor.(OptionsWriter); ok {
+		return writer.PutWithOptions(name, r, length, opts)
+	}
+	if opts != (PutOptions{}) {
+		return errors.NotSupportedf("put options")
+	}
+	return stor.Put(name, r, length)
+}
+
+// PutHashed puts the file, given the hex-encoded SHA256 hash of its
+// content. If stor implements HashedWriter, and the file already
+// exists with that content, it is not uploaded again. Otherwise, or
+// if the hash is empty, the file is put with PutMultipart.
+func PutHashed(stor StorageWriter, name, sha256 string, r io.Reader, length int64) error {
+	if writer, ok := stor.(HashedWriter); ok && sha256 != "" {
+		return writer.PutHashed(name, sha256, r, length)
+	}
+	return PutMultipart(stor, name, r, length)
+}
+
+// retryDelay is the delay between attempts made with a retry budget
+// from model config.
+const retryDelay = 200 * time.Millisecond
+
+// RetryStrategy returns the attempt strategy for the given class of
+// operation on stor. The retry budget in cfg is used if one is set;
+// otherwise reads of strongly consistent storage are attempted once,
+// and other operations use stor's defaults. It may be used to
+// implement OperationRetrier; cfg may be nil.
+func RetryStrategy(stor StorageReader, op config.StorageOperation, cfg *config.Config) utils.AttemptStrategy {
+	if cfg != nil {
+		if settings, ok := cfg.StorageRetry(op); ok {
+			return utils.AttemptStrategy{
+				Total: settings.Timeout,
+				Delay: retryDelay,
+				Min:   settings.Attempts,
+			}
+		}
+	}
+	if op == config.StoragePut {
+		return partAttempt
+	}
+	if reporter, ok := stor.(ConsistencyReporter); ok && reporter.Consistency() == StrongConsistency {
+		return utils.AttemptStrategy{}
+	}
+	// TODO(katco): 2016-08-09: lp:1611427
+	return stor.DefaultConsistencyStrategy()
+}
+
+// operationStrategy returns the attempt strategy for reads of the
+// given class from stor.
+func operationStrategy(stor StorageReader, op config.StorageOperation) utils.AttemptStrategy {
+	if retrier, ok := stor.(OperationRetrier); ok {
+		return retrier.RetryStrategy(op)
+	}
+	return stor.DefaultConsistencyStrategy()
+}
+
+// multipartPartSize is the size of each part uploaded by PutMultipart.
+// It must be at least 5MiB, the minimum part size accepted by S3.
+var multipartPartSize int64 = 16 * 1024 * 1024
+
+// partAttempt is the default strategy used to retry a failed part
+// upload.
+var partAttempt = utils.AttemptStrategy{
+	Total: 30 * time.Second,
+	Delay: time.Second,
+	Min:   3,
+}
+
+// PutMultipart puts the file, uploading it in parts if stor supports
+// it and the file is larger than a single part. A part that fails to
+// upload is retried without restarting the whole upload. If stor does
+// not support multipart uploads, the file is put in one request.
+func PutMultipart(stor StorageWriter, name string, r io.Reader, length int64) error {
+	attempt := partAttempt
+	if retrier, ok := stor.(OperationRetrier); ok {
+		attempt = retrier.RetryStrategy(config.StoragePut)
+	}
+	return PutMultipartWithRetry(stor, name, r, length, attempt)
+}
+
+// PutMultipartWithRetry is like PutMultipart, but retries failed parts
+// using the specified attempt strategy.
+func PutMultipartWithRetry(stor StorageWriter, name string, r io.Reader, length int64, attempt utils.AttemptStrategy) error {
+	writer, ok := stor.(MultipartWriter)
+	if !ok || length <= multipartPartSize {
+		return stor.Put(name, r, length)
+	}
+	upload, err := writer.InitMultipart(name)
+	if errors.IsNotSupported(err) {
+		return stor.Put(name, r, length)
+	} else if err != nil {
+		return errors.Annotatef(err, "starting multipart upload of %q", name)
+	}
+	if err := putParts(upload, r, length, attempt); err != nil {
+		if abortErr := upload.Abort(); abortErr != nil {
+			logger.Warningf("cannot abort multipart upload of %q: %v", name, abortErr)
+		}
+		return errors.Annotatef(err, "uploading %q", name)
+	}
+	if err := upload.Complete(); err != nil {
+		return errors.Annotatef(err, "completing multipart upload of %q", name)
+	}
+	return nil
+}
+
+func putParts(upload MultipartUpload, r io.Reader, length int64, attempt utils.AttemptStrategy) error {
+	buf := make([]byte, multipartPartSize)
+	for n := 1; length > 0; n++ {
+		size := multipartPartSize
+		if length < size {
+			size = length
+		}
+		if _, err := io.ReadFull(r, buf[:size]); err != nil {
+			return errors.Annotatef(err, "reading part %d", n)
+		}
+		if err := putPart(upload, n, bytes.NewReader(buf[:size]), size, attempt); err != nil {
+			return errors.Annotatef(err, "uploading part %d", n)
+		}
+		length -= size
+	}
+	return nil
+}
+
+func putPart(upload MultipartUpload, n int, part io.ReadSeeker, size int64, attempt utils.AttemptStrategy) (err error) {
+	for a := attempt.Start(); a.Next(); {
+		if _, err = part.Seek(0, 0); err != nil {
+			return errors.Trace(err)
+		}
+		if err = upload.PutPart(n, part, size); err == nil {
+			return nil
+		}
+		logger.Debugf("part %d failed, retrying: %v", n, err)
+	}
+	return errors.Trace(err)
+}
+
 // Get gets the named file from stor using the stor's default consistency strategy.
 func Get(stor StorageReader, name string) (io.ReadCloser, error) {
-	return GetWithRetry(stor, name, stor.DefaultConsistencyStrategy())
+	return GetWithRetry(stor, name, operationStrategy(stor, config.StorageGet))
 }
 
 // GetWithRetry gets the named file from stor using the specified attempt strategy.
@@ -51,9 +205,146 @@ func GetWithRetry(stor StorageReader, name string, attempt utils.AttemptStrategy
 	return r, err
 }
 
+// GetRange gets at most length bytes of the named file from stor,
+// starting at the given offset, using the stor's default consistency
+// strategy. If length is negative, the file is read to the end. The
+// file is streamed rather than read into memory, so an interrupted
+// read can be resumed from the offset of the last byte received.
+func GetRange(stor StorageReader, name string, offset, length int64) (io.ReadCloser, error) {
+	return GetRangeWithRetry(stor, name, offset, length, operationStrategy(stor, config.StorageGet))
+}
+
+// GetRangeWithRetry is like GetRange, but uses the specified attempt
+// strategy. If stor does not implement RangeReader, the whole file is
+// requested, and the bytes before the offset are discarded.
+//
+// TODO(katco): 2016-08-09: lp:1611427
+func GetRangeWithRetry(stor StorageReader, name string, offset, length int64, attempt utils.AttemptStrategy) (r io.ReadCloser, err error) {
+	if offset < 0 {
+		return nil, errors.NotValidf("negative offset %d", offset)
+	}
+	rangeReader, ok := stor.(RangeReader)
+	// An empty range cannot be expressed as an HTTP range, so it
+	// is read like any other file that is read from the start.
+	if offset == 0 && length <= 0 || !ok {
+		r, err = GetWithRetry(stor, name, attempt)
+		if err != nil {
+			return nil, err
+		}
+		return LimitRange(r, offset, length)
+	}
+	for a := attempt.Start(); a.Next(); {
+		r, err = rangeReader.GetRange(name, offset, length)
+		if err == nil || !stor.ShouldRetry(err) {
+			break
+		}
+	}
+	return r, err
+}
+
+// maxResumes is the number of times a read started by GetResuming is
+// resumed after failing part way through.
+const maxResumes = 5
+
+// GetResuming gets the named file from stor, using the stor's default
+// consistency strategy. If reading the file fails part way through
+// with an error that stor says should be retried, the remainder of
+// the file is requested with GetRange, starting from the last byte
+// received, rather than reading the whole file again.
+func GetResuming(stor StorageReader, name string) (io.ReadCloser, error) {
+	return GetResumingWithRetry(stor, name, operationStrategy(stor, config.StorageGet))
+}
+
+// GetResumingWithRetry is like GetResuming, but uses the specified
+// attempt strategy for each request.
+func GetResumingWithRetry(stor StorageReader, name string, attempt utils.AttemptStrategy) (io.ReadCloser, error) {
+	r, err := GetWithRetry(stor, name, attempt)
+	if err != nil {
+		return nil, err
+	}
+	return &resumingReader{
+		stor:    stor,
+		name:    name,
+		attempt: attempt,
+		r:       r,
+	}, nil
+}
+
+// resumingReader reads a file from storage, resuming the read from
+// the current offset if it fails with a retryable error.
+type resumingReader struct {
+	stor    StorageReader
+	name    string
+	attempt utils.AttemptStrategy
+	r       io.ReadCloser
+	offset  int64
+	resumes int
+}
+
+// Read is part of the io.Reader interface.
+func (r *resumingReader) Read(buf []byte) (int, error) {
+	for {
+		n, err := r.r.Read(buf)
+		r.offset += int64(n)
+		if err == nil || err == io.EOF || !r.stor.ShouldRetry(err) || r.resumes >= maxResumes {
+			return n, err
+		}
+		if n > 0 {
+			// Return what was read; the error will
+			// recur on the next read.
+			return n, nil
+		}
+		r.resumes++
+		logger.Debugf("reading %q failed at offset %d, resuming: %v", r.name, r.offset, err)
+		resumed, rangeErr := GetRangeWithRetry(r.stor, r.name, r.offset, -1, r.attempt)
+		if rangeErr != nil {
+			return 0, errors.Annotatef(rangeErr, "resuming read of %q after %v", r.name, err)
+		}
+		r.r.Close()
+		r.r = resumed
+	}
+}
+
+// Close is part of the io.Closer interface.
+func (r *resumingReader) Close() error {
+	return r.r.Close()
+}
+
+// LimitRange returns a ReadCloser that reads at most length bytes of
+// r, after discarding the given number of bytes from its start. If
+// length is negative, r is read to the end. Closing the returned
+// ReadCloser closes r.
+func LimitRange(r io.ReadCloser, offset, length int64) (io.ReadCloser, error) {
+	if offset > 0 {
+		if _, err := io.CopyN(ioutil.Discard, r, offset); err != nil && err != io.EOF {
+			r.Close()
+			return nil, errors.Trace(err)
+		}
+	}
+	if length < 0 {
+		return r, nil
+	}
+	return limitReadCloser{io.LimitReader(r, length), r}, nil
+}
+
+type limitReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// HTTPRange returns the value of an HTTP Range header requesting at
+// most length bytes starting at the given offset, or to the end of
+// the resource if length is negative.
+func HTTPRange(offset, length int64) string {
+	if length < 0 {
+		return fmt.Sprintf("bytes=%d-", offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
 // List lists the files matching prefix from stor using the stor's default consistency strategy.
 func List(stor StorageReader, prefix string) ([]string, error) {
-	return ListWithRetry(stor, prefix, stor.DefaultConsistencyStrategy())
+	return ListWithRetry(stor, prefix, operationStrategy(stor, config.StorageList))
 }
 
 // ListWithRetry lists the files matching prefix from stor using the specified attempt strategy.
@@ -69,12 +360,87 @@ func ListWithRetry(stor StorageReader, prefix string, attempt utils.AttemptStrat
 	return list, err
 }
 
+// ListPage lists a page of at most maxItems files matching prefix
+// from stor, following the page that returned the given continuation
+// token, using the stor's default consistency strategy. It returns the
+// names and the token for the next page, which is empty after the last
+// page. If stor does not implement PagedLister, all the matching files
+// are listed and the page taken from them.
+func ListPage(stor StorageReader, prefix, token string, maxItems int) (names []string, next string, err error) {
+	lister, ok := stor.(PagedLister)
+	// TODO(katco): 2016-08-09: lp:1611427
+	for a := operationStrategy(stor, config.StorageList).Start(); a.Next(); {
+		if ok {
+			names, next, err = lister.ListPage(prefix, token, maxItems)
+		} else {
+			names, next, err = listPage(stor, prefix, token, maxItems)
+		}
+		if err == nil || !stor.ShouldRetry(err) {
+			break
+		}
+	}
+	return names, next, err
+}
+
+// listPage takes a page from all the files matching prefix. The
+// continuation token is the last name on the previous page.
+func listPage(stor StorageReader, prefix, token string, maxItems int) ([]string, string, error) {
+	names, err := stor.List(prefix)
+	if err != nil {
+		return nil, "", err
+	}
+	if token != "" {
+		names = names[sort.SearchStrings(names, token):]
+		if len(names) > 0 && names[0] == token {
+			names = names[1:]
+		}
+	}
+	if maxItems <= 0 || len(names) <= maxItems {
+		return names, "", nil
+	}
+	names = names[:maxItems]
+	return names, names[maxItems-1], nil
+}
+
+// ListPages lists all the files matching prefix from lister, a page at
+// a time. It may be used to implement StorageReader.List.
+func ListPages(lister PagedLister, prefix string) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		page, next, err := lister.ListPage(prefix, token, 0)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, page...)
+		if next == "" {
+			return names, nil
+		}
+		token = next
+	}
+}
+
 // BaseToolsPath is the container where tools tarballs and metadata are found.
 var BaseToolsPath = "tools"
 
 // BaseImagesPath is the container where images metadata is found.
 var BaseImagesPath = "images"
 
+// SignedURL returns a URL through which the named file may be read
+// until the given time, if stor supports signed URLs. If it does not,
+// or cannot sign URLs with its current credentials, the file's
+// ordinary URL is returned.
+func SignedURL(stor StorageReader, name string, expires time.Time) (string, error) {
+	if signer, ok := stor.(SignedURLReader); ok {
+		u, err := signer.SignedURL(name, expires)
+		if !errors.IsNotSupported(err) {
+			return u, errors.Trace(err)
+		}
+		logger.Debugf("cannot sign URL for %q, using unsigned URL: %v", name, err)
+	}
+	return stor.URL(name)
+}
+
 // A storageSimpleStreamsDataSource retrieves data from a StorageReader.
 type storageSimpleStreamsDataSource struct {
 	description   string
@@ -83,6 +449,10 @@ type storageSimpleStreamsDataSource struct {
 	allowRetry    bool
 	priority      int
 	requireSigned bool
+
+	// urlExpiry, if non-zero, is the lifetime of the signed URLs
+	// returned by the datasource.
+	urlExpiry time.Duration
 }
 
 // TestingGetAllowRetry is used in tests which need to see if allowRetry has been
@@ -96,7 +466,39 @@ func TestingGetAllowRetry(s simplestreams.DataSource) (bool, ok bool) {
 
 // NewStorageSimpleStreamsDataSource returns a new datasource reading from the specified storage.
 func NewStorageSimpleStreamsDataSource(description string, storage StorageReader, basePath string, priority int, requireSigned bool) simplestreams.DataSource {
-	return &storageSimpleStreamsDataSource{description, basePath, storage, false, priority, requireSigned}
+	return &storageSimpleStreamsDataSource{
+		description:   description,
+		basePath:      basePath,
+		storage:       storage,
+		priority:      priority,
+		requireSigned: requireSigned,
+	}
+}
+
+// NewSignedStorageSimpleStreamsDataSource returns a new datasource
+// reading from the specified storage, whose URLs are signed to grant
+// access to the data for the given duration. This allows agents to
+// fetch data directly from the storage rather than through the
+// controller. Where the storage cannot sign URLs, its ordinary URLs
+// are used.
+func NewSignedStorageSimpleStreamsDataSource(
+	description string, storage StorageReader, basePath string, priority int, requireSigned bool, urlExpiry time.Duration,
+) simplestreams.DataSource {
+	return &storageSimpleStreamsDataSource{
+		description:   description,
+		basePath:      basePath,
+		storage:       storage,
+		priority:      priority,
+		requireSigned: requireSigned,
+		urlExpiry:     urlExpiry,
+	}
+}
+
+func (s *storageSimpleStreamsDataSource) storageURL(relpath string) (string, error) {
+	if s.urlExpiry == 0 {
+		return s.storage.URL(relpath)
+	}
+	return SignedURL(s.storage, relpath, time.Now().Add(s.urlExpiry))
 }
 
 func (s *storageSimpleStreamsDataSource) relpath(storagePath string) string {
@@ -116,16 +518,16 @@ func (s *storageSimpleStreamsDataSource) Description() string {
 func (s *storageSimpleStreamsDataSource) Fetch(path string) (io.ReadCloser, string, error) {
 	relpath := s.relpath(path)
 	dataURL := relpath
-	fullURL, err := s.storage.URL(relpath)
+	fullURL, err := s.storageURL(relpath)
 	if err == nil {
 		dataURL = fullURL
 	}
 	// TODO(katco): 2016-08-09: lp:1611427
 	var attempt utils.AttemptStrategy
 	if s.allowRetry {
-		attempt = s.storage.DefaultConsistencyStrategy()
+		attempt = operationStrategy(s.storage, config.StorageGet)
 	}
-	rc, err := GetWithRetry(s.storage, relpath, attempt)
+	rc, err := GetResumingWithRetry(s.storage, relpath, attempt)
 	if err != nil {
 		return nil, dataURL, err
 	}
@@ -134,7 +536,7 @@ func (s *storageSimpleStreamsDataSource) Fetch(path string) (io.ReadCloser, stri
 
 // URL is defined in simplestreams.DataSource.
 func (s *storageSimpleStreamsDataSource) URL(path string) (string, error) {
-	return s.storage.URL(s.relpath(path))
+	return s.storageURL(s.relpath(path))
 }
 
 // PublicSigningKey is defined in simplestreams.DataSource.
@@ -8,12 +8,16 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
 	stdtesting "testing"
+	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/storage"
@@ -108,6 +112,60 @@ func (s *datasourceSuite) TestURLWithBasePath(c *gc.C) {
 	c.Assert(url, gc.Equals, expectedURL)
 }
 
+// signingStorage is a storage that signs URLs, if it has a key.
+type signingStorage struct {
+	storage.Storage
+	key     string
+	expires time.Time
+}
+
+func (s *signingStorage) SignedURL(name string, expires time.Time) (string, error) {
+	if s.key == "" {
+		return "", errors.NotSupportedf("signed URLs")
+	}
+	s.expires = expires
+	u, err := s.URL(name)
+	return u + "?key=" + s.key, err
+}
+
+func (s *datasourceSuite) TestSignedURL(c *gc.C) {
+	stor := &signingStorage{Storage: s.stor, key: "secret"}
+	before := time.Now()
+	ds := storage.NewSignedStorageSimpleStreamsDataSource("test datasource", stor, "base", simplestreams.DEFAULT_CLOUD_DATA, false, time.Hour)
+	url, err := ds.URL("bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, gc.Equals, s.baseURL+"/base/bar?key=secret")
+	c.Assert(stor.expires.Before(before.Add(time.Hour)), jc.IsFalse)
+	c.Assert(stor.expires.After(time.Now().Add(time.Hour)), jc.IsFalse)
+}
+
+func (s *datasourceSuite) TestFetchSignedURL(c *gc.C) {
+	sampleData := "hello world"
+	s.stor.Put("foo/data.txt", bytes.NewReader([]byte(sampleData)), int64(len(sampleData)))
+	stor := &signingStorage{Storage: s.stor, key: "secret"}
+	ds := storage.NewSignedStorageSimpleStreamsDataSource("test datasource", stor, "", simplestreams.DEFAULT_CLOUD_DATA, false, time.Hour)
+	rc, url, err := ds.Fetch("foo/data.txt")
+	c.Assert(err, jc.ErrorIsNil)
+	defer rc.Close()
+	c.Assert(url, gc.Equals, s.baseURL+"/foo/data.txt?key=secret")
+}
+
+func (s *datasourceSuite) TestSignedURLNotSupported(c *gc.C) {
+	// Without a key, the storage's ordinary URL is used.
+	stor := &signingStorage{Storage: s.stor}
+	ds := storage.NewSignedStorageSimpleStreamsDataSource("test datasource", stor, "", simplestreams.DEFAULT_CLOUD_DATA, false, time.Hour)
+	url, err := ds.URL("bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, gc.Equals, s.baseURL+"/bar")
+
+	// Nor is it signed by a datasource without an expiry.
+	stor.key = "secret"
+	ds = storage.NewStorageSimpleStreamsDataSource("test datasource", stor, "", simplestreams.DEFAULT_CLOUD_DATA, false)
+	url, err = ds.URL("bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, gc.Equals, s.baseURL+"/bar")
+}
+
 var _ = gc.Suite(&storageSuite{})
 
 type storageSuite struct{}
@@ -167,6 +225,123 @@ func (s *storageSuite) TestGetNoRetryAllowed(c *gc.C) {
 	c.Assert(stor.invokeCount, gc.Equals, 1)
 }
 
+func (s *storageSuite) TestGetRangeNotSupported(c *gc.C) {
+	stor := &fakeStorage{}
+	_, err := storage.GetRange(stor, "foo", 3, 4)
+	c.Assert(err, gc.ErrorMatches, "an error")
+	c.Assert(stor.getName, gc.Equals, "foo")
+	c.Assert(stor.invokeCount, gc.Equals, 1)
+}
+
+func (s *storageSuite) TestGetRangeNegativeOffset(c *gc.C) {
+	_, err := storage.GetRange(&fakeStorage{}, "foo", -1, 4)
+	c.Assert(err, gc.ErrorMatches, "negative offset -1 not valid")
+}
+
+func (s *storageSuite) TestLimitRange(c *gc.C) {
+	for _, test := range []struct {
+		offset, length int64
+		expect         string
+	}{
+		{0, -1, "0123456789"},
+		{3, 4, "3456"},
+		{7, -1, "789"},
+		{8, 10, "89"},
+		{12, 1, ""},
+	} {
+		c.Logf("offset %d, length %d", test.offset, test.length)
+		r, err := storage.LimitRange(ioutil.NopCloser(bytes.NewReader([]byte("0123456789"))), test.offset, test.length)
+		c.Assert(err, jc.ErrorIsNil)
+		b, err := ioutil.ReadAll(r)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(string(b), gc.Equals, test.expect)
+	}
+}
+
+// flakyStorage serves a single file, dropping each connection
+// after at most dropAfter bytes have been read from it.
+type flakyStorage struct {
+	fakeStorage
+	content   string
+	dropAfter int
+	offsets   []int64
+}
+
+func (s *flakyStorage) Get(name string) (io.ReadCloser, error) {
+	return s.GetRange(name, 0, -1)
+}
+
+func (s *flakyStorage) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	s.offsets = append(s.offsets, offset)
+	rest := s.content[offset:]
+	var err error = io.EOF
+	if len(rest) > s.dropAfter {
+		rest, err = rest[:s.dropAfter], io.ErrUnexpectedEOF
+	}
+	return ioutil.NopCloser(io.MultiReader(strings.NewReader(rest), errorReader{err})), nil
+}
+
+func (s *flakyStorage) ShouldRetry(err error) bool {
+	return err == io.ErrUnexpectedEOF
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func (s *storageSuite) TestGetResuming(c *gc.C) {
+	stor := &flakyStorage{content: "0123456789", dropAfter: 4}
+	r, err := storage.GetResuming(stor, "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(b), gc.Equals, "0123456789")
+	c.Assert(stor.offsets, jc.DeepEquals, []int64{0, 4, 8})
+}
+
+func (s *storageSuite) TestGetResumingGivesUp(c *gc.C) {
+	stor := &flakyStorage{content: "0123456789", dropAfter: 1}
+	r, err := storage.GetResuming(stor, "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	c.Assert(err, gc.Equals, io.ErrUnexpectedEOF)
+	c.Assert(string(b), gc.Equals, "012345")
+	c.Assert(stor.offsets, gc.HasLen, 6)
+}
+
+func (s *storageSuite) TestHTTPRange(c *gc.C) {
+	c.Assert(storage.HTTPRange(3, 4), gc.Equals, "bytes=3-6")
+	c.Assert(storage.HTTPRange(3, -1), gc.Equals, "bytes=3-")
+}
+
+func (s *storageSuite) TestListPageNotSupported(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range []string{"a", "b/c", "b/d", "b/e", "c"} {
+		err := stor.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	var pages [][]string
+	token := ""
+	for {
+		names, next, err := storage.ListPage(stor, "b/", token, 2)
+		c.Assert(err, jc.ErrorIsNil)
+		pages = append(pages, names)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	c.Assert(pages, jc.DeepEquals, [][]string{{"b/c", "b/d"}, {"b/e"}})
+}
+
 func (s *storageSuite) TestListWithRetry(c *gc.C) {
 	stor := &fakeStorage{shouldRetry: true}
 	// TODO(katco): 2016-08-09: lp:1611427
@@ -189,3 +364,271 @@ func (s *storageSuite) TestListNoRetryAllowed(c *gc.C) {
 	c.Assert(stor.listPrefix, gc.Equals, "foo")
 	c.Assert(stor.invokeCount, gc.Equals, 1)
 }
+
+type fakeWriter struct {
+	putName string
+}
+
+func (w *fakeWriter) Put(name string, r io.Reader, length int64) error {
+	w.putName = name
+	return nil
+}
+
+func (w *fakeWriter) Remove(name string) error {
+	return nil
+}
+
+func (w *fakeWriter) RemoveAll() error {
+	return nil
+}
+
+func (s *storageSuite) TestPutWithOptionsNoOptions(c *gc.C) {
+	w := &fakeWriter{}
+	err := storage.PutWithOptions(w, "foo", bytes.NewReader(nil), 0, storage.PutOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.putName, gc.Equals, "foo")
+}
+
+func (s *storageSuite) TestPutWithOptionsNotSupported(c *gc.C) {
+	w := &fakeWriter{}
+	err := storage.PutWithOptions(w, "foo", bytes.NewReader(nil), 0, storage.PutOptions{
+		Encryption: storage.EncryptionSSE,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(w.putName, gc.Equals, "")
+}
+
+var invalidPutOptionsTests = []struct {
+	opts storage.PutOptions
+	err  string
+}{{
+	opts: storage.PutOptions{Encryption: "rot13"},
+	err:  `encryption mode "rot13" not valid`,
+}, {
+	opts: storage.PutOptions{Encryption: storage.EncryptionSSEKMS},
+	err:  `"sse-kms" encryption without KMS key ID not valid`,
+}, {
+	opts: storage.PutOptions{Encryption: storage.EncryptionSSE, KMSKeyID: "key"},
+	err:  `KMS key ID without "sse-kms" encryption not valid`,
+}}
+
+func (s *storageSuite) TestPutWithOptionsInvalid(c *gc.C) {
+	for i, test := range invalidPutOptionsTests {
+		c.Logf("test %d: %+v", i, test.opts)
+		err := storage.PutWithOptions(&fakeWriter{}, "foo", bytes.NewReader(nil), 0, test.opts)
+		c.Assert(err, gc.ErrorMatches, test.err)
+	}
+}
+
+type fakeMultipartWriter struct {
+	fakeWriter
+	parts     map[int][]byte
+	failures  int
+	completed bool
+	aborted   bool
+}
+
+func (w *fakeMultipartWriter) InitMultipart(name string) (storage.MultipartUpload, error) {
+	w.parts = make(map[int][]byte)
+	return w, nil
+}
+
+func (w *fakeMultipartWriter) PutPart(n int, r io.ReadSeeker, length int64) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != length {
+		return fmt.Errorf("part %d: got %d bytes, expected %d", n, len(data), length)
+	}
+	if w.failures > 0 {
+		w.failures--
+		return fmt.Errorf("connection reset")
+	}
+	w.parts[n] = data
+	return nil
+}
+
+func (w *fakeMultipartWriter) Complete() error {
+	w.completed = true
+	return nil
+}
+
+func (w *fakeMultipartWriter) Abort() error {
+	w.aborted = true
+	return nil
+}
+
+type multipartSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&multipartSuite{})
+
+func (s *multipartSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(storage.MultipartPartSize, int64(4))
+	s.PatchValue(storage.PartAttempt, utils.AttemptStrategy{Min: 3})
+}
+
+func (s *multipartSuite) TestPutMultipart(c *gc.C) {
+	w := &fakeMultipartWriter{failures: 2}
+	err := storage.PutMultipart(w, "foo", bytes.NewReader([]byte("0123456789")), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.parts, jc.DeepEquals, map[int][]byte{
+		1: []byte("0123"),
+		2: []byte("4567"),
+		3: []byte("89"),
+	})
+	c.Assert(w.completed, jc.IsTrue)
+	c.Assert(w.putName, gc.Equals, "")
+}
+
+func (s *multipartSuite) TestPutMultipartAbortsOnFailure(c *gc.C) {
+	w := &fakeMultipartWriter{failures: 3}
+	err := storage.PutMultipart(w, "foo", bytes.NewReader([]byte("0123456789")), 10)
+	c.Assert(err, gc.ErrorMatches, `uploading "foo": uploading part 1: connection reset`)
+	c.Assert(w.aborted, jc.IsTrue)
+	c.Assert(w.completed, jc.IsFalse)
+}
+
+func (s *multipartSuite) TestPutMultipartWithRetry(c *gc.C) {
+	w := &fakeMultipartWriter{failures: 1}
+	err := storage.PutMultipartWithRetry(w, "foo", bytes.NewReader([]byte("0123456789")), 10, utils.AttemptStrategy{Min: 1})
+	c.Assert(err, gc.ErrorMatches, `uploading "foo": uploading part 1: connection reset`)
+	c.Assert(w.aborted, jc.IsTrue)
+
+	w = &fakeMultipartWriter{failures: 1}
+	err = storage.PutMultipartWithRetry(w, "foo", bytes.NewReader([]byte("0123456789")), 10, utils.AttemptStrategy{Min: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.parts, gc.HasLen, 3)
+	c.Assert(w.completed, jc.IsTrue)
+}
+
+func (s *multipartSuite) TestPutMultipartShortReader(c *gc.C) {
+	w := &fakeMultipartWriter{}
+	err := storage.PutMultipart(w, "foo", bytes.NewReader([]byte("012345678")), 10)
+	c.Assert(err, gc.ErrorMatches, `uploading "foo": reading part 3: unexpected EOF`)
+	c.Assert(w.aborted, jc.IsTrue)
+	c.Assert(w.completed, jc.IsFalse)
+}
+
+func (s *multipartSuite) TestPutHashedLargeUsesMultipart(c *gc.C) {
+	w := &fakeMultipartWriter{}
+	err := storage.PutHashed(w, "foo", "abc", bytes.NewReader([]byte("0123456789")), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.parts, gc.HasLen, 3)
+	c.Assert(w.completed, jc.IsTrue)
+	c.Assert(w.putName, gc.Equals, "")
+}
+
+func (s *multipartSuite) TestPutMultipartSinglePart(c *gc.C) {
+	w := &fakeMultipartWriter{}
+	err := storage.PutMultipart(w, "foo", bytes.NewReader([]byte("0123")), 4)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.putName, gc.Equals, "foo")
+	c.Assert(w.parts, gc.IsNil)
+}
+
+func (s *multipartSuite) TestPutMultipartNotSupported(c *gc.C) {
+	w := &fakeWriter{}
+	err := storage.PutMultipart(w, "foo", bytes.NewReader([]byte("0123456789")), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.putName, gc.Equals, "foo")
+}
+
+type fakeHashedWriter struct {
+	fakeWriter
+	hash string
+}
+
+func (w *fakeHashedWriter) PutHashed(name, sha256 string, r io.Reader, length int64) error {
+	w.hash = sha256
+	return nil
+}
+
+func (s *storageSuite) TestPutHashed(c *gc.C) {
+	w := &fakeHashedWriter{}
+	err := storage.PutHashed(w, "foo", "abc", bytes.NewReader(nil), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.hash, gc.Equals, "abc")
+	c.Assert(w.putName, gc.Equals, "")
+}
+
+func (s *storageSuite) TestPutHashedEmptyHash(c *gc.C) {
+	w := &fakeHashedWriter{}
+	err := storage.PutHashed(w, "foo", "", bytes.NewReader(nil), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.putName, gc.Equals, "foo")
+}
+
+func (s *storageSuite) TestPutHashedNotSupported(c *gc.C) {
+	w := &fakeWriter{}
+	err := storage.PutHashed(w, "foo", "abc", bytes.NewReader(nil), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.putName, gc.Equals, "foo")
+}
+
+type strongStorage struct {
+	fakeStorage
+}
+
+func (*strongStorage) Consistency() storage.Consistency {
+	return storage.StrongConsistency
+}
+
+func (s *storageSuite) TestRetryStrategyFromConfig(c *gc.C) {
+	cfg := testing.CustomModelConfig(c, testing.Attrs{
+		"storage-get-retry-attempts": 3,
+		"storage-get-retry-timeout":  10,
+	})
+	attempt := storage.RetryStrategy(&strongStorage{}, config.StorageGet, cfg)
+	c.Assert(attempt, jc.DeepEquals, utils.AttemptStrategy{
+		Total: 10 * time.Second,
+		Delay: 200 * time.Millisecond,
+		Min:   3,
+	})
+}
+
+func (s *storageSuite) TestRetryStrategyDefaults(c *gc.C) {
+	cfg := testing.ModelConfig(c)
+	attempt := storage.RetryStrategy(&fakeStorage{}, config.StorageList, cfg)
+	c.Assert(attempt, jc.DeepEquals, utils.AttemptStrategy{Min: 10})
+
+	attempt = storage.RetryStrategy(&strongStorage{}, config.StorageList, cfg)
+	c.Assert(attempt, jc.DeepEquals, utils.AttemptStrategy{})
+}
+
+// retrierStorage is a fakeStorage that configures its retries from
+// model config.
+type retrierStorage struct {
+	fakeStorage
+	cfg *config.Config
+}
+
+func (s *retrierStorage) RetryStrategy(op config.StorageOperation) utils.AttemptStrategy {
+	return storage.RetryStrategy(s, op, s.cfg)
+}
+
+func (s *storageSuite) TestOperationRetrierUsedByHelpers(c *gc.C) {
+	cfg := testing.CustomModelConfig(c, testing.Attrs{
+		"storage-get-retry-attempts":  3,
+		"storage-list-retry-attempts": 5,
+	})
+	stor := &retrierStorage{cfg: cfg}
+	stor.shouldRetry = true
+
+	_, err := storage.Get(stor, "foo")
+	c.Assert(err, gc.ErrorMatches, "an error")
+	c.Assert(stor.invokeCount, gc.Equals, 3)
+
+	stor.invokeCount = 0
+	_, err = storage.List(stor, "foo")
+	c.Assert(err, gc.ErrorMatches, "an error")
+	c.Assert(stor.invokeCount, gc.Equals, 5)
+}
+
+func (s *storageSuite) TestRetryStrategyNilConfig(c *gc.C) {
+	attempt := storage.RetryStrategy(&fakeStorage{}, config.StorageGet, nil)
+	c.Assert(attempt, jc.DeepEquals, utils.AttemptStrategy{Min: 10})
+}
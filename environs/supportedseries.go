@@ -0,0 +1,48 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/series"
+)
+
+// SupportedSeries returns the series of the instances that env can
+// start. If env does not implement SeriesSupporter, all series known
+// to juju are returned.
+func SupportedSeries(env Environ) ([]string, error) {
+	if supporter, ok := env.(SeriesSupporter); ok {
+		return supporter.SupportedSeries()
+	}
+	return series.SupportedSeries(), nil
+}
+
+// CheckSeriesSupported returns an error satisfying errors.IsNotSupported
+// if env cannot start instances running the given series.
+func CheckSeriesSupported(env Environ, seriesName string) error {
+	supporter, ok := env.(SeriesSupporter)
+	if !ok {
+		// Don't reject series that juju doesn't know about yet;
+		// the provider will have its say when starting instances.
+		return nil
+	}
+	supported, err := supporter.SupportedSeries()
+	if err != nil {
+		return errors.Annotate(err, "getting supported series")
+	}
+	for _, s := range supported {
+		if s == seriesName {
+			return nil
+		}
+	}
+	what := fmt.Sprintf("series %q", seriesName)
+	if osType, err := series.GetOSFromSeries(seriesName); err == nil {
+		what = fmt.Sprintf("%s series %q", osType, seriesName)
+	}
+	return errors.NewNotSupported(nil, fmt.Sprintf(
+		"provider %q does not support %s", env.Config().Type(), what,
+	))
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/series"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type supportedSeriesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&supportedSeriesSuite{})
+
+type plainEnviron struct {
+	environs.Environ
+	cfg *config.Config
+}
+
+func (e *plainEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+type seriesEnviron struct {
+	plainEnviron
+	series []string
+	err    error
+}
+
+func (e *seriesEnviron) SupportedSeries() ([]string, error) {
+	return e.series, e.err
+}
+
+func (s *supportedSeriesSuite) TestSupportedSeriesDefault(c *gc.C) {
+	env := &plainEnviron{cfg: coretesting.ModelConfig(c)}
+	supported, err := environs.SupportedSeries(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.SameContents, series.SupportedSeries())
+	c.Assert(environs.CheckSeriesSupported(env, "centos7"), jc.ErrorIsNil)
+}
+
+func (s *supportedSeriesSuite) TestSupportedSeries(c *gc.C) {
+	env := &seriesEnviron{
+		plainEnviron: plainEnviron{cfg: coretesting.ModelConfig(c)},
+		series:       []string{"trusty", "xenial"},
+	}
+	supported, err := environs.SupportedSeries(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.DeepEquals, []string{"trusty", "xenial"})
+	c.Assert(environs.CheckSeriesSupported(env, "xenial"), jc.ErrorIsNil)
+}
+
+func (s *supportedSeriesSuite) TestCheckSeriesSupportedUnsupported(c *gc.C) {
+	env := &seriesEnviron{
+		plainEnviron: plainEnviron{cfg: coretesting.ModelConfig(c)},
+		series:       []string{"xenial"},
+	}
+	err := environs.CheckSeriesSupported(env, "win2012r2")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `provider "someprovider" does not support Windows series "win2012r2"`)
+
+	err = environs.CheckSeriesSupported(env, "centos7")
+	c.Assert(err, gc.ErrorMatches, `provider "someprovider" does not support CentOS series "centos7"`)
+
+	err = environs.CheckSeriesSupported(env, "nonsense")
+	c.Assert(err, gc.ErrorMatches, `provider "someprovider" does not support series "nonsense"`)
+}
+
+func (s *supportedSeriesSuite) TestCheckSeriesSupportedError(c *gc.C) {
+	env := &seriesEnviron{
+		plainEnviron: plainEnviron{cfg: coretesting.ModelConfig(c)},
+		err:          errors.New("boom"),
+	}
+	err := environs.CheckSeriesSupported(env, "xenial")
+	c.Assert(err, gc.ErrorMatches, "getting supported series: boom")
+}
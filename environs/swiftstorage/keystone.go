@@ -0,0 +1,173 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package swiftstorage
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+)
+
+const defaultDomainName = "Default"
+
+// token holds a Keystone v3 token and the Swift endpoint found in
+// the catalog returned with it.
+type token struct {
+	id         string
+	storageURL string
+}
+
+type authRequest struct {
+	Auth authBody `json:"auth"`
+}
+
+type authBody struct {
+	Identity authIdentity `json:"identity"`
+	Scope    *authScope   `json:"scope,omitempty"`
+}
+
+type authIdentity struct {
+	Methods               []string             `json:"methods"`
+	Password              *authPassword        `json:"password,omitempty"`
+	ApplicationCredential *authApplicationCred `json:"application_credential,omitempty"`
+}
+
+type authPassword struct {
+	User authUser `json:"user"`
+}
+
+type authUser struct {
+	Name     string     `json:"name"`
+	Password string     `json:"password"`
+	Domain   authDomain `json:"domain"`
+}
+
+type authDomain struct {
+	Name string `json:"name"`
+}
+
+type authApplicationCred struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+type authScope struct {
+	Project authProject `json:"project"`
+}
+
+type authProject struct {
+	Name   string     `json:"name"`
+	Domain authDomain `json:"domain"`
+}
+
+type authResponse struct {
+	Token struct {
+		Catalog []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				Region    string `json:"region"`
+				RegionID  string `json:"region_id"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+// newAuthRequest returns the Keystone v3 token request for the given
+// settings. Application credentials are scoped by Keystone itself, so
+// only password authentication carries an explicit project scope.
+func newAuthRequest(settings config.SwiftStorageSettings) authRequest {
+	if settings.ApplicationCredentialID != "" {
+		return authRequest{Auth: authBody{
+			Identity: authIdentity{
+				Methods: []string{"application_credential"},
+				ApplicationCredential: &authApplicationCred{
+					ID:     settings.ApplicationCredentialID,
+					Secret: settings.ApplicationCredentialSecret,
+				},
+			},
+		}}
+	}
+	return authRequest{Auth: authBody{
+		Identity: authIdentity{
+			Methods: []string{"password"},
+			Password: &authPassword{User: authUser{
+				Name:     settings.Username,
+				Password: settings.Password,
+				Domain:   authDomain{orDefaultDomain(settings.UserDomainName)},
+			}},
+		},
+		Scope: &authScope{Project: authProject{
+			Name:   settings.ProjectName,
+			Domain: authDomain{orDefaultDomain(settings.ProjectDomainName)},
+		}},
+	}}
+}
+
+func orDefaultDomain(name string) string {
+	if name == "" {
+		return defaultDomainName
+	}
+	return name
+}
+
+// authenticate requests a new token from Keystone, and finds the
+// public object-store endpoint for the configured region.
+func authenticate(client *http.Client, settings config.SwiftStorageSettings) (*token, error) {
+	body, err := json.Marshal(newAuthRequest(settings))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	url := strings.TrimSuffix(settings.AuthURL, "/")
+	if !strings.HasSuffix(url, "/v3") {
+		url += "/v3"
+	}
+	req, err := http.NewRequest("POST", url+"/auth/tokens", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "requesting Keystone token")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return nil, errors.Errorf("requesting Keystone token: %s", storage.HTTPResponseError(resp))
+	}
+	var result authResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Annotate(err, "decoding Keystone token")
+	}
+	storageURL := ""
+	for _, service := range result.Token.Catalog {
+		if service.Type != "object-store" {
+			continue
+		}
+		for _, ep := range service.Endpoints {
+			if ep.Interface != "public" {
+				continue
+			}
+			if settings.Region != "" && ep.Region != settings.Region && ep.RegionID != settings.Region {
+				continue
+			}
+			storageURL = ep.URL
+			break
+		}
+	}
+	if storageURL == "" {
+		return nil, errors.NotFoundf("object-store endpoint in region %q", settings.Region)
+	}
+	return &token{
+		id:         resp.Header.Get("X-Subject-Token"),
+		storageURL: strings.TrimSuffix(storageURL, "/"),
+	}, nil
+}
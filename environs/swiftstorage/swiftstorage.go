@@ -0,0 +1,438 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package swiftstorage provides an implementation of storage.Storage
+// backed by OpenStack Swift, authenticating with Keystone v3.
+//
+// Keystone v3 is used directly rather than through goose, so that
+// domain-scoped users and application credentials can be used with
+// clouds that have disabled older identity API versions.
+package swiftstorage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+)
+
+// NewFromModelConfig returns a storage.Storage using the Swift storage
+// settings in the given model config. If Swift storage is not
+// configured, an error satisfying errors.IsNotFound is returned.
+func NewFromModelConfig(cfg *config.Config) (storage.Storage, error) {
+	settings, ok := cfg.SwiftStorage()
+	if !ok {
+		return nil, errors.NotFoundf("Swift storage settings")
+	}
+	stor, err := New(settings, storage.HTTPClient)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stor.(*swiftStorage).cfg = cfg
+	return stor, nil
+}
+
+// New returns a storage.Storage that keeps its files in the Swift
+// container described by the given settings. The container is created
+// when the first file is put, if it does not already exist. Keystone
+// is not contacted until the storage is first used.
+func New(settings config.SwiftStorageSettings, client *http.Client) (storage.Storage, error) {
+	if settings.Container == "" {
+		return nil, errors.NotValidf("empty container name")
+	}
+	if settings.AuthURL == "" {
+		return nil, errors.NotValidf("empty auth URL")
+	}
+	return &swiftStorage{
+		settings: settings,
+		client:   client,
+	}, nil
+}
+
+// swiftStorage implements storage.Storage on a Swift container.
+type swiftStorage struct {
+	settings config.SwiftStorageSettings
+	client   *http.Client
+
+	// cfg holds the model config the storage was created from,
+	// if any.
+	cfg *config.Config
+
+	mu            sync.Mutex
+	token         *token
+	madeContainer bool
+}
+
+// currentToken returns the token to use for requests, authenticating
+// if there is none.
+func (s *swiftStorage) currentToken() (*token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil {
+		return s.token, nil
+	}
+	t, err := authenticate(s.client, s.settings)
+	if err != nil {
+		return nil, errors.Annotate(err, "authenticating with Keystone")
+	}
+	s.token = t
+	return t, nil
+}
+
+// do sends a request for the given path relative to the container.
+// If the token has expired it is discarded, and a request without a
+// body is sent again once with a new token.
+func (s *swiftStorage) do(method, path string, query url.Values, header http.Header, body io.Reader, length int64) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		t, err := s.currentToken()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		u := t.storageURL + "/" + escapePath(s.settings.Container)
+		if path != "" {
+			u += "/" + escapePath(path)
+		}
+		if len(query) > 0 {
+			u += "?" + query.Encode()
+		}
+		req, err := http.NewRequest(method, u, body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if body != nil {
+			req.ContentLength = length
+		}
+		req.Header.Set("X-Auth-Token", t.id)
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized {
+			return resp, nil
+		}
+		s.mu.Lock()
+		if s.token == t {
+			s.token = nil
+		}
+		s.mu.Unlock()
+		// A request with a body cannot be replayed, so only
+		// body-less requests are retried with a new token.
+		if body != nil || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+}
+
+// makeContainer makes the container in which files are stored. To
+// avoid two round trips on every PUT operation, we do this only once.
+func (s *swiftStorage) makeContainer() error {
+	s.mu.Lock()
+	made := s.madeContainer
+	s.mu.Unlock()
+	if made {
+		return nil
+	}
+	// PUT succeeds if the container already exists.
+	resp, err := s.do("PUT", "", nil, nil, nil, 0)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		return errors.New(storage.HTTPResponseError(resp))
+	}
+	s.mu.Lock()
+	s.madeContainer = true
+	s.mu.Unlock()
+	return nil
+}
+
+// Put is specified in the StorageWriter interface.
+func (s *swiftStorage) Put(name string, r io.Reader, length int64) error {
+	return s.put(name, r, length, nil)
+}
+
+// sha256Header is the object metadata header holding the hash of an
+// object written by PutHashed.
+const sha256Header = "X-Object-Meta-Sha256"
+
+// PutHashed is specified in the storage.HashedWriter interface. The
+// hash is kept in the object's metadata, so objects written by Put
+// are always uploaded again.
+func (s *swiftStorage) PutHashed(name, sha256 string, r io.Reader, length int64) error {
+	resp, err := s.do("HEAD", name, nil, nil, nil, 0)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && resp.Header.Get(sha256Header) == sha256 && resp.ContentLength == length {
+		return nil
+	}
+	return s.put(name, r, length, http.Header{sha256Header: {sha256}})
+}
+
+func (s *swiftStorage) put(name string, r io.Reader, length int64, header http.Header) error {
+	if err := s.makeContainer(); err != nil {
+		return errors.Annotatef(err, "cannot make Swift container %q", s.settings.Container)
+	}
+	resp, err := s.do("PUT", name, nil, header, r, length)
+	if err != nil {
+		return errors.Annotatef(err, "cannot write file %q to Swift container %q", name, s.settings.Container)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return errors.Errorf(
+			"cannot write file %q to Swift container %q: %s",
+			name, s.settings.Container, storage.HTTPResponseError(resp),
+		)
+	}
+	return nil
+}
+
+// Get is specified in the StorageReader interface.
+func (s *swiftStorage) Get(name string) (io.ReadCloser, error) {
+	resp, err := s.do("GET", name, nil, nil, nil, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	defer resp.Body.Close()
+	return nil, errors.Errorf("cannot read file %q: %s", name, storage.HTTPResponseError(resp))
+}
+
+// GetRange is specified in the storage.RangeReader interface.
+func (s *swiftStorage) GetRange(name string, offset, length int64) (io.ReadCloser, error) {
+	header := http.Header{"Range": {storage.HTTPRange(offset, length)}}
+	resp, err := s.do("GET", name, nil, header, nil, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusOK:
+		// The range was ignored, so the whole file is returned.
+		return storage.LimitRange(resp.Body, offset, length)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The offset is at or beyond the end of the file.
+		resp.Body.Close()
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	defer resp.Body.Close()
+	return nil, errors.Errorf("cannot read file %q: %s", name, storage.HTTPResponseError(resp))
+}
+
+// List is specified in the StorageReader interface.
+func (s *swiftStorage) List(prefix string) ([]string, error) {
+	return storage.ListPages(s, prefix)
+}
+
+// ListPage is specified in the storage.PagedLister interface. The
+// continuation token is the last name on the previous page.
+func (s *swiftStorage) ListPage(prefix, token string, maxItems int) ([]string, string, error) {
+	query := url.Values{
+		"format": {"json"},
+		"prefix": {prefix},
+	}
+	if token != "" {
+		query.Set("marker", token)
+	}
+	if maxItems > 0 {
+		query.Set("limit", fmt.Sprint(maxItems))
+	}
+	resp, err := s.do("GET", "", query, nil, nil, 0)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// The container is only created when the first file is
+		// put, so a missing container is not an error.
+		return nil, "", nil
+	case http.StatusNoContent:
+		return nil, "", nil
+	default:
+		return nil, "", errors.Errorf("cannot list files: %s", storage.HTTPResponseError(resp))
+	}
+	var objects []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&objects); err != nil {
+		return nil, "", errors.Annotate(err, "decoding file list")
+	}
+	if len(objects) == 0 {
+		return nil, "", nil
+	}
+	names := make([]string, len(objects))
+	for i, object := range objects {
+		names[i] = object.Name
+	}
+	// Swift does not say whether there are more objects, so unless
+	// the page is short, the listing continues until a page is empty.
+	if maxItems > 0 && len(names) < maxItems {
+		return names, "", nil
+	}
+	return names, names[len(names)-1], nil
+}
+
+// URL is specified in the StorageReader interface. If the account has
+// a temporary URL key, a signed URL is returned; otherwise the plain
+// URL of the file is returned, which is only usable if the container
+// is publicly readable.
+func (s *swiftStorage) URL(name string) (string, error) {
+	u, err := s.SignedURL(name, storage.URLExpiry())
+	if errors.IsNotSupported(err) {
+		t, err := s.currentToken()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return t.storageURL + "/" + escapePath(s.settings.Container) + "/" + escapePath(name), nil
+	}
+	return u, errors.Trace(err)
+}
+
+// SignedURL is specified in the storage.SignedURLReader interface.
+// Temporary URLs can only be generated if the account has a key for
+// signing them.
+func (s *swiftStorage) SignedURL(name string, expiry time.Time) (string, error) {
+	t, err := s.currentToken()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	objectURL := t.storageURL + "/" + escapePath(s.settings.Container) + "/" + escapePath(name)
+	key, err := s.tempURLKey(t)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if key == "" {
+		return "", errors.NotSupportedf("signed URLs without a temporary URL key")
+	}
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	expires := expiry.Unix()
+	mac := hmac.New(sha1.New, []byte(key))
+	fmt.Fprintf(mac, "GET\n%d\n%s", expires, u.EscapedPath())
+	query := url.Values{
+		"temp_url_sig":     {hex.EncodeToString(mac.Sum(nil))},
+		"temp_url_expires": {fmt.Sprint(expires)},
+	}
+	return objectURL + "?" + query.Encode(), nil
+}
+
+// tempURLKey returns the account's key for signing temporary URLs,
+// or an empty string if there is none.
+func (s *swiftStorage) tempURLKey(t *token) (string, error) {
+	req, err := http.NewRequest("HEAD", t.storageURL, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req.Header.Set("X-Auth-Token", t.id)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", errors.Annotate(err, "getting temporary URL key")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", errors.Errorf("getting temporary URL key: %s", storage.HTTPResponseError(resp))
+	}
+	return resp.Header.Get("X-Account-Meta-Temp-Url-Key"), nil
+}
+
+// DefaultConsistencyStrategy is specified in the StorageReader interface.
+func (s *swiftStorage) DefaultConsistencyStrategy() utils.AttemptStrategy {
+	return storage.HTTPAttempt
+}
+
+// Consistency is specified in the storage.ConsistencyReporter interface.
+func (s *swiftStorage) Consistency() storage.Consistency {
+	return storage.EventualConsistency
+}
+
+// RetryStrategy is specified in the storage.OperationRetrier interface.
+// The retry budgets in the model config the storage was created from,
+// if any, override the defaults.
+func (s *swiftStorage) RetryStrategy(op config.StorageOperation) utils.AttemptStrategy {
+	return storage.RetryStrategy(s, op, s.cfg)
+}
+
+// ShouldRetry is specified in the StorageReader interface.
+func (s *swiftStorage) ShouldRetry(err error) bool {
+	return errors.IsNotFound(err)
+}
+
+// Remove is specified in the StorageWriter interface.
+func (s *swiftStorage) Remove(name string) error {
+	resp, err := s.do("DELETE", name, nil, nil, nil, 0)
+	if err != nil {
+		return errors.Annotatef(err, "cannot remove file %q", name)
+	}
+	defer resp.Body.Close()
+	// If the file or container does not exist, then we don't care.
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return errors.Errorf("cannot remove file %q: %s", name, storage.HTTPResponseError(resp))
+	}
+	return nil
+}
+
+// RemoveAll is specified in the StorageWriter interface.
+func (s *swiftStorage) RemoveAll() error {
+	names, err := storage.List(s, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range names {
+		if err := s.Remove(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	s.mu.Lock()
+	s.madeContainer = false
+	s.mu.Unlock()
+	resp, err := s.do("DELETE", "", nil, nil, nil, 0)
+	if err != nil {
+		return errors.Annotatef(err, "cannot remove Swift container %q", s.settings.Container)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return errors.Errorf("cannot remove Swift container %q: %s", s.settings.Container, storage.HTTPResponseError(resp))
+	}
+	return nil
+}
+
+// escapePath escapes a container or object name for use in a URL
+// path. Slashes in object names are left intact.
+func escapePath(name string) string {
+	return (&url.URL{Path: name}).EscapedPath()
+}
@@ -0,0 +1,322 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package swiftstorage_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/swiftstorage"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+// fakeCloud is a minimal Keystone v3 and Swift server.
+type fakeCloud struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	authBodies []map[string]interface{}
+	token      string
+	containers map[string]map[string][]byte
+}
+
+func newFakeCloud() *fakeCloud {
+	f := &fakeCloud{containers: make(map[string]map[string][]byte)}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+func (f *fakeCloud) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if req.URL.Path == "/v3/auth/tokens" {
+		var body map[string]interface{}
+		json.NewDecoder(req.Body).Decode(&body)
+		f.authBodies = append(f.authBodies, body)
+		f.token = fmt.Sprintf("tok%d", len(f.authBodies))
+		w.Header().Set("X-Subject-Token", f.token)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"token": map[string]interface{}{
+				"catalog": []interface{}{map[string]interface{}{
+					"type": "object-store",
+					"endpoints": []interface{}{map[string]interface{}{
+						"interface": "public",
+						"region":    "RegionOne",
+						"url":       f.URL + "/swift/v1",
+					}},
+				}},
+			},
+		})
+		return
+	}
+	if f.token == "" || req.Header.Get("X-Auth-Token") != f.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/swift/v1")
+	if path == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	container := f.containers[parts[0]]
+	if len(parts) == 1 {
+		switch req.Method {
+		case "PUT":
+			if container == nil {
+				f.containers[parts[0]] = make(map[string][]byte)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			if container == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(f.containers, parts[0])
+			w.WriteHeader(http.StatusNoContent)
+		case "GET":
+			if container == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			prefix := req.URL.Query().Get("prefix")
+			marker := req.URL.Query().Get("marker")
+			var names []string
+			for name := range container {
+				if strings.HasPrefix(name, prefix) && name > marker {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			var objects []map[string]string
+			for _, name := range names {
+				objects = append(objects, map[string]string{"name": name})
+			}
+			if len(objects) == 0 {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			json.NewEncoder(w).Encode(objects)
+		}
+		return
+	}
+	if container == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch req.Method {
+	case "PUT":
+		data, _ := ioutil.ReadAll(req.Body)
+		container[parts[1]] = data
+		w.WriteHeader(http.StatusCreated)
+	case "GET":
+		data, ok := container[parts[1]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case "DELETE":
+		if _, ok := container[parts[1]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(container, parts[1])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type swiftstorageSuite struct {
+	testing.BaseSuite
+	cloud   *fakeCloud
+	storage storage.Storage
+}
+
+var _ = gc.Suite(&swiftstorageSuite{})
+
+func (s *swiftstorageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.cloud = newFakeCloud()
+	s.AddCleanup(func(*gc.C) { s.cloud.Close() })
+	var err error
+	s.storage, err = swiftstorage.New(config.SwiftStorageSettings{
+		AuthURL:        s.cloud.URL,
+		Container:      "juju-test",
+		Username:       "user",
+		Password:       "pass",
+		ProjectName:    "project",
+		UserDomainName: "users",
+	}, http.DefaultClient)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *swiftstorageSuite) TestPasswordAuth(c *gc.C) {
+	_, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.cloud.authBodies, gc.HasLen, 1)
+	c.Assert(s.cloud.authBodies[0], jc.DeepEquals, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []interface{}{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     "user",
+						"password": "pass",
+						"domain":   map[string]interface{}{"name": "users"},
+					},
+				},
+			},
+			"scope": map[string]interface{}{
+				"project": map[string]interface{}{
+					"name":   "project",
+					"domain": map[string]interface{}{"name": "Default"},
+				},
+			},
+		},
+	})
+}
+
+func (s *swiftstorageSuite) TestApplicationCredentialAuth(c *gc.C) {
+	stor, err := swiftstorage.New(config.SwiftStorageSettings{
+		AuthURL:                     s.cloud.URL + "/v3",
+		Container:                   "juju-test",
+		ApplicationCredentialID:     "id",
+		ApplicationCredentialSecret: "secret",
+	}, http.DefaultClient)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stor.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.cloud.authBodies, gc.HasLen, 1)
+	c.Assert(s.cloud.authBodies[0], jc.DeepEquals, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []interface{}{"application_credential"},
+				"application_credential": map[string]interface{}{
+					"id":     "id",
+					"secret": "secret",
+				},
+			},
+		},
+	})
+}
+
+func (s *swiftstorageSuite) TestNoObjectStoreInRegion(c *gc.C) {
+	stor, err := swiftstorage.New(config.SwiftStorageSettings{
+		AuthURL:                     s.cloud.URL,
+		Region:                      "RegionTwo",
+		Container:                   "juju-test",
+		ApplicationCredentialID:     "id",
+		ApplicationCredentialSecret: "secret",
+	}, http.DefaultClient)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stor.List("")
+	c.Assert(err, gc.ErrorMatches, `authenticating with Keystone: object-store endpoint in region "RegionTwo" not found`)
+}
+
+func (s *swiftstorageSuite) TestPutGet(c *gc.C) {
+	data := []byte("hello")
+	err := s.storage.Put("a/b", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	r, err := s.storage.Get("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, data)
+}
+
+func (s *swiftstorageSuite) TestExpiredTokenRefreshed(c *gc.C) {
+	_, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	s.cloud.mu.Lock()
+	s.cloud.token = ""
+	s.cloud.mu.Unlock()
+
+	// A request without a body is retried with a new token.
+	_, err = s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.cloud.authBodies, gc.HasLen, 2)
+}
+
+func (s *swiftstorageSuite) TestExpiredTokenDiscardedOnPut(c *gc.C) {
+	data := []byte("hello")
+	err := s.storage.Put("a", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	s.cloud.mu.Lock()
+	s.cloud.token = ""
+	s.cloud.mu.Unlock()
+
+	// A request with a body cannot be replayed, so it fails, but
+	// the next request authenticates again.
+	err = s.storage.Put("a", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, gc.ErrorMatches, `cannot write file "a" to Swift container "juju-test": bad HTTP response: 401 Unauthorized`)
+	err = s.storage.Put("a", bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.cloud.authBodies, gc.HasLen, 2)
+}
+
+func (s *swiftstorageSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.storage.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *swiftstorageSuite) TestList(c *gc.C) {
+	names, err := s.storage.List("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+
+	for _, name := range []string{"b/c", "a", "b/d", "c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	names, err = s.storage.List("b/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"b/c", "b/d"})
+}
+
+func (s *swiftstorageSuite) TestRemoveAll(c *gc.C) {
+	for _, name := range []string{"a", "b/c"} {
+		err := s.storage.Put(name, bytes.NewReader(nil), 0)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storage.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storage.RemoveAll()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.cloud.containers, gc.HasLen, 0)
+}
+
+func (s *swiftstorageSuite) TestURL(c *gc.C) {
+	url, err := s.storage.URL("a/b")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, gc.Equals, s.cloud.URL+"/swift/v1/juju-test/a/b")
+}
+
+func (s *swiftstorageSuite) TestNewFromModelConfigNotConfigured(c *gc.C) {
+	_, err := swiftstorage.NewFromModelConfig(testing.ModelConfig(c))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,281 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+)
+
+// ManifestDir is the storage directory, relative to the tools base
+// path, in which signed sync manifests are written.
+var ManifestDir = storage.BaseToolsPath + "/manifests"
+
+// ImageManifestDir is the storage directory, relative to the images
+// base path, in which signed image metadata manifests are written.
+var ImageManifestDir = storage.BaseImagesPath + "/manifests"
+
+// manifestTimeFormat is the format of the timestamp embedded in
+// manifest names.
+const manifestTimeFormat = "20060102T150405Z"
+
+// ManifestEntry records a single object copied by a sync run.
+type ManifestEntry struct {
+	// Path is the storage name of the object.
+	Path string `json:"path"`
+
+	// SHA256 is the hex encoded SHA-256 hash of the object.
+	SHA256 string `json:"sha256"`
+
+	// Size is the size of the object in bytes.
+	Size int64 `json:"size"`
+
+	// SourceURL is the URL the object was copied from.
+	SourceURL string `json:"source-url,omitempty"`
+}
+
+// Manifest describes the objects copied by a single sync run. Signed
+// manifests allow the chain of custody of tools to be checked when
+// they are later imported elsewhere.
+type Manifest struct {
+	// Stream is the simplestreams stream that was synced.
+	Stream string `json:"stream"`
+
+	// Timestamp records when the sync run completed.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Entries holds one entry per copied object.
+	Entries []ManifestEntry `json:"entries"`
+}
+
+// ManifestPath returns the storage name for a manifest written for
+// the given stream at the given time.
+func ManifestPath(stream string, timestamp time.Time) string {
+	return manifestPath(ManifestDir, stream, timestamp)
+}
+
+// ImageManifestPath returns the storage name for an image metadata
+// manifest written for the given stream at the given time.
+func ImageManifestPath(stream string, timestamp time.Time) string {
+	return manifestPath(ImageManifestDir, stream, timestamp)
+}
+
+func manifestPath(dir, stream string, timestamp time.Time) string {
+	return fmt.Sprintf("%s/%s-%s%s", dir, stream, timestamp.UTC().Format(manifestTimeFormat), simplestreams.SignedSuffix)
+}
+
+// parseManifestPath returns the stream for which the manifest with the
+// given storage name in dir was written. It returns false if the name
+// is not that of a manifest.
+func parseManifestPath(dir, name string) (string, bool) {
+	if !strings.HasPrefix(name, dir+"/") || !strings.HasSuffix(name, simplestreams.SignedSuffix) {
+		return "", false
+	}
+	base := strings.TrimSuffix(strings.TrimPrefix(name, dir+"/"), simplestreams.SignedSuffix)
+	sep := strings.LastIndex(base, "-")
+	if sep <= 0 {
+		return "", false
+	}
+	if _, err := time.Parse(manifestTimeFormat, base[sep+1:]); err != nil {
+		return "", false
+	}
+	return base[:sep], true
+}
+
+// Entry returns the manifest entry for the given storage name.
+func (m *Manifest) Entry(path string) (ManifestEntry, bool) {
+	for _, entry := range m.Entries {
+		if entry.Path == path {
+			return entry, true
+		}
+	}
+	return ManifestEntry{}, false
+}
+
+// SignManifest encodes the manifest as JSON and returns an inline
+// signed copy of it, using the given armored private key.
+func SignManifest(m *Manifest, armoredPrivateKey, passphrase string) ([]byte, error) {
+	sorted := *m
+	sorted.Entries = append([]ManifestEntry(nil), m.Entries...)
+	sort.Sort(byPath(sorted.Entries))
+	data, err := json.MarshalIndent(&sorted, "", "    ")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	signed, err := simplestreams.Encode(bytes.NewReader(data), armoredPrivateKey, passphrase)
+	if err != nil {
+		return nil, errors.Annotate(err, "signing sync manifest")
+	}
+	return signed, nil
+}
+
+// DecodeManifest checks the signature of the manifest read from r
+// against the given armored public key and returns the manifest.
+func DecodeManifest(r io.Reader, armoredPublicKey string) (*Manifest, error) {
+	data, err := simplestreams.DecodeCheckSignature(r, armoredPublicKey)
+	if err != nil {
+		return nil, errors.Annotate(err, "checking sync manifest signature")
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal sync manifest")
+	}
+	return &m, nil
+}
+
+// ReadLatestManifest reads and verifies the most recently written
+// manifest for the given stream from the given storage.
+func ReadLatestManifest(stor storage.StorageReader, stream, armoredPublicKey string) (*Manifest, error) {
+	return readLatestManifest(stor, ManifestDir, stream, armoredPublicKey)
+}
+
+// ReadLatestImageManifest reads and verifies the most recently written
+// image metadata manifest for the given stream from the given storage.
+func ReadLatestImageManifest(stor storage.StorageReader, stream, armoredPublicKey string) (*Manifest, error) {
+	return readLatestManifest(stor, ImageManifestDir, stream, armoredPublicKey)
+}
+
+func readLatestManifest(stor storage.StorageReader, dir, stream, armoredPublicKey string) (*Manifest, error) {
+	names, err := storage.List(stor, dir+"/"+stream+"-")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The prefix also matches the manifests of streams whose names
+	// start with this stream's name followed by a hyphen.
+	var name string
+	for _, candidate := range names {
+		if manifestStream, ok := parseManifestPath(dir, candidate); ok && manifestStream == stream {
+			// Manifest names embed a sortable timestamp, and List
+			// returns names in alphabetical order.
+			name = candidate
+		}
+	}
+	if name == "" {
+		return nil, errors.NotFoundf("sync manifest for stream %q", stream)
+	}
+	r, err := storage.Get(stor, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer r.Close()
+	m, err := DecodeManifest(r, armoredPublicKey)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading %q", name)
+	}
+	return m, nil
+}
+
+// VerifyLatestManifests checks, for each stream with a manifest in the
+// given manifest directory, that the most recently written manifest is
+// signed with the given key and that every object it lists exists in
+// the storage with the recorded size and hash. It returns the number
+// of manifests verified.
+func VerifyLatestManifests(stor storage.StorageReader, dir, armoredPublicKey string) (int, error) {
+	names, err := storage.List(stor, dir+"/")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	streams := make(map[string]bool)
+	for _, name := range names {
+		if stream, ok := parseManifestPath(dir, name); ok {
+			streams[stream] = true
+		}
+	}
+	for stream := range streams {
+		m, err := readLatestManifest(stor, dir, stream, armoredPublicKey)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if err := VerifyManifest(m, stor); err != nil {
+			return 0, errors.Annotatef(err, "verifying %q manifest", stream)
+		}
+	}
+	return len(streams), nil
+}
+
+// BuildManifest returns a manifest for the given stream, recording
+// every object in the storage whose name has the given prefix.
+func BuildManifest(stor storage.StorageReader, prefix, stream string) (*Manifest, error) {
+	names, err := storage.List(stor, prefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m := &Manifest{
+		Stream:    stream,
+		Timestamp: time.Now().UTC(),
+	}
+	for _, name := range names {
+		r, err := storage.Get(stor, name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "reading %q", name)
+		}
+		sha256, size, err := utils.ReadSHA256(r)
+		r.Close()
+		if err != nil {
+			return nil, errors.Annotatef(err, "reading %q", name)
+		}
+		m.Entries = append(m.Entries, ManifestEntry{
+			Path:   name,
+			SHA256: sha256,
+			Size:   size,
+		})
+	}
+	return m, nil
+}
+
+// VerifyManifest checks that every object listed in the manifest
+// exists in the given storage with the recorded size and hash.
+func VerifyManifest(m *Manifest, stor storage.StorageReader) error {
+	for _, entry := range m.Entries {
+		r, err := storage.Get(stor, entry.Path)
+		if err != nil {
+			return errors.Annotatef(err, "verifying %q", entry.Path)
+		}
+		sha256, size, err := utils.ReadSHA256(r)
+		r.Close()
+		if err != nil {
+			return errors.Annotatef(err, "verifying %q", entry.Path)
+		}
+		if err := checkManifestEntry(entry, sha256, size); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// checkManifestEntry returns an error if the given hash and size
+// do not match those recorded in the manifest entry.
+func checkManifestEntry(entry ManifestEntry, sha256 string, size int64) error {
+	if sha256 != entry.SHA256 {
+		return errors.Errorf("%q: SHA-256 hash mismatch (%v/%v)", entry.Path, sha256, entry.SHA256)
+	}
+	if size != entry.Size {
+		return errors.Errorf("%q: size mismatch (%d/%d)", entry.Path, size, entry.Size)
+	}
+	return nil
+}
+
+// ManifestUploader is implemented by ToolsUploaders that are able to
+// store a signed sync manifest alongside the uploaded tools.
+type ManifestUploader interface {
+	// UploadManifest stores the signed manifest data with the given name.
+	UploadManifest(name string, data []byte) error
+}
+
+type byPath []ManifestEntry
+
+func (b byPath) Len() int           { return len(b) }
+func (b byPath) Less(i, j int) bool { return b[i].Path < b[j].Path }
+func (b byPath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
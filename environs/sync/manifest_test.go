@@ -0,0 +1,176 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sync_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/filestorage"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/sync"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type manifestSuite struct {
+	coretesting.BaseSuite
+	storage storage.Storage
+}
+
+var _ = gc.Suite(&manifestSuite{})
+
+func (s *manifestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.storage = stor
+}
+
+func (s *manifestSuite) putObject(c *gc.C, name, content string) {
+	err := s.storage.Put(name, strings.NewReader(content), int64(len(content)))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// sha256 of "hello".
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func (s *manifestSuite) manifest() *sync.Manifest {
+	return &sync.Manifest{
+		Stream:    "released",
+		Timestamp: time.Date(2016, 8, 1, 12, 0, 0, 0, time.UTC),
+		Entries: []sync.ManifestEntry{{
+			Path:      "tools/released/juju-1.8.3-quantal-amd64.tgz",
+			SHA256:    helloSHA256,
+			Size:      5,
+			SourceURL: "https://example.com/juju-1.8.3-quantal-amd64.tgz",
+		}},
+	}
+}
+
+func (s *manifestSuite) TestManifestPath(c *gc.C) {
+	t := time.Date(2016, 8, 1, 12, 30, 5, 0, time.UTC)
+	c.Assert(sync.ManifestPath("released", t), gc.Equals, "tools/manifests/released-20160801T123005Z.sjson")
+}
+
+func (s *manifestSuite) TestSignDecodeRoundTrip(c *gc.C) {
+	m := s.manifest()
+	data, err := sync.SignManifest(m, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+	decoded, err := sync.DecodeManifest(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decoded, jc.DeepEquals, m)
+}
+
+func (s *manifestSuite) TestDecodeUnsigned(c *gc.C) {
+	_, err := sync.DecodeManifest(strings.NewReader(`{"stream": "released"}`), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, "checking sync manifest signature: no PGP signature embedded in plain text data")
+}
+
+func (s *manifestSuite) TestReadLatestManifest(c *gc.C) {
+	older := s.manifest()
+	older.Timestamp = older.Timestamp.Add(-time.Hour)
+	older.Entries = nil
+	for _, m := range []*sync.Manifest{older, s.manifest()} {
+		data, err := sync.SignManifest(m, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+		c.Assert(err, jc.ErrorIsNil)
+		s.putObject(c, sync.ManifestPath("released", m.Timestamp), string(data))
+	}
+	m, err := sync.ReadLatestManifest(s.storage, "released", sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, jc.DeepEquals, s.manifest())
+}
+
+func (s *manifestSuite) putManifest(c *gc.C, name string, m *sync.Manifest) {
+	data, err := sync.SignManifest(m, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+	s.putObject(c, name, string(data))
+}
+
+func (s *manifestSuite) TestReadLatestManifestMatchesStreamExactly(c *gc.C) {
+	s.putManifest(c, sync.ManifestPath("released", s.manifest().Timestamp), s.manifest())
+	other := s.manifest()
+	other.Stream = "released-foo"
+	other.Timestamp = other.Timestamp.Add(time.Hour)
+	other.Entries = nil
+	s.putManifest(c, sync.ManifestPath("released-foo", other.Timestamp), other)
+
+	m, err := sync.ReadLatestManifest(s.storage, "released", sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, jc.DeepEquals, s.manifest())
+
+	_, err = sync.ReadLatestManifest(s.storage, "released-f", sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *manifestSuite) TestImageManifestPath(c *gc.C) {
+	t := time.Date(2016, 8, 1, 12, 30, 5, 0, time.UTC)
+	c.Assert(sync.ImageManifestPath("released", t), gc.Equals, "images/manifests/released-20160801T123005Z.sjson")
+}
+
+func (s *manifestSuite) TestBuildManifest(c *gc.C) {
+	s.putObject(c, "images/streams/v1/index.json", "hello")
+	s.putObject(c, "images/streams/v1/products.json", "jello")
+	s.putObject(c, "tools/released/juju-1.8.3-quantal-amd64.tgz", "hello")
+
+	m, err := sync.BuildManifest(s.storage, "images/streams/", "released")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Stream, gc.Equals, "released")
+	c.Assert(m.Entries, jc.DeepEquals, []sync.ManifestEntry{{
+		Path:   "images/streams/v1/index.json",
+		SHA256: helloSHA256,
+		Size:   5,
+	}, {
+		Path:   "images/streams/v1/products.json",
+		SHA256: "187c9bceeb919e1b3e6d20fa50ecabf7d9d50b5343e8f9a3d912abb13929102e",
+		Size:   5,
+	}})
+}
+
+func (s *manifestSuite) TestVerifyLatestManifests(c *gc.C) {
+	s.putObject(c, "tools/released/juju-1.8.3-quantal-amd64.tgz", "hello")
+	older := s.manifest()
+	older.Timestamp = older.Timestamp.Add(-time.Hour)
+	older.Entries[0].SHA256 = "bad"
+	s.putManifest(c, sync.ManifestPath("released", older.Timestamp), older)
+	s.putManifest(c, sync.ManifestPath("released", s.manifest().Timestamp), s.manifest())
+	proposed := s.manifest()
+	proposed.Stream = "proposed"
+	s.putManifest(c, sync.ManifestPath("proposed", proposed.Timestamp), proposed)
+
+	count, err := sync.VerifyLatestManifests(s.storage, sync.ManifestDir, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+
+	s.putObject(c, "tools/released/juju-1.8.3-quantal-amd64.tgz", "jello")
+	_, err = sync.VerifyLatestManifests(s.storage, sync.ManifestDir, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, `verifying ".*" manifest: .*SHA-256 hash mismatch .*`)
+}
+
+func (s *manifestSuite) TestReadLatestManifestNotFound(c *gc.C) {
+	_, err := sync.ReadLatestManifest(s.storage, "released", sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *manifestSuite) TestVerifyManifest(c *gc.C) {
+	s.putObject(c, "tools/released/juju-1.8.3-quantal-amd64.tgz", "hello")
+	err := sync.VerifyManifest(s.manifest(), s.storage)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *manifestSuite) TestVerifyManifestHashMismatch(c *gc.C) {
+	s.putObject(c, "tools/released/juju-1.8.3-quantal-amd64.tgz", "jello")
+	err := sync.VerifyManifest(s.manifest(), s.storage)
+	c.Assert(err, gc.ErrorMatches, `"tools/released/juju-1.8.3-quantal-amd64.tgz": SHA-256 hash mismatch .*`)
+}
+
+func (s *manifestSuite) TestVerifyManifestMissingObject(c *gc.C) {
+	err := sync.VerifyManifest(s.manifest(), s.storage)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
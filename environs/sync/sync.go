@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -56,6 +57,20 @@ type SyncContext struct {
 	// Source, if non-empty, specifies a directory in the local file system
 	// to use as a source.
 	Source string
+
+	// ManifestSigningKey, if non-empty, is the armored private key used
+	// to sign a manifest of the tools copied by this sync run. The
+	// TargetToolsUploader must implement ManifestUploader.
+	ManifestSigningKey string
+
+	// ManifestPassphrase is the passphrase for ManifestSigningKey.
+	ManifestPassphrase string
+
+	// ManifestPublicKey, if non-empty, is the armored public key used to
+	// verify the latest signed manifest found in the Source directory.
+	// Any tools not recorded in that manifest with a matching hash are
+	// rejected.
+	ManifestPublicKey string
 }
 
 // ToolsFinder provides an interface for finding tools of a specified version.
@@ -74,6 +89,16 @@ type ToolsUploader interface {
 // SyncTools copies the Juju tools tarball from the official bucket
 // or a specified source directory into the user's environment.
 func SyncTools(syncContext *SyncContext) error {
+	var manifestUploader ManifestUploader
+	if syncContext.ManifestSigningKey != "" {
+		// Check that a manifest can be written before copying
+		// anything, rather than leaving unrecorded tools behind.
+		var ok bool
+		manifestUploader, ok = syncContext.TargetToolsUploader.(ManifestUploader)
+		if !ok {
+			return errors.NotSupportedf("writing sync manifests with %T", syncContext.TargetToolsUploader)
+		}
+	}
 	sourceDataSource, err := selectSourceDatasource(syncContext)
 	if err != nil {
 		return errors.Trace(err)
@@ -141,14 +166,59 @@ func SyncTools(syncContext *SyncContext) error {
 		return nil
 	}
 
-	err = copyTools(toolsDir, syncContext.Stream, missing, syncContext.TargetToolsUploader)
+	var sourceManifest *Manifest
+	if syncContext.ManifestPublicKey != "" {
+		sourceManifest, err = readSourceManifest(syncContext, toolsDir)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	entries, err := copyTools(toolsDir, syncContext.Stream, missing, syncContext.TargetToolsUploader, sourceManifest)
 	if err != nil {
 		return err
 	}
 	logger.Infof("copied %d tools", len(missing))
+	if manifestUploader != nil {
+		return writeManifest(syncContext, manifestUploader, toolsDir, entries)
+	}
 	return nil
 }
 
+// readSourceManifest reads and verifies the latest signed manifest
+// found in the local source directory.
+func readSourceManifest(syncContext *SyncContext, toolsDir string) (*Manifest, error) {
+	if syncContext.Source == "" {
+		return nil, errors.New("verifying a sync manifest requires a local source directory")
+	}
+	reader, err := filestorage.NewFileStorageReader(syncContext.Source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m, err := ReadLatestManifest(reader, toolsDir, syncContext.ManifestPublicKey)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot verify sync source")
+	}
+	logger.Infof("verified sync manifest from %v with %d entries", m.Timestamp, len(m.Entries))
+	return m, nil
+}
+
+// writeManifest signs a manifest of the given entries and stores it
+// using the given uploader.
+func writeManifest(syncContext *SyncContext, uploader ManifestUploader, toolsDir string, entries []ManifestEntry) error {
+	m := &Manifest{
+		Stream:    syncContext.Stream,
+		Timestamp: time.Now().UTC(),
+		Entries:   entries,
+	}
+	data, err := SignManifest(m, syncContext.ManifestSigningKey, syncContext.ManifestPassphrase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name := ManifestPath(toolsDir, m.Timestamp)
+	logger.Infof("writing sync manifest %v", name)
+	return errors.Trace(uploader.UploadManifest(name, data))
+}
+
 // selectSourceDatasource returns a storage reader based on the source setting.
 func selectSourceDatasource(syncContext *SyncContext) (simplestreams.DataSource, error) {
 	source := syncContext.Source
@@ -163,40 +233,62 @@ func selectSourceDatasource(syncContext *SyncContext) (simplestreams.DataSource,
 	return simplestreams.NewURLSignedDataSource("sync tools source", sourceURL, keys.JujuPublicKey, utils.VerifySSLHostnames, simplestreams.CUSTOM_CLOUD_DATA, false), nil
 }
 
-// copyTools copies a set of tools from the source to the target.
-func copyTools(toolsDir, stream string, tools []*coretools.Tools, u ToolsUploader) error {
+// copyTools copies a set of tools from the source to the target,
+// returning a manifest entry for each. If sourceManifest is non-nil,
+// each tools tarball must match its entry in that manifest.
+func copyTools(toolsDir, stream string, tools []*coretools.Tools, u ToolsUploader, sourceManifest *Manifest) ([]ManifestEntry, error) {
+	entries := make([]ManifestEntry, 0, len(tools))
 	for _, tool := range tools {
 		logger.Infof("copying %s from %s", tool.Version, tool.URL)
-		if err := copyOneToolsPackage(toolsDir, stream, tool, u); err != nil {
-			return err
+		entry, err := copyOneToolsPackage(toolsDir, stream, tool, u, sourceManifest)
+		if err != nil {
+			return nil, err
 		}
+		entries = append(entries, entry)
 	}
-	return nil
+	return entries, nil
 }
 
 // copyOneToolsPackage copies one tool from the source to the target.
-func copyOneToolsPackage(toolsDir, stream string, tools *coretools.Tools, u ToolsUploader) error {
+func copyOneToolsPackage(toolsDir, stream string, tools *coretools.Tools, u ToolsUploader, sourceManifest *Manifest) (ManifestEntry, error) {
 	toolsName := envtools.StorageName(tools.Version, toolsDir)
 	logger.Infof("downloading %q %v (%v)", stream, toolsName, tools.URL)
 	resp, err := utils.GetValidatingHTTPClient().Get(tools.URL)
 	if err != nil {
-		return err
+		return ManifestEntry{}, err
 	}
 	defer resp.Body.Close()
 	// Verify SHA-256 hash.
 	var buf bytes.Buffer
 	sha256, size, err := utils.ReadSHA256(io.TeeReader(resp.Body, &buf))
 	if err != nil {
-		return err
+		return ManifestEntry{}, err
 	}
 	if tools.SHA256 == "" {
 		logger.Errorf("no SHA-256 hash for %v", tools.SHA256) // TODO(dfc) can you spot the bug ?
 	} else if sha256 != tools.SHA256 {
-		return errors.Errorf("SHA-256 hash mismatch (%v/%v)", sha256, tools.SHA256)
+		return ManifestEntry{}, errors.Errorf("SHA-256 hash mismatch (%v/%v)", sha256, tools.SHA256)
+	}
+	if sourceManifest != nil {
+		expected, ok := sourceManifest.Entry(toolsName)
+		if !ok {
+			return ManifestEntry{}, errors.Errorf("%q not found in sync manifest", toolsName)
+		}
+		if err := checkManifestEntry(expected, sha256, size); err != nil {
+			return ManifestEntry{}, errors.Trace(err)
+		}
 	}
 	sizeInKB := (size + 512) / 1024
 	logger.Infof("uploading %v (%dkB) to model", toolsName, sizeInKB)
-	return u.UploadTools(toolsDir, stream, tools, buf.Bytes())
+	if err := u.UploadTools(toolsDir, stream, tools, buf.Bytes()); err != nil {
+		return ManifestEntry{}, err
+	}
+	return ManifestEntry{
+		Path:      toolsName,
+		SHA256:    sha256,
+		Size:      size,
+		SourceURL: tools.URL,
+	}, nil
 }
 
 // UploadFunc is the type of Upload, which may be
@@ -413,7 +505,10 @@ type StorageToolsUploader struct {
 
 func (u StorageToolsUploader) UploadTools(toolsDir, stream string, tools *coretools.Tools, data []byte) error {
 	toolsName := envtools.StorageName(tools.Version, toolsDir)
-	if err := u.Storage.Put(toolsName, bytes.NewReader(data), int64(len(data))); err != nil {
+	// Tools tarballs are large, so skip uploading them if the storage
+	// already has them, and otherwise upload them in parts where the
+	// storage allows, to avoid restarting on a flaky connection.
+	if err := storage.PutHashed(u.Storage, toolsName, tools.SHA256, bytes.NewReader(data), int64(len(data))); err != nil {
 		return err
 	}
 	if !u.WriteMetadata {
@@ -426,3 +521,8 @@ func (u StorageToolsUploader) UploadTools(toolsDir, stream string, tools *coreto
 	}
 	return nil
 }
+
+// UploadManifest is part of the ManifestUploader interface.
+func (u StorageToolsUploader) UploadManifest(name string, data []byte) error {
+	return u.Storage.Put(name, bytes.NewReader(data), int64(len(data)))
+}
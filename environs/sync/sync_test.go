@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	"github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/environs/sync"
 	envtesting "github.com/juju/juju/environs/testing"
@@ -584,3 +585,71 @@ type mockToolsFinder struct{}
 func (mockToolsFinder) FindTools(major int, stream string) (coretools.List, error) {
 	return nil, coretools.ErrNoMatches
 }
+
+func (s *syncSuite) TestSyncWithManifest(c *gc.C) {
+	s.setUpTest(c)
+	defer s.tearDownTest(c)
+
+	// Sync from the local source into an intermediate storage,
+	// recording a signed manifest of what was copied.
+	mirrorDir := c.MkDir()
+	mirror, err := filestorage.NewFileStorageWriter(mirrorDir)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sync.SyncTools(&sync.SyncContext{
+		Source:              s.localStorage,
+		TargetToolsFinder:   mockToolsFinder{},
+		TargetToolsUploader: sync.StorageToolsUploader{Storage: mirror, WriteMetadata: true},
+		ManifestSigningKey:  sstesting.SignedMetadataPrivateKey,
+		ManifestPassphrase:  sstesting.PrivateKeyPassphrase,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := sync.ReadLatestManifest(mirror, "released", sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Entries, gc.HasLen, len(v180all))
+	c.Assert(sync.VerifyManifest(m, mirror), jc.ErrorIsNil)
+
+	// Importing from the intermediate storage verifies the manifest.
+	uploader := fakeToolsUploader{
+		uploaded: make(map[version.Binary]bool),
+	}
+	err = sync.SyncTools(&sync.SyncContext{
+		Source:              mirrorDir,
+		TargetToolsFinder:   mockToolsFinder{},
+		TargetToolsUploader: &uploader,
+		ManifestPublicKey:   sstesting.SignedMetadataPublicKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uploader.uploaded, gc.HasLen, len(v180all))
+}
+
+func (s *syncSuite) TestSyncManifestNotSupported(c *gc.C) {
+	s.setUpTest(c)
+	defer s.tearDownTest(c)
+
+	uploader := fakeToolsUploader{
+		uploaded: make(map[version.Binary]bool),
+	}
+	err := sync.SyncTools(&sync.SyncContext{
+		Source:              s.localStorage,
+		TargetToolsFinder:   mockToolsFinder{},
+		TargetToolsUploader: &uploader,
+		ManifestSigningKey:  sstesting.SignedMetadataPrivateKey,
+		ManifestPassphrase:  sstesting.PrivateKeyPassphrase,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	// Nothing is copied if the manifest can't be written.
+	c.Assert(uploader.uploaded, gc.HasLen, 0)
+}
+
+func (s *syncSuite) TestSyncManifestMissing(c *gc.C) {
+	s.setUpTest(c)
+	defer s.tearDownTest(c)
+
+	err := sync.SyncTools(&sync.SyncContext{
+		Source:              s.localStorage,
+		TargetToolsFinder:   mockToolsFinder{},
+		TargetToolsUploader: &fakeToolsUploader{make(map[version.Binary]bool)},
+		ManifestPublicKey:   sstesting.SignedMetadataPublicKey,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot verify sync source: sync manifest for stream "released" not found`)
+}
@@ -21,6 +21,10 @@ const (
 	// whether a machine instance is a controller or not.
 	JujuIsController = JujuTagPrefix + "is-controller"
 
+	// JujuMachine is the tag name used for identifying the
+	// Juju machine that a machine instance was provisioned for.
+	JujuMachine = JujuTagPrefix + "machine-id"
+
 	// JujuUnitsDeployed is the tag name used for identifying
 	// the units deployed to a machine instance. The value is
 	// a space-separated list of the unit names.
@@ -519,7 +519,7 @@ func MergeAndWriteMetadata(stor storage.Storage, toolsDir, stream string, tools
 // fetchToolsHash fetches the tools from storage and calculates
 // its size in bytes and computes a SHA256 hash of its contents.
 func fetchToolsHash(stor storage.StorageReader, stream string, ver version.Binary) (size int64, sha256hash hash.Hash, err error) {
-	r, err := storage.Get(stor, StorageName(ver, stream))
+	r, err := storage.GetResuming(stor, StorageName(ver, stream))
 	if err != nil {
 		return 0, nil, err
 	}
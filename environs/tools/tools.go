@@ -81,21 +81,29 @@ type HasAgentMirror interface {
 // If minorVersion = -1, then only majorVersion is considered.
 // If no *available* tools have the supplied major.minor version number, or match the
 // supplied filter, the function returns a *NotFoundError.
-func FindTools(env environs.Environ, majorVersion, minorVersion int, stream string, filter coretools.Filter) (_ coretools.List, err error) {
+func FindTools(env environs.Environ, majorVersion, minorVersion int, stream string, filter coretools.Filter) (coretools.List, error) {
+	list, _, err := FindToolsWithSource(env, majorVersion, minorVersion, stream, filter)
+	return list, err
+}
+
+// FindToolsWithSource is like FindTools, but also returns details of
+// the simplestreams source the tools were found in, including whether
+// its metadata was signed.
+func FindToolsWithSource(env environs.Environ, majorVersion, minorVersion int, stream string, filter coretools.Filter) (_ coretools.List, _ *simplestreams.ResolveInfo, err error) {
 	var cloudSpec simplestreams.CloudSpec
 	switch env := env.(type) {
 	case simplestreams.HasRegion:
 		if cloudSpec, err = env.Region(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	case HasAgentMirror:
 		if cloudSpec, err = env.AgentMirror(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	// If only one of region or endpoint is provided, that is a problem.
 	if cloudSpec.Region != cloudSpec.Endpoint && (cloudSpec.Region == "" || cloudSpec.Endpoint == "") {
-		return nil, errors.New("cannot find agent binaries without a complete cloud configuration")
+		return nil, nil, errors.New("cannot find agent binaries without a complete cloud configuration")
 	}
 
 	logger.Infof("finding agent binaries in stream %q", stream)
@@ -118,9 +126,9 @@ func FindTools(env environs.Environ, majorVersion, minorVersion int, stream stri
 	}
 	sources, err := GetMetadataSources(env)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return FindToolsForCloud(sources, cloudSpec, stream, majorVersion, minorVersion, filter)
+	return findToolsForCloud(sources, cloudSpec, stream, majorVersion, minorVersion, filter)
 }
 
 // FindToolsForCloud returns a List containing all tools in the given stream, with a given
@@ -130,26 +138,32 @@ func FindTools(env environs.Environ, majorVersion, minorVersion int, stream stri
 // supplied filter, the function returns a *NotFoundError.
 func FindToolsForCloud(sources []simplestreams.DataSource, cloudSpec simplestreams.CloudSpec, stream string,
 	majorVersion, minorVersion int, filter coretools.Filter) (list coretools.List, err error) {
+	list, _, err = findToolsForCloud(sources, cloudSpec, stream, majorVersion, minorVersion, filter)
+	return list, err
+}
+
+func findToolsForCloud(sources []simplestreams.DataSource, cloudSpec simplestreams.CloudSpec, stream string,
+	majorVersion, minorVersion int, filter coretools.Filter) (coretools.List, *simplestreams.ResolveInfo, error) {
 
 	toolsConstraint, err := makeToolsConstraint(cloudSpec, stream, majorVersion, minorVersion, filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	toolsMetadata, _, err := Fetch(sources, toolsConstraint)
+	toolsMetadata, resolveInfo, err := Fetch(sources, toolsConstraint)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			err = ErrNoTools
 		}
-		return nil, err
+		return nil, nil, err
 	}
 	if len(toolsMetadata) == 0 {
-		return nil, coretools.ErrNoMatches
+		return nil, nil, coretools.ErrNoMatches
 	}
-	list = make(coretools.List, len(toolsMetadata))
+	list := make(coretools.List, len(toolsMetadata))
 	for i, metadata := range toolsMetadata {
 		binary, err := metadata.binary()
 		if err != nil {
-			return nil, errors.Trace(err)
+			return nil, nil, errors.Trace(err)
 		}
 		list[i] = &coretools.Tools{
 			Version: binary,
@@ -160,10 +174,10 @@ func FindToolsForCloud(sources []simplestreams.DataSource, cloudSpec simplestrea
 	}
 	if filter.Series != "" {
 		if err := checkToolsSeries(list, filter.Series); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
-	return list, err
+	return list, resolveInfo, nil
 }
 
 // FindExactTools returns only the tools that match the supplied version.
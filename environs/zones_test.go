@@ -0,0 +1,187 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type zonesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&zonesSuite{})
+
+type zone struct {
+	name      string
+	available bool
+}
+
+func (z zone) Name() string    { return z.name }
+func (z zone) Available() bool { return z.available }
+
+type zonedEnviron struct {
+	environs.ZonedEnviron
+	zones []environs.AvailabilityZone
+	err   error
+
+	instances        []instance.Instance
+	allInstancesErr  error
+	instanceZoneName func([]instance.Id) ([]string, error)
+}
+
+func (e *zonedEnviron) AvailabilityZones() ([]environs.AvailabilityZone, error) {
+	return e.zones, e.err
+}
+
+func (e *zonedEnviron) AllInstances() ([]instance.Instance, error) {
+	return e.instances, e.allInstancesErr
+}
+
+func (e *zonedEnviron) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	return e.instanceZoneName(ids)
+}
+
+type fakeInstance struct {
+	instance.Instance
+	id instance.Id
+}
+
+func (inst fakeInstance) Id() instance.Id { return inst.id }
+
+// newAllocationsEnviron returns an environ with instances inst0-2, and
+// zones az0-2 of which az0 is unavailable.
+func newAllocationsEnviron(instanceZoneName func([]instance.Id) ([]string, error)) *zonedEnviron {
+	return &zonedEnviron{
+		zones: []environs.AvailabilityZone{
+			zone{"az0", false},
+			zone{"az1", true},
+			zone{"az2", true},
+		},
+		instances: []instance.Instance{
+			fakeInstance{id: "inst0"},
+			fakeInstance{id: "inst1"},
+			fakeInstance{id: "inst2"},
+		},
+		instanceZoneName: instanceZoneName,
+	}
+}
+
+func (s *zonesSuite) TestFindAvailabilityZone(c *gc.C) {
+	env := &zonedEnviron{zones: []environs.AvailabilityZone{
+		zone{"az1", true},
+		zone{"az2", false},
+	}}
+	z, err := environs.FindAvailabilityZone(env, "az1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(z, gc.Equals, zone{"az1", true})
+
+	// Unavailable zones are still found.
+	z, err = environs.FindAvailabilityZone(env, "az2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(z.Available(), jc.IsFalse)
+}
+
+func (s *zonesSuite) TestFindAvailabilityZoneNotFound(c *gc.C) {
+	env := &zonedEnviron{zones: []environs.AvailabilityZone{zone{"az1", true}}}
+	_, err := environs.FindAvailabilityZone(env, "az3")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `invalid availability zone "az3"`)
+}
+
+func (s *zonesSuite) TestFindAvailabilityZoneError(c *gc.C) {
+	env := &zonedEnviron{err: errors.New("boom")}
+	_, err := environs.FindAvailabilityZone(env, "az1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *zonesSuite) TestAvailabilityZoneAllocationsAllInstances(c *gc.C) {
+	var called int
+	env := newAllocationsEnviron(func(ids []instance.Id) ([]string, error) {
+		c.Assert(ids, gc.DeepEquals, []instance.Id{"inst0", "inst1", "inst2"})
+		called++
+		return []string{"az0", "az1", "az2"}, nil
+	})
+	zoneInstances, err := environs.AvailabilityZoneAllocations(env, nil)
+	c.Assert(called, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	// az0 is unavailable, so az1 and az2 come out as equal best;
+	// az1 comes first due to lexicographical ordering on the name.
+	c.Assert(zoneInstances, gc.DeepEquals, []environs.AvailabilityZoneInstances{{
+		ZoneName:  "az1",
+		Instances: []instance.Id{"inst1"},
+	}, {
+		ZoneName:  "az2",
+		Instances: []instance.Id{"inst2"},
+	}})
+}
+
+func (s *zonesSuite) TestAvailabilityZoneAllocationsAllInstancesErrors(c *gc.C) {
+	env := newAllocationsEnviron(nil)
+	env.allInstancesErr = errors.New("oh noes")
+	zoneInstances, err := environs.AvailabilityZoneAllocations(env, nil)
+	c.Assert(err, gc.ErrorMatches, "oh noes")
+	c.Assert(zoneInstances, gc.HasLen, 0)
+}
+
+func (s *zonesSuite) TestAvailabilityZoneAllocationsPartialInstances(c *gc.C) {
+	group := []instance.Id{"nichts", "inst1", "null", "inst2"}
+	env := newAllocationsEnviron(func(ids []instance.Id) ([]string, error) {
+		c.Assert(ids, gc.DeepEquals, group)
+		return []string{"", "az1", "", "az1"}, environs.ErrPartialInstances
+	})
+	zoneInstances, err := environs.AvailabilityZoneAllocations(env, group)
+	c.Assert(err, jc.ErrorIsNil)
+	// az2 has fewer instances, so comes first.
+	c.Assert(zoneInstances, gc.DeepEquals, []environs.AvailabilityZoneInstances{{
+		ZoneName: "az2",
+	}, {
+		ZoneName:  "az1",
+		Instances: []instance.Id{"inst1", "inst2"},
+	}})
+}
+
+func (s *zonesSuite) TestAvailabilityZoneAllocationsInstanceAvailabilityZonesErrors(c *gc.C) {
+	env := newAllocationsEnviron(func(ids []instance.Id) ([]string, error) {
+		return nil, errors.New("whatever")
+	})
+	zoneInstances, err := environs.AvailabilityZoneAllocations(env, nil)
+	c.Assert(err, gc.ErrorMatches, "whatever")
+	c.Assert(zoneInstances, gc.HasLen, 0)
+}
+
+func (s *zonesSuite) TestAvailabilityZoneAllocationsInstanceAvailabilityZonesNoInstances(c *gc.C) {
+	env := newAllocationsEnviron(func(ids []instance.Id) ([]string, error) {
+		return nil, environs.ErrNoInstances
+	})
+	zoneInstances, err := environs.AvailabilityZoneAllocations(env, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zoneInstances, gc.HasLen, 2)
+}
+
+func (s *zonesSuite) TestAvailabilityZoneAllocationsNoZones(c *gc.C) {
+	env := newAllocationsEnviron(func(ids []instance.Id) ([]string, error) {
+		return []string{"", "", ""}, nil
+	})
+	env.zones = nil
+	zoneInstances, err := environs.AvailabilityZoneAllocations(env, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zoneInstances, gc.HasLen, 0)
+}
+
+func (s *zonesSuite) TestAvailabilityZoneAllocationsErrors(c *gc.C) {
+	env := newAllocationsEnviron(func(ids []instance.Id) ([]string, error) {
+		return []string{"", "", ""}, nil
+	})
+	env.err = errors.New("u can haz no az")
+	zoneInstances, err := environs.AvailabilityZoneAllocations(env, nil)
+	c.Assert(err, gc.ErrorMatches, "u can haz no az")
+	c.Assert(zoneInstances, gc.HasLen, 0)
+}
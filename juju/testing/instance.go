@@ -171,8 +171,8 @@ func fillinStartInstanceParams(env environs.Environ, machineId string, isControl
 		Number: agentVersion,
 		Series: preferredSeries,
 	}
-	if params.Constraints.Arch != nil {
-		filter.Arch = *params.Constraints.Arch
+	if params.Constraints.HasArch() {
+		filter.Arch = params.Constraints.Arches()[0]
 	}
 	stream := tools.PreferredStream(&agentVersion, env.Config().Development(), env.Config().AgentStream())
 	possibleTools, err := tools.FindTools(env, -1, -1, stream, filter)
@@ -219,7 +219,7 @@ func fillinStartInstanceParams(env environs.Environ, machineId string, isControl
 		instanceConfig.Jobs = []multiwatcher.MachineJob{multiwatcher.JobHostUnits, multiwatcher.JobManageModel}
 	}
 	cfg := env.Config()
-	instanceConfig.Tags = instancecfg.InstanceTags(env.Config().UUID(), params.ControllerUUID, cfg, nil)
+	instanceConfig.Tags = instancecfg.InstanceTags(env.Config().UUID(), params.ControllerUUID, machineId, cfg, nil)
 	params.Tools = possibleTools
 	params.InstanceConfig = instanceConfig
 	if params.StatusCallback == nil {
@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclienttesting
+
+import (
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/jujuclient"
+)
+
+// MemStoreSnapshot holds the contents of a MemStore at the time
+// MemStore.Snapshot was called.
+type MemStoreSnapshot struct {
+	store *MemStore
+}
+
+// Snapshot returns a snapshot of the store's contents, which may later
+// be passed to Restore to undo any changes made in the meantime.
+func (c *MemStore) Snapshot() MemStoreSnapshot {
+	return MemStoreSnapshot{c.Clone()}
+}
+
+// Restore replaces the store's contents with those recorded in the
+// given snapshot. The snapshot may be restored any number of times.
+func (c *MemStore) Restore(snapshot MemStoreSnapshot) {
+	*c = *snapshot.store.Clone()
+}
+
+// Clone returns a deep copy of the store, which may be changed without
+// affecting the original.
+func (c *MemStore) Clone() *MemStore {
+	clone := NewMemStore()
+	clone.CurrentControllerName = c.CurrentControllerName
+	for name, details := range c.Controllers {
+		details.UnresolvedAPIEndpoints = copyStrings(details.UnresolvedAPIEndpoints)
+		details.APIEndpoints = copyStrings(details.APIEndpoints)
+		clone.Controllers[name] = details
+	}
+	for name, models := range c.Models {
+		modelsCopy := &jujuclient.ControllerModels{
+			CurrentModel: models.CurrentModel,
+		}
+		if models.Models != nil {
+			modelsCopy.Models = make(map[string]jujuclient.ModelDetails)
+			for modelName, details := range models.Models {
+				modelsCopy.Models[modelName] = details
+			}
+		}
+		clone.Models[name] = modelsCopy
+	}
+	for name, details := range c.Accounts {
+		clone.Accounts[name] = details
+	}
+	for name, credential := range c.Credentials {
+		// Credentials are immutable, so only the map need be copied.
+		if credential.AuthCredentials != nil {
+			authCredentials := make(map[string]cloud.Credential)
+			for credName, cred := range credential.AuthCredentials {
+				authCredentials[credName] = cred
+			}
+			credential.AuthCredentials = authCredentials
+		}
+		clone.Credentials[name] = credential
+	}
+	for name, cfg := range c.BootstrapConfig {
+		if cfg.ControllerConfig != nil {
+			cfg.ControllerConfig = controller.Config(copyAttrs(cfg.ControllerConfig))
+		}
+		if cfg.Config != nil {
+			cfg.Config = copyAttrs(cfg.Config)
+		}
+		clone.BootstrapConfig[name] = cfg
+	}
+	return clone
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
+// copyAttrs returns a deep copy of the given attributes, copying any
+// nested maps and slices.
+func copyAttrs(attrs map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		result[k] = copyValue(v)
+	}
+	return result
+}
+
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return copyAttrs(v)
+	case map[string]string:
+		result := make(map[string]string, len(v))
+		for k, s := range v {
+			result[k] = s
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = copyValue(elem)
+		}
+		return result
+	case []string:
+		return copyStrings(v)
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclienttesting_test
+
+import (
+	stdtesting "testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type snapshotSuite struct {
+	testing.BaseSuite
+	store *jujuclienttesting.MemStore
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.Controllers["ctrl"] = jujuclient.ControllerDetails{
+		ControllerUUID: testing.ControllerTag.Id(),
+		CACert:         testing.CACert,
+		APIEndpoints:   []string{"10.0.0.1:17070"},
+	}
+	s.store.CurrentControllerName = "ctrl"
+	err := s.store.UpdateModel("ctrl", "admin/default", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetCurrentModel("ctrl", "admin/default")
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.BootstrapConfig["ctrl"] = jujuclient.BootstrapConfig{
+		Config: map[string]interface{}{
+			"name":          "controller",
+			"resource-tags": map[string]interface{}{"owner": "me"},
+		},
+	}
+}
+
+func (s *snapshotSuite) TestRestore(c *gc.C) {
+	snapshot := s.store.Snapshot()
+	original := s.store.Clone()
+
+	s.store.CurrentControllerName = ""
+	details := s.store.Controllers["ctrl"]
+	details.APIEndpoints[0] = "10.0.0.2:17070"
+	err := s.store.UpdateModel("ctrl", "admin/other", jujuclient.ModelDetails{
+		ModelUUID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.BootstrapConfig["ctrl"].Config["resource-tags"].(map[string]interface{})["owner"] = "you"
+	delete(s.store.Accounts, "ctrl")
+
+	s.store.Restore(snapshot)
+	c.Assert(s.store, jc.DeepEquals, original)
+
+	// A snapshot may be restored more than once.
+	s.store.CurrentControllerName = ""
+	s.store.Restore(snapshot)
+	c.Assert(s.store.CurrentControllerName, gc.Equals, "ctrl")
+}
+
+func (s *snapshotSuite) TestClone(c *gc.C) {
+	clone := s.store.Clone()
+	c.Assert(clone, jc.DeepEquals, s.store)
+
+	clone.Models["ctrl"].CurrentModel = "admin/other"
+	clone.BootstrapConfig["ctrl"].Config["name"] = "changed"
+	c.Assert(s.store.Models["ctrl"].CurrentModel, gc.Equals, "admin/default")
+	c.Assert(s.store.BootstrapConfig["ctrl"].Config["name"], gc.Equals, "controller")
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/constraints"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
@@ -34,6 +35,8 @@ type PrecheckBackend interface {
 	ControllerBackend() (PrecheckBackendCloser, error)
 	CloudCredential(tag names.CloudCredentialTag) (cloud.Credential, error)
 	ListPendingResources(string) ([]resource.Resource, error)
+	ModelConstraints() (constraints.Value, error)
+	HasSecrets() (bool, error)
 }
 
 // PrecheckBackendCloser adds the Close method to the standard
@@ -64,6 +67,8 @@ type PrecheckMachine interface {
 	AgentPresence() (bool, error)
 	InstanceStatus() (status.StatusInfo, error)
 	ShouldRebootOrShutdown() (state.RebootAction, error)
+	Constraints() (constraints.Value, error)
+	Tags() map[string]string
 }
 
 // PrecheckApplication describes the state interface for an
@@ -74,6 +79,8 @@ type PrecheckApplication interface {
 	CharmURL() (*charm.URL, bool)
 	AllUnits() ([]PrecheckUnit, error)
 	MinUnits() int
+	Constraints() (constraints.Value, error)
+	Tags() map[string]string
 }
 
 // PrecheckUnit describes state interface for a unit needed by
@@ -104,6 +111,21 @@ func SourcePrecheck(backend PrecheckBackend) error {
 		return errors.Trace(err)
 	}
 
+	if err := checkConstraints(backend); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := checkResourceTags(backend); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Secrets are not yet part of the model description.
+	if hasSecrets, err := backend.HasSecrets(); err != nil {
+		return errors.Annotate(err, "checking secrets")
+	} else if hasSecrets {
+		return errors.New("model has secrets, which cannot be migrated")
+	}
+
 	if cleanupNeeded, err := backend.NeedsCleanup(); err != nil {
 		return errors.Annotate(err, "checking cleanups")
 	} else if cleanupNeeded {
@@ -145,6 +167,82 @@ func checkModel(backend PrecheckBackend) error {
 	return nil
 }
 
+// checkConstraints returns an error if the model, or any machine or
+// application in it, has constraints that are not yet part of the
+// model description, as the model could not then be exported.
+func checkConstraints(backend PrecheckBackend) error {
+	cons, err := backend.ModelConstraints()
+	if err != nil {
+		return errors.Annotate(err, "retrieving model constraints")
+	}
+	if err := checkMigratableConstraints("model", cons); err != nil {
+		return errors.Trace(err)
+	}
+	machines, err := backend.AllMachines()
+	if err != nil {
+		return errors.Annotate(err, "retrieving machines")
+	}
+	for _, machine := range machines {
+		cons, err := machine.Constraints()
+		if err != nil {
+			return errors.Annotatef(err, "retrieving constraints for machine %s", machine.Id())
+		}
+		if err := checkMigratableConstraints("machine "+machine.Id(), cons); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	apps, err := backend.AllApplications()
+	if err != nil {
+		return errors.Annotate(err, "retrieving applications")
+	}
+	for _, app := range apps {
+		cons, err := app.Constraints()
+		if err != nil {
+			return errors.Annotatef(err, "retrieving constraints for application %s", app.Name())
+		}
+		if err := checkMigratableConstraints("application "+app.Name(), cons); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func checkMigratableConstraints(entity string, cons constraints.Value) error {
+	names, err := state.UnmigratableConstraints(cons)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(names) > 0 {
+		return errors.Errorf("%s has %s constraint, which cannot be migrated", entity, names[0])
+	}
+	return nil
+}
+
+// checkResourceTags returns an error if any machine or application in
+// the model has user-defined tags, as they are not yet part of the
+// model description and would be lost by the migration.
+func checkResourceTags(backend PrecheckBackend) error {
+	machines, err := backend.AllMachines()
+	if err != nil {
+		return errors.Annotate(err, "retrieving machines")
+	}
+	for _, machine := range machines {
+		if len(machine.Tags()) > 0 {
+			return errors.Errorf("machine %s has tags, which cannot be migrated", machine.Id())
+		}
+	}
+	apps, err := backend.AllApplications()
+	if err != nil {
+		return errors.Annotate(err, "retrieving applications")
+	}
+	for _, app := range apps {
+		if len(app.Tags()) > 0 {
+			return errors.Errorf("application %s has tags, which cannot be migrated", app.Name())
+		}
+	}
+	return nil
+}
+
 // TargetPrecheck checks the state of the target controller to make
 // sure that the preconditions for model migration are met. The
 // backend provided must be for the target controller.
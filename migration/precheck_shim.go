@@ -105,6 +105,15 @@ func (s *precheckShim) ListPendingResources(app string) ([]resource.Resource, er
 	return resources, nil
 }
 
+// HasSecrets implements PrecheckBackend.
+func (s *precheckShim) HasSecrets() (bool, error) {
+	secrets, err := s.State.AllSecrets()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return len(secrets) > 0, nil
+}
+
 // ControllerBackend implements PrecheckBackend.
 func (s *precheckShim) ControllerBackend() (PrecheckBackendCloser, error) {
 	model, err := s.State.ControllerModel()
@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/constraints"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/resource"
@@ -87,6 +88,27 @@ func (*SourcePrecheckSuite) TestPendingResourcesError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `checking resources: blam`)
 }
 
+func (*SourcePrecheckSuite) TestModelWithUnmigratableConstraints(c *gc.C) {
+	backend := newHappyBackend()
+	backend.modelConstraints = constraints.MustParse("gpus=1")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model has gpus constraint, which cannot be migrated")
+}
+
+func (*SourcePrecheckSuite) TestMachineWithUnmigratableConstraints(c *gc.C) {
+	backend := newHappyBackend()
+	backend.machines[1].(*fakeMachine).constraints = constraints.MustParse("gpu-type=nvidia")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "machine 1 has gpu-type constraint, which cannot be migrated")
+}
+
+func (*SourcePrecheckSuite) TestApplicationWithUnmigratableConstraints(c *gc.C) {
+	backend := newHappyBackend()
+	backend.apps[1].(*fakeApp).constraints = constraints.MustParse("mem=4G gpus=2")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "application bar has gpus constraint, which cannot be migrated")
+}
+
 func (*SourcePrecheckSuite) TestImportingModel(c *gc.C) {
 	backend := newFakeBackend()
 	backend.model.migrationMode = state.MigrationModeImporting
@@ -101,6 +123,34 @@ func (*SourcePrecheckSuite) TestCleanupsError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "checking cleanups: boom")
 }
 
+func (*SourcePrecheckSuite) TestMachineWithTags(c *gc.C) {
+	backend := newHappyBackend()
+	backend.machines[1].(*fakeMachine).tags = map[string]string{"team": "ops"}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "machine 1 has tags, which cannot be migrated")
+}
+
+func (*SourcePrecheckSuite) TestApplicationWithTags(c *gc.C) {
+	backend := newHappyBackend()
+	backend.apps[1].(*fakeApp).tags = map[string]string{"team": "ops"}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "application bar has tags, which cannot be migrated")
+}
+
+func (*SourcePrecheckSuite) TestHasSecrets(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasSecrets = true
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model has secrets, which cannot be migrated")
+}
+
+func (*SourcePrecheckSuite) TestHasSecretsError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasSecretsErr = errors.New("boom")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "checking secrets: boom")
+}
+
 func (*SourcePrecheckSuite) TestCleanupsNeeded(c *gc.C) {
 	backend := newFakeBackend()
 	backend.cleanupNeeded = true
@@ -655,6 +705,11 @@ type fakeBackend struct {
 	pendingResources    []resource.Resource
 	pendingResourcesErr error
 
+	modelConstraints constraints.Value
+
+	hasSecrets    bool
+	hasSecretsErr error
+
 	controllerBackend *fakeBackend
 }
 
@@ -703,6 +758,14 @@ func (b *fakeBackend) ListPendingResources(app string) ([]resource.Resource, err
 	return b.pendingResources, b.pendingResourcesErr
 }
 
+func (b *fakeBackend) ModelConstraints() (constraints.Value, error) {
+	return b.modelConstraints, nil
+}
+
+func (b *fakeBackend) HasSecrets() (bool, error) {
+	return b.hasSecrets, b.hasSecretsErr
+}
+
 func (b *fakeBackend) ControllerBackend() (migration.PrecheckBackendCloser, error) {
 	if b.controllerBackend == nil {
 		return b, nil
@@ -754,6 +817,8 @@ type fakeMachine struct {
 	instanceStatus status.Status
 	lost           bool
 	rebootAction   state.RebootAction
+	constraints    constraints.Value
+	tags           map[string]string
 }
 
 func (m *fakeMachine) Id() string {
@@ -805,12 +870,22 @@ func (m *fakeMachine) ShouldRebootOrShutdown() (state.RebootAction, error) {
 	return m.rebootAction, nil
 }
 
+func (m *fakeMachine) Constraints() (constraints.Value, error) {
+	return m.constraints, nil
+}
+
+func (m *fakeMachine) Tags() map[string]string {
+	return m.tags
+}
+
 type fakeApp struct {
-	name     string
-	life     state.Life
-	charmURL string
-	units    []migration.PrecheckUnit
-	minunits int
+	name        string
+	life        state.Life
+	charmURL    string
+	units       []migration.PrecheckUnit
+	minunits    int
+	constraints constraints.Value
+	tags        map[string]string
 }
 
 func (a *fakeApp) Name() string {
@@ -837,6 +912,14 @@ func (a *fakeApp) MinUnits() int {
 	return a.minunits
 }
 
+func (a *fakeApp) Constraints() (constraints.Value, error) {
+	return a.constraints, nil
+}
+
+func (a *fakeApp) Tags() map[string]string {
+	return a.tags
+}
+
 type fakeUnit struct {
 	name        string
 	version     version.Binary
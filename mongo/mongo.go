@@ -24,6 +24,7 @@ import (
 	"github.com/juju/utils/series"
 	"gopkg.in/mgo.v2"
 
+	"github.com/juju/juju/cert"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/service"
@@ -414,6 +415,10 @@ type EnsureServerParams struct {
 	// Cert is the certificate.
 	Cert string
 
+	// CertChain holds any intermediate CA certificates to be
+	// served along with the certificate.
+	CertChain string
+
 	// PrivateKey is the certificate's private key.
 	PrivateKey string
 
@@ -492,7 +497,7 @@ func ensureServer(args EnsureServerParams, mongoKernelTweaks map[string]string)
 	}
 	logVersion(mongoPath)
 
-	if err := UpdateSSLKey(args.DataDir, args.Cert, args.PrivateKey); err != nil {
+	if err := UpdateSSLKey(args.DataDir, cert.JoinChain(args.Cert, args.CertChain), args.PrivateKey); err != nil {
 		return err
 	}
 
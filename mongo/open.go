@@ -5,7 +5,6 @@ package mongo
 
 import (
 	"crypto/tls"
-	"crypto/x509"
 	stderrors "errors"
 	"fmt"
 	"net"
@@ -13,9 +12,10 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
-	"github.com/juju/utils/cert"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/cert"
 )
 
 // SocketTimeout should be long enough that even a slow mongo server
@@ -107,12 +107,10 @@ func DialInfo(info Info, opts DialOpts) (*mgo.DialInfo, error) {
 	if len(info.CACert) == 0 {
 		return nil, stderrors.New("missing CA certificate")
 	}
-	xcert, err := cert.ParseCert(info.CACert)
+	pool, err := cert.NewCertPool(info.CACert)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse CA certificate: %v", err)
 	}
-	pool := x509.NewCertPool()
-	pool.AddCert(xcert)
 	tlsConfig := utils.SecureTLSConfig()
 
 	// TODO(natefinch): revisit this when are full-time on mongo 3.
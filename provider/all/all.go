@@ -3,8 +3,10 @@
 
 package all
 
-// Register all the available providers.
+// Register all the available providers, and the datasources they
+// share.
 import (
+	_ "github.com/juju/juju/environs/modelstorage"
 	_ "github.com/juju/juju/provider/azure"
 	_ "github.com/juju/juju/provider/cloudsigma"
 	_ "github.com/juju/juju/provider/ec2"
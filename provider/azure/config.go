@@ -9,6 +9,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/arm/storage"
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/environs/config"
@@ -25,8 +26,11 @@ const (
 	resourceNameLengthMax = 80
 )
 
-var configFields = schema.Fields{
-	configAttrStorageAccountType: schema.String(),
+var configSchema = environschema.Fields{
+	configAttrStorageAccountType: {
+		Description: "The type of storage account used for machine disks.",
+		Type:        environschema.Tstring,
+	},
 }
 
 var configDefaults = schema.Defaults{
@@ -63,7 +67,7 @@ func validateConfig(newCfg, oldCfg *config.Config) (*azureModelConfig, error) {
 		return nil, err
 	}
 
-	validated, err := newCfg.ValidateUnknownAttrs(configFields, configDefaults)
+	validated, err := newCfg.ValidateProviderAttrs()
 	if err != nil {
 		return nil, err
 	}
@@ -368,6 +368,12 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported([]string{
 		constraints.CpuPower,
+		constraints.GPUs,
+		constraints.GPUType,
+		// OS disks are always created in the model's storage
+		// account, whose type is set by storage-account-type.
+		constraints.RootDiskSource,
+		constraints.Spread,
 		constraints.Tags,
 		constraints.VirtType,
 	})
@@ -379,6 +385,10 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		constraints.InstanceType,
 		instTypeNames,
 	)
+	validator.RegisterVocabulary(
+		constraints.AllocatePublicIP,
+		[]bool{true},
+	)
 	validator.RegisterConflicts(
 		[]string{constraints.InstanceType},
 		[]string{
@@ -404,7 +414,7 @@ func (env *azureEnviron) PrecheckInstance(series string, cons constraints.Value,
 		return err
 	}
 	for _, instanceType := range instanceTypes {
-		if instanceType.Name == *cons.InstanceType {
+		if cons.MatchesInstanceType(instanceType.Name) {
 			return nil
 		}
 	}
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
 	"github.com/juju/version"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -1125,6 +1126,7 @@ func (s *environSuite) TestBootstrap(c *gc.C) {
 
 	c.Assert(len(s.requests), gc.Equals, numExpectedStartInstanceRequests)
 	s.vmTags[tags.JujuIsController] = to.StringPtr("true")
+	s.vmTags[tags.JujuMachine] = to.StringPtr("0")
 	s.assertStartInstanceRequests(c, s.requests[1:], assertStartInstanceRequestsParams{
 		availabilitySetName: "juju-controller",
 		imageReference:      &quantalImageReference,
@@ -1174,6 +1176,7 @@ func (s *environSuite) TestBootstrapInstanceConstraints(c *gc.C) {
 
 	c.Assert(len(s.requests), gc.Equals, numExpectedStartInstanceRequests)
 	s.vmTags[tags.JujuIsController] = to.StringPtr("true")
+	s.vmTags[tags.JujuMachine] = to.StringPtr("0")
 	s.assertStartInstanceRequests(c, s.requests[1:], assertStartInstanceRequestsParams{
 		availabilitySetName: "juju-controller",
 		imageReference:      &quantalImageReference,
@@ -1482,12 +1485,12 @@ func (s *environSuite) TestDestroyControllerErrors(c *gc.C) {
 func (s *environSuite) TestInstanceInformation(c *gc.C) {
 	env := s.openEnviron(c)
 	s.sender = s.startInstanceSenders(false)
-	types, err := env.InstanceTypes(constraints.Value{})
+	types, err := env.InstanceTypes(context.Background(), constraints.Value{})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(types.InstanceTypes, gc.HasLen, 6)
 
 	cons := constraints.MustParse("mem=4G")
-	types, err = env.InstanceTypes(cons)
+	types, err = env.InstanceTypes(context.Background(), cons)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(types.InstanceTypes, gc.HasLen, 2)
 }
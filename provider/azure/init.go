@@ -9,6 +9,7 @@ import (
 	"github.com/juju/utils/ssh"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/azure/internal/azureauth"
 	"github.com/juju/juju/provider/azure/internal/azurestorage"
 )
@@ -40,6 +41,12 @@ func init() {
 	}
 
 	environs.RegisterProvider(providerType, environProvider)
+	if err := config.RegisterProviderSchema(providerType, config.ProviderConfigSchema{
+		Fields:   configSchema,
+		Defaults: configDefaults,
+	}); err != nil {
+		panic(err)
+	}
 
 	// TODO(axw) register an image metadata data source that queries
 	// the Azure image registry, and introduce a way to disable the
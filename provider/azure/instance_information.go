@@ -5,6 +5,7 @@ package azure
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
@@ -14,7 +15,7 @@ import (
 var _ environs.InstanceTypesFetcher = (*azureEnviron)(nil)
 
 // InstanceTypes implements InstanceTypesFetcher
-func (env *azureEnviron) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+func (env *azureEnviron) InstanceTypes(ctx context.Context, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
 	types, err := env.getInstanceTypes()
 	if err != nil {
 		return instances.InstanceTypesWithCostMetadata{}, errors.Trace(err)
@@ -168,7 +168,7 @@ func constraintHasArch(constraint *instances.InstanceConstraint, arch string) bo
 // baseline constraints that are just slightly more ambitious than that.
 func defaultToBaselineSpec(constraint constraints.Value) constraints.Value {
 	result := constraint
-	if !result.HasInstanceType() && result.Mem == nil {
+	if (!result.HasInstanceType() || result.HasInstanceTypePattern()) && result.Mem == nil {
 		var value uint64 = defaultMem
 		result.Mem = &value
 	}
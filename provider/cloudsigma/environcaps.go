@@ -9,7 +9,11 @@ import (
 
 var unsupportedConstraints = []string{
 	constraints.Container,
+	constraints.GPUs,
+	constraints.GPUType,
 	constraints.InstanceType,
+	constraints.RootDiskSource,
+	constraints.Spread,
 	constraints.Tags,
 	constraints.VirtType,
 }
@@ -19,6 +23,8 @@ var unsupportedConstraints = []string{
 func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	// Servers are created with exactly the minimum resources.
+	validator.RegisterUnsupportedRanges([]string{constraints.Cores, constraints.Mem})
 	return validator, nil
 }
 
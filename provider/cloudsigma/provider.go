@@ -54,6 +54,9 @@ func init() {
 	// package; please do *not* import individual providers anywhere else,
 	// except in direct tests for that provider.
 	environs.RegisterProvider("cloudsigma", providerInstance)
+	if err := config.RegisterProviderSchema("cloudsigma", config.ProviderConfigSchema{}); err != nil {
+		panic(err)
+	}
 	environs.RegisterImageDataSourceFunc("cloud sigma image source", getImageSource)
 }
 
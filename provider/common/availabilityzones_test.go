@@ -33,132 +33,22 @@ func (s *AvailabilityZoneSuite) SetUpSuite(c *gc.C) {
 		return allInstances, nil
 	}
 
-	availabilityZones := make([]common.AvailabilityZone, 3)
+	availabilityZones := make([]environs.AvailabilityZone, 3)
 	for i := range availabilityZones {
 		availabilityZones[i] = &mockAvailabilityZone{
 			name:      fmt.Sprintf("az%d", i),
 			available: i > 0,
 		}
 	}
-	s.env.availabilityZones = func() ([]common.AvailabilityZone, error) {
+	s.env.availabilityZones = func() ([]environs.AvailabilityZone, error) {
 		return availabilityZones, nil
 	}
 }
 
-func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsAllInstances(c *gc.C) {
-	var called int
-	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
-		c.Assert(ids, gc.DeepEquals, []instance.Id{"inst0", "inst1", "inst2"})
-		called++
-		return []string{"az0", "az1", "az2"}, nil
-	})
-	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
-	c.Assert(called, gc.Equals, 1)
-	c.Assert(err, jc.ErrorIsNil)
-	// az0 is unavailable, so az1 and az2 come out as equal best;
-	// az1 comes first due to lexicographical ordering on the name.
-	c.Assert(zoneInstances, gc.DeepEquals, []common.AvailabilityZoneInstances{{
-		ZoneName:  "az1",
-		Instances: []instance.Id{"inst1"},
-	}, {
-		ZoneName:  "az2",
-		Instances: []instance.Id{"inst2"},
-	}})
-}
-
-func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsAllInstancesErrors(c *gc.C) {
-	resultErr := fmt.Errorf("oh noes")
-	s.PatchValue(&s.env.allInstances, func() ([]instance.Instance, error) {
-		return nil, resultErr
-	})
-	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
-	c.Assert(err, gc.Equals, resultErr)
-	c.Assert(zoneInstances, gc.HasLen, 0)
-}
-
-func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsPartialInstances(c *gc.C) {
-	var called int
-	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
-		c.Assert(ids, gc.DeepEquals, []instance.Id{"nichts", "inst1", "null", "inst2"})
-		called++
-		return []string{"", "az1", "", "az1"}, environs.ErrPartialInstances
-	})
-	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, []instance.Id{"nichts", "inst1", "null", "inst2"})
-	c.Assert(called, gc.Equals, 1)
-	c.Assert(err, jc.ErrorIsNil)
-	// az2 has fewer instances, so comes first.
-	c.Assert(zoneInstances, gc.DeepEquals, []common.AvailabilityZoneInstances{{
-		ZoneName: "az2",
-	}, {
-		ZoneName:  "az1",
-		Instances: []instance.Id{"inst1", "inst2"},
-	}})
-}
-
-func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsInstanceAvailabilityZonesErrors(c *gc.C) {
-	returnErr := fmt.Errorf("whatever")
-	var called int
-	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
-		called++
-		return nil, returnErr
-	})
-	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
-	c.Assert(called, gc.Equals, 1)
-	c.Assert(err, gc.Equals, returnErr)
-	c.Assert(zoneInstances, gc.HasLen, 0)
-}
-
-func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsInstanceAvailabilityZonesNoInstances(c *gc.C) {
-	var called int
-	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
-		called++
-		return nil, environs.ErrNoInstances
-	})
-	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
-	c.Assert(called, gc.Equals, 1)
-	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(zoneInstances, gc.HasLen, 2)
-}
-
-func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsNoZones(c *gc.C) {
-	var calls []string
-	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
-		c.Assert(ids, gc.DeepEquals, []instance.Id{"inst0", "inst1", "inst2"})
-		calls = append(calls, "InstanceAvailabilityZoneNames")
-		return []string{"", "", ""}, nil
-	})
-	s.PatchValue(&s.env.availabilityZones, func() ([]common.AvailabilityZone, error) {
-		calls = append(calls, "AvailabilityZones")
-		return []common.AvailabilityZone{}, nil
-	})
-	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
-	c.Assert(calls, gc.DeepEquals, []string{"InstanceAvailabilityZoneNames", "AvailabilityZones"})
-	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(zoneInstances, gc.HasLen, 0)
-}
-
-func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsErrors(c *gc.C) {
-	var calls []string
-	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
-		c.Assert(ids, gc.DeepEquals, []instance.Id{"inst0", "inst1", "inst2"})
-		calls = append(calls, "InstanceAvailabilityZoneNames")
-		return []string{"", "", ""}, nil
-	})
-	resultErr := fmt.Errorf("u can haz no az")
-	s.PatchValue(&s.env.availabilityZones, func() ([]common.AvailabilityZone, error) {
-		calls = append(calls, "AvailabilityZones")
-		return nil, resultErr
-	})
-	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
-	c.Assert(calls, gc.DeepEquals, []string{"InstanceAvailabilityZoneNames", "AvailabilityZones"})
-	c.Assert(err, gc.Equals, resultErr)
-	c.Assert(zoneInstances, gc.HasLen, 0)
-}
-
 func (s *AvailabilityZoneSuite) TestDistributeInstancesGroup(c *gc.C) {
 	expectedGroup := []instance.Id{"0", "1", "2"}
 	var called bool
-	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ environs.ZonedEnviron, group []instance.Id) ([]environs.AvailabilityZoneInstances, error) {
 		c.Assert(group, gc.DeepEquals, expectedGroup)
 		called = true
 		return nil, nil
@@ -169,7 +59,7 @@ func (s *AvailabilityZoneSuite) TestDistributeInstancesGroup(c *gc.C) {
 
 func (s *AvailabilityZoneSuite) TestDistributeInstancesGroupErrors(c *gc.C) {
 	resultErr := fmt.Errorf("whatever")
-	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ environs.ZonedEnviron, group []instance.Id) ([]environs.AvailabilityZoneInstances, error) {
 		return nil, resultErr
 	})
 	_, err := common.DistributeInstances(&s.env, nil, nil)
@@ -177,19 +67,19 @@ func (s *AvailabilityZoneSuite) TestDistributeInstancesGroupErrors(c *gc.C) {
 }
 
 func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
-	var zoneInstances []common.AvailabilityZoneInstances
-	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+	var zoneInstances []environs.AvailabilityZoneInstances
+	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ environs.ZonedEnviron, group []instance.Id) ([]environs.AvailabilityZoneInstances, error) {
 		return zoneInstances, nil
 	})
 
 	type distributeInstancesTest struct {
-		zoneInstances []common.AvailabilityZoneInstances
+		zoneInstances []environs.AvailabilityZoneInstances
 		candidates    []instance.Id
 		eligible      []instance.Id
 	}
 
 	tests := []distributeInstancesTest{{
-		zoneInstances: []common.AvailabilityZoneInstances{{
+		zoneInstances: []environs.AvailabilityZoneInstances{{
 			ZoneName:  "az0",
 			Instances: []instance.Id{"i0"},
 		}, {
@@ -202,7 +92,7 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		candidates: []instance.Id{"i2", "i3", "i4"},
 		eligible:   []instance.Id{"i2"},
 	}, {
-		zoneInstances: []common.AvailabilityZoneInstances{{
+		zoneInstances: []environs.AvailabilityZoneInstances{{
 			ZoneName:  "az0",
 			Instances: []instance.Id{"i0"},
 		}, {
@@ -215,7 +105,7 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		candidates: []instance.Id{"i0", "i1", "i2"},
 		eligible:   []instance.Id{"i0", "i1", "i2"},
 	}, {
-		zoneInstances: []common.AvailabilityZoneInstances{{
+		zoneInstances: []environs.AvailabilityZoneInstances{{
 			ZoneName:  "az0",
 			Instances: []instance.Id{"i0"},
 		}, {
@@ -228,7 +118,7 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		candidates: []instance.Id{"i3", "i4", "i5"},
 		eligible:   []instance.Id{},
 	}, {
-		zoneInstances: []common.AvailabilityZoneInstances{{
+		zoneInstances: []environs.AvailabilityZoneInstances{{
 			ZoneName:  "az0",
 			Instances: []instance.Id{"i0"},
 		}, {
@@ -241,7 +131,7 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		candidates: []instance.Id{},
 		eligible:   []instance.Id{},
 	}, {
-		zoneInstances: []common.AvailabilityZoneInstances{},
+		zoneInstances: []environs.AvailabilityZoneInstances{},
 		candidates:    []instance.Id{"i0"},
 		eligible:      []instance.Id{},
 	}}
@@ -112,6 +112,7 @@ func (s *BootstrapSuite) TestCannotStartInstance(c *gc.C) {
 		expectedMcfg.Tags = map[string]string{
 			"juju-model-uuid":      coretesting.ModelTag.Id(),
 			"juju-controller-uuid": coretesting.ControllerTag.Id(),
+			"juju-machine-id":      "0",
 			"juju-is-controller":   "true",
 		}
 		expectedMcfg.NetBondReconfigureDelay = env.Config().NetBondReconfigureDelay()
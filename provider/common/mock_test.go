@@ -14,7 +14,6 @@ import (
 	"github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	"github.com/juju/juju/provider/common"
 	jujustorage "github.com/juju/juju/storage"
 	"github.com/juju/juju/tools"
 )
@@ -95,7 +94,7 @@ func (env *mockEnviron) StorageProvider(t jujustorage.ProviderType) (jujustorage
 	return env.storageProviders.StorageProvider(t)
 }
 
-type availabilityZonesFunc func() ([]common.AvailabilityZone, error)
+type availabilityZonesFunc func() ([]environs.AvailabilityZone, error)
 type instanceAvailabilityZoneNamesFunc func([]instance.Id) ([]string, error)
 
 type mockZonedEnviron struct {
@@ -104,7 +103,7 @@ type mockZonedEnviron struct {
 	instanceAvailabilityZoneNames instanceAvailabilityZoneNamesFunc
 }
 
-func (env *mockZonedEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *mockZonedEnviron) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	return env.availabilityZones()
 }
 
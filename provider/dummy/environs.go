@@ -14,6 +14,12 @@
 // after the environment has been opened will return
 // the error "broken environment", and will also log that.
 //
+// The "latency" property holds whitespace-separated
+// method=duration pairs, such as "StartInstance=5s
+// Bootstrap=500ms". Each named Environ method waits for
+// the given duration before doing anything else, so that
+// slow clouds can be simulated.
+//
 // The DNS name of instances is the same as the Id,
 // with ".dns" appended.
 package dummy
@@ -41,6 +47,7 @@ import (
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/series"
 	"github.com/juju/version"
+	"github.com/prometheus/client_golang/prometheus"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
@@ -59,7 +66,6 @@ import (
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/mongo/mongotest"
 	"github.com/juju/juju/network"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/pubsub/centralhub"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -179,6 +185,8 @@ type OpStartInstance struct {
 	PossibleTools    coretools.List
 	Instance         instance.Instance
 	Constraints      constraints.Value
+	Placement        string
+	Spot             *environs.SpotInstanceParams
 	SubnetsToZones   map[network.Id][]string
 	NetworkInfo      []network.InterfaceInfo
 	Volumes          []storage.Volume
@@ -268,6 +276,12 @@ var discardOperations = make(chan Operation)
 
 func init() {
 	environs.RegisterProvider("dummy", &dummy)
+	if err := config.RegisterProviderSchema("dummy", config.ProviderConfigSchema{
+		Fields:   configSchema,
+		Defaults: configDefaults,
+	}); err != nil {
+		panic(err)
+	}
 
 	// Prime the first ops channel, so that naive clients can use
 	// the testing environment by simply importing it.
@@ -288,6 +302,14 @@ var dummy = environProvider{
 	supportsSpaceDiscovery: false,
 }
 
+// latencyClock is the clock used to wait for the latency configured
+// for Environ methods. It has its own lock, as Open is called with
+// the provider locked.
+var latencyClock = struct {
+	mu  sync.Mutex
+	clk clock.Clock
+}{clk: clock.WallClock}
+
 // Reset resets the entire dummy environment and forgets any registered
 // operation listener. All opened environments after Reset will share
 // the same underlying state.
@@ -304,6 +326,7 @@ func Reset(c *gc.C) {
 	dummy.supportsSpaces = true
 	dummy.supportsSpaceDiscovery = false
 	dummy.mu.Unlock()
+	SetLatencyClock(clock.WallClock)
 
 	// NOTE(axw) we must destroy the old states without holding
 	// the provider lock, or we risk deadlocking. Destroying
@@ -444,6 +467,16 @@ func SetSupportsSpaces(supports bool) bool {
 	return current
 }
 
+// SetLatencyClock sets the clock used to wait for the latency
+// configured for Environ methods, and returns the previous clock.
+func SetLatencyClock(clk clock.Clock) clock.Clock {
+	latencyClock.mu.Lock()
+	defer latencyClock.mu.Unlock()
+	current := latencyClock.clk
+	latencyClock.clk = clk
+	return current
+}
+
 // SetSupportsSpaceDiscovery allows to enable and disable
 // SupportsSpaceDiscovery for tests.
 func SetSupportsSpaceDiscovery(supports bool) bool {
@@ -479,6 +512,10 @@ var configSchema = environschema.Fields{
 		Description: "Whitespace-separated Environ methods that should return an error when called",
 		Type:        environschema.Tstring,
 	},
+	"latency": {
+		Description: "Whitespace-separated method=duration pairs, naming Environ methods that should wait for the duration when called",
+		Type:        environschema.Tstring,
+	},
 	"secret": {
 		Description: "A secret",
 		Type:        environschema.Tstring,
@@ -495,6 +532,7 @@ var configFields = func() schema.Fields {
 
 var configDefaults = schema.Defaults{
 	"broken":     "",
+	"latency":    "",
 	"secret":     "pork",
 	"controller": false,
 }
@@ -512,6 +550,29 @@ func (c *environConfig) broken() string {
 	return c.attrs["broken"].(string)
 }
 
+// latency returns the latency configured for each Environ method.
+func (c *environConfig) latency() map[string]time.Duration {
+	latency, _ := parseLatency(c.attrs["latency"].(string))
+	return latency
+}
+
+// parseLatency parses a latency config value.
+func parseLatency(value string) (map[string]time.Duration, error) {
+	latency := make(map[string]time.Duration)
+	for _, field := range strings.Fields(value) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.NotValidf("latency %q", field)
+		}
+		d, err := time.ParseDuration(parts[1])
+		if err != nil || d < 0 {
+			return nil, errors.NotValidf("latency %q", field)
+		}
+		latency[parts[0]] = d
+	}
+	return latency, nil
+}
+
 func (c *environConfig) secret() string {
 	return c.attrs["secret"].(string)
 }
@@ -583,6 +644,9 @@ func (p *environProvider) Validate(cfg, old *config.Config) (valid *config.Confi
 	if err != nil {
 		return nil, err
 	}
+	if _, err := parseLatency(validated["latency"].(string)); err != nil {
+		return nil, err
+	}
 	// Apply the coerced unknown values back into the config.
 	return cfg.Apply(validated)
 }
@@ -647,7 +711,10 @@ func (e *environ) ecfg() *environConfig {
 	return ecfg
 }
 
+// checkBroken waits for any latency configured for the method, and
+// then returns an error if the method is configured as broken.
 func (e *environ) checkBroken(method string) error {
+	e.simulateLatency(method)
 	for _, m := range strings.Fields(e.ecfg().broken()) {
 		if m == method {
 			return fmt.Errorf("dummy.%s is broken", method)
@@ -656,6 +723,20 @@ func (e *environ) checkBroken(method string) error {
 	return nil
 }
 
+// simulateLatency waits for the latency configured for the method,
+// if any.
+func (e *environ) simulateLatency(method string) {
+	d := e.ecfg().latency()[method]
+	if d == 0 {
+		return
+	}
+	latencyClock.mu.Lock()
+	clk := latencyClock.clk
+	latencyClock.mu.Unlock()
+	logger.Debugf("dummy.%s waiting %v", method, d)
+	<-clk.After(d)
+}
+
 // PrecheckInstance is specified in the state.Prechecker interface.
 func (*environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
 	if placement != "" && placement != "valid" {
@@ -664,6 +745,14 @@ func (*environ) PrecheckInstance(series string, cons constraints.Value, placemen
 	return nil
 }
 
+// CheckSpotInstanceParams is specified in the environs.SpotInstancer
+// interface. The dummy provider accepts any spot parameters; tests can
+// simulate the eviction of a spot instance by setting its status to
+// status.Evicted with SetInstanceStatus.
+func (e *environ) CheckSpotInstanceParams(environs.SpotInstanceParams) error {
+	return e.checkBroken("CheckSpotInstanceParams")
+}
+
 // Create is part of the Environ interface.
 func (e *environ) Create(args environs.CreateParams) error {
 	dummy.mu.Lock()
@@ -833,6 +922,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 						io.WriteString(w, "gazing")
 					}))
 				},
+				PrometheusGatherer: prometheus.NewRegistry(),
 			})
 			if err != nil {
 				panic(err)
@@ -1015,7 +1105,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		// We will just assume the instance hardware characteristics exactly matches
 		// the supplied constraints (if specified).
 		hc = &instance.HardwareCharacteristics{
-			Arch:     args.Constraints.Arch,
+			Arch:     preferredArch(args.Constraints),
 			Mem:      args.Constraints.Mem,
 			RootDisk: args.Constraints.RootDisk,
 			CpuCores: args.Constraints.CpuCores,
@@ -1078,6 +1168,8 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		MachineNonce:     args.InstanceConfig.MachineNonce,
 		PossibleTools:    args.Tools,
 		Constraints:      args.Constraints,
+		Placement:        args.Placement,
+		Spot:             args.Spot,
 		SubnetsToZones:   subnetsToZones,
 		Volumes:          volumes,
 		Instance:         i,
@@ -1093,6 +1185,16 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}, nil
 }
 
+// preferredArch returns the first of the architectures named in the
+// given constraints, or the arch constraint itself if none are named.
+func preferredArch(cons constraints.Value) *string {
+	if !cons.HasArch() {
+		return cons.Arch
+	}
+	preferred := cons.Arches()[0]
+	return &preferred
+}
+
 func (e *environ) StopInstances(ids ...instance.Id) error {
 	defer delay()
 	if err := e.checkBroken("StopInstance"); err != nil {
@@ -1270,9 +1372,9 @@ func (az azShim) Available() bool {
 }
 
 // AvailabilityZones implements environs.ZonedEnviron.
-func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	// TODO(dimitern): Fix this properly.
-	return []common.AvailabilityZone{
+	return []environs.AvailabilityZone{
 		azShim{"zone1", true},
 		azShim{"zone2", false},
 	}, nil
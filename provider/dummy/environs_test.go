@@ -220,6 +220,58 @@ func (s *suite) breakMethods(c *gc.C, e environs.NetworkingEnviron, names ...str
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *suite) setLatency(c *gc.C, e environs.Environ, latency string) error {
+	cfg, err := e.Config().Apply(map[string]interface{}{
+		"latency": latency,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return e.SetConfig(cfg)
+}
+
+func (s *suite) TestLatency(c *gc.C) {
+	e := s.bootstrapTestEnviron(c)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+	clock := gitjujutesting.NewClock(testing.ZeroTime())
+	defer dummy.SetLatencyClock(dummy.SetLatencyClock(clock))
+
+	err := s.setLatency(c, e, "StartInstance=1m Bootstrap=1h")
+	c.Assert(err, jc.ErrorIsNil)
+
+	started := make(chan error, 1)
+	go func() {
+		_, _, _, err := jujutesting.StartInstance(e, s.ControllerUUID, "0")
+		started <- err
+	}()
+	select {
+	case <-started:
+		c.Fatalf("instance started without waiting")
+	case <-time.After(testing.ShortWait):
+	}
+	err = clock.WaitAdvance(time.Minute, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-started:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for instance to start")
+	}
+}
+
+func (s *suite) TestLatencyInvalid(c *gc.C) {
+	e := s.bootstrapTestEnviron(c)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+	for _, latency := range []string{"StartInstance", "=1s", "StartInstance=soon", "StartInstance=-1s"} {
+		err := s.setLatency(c, e, latency)
+		c.Check(err, gc.ErrorMatches, `latency ".*" not valid`)
+	}
+}
+
 func (s *suite) TestNetworkInterfaces(c *gc.C) {
 	e := s.bootstrapTestEnviron(c)
 	defer func() {
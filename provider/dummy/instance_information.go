@@ -5,6 +5,7 @@ package dummy
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
@@ -14,7 +15,7 @@ import (
 var _ environs.InstanceTypesFetcher = (*environ)(nil)
 
 // InstanceTypes implements InstanceTypesFetcher
-func (e *environ) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+func (e *environ) InstanceTypes(ctx context.Context, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
 	result := instances.InstanceTypesWithCostMetadata{}
 	return result, errors.NotSupportedf("InstanceTypes")
 }
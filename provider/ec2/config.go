@@ -88,7 +88,7 @@ func validateConfig(cfg, old *config.Config) (*environConfig, error) {
 	if err := config.Validate(cfg, old); err != nil {
 		return nil, err
 	}
-	validated, err := cfg.ValidateUnknownAttrs(configFields, configDefaults)
+	validated, err := cfg.ValidateProviderAttrs()
 	if err != nil {
 		return nil, err
 	}
@@ -137,14 +137,14 @@ var configTests = []configTest{
 		config: attrs{
 			"vpc-id": 42,
 		},
-		err:        `.*expected string, got int\(42\)`,
+		err:        `.*vpc-id must be a string, got integer`,
 		vpcID:      "",
 		forceVPCID: false,
 	}, {
 		config: attrs{
 			"vpc-id-force": "nonsense",
 		},
-		err:        `.*expected bool, got string\("nonsense"\)`,
+		err:        `.*vpc-id-force must be a bool, got string`,
 		vpcID:      "",
 		forceVPCID: false,
 	}, {
@@ -152,7 +152,7 @@ var configTests = []configTest{
 			"vpc-id":       "vpc-anything",
 			"vpc-id-force": 999,
 		},
-		err:        `.*expected bool, got int\(999\)`,
+		err:        `.*vpc-id-force must be a bool, got integer`,
 		vpcID:      "",
 		forceVPCID: false,
 	}, {
@@ -176,6 +176,14 @@ var configTests = []configTest{
 		},
 		vpcID:      "vpc-some-id",
 		forceVPCID: true,
+	}, {
+		// As set by "juju model-config vpc-id-force=true".
+		config: attrs{
+			"vpc-id":       "vpc-some-id",
+			"vpc-id-force": "true",
+		},
+		vpcID:      "vpc-some-id",
+		forceVPCID: true,
 	}, {
 		config: attrs{
 			"vpc-id":       "vpc-abcd",
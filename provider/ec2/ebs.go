@@ -878,6 +878,25 @@ func minRootDiskSizeMiB(series string) uint64 {
 	return gibToMib(common.MinRootDiskSizeGiB(series))
 }
 
+// rootDiskSources holds the values accepted for the root-disk-source
+// constraint, which name the EBS volume type of the root disk.
+var rootDiskSources = []string{volumeTypeMagnetic, volumeTypeSSD}
+
+// rootDiskVolumeType returns the EBS volume type to use for the root
+// disk, or an empty string if the default volume type should be used.
+func rootDiskVolumeType(cons constraints.Value) string {
+	if !cons.HasRootDiskSource() {
+		return ""
+	}
+	switch *cons.RootDiskSource {
+	case volumeTypeMagnetic:
+		return volumeTypeStandard
+	case volumeTypeSSD:
+		return volumeTypeGP2
+	}
+	return ""
+}
+
 // getBlockDeviceMappings translates constraints into BlockDeviceMappings.
 //
 // The first entry is always the root disk mapping, followed by instance
@@ -907,6 +926,7 @@ func getBlockDeviceMappings(
 	blockDeviceMappings := []ec2.BlockDeviceMapping{{
 		DeviceName: rootDiskDeviceName,
 		VolumeSize: int64(mibToGib(rootDiskSizeMiB)),
+		VolumeType: rootDiskVolumeType(cons),
 	}}
 
 	// Not all machines have this many instance stores.
@@ -68,7 +68,7 @@ type environ struct {
 	ecfgUnlocked *environConfig
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []environs.AvailabilityZone
 
 	defaultVPCMutex   sync.Mutex
 	defaultVPCChecked bool
@@ -152,11 +152,12 @@ func (e *environ) SupportsSpaceDiscovery() (bool, error) {
 
 var unsupportedConstraints = []string{
 	constraints.Tags,
-	// TODO(anastasiamac 2016-03-16) LP#1557874
-	// use virt-type in StartInstances
-	constraints.VirtType,
 }
 
+// supportedVirtTypes holds the virtualisation types of EC2 instance
+// types and images.
+var supportedVirtTypes = []string{"hvm", "pv"}
+
 // ConstraintsValidator is defined on the Environs interface.
 func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
@@ -173,16 +174,23 @@ func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 		instTypeNames[i] = itype.Name
 	}
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
+	validator.RegisterVocabulary(constraints.GPUType, ec2instancetypes.GPUTypes())
+	validator.RegisterVocabulary(constraints.VirtType, supportedVirtTypes)
+	validator.RegisterVocabulary(constraints.RootDiskSource, rootDiskSources)
+	// Instances are always started with a public address.
+	validator.RegisterVocabulary(constraints.AllocatePublicIP, []bool{true})
 	return validator, nil
 }
 
-func archMatches(arches []string, arch *string) bool {
-	if arch == nil {
+func archMatches(arches []string, wanted []string) bool {
+	if len(wanted) == 0 {
 		return true
 	}
 	for _, a := range arches {
-		if a == *arch {
-			return true
+		for _, w := range wanted {
+			if a == w {
+				return true
+			}
 		}
 	}
 	return false
@@ -204,7 +212,7 @@ func (z *ec2AvailabilityZone) Available() bool {
 
 // AvailabilityZones returns a slice of availability zones
 // for the configured region.
-func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -215,7 +223,7 @@ func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
 			return nil, err
 		}
 		logger.Debugf("availability zones: %+v", resp)
-		e.availabilityZones = make([]common.AvailabilityZone, len(resp.Zones))
+		e.availabilityZones = make([]environs.AvailabilityZone, len(resp.Zones))
 		for i, z := range resp.Zones {
 			e.availabilityZones[i] = &ec2AvailabilityZone{z}
 		}
@@ -252,20 +260,13 @@ func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
 	}
 	switch key, value := placement[:pos], placement[pos+1:]; key {
 	case "zone":
-		availabilityZone := value
-		zones, err := e.AvailabilityZones()
+		zone, err := environs.FindAvailabilityZone(e, value)
 		if err != nil {
 			return nil, err
 		}
-		for _, z := range zones {
-			if z.Name() == availabilityZone {
-				ec2AZ := z.(*ec2AvailabilityZone)
-				return &ec2Placement{
-					availabilityZone: &ec2AZ.AvailabilityZoneInfo,
-				}, nil
-			}
-		}
-		return nil, fmt.Errorf("invalid availability zone %q", availabilityZone)
+		return &ec2Placement{
+			availabilityZone: &zone.(*ec2AvailabilityZone).AvailabilityZoneInfo,
+		}, nil
 	case "subnet":
 		logger.Debugf("searching for subnet matching placement directive %q", value)
 		matcher := CreateSubnetMatcher(value)
@@ -317,10 +318,10 @@ func (e *environ) PrecheckInstance(series string, cons constraints.Value, placem
 		return errors.Trace(err)
 	}
 	for _, itype := range instanceTypes {
-		if itype.Name != *cons.InstanceType {
+		if !cons.MatchesInstanceType(itype.Name) {
 			continue
 		}
-		if archMatches(itype.Arches, cons.Arch) {
+		if archMatches(itype.Arches, cons.Arches()) {
 			return nil
 		}
 	}
@@ -371,7 +372,7 @@ func (e *environ) DistributeInstances(candidates, distributionGroup []instance.I
 	return common.DistributeInstances(e, candidates, distributionGroup)
 }
 
-var availabilityZoneAllocations = common.AvailabilityZoneAllocations
+var availabilityZoneAllocations = environs.AvailabilityZoneAllocations
 
 // MaintainInstance is specified in the InstanceBroker interface.
 func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
@@ -424,7 +425,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 	// If no availability zone is specified, then automatically spread across
 	// the known zones for optimal spread across the instance distribution
 	// group.
-	var zoneInstances []common.AvailabilityZoneInstances
+	var zoneInstances []environs.AvailabilityZoneInstances
 	if len(availabilityZones) == 0 {
 		var err error
 		var group []instance.Id
@@ -104,6 +104,23 @@ func (*Suite) TestRootDiskBlockDeviceMapping(c *gc.C) {
 	}
 }
 
+func (*Suite) TestRootDiskSourceBlockDeviceMapping(c *gc.C) {
+	for source, volumeType := range map[string]string{
+		"":         "",
+		"magnetic": "standard",
+		"ssd":      "gp2",
+	} {
+		c.Logf("root-disk-source=%s", source)
+		cons := constraints.Value{RootDiskSource: &source}
+		mappings := getBlockDeviceMappings(cons, "trusty", false)
+		c.Assert(mappings[0], gc.DeepEquals, amzec2.BlockDeviceMapping{
+			VolumeSize: 8,
+			DeviceName: "/dev/sda1",
+			VolumeType: volumeType,
+		})
+	}
+}
+
 func pInt(i uint64) *uint64 {
 	return &i
 }
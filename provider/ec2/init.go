@@ -3,7 +3,10 @@
 
 package ec2
 
-import "github.com/juju/juju/environs"
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
 
 const (
 	providerType = "ec2"
@@ -11,4 +14,10 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, environProvider{})
+	if err := config.RegisterProviderSchema(providerType, config.ProviderConfigSchema{
+		Fields:   configSchema,
+		Defaults: configDefaults,
+	}); err != nil {
+		panic(err)
+	}
 }
@@ -5,16 +5,20 @@ package ec2
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/instances"
 )
 
-var _ environs.InstanceTypesFetcher = (*environ)(nil)
+var (
+	_ environs.InstanceTypesFetcher = (*environ)(nil)
+	_ environs.CostEstimator        = (*environ)(nil)
+)
 
 // InstanceTypes implements InstanceTypesFetcher
-func (e *environ) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+func (e *environ) InstanceTypes(ctx context.Context, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
 	iTypes, err := e.supportedInstanceTypes()
 	if err != nil {
 		return instances.InstanceTypesWithCostMetadata{}, errors.Trace(err)
@@ -29,3 +33,19 @@ func (e *environ) InstanceTypes(c constraints.Value) (instances.InstanceTypesWit
 		CostDivisor:   1000,
 		CostCurrency:  "USD"}, nil
 }
+
+// EstimateCost implements environs.CostEstimator. The estimate is based
+// on the cheapest instance type matching the constraints.
+func (e *environ) EstimateCost(ctx context.Context, c constraints.Value) (environs.CostEstimate, error) {
+	iTypes, err := e.InstanceTypes(ctx, c)
+	if err != nil {
+		return environs.CostEstimate{}, errors.Trace(err)
+	}
+	// Matching instance types are sorted by increasing cost.
+	iType := iTypes.InstanceTypes[0]
+	return environs.CostEstimate{
+		InstanceType: iType.Name,
+		HourlyCost:   float64(iType.Cost) / float64(iTypes.CostDivisor),
+		Currency:     iTypes.CostCurrency,
+	}, nil
+}
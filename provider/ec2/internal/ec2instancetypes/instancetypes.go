@@ -6,6 +6,7 @@ package ec2instancetypes
 //go:generate go run process_cost_data.go -o generated.go index.json
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/juju/juju/environs/instances"
@@ -21,7 +22,53 @@ func RegionInstanceTypes(region string) []instances.InstanceType {
 	if !ok {
 		instanceTypes = allInstanceTypes["us-east-1"]
 	}
-	return instanceTypes
+	return withGPUs(instanceTypes)
+}
+
+// gpuInfo describes the GPUs attached to an instance type.
+type gpuInfo struct {
+	count   uint64
+	gpuType string
+}
+
+// gpuInstanceTypes records the GPUs attached to each instance type
+// that has them. The cost data from which the instance types are
+// generated does not describe GPUs, so they are maintained by hand.
+var gpuInstanceTypes = map[string]gpuInfo{
+	"cg1.4xlarge": {2, "nvidia-m2050"},
+	"g2.2xlarge":  {1, "nvidia-k520"},
+	"g2.8xlarge":  {4, "nvidia-k520"},
+	"p2.xlarge":   {1, "nvidia-k80"},
+	"p2.8xlarge":  {8, "nvidia-k80"},
+	"p2.16xlarge": {16, "nvidia-k80"},
+}
+
+// GPUTypes returns the names of the GPU types available in EC2.
+func GPUTypes() []string {
+	seen := make(map[string]bool)
+	var gpuTypes []string
+	for _, info := range gpuInstanceTypes {
+		if !seen[info.gpuType] {
+			seen[info.gpuType] = true
+			gpuTypes = append(gpuTypes, info.gpuType)
+		}
+	}
+	sort.Strings(gpuTypes)
+	return gpuTypes
+}
+
+// withGPUs returns a copy of the given instance types with their
+// GPU details filled in.
+func withGPUs(instanceTypes []instances.InstanceType) []instances.InstanceType {
+	result := make([]instances.InstanceType, len(instanceTypes))
+	for i, itype := range instanceTypes {
+		if info, ok := gpuInstanceTypes[itype.Name]; ok {
+			itype.GPUs = info.count
+			itype.GPUType = info.gpuType
+		}
+		result[i] = itype
+	}
+	return result
 }
 
 // SupportsClassic reports whether the instance type with the given
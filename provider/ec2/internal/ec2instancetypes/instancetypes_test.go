@@ -110,3 +110,27 @@ func (s *InstanceTypesSuite) TestSupportsClassic(c *gc.C) {
 	assertDoesNotSupportClassic("t2.medium")
 	assertDoesNotSupportClassic("x1.32xlarge")
 }
+
+func (s *InstanceTypesSuite) TestRegionInstanceTypesGPUs(c *gc.C) {
+	gpus := make(map[string]uint64)
+	for _, instanceType := range ec2instancetypes.RegionInstanceTypes("us-east-1") {
+		if instanceType.GPUs > 0 {
+			gpus[instanceType.Name] = instanceType.GPUs
+			c.Assert(instanceType.GPUType, gc.Not(gc.Equals), "")
+		}
+	}
+	c.Assert(gpus, jc.DeepEquals, map[string]uint64{
+		"cg1.4xlarge": 2,
+		"g2.2xlarge":  1,
+		"g2.8xlarge":  4,
+		"p2.xlarge":   1,
+		"p2.8xlarge":  8,
+		"p2.16xlarge": 16,
+	})
+}
+
+func (s *InstanceTypesSuite) TestGPUTypes(c *gc.C) {
+	c.Assert(ec2instancetypes.GPUTypes(), jc.DeepEquals, []string{
+		"nvidia-k520", "nvidia-k80", "nvidia-m2050",
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/utils/set"
 	"github.com/juju/utils/ssh"
 	"github.com/juju/version"
+	"golang.org/x/net/context"
 	"gopkg.in/amz.v3/aws"
 	amzec2 "gopkg.in/amz.v3/ec2"
 	"gopkg.in/amz.v3/ec2/ec2test"
@@ -43,7 +44,6 @@ import (
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/jujuclient/jujuclienttesting"
 	"github.com/juju/juju/network"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/ec2"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/storage"
@@ -820,7 +820,7 @@ func (t *localServerSuite) TestGetAvailabilityZones(c *gc.C) {
 		}
 		return resp, resultErr
 	})
-	env := t.Prepare(c).(common.ZonedEnviron)
+	env := t.Prepare(c).(environs.ZonedEnviron)
 
 	resultErr = fmt.Errorf("failed to get availability zones")
 	zones, err := env.AvailabilityZones()
@@ -854,7 +854,7 @@ func (t *localServerSuite) TestGetAvailabilityZonesCommon(c *gc.C) {
 		}
 		return resp, nil
 	})
-	env := t.Prepare(c).(common.ZonedEnviron)
+	env := t.Prepare(c).(environs.ZonedEnviron)
 	resultZones = make([]amzec2.AvailabilityZoneInfo, 2)
 	resultZones[0].Name = "az1"
 	resultZones[1].Name = "az2"
@@ -871,13 +871,13 @@ func (t *localServerSuite) TestGetAvailabilityZonesCommon(c *gc.C) {
 
 type mockAvailabilityZoneAllocations struct {
 	group  []instance.Id // input param
-	result []common.AvailabilityZoneInstances
+	result []environs.AvailabilityZoneInstances
 	err    error
 }
 
 func (t *mockAvailabilityZoneAllocations) AvailabilityZoneAllocations(
-	e common.ZonedEnviron, group []instance.Id,
-) ([]common.AvailabilityZoneInstances, error) {
+	e environs.ZonedEnviron, group []instance.Id,
+) ([]environs.AvailabilityZoneInstances, error) {
 	t.group = group
 	return t.result, t.err
 }
@@ -886,7 +886,7 @@ func (t *localServerSuite) TestStartInstanceDistributionParams(c *gc.C) {
 	env := t.prepareAndBootstrap(c)
 
 	mock := mockAvailabilityZoneAllocations{
-		result: []common.AvailabilityZoneInstances{{ZoneName: "az1"}},
+		result: []environs.AvailabilityZoneInstances{{ZoneName: "az1"}},
 	}
 	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
 
@@ -984,7 +984,7 @@ func (t *localServerSuite) testStartInstanceAvailZoneAllConstrained(c *gc.C, run
 	env := t.prepareAndBootstrap(c)
 
 	mock := mockAvailabilityZoneAllocations{
-		result: []common.AvailabilityZoneInstances{
+		result: []environs.AvailabilityZoneInstances{
 			{ZoneName: "az1"}, {ZoneName: "az2"},
 		},
 	}
@@ -1148,7 +1148,7 @@ func (t *localServerSuite) testStartInstanceAvailZoneOneConstrained(c *gc.C, run
 	env := t.prepareAndBootstrap(c)
 
 	mock := mockAvailabilityZoneAllocations{
-		result: []common.AvailabilityZoneInstances{
+		result: []environs.AvailabilityZoneInstances{
 			{ZoneName: "az1"}, {ZoneName: "az2"},
 		},
 	}
@@ -1172,6 +1172,45 @@ func (t *localServerSuite) testStartInstanceAvailZoneOneConstrained(c *gc.C, run
 	c.Check(*hwc.AvailabilityZone, gc.Equals, "az2")
 }
 
+func (t *localServerSuite) TestStartInstanceSpreadZoneFallsBack(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	// az1 holds the fewest instances of the distribution group, but
+	// is constrained, so the instance is started in az2.
+	group := []instance.Id{"i-0", "i-1"}
+	mock := mockAvailabilityZoneAllocations{
+		result: []environs.AvailabilityZoneInstances{
+			{ZoneName: "az1"},
+			{ZoneName: "az2", Instances: group[:1]},
+			{ZoneName: "az3", Instances: group[1:]},
+		},
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+	var azArgs []string
+	realRunInstances := *ec2.RunInstances
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances, c environs.StatusCallbackFunc) (*amzec2.RunInstancesResp, error) {
+		azArgs = append(azArgs, ri.AvailZone)
+		if len(azArgs) == 1 {
+			return nil, azConstrainedErr
+		}
+		return realRunInstances(e, ri, fakeCallback)
+	})
+
+	params := environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		Constraints:    constraints.MustParse("spread=zone"),
+		DistributionGroup: func() ([]instance.Id, error) {
+			return group, nil
+		},
+		StatusCallback: fakeCallback,
+	}
+	result, err := testing.StartInstanceWithParams(env, "1", params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mock.group, gc.DeepEquals, group)
+	c.Assert(azArgs, gc.DeepEquals, []string{"az1", "az2"})
+	c.Assert(ec2.InstanceEC2(result.Instance).AvailZone, gc.Equals, "az2")
+}
+
 func (t *localServerSuite) TestAddresses(c *gc.C) {
 	env := t.prepareAndBootstrap(c)
 	inst, _ := testing.AssertStartInstance(c, env, t.ControllerUUID, "1")
@@ -1200,10 +1239,28 @@ func (t *localServerSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	env := t.Prepare(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=foo virt-type=kvm")
+	cons := constraints.MustParse("arch=amd64 tags=foo virt-type=hvm")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"tags", "virt-type"})
+	c.Assert(unsupported, jc.SameContents, []string{"tags"})
+}
+
+func (t *localServerSuite) TestConstraintsValidatorVirtTypeVocab(c *gc.C) {
+	env := t.Prepare(c)
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("virt-type=kvm"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: virt-type=kvm\nvalid values are: \\[hvm pv\\]")
+}
+
+func (t *localServerSuite) TestConstraintsValidatorAllocatePublicIPVocab(c *gc.C) {
+	env := t.Prepare(c)
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("allocate-public-ip=true"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("allocate-public-ip=false"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: allocate-public-ip=false\nvalid values are: \\[true\\]")
 }
 
 func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -1215,6 +1272,16 @@ func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: instance-type=foo\nvalid values are:.*")
 }
 
+func (t *localServerSuite) TestConstraintsValidatorGPUTypeVocab(c *gc.C) {
+	env := t.Prepare(c)
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("gpus=1 gpu-type=nvidia-k80"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("gpu-type=voodoo"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: gpu-type=voodoo\nvalid values are:.*")
+}
+
 func (t *localServerSuite) TestConstraintsValidatorVocabNoDefaultOrSpecifiedVPC(c *gc.C) {
 	t.srv.defaultVPC.IsDefault = false
 	err := t.srv.ec2srv.UpdateVPC(*t.srv.defaultVPC)
@@ -1439,12 +1506,12 @@ func (t *localServerSuite) TestInstanceInformation(c *gc.C) {
 	// TODO(macgreagoir) Where do these magic length numbers come from?
 	c.Skip("Hard-coded InstanceTypes counts without explanation")
 	env := t.prepareEnviron(c)
-	types, err := env.InstanceTypes(constraints.Value{})
+	types, err := env.InstanceTypes(context.Background(), constraints.Value{})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(types.InstanceTypes, gc.HasLen, 53)
 
 	cons := constraints.MustParse("mem=4G")
-	types, err = env.InstanceTypes(cons)
+	types, err = env.InstanceTypes(context.Background(), cons)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(types.InstanceTypes, gc.HasLen, 48)
 }
@@ -1515,6 +1582,7 @@ func (t *localServerSuite) TestInstanceTags(c *gc.C) {
 		{"Name", "juju-sample-machine-0"},
 		{"juju-model-uuid", coretesting.ModelTag.Id()},
 		{"juju-controller-uuid", t.ControllerUUID},
+		{"juju-machine-id", "0"},
 		{"juju-is-controller", "true"},
 	})
 }
@@ -49,7 +49,7 @@ func newConfig(cfg, old *config.Config) (*environConfig, error) {
 	if err := config.Validate(cfg, old); err != nil {
 		return nil, errors.Trace(err)
 	}
-	attrs, err := cfg.ValidateUnknownAttrs(configFields, configDefaults)
+	attrs, err := cfg.ValidateProviderAttrs()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -8,18 +8,17 @@ import (
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/gce/google"
 )
 
 // AvailabilityZones returns all availability zones in the environment.
-func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	zones, err := env.gce.AvailabilityZones(env.cloud.Region)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	var result []common.AvailabilityZone
+	var result []environs.AvailabilityZone
 	for _, zone := range zones {
 		if zone.Deprecated() {
 			continue
@@ -77,7 +76,7 @@ func (env *environ) availZoneUp(name string) (*google.AvailabilityZone, error) {
 	return zone, nil
 }
 
-var availabilityZoneAllocations = common.AvailabilityZoneAllocations
+var availabilityZoneAllocations = environs.AvailabilityZoneAllocations
 
 // parseAvailabilityZones returns the availability zones that should be
 // tried for the given instance spec. If a placement argument was
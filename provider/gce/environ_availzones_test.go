@@ -8,8 +8,8 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/gce"
 	"github.com/juju/juju/provider/gce/google"
 )
@@ -77,7 +77,7 @@ func (s *environAZSuite) TestInstanceAvailabilityZoneNamesAPIs(c *gc.C) {
 }
 
 func (s *environAZSuite) TestParseAvailabilityZones(c *gc.C) {
-	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+	s.FakeCommon.AZInstances = []environs.AvailabilityZoneInstances{{
 		ZoneName:  "home-zone",
 		Instances: []instance.Id{s.Instance.Id()},
 	}}
@@ -90,7 +90,7 @@ func (s *environAZSuite) TestParseAvailabilityZones(c *gc.C) {
 
 func (s *environAZSuite) TestParseAvailabilityZonesAPI(c *gc.C) {
 	ids := []instance.Id{s.Instance.Id()}
-	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+	s.FakeCommon.AZInstances = []environs.AvailabilityZoneInstances{{
 		ZoneName:  "home-zone",
 		Instances: ids,
 	}}
@@ -152,7 +152,7 @@ func (s *environAZSuite) TestParseAvailabilityZonesPlacementUnavailable(c *gc.C)
 }
 
 func (s *environAZSuite) TestParseAvailabilityZonesDistGroup(c *gc.C) {
-	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+	s.FakeCommon.AZInstances = []environs.AvailabilityZoneInstances{{
 		ZoneName:  "home-zone",
 		Instances: []instance.Id{s.Instance.Id()},
 	}}
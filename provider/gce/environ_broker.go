@@ -33,6 +33,16 @@ func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
 	return nil
 }
 
+var _ environs.SpotInstancer = (*environ)(nil)
+
+// CheckSpotInstanceParams is specified in the environs.SpotInstancer
+// interface. Spot instances are started as GCE preemptible instances.
+// Preemptible capacity is sold at a fixed price rather than by bid,
+// so any maximum price is accepted.
+func (*environ) CheckSpotInstanceParams(environs.SpotInstanceParams) error {
+	return nil
+}
+
 // StartInstance implements environs.InstanceBroker.
 func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
 	// Start a new instance.
@@ -167,6 +177,7 @@ func (env *environ) newRawInstance(args environs.StartInstanceParams, spec *inst
 		NetworkInterfaces: []string{"ExternalNAT"},
 		Metadata:          metadata,
 		Tags:              tags,
+		Preemptible:       args.Spot != nil,
 		// Network is omitted (left empty).
 	}
 
@@ -252,6 +263,9 @@ func getDisks(spec *instances.InstanceSpec, cons constraints.Value, ser, eUUID s
 		AutoDelete:  true,
 		Description: eUUID,
 	}
+	if cons.HasRootDiskSource() {
+		dSpec.PersistentDiskType = google.DiskType(*cons.RootDiskSource)
+	}
 	if cons.RootDisk != nil && dSpec.TooSmall() {
 		msg := "Ignoring root-disk constraint of %dM because it is smaller than the GCE image size of %dG"
 		logger.Infof(msg, *cons.RootDisk, google.MinDiskSizeGB(ser))
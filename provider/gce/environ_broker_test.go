@@ -13,12 +13,14 @@ import (
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/instance"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
 )
 
 type environBrokerSuite struct {
@@ -118,7 +120,7 @@ func (s *environBrokerSuite) TestFindInstanceSpec(c *gc.C) {
 
 func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
 	s.FakeConn.Inst = s.BaseInstance
-	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+	s.FakeCommon.AZInstances = []environs.AvailabilityZoneInstances{{
 		ZoneName:  "home-zone",
 		Instances: []instance.Id{s.Instance.Id()},
 	}}
@@ -129,6 +131,33 @@ func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
 	c.Check(inst, jc.DeepEquals, s.BaseInstance)
 }
 
+func (s *environBrokerSuite) TestNewRawInstanceSpot(c *gc.C) {
+	s.FakeConn.Inst = s.BaseInstance
+	s.FakeCommon.AZInstances = []environs.AvailabilityZoneInstances{{
+		ZoneName:  "home-zone",
+		Instances: []instance.Id{s.Instance.Id()},
+	}}
+	s.StartInstArgs.Spot = &environs.SpotInstanceParams{MaxPrice: 0.05}
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var added []google.InstanceSpec
+	for _, call := range s.FakeConn.Calls {
+		if call.FuncName == "AddInstance" {
+			added = append(added, call.InstanceSpec)
+		}
+	}
+	c.Assert(added, gc.HasLen, 1)
+	c.Check(added[0].Preemptible, jc.IsTrue)
+}
+
+func (s *environBrokerSuite) TestCheckSpotInstanceParams(c *gc.C) {
+	var spotInstancer environs.SpotInstancer = s.Env
+	err := spotInstancer.CheckSpotInstanceParams(environs.SpotInstanceParams{MaxPrice: 0.05})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *environBrokerSuite) TestGetMetadataUbuntu(c *gc.C) {
 	metadata, err := gce.GetMetadata(s.StartInstArgs, jujuos.Ubuntu)
 
@@ -194,6 +223,14 @@ func (s *environBrokerSuite) TestGetDisks(c *gc.C) {
 	c.Assert(spec.ImageURL, gc.Equals, gce.UbuntuDailyImageBasePath+s.spec.Image.Id)
 }
 
+func (s *environBrokerSuite) TestGetDisksRootDiskSource(c *gc.C) {
+	cons := constraints.MustParse("root-disk-source=pd-ssd")
+	diskSpecs, err := gce.GetDisks(s.spec, cons, "trusty", "32f7d570-5bac-4b72-b169-250c24a94b2b", false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(diskSpecs, gc.HasLen, 1)
+	c.Check(diskSpecs[0].PersistentDiskType, gc.Equals, google.DiskPersistentSSD)
+}
+
 func (s *environBrokerSuite) TestGetHardwareCharacteristics(c *gc.C) {
 	hwc := gce.GetHardwareCharacteristics(s.Env, s.spec, s.Instance)
 
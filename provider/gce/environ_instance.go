@@ -171,7 +171,7 @@ func (env *environ) parsePlacement(placement string) (*instPlacement, error) {
 func checkInstanceType(cons constraints.Value) bool {
 	// Constraint has an instance-type constraint so let's see if it is valid.
 	for _, itype := range allInstanceTypes {
-		if itype.Name == *cons.InstanceType {
+		if cons.MatchesInstanceType(itype.Name) {
 			return true
 		}
 	}
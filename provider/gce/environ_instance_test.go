@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/constraints"
@@ -190,14 +191,14 @@ func (s *environInstSuite) TestCheckInstanceTypeUnknown(c *gc.C) {
 }
 
 func (s *environInstSuite) TestListMachineTypes(c *gc.C) {
-	_, err := s.Env.InstanceTypes(constraints.Value{})
+	_, err := s.Env.InstanceTypes(context.Background(), constraints.Value{})
 	c.Assert(err, gc.ErrorMatches, "no instance types in  matching constraints \"\"")
 
 	zone := google.NewZone("a-zone", google.StatusUp, "", "")
 	s.FakeConn.Zones = []google.AvailabilityZone{zone}
 
 	mem := uint64(1025)
-	types, err := s.Env.InstanceTypes(constraints.Value{Mem: &mem})
+	types, err := s.Env.InstanceTypes(context.Background(), constraints.Value{Mem: &mem})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(types.InstanceTypes, gc.HasLen, 1)
 
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/provider/gce/google"
 )
 
 // PrecheckInstance verifies that the provided series and constraints
@@ -26,10 +27,19 @@ func (env *environ) PrecheckInstance(series string, cons constraints.Value, plac
 }
 
 var unsupportedConstraints = []string{
+	constraints.GPUs,
+	constraints.GPUType,
 	constraints.Tags,
 	constraints.VirtType,
 }
 
+// rootDiskSources holds the values accepted for the root-disk-source
+// constraint, which name the persistent disk type of the root disk.
+var rootDiskSources = []string{
+	string(google.DiskPersistentStandard),
+	string(google.DiskPersistentSSD),
+}
+
 // instanceTypeConstraints defines the fields defined on each of the
 // instance types.  See instancetypes.go.
 var instanceTypeConstraints = []string{
@@ -67,6 +77,12 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 
 	validator.RegisterVocabulary(constraints.Container, []string{vtype})
 
+	// The root disk is always a persistent disk.
+	validator.RegisterVocabulary(constraints.RootDiskSource, rootDiskSources)
+
+	// Instances are always started with an external address.
+	validator.RegisterVocabulary(constraints.AllocatePublicIP, []bool{true})
+
 	return validator, nil
 }
 
@@ -165,6 +165,17 @@ func (s *environPolSuite) TestConstraintsValidatorVocabInstType(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "invalid constraint value: instance-type=foo\nvalid values are:.*")
 }
 
+func (s *environPolSuite) TestConstraintsValidatorVocabRootDiskSource(c *gc.C) {
+	validator, err := s.Env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = validator.Validate(constraints.MustParse("root-disk-source=pd-ssd"))
+	c.Check(err, jc.ErrorIsNil)
+
+	_, err = validator.Validate(constraints.MustParse("root-disk-source=local-ssd"))
+	c.Check(err, gc.ErrorMatches, "invalid constraint value: root-disk-source=local-ssd\nvalid values are:.*")
+}
+
 func (s *environPolSuite) TestConstraintsValidatorVocabContainer(c *gc.C) {
 	validator, err := s.Env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
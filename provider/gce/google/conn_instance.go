@@ -23,6 +23,7 @@ func (gce *Connection) addInstance(requestedInst *compute.Instance, machineType
 		var waitErr error
 		inst := *requestedInst
 		inst.MachineType = formatMachineType(zoneName, machineType)
+		inst.Disks = formatAttachedDisks(zoneName, inst.Disks)
 		err := gce.raw.AddInstance(gce.projectID, zoneName, &inst)
 		if isWaitError(err) {
 			waitErr = err
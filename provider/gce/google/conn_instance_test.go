@@ -44,6 +44,26 @@ func (s *connSuite) TestConnectionSimpleAddInstanceAPI(c *gc.C) {
 	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
 }
 
+func (s *connSuite) TestConnectionAddInstanceDiskType(c *gc.C) {
+	s.FakeConn.Instance = &s.RawInstanceFull
+
+	disk := s.AttachedDisk
+	params := *disk.InitializeParams
+	params.DiskType = "pd-ssd"
+	disk.InitializeParams = &params
+	inst := s.RawInstance
+	inst.Disks = []*compute.AttachedDisk{&disk}
+	zones := []string{"a-zone"}
+	err := google.ConnAddInstance(s.Conn, &inst, "mtype", zones)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The disk type is qualified with the zone, leaving the requested
+	// disk as it was for any other zone.
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].InstValue.Disks[0].InitializeParams.DiskType, gc.Equals, "zones/a-zone/diskTypes/pd-ssd")
+	c.Check(disk.InitializeParams.DiskType, gc.Equals, "pd-ssd")
+}
+
 func (s *instanceSuite) TestConnectionAddInstance(c *gc.C) {
 	s.FakeConn.Instance = &s.RawInstanceFull
 
@@ -157,6 +157,7 @@ func (ds *DiskSpec) newAttached() *compute.AttachedDisk {
 			// DiskName (defaults to instance name)
 			DiskSizeGb: int64(ds.SizeGB()),
 			// DiskType (defaults to pd-standard, pd-ssd, local-ssd)
+			DiskType:    string(ds.PersistentDiskType),
 			SourceImage: ds.ImageURL,
 		},
 		// Interface (defaults to SCSI)
@@ -128,6 +128,13 @@ func (s *diskSuite) TestDiskSpecNewAttachedReadOnly(c *gc.C) {
 	})
 }
 
+func (s *diskSuite) TestDiskSpecNewAttachedDiskType(c *gc.C) {
+	s.DiskSpec.PersistentDiskType = google.DiskPersistentSSD
+	attached := google.NewAttached(s.DiskSpec)
+
+	c.Check(attached.InitializeParams.DiskType, gc.Equals, "pd-ssd")
+}
+
 func (s *diskSuite) TestRootDiskInstance(c *gc.C) {
 	attached := s.Instance.RootDisk()
 
@@ -43,10 +43,13 @@ type InstanceSpec struct {
 	// useful when making bulk calls or in relation to some API methods
 	// (e.g. related to firewalls access rules).
 	Tags []string
+	// Preemptible requests that the instance be started using GCE's
+	// preemptible capacity, which GCE may reclaim at any time.
+	Preemptible bool
 }
 
 func (is InstanceSpec) raw() *compute.Instance {
-	return &compute.Instance{
+	raw := &compute.Instance{
 		Name:              is.ID,
 		Disks:             is.disks(),
 		NetworkInterfaces: is.networkInterfaces(),
@@ -54,6 +57,15 @@ func (is InstanceSpec) raw() *compute.Instance {
 		Tags:              &compute.Tags{Items: is.Tags},
 		// MachineType is set in the addInstance call.
 	}
+	if is.Preemptible {
+		// Preemptible instances cannot be live migrated, so they
+		// must be terminated for host maintenance.
+		raw.Scheduling = &compute.Scheduling{
+			Preemptible:       true,
+			OnHostMaintenance: "TERMINATE",
+		}
+	}
+	return raw
 }
 
 // Summary builds an InstanceSummary based on the spec and returns it.
@@ -99,15 +111,19 @@ type InstanceSummary struct {
 	Metadata map[string]string
 	// Addresses are the IP Addresses associated with the instance.
 	Addresses []network.Address
+	// Preemptible reports whether the instance is running on GCE's
+	// preemptible capacity.
+	Preemptible bool
 }
 
 func newInstanceSummary(raw *compute.Instance) InstanceSummary {
 	return InstanceSummary{
-		ID:        raw.Name,
-		ZoneName:  path.Base(raw.Zone),
-		Status:    raw.Status,
-		Metadata:  unpackMetadata(raw.Metadata),
-		Addresses: extractAddresses(raw.NetworkInterfaces...),
+		ID:          raw.Name,
+		ZoneName:    path.Base(raw.Zone),
+		Status:      raw.Status,
+		Metadata:    unpackMetadata(raw.Metadata),
+		Addresses:   extractAddresses(raw.NetworkInterfaces...),
+		Preemptible: raw.Scheduling != nil && raw.Scheduling.Preemptible,
 	}
 }
 
@@ -206,3 +222,23 @@ func unpackMetadata(data *compute.Metadata) map[string]string {
 func formatMachineType(zone, name string) string {
 	return fmt.Sprintf("zones/%s/machineTypes/%s", zone, name)
 }
+
+// formatAttachedDisks returns a copy of the given disks in which the
+// type of each disk to be created with the instance is qualified with
+// the zone. The disks themselves are left untouched so that they may
+// be formatted again for another zone.
+func formatAttachedDisks(zone string, disks []*compute.AttachedDisk) []*compute.AttachedDisk {
+	result := make([]*compute.AttachedDisk, len(disks))
+	for i, disk := range disks {
+		result[i] = disk
+		if disk.InitializeParams == nil || disk.InitializeParams.DiskType == "" {
+			continue
+		}
+		params := *disk.InitializeParams
+		params.DiskType = fmt.Sprintf("zones/%s/diskTypes/%s", zone, params.DiskType)
+		formatted := *disk
+		formatted.InitializeParams = &params
+		result[i] = &formatted
+	}
+	return result
+}
@@ -29,6 +29,23 @@ func (s *instanceSuite) TestNewInstance(c *gc.C) {
 	c.Check(spec, jc.DeepEquals, &s.InstanceSpec)
 }
 
+func (s *instanceSuite) TestNewInstancePreemptible(c *gc.C) {
+	c.Check(google.NewInstanceRaw(&s.RawInstanceFull, nil).Preemptible, jc.IsFalse)
+
+	raw := s.RawInstanceFull
+	raw.Scheduling = &compute.Scheduling{Preemptible: true}
+	inst := google.NewInstanceRaw(&raw, nil)
+	c.Check(inst.Preemptible, jc.IsTrue)
+}
+
+func (s *instanceSuite) TestInstanceSpecPreemptible(c *gc.C) {
+	c.Check(s.InstanceSpec.Summary().Preemptible, jc.IsFalse)
+
+	spec := s.InstanceSpec
+	spec.Preemptible = true
+	c.Check(spec.Summary().Preemptible, jc.IsTrue)
+}
+
 func (s *instanceSuite) TestNewInstanceNoSpec(c *gc.C) {
 	inst := google.NewInstanceRaw(&s.RawInstanceFull, nil)
 
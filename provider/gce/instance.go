@@ -42,6 +42,11 @@ func (inst *environInstance) Status() instance.InstanceStatus {
 		jujuStatus = status.Running
 	case "STOPPING", "TERMINATED":
 		jujuStatus = status.Empty
+		if inst.base.Preemptible {
+			// GCE stops preemptible instances when it
+			// reclaims their capacity.
+			jujuStatus = status.Evicted
+		}
 	default:
 		jujuStatus = status.Empty
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/gce"
 	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/status"
 )
 
 type instanceSuite struct {
@@ -40,6 +41,32 @@ func (s *instanceSuite) TestStatus(c *gc.C) {
 	s.CheckNoAPI(c)
 }
 
+func (s *instanceSuite) TestStatusEvicted(c *gc.C) {
+	base := google.NewInstance(google.InstanceSummary{
+		ID:          "spam",
+		Status:      google.StatusTerminated,
+		Preemptible: true,
+	}, nil)
+	inst := gce.NewInstance(base, s.Env)
+
+	c.Check(inst.Status(), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Evicted,
+		Message: google.StatusTerminated,
+	})
+	s.CheckNoAPI(c)
+}
+
+func (s *instanceSuite) TestStatusTerminated(c *gc.C) {
+	base := google.NewInstance(google.InstanceSummary{
+		ID:     "spam",
+		Status: google.StatusTerminated,
+	}, nil)
+	inst := gce.NewInstance(base, s.Env)
+
+	c.Check(inst.Status().Status, gc.Equals, status.Empty)
+	s.CheckNoAPI(c)
+}
+
 func (s *instanceSuite) TestAddresses(c *gc.C) {
 	addresses, err := s.Instance.Addresses()
 	c.Assert(err, jc.ErrorIsNil)
@@ -25,7 +25,6 @@ import (
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/gce/google"
 	"github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
@@ -354,7 +353,7 @@ type fakeCommon struct {
 	Arch        string
 	Series      string
 	BSFinalizer environs.BootstrapFinalizer
-	AZInstances []common.AvailabilityZoneInstances
+	AZInstances []environs.AvailabilityZoneInstances
 }
 
 func (fc *fakeCommon) Bootstrap(ctx environs.BootstrapContext, env environs.Environ, params environs.BootstrapParams) (*environs.BootstrapResult, error) {
@@ -379,7 +378,7 @@ func (fc *fakeCommon) Destroy(env environs.Environ) error {
 	return fc.err()
 }
 
-func (fc *fakeCommon) AvailabilityZoneAllocations(env common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+func (fc *fakeCommon) AvailabilityZoneAllocations(env environs.ZonedEnviron, group []instance.Id) ([]environs.AvailabilityZoneInstances, error) {
 	fc.addCall("AvailabilityZoneAllocations", FakeCallArgs{
 		"switch": env,
 		"group":  group,
@@ -71,7 +71,7 @@ func (env *joyentEnviron) PrecheckInstance(series string, cons constraints.Value
 		return err
 	}
 	for _, instanceType := range instanceTypes {
-		if instanceType.Name == *cons.InstanceType {
+		if cons.MatchesInstanceType(instanceType.Name) {
 			return nil
 		}
 	}
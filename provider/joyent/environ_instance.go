@@ -52,6 +52,10 @@ func (env *joyentEnviron) machineFullName(machineId string) string {
 
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
+	constraints.GPUs,
+	constraints.GPUType,
+	constraints.RootDiskSource,
+	constraints.Spread,
 	constraints.Tags,
 	constraints.VirtType,
 }
@@ -59,6 +63,10 @@ var unsupportedConstraints = []string{
 // ConstraintsValidator is defined on the Environs interface.
 func (env *joyentEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
+	// Packages fix the memory, disk and cores of an instance.
+	validator.RegisterConflicts(
+		[]string{constraints.InstanceType},
+		[]string{constraints.Mem, constraints.RootDisk, constraints.Cores})
 	validator.RegisterUnsupported(unsupportedConstraints)
 	packages, err := env.compute.cloudapi.ListPackages(nil)
 	if err != nil {
@@ -3,7 +3,10 @@
 
 package joyent
 
-import "github.com/juju/juju/environs"
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
 
 const (
 	providerType = "joyent"
@@ -11,4 +14,7 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, providerInstance)
+	if err := config.RegisterProviderSchema(providerType, config.ProviderConfigSchema{}); err != nil {
+		panic(err)
+	}
 }
@@ -5,6 +5,7 @@ package joyent
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
@@ -14,7 +15,7 @@ import (
 var _ environs.InstanceTypesFetcher = (*joyentEnviron)(nil)
 
 // InstanceTypes implements InstanceTypesFetcher
-func (env *joyentEnviron) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+func (env *joyentEnviron) InstanceTypes(ctx context.Context, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
 	iTypes, err := env.listInstanceTypes()
 	if err != nil {
 		return instances.InstanceTypesWithCostMetadata{}, errors.Trace(err)
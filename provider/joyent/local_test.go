@@ -10,6 +10,7 @@ import (
 	lc "github.com/joyent/gosdc/localservices/cloudapi"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cloud"
@@ -392,14 +393,23 @@ func (s *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: instance-type=foo\nvalid values are:.*")
 }
 
+func (s *localServerSuite) TestConstraintsValidatorConflicts(c *gc.C) {
+	env := s.Prepare(c)
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	cons := constraints.MustParse("instance-type=foo mem=4G")
+	_, err = validator.Validate(cons)
+	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
+}
+
 func (t *localServerSuite) TestInstanceInformation(c *gc.C) {
 	env := t.Prepare(c)
-	types, err := env.InstanceTypes(constraints.Value{})
+	types, err := env.InstanceTypes(context.Background(), constraints.Value{})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(types.InstanceTypes, gc.HasLen, 3)
 
 	cons := constraints.MustParse("mem=4G")
-	types, err = env.InstanceTypes(cons)
+	types, err = env.InstanceTypes(context.Background(), cons)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(types.InstanceTypes, gc.HasLen, 1)
 }
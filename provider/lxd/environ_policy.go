@@ -8,6 +8,8 @@ package lxd
 import (
 	"github.com/juju/errors"
 	"github.com/juju/utils/arch"
+	"github.com/juju/utils/os"
+	"github.com/juju/utils/series"
 
 	"github.com/juju/juju/constraints"
 )
@@ -26,11 +28,33 @@ func (env *environ) PrecheckInstance(series string, cons constraints.Value, plac
 	return nil
 }
 
+// SupportedSeries is specified in the environs.SeriesSupporter
+// interface. LXD images are available for Ubuntu and for CentOS 7.
+func (env *environ) SupportedSeries() ([]string, error) {
+	var supported []string
+	for _, s := range series.SupportedSeries() {
+		seriesOS, err := series.GetOSFromSeries(s)
+		if err != nil {
+			continue
+		}
+		if seriesOS == os.Ubuntu || s == "centos7" {
+			supported = append(supported, s)
+		}
+	}
+	return supported, nil
+}
+
 var unsupportedConstraints = []string{
+	constraints.AllocatePublicIP,
 	constraints.Cores,
 	constraints.CpuPower,
+	constraints.GPUs,
+	constraints.GPUType,
+	constraints.ImageID,
 	//TODO(ericsnow) Add constraints.Mem as unsupported?
 	constraints.InstanceType,
+	constraints.RootDiskSource,
+	constraints.Spread,
 	constraints.Tags,
 	constraints.VirtType,
 }
@@ -47,6 +71,8 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	// Register unsupported constraints.
 
 	validator.RegisterUnsupported(unsupportedConstraints)
+	// Containers are limited to exactly the minimum resources.
+	validator.RegisterUnsupportedRanges([]string{constraints.Cores, constraints.Mem})
 
 	// Register the constraints vocab.
 
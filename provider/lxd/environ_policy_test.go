@@ -56,6 +56,16 @@ func (s *environPolSuite) TestPrecheckInstanceDiskSize(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
+func (s *environPolSuite) TestSupportedSeries(c *gc.C) {
+	supported, err := s.Env.SupportedSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(supported, jc.Contains, series.LatestLts())
+	c.Check(supported, jc.Contains, "centos7")
+	for _, s := range supported {
+		c.Check(strings.HasPrefix(s, "win"), jc.IsFalse)
+	}
+}
+
 func (s *environPolSuite) TestPrecheckInstanceUnsupportedArch(c *gc.C) {
 	s.PatchValue(&arch.HostArch, func() string { return arch.AMD64 })
 
@@ -149,6 +159,16 @@ func (s *environPolSuite) TestConstraintsValidatorVocabArchUnknown(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are: \\[amd64\\]")
 }
 
+func (s *environPolSuite) TestConstraintsValidatorRanges(c *gc.C) {
+	validator, err := s.Env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("mem=1G..2G")
+	_, err = validator.Validate(cons)
+
+	c.Check(err, gc.ErrorMatches, "mem ranges are not supported")
+}
+
 func (s *environPolSuite) TestConstraintsValidatorVocabContainerUnknown(c *gc.C) {
 	c.Skip("this will fail until we add a container vocabulary")
 	validator, err := s.Env.ConstraintsValidator()
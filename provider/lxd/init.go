@@ -7,9 +7,16 @@ package lxd
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/lxd/lxdnames"
 )
 
 func init() {
 	environs.RegisterProvider(lxdnames.ProviderType, NewProvider())
+	if err := config.RegisterProviderSchema(lxdnames.ProviderType, config.ProviderConfigSchema{
+		Fields:   configSchema,
+		Defaults: configDefaults,
+	}); err != nil {
+		panic(err)
+	}
 }
@@ -65,7 +65,7 @@ func (prov MaasEnvironProvider) Validate(cfg, oldCfg *config.Config) (*config.Co
 	if err != nil {
 		return nil, err
 	}
-	validated, err := cfg.ValidateUnknownAttrs(configFields, configDefaults)
+	validated, err := cfg.ValidateProviderAttrs()
 	if err != nil {
 		return nil, err
 	}
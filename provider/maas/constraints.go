@@ -17,7 +17,11 @@ import (
 )
 
 var unsupportedConstraints = []string{
+	constraints.AllocatePublicIP,
 	constraints.CpuPower,
+	constraints.GPUs,
+	constraints.GPUType,
+	constraints.ImageID,
 	constraints.InstanceType,
 	constraints.VirtType,
 }
@@ -26,6 +30,8 @@ var unsupportedConstraints = []string{
 func (environ *maasEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	// MAAS allocates machines by their minimum resources only.
+	validator.RegisterUnsupportedRanges([]string{constraints.Cores, constraints.Mem})
 	supportedArches, err := environ.getSupportedArchitectures()
 	if err != nil {
 		return nil, err
@@ -43,7 +49,11 @@ func convertConstraints(cons constraints.Value) url.Values {
 		// Note: Juju and MAAS use the same architecture names.
 		// MAAS also accepts a subarchitecture (e.g. "highbank"
 		// for ARM), which defaults to "generic" if unspecified.
-		params.Add("arch", *cons.Arch)
+		// If several architectures are given, MAAS may acquire
+		// a node matching any of them.
+		for _, arch := range strings.Split(*cons.Arch, ",") {
+			params.Add("arch", arch)
+		}
 	}
 	if cons.CpuCores != nil {
 		params.Add("cpu_count", fmt.Sprintf("%d", *cons.CpuCores))
@@ -62,8 +72,11 @@ func convertConstraints(cons constraints.Value) url.Values {
 // gomaasapi.AllocateMachineArgs for paasing to MAAS 2.
 func convertConstraints2(cons constraints.Value) gomaasapi.AllocateMachineArgs {
 	params := gomaasapi.AllocateMachineArgs{}
-	if cons.Arch != nil {
-		params.Architecture = *cons.Arch
+	if arches := cons.Arches(); len(arches) > 0 {
+		// MAAS 2 only accepts a single architecture, so we
+		// request the preferred one. acquireNode2 requests
+		// each of the others in turn if no machine matches.
+		params.Architecture = arches[0]
 	}
 	if cons.CpuCores != nil {
 		params.MinCPUCount = int(*cons.CpuCores)
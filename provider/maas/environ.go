@@ -97,7 +97,7 @@ type maasEnviron struct {
 	namespace instance.Namespace
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []environs.AvailabilityZone
 
 	// apiVersion tells us if we are using the MAAS 1.0 or 2.0 api.
 	apiVersion string
@@ -488,11 +488,11 @@ func (z maasAvailabilityZone) Available() bool {
 
 // AvailabilityZones returns a slice of availability zones
 // for the configured region.
-func (e *maasEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *maasEnviron) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
-		var availabilityZones []common.AvailabilityZone
+		var availabilityZones []environs.AvailabilityZone
 		var err error
 		if e.usingMAAS2() {
 			availabilityZones, err = e.availabilityZones2()
@@ -510,7 +510,7 @@ func (e *maasEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
 	return e.availabilityZones, nil
 }
 
-func (e *maasEnviron) availabilityZones1() ([]common.AvailabilityZone, error) {
+func (e *maasEnviron) availabilityZones1() ([]environs.AvailabilityZone, error) {
 	zonesObject := e.getMAASClient().GetSubObject("zones")
 	result, err := zonesObject.CallGet("", nil)
 	if err, ok := errors.Cause(err).(gomaasapi.ServerError); ok && err.StatusCode == http.StatusNotFound {
@@ -524,7 +524,7 @@ func (e *maasEnviron) availabilityZones1() ([]common.AvailabilityZone, error) {
 		return nil, err
 	}
 	logger.Debugf("availability zones: %+v", list)
-	availabilityZones := make([]common.AvailabilityZone, len(list))
+	availabilityZones := make([]environs.AvailabilityZone, len(list))
 	for i, obj := range list {
 		zone, err := obj.GetMap()
 		if err != nil {
@@ -539,12 +539,12 @@ func (e *maasEnviron) availabilityZones1() ([]common.AvailabilityZone, error) {
 	return availabilityZones, nil
 }
 
-func (e *maasEnviron) availabilityZones2() ([]common.AvailabilityZone, error) {
+func (e *maasEnviron) availabilityZones2() ([]environs.AvailabilityZone, error) {
 	zones, err := e.maasController.Zones()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	availabilityZones := make([]common.AvailabilityZone, len(zones))
+	availabilityZones := make([]environs.AvailabilityZone, len(zones))
 	for i, zone := range zones {
 		availabilityZones[i] = maasAvailabilityZone{zone.Name()}
 	}
@@ -587,17 +587,10 @@ func (e *maasEnviron) parsePlacement(placement string) (*maasPlacement, error) {
 	}
 	switch key, value := placement[:pos], placement[pos+1:]; key {
 	case "zone":
-		availabilityZone := value
-		zones, err := e.AvailabilityZones()
-		if err != nil {
+		if _, err := environs.FindAvailabilityZone(e, value); err != nil {
 			return nil, err
 		}
-		for _, z := range zones {
-			if z.Name() == availabilityZone {
-				return &maasPlacement{zoneName: availabilityZone}, nil
-			}
-		}
-		return nil, errors.Errorf("invalid availability zone %q", availabilityZone)
+		return &maasPlacement{zoneName: value}, nil
 	}
 	return nil, errors.Errorf("unknown placement directive: %v", placement)
 }
@@ -757,8 +750,22 @@ func (environ *maasEnviron) acquireNode2(
 	if nodeName != "" {
 		acquireParams.Hostname = nodeName
 	}
-	machine, constraintMatches, err := environ.maasController.AllocateMachine(acquireParams)
-
+	// MAAS 2 only matches a single architecture per request, so
+	// each acceptable architecture is tried in order of preference.
+	arches := cons.Arches()
+	if len(arches) == 0 {
+		arches = []string{""}
+	}
+	var machine gomaasapi.Machine
+	var constraintMatches gomaasapi.ConstraintMatches
+	for i, arch := range arches {
+		acquireParams.Architecture = arch
+		machine, constraintMatches, err = environ.maasController.AllocateMachine(acquireParams)
+		if err == nil || !gomaasapi.IsNoMatchError(err) || i == len(arches)-1 {
+			break
+		}
+		logger.Debugf("no %q machine matches, trying %q", arch, arches[i+1])
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -866,7 +873,7 @@ func (e *maasEnviron) DistributeInstances(candidates, distributionGroup []instan
 	return common.DistributeInstances(e, candidates, distributionGroup)
 }
 
-var availabilityZoneAllocations = common.AvailabilityZoneAllocations
+var availabilityZoneAllocations = environs.AvailabilityZoneAllocations
 
 // MaintainInstance is specified in the InstanceBroker interface.
 func (*maasEnviron) MaintainInstance(args environs.StartInstanceParams) error {
@@ -886,13 +886,13 @@ func (s *environSuite) TestGetAvailabilityZones(c *gc.C) {
 
 type mockAvailabilityZoneAllocations struct {
 	group  []instance.Id // input param
-	result []common.AvailabilityZoneInstances
+	result []environs.AvailabilityZoneInstances
 	err    error
 }
 
 func (m *mockAvailabilityZoneAllocations) AvailabilityZoneAllocations(
-	e common.ZonedEnviron, group []instance.Id,
-) ([]common.AvailabilityZoneInstances, error) {
+	e environs.ZonedEnviron, group []instance.Id,
+) ([]environs.AvailabilityZoneInstances, error) {
 	m.group = group
 	return m.result, m.err
 }
@@ -994,7 +994,7 @@ func (s *environSuite) TestStartInstanceDistribution(c *gc.C) {
 
 func (s *environSuite) TestStartInstanceDistributionFailover(c *gc.C) {
 	mock := mockAvailabilityZoneAllocations{
-		result: []common.AvailabilityZoneInstances{{
+		result: []environs.AvailabilityZoneInstances{{
 			ZoneName: "zone1",
 		}, {
 			ZoneName: "zonelord",
@@ -1035,7 +1035,7 @@ func (s *environSuite) TestStartInstanceDistributionFailover(c *gc.C) {
 
 func (s *environSuite) TestStartInstanceDistributionOneAssigned(c *gc.C) {
 	mock := mockAvailabilityZoneAllocations{
-		result: []common.AvailabilityZoneInstances{{
+		result: []environs.AvailabilityZoneInstances{{
 			ZoneName: "zone1",
 		}, {
 			ZoneName: "zone2",
@@ -5,6 +5,7 @@ package maas
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 )
 
 const (
@@ -13,4 +14,10 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, MaasEnvironProvider{GetCapabilities: getCapabilities})
+	if err := config.RegisterProviderSchema(providerType, config.ProviderConfigSchema{
+		Fields:   configSchema,
+		Defaults: configDefaults,
+	}); err != nil {
+		panic(err)
+	}
 }
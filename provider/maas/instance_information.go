@@ -5,6 +5,7 @@ package maas
 
 import (
 	"github.com/juju/errors"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
@@ -13,7 +14,7 @@ import (
 
 var _ environs.InstanceTypesFetcher = (*maasEnviron)(nil)
 
-func (env *maasEnviron) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+func (env *maasEnviron) InstanceTypes(ctx context.Context, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
 	result := instances.InstanceTypesWithCostMetadata{}
 	return result, errors.NotSupportedf("InstanceTypes")
 }
@@ -330,6 +330,7 @@ func (suite *maas2EnvironSuite) TestStartInstanceAppliesResourceTags(c *gc.C) {
 	c.Assert(machine.Calls()[1].Args[0], gc.DeepEquals, map[string]string{
 		"claude":            "rains",
 		tags.JujuController: suite.controllerUUID,
+		tags.JujuMachine:    "1",
 		tags.JujuModel:      config.UUID(),
 	})
 }
@@ -382,6 +383,53 @@ func (suite *maas2EnvironSuite) TestAcquireNodePassedAgentName(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
+// archFallbackController is a fake MAAS 2 controller that only has
+// machines of a single architecture.
+type archFallbackController struct {
+	*fakeController
+	arch      string
+	requested []string
+}
+
+func (c *archFallbackController) AllocateMachine(args gomaasapi.AllocateMachineArgs) (gomaasapi.Machine, gomaasapi.ConstraintMatches, error) {
+	c.requested = append(c.requested, args.Architecture)
+	if args.Architecture != c.arch {
+		return nil, gomaasapi.ConstraintMatches{}, gomaasapi.NewNoMatchError("no machine")
+	}
+	return c.fakeController.AllocateMachine(args)
+}
+
+func (suite *maas2EnvironSuite) TestAcquireNodeTriesEachArch(c *gc.C) {
+	controller := &archFallbackController{
+		fakeController: &fakeController{
+			allocateMachine: newFakeMachine("Bruce Sterling", "arm64", ""),
+		},
+		arch: "arm64",
+	}
+	suite.injectController(controller)
+	suite.setupFakeTools(c)
+	env := suite.makeEnviron(c, nil)
+
+	inst, err := env.acquireNode2("", "", constraints.MustParse("arch=amd64,arm64"), nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inst.Id(), gc.Equals, instance.Id("Bruce Sterling"))
+	c.Assert(controller.requested, jc.DeepEquals, []string{"amd64", "arm64"})
+}
+
+func (suite *maas2EnvironSuite) TestAcquireNodeNoArchMatches(c *gc.C) {
+	controller := &archFallbackController{
+		fakeController: &fakeController{},
+		arch:           "ppc64el",
+	}
+	suite.injectController(controller)
+	suite.setupFakeTools(c)
+	env := suite.makeEnviron(c, nil)
+
+	_, err := env.acquireNode2("", "", constraints.MustParse("arch=amd64,arm64"), nil, nil)
+	c.Assert(err, jc.Satisfies, gomaasapi.IsNoMatchError)
+	c.Assert(controller.requested, jc.DeepEquals, []string{"amd64", "arm64"})
+}
+
 func (suite *maas2EnvironSuite) TestAcquireNodePassesPositiveAndNegativeTags(c *gc.C) {
 	var env *maasEnviron
 	expected := gomaasapi.AllocateMachineArgs{
@@ -1832,6 +1880,7 @@ func (suite *maas2EnvironSuite) TestStartInstanceEndToEnd(c *gc.C) {
 		"claude":              "rains",
 		tags.JujuController:   suite.controllerUUID,
 		tags.JujuIsController: "true",
+		tags.JujuMachine:      "0",
 		tags.JujuModel:        env.Config().UUID(),
 	})
 
@@ -1993,6 +2042,17 @@ func (suite *maas2EnvironSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are: \\[amd64 armhf\\]")
 }
 
+func (suite *maas2EnvironSuite) TestConstraintsValidatorRanges(c *gc.C) {
+	controller := newFakeController()
+	controller.bootResources = []gomaasapi.BootResource{&fakeBootResource{name: "trusty", architecture: "amd64"}}
+	env := suite.makeEnviron(c, controller)
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	cons := constraints.MustParse("cores=2..4")
+	_, err = validator.Validate(cons)
+	c.Assert(err, gc.ErrorMatches, "cores ranges are not supported")
+}
+
 func (suite *maas2EnvironSuite) TestReleaseContainerAddresses(c *gc.C) {
 	dev1 := newFakeDevice("a", "eleven")
 	dev2 := newFakeDevice("b", "will")
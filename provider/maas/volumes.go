@@ -144,8 +144,9 @@ func mibToGb(m uint64) uint64 {
 
 // buildMAASVolumeParameters creates the MAAS volume information to include
 // in a request to acquire a MAAS node, based on the supplied storage parameters.
+// The root-disk-source constraint names a tag the root disk must have.
 func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Value) ([]volumeInfo, error) {
-	if len(args) == 0 && cons.RootDisk == nil {
+	if len(args) == 0 && cons.RootDisk == nil && !cons.HasRootDiskSource() {
 		return nil, nil
 	}
 	volumes := make([]volumeInfo, len(args)+1)
@@ -153,6 +154,9 @@ func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Val
 	if cons.RootDisk != nil {
 		rootVolume.sizeInGB = mibToGb(*cons.RootDisk)
 	}
+	if cons.HasRootDiskSource() {
+		rootVolume.tags = []string{*cons.RootDiskSource}
+	}
 	volumes[0] = rootVolume
 	for i, v := range args {
 		cfg, err := newStorageConfig(v.Attributes)
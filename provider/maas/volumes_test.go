@@ -38,6 +38,18 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersJustRootDisk(c *gc.C) {
 	})
 }
 
+func (s *volumeSuite) TestBuildMAASVolumeParametersRootDiskSource(c *gc.C) {
+	cons := constraints.MustParse("root-disk-source=ssd")
+	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
+		{Tag: names.NewVolumeTag("1"), Size: 2000000},
+	}, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, []string{"ssd"}}, //root disk
+		{"1", 1954, nil},
+	})
+}
+
 func (s *volumeSuite) TestBuildMAASVolumeParametersNoTags(c *gc.C) {
 	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
 		{Tag: names.NewVolumeTag("1"), Size: 2000000},
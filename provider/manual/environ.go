@@ -319,8 +319,14 @@ func (*manualEnviron) PrecheckInstance(series string, _ constraints.Value, place
 }
 
 var unsupportedConstraints = []string{
+	constraints.AllocatePublicIP,
 	constraints.CpuPower,
+	constraints.GPUs,
+	constraints.GPUType,
+	constraints.ImageID,
 	constraints.InstanceType,
+	constraints.RootDiskSource,
+	constraints.Spread,
 	constraints.Tags,
 	constraints.VirtType,
 }
@@ -329,6 +335,7 @@ var unsupportedConstraints = []string{
 func (e *manualEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterUnsupportedRanges([]string{constraints.Cores, constraints.Mem})
 	if isRunningController() {
 		validator.UpdateVocabulary(constraints.Arch, []string{arch.HostArch()})
 	} else {
@@ -3,7 +3,10 @@
 
 package manual
 
-import "github.com/juju/juju/environs"
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
 
 const (
 	providerType = "manual"
@@ -12,4 +15,7 @@ const (
 func init() {
 	p := ManualProvider{}
 	environs.RegisterProvider(providerType, p, "null")
+	if err := config.RegisterProviderSchema(providerType, config.ProviderConfigSchema{}); err != nil {
+		panic(err)
+	}
 }
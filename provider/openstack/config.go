@@ -96,13 +96,23 @@ func (p EnvironProvider) ConfigDefaults() schema.Defaults {
 	return configDefaults
 }
 
+// ProviderConfigSchema returns the schema of the provider specific
+// config attributes, with the defaults given by the provider's
+// configurator, for registration with config.RegisterProviderSchema.
+func (p EnvironProvider) ProviderConfigSchema() config.ProviderConfigSchema {
+	return config.ProviderConfigSchema{
+		Fields:   configSchema,
+		Defaults: p.Configurator.GetConfigDefaults(),
+	}
+}
+
 func (p EnvironProvider) Validate(cfg, old *config.Config) (valid *config.Config, err error) {
 	// Check for valid changes for the base config values.
 	if err := config.Validate(cfg, old); err != nil {
 		return nil, err
 	}
 
-	validated, err := cfg.ValidateUnknownAttrs(configFields, p.Configurator.GetConfigDefaults())
+	validated, err := cfg.ValidateProviderAttrs()
 	if err != nil {
 		return nil, err
 	}
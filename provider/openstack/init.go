@@ -5,6 +5,7 @@ package openstack
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tools"
 )
 
@@ -14,6 +15,9 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, providerInstance)
+	if err := config.RegisterProviderSchema(providerType, providerInstance.ProviderConfigSchema()); err != nil {
+		panic(err)
+	}
 
 	environs.RegisterImageDataSourceFunc("keystone catalog", getKeystoneImageSource)
 	tools.RegisterToolsDataSourceFunc("keystone catalog", getKeystoneToolsSource)
@@ -1615,7 +1615,7 @@ func (t *localServerSuite) TestGetAvailabilityZones(c *gc.C) {
 	t.PatchValue(openstack.NovaListAvailabilityZones, func(c *nova.Client) ([]nova.AvailabilityZone, error) {
 		return append([]nova.AvailabilityZone{}, resultZones...), resultErr
 	})
-	env := t.env.(common.ZonedEnviron)
+	env := t.env.(environs.ZonedEnviron)
 
 	resultErr = fmt.Errorf("failed to get availability zones")
 	zones, err := env.AvailabilityZones()
@@ -1646,7 +1646,7 @@ func (t *localServerSuite) TestGetAvailabilityZonesCommon(c *gc.C) {
 	t.PatchValue(openstack.NovaListAvailabilityZones, func(c *nova.Client) ([]nova.AvailabilityZone, error) {
 		return append([]nova.AvailabilityZone{}, resultZones...), nil
 	})
-	env := t.env.(common.ZonedEnviron)
+	env := t.env.(environs.ZonedEnviron)
 	resultZones = make([]nova.AvailabilityZone, 2)
 	resultZones[0].Name = "az1"
 	resultZones[1].Name = "az2"
@@ -1663,13 +1663,13 @@ func (t *localServerSuite) TestGetAvailabilityZonesCommon(c *gc.C) {
 
 type mockAvailabilityZoneAllocations struct {
 	group  []instance.Id // input param
-	result []common.AvailabilityZoneInstances
+	result []environs.AvailabilityZoneInstances
 	err    error
 }
 
 func (t *mockAvailabilityZoneAllocations) AvailabilityZoneAllocations(
-	e common.ZonedEnviron, group []instance.Id,
-) ([]common.AvailabilityZoneInstances, error) {
+	e environs.ZonedEnviron, group []instance.Id,
+) ([]environs.AvailabilityZoneInstances, error) {
 	t.group = group
 	return t.result, t.err
 }
@@ -1842,6 +1842,7 @@ func (t *localServerSuite) TestInstanceTags(c *gc.C) {
 		map[string]string{
 			"juju-model-uuid":      coretesting.ModelTag.Id(),
 			"juju-controller-uuid": coretesting.ControllerTag.Id(),
+			"juju-machine-id":      "0",
 			"juju-is-controller":   "true",
 		},
 	)
@@ -1862,6 +1863,7 @@ func (t *localServerSuite) TestTagInstance(c *gc.C) {
 			map[string]string{
 				"juju-model-uuid":      coretesting.ModelTag.Id(),
 				"juju-controller-uuid": coretesting.ControllerTag.Id(),
+				"juju-machine-id":      "0",
 				"juju-is-controller":   "true",
 				extraKey:               extraValue,
 			},
@@ -267,7 +267,7 @@ type Environ struct {
 	keystoneToolsDataSource      simplestreams.DataSource
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []environs.AvailabilityZone
 	firewaller             Firewaller
 	networking             Networking
 	configurator           ProviderConfigurator
@@ -476,6 +476,10 @@ func (e *Environ) neutron() *neutron.Client {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.GPUs,
+	constraints.GPUType,
+	// Instances always boot from the flavor's local disk.
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -514,7 +518,7 @@ func (z *openstackAvailabilityZone) Available() bool {
 }
 
 // AvailabilityZones returns a slice of availability zones.
-func (e *Environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *Environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -525,7 +529,7 @@ func (e *Environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
 		if err != nil {
 			return nil, err
 		}
-		e.availabilityZones = make([]common.AvailabilityZone, len(zones))
+		e.availabilityZones = make([]environs.AvailabilityZone, len(zones))
 		for i, z := range zones {
 			e.availabilityZones[i] = &openstackAvailabilityZone{z}
 		}
@@ -561,19 +565,13 @@ func (e *Environ) parsePlacement(placement string) (*openstackPlacement, error)
 	}
 	switch key, value := placement[:pos], placement[pos+1:]; key {
 	case "zone":
-		availabilityZone := value
-		zones, err := e.AvailabilityZones()
+		zone, err := environs.FindAvailabilityZone(e, value)
 		if err != nil {
 			return nil, err
 		}
-		for _, z := range zones {
-			if z.Name() == availabilityZone {
-				return &openstackPlacement{
-					z.(*openstackAvailabilityZone).AvailabilityZone,
-				}, nil
-			}
-		}
-		return nil, errors.Errorf("invalid availability zone %q", availabilityZone)
+		return &openstackPlacement{
+			zone.(*openstackAvailabilityZone).AvailabilityZone,
+		}, nil
 	}
 	return nil, errors.Errorf("unknown placement directive: %v", placement)
 }
@@ -595,7 +593,7 @@ func (e *Environ) PrecheckInstance(series string, cons constraints.Value, placem
 		return err
 	}
 	for _, flavor := range flavors {
-		if flavor.Name == *cons.InstanceType {
+		if cons.MatchesInstanceType(flavor.Name) {
 			return nil
 		}
 	}
@@ -912,7 +910,7 @@ func (e *Environ) DistributeInstances(candidates, distributionGroup []instance.I
 	return common.DistributeInstances(e, candidates, distributionGroup)
 }
 
-var availabilityZoneAllocations = common.AvailabilityZoneAllocations
+var availabilityZoneAllocations = environs.AvailabilityZoneAllocations
 
 // MaintainInstance is specified in the InstanceBroker interface.
 func (*Environ) MaintainInstance(args environs.StartInstanceParams) error {
@@ -1099,6 +1097,9 @@ func (e *Environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}
 	logger.Infof("started instance %q", inst.Id())
 	withPublicIP := e.ecfg().useFloatingIP()
+	if args.Constraints.HasAllocatePublicIP() {
+		withPublicIP = *args.Constraints.AllocatePublicIP
+	}
 	if withPublicIP {
 		var publicIP *string
 		logger.Debugf("allocating public IP address for openstack node")
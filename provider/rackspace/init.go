@@ -8,6 +8,7 @@ import (
 	"gopkg.in/goose.v1/identity"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/openstack"
 )
 
@@ -30,4 +31,7 @@ func init() {
 		osProvider,
 	}
 	environs.RegisterProvider(providerType, providerInstance)
+	if err := config.RegisterProviderSchema(providerType, osProvider.ProviderConfigSchema()); err != nil {
+		panic(err)
+	}
 }
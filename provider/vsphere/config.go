@@ -8,6 +8,7 @@ package vsphere
 import (
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
@@ -17,12 +18,23 @@ const (
 	cfgExternalNetwork = "external-network"
 )
 
-// configFields is the spec for each vmware config value's type.
 var (
-	configFields = schema.Fields{
-		cfgExternalNetwork: schema.String(),
+	configSchema = environschema.Fields{
+		cfgExternalNetwork: {
+			Description: "The name of the network on which machines are given an external address.",
+			Type:        environschema.Tstring,
+		},
 	}
 
+	// configFields is the spec for each vmware config value's type.
+	configFields = func() schema.Fields {
+		fs, _, err := configSchema.ValidationSchema()
+		if err != nil {
+			panic(err)
+		}
+		return fs
+	}()
+
 	requiredFields = []string{}
 
 	configDefaults = schema.Defaults{
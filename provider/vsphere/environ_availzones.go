@@ -11,31 +11,30 @@ import (
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
-	"github.com/juju/juju/provider/common"
 )
 
 type vmwareAvailZone struct {
 	r mo.ComputeResource
 }
 
-// Name implements common.AvailabilityZone
+// Name implements environs.AvailabilityZone
 func (z *vmwareAvailZone) Name() string {
 	return z.r.Name
 }
 
-// Available implements common.AvailabilityZone
+// Available implements environs.AvailabilityZone
 func (z *vmwareAvailZone) Available() bool {
 	return true
 }
 
 // AvailabilityZones returns all availability zones in the environment.
-func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	zones, err := env.client.AvailabilityZones()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	var result []common.AvailabilityZone
+	var result []environs.AvailabilityZone
 	for _, zone := range zones {
 		result = append(result, &vmwareAvailZone{*zone})
 	}
@@ -69,23 +68,18 @@ func (env *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string,
 }
 
 func (env *environ) availZone(name string) (*vmwareAvailZone, error) {
-	zones, err := env.client.AvailabilityZones()
+	zone, err := environs.FindAvailabilityZone(env, name)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	for _, z := range zones {
-		if z.Name == name {
-			return &vmwareAvailZone{*z}, nil
-		}
-	}
-	return nil, errors.NotFoundf("invalid availability zone %q", name)
+	return zone.(*vmwareAvailZone), nil
 }
 
 //AvailabilityZoneAllocations is exported, because it has to be rewritten in external unit tests
-var AvailabilityZoneAllocations = common.AvailabilityZoneAllocations
+var AvailabilityZoneAllocations = environs.AvailabilityZoneAllocations
 
 // AllAvailabilityZones is exported because it has to be patched in external unit tests.
-var AllAvailabilityZones = func(env common.ZonedEnviron) ([]common.AvailabilityZone, error) {
+var AllAvailabilityZones = func(env environs.ZonedEnviron) ([]environs.AvailabilityZone, error) {
 	return env.AvailabilityZones()
 }
 
@@ -61,8 +61,8 @@ func (s *environBrokerSuite) PrepareStartInstanceFakesForNewImplementation(c *gc
 }
 
 func (s *environBrokerSuite) fakeAvailabilityZonesAllocations() {
-	fakeAZAllocations := func(env common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
-		return []common.AvailabilityZoneInstances{
+	fakeAZAllocations := func(env environs.ZonedEnviron, group []instance.Id) ([]environs.AvailabilityZoneInstances, error) {
+		return []environs.AvailabilityZoneInstances{
 			{ZoneName: "z1"},
 		}, nil
 	}
@@ -82,8 +82,8 @@ func (f fakeAZ) Available() bool {
 }
 func (s *environBrokerSuite) fakeAllAvailabilityZones() {
 
-	fakeAllAZ := func(env common.ZonedEnviron) ([]common.AvailabilityZone, error) {
-		return []common.AvailabilityZone{
+	fakeAllAZ := func(env environs.ZonedEnviron) ([]environs.AvailabilityZone, error) {
+		return []environs.AvailabilityZone{
 			fakeAZ{name: "z1"},
 		}, nil
 	}
@@ -249,7 +249,7 @@ func (s *environBrokerSuite) TestStartInstanceCallsAvailabilityZoneAllocations(c
 	startInstArgs.DistributionGroup = func() ([]instance.Id, error) {
 		return []instance.Id{instance.Id("someId")}, nil
 	}
-	s.PatchValue(&vsphere.AvailabilityZoneAllocations, func(env common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+	s.PatchValue(&vsphere.AvailabilityZoneAllocations, func(env environs.ZonedEnviron, group []instance.Id) ([]environs.AvailabilityZoneInstances, error) {
 		c.Assert(len(group), gc.Equals, 1)
 		c.Assert(string(group[0]), gc.Equals, "someId")
 		return nil, errors.New("AvailabilityZoneAllocations called")
@@ -271,16 +271,16 @@ func (s *environBrokerSuite) TestStartInstanceTriesToCreateInstanceInAllAvailabi
 	s.FakeAvailabilityZones(client, "z1", "z2")
 	client.SetPropertyProxyHandler("FakeRootFolder", vsphere.RetrieveDatacenter)
 
-	fakeAZAllocations := func(env common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
-		return []common.AvailabilityZoneInstances{
+	fakeAZAllocations := func(env environs.ZonedEnviron, group []instance.Id) ([]environs.AvailabilityZoneInstances, error) {
+		return []environs.AvailabilityZoneInstances{
 			{ZoneName: "z1"},
 			{ZoneName: "z2"},
 		}, nil
 	}
 	s.PatchValue(&vsphere.AvailabilityZoneAllocations, fakeAZAllocations)
 
-	fakeAllAZ := func(env common.ZonedEnviron) ([]common.AvailabilityZone, error) {
-		return []common.AvailabilityZone{
+	fakeAllAZ := func(env environs.ZonedEnviron) ([]environs.AvailabilityZone, error) {
+		return []environs.AvailabilityZone{
 			fakeAZ{name: "z1"},
 			fakeAZ{name: "z2"},
 		}, nil
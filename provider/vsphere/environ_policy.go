@@ -68,6 +68,9 @@ func (env *environ) lookupArchitectures() ([]string, error) {
 }
 
 var unsupportedConstraints = []string{
+	constraints.GPUs,
+	constraints.GPUType,
+	constraints.RootDiskSource,
 	constraints.Tags,
 	constraints.VirtType,
 }
@@ -77,6 +80,8 @@ var unsupportedConstraints = []string{
 func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	// VMs are created with exactly the minimum resources.
+	validator.RegisterUnsupportedRanges([]string{constraints.Cores, constraints.Mem})
 
 	supportedArches, err := env.allSupportedArchitectures()
 	if err != nil {
@@ -5,7 +5,10 @@
 
 package vsphere
 
-import "github.com/juju/juju/environs"
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
 
 const (
 	providerType = "vsphere"
@@ -13,4 +16,10 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, providerInstance)
+	if err := config.RegisterProviderSchema(providerType, config.ProviderConfigSchema{
+		Fields:   configSchema,
+		Defaults: configDefaults,
+	}); err != nil {
+		panic(err)
+	}
 }
@@ -28,6 +28,10 @@ type Call struct {
 type RequestError struct {
 	Message string
 	Code    string
+
+	// CorrelationId identifies the failed request in the logs of
+	// the server, if the server supplied it.
+	CorrelationId string
 }
 
 func (e *RequestError) Error() string {
@@ -109,8 +113,9 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		// any subsequent requests will get the ReadResponseBody
 		// error if there is one.
 		call.Error = &RequestError{
-			Message: hdr.Error,
-			Code:    hdr.ErrorCode,
+			Message:       hdr.Error,
+			Code:          hdr.ErrorCode,
+			CorrelationId: hdr.CorrelationId,
 		}
 		err = conn.readBody(nil, false)
 		call.done()
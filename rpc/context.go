@@ -0,0 +1,26 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpc
+
+import (
+	"golang.org/x/net/context"
+)
+
+type correlationIdKey struct{}
+
+// WithCorrelationId returns a copy of ctx carrying the given
+// correlation id.
+func WithCorrelationId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIdKey{}, id)
+}
+
+// CorrelationId returns the correlation id of the request on whose
+// behalf ctx was created, or the empty string if there is none.
+func CorrelationId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationIdKey{}).(string)
+	return id
+}
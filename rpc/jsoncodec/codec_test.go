@@ -102,6 +102,15 @@ func (*suite) TestRead(c *gc.C) {
 			Version:   1,
 		},
 		expectBody: &value{X: "result"},
+	}, {
+		msg: `{"request-id": 5, "error": "an error", "correlation-id": "0123456789abcdef"}`,
+		expectHdr: rpc.Header{
+			RequestId:     5,
+			Error:         "an error",
+			Version:       1,
+			CorrelationId: "0123456789abcdef",
+		},
+		expectBody: new(map[string]interface{}),
 	}, {
 		msg: `{"request-id": 4, "type": "foo", "version": 2, "id": "id", "request": "frob", "params": {"X": "param"}}`,
 		expectHdr: rpc.Header{
@@ -223,6 +232,14 @@ func (*suite) TestWrite(c *gc.C) {
 		},
 		body:   &value{X: "result"},
 		expect: `{"request-id": 3, "response": {"X": "result"}}`,
+	}, {
+		hdr: &rpc.Header{
+			RequestId:     5,
+			Error:         "an error",
+			Version:       1,
+			CorrelationId: "0123456789abcdef",
+		},
+		expect: `{"request-id": 5, "error": "an error", "correlation-id": "0123456789abcdef"}`,
 	}, {
 		hdr: &rpc.Header{
 			RequestId: 4,
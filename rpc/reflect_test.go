@@ -7,8 +7,10 @@ import (
 	"reflect"
 
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/testing"
 )
@@ -33,6 +35,7 @@ func (*reflectSuite) TestTypeOf(c *gc.C) {
 		"CallbackMethods":  reflect.TypeOf(&CallbackMethods{}),
 		"ChangeAPIMethods": reflect.TypeOf(&ChangeAPIMethods{}),
 		"DelayedMethods":   reflect.TypeOf(&DelayedMethods{}),
+		"DelayedWatcher":   reflect.TypeOf(&DelayedWatcher{}),
 		"ErrorMethods":     reflect.TypeOf(&ErrorMethods{}),
 		"InterfaceMethods": reflect.TypeOf((*InterfaceMethods)(nil)).Elem(),
 		"SimpleMethods":    reflect.TypeOf(&SimpleMethods{}),
@@ -130,7 +133,7 @@ func (*reflectSuite) TestFindMethod(c *gc.C) {
 	c.Assert(m.ParamsType(), gc.Equals, reflect.TypeOf(stringVal{}))
 	c.Assert(m.ResultType(), gc.Equals, reflect.TypeOf(stringVal{}))
 
-	ret, err := m.Call("a99", reflect.ValueOf(stringVal{"foo"}))
+	ret, err := m.Call(context.Background(), "a99", reflect.ValueOf(stringVal{"foo"}))
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ret.Interface(), gc.Equals, stringVal{"Call1r1e ret"})
 }
@@ -151,3 +154,36 @@ func (*reflectSuite) TestFindMethodRefusesVersionsNot0(c *gc.C) {
 	c.Assert(err, gc.FitsTypeOf, (*rpcreflect.CallNotImplementedError)(nil))
 	c.Assert(err, gc.ErrorMatches, `unknown version \(1\) of interface "SimpleMethods"`)
 }
+
+type contextMethods struct{}
+
+func (contextMethods) NoArg(ctx context.Context) stringVal {
+	return stringVal{rpc.CorrelationId(ctx)}
+}
+
+func (contextMethods) Arg(ctx context.Context, arg stringVal) (stringVal, error) {
+	return stringVal{arg.Val + " " + rpc.CorrelationId(ctx)}, nil
+}
+
+func (contextMethods) Discard(ctx context.Context, a, b stringVal) {}
+
+func (*reflectSuite) TestObjTypeOfContextMethods(c *gc.C) {
+	objType := rpcreflect.ObjTypeOf(reflect.TypeOf(contextMethods{}))
+	c.Check(objType.MethodNames(), jc.SameContents, []string{"Arg", "NoArg"})
+	c.Check(objType.DiscardedMethods(), gc.DeepEquals, []string{"Discard"})
+	ctx := rpc.WithCorrelationId(context.Background(), "0123456789abcdef")
+
+	m, err := objType.Method("NoArg")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Params, gc.IsNil)
+	ret, err := m.Call(ctx, reflect.ValueOf(contextMethods{}), reflect.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ret.Interface(), gc.Equals, stringVal{"0123456789abcdef"})
+
+	m, err = objType.Method("Arg")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Params, gc.Equals, reflect.TypeOf(stringVal{}))
+	ret, err = m.Call(ctx, reflect.ValueOf(contextMethods{}), reflect.ValueOf(stringVal{"arg"}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ret.Interface(), gc.Equals, stringVal{"arg 0123456789abcdef"})
+}
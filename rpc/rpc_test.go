@@ -15,6 +15,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
@@ -83,6 +84,15 @@ func (r *Root) DelayedMethods(id string) (*DelayedMethods, error) {
 	return nil, fmt.Errorf("unknown DelayedMethods id")
 }
 
+func (r *Root) DelayedWatcher(id string) (*DelayedWatcher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a := r.delayed[id]; a != nil {
+		return &DelayedWatcher{a}, nil
+	}
+	return nil, fmt.Errorf("unknown DelayedWatcher id")
+}
+
 func (r *Root) ErrorMethods(id string) (*ErrorMethods, error) {
 	if r.errorInst == nil {
 		return nil, fmt.Errorf("no error methods")
@@ -208,6 +218,16 @@ func (a *DelayedMethods) Delay() (stringVal, error) {
 	}
 }
 
+// DelayedWatcher serves DelayedMethods as the Next method of a
+// watcher.
+type DelayedWatcher struct {
+	methods *DelayedMethods
+}
+
+func (w *DelayedWatcher) Next() (stringVal, error) {
+	return w.methods.Delay()
+}
+
 type ErrorMethods struct {
 	err error
 }
@@ -297,7 +317,7 @@ func (c customMethodCaller) ResultType() reflect.Type {
 	return c.objMethod.Result
 }
 
-func (c customMethodCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+func (c customMethodCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
 	sm, err := c.root.SimpleMethods(objId)
 	if err != nil {
 		return reflect.Value{}, err
@@ -307,7 +327,7 @@ func (c customMethodCaller) Call(objId string, arg reflect.Value) (reflect.Value
 		logger.Errorf("got the wrong type back, expected %s got %T", c.expectedType, obj)
 	}
 	logger.Debugf("calling: %T %v %#v", obj, obj, c.objMethod)
-	return c.objMethod.Call(obj, arg)
+	return c.objMethod.Call(ctx, obj, arg)
 }
 
 func (cc *CustomRoot) Kill() {
@@ -504,10 +524,13 @@ func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint
 	// Test that there was a notification for the request.
 	c.Assert(p.serverNotifier.serverRequests, gc.HasLen, 1)
 	serverReq := p.serverNotifier.serverRequests[0]
+	correlationId := serverReq.hdr.CorrelationId
+	c.Assert(correlationId, gc.Not(gc.Equals), "")
 	c.Assert(serverReq.hdr, gc.DeepEquals, rpc.Header{
-		RequestId: requestId,
-		Request:   p.request(),
-		Version:   1,
+		RequestId:     requestId,
+		Request:       p.request(),
+		Version:       1,
+		CorrelationId: correlationId,
 	})
 	if p.narg > 0 {
 		c.Assert(serverReq.body, gc.Equals, stringVal{"arg"})
@@ -526,9 +549,10 @@ func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint
 	}
 	if p.retErr && p.testErr {
 		c.Assert(serverReply.hdr, gc.Equals, rpc.Header{
-			RequestId: requestId,
-			Error:     p.errorMessage(),
-			Version:   1,
+			RequestId:     requestId,
+			Error:         p.errorMessage(),
+			Version:       1,
+			CorrelationId: correlationId,
 		})
 	} else {
 		c.Assert(serverReply.hdr, gc.Equals, rpc.Header{
@@ -800,6 +824,131 @@ func (*rpcSuite) TestServerWaitsForOutstandingCalls(c *gc.C) {
 	start <- "xxx"
 }
 
+func (*rpcSuite) TestDrain(c *gc.C) {
+	ready := make(chan struct{})
+	doneError := make(chan error)
+	root := &Root{
+		delayed: map[string]*DelayedMethods{
+			"1": {
+				ready:     ready,
+				doneError: doneError,
+			},
+		},
+	}
+	client, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	callDone := make(chan error, 1)
+	go func() {
+		var r stringVal
+		callDone <- client.Call(rpc.Request{"DelayedMethods", 0, "1", "Delay"}, nil, &r)
+	}()
+	chanRead(c, ready, "DelayedMethods.Delay ready")
+
+	drained := make(chan bool, 1)
+	go func() {
+		drained <- root.conn.Drain(errors.New("draining"), nil)
+	}()
+	select {
+	case <-drained:
+		c.Fatalf("drain completed while outstanding operation in progress")
+	case <-time.After(25 * time.Millisecond):
+	}
+
+	// New requests are refused.
+	err := client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, "draining")
+
+	// Outstanding requests that fail report their own errors.
+	doneError <- errors.New("aborted")
+	select {
+	case err := <-callDone:
+		c.Assert(err, gc.ErrorMatches, "aborted")
+	case <-time.After(3 * time.Second):
+		c.Fatalf("timed out waiting for outstanding call")
+	}
+	select {
+	case ok := <-drained:
+		c.Assert(ok, jc.IsTrue)
+	case <-time.After(3 * time.Second):
+		c.Fatalf("timed out waiting for drain")
+	}
+}
+
+func (*rpcSuite) TestDrainWatcherNext(c *gc.C) {
+	ready := make(chan struct{})
+	doneError := make(chan error)
+	root := &Root{
+		delayed: map[string]*DelayedMethods{
+			"1": {
+				ready:     ready,
+				doneError: doneError,
+			},
+		},
+	}
+	client, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	callDone := make(chan error, 1)
+	go func() {
+		var r stringVal
+		callDone <- client.Call(rpc.Request{"DelayedWatcher", 0, "1", "Next"}, nil, &r)
+	}()
+	chanRead(c, ready, "DelayedWatcher.Next ready")
+
+	drained := make(chan bool, 1)
+	go func() {
+		drained <- root.conn.Drain(errors.New("draining"), nil)
+	}()
+
+	// Aborted watcher Next calls report the drain error.
+	doneError <- errors.New("watcher was stopped")
+	select {
+	case err := <-callDone:
+		c.Assert(err, gc.ErrorMatches, "draining")
+	case <-time.After(3 * time.Second):
+		c.Fatalf("timed out waiting for outstanding call")
+	}
+	select {
+	case ok := <-drained:
+		c.Assert(ok, jc.IsTrue)
+	case <-time.After(3 * time.Second):
+		c.Fatalf("timed out waiting for drain")
+	}
+}
+
+func (*rpcSuite) TestDrainAbort(c *gc.C) {
+	ready := make(chan struct{})
+	start := make(chan string)
+	root := &Root{
+		delayed: map[string]*DelayedMethods{
+			"1": {
+				ready: ready,
+				done:  start,
+			},
+		},
+	}
+	client, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	callDone := make(chan error, 1)
+	go func() {
+		var r stringVal
+		callDone <- client.Call(rpc.Request{"DelayedMethods", 0, "1", "Delay"}, nil, &r)
+	}()
+	chanRead(c, ready, "DelayedMethods.Delay ready")
+
+	abort := make(chan struct{})
+	close(abort)
+	c.Assert(root.conn.Drain(errors.New("draining"), abort), jc.IsFalse)
+
+	// Calls that succeed while draining return their results.
+	start <- "xxx"
+	select {
+	case err := <-callDone:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(3 * time.Second):
+		c.Fatalf("timed out waiting for outstanding call")
+	}
+}
+
 func chanRead(c *gc.C, ch <-chan struct{}, what string) {
 	select {
 	case <-ch:
@@ -907,24 +1056,30 @@ func testBadCall(
 	if requestKnown {
 		expectBody = struct{}{}
 	}
+	correlationId := serverNotifier.serverRequests[0].hdr.CorrelationId
+	c.Assert(correlationId, gc.Not(gc.Equals), "")
 	c.Assert(serverNotifier.serverRequests[0], gc.DeepEquals, requestEvent{
 		hdr: rpc.Header{
-			RequestId: client.ClientRequestID(),
-			Request:   req,
-			Version:   1,
+			RequestId:     client.ClientRequestID(),
+			Request:       req,
+			Version:       1,
+			CorrelationId: correlationId,
 		},
 		body: expectBody,
 	})
+	c.Assert(errors.Cause(err), gc.FitsTypeOf, &rpc.RequestError{})
+	c.Assert(errors.Cause(err).(*rpc.RequestError).CorrelationId, gc.Equals, correlationId)
 
 	// Test that there was a notification for the server reply.
 	c.Assert(serverNotifier.serverReplies, gc.HasLen, 1)
 	serverReply := serverNotifier.serverReplies[0]
 	c.Assert(serverReply, gc.DeepEquals, replyEvent{
 		hdr: rpc.Header{
-			RequestId: client.ClientRequestID(),
-			Error:     expectedErr,
-			ErrorCode: expectedErrCode,
-			Version:   1,
+			RequestId:     client.ClientRequestID(),
+			Error:         expectedErr,
+			ErrorCode:     expectedErrCode,
+			Version:       1,
+			CorrelationId: correlationId,
 		},
 		req:  req,
 		body: struct{}{},
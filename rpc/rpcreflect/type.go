@@ -8,11 +8,14 @@ import (
 	"reflect"
 	"sort"
 	"sync"
+
+	"golang.org/x/net/context"
 )
 
 var (
-	errorType  = reflect.TypeOf((*error)(nil)).Elem()
-	stringType = reflect.TypeOf("")
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	stringType  = reflect.TypeOf("")
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 )
 
 var (
@@ -215,7 +218,9 @@ type ObjMethod struct {
 	// Call calls the method with the given argument
 	// on the given receiver value. If the method does
 	// not return a value, the returned value will not be valid.
-	Call func(rcvr, arg reflect.Value) (reflect.Value, error)
+	// The context is passed to methods that take a
+	// context.Context as their first parameter.
+	Call func(ctx context.Context, rcvr, arg reflect.Value) (reflect.Value, error)
 }
 
 // ObjTypeOf returns information on all RPC methods
@@ -269,7 +274,7 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 		return nil
 	}
 	var p ObjMethod
-	var assemble func(arg reflect.Value) []reflect.Value
+	var assemble func(ctx context.Context, arg reflect.Value) []reflect.Value
 	// N.B. The method type has the receiver as its first argument
 	// unless the receiver is an interface.
 	receiverArgCount := 1
@@ -277,16 +282,28 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 		receiverArgCount = 0
 	}
 	t := m.Type
+	// Methods may take a context.Context before any argument.
+	withContext := t.NumIn() > receiverArgCount && t.In(receiverArgCount) == contextType
+	argCount := receiverArgCount
+	if withContext {
+		argCount++
+	}
 	switch {
-	case t.NumIn() == 0+receiverArgCount:
-		// Method() ...
-		assemble = func(arg reflect.Value) []reflect.Value {
+	case t.NumIn() == 0+argCount:
+		// Method([ctx]) ...
+		assemble = func(ctx context.Context, arg reflect.Value) []reflect.Value {
+			if withContext {
+				return []reflect.Value{reflect.ValueOf(&ctx).Elem()}
+			}
 			return nil
 		}
-	case t.NumIn() == 1+receiverArgCount:
-		// Method(T) ...
-		p.Params = t.In(receiverArgCount)
-		assemble = func(arg reflect.Value) []reflect.Value {
+	case t.NumIn() == 1+argCount:
+		// Method([ctx, ]T) ...
+		p.Params = t.In(argCount)
+		assemble = func(ctx context.Context, arg reflect.Value) []reflect.Value {
+			if withContext {
+				return []reflect.Value{reflect.ValueOf(&ctx).Elem(), arg}
+			}
 			return []reflect.Value{arg}
 		}
 	default:
@@ -296,14 +313,14 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	switch {
 	case t.NumOut() == 0:
 		// Method(...)
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			rcvr.Method(m.Index).Call(assemble(arg))
+		p.Call = func(ctx context.Context, rcvr, arg reflect.Value) (r reflect.Value, err error) {
+			rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			return
 		}
 	case t.NumOut() == 1 && t.Out(0) == errorType:
 		// Method(...) error
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.Call = func(ctx context.Context, rcvr, arg reflect.Value) (r reflect.Value, err error) {
+			out := rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			if !out[0].IsNil() {
 				err = out[0].Interface().(error)
 			}
@@ -312,15 +329,15 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	case t.NumOut() == 1:
 		// Method(...) R
 		p.Result = t.Out(0)
-		p.Call = func(rcvr, arg reflect.Value) (reflect.Value, error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.Call = func(ctx context.Context, rcvr, arg reflect.Value) (reflect.Value, error) {
+			out := rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			return out[0], nil
 		}
 	case t.NumOut() == 2 && t.Out(1) == errorType:
 		// Method(...) (R, error)
 		p.Result = t.Out(0)
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.Call = func(ctx context.Context, rcvr, arg reflect.Value) (r reflect.Value, err error) {
+			out := rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			r = out[0]
 			if !out[1].IsNil() {
 				err = out[1].Interface().(error)
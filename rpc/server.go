@@ -4,12 +4,16 @@
 package rpc
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/rpc/rpcreflect"
 )
@@ -64,6 +68,11 @@ type Header struct {
 
 	// Version defines the wire format of the request and response structure.
 	Version int
+
+	// CorrelationId identifies a single request in the logs of the
+	// server, and is returned to the client when the request fails.
+	// The server generates one for requests that do not supply it.
+	CorrelationId string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -136,6 +145,10 @@ type Conn struct {
 	// will be initiated.
 	closing bool
 
+	// drainErr is set when the connection is being drained via
+	// Drain. When this is set, server requests fail with it.
+	drainErr error
+
 	// shutdown is set when the input loop terminates. When this
 	// is set, no more client requests will be sent to the server.
 	shutdown bool
@@ -204,6 +217,10 @@ func (conn *Conn) Start() {
 //	Method(T) (R, error)
 //	Method(T) error
 //
+// Any of these methods may also take a context.Context as its first
+// parameter, as in Method(ctx) or Method(ctx, T). The context carries
+// the correlation id of the request; see CorrelationId.
+//
 // If transformErrors is non-nil, it will be called on all returned
 // non-nil errors, for example to transform the errors into ServerErrors
 // with specified codes.  There will be a panic if transformErrors
@@ -296,6 +313,44 @@ func (conn *Conn) Close() error {
 	return conn.inputLoopError
 }
 
+// Drain starts an orderly shutdown of the server side of the
+// connection. New requests are refused with err, and the root is
+// asked to abort outstanding requests. Watcher Next calls aborted
+// this way fail with err; other requests report their own errors,
+// so that only requests known to be safe to retry look retryable.
+// Drain waits until outstanding requests have
+// completed or abort is closed, and reports whether they completed.
+// Close must still be called to release the connection.
+func (conn *Conn) Drain(err error, abort <-chan struct{}) bool {
+	conn.mutex.Lock()
+	if conn.drainErr == nil {
+		conn.drainErr = err
+		if conn.root != nil {
+			conn.root.Kill()
+		}
+	}
+	conn.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		conn.srvPending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-abort:
+		return false
+	}
+}
+
+// drainError returns the error passed to Drain, if it has been called.
+func (conn *Conn) drainError() error {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	return conn.drainErr
+}
+
 // ErrorCoder represents an any error that has an associated
 // error code. An error code is a short string that represents the
 // kind of an error.
@@ -372,6 +427,9 @@ func (conn *Conn) readBody(resp interface{}, isRequest bool) error {
 }
 
 func (conn *Conn) handleRequest(hdr *Header) error {
+	if hdr.CorrelationId == "" {
+		hdr.CorrelationId = newCorrelationId()
+	}
 	observer := conn.observerFactory.RPCObserver()
 	req, err := conn.bindRequest(hdr)
 	if err != nil {
@@ -413,9 +471,13 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 	} else {
 		observer.ServerRequest(hdr, struct{}{})
 	}
+	// The request must not be counted in srvPending once Drain or
+	// Close may be waiting for it, so both are checked under the
+	// same lock as the counting.
 	conn.mutex.Lock()
 	closing := conn.closing
-	if !closing {
+	drainErr := conn.drainErr
+	if !closing && drainErr == nil {
 		conn.srvPending.Add(1)
 		go conn.runRequest(req, arg, hdr.Version, observer)
 	}
@@ -424,6 +486,9 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 		// We're closing down - no new requests may be initiated.
 		return conn.writeErrorResponse(hdr, req.transformErrors(ErrShutdown), observer)
 	}
+	if drainErr != nil {
+		return conn.writeErrorResponse(hdr, req.transformErrors(drainErr), observer)
+	}
 	return nil
 }
 
@@ -431,8 +496,9 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, observer Observe
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
 	hdr := &Header{
-		RequestId: reqHdr.RequestId,
-		Version:   reqHdr.Version,
+		RequestId:     reqHdr.RequestId,
+		Version:       reqHdr.Version,
+		CorrelationId: reqHdr.CorrelationId,
 	}
 	if err, ok := err.(ErrorCoder); ok {
 		hdr.ErrorCode = err.ErrorCode()
@@ -440,6 +506,10 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, observer Observe
 		hdr.ErrorCode = ""
 	}
 	hdr.Error = err.Error()
+	logger.Infof(
+		"%s.%s request %d failed (correlation id %s): %v",
+		reqHdr.Request.Type, reqHdr.Request.Action, reqHdr.RequestId, reqHdr.CorrelationId, hdr.Error,
+	)
 	observer.ServerReply(reqHdr.Request, hdr, struct{}{})
 
 	return conn.codec.WriteMessage(hdr, struct{}{})
@@ -460,8 +530,15 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 	conn.mutex.Lock()
 	root := conn.root
 	transformErrors := conn.transformErrors
+	drainErr := conn.drainErr
 	conn.mutex.Unlock()
 
+	if drainErr != nil {
+		if transformErrors != nil {
+			drainErr = transformErrors(drainErr)
+		}
+		return boundRequest{}, drainErr
+	}
 	if root == nil {
 		return boundRequest{}, errors.New("no service")
 	}
@@ -487,8 +564,17 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 // runRequest runs the given request and sends the reply.
 func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, version int, observer Observer) {
 	defer conn.srvPending.Done()
-	rv, err := req.Call(req.hdr.Request.Id, arg)
+	ctx := WithCorrelationId(context.Background(), req.hdr.CorrelationId)
+	rv, err := req.Call(ctx, req.hdr.Request.Id, arg)
 	if err != nil {
+		// Watchers are stopped when the connection is drained, so
+		// report the reason for that rather than the watcher's
+		// own error.
+		if isWatcherNext(req.hdr.Request) {
+			if drainErr := conn.drainError(); drainErr != nil {
+				err = drainErr
+			}
+		}
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), observer)
 	} else {
 		hdr := &Header{
@@ -511,6 +597,23 @@ func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, version int, o
 	}
 }
 
+// isWatcherNext reports whether the request is a call to the Next
+// method of a watcher facade.
+func isWatcherNext(req Request) bool {
+	return req.Action == "Next" && strings.HasSuffix(req.Type, "Watcher")
+}
+
+// newCorrelationId returns a random identifier for a server request,
+// or the empty string if one cannot be generated.
+func newCorrelationId() string {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		logger.Warningf("cannot generate correlation id: %v", err)
+		return ""
+	}
+	return hex.EncodeToString(buf[:])
+}
+
 type serverError struct {
 	error
 }
@@ -19,6 +19,43 @@ const (
 	agentServiceTimeout = 300 // 5 minutes
 )
 
+// AgentSlice is the cgroup slice in which agent services with
+// resource limits are run.
+const AgentSlice = "juju-agents.slice"
+
+// AgentResourceLimits holds the resource limits applied to an agent's
+// init service. A zero value means no limit.
+type AgentResourceLimits struct {
+	// CPUQuota is the CPU time available to the agent, as a
+	// percentage of a single CPU.
+	CPUQuota int
+
+	// MemoryLimit is the memory available to the agent, in megabytes.
+	MemoryLimit uint64
+}
+
+// IsZero reports whether no resource limits are set.
+func (l AgentResourceLimits) IsZero() bool {
+	return l.CPUQuota <= 0 && l.MemoryLimit == 0
+}
+
+// Validate checks that the limits are sensible.
+func (l AgentResourceLimits) Validate() error {
+	if l.CPUQuota < 0 {
+		return errors.NotValidf("negative CPU quota %d", l.CPUQuota)
+	}
+	return nil
+}
+
+func (l AgentResourceLimits) apply(conf *common.Conf) {
+	if l.IsZero() {
+		return
+	}
+	conf.Slice = AgentSlice
+	conf.CPUQuota = l.CPUQuota
+	conf.MemoryLimit = l.MemoryLimit
+}
+
 // AgentConf returns the data that defines an init service config
 // for the identified agent.
 func AgentConf(info AgentInfo, renderer shell.Renderer) common.Conf {
@@ -40,6 +77,7 @@ func AgentConf(info AgentInfo, renderer shell.Renderer) common.Conf {
 	case AgentKindUnit:
 		conf.Desc = "juju unit agent for " + info.ID
 	}
+	info.Limits.apply(&conf)
 
 	return conf
 }
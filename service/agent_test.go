@@ -111,6 +111,39 @@ func (*agentSuite) TestAgentConfMachineUbuntu(c *gc.C) {
 	})
 }
 
+func (*agentSuite) TestAgentConfResourceLimits(c *gc.C) {
+	info := service.NewUnitAgentInfo("wordpress/0", "/var/lib/juju", "/var/log/juju")
+	info.Limits = service.AgentResourceLimits{
+		CPUQuota:    50,
+		MemoryLimit: 512,
+	}
+	renderer, err := shell.NewRenderer("ubuntu")
+	c.Assert(err, jc.ErrorIsNil)
+	conf := service.AgentConf(info, renderer)
+
+	c.Check(conf.Slice, gc.Equals, service.AgentSlice)
+	c.Check(conf.CPUQuota, gc.Equals, 50)
+	c.Check(conf.MemoryLimit, gc.Equals, uint64(512))
+}
+
+func (*agentSuite) TestAgentConfNoResourceLimits(c *gc.C) {
+	info := service.NewUnitAgentInfo("wordpress/0", "/var/lib/juju", "/var/log/juju")
+	renderer, err := shell.NewRenderer("ubuntu")
+	c.Assert(err, jc.ErrorIsNil)
+	conf := service.AgentConf(info, renderer)
+
+	c.Check(conf.Slice, gc.Equals, "")
+	c.Check(conf.CPUQuota, gc.Equals, 0)
+	c.Check(conf.MemoryLimit, gc.Equals, uint64(0))
+}
+
+func (*agentSuite) TestAgentResourceLimitsValidate(c *gc.C) {
+	err := service.AgentResourceLimits{CPUQuota: -1}.Validate()
+	c.Check(err, gc.ErrorMatches, "negative CPU quota -1 not valid")
+	err = service.AgentResourceLimits{CPUQuota: 200, MemoryLimit: 1024}.Validate()
+	c.Check(err, jc.ErrorIsNil)
+}
+
 func (*agentSuite) TestAgentConfMachineWindows(c *gc.C) {
 	dataDir := `C:\Juju\lib\juju`
 	logDir := `C:\Juju\logs\juju`
@@ -44,6 +44,10 @@ type AgentInfo struct {
 
 	// LogDir is the path to the agent's log dir.
 	LogDir string
+
+	// Limits holds the resource limits, if any, applied to the
+	// agent's init service.
+	Limits AgentResourceLimits
 }
 
 // NewAgentInfo composes a new AgentInfo for the given essentials.
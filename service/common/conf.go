@@ -36,6 +36,20 @@ type Conf struct {
 	// Currently not used on Windows.
 	Limit map[string]int
 
+	// Slice is the name of the cgroup slice, if any, in which the
+	// service runs. Currently only used by systemd.
+	Slice string
+
+	// CPUQuota, if positive, limits the CPU time available to the
+	// service, as a percentage of a single CPU.
+	// Currently only used by systemd.
+	CPUQuota int
+
+	// MemoryLimit, if positive, limits the memory available to the
+	// service, in megabytes.
+	// Currently only used by systemd.
+	MemoryLimit uint64
+
 	// Timeout is how many seconds may pass before an exec call (e.g.
 	// ExecStart) times out. Values less than or equal to 0 (the
 	// default) are treated as though there is no timeout.
@@ -93,6 +107,10 @@ func (c Conf) Validate(renderer shell.Renderer) error {
 		}
 	}
 
+	if c.CPUQuota < 0 {
+		return errors.NotValidf("negative CPUQuota %d", c.CPUQuota)
+	}
+
 	return nil
 }
 
@@ -131,6 +131,10 @@ func validate(name string, conf common.Conf, renderer shell.Renderer) error {
 		}
 	}
 
+	if conf.Slice != "" && !strings.HasSuffix(conf.Slice, ".slice") {
+		return errors.NotValidf("conf.Slice %q", conf.Slice)
+	}
+
 	return nil
 }
 
@@ -208,6 +212,30 @@ func serializeService(conf common.Conf) []*unit.UnitOption {
 		})
 	}
 
+	if conf.Slice != "" {
+		unitOptions = append(unitOptions, &unit.UnitOption{
+			Section: "Service",
+			Name:    "Slice",
+			Value:   conf.Slice,
+		})
+	}
+
+	if conf.CPUQuota > 0 {
+		unitOptions = append(unitOptions, &unit.UnitOption{
+			Section: "Service",
+			Name:    "CPUQuota",
+			Value:   fmt.Sprintf("%d%%", conf.CPUQuota),
+		})
+	}
+
+	if conf.MemoryLimit > 0 {
+		unitOptions = append(unitOptions, &unit.UnitOption{
+			Section: "Service",
+			Name:    "MemoryLimit",
+			Value:   fmt.Sprintf("%dM", conf.MemoryLimit),
+		})
+	}
+
 	if conf.ExecStart != "" {
 		unitOptions = append(unitOptions, &unit.UnitOption{
 			Section: "Service",
@@ -311,6 +339,20 @@ func deserializeOptions(opts []*unit.UnitOption, renderer shell.Renderer) (commo
 						break
 					}
 				}
+			case uo.Name == "Slice":
+				conf.Slice = uo.Value
+			case uo.Name == "CPUQuota":
+				quota, err := strconv.Atoi(strings.TrimSuffix(uo.Value, "%"))
+				if err != nil {
+					return conf, errors.Trace(err)
+				}
+				conf.CPUQuota = quota
+			case uo.Name == "MemoryLimit":
+				limit, err := strconv.ParseUint(strings.TrimSuffix(uo.Value, "M"), 10, 64)
+				if err != nil {
+					return conf, errors.Trace(err)
+				}
+				conf.MemoryLimit = limit
 			case uo.Name == "TimeoutSec":
 				timeout, err := strconv.Atoi(uo.Value)
 				if err != nil {
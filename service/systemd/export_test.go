@@ -9,6 +9,7 @@ import (
 
 var (
 	Serialize       = serialize
+	Deserialize     = deserialize
 	SyslogUserGroup = syslogUserGroup
 )
 
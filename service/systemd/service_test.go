@@ -858,3 +858,41 @@ func (s *initSystemSuite) TestStartCommands(c *gc.C) {
 		"/bin/systemctl start jujud-machine-0.service",
 	})
 }
+
+func (s *initSystemSuite) TestSerializeResourceLimits(c *gc.C) {
+	s.conf.Slice = "juju-agents.slice"
+	s.conf.CPUQuota = 50
+	s.conf.MemoryLimit = 512
+
+	data, err := systemd.Serialize(s.name, s.conf, renderer)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, `
+[Unit]
+Description=juju agent for machine-0
+After=syslog.target
+After=network.target
+After=systemd-user-sessions.service
+
+[Service]
+Slice=juju-agents.slice
+CPUQuota=50%
+MemoryLimit=512M
+ExecStart=`[1:]+jujud+` machine-0
+Restart=on-failure
+
+[Install]
+WantedBy=multi-user.target
+
+`)
+
+	conf, err := systemd.Deserialize(data, renderer)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(conf, jc.DeepEquals, s.conf)
+}
+
+func (s *initSystemSuite) TestSerializeInvalidSlice(c *gc.C) {
+	s.conf.Slice = "juju-agents"
+
+	_, err := systemd.Serialize(s.name, s.conf, renderer)
+	c.Assert(err, gc.ErrorMatches, `conf.Slice "juju-agents" not valid`)
+}
@@ -155,33 +155,54 @@ func (st *State) AddOneMachine(template MachineTemplate) (*Machine, error) {
 }
 
 // AddMachines adds new machines configured according to the
-// given templates.
+// given templates. All of the machines are added in a single
+// transaction, so either every machine is added or none is. The
+// machines are given consecutive ids, in the order of the templates.
 func (st *State) AddMachines(templates ...MachineTemplate) (_ []*Machine, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add a new machine")
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	// Validate every template before reserving any ids, so that
+	// an invalid template does not use up machine ids.
+	effective := make([]MachineTemplate, len(templates))
+	for i, template := range templates {
+		effective[i], err = st.prepareMachineTemplate(template)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	first, err := st.sequenceRange("machine", len(effective))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	var ms []*Machine
-	var ops []txn.Op
+	var addOps []txn.Op
 	var mdocs []*machineDoc
-	for _, template := range templates {
-		mdoc, addOps, err := st.addMachineOps(template)
+	for i, template := range effective {
+		mdoc, ops, err := st.addMachineWithIdOps(template, strconv.Itoa(first+i))
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
 		mdocs = append(mdocs, mdoc)
 		ms = append(ms, newMachine(st, mdoc))
-		ops = append(ops, addOps...)
+		addOps = append(addOps, ops...)
 	}
-	ssOps, err := st.maintainControllersOps(mdocs, nil)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	ops = append(ops, ssOps...)
-	ops = append(ops, assertModelActiveOp(st.ModelUUID()))
-	if err := st.runTransaction(ops); err != nil {
-		if errors.Cause(err) == txn.ErrAborted {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
 			if err := checkModelActive(st); err != nil {
 				return nil, errors.Trace(err)
 			}
 		}
+		ssOps, err := st.maintainControllersOps(mdocs, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := append([]txn.Op{}, addOps...)
+		ops = append(ops, ssOps...)
+		return append(ops, assertModelActiveOp(st.ModelUUID())), nil
+	}
+	if err := st.run(withModelQuotas(st, buildTxn)); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return ms, nil
@@ -189,12 +210,13 @@ func (st *State) AddMachines(templates ...MachineTemplate) (_ []*Machine, err er
 
 func (st *State) addMachine(mdoc *machineDoc, ops []txn.Op) (*Machine, error) {
 	ops = append([]txn.Op{assertModelActiveOp(st.ModelUUID())}, ops...)
-	if err := st.runTransaction(ops); err != nil {
-		if errors.Cause(err) == txn.ErrAborted {
-			if err := checkModelActive(st); err != nil {
-				return nil, errors.Trace(err)
-			}
+	aborted := func() error {
+		if err := checkModelActive(st); err != nil {
+			return errors.Trace(err)
 		}
+		return errors.Trace(txn.ErrAborted)
+	}
+	if err := st.runWithModelQuotas(ops, aborted); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return newMachine(st, mdoc), nil
@@ -258,20 +280,37 @@ func (st *State) effectiveMachineTemplate(p MachineTemplate, allowController boo
 // based on the given template. It also returns the machine document
 // that will be inserted.
 func (st *State) addMachineOps(template MachineTemplate) (*machineDoc, []txn.Op, error) {
-	template, err := st.effectiveMachineTemplate(template, st.IsController())
+	template, err := st.prepareMachineTemplate(template)
 	if err != nil {
 		return nil, nil, err
 	}
-	if template.InstanceId == "" {
-		if err := st.precheckInstance(template.Series, template.Constraints, template.Placement); err != nil {
-			return nil, nil, err
-		}
-	}
 	seq, err := st.sequence("machine")
 	if err != nil {
 		return nil, nil, err
 	}
-	mdoc := st.machineDocForTemplate(template, strconv.Itoa(seq))
+	return st.addMachineWithIdOps(template, strconv.Itoa(seq))
+}
+
+// prepareMachineTemplate validates the given template for a new top
+// level machine, and returns the effective template to use for it.
+func (st *State) prepareMachineTemplate(template MachineTemplate) (MachineTemplate, error) {
+	template, err := st.effectiveMachineTemplate(template, st.IsController())
+	if err != nil {
+		return MachineTemplate{}, err
+	}
+	if template.InstanceId == "" {
+		if err := st.precheckInstance(template.Series, template.Constraints, template.Placement); err != nil {
+			return MachineTemplate{}, err
+		}
+	}
+	return template, nil
+}
+
+// addMachineWithIdOps returns operations to add a new top level
+// machine with the given id, based on the given effective template.
+// It also returns the machine document that will be inserted.
+func (st *State) addMachineWithIdOps(template MachineTemplate, id string) (*machineDoc, []txn.Op, error) {
+	mdoc := st.machineDocForTemplate(template, id)
 	prereqOps, machineOp, err := st.insertNewMachineOps(mdoc, template)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
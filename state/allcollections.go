@@ -396,6 +396,27 @@ func allCollections() collectionSchema {
 			}},
 		},
 
+		// This collection holds the changes made to each model's
+		// config, so that previous versions can be inspected.
+		modelConfigHistoryC: {
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "-time"},
+			}, {
+				// used for pruning
+				Key: []string{"time"},
+			}},
+		},
+
+		// This collection holds the limits on the machines, units and
+		// storage each model may use.
+		modelQuotasC: {},
+
+		// This collection holds the secrets stored in each model,
+		// with their recent revisions and the entities that may
+		// read them.
+		secretsC: {},
+
 		// This collection holds information about cloud image metadata.
 		cloudimagemetadataC: {
 			global: true,
@@ -411,6 +432,11 @@ func allCollections() collectionSchema {
 		auditingC: {
 			global:    true,
 			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"time"},
+			}, {
+				Key: []string{"model-uuid", "time"},
+			}},
 		},
 	}
 	if featureflag.Enabled(feature.CrossModelRelations) {
@@ -478,10 +504,12 @@ const (
 	migrationsC              = "migrations"
 	migrationsMinionSyncC    = "migrations.minionsync"
 	migrationsStatusC        = "migrations.status"
+	modelConfigHistoryC      = "modelconfighistory"
 	modelUserLastConnectionC = "modelUserLastConnection"
 	modelUsersC              = "modelusers"
 	modelsC                  = "models"
 	modelEntityRefsC         = "modelEntityRefs"
+	modelQuotasC             = "modelQuotas"
 	openedPortsC             = "openedPorts"
 	payloadsC                = "payloads"
 	permissionsC             = "permissions"
@@ -495,6 +523,7 @@ const (
 	endpointBindingsC        = "endpointbindings"
 	settingsC                = "settings"
 	refcountsC               = "refcounts"
+	secretsC                 = "secrets"
 	sshHostKeysC             = "sshhostkeys"
 	spacesC                  = "spaces"
 	statusesC                = "statuses"
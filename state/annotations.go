@@ -24,6 +24,10 @@ type annotatorDoc struct {
 	GlobalKey   string            `bson:"globalkey"`
 	Tag         string            `bson:"tag"`
 	Annotations map[string]string `bson:"annotations"`
+
+	// Metadata holds the typed OperatorMetadata of the entity, if
+	// any has been set.
+	Metadata *operatorMetadataDoc `bson:"metadata,omitempty"`
 }
 
 // SetAnnotations adds key/value pairs to annotations in MongoDB.
@@ -63,7 +67,7 @@ func (st *State) SetAnnotations(entity GlobalEntity, annotations map[string]stri
 			if attempt != 0 {
 				return nil, fmt.Errorf("%s no longer exists", entity.Tag())
 			}
-			return insertAnnotationsOps(st, entity, toInsert)
+			return insertAnnotationsOps(st, entity, toInsert, nil)
 		}
 		return updateAnnotations(st, entity, toUpdate, toRemove), nil
 	}
@@ -96,9 +100,13 @@ func (st *State) Annotation(entity GlobalEntity, key string) (string, error) {
 	return ann[key], nil
 }
 
-// insertAnnotationsOps returns the operations required to insert annotations in MongoDB.
-func insertAnnotationsOps(st *State, entity GlobalEntity, toInsert map[string]string) ([]txn.Op, error) {
+// insertAnnotationsOps returns the operations required to insert annotations
+// and operator metadata in MongoDB.
+func insertAnnotationsOps(st *State, entity GlobalEntity, toInsert map[string]string, metadata *operatorMetadataDoc) ([]txn.Op, error) {
 	tag := entity.Tag()
+	if toInsert == nil {
+		toInsert = make(map[string]string)
+	}
 	ops := []txn.Op{{
 		C:      annotationsC,
 		Id:     st.docID(entity.globalKey()),
@@ -107,6 +115,7 @@ func insertAnnotationsOps(st *State, entity GlobalEntity, toInsert map[string]st
 			GlobalKey:   entity.globalKey(),
 			Tag:         tag.String(),
 			Annotations: toInsert,
+			Metadata:    metadata,
 		},
 	}}
 
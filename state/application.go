@@ -14,6 +14,8 @@ import (
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/series"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6-unstable"
 	csparams "gopkg.in/juju/charmrepo.v2-unstable/csclient/params"
 	"gopkg.in/juju/names.v2"
@@ -52,6 +54,11 @@ type applicationDoc struct {
 	MinUnits             int        `bson:"minunits"`
 	TxnRevno             int64      `bson:"txn-revno"`
 	MetricCredentials    []byte     `bson:"metric-credentials"`
+
+	// Tags holds user-defined tags, applied to the instances
+	// provisioned for the application's units where the provider
+	// supports it.
+	Tags map[string]string `bson:"tags,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -292,6 +299,12 @@ func (a *Application) removeOps(asserts bson.D) ([]txn.Op, error) {
 	ops = append(ops, charmOps...)
 	ops = append(ops, finalAppCharmRemoveOps(name, curl)...)
 
+	secretOps, err := removeSecretReaderOps(a.st, a.Tag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, secretOps...)
+
 	globalKey := a.globalKey()
 	ops = append(ops,
 		removeEndpointBindingsOp(globalKey),
@@ -1206,14 +1219,15 @@ func (a *Application) AddUnit() (unit *Unit, err error) {
 		return nil, err
 	}
 
-	if err := a.st.runTransaction(ops); err == txn.ErrAborted {
+	aborted := func() error {
 		if alive, err := isAlive(a.st, applicationsC, a.doc.DocID); err != nil {
-			return nil, err
+			return err
 		} else if !alive {
-			return nil, errors.New("application is not alive")
+			return errors.New("application is not alive")
 		}
-		return nil, errors.New("inconsistent state")
-	} else if err != nil {
+		return errors.New("inconsistent state")
+	}
+	if err := a.st.runWithModelQuotas(ops, aborted); err != nil {
 		return nil, err
 	}
 	return a.st.Unit(name)
@@ -1239,6 +1253,11 @@ func (a *Application) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		return nil, errors.Trace(err)
 	}
 	ops = append(ops, resOps...)
+	secretOps, err := removeSecretReaderOps(a.st, u.Tag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, secretOps...)
 
 	observedFieldsMatch := bson.D{
 		{"charmurl", u.doc.CharmURL},
@@ -1499,13 +1518,18 @@ func (a *Application) SetConstraints(cons constraints.Value) (err error) {
 	if len(unsupported) > 0 {
 		logger.Warningf(
 			"setting constraints on application %q: unsupported constraints: %v", a.Name(), strings.Join(unsupported, ","))
-	} else if err != nil {
+	}
+	if err != nil {
 		return err
 	}
 	if a.doc.Subordinate {
 		return ErrSubordinateConstraints
 	}
 	defer errors.DeferredAnnotatef(&err, "cannot set constraints")
+	cons, err = a.st.expandConstraintProfile(cons)
+	if err != nil {
+		return err
+	}
 	if a.doc.Life != Alive {
 		return errNotAlive
 	}
@@ -1518,6 +1542,36 @@ func (a *Application) SetConstraints(cons constraints.Value) (err error) {
 	return onAbort(a.st.runTransaction(ops), errNotAlive)
 }
 
+// SetDesiredAgentVersion records the version of juju that the agents of
+// the machines hosting the application's units should run ahead of the
+// model's agent-version. Setting the zero version clears it. Units not
+// yet assigned to a machine are not affected.
+func (a *Application) SetDesiredAgentVersion(v version.Number) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set desired agent version for application %q", a.doc.Name)
+	units, err := a.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machineIds := make(set.Strings)
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		machineIds.Add(machineId)
+	}
+	var ops []txn.Op
+	for _, machineId := range machineIds.SortedValues() {
+		ops = append(ops, setDesiredAgentVersionOp(a.st.docID(machineId), v))
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	return onAbort(a.st.runTransaction(ops), ErrDead)
+}
+
 // EndpointBindings returns the mapping for each endpoint name and the space
 // name it is bound to (or empty if unspecified). When no bindings are stored
 // for the application, defaults are returned.
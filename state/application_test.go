@@ -13,6 +13,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils/set"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/mgo.v2/bson"
@@ -1812,6 +1813,32 @@ func uint64p(val uint64) *uint64 {
 	return &val
 }
 
+func (s *ApplicationSuite) TestSetDesiredAgentVersion(c *gc.C) {
+	unit0, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit0.AssignToMachine(machine), gc.IsNil)
+	// An unassigned unit is skipped.
+	_, err = s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetDesiredAgentVersion(version.MustParse("2.2.1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	v, ok := machine.DesiredAgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, version.MustParse("2.2.1"))
+	err = other.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = other.DesiredAgentVersion()
+	c.Assert(ok, jc.IsFalse)
+}
+
 func (s *ApplicationSuite) TestConstraints(c *gc.C) {
 	// Constraints are initially empty (for now).
 	cons, err := s.mysql.Constraints()
@@ -1856,6 +1883,12 @@ func (s *ApplicationSuite) TestSetInvalidConstraints(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
 }
 
+func (s *ApplicationSuite) TestSetInvalidConstraintsWithUnsupported(c *gc.C) {
+	cons := constraints.MustParse("mem=4G instance-type=foo cpu-power=10")
+	err := s.mysql.SetConstraints(cons)
+	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
+}
+
 func (s *ApplicationSuite) TestSetUnsupportedConstraintsWarning(c *gc.C) {
 	defer loggo.ResetWriters()
 	logger := loggo.GetLogger("test")
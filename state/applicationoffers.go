@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -239,18 +240,27 @@ func (s *applicationOffers) ListOffers(filter ...crossmodel.ApplicationOfferFilt
 	applicationOffersCollection, closer := s.st.getCollection(applicationOffersC)
 	defer closer()
 
-	// TODO(wallyworld) - add support for filtering on endpoints
+	// Endpoint roles and interfaces are only known once the offered
+	// application's charm has been read, so terms that filter on
+	// endpoints are matched after the query.
 	var mgoTerms []bson.D
+	var filterEndpoints, matchAll bool
 	for _, term := range filter {
+		if len(term.Endpoints) > 0 {
+			filterEndpoints = true
+		}
 		elems := s.makeFilterTerm(term)
 		if len(elems) == 0 {
+			if len(term.Endpoints) > 0 {
+				matchAll = true
+			}
 			continue
 		}
 		mgoTerms = append(mgoTerms, bson.D{{"$and", []bson.D{elems}}})
 	}
 	var docs []applicationOfferDoc
 	var mgoQuery bson.D
-	if len(mgoTerms) > 0 {
+	if len(mgoTerms) > 0 && !matchAll {
 		mgoQuery = bson.D{{"$or", mgoTerms}}
 	}
 	err := applicationOffersCollection.Find(mgoQuery).All(&docs)
@@ -258,17 +268,66 @@ func (s *applicationOffers) ListOffers(filter ...crossmodel.ApplicationOfferFilt
 		return nil, errors.Annotate(err, "cannot find application offers")
 	}
 	sort.Sort(srSlice(docs))
-	offers := make([]crossmodel.ApplicationOffer, len(docs))
-	for i, doc := range docs {
+	offers := make([]crossmodel.ApplicationOffer, 0, len(docs))
+	for _, doc := range docs {
 		offer, err := s.makeApplicationOffer(doc)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		offers[i] = *offer
+		if filterEndpoints && !offerMatchesAnyTerm(*offer, filter) {
+			continue
+		}
+		offers = append(offers, *offer)
 	}
 	return offers, nil
 }
 
+// offerMatchesAnyTerm reports whether the offer matches all the
+// criteria of any one of the filter terms, mirroring the query built
+// by makeFilterTerm.
+func offerMatchesAnyTerm(offer crossmodel.ApplicationOffer, filter []crossmodel.ApplicationOfferFilter) bool {
+	for _, term := range filter {
+		if term.ApplicationName != "" && term.ApplicationName != offer.ApplicationName {
+			continue
+		}
+		if !strings.Contains(offer.OfferName, term.OfferName) {
+			continue
+		}
+		if !strings.Contains(offer.ApplicationDescription, term.ApplicationDescription) {
+			continue
+		}
+		if offerHasEndpoints(offer, term.Endpoints) {
+			return true
+		}
+	}
+	return false
+}
+
+// offerHasEndpoints reports whether, for each of the endpoint filter
+// terms, the offer has an endpoint matching all of its criteria.
+func offerHasEndpoints(offer crossmodel.ApplicationOffer, terms []crossmodel.EndpointFilterTerm) bool {
+	for _, term := range terms {
+		var found bool
+		for _, rel := range offer.Endpoints {
+			if term.Name != "" && term.Name != rel.Name {
+				continue
+			}
+			if term.Interface != "" && term.Interface != rel.Interface {
+				continue
+			}
+			if term.Role != "" && term.Role != rel.Role {
+				continue
+			}
+			found = true
+			break
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *applicationOffers) makeApplicationOffer(doc applicationOfferDoc) (*crossmodel.ApplicationOffer, error) {
 	offer := &crossmodel.ApplicationOffer{
 		OfferName:              doc.OfferName,
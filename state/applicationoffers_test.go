@@ -175,6 +175,52 @@ func (s *applicationOffersSuite) TestListOffersFilterOfferNameRegexp(c *gc.C) {
 	c.Assert(offers[0], jc.DeepEquals, offer)
 }
 
+func (s *applicationOffersSuite) TestListOffersFilterEndpoints(c *gc.C) {
+	sd := state.NewApplicationOffers(s.State)
+	offer := s.createDefaultOffer(c)
+
+	offers, err := sd.ListOffers(crossmodel.ApplicationOfferFilter{
+		Endpoints: []crossmodel.EndpointFilterTerm{{
+			Interface: "mysql-root",
+			Role:      charm.RoleProvider,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, jc.DeepEquals, []crossmodel.ApplicationOffer{offer})
+
+	offers, err = sd.ListOffers(crossmodel.ApplicationOfferFilter{
+		Endpoints: []crossmodel.EndpointFilterTerm{{
+			Name: "server",
+		}, {
+			Role: charm.RoleRequirer,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, gc.HasLen, 0)
+}
+
+func (s *applicationOffersSuite) TestListOffersFilterEndpointsAndNames(c *gc.C) {
+	sd := state.NewApplicationOffers(s.State)
+	s.createOffer(c, "offer1", "description for offer1")
+	offer2 := s.createOffer(c, "offer2", "description for offer2")
+	offers, err := sd.ListOffers(
+		crossmodel.ApplicationOfferFilter{
+			OfferName: "offer1",
+			Endpoints: []crossmodel.EndpointFilterTerm{{
+				Role: charm.RoleRequirer,
+			}},
+		},
+		crossmodel.ApplicationOfferFilter{
+			OfferName: "offer2",
+			Endpoints: []crossmodel.EndpointFilterTerm{{
+				Interface: "mysql",
+			}},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, jc.DeepEquals, []crossmodel.ApplicationOffer{offer2})
+}
+
 func (s *applicationOffersSuite) TestAddApplicationOfferDuplicate(c *gc.C) {
 	sd := state.NewApplicationOffers(s.State)
 	_, err := sd.AddOffer(crossmodel.AddApplicationOfferArgs{
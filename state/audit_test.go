@@ -0,0 +1,74 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type AuditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+func (s *AuditSuite) putEntries(c *gc.C, base time.Time, origins ...string) []audit.AuditEntry {
+	put := s.State.PutAuditEntryFn()
+	entries := make([]audit.AuditEntry, len(origins))
+	for i, origin := range origins {
+		entries[i] = audit.AuditEntry{
+			JujuServerVersion: jujuversion.Current,
+			ModelUUID:         s.State.ModelUUID(),
+			Timestamp:         base.Add(time.Duration(i) * time.Hour),
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        origin,
+			Operation:         "Application:v1 - Deploy",
+			Data:              map[string]interface{}{"error-code": ""},
+		}
+		err := put(entries[i])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return entries
+}
+
+func (s *AuditSuite) TestAuditEntries(c *gc.C) {
+	base := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := s.putEntries(c, base, "user-bob", "user-mary", "user-bob")
+
+	all, err := s.State.AuditEntries(audit.Filter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, []audit.AuditEntry{entries[2], entries[1], entries[0]})
+
+	bob, err := s.State.AuditEntries(audit.Filter{OriginName: "user-bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bob, jc.DeepEquals, []audit.AuditEntry{entries[2], entries[0]})
+
+	recent, err := s.State.AuditEntries(audit.Filter{After: base})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recent, jc.DeepEquals, []audit.AuditEntry{entries[2], entries[1]})
+
+	limited, err := s.State.AuditEntries(audit.Filter{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limited, jc.DeepEquals, []audit.AuditEntry{entries[2]})
+}
+
+func (s *AuditSuite) TestPruneAuditLog(c *gc.C) {
+	base := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := s.putEntries(c, base, "user-bob", "user-mary", "user-bob")
+
+	removed, err := s.State.PruneAuditLog(base.Add(90 * time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.Equals, 2)
+
+	remaining, err := s.State.AuditEntries(audit.Filter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remaining, jc.DeepEquals, []audit.AuditEntry{entries[2]})
+}
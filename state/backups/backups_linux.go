@@ -8,6 +8,7 @@ package backups
 import (
 	"net"
 	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/shell"
@@ -202,6 +203,20 @@ func (b *backups) Restore(backupId string, dbInfo *DBInfo, args RestoreArgs) (na
 		GetDB:           GetDB,
 	}
 
+	// Backups made by older controllers may not include the tools
+	// and image catalogs, in which case the restored controller must
+	// re-sync them before it can provision machines.
+	missing, err := missingCatalog(workspace.DBDumpDir)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot inspect database dump")
+	}
+	if len(missing) > 0 {
+		logger.Warningf(
+			"backup does not include %s; tools and images will be fetched from streams when needed",
+			strings.Join(missing, ", "),
+		)
+	}
+
 	// Restore mongodb from backup
 	restorer, err := NewDBRestorer(rArgs)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
@@ -56,6 +57,22 @@ var ignoredDatabases = set.NewStrings(
 	imagestorage.ImagesDB, // note: this is still backed up anyway
 )
 
+// catalogCollections holds, by database, the collections that make up
+// the controller's tools and image catalogs: the managed blob storage
+// holding uploaded and cached tools, the tools metadata, and the cloud
+// image metadata. They are always included in backups so that a
+// restored controller can provision machines without first re-syncing
+// tools and image streams.
+var catalogCollections = map[string][]string{
+	"blobstore": {"blobstore.files", "blobstore.chunks"},
+	"juju": {
+		"toolsmetadata",
+		"storedResources",
+		"managedStoredResources",
+		"cloudimagemetadata",
+	},
+}
+
 type DBSession interface {
 	DatabaseNames() ([]string, error)
 }
@@ -192,8 +209,72 @@ func (md *mongoDumper) Dump(baseDumpDir string) error {
 	if md.DBInfo.MongoVersion.NewerThan(mongo.Mongo26) == -1 {
 		ignored.Remove("admin")
 	}
-	err = stripIgnored(ignored, baseDumpDir)
-	return errors.Trace(err)
+	if err := stripIgnored(ignored, baseDumpDir); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(checkCatalogDumped(md.Targets, baseDumpDir))
+}
+
+// checkCatalogDumped returns an error if any of the targeted databases
+// holding the tools and image catalogs is missing from the dump.
+func checkCatalogDumped(targets set.Strings, dumpDir string) error {
+	for dbName := range catalogCollections {
+		if !targets.Contains(dbName) {
+			continue
+		}
+		_, err := os.Stat(filepath.Join(dumpDir, dbName))
+		if os.IsNotExist(err) {
+			return errors.Errorf("%q database missing from dump", dbName)
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	dumped, err := dumpedCatalog(dumpDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("dumped catalog collections: %v", dumped.SortedValues())
+	return nil
+}
+
+// dumpedCatalog returns the tools and image catalog collections, named
+// as "database.collection", found in the mongo dump files.
+func dumpedCatalog(dumpDir string) (set.Strings, error) {
+	dumped := make(set.Strings)
+	for dbName, collections := range catalogCollections {
+		for _, collection := range collections {
+			_, err := os.Stat(filepath.Join(dumpDir, dbName, collection+".bson"))
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			dumped.Add(dbName + "." + collection)
+		}
+	}
+	return dumped, nil
+}
+
+// missingCatalog returns the tools and image catalog collections, named
+// as "database.collection", that are not found in the mongo dump files.
+// Mongo creates collections lazily, so a collection absent from a
+// database that was dumped is empty rather than missing; only the
+// collections of databases left out of the dump are reported.
+func missingCatalog(dumpDir string) ([]string, error) {
+	var missing []string
+	for dbName, collections := range catalogCollections {
+		_, err := os.Stat(filepath.Join(dumpDir, dbName))
+		if err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return nil, errors.Trace(err)
+		}
+		for _, collection := range collections {
+			missing = append(missing, dbName+"."+collection)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
 }
 
 // stripIgnored removes the ignored DBs from the mongo dump files.
@@ -4,6 +4,7 @@
 package backups_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -155,3 +156,52 @@ func (s *dumpSuite) TestDumpNothingIgnored(c *gc.C) {
 
 	s.checkDBs(c, "juju", "admin")
 }
+
+func (s *dumpSuite) TestDumpCatalogDatabaseMissing(c *gc.C) {
+	s.dbInfo.Targets = set.NewStrings("juju", "admin", "blobstore")
+	s.patch(c)
+	dumper := s.prep(c, "juju", "admin")
+
+	err := dumper.Dump(s.dumpDir)
+	c.Check(err, gc.ErrorMatches, `"blobstore" database missing from dump`)
+}
+
+func (s *dumpSuite) TestMissingCatalog(c *gc.C) {
+	s.dbInfo.Targets = set.NewStrings("juju", "admin", "blobstore")
+	s.patch(c)
+	dumper := s.prep(c, "juju", "admin", "blobstore")
+	for _, name := range []string{
+		"blobstore/blobstore.files.bson",
+		"blobstore/blobstore.chunks.bson",
+		"juju/toolsmetadata.bson",
+		"juju/storedResources.bson",
+		"juju/managedStoredResources.bson",
+	} {
+		err := ioutil.WriteFile(filepath.Join(s.dumpDir, name), nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err := dumper.Dump(s.dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// cloudimagemetadata was never created, so is empty rather
+	// than missing.
+	missing, err := backups.MissingCatalog(s.dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(missing, gc.HasLen, 0)
+}
+
+func (s *dumpSuite) TestMissingCatalogDatabaseNotDumped(c *gc.C) {
+	s.patch(c)
+	dumper := s.prep(c, "juju", "admin")
+
+	err := dumper.Dump(s.dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	missing, err := backups.MissingCatalog(s.dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(missing, jc.DeepEquals, []string{
+		"blobstore.blobstore.chunks",
+		"blobstore.blobstore.files",
+	})
+}
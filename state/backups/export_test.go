@@ -17,8 +17,9 @@ import (
 )
 
 var (
-	Create        = create
-	FileTimestamp = fileTimestamp
+	Create         = create
+	FileTimestamp  = fileTimestamp
+	MissingCatalog = missingCatalog
 
 	TestGetFilesToBackUp  = &getFilesToBackUp
 	GetDBDumper           = &getDBDumper
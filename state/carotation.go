@@ -0,0 +1,276 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/controller"
+)
+
+// caRotationKey is the id of the document in the controllers
+// collection that records a CA rotation in progress.
+const caRotationKey = "caRotation"
+
+// CARotationPhase describes how far a CA rotation has progressed.
+type CARotationPhase string
+
+const (
+	// CARotationStaged means that the new CA has been staged.
+	// Agents trust both the previous and the new CA, but the
+	// controllers still serve certificates signed by the previous
+	// CA.
+	CARotationStaged CARotationPhase = "staged"
+
+	// CARotationCutOver means that the controllers serve
+	// certificates signed by the new CA. Agents still trust the
+	// previous CA until the rotation is completed.
+	CARotationCutOver CARotationPhase = "cut-over"
+)
+
+// CARotation describes a CA rotation in progress.
+type CARotation struct {
+	// Phase is the phase the rotation has reached.
+	Phase CARotationPhase
+
+	// CACert is the new CA certificate, in PEM format.
+	CACert string
+
+	// PreviousCACert is the CA certificate being replaced, in PEM
+	// format.
+	PreviousCACert string
+}
+
+// caRotationDoc records a CA rotation in progress.
+type caRotationDoc struct {
+	Id             string `bson:"_id"`
+	Phase          string `bson:"phase"`
+	CACert         string `bson:"ca-cert"`
+	CAPrivateKey   string `bson:"ca-private-key"`
+	PreviousCACert string `bson:"previous-ca-cert"`
+}
+
+func (doc *caRotationDoc) rotation() CARotation {
+	return CARotation{
+		Phase:          CARotationPhase(doc.Phase),
+		CACert:         doc.CACert,
+		PreviousCACert: doc.PreviousCACert,
+	}
+}
+
+func (st *State) caRotationDoc() (*caRotationDoc, error) {
+	controllers, closer := st.getCollection(controllersC)
+	defer closer()
+	var doc caRotationDoc
+	if err := controllers.FindId(caRotationKey).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("CA rotation")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read CA rotation")
+	}
+	return &doc, nil
+}
+
+// CARotation returns the CA rotation in progress, or an error
+// satisfying errors.IsNotFound if there is none.
+func (st *State) CARotation() (CARotation, error) {
+	doc, err := st.caRotationDoc()
+	if err != nil {
+		return CARotation{}, errors.Trace(err)
+	}
+	return doc.rotation(), nil
+}
+
+// StageCARotation starts replacing the controller's CA with the given
+// CA certificate and private key. The controller's ca-cert becomes a
+// bundle of the previous and the new CA certificates, which agents
+// pick up so that they trust certificates signed by either CA.
+func (st *State) StageCARotation(caCert, caPrivateKey string) error {
+	if err := validateCA(caCert, caPrivateKey); err != nil {
+		return errors.Annotate(err, "cannot stage CA rotation")
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.caRotationDoc(); err == nil {
+			return nil, errors.AlreadyExistsf("CA rotation")
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		settings, err := readSettings(st, controllersC, controllerSettingsGlobalKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		previous, _ := settings.Get(controller.CACertKey)
+		previousCACert, _ := previous.(string)
+		if previousCACert == "" {
+			return nil, errors.New("controller has no CA certificate")
+		}
+		settings.Set(controller.CACertKey, joinCACerts(previousCACert, caCert))
+		_, settingsOps := settings.settingsUpdateOps()
+		ops := []txn.Op{{
+			C:      controllersC,
+			Id:     caRotationKey,
+			Assert: txn.DocMissing,
+			Insert: &caRotationDoc{
+				Id:             caRotationKey,
+				Phase:          string(CARotationStaged),
+				CACert:         caCert,
+				CAPrivateKey:   caPrivateKey,
+				PreviousCACert: previousCACert,
+			},
+		}, settings.assertUnchangedOp()}
+		return append(ops, settingsOps...), nil
+	}
+	return errors.Annotate(st.run(buildTxn), "cannot stage CA rotation")
+}
+
+// CutOverCARotation replaces the controller's certificate with one
+// signed by the staged CA, for the same host names and addresses. The
+// controllers pick up the new certificate as they do any rotated
+// certificate. The new CA certificate moves to the front of the
+// controller's ca-cert bundle, to match the CA private key used to
+// sign any further controller certificates. Agents must have picked up
+// the staged CA before the rotation is cut over, or they will not
+// trust the controllers.
+func (st *State) CutOverCARotation() error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		doc, err := st.caRotationDoc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if doc.Phase != string(CARotationStaged) {
+			return nil, errors.Errorf("CA rotation is %s, not %s", doc.Phase, CARotationStaged)
+		}
+		info, err := st.StateServingInfo()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		hostnames, err := certHostnames(info.Cert)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read controller certificate")
+		}
+		newCert, newKey, err := cert.NewDefaultServer(doc.CACert, doc.CAPrivateKey, hostnames)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot generate controller certificate")
+		}
+		settings, err := readSettings(st, controllersC, controllerSettingsGlobalKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		settings.Set(controller.CACertKey, joinCACerts(doc.CACert, doc.PreviousCACert))
+		_, settingsOps := settings.settingsUpdateOps()
+		ops := []txn.Op{{
+			C:      controllersC,
+			Id:     caRotationKey,
+			Assert: bson.D{{"phase", CARotationStaged}},
+			Update: bson.D{{"$set", bson.D{{"phase", CARotationCutOver}}}},
+		}, {
+			C:      controllersC,
+			Id:     stateServingInfoKey,
+			Assert: bson.D{{"cert", info.Cert}},
+			Update: bson.D{{"$set", bson.D{
+				{"cert", newCert},
+				{"privatekey", newKey},
+				{"certchain", ""},
+				{"caprivatekey", doc.CAPrivateKey},
+			}}},
+		}, settings.assertUnchangedOp()}
+		return append(ops, settingsOps...), nil
+	}
+	return errors.Annotate(st.run(buildTxn), "cannot cut over CA rotation")
+}
+
+// CompleteCARotation finishes a CA rotation that has been cut over, so
+// that the controller's ca-cert holds only the new CA certificate and
+// agents stop trusting the previous CA. The controllers' mongo servers
+// must have been restarted with the new certificate first.
+func (st *State) CompleteCARotation() error {
+	return errors.Annotate(st.endCARotation(CARotationCutOver, func(doc *caRotationDoc) string {
+		return doc.CACert
+	}), "cannot complete CA rotation")
+}
+
+// AbortCARotation abandons a CA rotation that has been staged but not
+// cut over, restoring the controller's previous ca-cert.
+func (st *State) AbortCARotation() error {
+	return errors.Annotate(st.endCARotation(CARotationStaged, func(doc *caRotationDoc) string {
+		return doc.PreviousCACert
+	}), "cannot abort CA rotation")
+}
+
+// endCARotation removes the CA rotation, which must be in the given
+// phase, and sets the controller's ca-cert to the value returned by
+// caCert.
+func (st *State) endCARotation(phase CARotationPhase, caCert func(*caRotationDoc) string) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		doc, err := st.caRotationDoc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if doc.Phase != string(phase) {
+			return nil, errors.Errorf("CA rotation is %s, not %s", doc.Phase, phase)
+		}
+		settings, err := readSettings(st, controllersC, controllerSettingsGlobalKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		settings.Set(controller.CACertKey, caCert(doc))
+		_, settingsOps := settings.settingsUpdateOps()
+		ops := []txn.Op{{
+			C:      controllersC,
+			Id:     caRotationKey,
+			Assert: bson.D{{"phase", phase}},
+			Remove: true,
+		}, settings.assertUnchangedOp()}
+		return append(ops, settingsOps...), nil
+	}
+	return st.run(buildTxn)
+}
+
+// validateCA returns an error unless caCert holds a CA certificate
+// matching caPrivateKey.
+func validateCA(caCert, caPrivateKey string) error {
+	certs, err := cert.ParseCertificates(caCert)
+	if err != nil {
+		return errors.Annotate(err, "invalid CA certificate")
+	}
+	if len(certs) != 1 {
+		return errors.NotValidf("CA certificate bundle")
+	}
+	if !certs[0].IsCA {
+		return errors.NotValidf("non-CA certificate")
+	}
+	if _, err := tls.X509KeyPair([]byte(caCert), []byte(caPrivateKey)); err != nil {
+		return errors.Annotate(err, "CA certificate and private key do not match")
+	}
+	return nil
+}
+
+// joinCACerts returns a bundle of the given PEM encoded certificates.
+func joinCACerts(caCerts ...string) string {
+	trimmed := make([]string, len(caCerts))
+	for i, c := range caCerts {
+		trimmed[i] = strings.TrimRight(c, "\n")
+	}
+	return strings.Join(trimmed, "\n") + "\n"
+}
+
+// certHostnames returns the host names and addresses for which the
+// given certificate is valid.
+func certHostnames(certPEM string) ([]string, error) {
+	certs, err := cert.ParseCertificates(certPEM)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hostnames := append([]string(nil), certs[0].DNSNames...)
+	for _, ip := range certs[0].IPAddresses {
+		hostnames = append(hostnames, ip.String())
+	}
+	return hostnames, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+)
+
+type CARotationSuite struct {
+	ConnSuite
+	serverCert string
+	serverKey  string
+}
+
+var _ = gc.Suite(&CARotationSuite{})
+
+func (s *CARotationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.serverCert, s.serverKey, err = cert.NewDefaultServer(
+		testing.CACert, testing.CAKey, []string{"localhost", "10.0.0.1"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetStateServingInfo(state.StateServingInfo{
+		APIPort:      69,
+		StatePort:    80,
+		Cert:         s.serverCert,
+		PrivateKey:   s.serverKey,
+		CAPrivateKey: testing.CAKey,
+		SharedSecret: "Some Keyfile",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CARotationSuite) assertControllerCACert(c *gc.C, expect string) {
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	c.Assert(caCert, gc.Equals, expect)
+}
+
+func (s *CARotationSuite) TestNoRotation(c *gc.C) {
+	_, err := s.State.CARotation()
+	c.Assert(err, gc.ErrorMatches, "CA rotation not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CARotationSuite) TestStageCARotation(c *gc.C) {
+	err := s.State.StageCARotation(testing.OtherCACert, testing.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rotation, err := s.State.CARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation, jc.DeepEquals, state.CARotation{
+		Phase:          state.CARotationStaged,
+		CACert:         testing.OtherCACert,
+		PreviousCACert: testing.CACert,
+	})
+
+	// Agents trust both CAs while the rotation is staged.
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	certs, err := cert.ParseCertificates(caCert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs, gc.HasLen, 2)
+	c.Assert(cert.Verify(s.serverCert, caCert, time.Now()), jc.ErrorIsNil)
+
+	// The controller certificate is unchanged.
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Cert, gc.Equals, s.serverCert)
+}
+
+func (s *CARotationSuite) TestStageCARotationTwice(c *gc.C) {
+	err := s.State.StageCARotation(testing.OtherCACert, testing.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.StageCARotation(testing.OtherCACert, testing.OtherCAKey)
+	c.Assert(err, gc.ErrorMatches, "cannot stage CA rotation: CA rotation already exists")
+}
+
+func (s *CARotationSuite) TestStageCARotationInvalid(c *gc.C) {
+	err := s.State.StageCARotation(s.serverCert, s.serverKey)
+	c.Assert(err, gc.ErrorMatches, "cannot stage CA rotation: non-CA certificate not valid")
+
+	err = s.State.StageCARotation(testing.OtherCACert, testing.CAKey)
+	c.Assert(err, gc.ErrorMatches, "cannot stage CA rotation: CA certificate and private key do not match: .*")
+
+	_, err = s.State.CARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CARotationSuite) TestCutOverCARotation(c *gc.C) {
+	err := s.State.StageCARotation(testing.OtherCACert, testing.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CutOverCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+
+	rotation, err := s.State.CARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase, gc.Equals, state.CARotationCutOver)
+
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.CAPrivateKey, gc.Equals, testing.OtherCAKey)
+	c.Assert(cert.Verify(info.Cert, testing.OtherCACert, time.Now()), jc.ErrorIsNil)
+	certs, err := cert.ParseCertificates(info.Cert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs[0].DNSNames, jc.SameContents, []string{"localhost"})
+	c.Assert(certs[0].IPAddresses, gc.HasLen, 1)
+	c.Assert(certs[0].IPAddresses[0].String(), gc.Equals, "10.0.0.1")
+
+	// The new CA leads the bundle, so that it matches the CA private
+	// key when further controller certificates are signed.
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	caCerts, err := cert.ParseCertificates(caCert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caCerts, gc.HasLen, 2)
+	newCACerts, err := cert.ParseCertificates(testing.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caCerts[0].Equal(newCACerts[0]), jc.IsTrue)
+	c.Assert(cert.Verify(s.serverCert, caCert, time.Now()), jc.ErrorIsNil)
+	_, _, err = cert.NewDefaultServer(caCert, info.CAPrivateKey, []string{"localhost"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AbortCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot abort CA rotation: CA rotation is cut-over, not staged")
+}
+
+func (s *CARotationSuite) TestCutOverCARotationNotStaged(c *gc.C) {
+	err := s.State.CutOverCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot cut over CA rotation: CA rotation not found")
+}
+
+func (s *CARotationSuite) TestCompleteCARotation(c *gc.C) {
+	err := s.State.StageCARotation(testing.OtherCACert, testing.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CompleteCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot complete CA rotation: CA rotation is staged, not cut-over")
+
+	err = s.State.CutOverCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CompleteCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertControllerCACert(c, testing.OtherCACert)
+	_, err = s.State.CARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CARotationSuite) TestAbortCARotation(c *gc.C) {
+	err := s.State.StageCARotation(testing.OtherCACert, testing.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AbortCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertControllerCACert(c, testing.CACert)
+	_, err = s.State.CARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CARotationSuite) TestWatchControllerConfig(c *gc.C) {
+	w := s.State.WatchControllerConfig()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.State.StageCARotation(testing.OtherCACert, testing.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
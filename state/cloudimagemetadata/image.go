@@ -189,6 +189,9 @@ type imagesMetadataDoc struct {
 	// for e.g. "daily" or "released"
 	Stream string `bson:"stream"`
 
+	// Cloud is the name of the cloud associated with the image.
+	Cloud string `bson:"cloud,omitempty"`
+
 	// Region is the name of cloud region associated with the image.
 	Region string `bson:"region"`
 
@@ -227,6 +230,7 @@ func (m imagesMetadataDoc) metadata() Metadata {
 		MetadataAttributes{
 			Source:          m.Source,
 			Stream:          m.Stream,
+			Cloud:           m.Cloud,
 			Region:          m.Region,
 			Version:         m.Version,
 			Series:          m.Series,
@@ -253,6 +257,7 @@ func (s *storage) mongoDoc(m Metadata) imagesMetadataDoc {
 	r := imagesMetadataDoc{
 		Id:              buildKey(m),
 		Stream:          m.Stream,
+		Cloud:           m.Cloud,
 		Region:          m.Region,
 		Version:         m.Version,
 		Series:          m.Series,
@@ -271,7 +276,7 @@ func (s *storage) mongoDoc(m Metadata) imagesMetadataDoc {
 }
 
 func buildKey(m Metadata) string {
-	return fmt.Sprintf("%s:%s:%s:%s:%s:%s:%s",
+	key := fmt.Sprintf("%s:%s:%s:%s:%s:%s:%s",
 		m.Stream,
 		m.Region,
 		m.Series,
@@ -279,6 +284,11 @@ func buildKey(m Metadata) string {
 		m.VirtType,
 		m.RootStorageType,
 		m.Source)
+	if m.Cloud != "" {
+		// Keys of metadata saved without a cloud are unchanged.
+		key += ":" + m.Cloud
+	}
+	return key
 }
 
 func validateMetadata(m *imagesMetadataDoc) error {
@@ -338,6 +348,11 @@ func buildSearchClauses(criteria MetadataFilter) bson.D {
 		all = append(all, bson.DocElem{"stream", criteria.Stream})
 	}
 
+	if criteria.Cloud != "" {
+		// Metadata saved without a cloud matches any.
+		all = append(all, bson.DocElem{"cloud", bson.D{{"$in", []interface{}{criteria.Cloud, nil}}}})
+	}
+
 	if criteria.Region != "" {
 		all = append(all, bson.DocElem{"region", criteria.Region})
 	}
@@ -368,6 +383,9 @@ func buildSearchClauses(criteria MetadataFilter) bson.D {
 // cloud image metadata. Since size and source are not discriminating attributes
 // for cloud image metadata, they are not included in search criteria.
 type MetadataFilter struct {
+	// Cloud stores metadata cloud.
+	Cloud string `json:"cloud,omitempty"`
+
 	// Region stores metadata region.
 	Region string `json:"region,omitempty"`
 
@@ -438,6 +438,34 @@ func (s *cloudImageMetadataSuite) TestSupportedArchitectures(c *gc.C) {
 	c.Assert(uniqueArches, gc.DeepEquals, expected)
 }
 
+func (s *cloudImageMetadataSuite) TestSupportedArchitecturesUnmatchedClouds(c *gc.C) {
+	attrs := cloudimagemetadata.MetadataAttributes{
+		Stream:          "stream",
+		Cloud:           "cloud-test",
+		Region:          "region-test",
+		Version:         "14.04",
+		Series:          "trusty",
+		Arch:            "arch",
+		VirtType:        "virtType-test",
+		Source:          "test",
+		RootStorageType: "rootStorageType-test"}
+	s.assertRecordMetadata(c, cloudimagemetadata.Metadata{attrs, 0, "1", 0})
+
+	// Metadata saved without a cloud matches any cloud.
+	attrs.Cloud = ""
+	attrs.Arch = "anotherArch"
+	s.assertRecordMetadata(c, cloudimagemetadata.Metadata{attrs, 0, "2", 0})
+
+	attrs.Cloud = "other-cloud"
+	attrs.Arch = "otherArch"
+	s.assertRecordMetadata(c, cloudimagemetadata.Metadata{attrs, 0, "3", 0})
+
+	uniqueArches, err := s.storage.SupportedArchitectures(
+		cloudimagemetadata.MetadataFilter{Stream: "stream", Cloud: "cloud-test", Region: "region-test"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uniqueArches, jc.SameContents, []string{"arch", "anotherArch"})
+}
+
 func (s *cloudImageMetadataSuite) TestSupportedArchitecturesUnmatchedStreams(c *gc.C) {
 	stream := "stream"
 	region := "region-test"
@@ -15,6 +15,10 @@ type MetadataAttributes struct {
 	// for e.g. "daily" or "released"
 	Stream string
 
+	// Cloud is the name of the cloud associated with the image.
+	// Metadata saved before clouds were recorded has none.
+	Cloud string
+
 	// Region is the name of cloud region associated with the image.
 	Region string
 
@@ -114,3 +114,39 @@ func (s *ConfigValidatorSuite) TestConfigValidateNoPolicy(c *gc.C) {
 	err := s.updateModelConfig(c)
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *ConfigValidatorSuite) TestValidateModelConfigUpdate(c *gc.C) {
+	oldCfg, newCfg, err := s.State.ValidateModelConfigUpdate(map[string]interface{}{
+		"authorized-keys": "different-keys",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newCfg, gc.Equals, s.configValidator.validateValid)
+	c.Assert(s.configValidator.validateOld, gc.Equals, oldCfg)
+	c.Assert(s.configValidator.validateCfg.AuthorizedKeys(), gc.Equals, "different-keys")
+
+	// The model config is left alone.
+	stateCfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateCfg.AllAttrs(), jc.DeepEquals, oldCfg.AllAttrs())
+}
+
+type failingConfigValidator struct {
+	err error
+}
+
+func (v failingConfigValidator) Validate(cfg, old *config.Config) (*config.Config, error) {
+	return nil, v.err
+}
+
+func (s *ConfigValidatorSuite) TestValidateModelConfigUpdateInvalid(c *gc.C) {
+	s.policy.GetConfigValidator = func() (config.Validator, error) {
+		return failingConfigValidator{errors.New("invalid config")}, nil
+	}
+	oldCfg, newCfg, err := s.State.ValidateModelConfigUpdate(map[string]interface{}{
+		"authorized-keys": "different-keys",
+	}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, "invalid config")
+	c.Assert(oldCfg, gc.NotNil)
+	// The unvalidated config is returned so the change can be reported.
+	c.Assert(newCfg.AuthorizedKeys(), gc.Equals, "different-keys")
+}
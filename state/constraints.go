@@ -17,47 +17,77 @@ import (
 
 // constraintsDoc is the mongodb representation of a constraints.Value.
 type constraintsDoc struct {
-	ModelUUID    string `bson:"model-uuid"`
-	Arch         *string
-	CpuCores     *uint64
-	CpuPower     *uint64
-	Mem          *uint64
-	RootDisk     *uint64
-	InstanceType *string
-	Container    *instance.ContainerType
-	Tags         *[]string
-	Spaces       *[]string
-	VirtType     *string
+	ModelUUID        string `bson:"model-uuid"`
+	AllocatePublicIP *bool
+	Arch             *string
+	CpuCores         *uint64
+	MaxCpuCores      *uint64
+	CpuPower         *uint64
+	GPUs             *uint64
+	GPUType          *string
+	ImageID          *string
+	Mem              *uint64
+	MaxMem           *uint64
+	Profile          *string
+	RootDisk         *uint64
+	RootDiskSource   *string
+	InstanceType     *string
+	Container        *instance.ContainerType
+	Tags             *[]string
+	Spaces           *[]string
+	SpotPrice        *float64
+	Spread           *string
+	VirtType         *string
 }
 
 func (doc constraintsDoc) value() constraints.Value {
 	result := constraints.Value{
-		Arch:         doc.Arch,
-		CpuCores:     doc.CpuCores,
-		CpuPower:     doc.CpuPower,
-		Mem:          doc.Mem,
-		RootDisk:     doc.RootDisk,
-		InstanceType: doc.InstanceType,
-		Container:    doc.Container,
-		Tags:         doc.Tags,
-		Spaces:       doc.Spaces,
-		VirtType:     doc.VirtType,
+		AllocatePublicIP: doc.AllocatePublicIP,
+		Arch:             doc.Arch,
+		CpuCores:         doc.CpuCores,
+		MaxCpuCores:      doc.MaxCpuCores,
+		CpuPower:         doc.CpuPower,
+		GPUs:             doc.GPUs,
+		GPUType:          doc.GPUType,
+		ImageID:          doc.ImageID,
+		Mem:              doc.Mem,
+		MaxMem:           doc.MaxMem,
+		Profile:          doc.Profile,
+		RootDisk:         doc.RootDisk,
+		RootDiskSource:   doc.RootDiskSource,
+		InstanceType:     doc.InstanceType,
+		Container:        doc.Container,
+		Tags:             doc.Tags,
+		Spaces:           doc.Spaces,
+		SpotPrice:        doc.SpotPrice,
+		Spread:           doc.Spread,
+		VirtType:         doc.VirtType,
 	}
 	return result
 }
 
 func newConstraintsDoc(st *State, cons constraints.Value) constraintsDoc {
 	result := constraintsDoc{
-		Arch:         cons.Arch,
-		CpuCores:     cons.CpuCores,
-		CpuPower:     cons.CpuPower,
-		Mem:          cons.Mem,
-		RootDisk:     cons.RootDisk,
-		InstanceType: cons.InstanceType,
-		Container:    cons.Container,
-		Tags:         cons.Tags,
-		Spaces:       cons.Spaces,
-		VirtType:     cons.VirtType,
+		AllocatePublicIP: cons.AllocatePublicIP,
+		Arch:             cons.Arch,
+		CpuCores:         cons.CpuCores,
+		MaxCpuCores:      cons.MaxCpuCores,
+		CpuPower:         cons.CpuPower,
+		GPUs:             cons.GPUs,
+		GPUType:          cons.GPUType,
+		ImageID:          cons.ImageID,
+		Mem:              cons.Mem,
+		MaxMem:           cons.MaxMem,
+		Profile:          cons.Profile,
+		RootDisk:         cons.RootDisk,
+		RootDiskSource:   cons.RootDiskSource,
+		InstanceType:     cons.InstanceType,
+		Container:        cons.Container,
+		Tags:             cons.Tags,
+		Spaces:           cons.Spaces,
+		SpotPrice:        cons.SpotPrice,
+		Spread:           cons.Spread,
+		VirtType:         cons.VirtType,
 	}
 	return result
 }
@@ -267,6 +267,72 @@ func (s *constraintsValidationSuite) TestServiceConstraints(c *gc.C) {
 	}
 }
 
+func (s *constraintsValidationSuite) TestMachineConstraintsWithProfile(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"constraint-profiles": map[string]interface{}{
+			"small": "mem=2G cores=1",
+			"big":   "instance-type=foo-42 cores=8",
+		},
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelConstraints(constraints.MustParse("profile=small"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Model constraints are stored with the profile expanded.
+	econs, err := s.State.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(econs, jc.DeepEquals, constraints.MustParse("mem=2G cores=1"))
+
+	// Machine constraints get the expanded model profile as fallback.
+	m, err := s.addOneMachine(c, constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cons, jc.DeepEquals, constraints.MustParse("mem=4G cores=1"))
+
+	// Explicit attributes override conflicting ones in the profile.
+	m, err = s.addOneMachine(c, constraints.MustParse("profile=big mem=16G"))
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err = m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cons, jc.DeepEquals, constraints.MustParse("mem=16G cores=8"))
+
+	_, err = s.addOneMachine(c, constraints.MustParse("profile=missing"))
+	c.Assert(err, gc.ErrorMatches, `.*constraint profile "missing" not found`)
+}
+
+func (s *constraintsValidationSuite) TestRemovingProfileKeepsStoredConstraints(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"constraint-profiles": map[string]interface{}{
+			"small": "mem=2G cores=1",
+		},
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelConstraints(constraints.MustParse("profile=small"))
+	c.Assert(err, jc.ErrorIsNil)
+	charm := s.AddTestingCharm(c, "wordpress")
+	app := s.AddTestingService(c, "wordpress", charm)
+	err = app.SetConstraints(constraints.MustParse("profile=small mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.UpdateModelConfig(nil, []string{"constraint-profiles"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	econs, err := s.State.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(econs, jc.DeepEquals, constraints.MustParse("mem=2G cores=1"))
+	acons, err := app.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(acons, jc.DeepEquals, constraints.MustParse("mem=4G cores=1"))
+
+	// New machines still resolve against the stored constraints.
+	m, err := s.addOneMachine(c, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	mcons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mcons, jc.DeepEquals, constraints.MustParse("mem=2G cores=1"))
+}
+
 func (s *applicationConstraintsSuite) SetUpTest(c *gc.C) {
 	s.ConnSuite.SetUpTest(c)
 	s.policy.GetConstraintsValidator = func() (constraints.Validator, error) {
@@ -33,3 +33,32 @@ func (st *State) ControllerConfig() (jujucontroller.Config, error) {
 	}
 	return settings.Map(), nil
 }
+
+// ConfigureMongoSession applies the mongo session settings in the
+// controller config to the State's session. The settings are
+// inherited by every session copied from it afterwards, including
+// those of States for other models created with ForModel.
+func (st *State) ConfigureMongoSession() error {
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if timeout := cfg.MongoSocketTimeout(); timeout > 0 {
+		st.session.SetSocketTimeout(timeout)
+	}
+	if size := cfg.MongoMaxPoolSize(); size > 0 {
+		st.session.SetPoolLimit(size)
+	}
+	if cfg.MongoWriteConcern() == jujucontroller.MongoWriteConcernOne {
+		// Majority write concern is only ever set when the session
+		// is dialled, as it depends on mongo running as a replica
+		// set; it is relaxed here rather than imposed.
+		if safe := st.session.Safe(); safe != nil && safe.WMode != "" {
+			relaxed := *safe
+			relaxed.WMode = ""
+			relaxed.W = 1
+			st.session.SetSafe(&relaxed)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package state_test
 import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
@@ -24,12 +25,35 @@ func (s *ControllerConfigSuite) TestControllerAndModelConfigInitialisation(c *gc
 	c.Assert(err, jc.ErrorIsNil)
 
 	optional := map[string]bool{
-		controller.IdentityURL:         true,
-		controller.IdentityPublicKey:   true,
-		controller.AutocertURLKey:      true,
-		controller.AutocertDNSNameKey:  true,
-		controller.AllowModelAccessKey: true,
-		controller.MongoMemoryProfile:  true,
+		controller.IdentityURL:                        true,
+		controller.IdentityPublicKey:                  true,
+		controller.AutocertURLKey:                     true,
+		controller.AutocertDNSNameKey:                 true,
+		controller.AllowModelAccessKey:                true,
+		controller.MongoMemoryProfile:                 true,
+		controller.MachineAuthBackendKey:              true,
+		controller.APIRateLimitConnectionKey:          true,
+		controller.APIRateLimitFacadeKey:              true,
+		controller.APIMaxConcurrentRequestsKey:        true,
+		controller.APIConcurrentRequestsBurstKey:      true,
+		controller.APIShutdownTimeoutKey:              true,
+		controller.AuditLogMaxAgeKey:                  true,
+		controller.ModelConfigHistoryMaxAgeKey:        true,
+		controller.TxnPruneMaxAgeKey:                  true,
+		controller.TxnPruneMaxSizeKey:                 true,
+		controller.CharmUploadMaxSizeKey:              true,
+		controller.ToolsUploadMaxSizeKey:              true,
+		controller.BackupUploadMaxSizeKey:             true,
+		controller.APIAllowedUserCIDRsKey:             true,
+		controller.APIAllowedAgentCIDRsKey:            true,
+		controller.MongoWriteConcernKey:               true,
+		controller.MongoSocketTimeoutKey:              true,
+		controller.MongoMaxPoolSizeKey:                true,
+		controller.StatusHistoryMaxAgeKey:             true,
+		controller.StatusHistoryMaxEntriesKey:         true,
+		controller.StatusHistoryRetentionKey:          true,
+		controller.StatusHistoryDownsampleAgeKey:      true,
+		controller.StatusHistoryDownsampleIntervalKey: true,
 	}
 	for _, controllerAttr := range controller.ControllerOnlyConfigAttributes {
 		v, ok := controllerSettings.Get(controllerAttr)
@@ -47,3 +71,38 @@ func (s *ControllerConfigSuite) TestControllerConfig(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg["controller-uuid"], gc.Equals, m.ControllerUUID())
 }
+
+func (s *ControllerConfigSuite) TestConfigureMongoSession(c *gc.C) {
+	settings, err := s.State.ReadSettings(state.ControllersC, "controllerSettings")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Update(map[string]interface{}{
+		controller.MongoWriteConcernKey:  "1",
+		controller.MongoSocketTimeoutKey: "42s",
+	})
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Simulate the write concern set when dialling a replica set.
+	session := s.State.MongoSession()
+	session.SetSafe(&mgo.Safe{WMode: "majority", J: true})
+	defer session.SetSafe(&mgo.Safe{})
+
+	err = s.State.ConfigureMongoSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(session.Safe(), jc.DeepEquals, &mgo.Safe{W: 1, J: true})
+
+	// Sessions copied from the State's session inherit the settings.
+	copied := session.Copy()
+	defer copied.Close()
+	c.Check(copied.Safe(), jc.DeepEquals, &mgo.Safe{W: 1, J: true})
+}
+
+func (s *ControllerConfigSuite) TestConfigureMongoSessionKeepsMajority(c *gc.C) {
+	session := s.State.MongoSession()
+	session.SetSafe(&mgo.Safe{WMode: "majority"})
+	defer session.SetSafe(&mgo.Safe{})
+
+	err := s.State.ConfigureMongoSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(session.Safe(), jc.DeepEquals, &mgo.Safe{WMode: "majority"})
+}
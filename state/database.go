@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
@@ -136,10 +137,11 @@ func Apply(db Database, change Change) error {
 type collectionInfo struct {
 
 	// explicitCreate, if non-nil, will cause the collection to be explicitly
-	// Create~d (with the given value) before ensuring indexes.
+	// Create~d (with the given value) when state is opened.
 	explicitCreate *mgo.CollectionInfo
 
-	// indexes listed here will be EnsureIndex~ed before state is opened.
+	// indexes listed here will be EnsureIndex~ed by MigrateSchema, when
+	// a controller is bootstrapped or upgraded.
 	indexes []mgo.Index
 
 	// global collections will not have model filtering applied. Non-
@@ -170,13 +172,14 @@ type collectionInfo struct {
 // collectionSchema defines the set of collections used in juju.
 type collectionSchema map[string]collectionInfo
 
-// Load causes all recorded collections to be created and indexed as specified;
+// Load causes all recorded collections to be created as specified;
 // the returned Database will filter queries and transactions according to the
 // suppplied model UUID.
 func (schema collectionSchema) Load(
 	db *mgo.Database,
 	modelUUID string,
 	runTransactionObserver RunTransactionObserverFunc,
+	queryObserver QueryObserverFunc,
 ) (Database, error) {
 	if !names.IsValidModel(modelUUID) {
 		return nil, errors.New("invalid model UUID")
@@ -189,17 +192,13 @@ func (schema collectionSchema) Load(
 				return nil, maybeUnauthorized(err, message)
 			}
 		}
-		for _, index := range info.indexes {
-			if err := rawCollection.EnsureIndex(index); err != nil {
-				return nil, maybeUnauthorized(err, "cannot create index")
-			}
-		}
 	}
 	return &database{
 		raw:                    db,
 		schema:                 schema,
 		modelUUID:              modelUUID,
 		runTransactionObserver: runTransactionObserver,
+		queryObserver:          queryObserver,
 	}, nil
 }
 
@@ -239,23 +238,42 @@ type database struct {
 	// runTransactionObserver is passed on to txn.TransactionRunner, to be
 	// invoked after calls to Run and RunTransaction.
 	runTransactionObserver RunTransactionObserverFunc
+
+	// queryObserver, if non-nil, is invoked after each query made
+	// through the collections returned by GetCollection.
+	queryObserver QueryObserverFunc
 }
 
 // RunTransactionObserverFunc is the type of a function to be called
 // after an mgo/txn transaction is run.
 type RunTransactionObserverFunc func(dbName, modelUUID string, ops []txn.Op, err error)
 
+// QueryObserverFunc is the type of a function to be called after a
+// query against the named collection completes, with the time the
+// query took.
+type QueryObserverFunc func(dbName, collection string, duration time.Duration)
+
 func (db *database) copySession(modelUUID string) (*database, SessionCloser) {
 	session := db.raw.Session.Copy()
 	return &database{
-		raw:        db.raw.With(session),
-		schema:     db.schema,
-		modelUUID:  modelUUID,
-		runner:     db.runner,
-		ownSession: true,
+		raw:           db.raw.With(session),
+		schema:        db.schema,
+		modelUUID:     modelUUID,
+		runner:        db.runner,
+		ownSession:    true,
+		queryObserver: db.queryObserver,
 	}, session.Close
 }
 
+// copySecondaryPreferred returns a matching database with its own
+// session, whose queries are served by a secondary when one is
+// available.
+func (db *database) copySecondaryPreferred() (*database, SessionCloser) {
+	copied, closer := db.copySession(db.modelUUID)
+	copied.raw.Session.SetMode(mgo.SecondaryPreferred, true)
+	return copied, closer
+}
+
 // Copy is part of the Database interface.
 func (db *database) Copy() (Database, SessionCloser) {
 	return db.copySession(db.modelUUID)
@@ -281,6 +299,17 @@ func (db *database) GetCollection(name string) (collection mongo.Collection, clo
 		collection, closer = mongo.CollectionFromName(db.raw, name)
 	}
 
+	// Time queries, if anyone is interested.
+	if db.queryObserver != nil {
+		dbName, observer := db.raw.Name, db.queryObserver
+		collection = &observedCollection{
+			WriteCollection: collection.Writeable(),
+			observe: func(d time.Duration) {
+				observer(dbName, name, d)
+			},
+		}
+	}
+
 	// Apply model filtering.
 	if !info.global {
 		collection = &modelStateCollection{
@@ -57,6 +57,42 @@ func onAbort(txnErr, err error) error {
 	return errors.Trace(txnErr)
 }
 
+// ErrQuotaExceeded is returned when adding machines, units or storage
+// to a model would exceed one of its quotas.
+type ErrQuotaExceeded struct {
+	resource string
+	limit    uint64
+	unit     string
+}
+
+func (e *ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("%s quota of %d%s exceeded", e.resource, e.limit, e.unit)
+}
+
+func newQuotaExceededError(resource string, limit uint64, unit string) error {
+	return &ErrQuotaExceeded{
+		resource: resource,
+		limit:    limit,
+		unit:     unit,
+	}
+}
+
+// IsQuotaExceededError returns if the given error or its cause is
+// ErrQuotaExceeded.
+func IsQuotaExceededError(err interface{}) bool {
+	if err == nil {
+		return false
+	}
+	// In case of a wrapped error, check the cause first.
+	value := err
+	cause := errors.Cause(err.(error))
+	if cause != nil {
+		value = cause
+	}
+	_, ok := value.(*ErrQuotaExceeded)
+	return ok
+}
+
 // ErrProviderIDNotUnique is a standard error to indicate the value specified
 // for a ProviderID field is not unique within the current model.
 type ErrProviderIDNotUnique struct {
@@ -4,8 +4,11 @@
 package audit
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/audit"
 	"github.com/juju/juju/mongo/utils"
@@ -26,6 +29,12 @@ type auditEntryDoc struct {
 	// unmarshaled via time.Time::UnmarshalText.
 	Timestamp string `bson:"timestamp"`
 
+	// Time is also when the audit entry was written. Unlike
+	// Timestamp it is stored as a date, so that entries can be
+	// selected and pruned by time. Entries written by older
+	// versions of jujud do not have it.
+	Time time.Time `bson:"time,omitempty"`
+
 	// RemoteAddress is the IP of the machine from which the
 	// audit-event was triggered.
 	RemoteAddress string `bson:"remote-address"`
@@ -75,6 +84,7 @@ func auditEntryDocFromAuditEntry(auditEntry audit.AuditEntry) (auditEntryDoc, er
 		JujuServerVersion: auditEntry.JujuServerVersion,
 		ModelUUID:         auditEntry.ModelUUID,
 		Timestamp:         string(timeAsBlob),
+		Time:              auditEntry.Timestamp,
 		RemoteAddress:     auditEntry.RemoteAddress,
 		OriginType:        auditEntry.OriginType,
 		OriginName:        auditEntry.OriginName,
@@ -82,3 +92,67 @@ func auditEntryDocFromAuditEntry(auditEntry audit.AuditEntry) (auditEntryDoc, er
 		Data:              utils.EscapeKeys(auditEntry.Data),
 	}, nil
 }
+
+// GetAuditEntriesFn creates a closure which when passed a Filter
+// will return the matching entries from the audit collection, most
+// recent first. findDocs should sort the matching documents by the
+// given fields, limit them to the given number if it is positive,
+// and unmarshal them into result.
+func GetAuditEntriesFn(
+	collectionName string,
+	findDocs func(collectionName string, query bson.M, sort []string, limit int, result interface{}) error,
+) func(audit.Filter) ([]audit.AuditEntry, error) {
+	return func(filter audit.Filter) ([]audit.AuditEntry, error) {
+		var docs []auditEntryDoc
+		err := findDocs(collectionName, filterQuery(filter), []string{"-time"}, filter.Limit, &docs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		entries := make([]audit.AuditEntry, len(docs))
+		for i, doc := range docs {
+			entries[i], err = auditEntryFromAuditEntryDoc(doc)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		return entries, nil
+	}
+}
+
+func filterQuery(filter audit.Filter) bson.M {
+	query := bson.M{}
+	if filter.ModelUUID != "" {
+		query["model-uuid"] = filter.ModelUUID
+	}
+	if filter.OriginName != "" {
+		query["origin-name"] = filter.OriginName
+	}
+	timeQuery := bson.M{}
+	if !filter.After.IsZero() {
+		timeQuery["$gt"] = filter.After
+	}
+	if !filter.Before.IsZero() {
+		timeQuery["$lt"] = filter.Before
+	}
+	if len(timeQuery) > 0 {
+		query["time"] = timeQuery
+	}
+	return query
+}
+
+func auditEntryFromAuditEntryDoc(doc auditEntryDoc) (audit.AuditEntry, error) {
+	var timestamp time.Time
+	if err := timestamp.UnmarshalText([]byte(doc.Timestamp)); err != nil {
+		return audit.AuditEntry{}, errors.Trace(err)
+	}
+	return audit.AuditEntry{
+		JujuServerVersion: doc.JujuServerVersion,
+		ModelUUID:         doc.ModelUUID,
+		Timestamp:         timestamp.UTC(),
+		RemoteAddress:     doc.RemoteAddress,
+		OriginType:        doc.OriginType,
+		OriginName:        doc.OriginName,
+		Operation:         doc.Operation,
+		Data:              utils.UnescapeKeys(doc.Data),
+	}, nil
+}
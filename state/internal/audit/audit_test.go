@@ -4,6 +4,8 @@
 package audit_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -58,6 +60,7 @@ func (*AuditSuite) TestPutAuditEntry_PersistAuditEntry(c *gc.C) {
 			"juju-server-version": requested.JujuServerVersion,
 			"model-uuid":          requested.ModelUUID,
 			"timestamp":           string(requestedTimeBlob),
+			"time":                requested.Timestamp,
 			"remote-address":      "8.8.8.8",
 			"origin-type":         requested.OriginType,
 			"origin-name":         requested.OriginName,
@@ -111,3 +114,67 @@ func (*AuditSuite) TestPutAuditEntry_ValidateAuditEntry(c *gc.C) {
 	err := putAuditEntry(auditEntry)
 	c.Check(err, gc.ErrorMatches, validationErr.Error())
 }
+
+func (*AuditSuite) TestGetAuditEntries(c *gc.C) {
+	timestamp := coretesting.NonZeroTime().UTC()
+	timeBlob, err := timestamp.MarshalText()
+	c.Assert(err, jc.ErrorIsNil)
+	modelUUID := utils.MustNewUUID().String()
+
+	findDocs := func(collectionName string, query bson.M, sort []string, limit int, result interface{}) error {
+		c.Check(collectionName, gc.Equals, "audit.log")
+		c.Check(query, jc.DeepEquals, bson.M{
+			"model-uuid":  modelUUID,
+			"origin-name": "user-bob",
+			"time":        bson.M{"$gt": timestamp.Add(-time.Hour)},
+		})
+		c.Check(sort, jc.DeepEquals, []string{"-time"})
+		c.Check(limit, gc.Equals, 10)
+
+		data, err := bson.Marshal(bson.M{"docs": []bson.M{{
+			"juju-server-version": version.MustParse("1.0.0"),
+			"model-uuid":          modelUUID,
+			"timestamp":           string(timeBlob),
+			"time":                timestamp,
+			"remote-address":      "8.8.8.8",
+			"origin-type":         "API request",
+			"origin-name":         "user-bob",
+			"operation":           "Application:v1 - Deploy",
+			"data":                mongoutils.EscapeKeys(map[string]interface{}{"$a.b": "c"}),
+		}}})
+		c.Assert(err, jc.ErrorIsNil)
+		var wrapper struct {
+			Docs bson.Raw `bson:"docs"`
+		}
+		c.Assert(bson.Unmarshal(data, &wrapper), jc.ErrorIsNil)
+		return wrapper.Docs.Unmarshal(result)
+	}
+
+	getAuditEntries := stateaudit.GetAuditEntriesFn("audit.log", findDocs)
+	entries, err := getAuditEntries(audit.Filter{
+		ModelUUID:  modelUUID,
+		OriginName: "user-bob",
+		After:      timestamp.Add(-time.Hour),
+		Limit:      10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, jc.DeepEquals, []audit.AuditEntry{{
+		JujuServerVersion: version.MustParse("1.0.0"),
+		ModelUUID:         modelUUID,
+		Timestamp:         timestamp,
+		RemoteAddress:     "8.8.8.8",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Application:v1 - Deploy",
+		Data:              map[string]interface{}{"$a.b": "c"},
+	}})
+}
+
+func (*AuditSuite) TestGetAuditEntries_PropagatesReadError(c *gc.C) {
+	findDocs := func(string, bson.M, []string, int, interface{}) error {
+		return errors.New("my error")
+	}
+	getAuditEntries := stateaudit.GetAuditEntriesFn("audit.log", findDocs)
+	_, err := getAuditEntries(audit.Filter{})
+	c.Check(err, gc.ErrorMatches, "my error")
+}
@@ -68,9 +68,10 @@ func (client *client) Leases() map[string]lease.Info {
 	for name, entry := range client.entries {
 		skew := client.skews[entry.writer]
 		leases[name] = lease.Info{
-			Holder:   entry.holder,
-			Expiry:   skew.Latest(entry.expiry),
-			Trapdoor: client.assertOpTrapdoor(name, entry.holder),
+			Holder:     entry.holder,
+			Expiry:     skew.Latest(entry.expiry),
+			Extensions: entry.extensions,
+			Trapdoor:   client.assertOpTrapdoor(name, entry.holder),
 		}
 	}
 	return leases
@@ -387,9 +388,10 @@ func (client *client) extendLeaseOps(name string, request lease.Request) ([]txn.
 	// We know we need to write a lease; we know when it needs to expire; we
 	// know what needs to go into the local cache:
 	nextEntry := entry{
-		holder: lastEntry.holder,
-		expiry: expiry,
-		writer: client.config.Id,
+		holder:     lastEntry.holder,
+		expiry:     expiry,
+		writer:     client.config.Id,
+		extensions: lastEntry.extensions + 1,
 	}
 
 	// ...and what needs to change in the database, and how to ensure the
@@ -403,8 +405,9 @@ func (client *client) extendLeaseOps(name string, request lease.Request) ([]txn.
 			fieldLeaseWriter: lastEntry.writer,
 		},
 		Update: bson.M{"$set": bson.M{
-			fieldLeaseExpiry: toInt64(expiry),
-			fieldLeaseWriter: client.config.Id,
+			fieldLeaseExpiry:     toInt64(expiry),
+			fieldLeaseWriter:     client.config.Id,
+			fieldLeaseExtensions: nextEntry.extensions,
 		}},
 	}
 
@@ -516,6 +519,10 @@ type entry struct {
 
 	// writer identifies the client that wrote the lease.
 	writer string
+
+	// extensions counts the times the lease has been extended since
+	// it was claimed.
+	extensions int
 }
 
 // errNoExtension is used internally to avoid running unnecessary transactions.
@@ -88,6 +88,28 @@ func (s *ClientOperationSuite) TestExtendLease(c *gc.C) {
 	c.Check("name", fix.Expiry(), exactExpiry)
 }
 
+func (s *ClientOperationSuite) TestExtendLeaseCountsExtensions(c *gc.C) {
+	fix := s.EasyFixture(c)
+	err := fix.Client.ClaimLease("name", lease.Request{"holder", time.Second})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fix.Client.Leases()["name"].Extensions, gc.Equals, 0)
+
+	err = fix.Client.ExtendLease("name", lease.Request{"holder", time.Minute})
+	c.Assert(err, jc.ErrorIsNil)
+	err = fix.Client.ExtendLease("name", lease.Request{"holder", time.Hour})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fix.Client.Leases()["name"].Extensions, gc.Equals, 2)
+
+	// An extension that needs no write is not counted.
+	err = fix.Client.ExtendLease("name", lease.Request{"holder", time.Second})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fix.Client.Leases()["name"].Extensions, gc.Equals, 2)
+
+	// The count is persisted, and seen by other clients.
+	fix2 := s.NewFixture(c, FixtureParams{Id: "other-client"})
+	c.Check(fix2.Client.Leases()["name"].Extensions, gc.Equals, 2)
+}
+
 func (s *ClientOperationSuite) TestCanExtendStaleLease(c *gc.C) {
 	fix := s.EasyFixture(c)
 	err := fix.Client.ClaimLease("name", lease.Request{"holder", time.Second})
@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lease
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const (
+	namespaceLabel = "namespace"
+	operationLabel = "operation"
+	failedLabel    = "failed"
+
+	operationClaim  = "claim"
+	operationExtend = "extend"
+	operationExpire = "expire"
+)
+
+var leaseOperationLabelNames = []string{
+	namespaceLabel,
+	operationLabel,
+	failedLabel,
+}
+
+// MetricsCollector is a prometheus.Collector that collects metrics
+// about the leases claimed, extended, and expired by the transactions
+// run against a lease collection.
+type MetricsCollector struct {
+	collection             string
+	leaseOperationsCounter *prometheus.CounterVec
+}
+
+// NewMetricsCollector returns a new MetricsCollector that counts the
+// lease operations written to the named collection.
+func NewMetricsCollector(collection string) *MetricsCollector {
+	return &MetricsCollector{
+		collection: collection,
+		leaseOperationsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "juju",
+				Name:      "lease_operations_total",
+				Help:      "Total number of lease claims, extensions, and expiries.",
+			},
+			leaseOperationLabelNames,
+		),
+	}
+}
+
+// AfterRunTransaction is called when a mgo/txn transaction has run.
+// It has the signature of state.RunTransactionObserverFunc.
+func (c *MetricsCollector) AfterRunTransaction(dbName, modelUUID string, ops []txn.Op, err error) {
+	var failed string
+	if err != nil {
+		failed = "failed"
+	}
+	for _, op := range ops {
+		if op.C != c.collection {
+			continue
+		}
+		namespace, ok := leaseDocNamespace(op.Id)
+		if !ok {
+			// Clock documents are written alongside every lease
+			// operation, and are not counted.
+			continue
+		}
+		var operation string
+		switch {
+		case op.Insert != nil:
+			operation = operationClaim
+		case op.Update != nil:
+			operation = operationExtend
+		case op.Remove:
+			operation = operationExpire
+		default:
+			continue
+		}
+		c.leaseOperationsCounter.With(prometheus.Labels{
+			namespaceLabel: namespace,
+			operationLabel: operation,
+			failedLabel:    failed,
+		}).Inc()
+	}
+}
+
+// leaseDocNamespace returns the namespace of the lease document with
+// the supplied id, which may be prefixed by a model UUID. It returns
+// false if the id is not that of a lease document.
+func leaseDocNamespace(id interface{}) (string, bool) {
+	docId, ok := id.(string)
+	if !ok {
+		return "", false
+	}
+	if i := strings.Index(docId, ":"); i >= 0 {
+		docId = docId[i+1:]
+	}
+	fields := strings.Split(docId, "#")
+	if len(fields) != 4 || fields[0] != typeLease || fields[3] != "" {
+		return "", false
+	}
+	return fields[1], true
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.leaseOperationsCounter.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.leaseOperationsCounter.Collect(ch)
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lease_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state/lease"
+)
+
+type MetricsCollectorSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&MetricsCollectorSuite{})
+
+func (s *MetricsCollectorSuite) TestCountsLeaseOperations(c *gc.C) {
+	collector := lease.NewMetricsCollector("leases")
+	registry := prometheus.NewRegistry()
+	err := registry.Register(collector)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clockOp := txn.Op{C: "leases", Id: "uuid:clock#ns#", Update: bson.M{}}
+	collector.AfterRunTransaction("juju", "uuid", []txn.Op{
+		clockOp,
+		{C: "leases", Id: "uuid:lease#ns#name#", Insert: bson.M{}},
+	}, nil)
+	collector.AfterRunTransaction("juju", "uuid", []txn.Op{
+		clockOp,
+		{C: "leases", Id: "uuid:lease#ns#name#", Update: bson.M{}},
+	}, nil)
+	collector.AfterRunTransaction("juju", "uuid", []txn.Op{
+		clockOp,
+		{C: "leases", Id: "lease#other#name#", Update: bson.M{}},
+	}, errors.New("boom"))
+	collector.AfterRunTransaction("juju", "uuid", []txn.Op{
+		clockOp,
+		{C: "leases", Id: "uuid:lease#ns#name#", Remove: true},
+	}, nil)
+	// Operations on other collections are ignored.
+	collector.AfterRunTransaction("juju", "uuid", []txn.Op{
+		{C: "machines", Id: "uuid:lease#ns#name#", Insert: bson.M{}},
+	}, nil)
+
+	families, err := registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(families, gc.HasLen, 1)
+	c.Assert(families[0].GetName(), gc.Equals, "juju_lease_operations_total")
+
+	counts := make(map[string]float64)
+	for _, metric := range families[0].Metric {
+		labels := make(map[string]string)
+		for _, label := range metric.Label {
+			labels[label.GetName()] = label.GetValue()
+		}
+		key := labels["namespace"] + " " + labels["operation"] + " " + labels["failed"]
+		counts[key] = metric.GetCounter().GetValue()
+	}
+	c.Assert(counts, jc.DeepEquals, map[string]float64{
+		"ns claim ":           1,
+		"ns extend ":          1,
+		"ns expire ":          1,
+		"other extend failed": 1,
+	})
+}
@@ -31,6 +31,10 @@ const (
 	fieldLeaseExpiry = "expiry"
 	fieldLeaseWriter = "writer"
 
+	// fieldLeaseExtensions is optional, and absent from documents
+	// written before extensions were counted.
+	fieldLeaseExtensions = "extensions"
+
 	// fieldClock* identify the fields in a clockDoc.
 	fieldClockWriters = "writers"
 )
@@ -68,6 +72,10 @@ type leaseDoc struct {
 	Holder string `bson:"holder"`
 	Expiry int64  `bson:"expiry"`
 	Writer string `bson:"writer"`
+
+	// Extensions maps directly to entry, and is zero when the lease
+	// has never been extended.
+	Extensions int `bson:"extensions,omitempty"`
 }
 
 // validate returns an error if any fields are invalid or inconsistent.
@@ -89,6 +97,9 @@ func (doc leaseDoc) validate() error {
 	if err := lease.ValidateString(doc.Writer); err != nil {
 		return errors.Annotatef(err, "invalid writer")
 	}
+	if doc.Extensions < 0 {
+		return errors.Errorf("invalid extensions")
+	}
 	return nil
 }
 
@@ -99,9 +110,10 @@ func (doc leaseDoc) entry() (string, entry, error) {
 		return "", entry{}, errors.Trace(err)
 	}
 	entry := entry{
-		holder: doc.Holder,
-		expiry: toTime(doc.Expiry),
-		writer: doc.Writer,
+		holder:     doc.Holder,
+		expiry:     toTime(doc.Expiry),
+		writer:     doc.Writer,
+		extensions: doc.Extensions,
 	}
 	return doc.Name, entry, nil
 }
@@ -110,13 +122,14 @@ func (doc leaseDoc) entry() (string, entry, error) {
 // entry in the supplied namespace, or an error.
 func newLeaseDoc(namespace, name string, entry entry) (*leaseDoc, error) {
 	doc := &leaseDoc{
-		Id:        leaseDocId(namespace, name),
-		Type:      typeLease,
-		Namespace: namespace,
-		Name:      name,
-		Holder:    entry.holder,
-		Expiry:    toInt64(entry.expiry),
-		Writer:    entry.writer,
+		Id:         leaseDocId(namespace, name),
+		Type:       typeLease,
+		Namespace:  namespace,
+		Name:       name,
+		Holder:     entry.holder,
+		Expiry:     toInt64(entry.expiry),
+		Writer:     entry.writer,
+		Extensions: entry.extensions,
 	}
 	if err := doc.validate(); err != nil {
 		return nil, errors.Trace(err)
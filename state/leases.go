@@ -0,0 +1,88 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/lease"
+	statelease "github.com/juju/juju/state/lease"
+)
+
+// LeaseDetails describes a lease held in the model.
+type LeaseDetails struct {
+	// Namespace identifies the kind of lease, such as application
+	// leadership.
+	Namespace string
+
+	// Name is the name of the lease within its namespace.
+	Name string
+
+	// Holder is the name of the current leaseholder.
+	Holder string
+
+	// Expiry is the latest time at which the lease might still be
+	// valid.
+	Expiry time.Time
+
+	// Extensions is the number of times the lease has been extended
+	// since it was last claimed.
+	Extensions int
+}
+
+// LeaseDetails returns the details of the application leadership and
+// singular controller leases held in the model, ordered by namespace
+// and name.
+func (st *State) LeaseDetails() ([]LeaseDetails, error) {
+	leadershipClient, err := st.getLeadershipLeaseClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	singularClient, err := st.getSingularLeaseClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []LeaseDetails
+	for _, source := range []struct {
+		namespace string
+		client    lease.Client
+	}{
+		{applicationLeadershipNamespace, leadershipClient},
+		{singularControllerNamespace, singularClient},
+	} {
+		for name, info := range source.client.Leases() {
+			result = append(result, LeaseDetails{
+				Namespace:  source.namespace,
+				Name:       name,
+				Holder:     info.Holder,
+				Expiry:     info.Expiry,
+				Extensions: info.Extensions,
+			})
+		}
+	}
+	sort.Sort(byLeaseNamespaceAndName(result))
+	return result, nil
+}
+
+type byLeaseNamespaceAndName []LeaseDetails
+
+func (b byLeaseNamespaceAndName) Len() int      { return len(b) }
+func (b byLeaseNamespaceAndName) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byLeaseNamespaceAndName) Less(i, j int) bool {
+	if b[i].Namespace != b[j].Namespace {
+		return b[i].Namespace < b[j].Namespace
+	}
+	return b[i].Name < b[j].Name
+}
+
+// NewLeaseMetricsCollector returns a collector of metrics about the
+// lease operations run against the database. Its AfterRunTransaction
+// method must be called with every transaction, for example from
+// the RunTransactionObserver supplied to Open.
+func NewLeaseMetricsCollector() *statelease.MetricsCollector {
+	return statelease.NewMetricsCollector(leasesC)
+}
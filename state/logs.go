@@ -59,28 +59,6 @@ type ModelSessioner interface {
 	ModelUUID() string
 }
 
-// logIndexes defines the indexes we need on the log collection.
-var logIndexes = [][]string{
-	// This index needs to include _id because
-	// logTailer.processCollection uses _id to ensure log records with
-	// the same time have a consistent ordering.
-	{"e", "t", "_id"},
-	{"e", "n"},
-}
-
-// InitDbLogs sets up the indexes for the logs collection. It should
-// be called as state is opened. It is idempotent.
-func InitDbLogs(session *mgo.Session) error {
-	logsColl := session.DB(logsDB).C(logsC)
-	for _, key := range logIndexes {
-		err := logsColl.EnsureIndex(mgo.Index{Key: key})
-		if err != nil {
-			return errors.Annotate(err, "cannot create index for logs collection")
-		}
-	}
-	return nil
-}
-
 // lastSentDoc captures timestamp of the last log record forwarded
 // to a log sink.
 type lastSentDoc struct {
@@ -340,6 +318,7 @@ type LogTailerParams struct {
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
+	MessageRegexp string          // Matched against log messages
 	Oplog         *mgo.Collection // For testing only
 	AllModels     bool
 }
@@ -575,6 +554,9 @@ func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bso
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if params.MessageRegexp != "" {
+		sel = append(sel, bson.DocElem{"x", bson.RegEx{Pattern: params.MessageRegexp}})
+	}
 	if prefix != "" {
 		for i, elem := range sel {
 			sel[i].Name = prefix + elem.Name
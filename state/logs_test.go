@@ -150,7 +150,8 @@ func (s *LogsSuite) TestAllLastSentLogTrackerNotController(c *gc.C) {
 }
 
 func (s *LogsSuite) TestIndexesCreated(c *gc.C) {
-	// Indexes should be created on the logs collection when state is opened.
+	// Indexes should be created on the logs collection when state is
+	// initialized.
 	indexes, err := s.logsColl.Indexes()
 	c.Assert(err, jc.ErrorIsNil)
 	var keys []string
@@ -780,6 +781,23 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestMessageRegexp(c *gc.C) {
+	failed := logTemplate{Message: `hook "install" failed: exit status 1`}
+	ok := logTemplate{Message: `hook "install" completed`}
+	writeLogs := func() {
+		s.writeLogs(c, 1, ok)
+		s.writeLogs(c, 1, failed)
+		s.writeLogs(c, 1, ok)
+	}
+	params := &state.LogTailerParams{
+		MessageRegexp: `hook ".*" failed`,
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, failed)
+	}
+	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	c *gc.C,
 	st *state.State,
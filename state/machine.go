@@ -148,6 +148,15 @@ type machineDoc struct {
 	// StopMongoUntilVersion holds the version that must be checked to
 	// know if mongo must be stopped.
 	StopMongoUntilVersion string `bson:",omitempty"`
+
+	// DesiredAgentVersion holds the version of juju that the machine's
+	// agent should run ahead of the model's agent-version, allowing
+	// an upgrade to be staged on a subset of machines.
+	DesiredAgentVersion string `bson:"desiredagentversion,omitempty"`
+
+	// Tags holds user-defined tags, applied to the machine's
+	// instance where the provider supports it.
+	Tags map[string]string `bson:"tags,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -329,6 +338,76 @@ func (m *Machine) StopMongoUntilVersion() (mongo.Version, error) {
 	return mongo.NewVersion(m.doc.StopMongoUntilVersion)
 }
 
+// DesiredAgentVersion returns the version of juju that the machine's
+// agent should run ahead of the model's agent-version, and whether one
+// has been set.
+func (m *Machine) DesiredAgentVersion() (version.Number, bool) {
+	if m.doc.DesiredAgentVersion == "" {
+		return version.Zero, false
+	}
+	v, err := version.Parse(m.doc.DesiredAgentVersion)
+	if err != nil {
+		logger.Warningf("ignoring invalid desired agent version %q for machine %v", m.doc.DesiredAgentVersion, m)
+		return version.Zero, false
+	}
+	return v, true
+}
+
+// SetDesiredAgentVersion records the version of juju that the machine's
+// agent should run ahead of the model's agent-version. Setting the zero
+// version clears it, so that the agent follows agent-version again.
+func (m *Machine) SetDesiredAgentVersion(v version.Number) error {
+	ops := []txn.Op{setDesiredAgentVersionOp(m.doc.DocID, v)}
+	if err := m.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set desired agent version for machine %v", m)
+	}
+	if v == version.Zero {
+		m.doc.DesiredAgentVersion = ""
+	} else {
+		m.doc.DesiredAgentVersion = v.String()
+	}
+	return nil
+}
+
+// clearDesiredAgentVersionsOps returns the operations that clear the
+// desired agent versions of all machines in the model.
+func (st *State) clearDesiredAgentVersionsOps() ([]txn.Op, error) {
+	machines, closer := st.getCollection(machinesC)
+	defer closer()
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	query := bson.D{{"desiredagentversion", bson.D{{"$exists", true}}}}
+	if err := machines.Find(query).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      machinesC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$unset", bson.D{{"desiredagentversion", nil}}}},
+		}
+	}
+	return ops, nil
+}
+
+// setDesiredAgentVersionOp returns the operation that records the
+// desired agent version of the machine with the given document id.
+func setDesiredAgentVersionOp(docID string, v version.Number) txn.Op {
+	update := bson.D{{"$set", bson.D{{"desiredagentversion", v.String()}}}}
+	if v == version.Zero {
+		update = bson.D{{"$unset", bson.D{{"desiredagentversion", nil}}}}
+	}
+	return txn.Op{
+		C:      machinesC,
+		Id:     docID,
+		Assert: notDeadDoc,
+		Update: update,
+	}
+}
+
 // IsManager returns true if the machine has JobManageModel.
 func (m *Machine) IsManager() bool {
 	return hasJob(m.doc.Jobs, JobManageModel)
@@ -1547,7 +1626,8 @@ func (m *Machine) SetConstraints(cons constraints.Value) (err error) {
 	if len(unsupported) > 0 {
 		logger.Warningf(
 			"setting constraints on machine %q: unsupported constraints: %v", m.Id(), strings.Join(unsupported, ","))
-	} else if err != nil {
+	}
+	if err != nil {
 		return err
 	}
 	notSetYet := bson.D{{"nonce", ""}}
@@ -211,6 +211,33 @@ func (s *MachineSuite) TestMachineIsManager(c *gc.C) {
 	c.Assert(s.machine.IsManager(), jc.IsFalse)
 }
 
+func (s *MachineSuite) TestSetDesiredAgentVersion(c *gc.C) {
+	_, ok := s.machine.DesiredAgentVersion()
+	c.Assert(ok, jc.IsFalse)
+
+	err := s.machine.SetDesiredAgentVersion(version.MustParse("2.2.1"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	v, ok := s.machine.DesiredAgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, version.MustParse("2.2.1"))
+
+	err = s.machine.SetDesiredAgentVersion(version.Zero)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.machine.DesiredAgentVersion()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *MachineSuite) TestSetDesiredAgentVersionDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetDesiredAgentVersion(version.MustParse("2.2.1"))
+	c.Assert(err, gc.ErrorMatches, "cannot set desired agent version for machine 1: not found or dead")
+}
+
 func (s *MachineSuite) TestMachineIsManualBootstrap(c *gc.C) {
 	cfg, err := s.State.ModelConfig()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1525,6 +1552,14 @@ func (s *MachineSuite) TestSetAmbiguousConstraints(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set constraints: ambiguous constraints: "instance-type" overlaps with "mem"`)
 }
 
+func (s *MachineSuite) TestSetAmbiguousConstraintsWithUnsupported(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	cons := constraints.MustParse("mem=4G instance-type=foo cpu-power=10")
+	err = machine.SetConstraints(cons)
+	c.Assert(err, gc.ErrorMatches, `cannot set constraints: ambiguous constraints: "instance-type" overlaps with "mem"`)
+}
+
 func (s *MachineSuite) TestSetUnsupportedConstraintsWarning(c *gc.C) {
 	defer loggo.ResetWriters()
 	logger := loggo.GetLogger("test")
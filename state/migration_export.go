@@ -14,6 +14,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2/bson"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/storage/poolmanager"
@@ -960,7 +961,27 @@ func (e *exporter) cloudimagemetadata() error {
 		return errors.Trace(err)
 	}
 	e.logger.Debugf("read %d cloudimagemetadata", len(cloudimagemetadata))
+	// Image metadata is shared by every model on the controller, so
+	// only that for the model's cloud and region is exported. The
+	// target controller then provisions the model's machines with the
+	// same images, including any added by the user. Metadata saved
+	// without a cloud is taken to be the controller's.
+	controllerModel, err := e.st.ControllerModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cloud, region := e.dbModel.Cloud(), e.dbModel.CloudRegion()
 	for _, metadata := range cloudimagemetadata {
+		metadataCloud := metadata.Cloud
+		if metadataCloud == "" {
+			metadataCloud = controllerModel.Cloud()
+		}
+		if metadataCloud != cloud {
+			continue
+		}
+		if region != "" && metadata.Region != region {
+			continue
+		}
 		e.model.AddCloudImageMetadata(description.CloudImageMetadataArgs{
 			Stream:          metadata.Stream,
 			Region:          metadata.Region,
@@ -1262,6 +1283,46 @@ func (e *exporter) statusHistoryArgs(globalKey string) []description.StatusArgs
 	return result
 }
 
+// unexportableConstraints holds the constraints, by constraints doc
+// field and constraint name, that cannot be represented in a model
+// description.
+var unexportableConstraints = []struct {
+	field string
+	name  string
+}{
+	{"gpus", constraints.GPUs},
+	{"gputype", constraints.GPUType},
+	{"imageid", constraints.ImageID},
+	{"allocatepublicip", constraints.AllocatePublicIP},
+	{"profile", constraints.Profile},
+	{"maxcpucores", "cores range"},
+	{"maxmem", "mem range"},
+	{"spread", constraints.Spread},
+	{"rootdisksource", constraints.RootDiskSource},
+	{"spotprice", constraints.SpotPrice},
+}
+
+// UnmigratableConstraints returns the names of the constraints in cons
+// that cannot be represented in a model description. A model in which
+// any such constraints are set cannot be migrated.
+func UnmigratableConstraints(cons constraints.Value) ([]string, error) {
+	data, err := bson.Marshal(newConstraintsDoc(nil, cons))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var names []string
+	for _, unexportable := range unexportableConstraints {
+		if doc[unexportable.field] != nil {
+			names = append(names, unexportable.name)
+		}
+	}
+	return names, nil
+}
+
 func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArgs, error) {
 	doc, found := e.constraints[globalKey]
 	if !found {
@@ -1305,6 +1366,13 @@ func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArg
 		}
 		return nil
 	}
+	// The description package cannot yet represent some constraints.
+	// Refuse to export them rather than silently dropping them.
+	for _, unexportable := range unexportableConstraints {
+		if doc[unexportable.field] != nil {
+			return description.ConstraintsArgs{}, errors.NotSupportedf("migrating %s constraint", unexportable.name)
+		}
+	}
 	result := description.ConstraintsArgs{
 		Architecture: optionalString("arch"),
 		Container:    optionalString("container"),
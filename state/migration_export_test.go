@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/juju/description"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
@@ -184,6 +185,25 @@ func (s *MigrationExportSuite) TestModelInfo(c *gc.C) {
 	})
 }
 
+func (s *MigrationExportSuite) TestUnexportableConstraints(c *gc.C) {
+	err := s.State.SetModelConstraints(constraints.MustParse("gpus=1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, "migrating gpus constraint not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MigrationExportSuite) TestUnmigratableConstraints(c *gc.C) {
+	names, err := state.UnmigratableConstraints(constraints.MustParse("mem=4G gpus=1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"gpus"})
+
+	names, err = state.UnmigratableConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
+
 func (s *MigrationExportSuite) TestModelUsers(c *gc.C) {
 	// Make sure we have some last connection times for the admin user,
 	// and create a few other users.
@@ -706,7 +726,7 @@ func (s *MigrationExportSuite) TestCloudImageMetadatas(c *gc.C) {
 	storageSize := uint64(3)
 	attrs := cloudimagemetadata.MetadataAttributes{
 		Stream:          "stream",
-		Region:          "region-test",
+		Region:          "dummy-region",
 		Version:         "14.04",
 		Series:          "trusty",
 		Arch:            "arch",
@@ -715,7 +735,17 @@ func (s *MigrationExportSuite) TestCloudImageMetadatas(c *gc.C) {
 		RootStorageSize: &storageSize,
 		Source:          "test",
 	}
-	metadata := []cloudimagemetadata.Metadata{{attrs, 2, "1", 2}}
+	// Metadata for clouds or regions other than the model's is not
+	// exported; metadata without a cloud is the controller's.
+	otherAttrs := attrs
+	otherAttrs.Region = "other-region"
+	otherCloudAttrs := attrs
+	otherCloudAttrs.Cloud = "other-cloud"
+	metadata := []cloudimagemetadata.Metadata{
+		{attrs, 2, "1", 2},
+		{otherAttrs, 2, "2", 2},
+		{otherCloudAttrs, 2, "3", 2},
+	}
 
 	err := s.State.CloudImageMetadataStorage.SaveMetadata(metadata)
 	c.Assert(err, jc.ErrorIsNil)
@@ -727,7 +757,7 @@ func (s *MigrationExportSuite) TestCloudImageMetadatas(c *gc.C) {
 	c.Assert(images, gc.HasLen, 1)
 	image := images[0]
 	c.Check(image.Stream(), gc.Equals, "stream")
-	c.Check(image.Region(), gc.Equals, "region-test")
+	c.Check(image.Region(), gc.Equals, "dummy-region")
 	c.Check(image.Version(), gc.Equals, "14.04")
 	c.Check(image.Arch(), gc.Equals, "arch")
 	c.Check(image.VirtType(), gc.Equals, "virtType-test")
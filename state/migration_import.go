@@ -141,15 +141,18 @@ func (st *State) Import(model description.Model) (_ *Model, _ *State, err error)
 	if err := restore.modelExtras(); err != nil {
 		return nil, nil, errors.Annotate(err, "base model aspects")
 	}
+	// Image metadata extends the constraints vocabulary with the
+	// architectures it supports, so it must be in place before any
+	// constraints are validated.
+	if err := restore.cloudimagemetadata(); err != nil {
+		return nil, nil, errors.Annotate(err, "cloudimagemetadata")
+	}
 	if err := newSt.SetModelConstraints(restore.constraints(model.Constraints())); err != nil {
 		return nil, nil, errors.Annotate(err, "model constraints")
 	}
 	if err := restore.sshHostKeys(); err != nil {
 		return nil, nil, errors.Annotate(err, "sshHostKeys")
 	}
-	if err := restore.cloudimagemetadata(); err != nil {
-		return nil, nil, errors.Annotate(err, "cloudimagemetadata")
-	}
 	if err := restore.actions(); err != nil {
 		return nil, nil, errors.Annotate(err, "actions")
 	}
@@ -1259,15 +1262,21 @@ func (i *importer) cloudimagemetadata() error {
 	images := i.model.CloudImageMetadata()
 	metadatas := make([]cloudimagemetadata.Metadata, len(images))
 	for index, image := range images {
+		var rootStorageSize *uint64
+		if size, ok := image.RootStorageSize(); ok {
+			rootStorageSize = &size
+		}
 		metadatas[index] = cloudimagemetadata.Metadata{
 			cloudimagemetadata.MetadataAttributes{
 				Source:          image.Source(),
 				Stream:          image.Stream(),
+				Cloud:           i.dbModel.Cloud(),
 				Region:          image.Region(),
 				Version:         image.Version(),
 				Series:          image.Series(),
 				Arch:            image.Arch(),
 				RootStorageType: image.RootStorageType(),
+				RootStorageSize: rootStorageSize,
 				VirtType:        image.VirtType(),
 			},
 			image.Priority(),
@@ -829,7 +829,7 @@ func (s *MigrationImportSuite) TestCloudImageMetadata(c *gc.C) {
 	storageSize := uint64(3)
 	attrs := cloudimagemetadata.MetadataAttributes{
 		Stream:          "stream",
-		Region:          "region-test",
+		Region:          "dummy-region",
 		Version:         "14.04",
 		Series:          "trusty",
 		Arch:            "arch",
@@ -843,17 +843,27 @@ func (s *MigrationImportSuite) TestCloudImageMetadata(c *gc.C) {
 	err := s.State.CloudImageMetadataStorage.SaveMetadata(metadata)
 	c.Assert(err, jc.ErrorIsNil)
 
-	_, newSt := s.importModel(c)
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	// Image metadata is shared by the controller's models, so remove
+	// it to be sure that the import recreates it.
+	err = s.State.CloudImageMetadataStorage.DeleteMetadata("1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	uuid := utils.MustNewUUID().String()
+	_, newSt, err := s.State.Import(newModel(out, uuid, "new"))
+	c.Assert(err, jc.ErrorIsNil)
 	defer func() {
 		c.Assert(newSt.Close(), jc.ErrorIsNil)
 	}()
 
-	images, err := s.State.CloudImageMetadataStorage.AllCloudImageMetadata()
+	images, err := newSt.CloudImageMetadataStorage.AllCloudImageMetadata()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(images, gc.HasLen, 1)
 	image := images[0]
 	c.Check(image.Stream, gc.Equals, "stream")
-	c.Check(image.Region, gc.Equals, "region-test")
+	c.Check(image.Cloud, gc.Equals, "dummy")
+	c.Check(image.Region, gc.Equals, "dummy-region")
 	c.Check(image.Version, gc.Equals, "14.04")
 	c.Check(image.Arch, gc.Equals, "arch")
 	c.Check(image.VirtType, gc.Equals, "virtType-test")
@@ -865,6 +875,41 @@ func (s *MigrationImportSuite) TestCloudImageMetadata(c *gc.C) {
 	c.Check(image.DateCreated, gc.Equals, int64(2))
 }
 
+func (s *MigrationImportSuite) TestCloudImageMetadataConstraintsVocabulary(c *gc.C) {
+	// The s390x architecture is only supported by way of the image
+	// metadata, which must be imported before the model constraints
+	// are validated.
+	attrs := cloudimagemetadata.MetadataAttributes{
+		Stream:   "released",
+		Region:   "dummy-region",
+		Version:  "14.04",
+		Series:   "trusty",
+		Arch:     "s390x",
+		VirtType: "kvm",
+		Source:   "custom",
+	}
+	err := s.State.CloudImageMetadataStorage.SaveMetadata([]cloudimagemetadata.Metadata{{attrs, 2, "1", 2}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelConstraints(constraints.MustParse("arch=s390x"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CloudImageMetadataStorage.DeleteMetadata("1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	uuid := utils.MustNewUUID().String()
+	_, newSt, err := s.State.Import(newModel(out, uuid, "new"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		c.Assert(newSt.Close(), jc.ErrorIsNil)
+	}()
+
+	cons, err := newSt.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("arch=s390x"))
+}
+
 func (s *MigrationImportSuite) TestAction(c *gc.C) {
 	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
 		Constraints: constraints.MustParse("arch=amd64 mem=8G"),
@@ -179,6 +179,19 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Metrics manager maintains controller specific state relating to
 		// the store and forward of charm metrics. Nothing to migrate here.
 		metricsManagerC,
+
+		// Model config history is kept to help diagnose problems with
+		// the model on this controller; only the current model config
+		// is migrated.
+		modelConfigHistoryC,
+
+		// Quotas are set by the administrators of the controller
+		// hosting the model, and are not migrated.
+		modelQuotasC,
+
+		// Secrets are not yet part of the model description;
+		// models with secrets are refused by the migration prechecks.
+		secretsC,
 	)
 
 	// THIS SET WILL BE REMOVED WHEN MIGRATIONS ARE COMPLETE
@@ -288,6 +301,12 @@ func (s *MigrationSuite) TestMachineDocFields(c *gc.C) {
 		// Ignored at this stage, could be an issue if mongo 3.0 isn't
 		// available.
 		"StopMongoUntilVersion",
+		// DesiredAgentVersion only stages an upgrade within the
+		// source model.
+		"DesiredAgentVersion",
+		// Tags are not yet part of the model description;
+		// models with tags are refused by the migration prechecks.
+		"Tags",
 	)
 	migrated := set.NewStrings(
 		"Addresses",
@@ -349,6 +368,9 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// Tags are not yet part of the model description;
+		// models with tags are refused by the migration prechecks.
+		"Tags",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -474,6 +496,9 @@ func (s *MigrationSuite) TestAnnatatorDocFields(c *gc.C) {
 		"GlobalKey",
 		"Tag",
 		"Annotations",
+		// The model description has no place for operator
+		// metadata yet, so it is not migrated.
+		"Metadata",
 	)
 	s.AssertExportedFields(c, annotatorDoc{}, fields)
 }
@@ -525,6 +550,18 @@ func (s *MigrationSuite) TestConstraintsDocFields(c *gc.C) {
 		"Tags",
 		"Spaces",
 		"VirtType",
+		// These can't be represented in a model description yet;
+		// Export refuses models that use them.
+		"GPUs",
+		"GPUType",
+		"ImageID",
+		"AllocatePublicIP",
+		"Profile",
+		"MaxCpuCores",
+		"MaxMem",
+		"Spread",
+		"RootDiskSource",
+		"SpotPrice",
 	)
 	s.AssertExportedFields(c, constraintsDoc{}, fields)
 }
@@ -6,6 +6,7 @@ package state
 import (
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
@@ -254,11 +255,17 @@ func checkControllerInheritedConfig(attrs attrValues) error {
 	return nil
 }
 
+// buildAndValidateModelConfig applies the given changes to oldConfig
+// and validates the result. If validation fails, the unvalidated
+// config is returned along with the error.
 func (st *State) buildAndValidateModelConfig(updateAttrs attrValues, removeAttrs []string, oldConfig *config.Config) (*config.Config, error) {
 	newConfig, err := oldConfig.Apply(updateAttrs)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	for _, warning := range newConfig.DeprecationWarnings() {
+		logger.Warningf("model %q: %s", oldConfig.Name(), warning)
+	}
 	if len(removeAttrs) != 0 {
 		newConfig, err = newConfig.Remove(removeAttrs)
 		if err != nil {
@@ -266,9 +273,13 @@ func (st *State) buildAndValidateModelConfig(updateAttrs attrValues, removeAttrs
 		}
 	}
 	if err := checkModelConfig(newConfig); err != nil {
-		return nil, errors.Trace(err)
+		return newConfig, errors.Trace(err)
+	}
+	validCfg, err := st.validate(newConfig, oldConfig)
+	if err != nil {
+		return newConfig, errors.Trace(err)
 	}
-	return st.validate(newConfig, oldConfig)
+	return validCfg, nil
 }
 
 type ValidateConfigFunc func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error
@@ -277,10 +288,63 @@ type ValidateConfigFunc func(updateAttrs map[string]interface{}, removeAttrs []s
 // configuration of the model with the provided updateAttrs and
 // removeAttrs.
 func (st *State) UpdateModelConfig(updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ValidateConfigFunc) error {
+	return st.UpdateModelConfigAs(nil, updateAttrs, removeAttrs, additionalValidation)
+}
+
+// UpdateModelConfigAs behaves like UpdateModelConfig, and records the
+// entity with the given tag as the author of the change in the model
+// config history. The author may be nil if it is not known.
+func (st *State) UpdateModelConfigAs(author names.Tag, updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ValidateConfigFunc) error {
 	if len(updateAttrs)+len(removeAttrs) == 0 {
 		return nil
 	}
 
+	// TODO(axw) 2013-12-6 #1167616
+	// Ensure that the settings on disk have not changed
+	// underneath us. The settings changes are actually
+	// applied as a delta to what's on disk; if there has
+	// been a concurrent update, the change may not be what
+	// the user asked for.
+
+	modelSettings, err := readSettings(st, settingsC, modelGlobalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	oldConfig, validCfg, err := st.ValidateModelConfigUpdate(updateAttrs, removeAttrs, additionalValidation)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	validAttrs := validCfg.AllAttrs()
+	for k := range oldConfig.AllAttrs() {
+		if _, ok := validAttrs[k]; !ok {
+			modelSettings.Delete(k)
+		}
+	}
+	// Some values require marshalling before storage.
+	validAttrs = config.CoerceForStorage(validAttrs)
+
+	modelSettings.Update(validAttrs)
+	changes, ops := modelSettings.settingsUpdateOps()
+	if err := modelSettings.write(ops); err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		recordModelConfigChanges(st, author, changes)
+	}
+	return nil
+}
+
+// ValidateModelConfigUpdate validates the change to the model's
+// configuration that UpdateModelConfig would make with the same
+// arguments, without applying it. It returns the current config and
+// the config that would result from the change.
+//
+// If the change can be applied but the resulting config fails
+// validation, the unvalidated config is returned along with the
+// error, so that callers can report what the change would have done.
+func (st *State) ValidateModelConfigUpdate(updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ValidateConfigFunc) (oldConfig, newConfig *config.Config, err error) {
 	if len(removeAttrs) > 0 {
 		var removed []string
 		if updateAttrs == nil {
@@ -290,7 +354,7 @@ func (st *State) UpdateModelConfig(updateAttrs map[string]interface{}, removeAtt
 		// and if there's one, use that.
 		inherited, err := st.inheritedConfigAttributes()
 		if err != nil {
-			return errors.Trace(err)
+			return nil, nil, errors.Trace(err)
 		}
 		for _, attr := range removeAttrs {
 			// We we are updating an attribute, that takes
@@ -306,46 +370,23 @@ func (st *State) UpdateModelConfig(updateAttrs map[string]interface{}, removeAtt
 		}
 		removeAttrs = removed
 	}
-	// TODO(axw) 2013-12-6 #1167616
-	// Ensure that the settings on disk have not changed
-	// underneath us. The settings changes are actually
-	// applied as a delta to what's on disk; if there has
-	// been a concurrent update, the change may not be what
-	// the user asked for.
-
-	modelSettings, err := readSettings(st, settingsC, modelGlobalKey)
-	if err != nil {
-		return errors.Trace(err)
-	}
 
 	// Get the existing model config from state.
-	oldConfig, err := st.ModelConfig()
+	oldConfig, err = st.ModelConfig()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	if additionalValidation != nil {
 		err = additionalValidation(updateAttrs, removeAttrs, oldConfig)
 		if err != nil {
-			return errors.Trace(err)
+			return oldConfig, nil, errors.Trace(err)
 		}
 	}
-	validCfg, err := st.buildAndValidateModelConfig(updateAttrs, removeAttrs, oldConfig)
+	newConfig, err = st.buildAndValidateModelConfig(updateAttrs, removeAttrs, oldConfig)
 	if err != nil {
-		return errors.Trace(err)
+		return oldConfig, newConfig, errors.Trace(err)
 	}
-
-	validAttrs := validCfg.AllAttrs()
-	for k := range oldConfig.AllAttrs() {
-		if _, ok := validAttrs[k]; !ok {
-			modelSettings.Delete(k)
-		}
-	}
-	// Some values require marshalling before storage.
-	validAttrs = config.CoerceForStorage(validAttrs)
-
-	modelSettings.Update(validAttrs)
-	_, ops := modelSettings.settingsUpdateOps()
-	return modelSettings.write(ops)
+	return oldConfig, newConfig, nil
 }
 
 type modelConfigSourceFunc func() (attrValues, error)
@@ -5,6 +5,7 @@ package state_test
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -106,6 +107,19 @@ func (s *ModelConfigSuite) TestModelConfig(c *gc.C) {
 	c.Assert(oldCfg, jc.DeepEquals, cfg)
 }
 
+func (s *ModelConfigSuite) TestUpdateModelConfigDeprecated(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"tools-stream": "proposed",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// The model's config always has agent-stream set, so the
+	// deprecated attribute is ignored.
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AgentStream(), gc.Equals, "released")
+	c.Assert(c.GetTestLog(), jc.Contains, `config attribute "tools-stream" is deprecated and ignored because "agent-stream" is also set`)
+}
+
 func (s *ModelConfigSuite) TestComposeNewModelConfig(c *gc.C) {
 	attrs := map[string]interface{}{
 		"authorized-keys": "different-keys",
@@ -210,6 +224,66 @@ func (s *ModelConfigSuite) TestUpdateModelConfigRemoveInherited(c *gc.C) {
 	c.Assert(ok, jc.IsFalse)
 }
 
+func (s *ModelConfigSuite) TestModelConfigHistory(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{"arbitrary-key": "shazam!"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(time.Minute)
+	author := names.NewUserTag("bob")
+	err = s.State.UpdateModelConfigAs(author, map[string]interface{}{
+		"apt-mirror": "http://different-mirror",
+	}, []string{"arbitrary-key"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.State.ModelConfigHistory(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Author, gc.Equals, "user-bob")
+	c.Assert(history[0].Changes, jc.DeepEquals, []state.ItemChange{{
+		Type:     state.ItemModified,
+		Key:      "apt-mirror",
+		OldValue: "http://cloud-mirror",
+		NewValue: "http://different-mirror",
+	}, {
+		Type:     state.ItemDeleted,
+		Key:      "arbitrary-key",
+		OldValue: "shazam!",
+	}})
+	c.Assert(history[1].Author, gc.Equals, "")
+	c.Assert(history[1].Changes, jc.DeepEquals, []state.ItemChange{{
+		Type:     state.ItemAdded,
+		Key:      "arbitrary-key",
+		NewValue: "shazam!",
+	}})
+	c.Assert(history[0].Time.Sub(history[1].Time), gc.Equals, time.Minute)
+}
+
+func (s *ModelConfigSuite) TestModelConfigHistoryUnchanged(c *gc.C) {
+	before, err := s.State.ModelConfigHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateModelConfig(map[string]interface{}{"name": cfg.Name()}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	after, err := s.State.ModelConfigHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(after, gc.HasLen, len(before))
+}
+
+func (s *ModelConfigSuite) TestPruneModelConfigHistory(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{"arbitrary-key": "shazam!"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	removed, err := s.State.PruneModelConfigHistory(s.Clock.Now().Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.Equals, 0)
+
+	_, err = s.State.PruneModelConfigHistory(s.Clock.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	history, err := s.State.ModelConfigHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
+
 func (s *ModelConfigSuite) TestUpdateModelConfigCoerce(c *gc.C) {
 	attrs := map[string]interface{}{
 		"resource-tags": map[string]string{"a": "b", "c": "d"},
@@ -0,0 +1,114 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ModelConfigHistoryEntry records a change made to a model's config.
+type ModelConfigHistoryEntry struct {
+	// Time is when the change was made.
+	Time time.Time
+
+	// Author is the tag of the entity that made the change, or
+	// empty if it is not known.
+	Author string
+
+	// Changes holds the attributes that were added, changed or
+	// removed, sorted by key.
+	Changes []ItemChange
+}
+
+type modelConfigHistoryDoc struct {
+	ModelUUID string                 `bson:"model-uuid"`
+	Time      time.Time              `bson:"time"`
+	Author    string                 `bson:"author,omitempty"`
+	Changes   []modelConfigChangeDoc `bson:"changes"`
+}
+
+type modelConfigChangeDoc struct {
+	Type     int         `bson:"type"`
+	Key      string      `bson:"key"`
+	OldValue interface{} `bson:"old-value,omitempty"`
+	NewValue interface{} `bson:"new-value,omitempty"`
+}
+
+// recordModelConfigChanges adds the given changes to the model config
+// history. The history is not needed for the model to work, so a
+// failure to record it is logged rather than returned.
+func recordModelConfigChanges(st *State, author names.Tag, changes []ItemChange) {
+	doc := &modelConfigHistoryDoc{
+		Time: st.clock.Now().UTC(),
+	}
+	if author != nil {
+		doc.Author = author.String()
+	}
+	for _, change := range changes {
+		doc.Changes = append(doc.Changes, modelConfigChangeDoc{
+			Type:     change.Type,
+			Key:      change.Key,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+	history, closer := st.getCollection(modelConfigHistoryC)
+	defer closer()
+	if err := history.Writeable().Insert(doc); err != nil {
+		logger.Errorf("failed to write model config history: %v", err)
+	}
+}
+
+// ModelConfigHistory returns the changes made to the model's config,
+// most recent first. If limit is positive, no more than that many
+// changes are returned.
+func (st *State) ModelConfigHistory(limit int) ([]ModelConfigHistoryEntry, error) {
+	history, closer := st.getCollection(modelConfigHistoryC)
+	defer closer()
+
+	query := history.Find(nil).Sort("-time")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var docs []modelConfigHistoryDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read model config history")
+	}
+	entries := make([]ModelConfigHistoryEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = ModelConfigHistoryEntry{
+			Time:   doc.Time.UTC(),
+			Author: doc.Author,
+		}
+		for _, change := range doc.Changes {
+			entries[i].Changes = append(entries[i].Changes, ItemChange{
+				Type:     change.Type,
+				Key:      change.Key,
+				OldValue: change.OldValue,
+				NewValue: change.NewValue,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// PruneModelConfigHistory removes the model config changes, for all
+// models, that were made before minTime, and returns the number of
+// changes removed.
+func (st *State) PruneModelConfigHistory(minTime time.Time) (int, error) {
+	history, closer := st.getRawCollection(modelConfigHistoryC)
+	defer closer()
+
+	info, err := history.RemoveAll(bson.M{
+		"time": bson.M{"$lt": minTime},
+	})
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot prune model config history")
+	}
+	return info.Removed, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/mongo"
+)
+
+// observedCollection wraps a mongo.WriteCollection, reporting the time
+// taken by each query made through it. Queries that return iterators
+// are not timed, since their cost is paid as the results are read.
+type observedCollection struct {
+	mongo.WriteCollection
+	observe func(time.Duration)
+}
+
+// Writeable is part of the Collection interface.
+func (c *observedCollection) Writeable() mongo.WriteCollection {
+	// As for modelStateCollection, we can't delegate this to the
+	// embedded WriteCollection without losing the observer.
+	return c
+}
+
+// Count is part of the Collection interface.
+func (c *observedCollection) Count() (int, error) {
+	defer c.timer()()
+	return c.WriteCollection.Count()
+}
+
+// Find is part of the Collection interface.
+func (c *observedCollection) Find(query interface{}) mongo.Query {
+	return &observedQuery{c.WriteCollection.Find(query), c}
+}
+
+// FindId is part of the Collection interface.
+func (c *observedCollection) FindId(id interface{}) mongo.Query {
+	return &observedQuery{c.WriteCollection.FindId(id), c}
+}
+
+// timer returns a func that reports the time elapsed since timer was
+// called.
+func (c *observedCollection) timer() func() {
+	start := time.Now()
+	return func() {
+		c.observe(time.Since(start))
+	}
+}
+
+// observedQuery wraps a mongo.Query, reporting the time taken to fetch
+// its results to the collection's observer.
+type observedQuery struct {
+	mongo.Query
+	coll *observedCollection
+}
+
+func (q *observedQuery) wrap(query mongo.Query) mongo.Query {
+	return &observedQuery{query, q.coll}
+}
+
+func (q *observedQuery) All(result interface{}) error {
+	defer q.coll.timer()()
+	return q.Query.All(result)
+}
+
+func (q *observedQuery) Apply(change mgo.Change, result interface{}) (*mgo.ChangeInfo, error) {
+	defer q.coll.timer()()
+	return q.Query.Apply(change, result)
+}
+
+func (q *observedQuery) Count() (int, error) {
+	defer q.coll.timer()()
+	return q.Query.Count()
+}
+
+func (q *observedQuery) Distinct(key string, result interface{}) error {
+	defer q.coll.timer()()
+	return q.Query.Distinct(key, result)
+}
+
+func (q *observedQuery) One(result interface{}) error {
+	defer q.coll.timer()()
+	return q.Query.One(result)
+}
+
+func (q *observedQuery) Batch(n int) mongo.Query {
+	return q.wrap(q.Query.Batch(n))
+}
+
+func (q *observedQuery) Comment(comment string) mongo.Query {
+	return q.wrap(q.Query.Comment(comment))
+}
+
+func (q *observedQuery) Hint(indexKey ...string) mongo.Query {
+	return q.wrap(q.Query.Hint(indexKey...))
+}
+
+func (q *observedQuery) Limit(n int) mongo.Query {
+	return q.wrap(q.Query.Limit(n))
+}
+
+func (q *observedQuery) LogReplay() mongo.Query {
+	return q.wrap(q.Query.LogReplay())
+}
+
+func (q *observedQuery) Prefetch(p float64) mongo.Query {
+	return q.wrap(q.Query.Prefetch(p))
+}
+
+func (q *observedQuery) Select(selector interface{}) mongo.Query {
+	return q.wrap(q.Query.Select(selector))
+}
+
+func (q *observedQuery) SetMaxScan(n int) mongo.Query {
+	return q.wrap(q.Query.SetMaxScan(n))
+}
+
+func (q *observedQuery) SetMaxTime(d time.Duration) mongo.Query {
+	return q.wrap(q.Query.SetMaxTime(d))
+}
+
+func (q *observedQuery) Skip(n int) mongo.Query {
+	return q.wrap(q.Query.Skip(n))
+}
+
+func (q *observedQuery) Snapshot() mongo.Query {
+	return q.wrap(q.Query.Snapshot())
+}
+
+func (q *observedQuery) Sort(fields ...string) mongo.Query {
+	return q.wrap(q.Query.Sort(fields...))
+}
@@ -57,6 +57,11 @@ type OpenParams struct {
 	// be called after mgo/txn transactions are run, successfully
 	// or not.
 	RunTransactionObserver RunTransactionObserverFunc
+
+	// QueryObserver, if non-nil, is a function that will be called
+	// after each query made by the State, and by any State derived
+	// from it, with the time the query took.
+	QueryObserver QueryObserverFunc
 }
 
 // Validate validates the OpenParams.
@@ -92,6 +97,7 @@ func Open(args OpenParams) (*State, error) {
 		args.NewPolicy,
 		args.Clock,
 		args.RunTransactionObserver,
+		args.QueryObserver,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -117,6 +123,7 @@ func open(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	queryObserver QueryObserverFunc,
 ) (*State, error) {
 	logger.Infof("opening state, mongo addresses: %q; entity %v", info.Addrs, info.Tag)
 	logger.Debugf("dialing mongo")
@@ -133,7 +140,7 @@ func open(
 	}
 	logger.Debugf("mongodb login successful")
 
-	st, err := newState(controllerModelTag, controllerModelTag, session, info, newPolicy, clock, runTransactionObserver)
+	st, err := newState(controllerModelTag, controllerModelTag, session, info, newPolicy, clock, runTransactionObserver, queryObserver)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -251,7 +258,7 @@ func Initialize(args InitializeParams) (_ *State, err error) {
 	// When creating the controller model, the new model
 	// UUID is also used as the controller UUID.
 	modelTag := names.NewModelTag(args.ControllerModelArgs.Config.UUID())
-	st, err := open(modelTag, args.MongoInfo, args.MongoDialOpts, args.NewPolicy, args.Clock, nil)
+	st, err := open(modelTag, args.MongoInfo, args.MongoDialOpts, args.NewPolicy, args.Clock, nil, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -346,6 +353,9 @@ func Initialize(args InitializeParams) (_ *State, err error) {
 	if err := st.runTransaction(ops); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if _, err := st.MigrateSchema(MigrateSchemaParams{}); err != nil {
+		return nil, errors.Trace(err)
+	}
 	controllerTag := names.NewControllerTag(args.ControllerConfig.ControllerUUID())
 	if err := st.start(controllerTag); err != nil {
 		return nil, errors.Trace(err)
@@ -516,6 +526,7 @@ func newState(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	queryObserver QueryObserverFunc,
 ) (_ *State, err error) {
 
 	defer func() {
@@ -530,13 +541,11 @@ func newState(
 		rawDB,
 		modelTag.Id(),
 		runTransactionObserver,
+		queryObserver,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	if err := InitDbLogs(session); err != nil {
-		return nil, errors.Trace(err)
-	}
 
 	// Create State.
 	st := &State{
@@ -548,6 +557,7 @@ func newState(
 		database:               database,
 		newPolicy:              newPolicy,
 		runTransactionObserver: runTransactionObserver,
+		queryObserver:          queryObserver,
 	}
 	if newPolicy != nil {
 		st.policy = newPolicy(st)
@@ -0,0 +1,226 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// maxOperatorMetadataLength is the longest owner or cost centre that
+// may be recorded.
+const maxOperatorMetadataLength = 64
+
+var (
+	validOperatorMetadataOwner      = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.+_@-]*$`)
+	validOperatorMetadataCostCenter = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+)
+
+// OperatorMetadata holds typed metadata that operators record against
+// an annotatable entity, alongside its free-form annotations.
+type OperatorMetadata struct {
+	// Owner identifies who is responsible for the entity, such as
+	// a user name or an email address.
+	Owner string
+
+	// CostCenter identifies the budget the entity is charged to.
+	CostCenter string
+
+	// Expiry is the time after which the entity is no longer
+	// needed. It is zero if the entity does not expire.
+	Expiry time.Time
+}
+
+// IsEmpty reports whether no metadata is set.
+func (m OperatorMetadata) IsEmpty() bool {
+	return m.Owner == "" && m.CostCenter == "" && m.Expiry.IsZero()
+}
+
+// Validate returns an error satisfying errors.IsNotValid if the
+// metadata cannot be recorded.
+func (m OperatorMetadata) Validate() error {
+	if m.Owner != "" {
+		if len(m.Owner) > maxOperatorMetadataLength || !validOperatorMetadataOwner.MatchString(m.Owner) {
+			return errors.NotValidf("owner %q", m.Owner)
+		}
+	}
+	if m.CostCenter != "" {
+		if len(m.CostCenter) > maxOperatorMetadataLength || !validOperatorMetadataCostCenter.MatchString(m.CostCenter) {
+			return errors.NotValidf("cost center %q", m.CostCenter)
+		}
+	}
+	return nil
+}
+
+// operatorMetadataDoc records OperatorMetadata in the annotations
+// document of an entity.
+type operatorMetadataDoc struct {
+	Owner      string    `bson:"owner,omitempty"`
+	CostCenter string    `bson:"cost-center,omitempty"`
+	Expiry     time.Time `bson:"expiry,omitempty"`
+}
+
+func newOperatorMetadataDoc(m OperatorMetadata) *operatorMetadataDoc {
+	doc := &operatorMetadataDoc{
+		Owner:      m.Owner,
+		CostCenter: m.CostCenter,
+	}
+	if !m.Expiry.IsZero() {
+		// Mongo only stores times to the millisecond; keep
+		// whole seconds so that the stored value is what was set.
+		doc.Expiry = m.Expiry.UTC().Truncate(time.Second)
+	}
+	return doc
+}
+
+func (doc *operatorMetadataDoc) metadata() OperatorMetadata {
+	if doc == nil {
+		return OperatorMetadata{}
+	}
+	m := OperatorMetadata{
+		Owner:      doc.Owner,
+		CostCenter: doc.CostCenter,
+	}
+	if !doc.Expiry.IsZero() {
+		m.Expiry = doc.Expiry.UTC()
+	}
+	return m
+}
+
+// OperatorMetadataFilter selects entities by their operator metadata.
+// Fields left empty match every entity.
+type OperatorMetadataFilter struct {
+	// Kind restricts the entities to those whose tags are of the
+	// given kind, such as names.MachineTagKind.
+	Kind string
+
+	// Owner restricts the entities to those with the given owner.
+	Owner string
+
+	// CostCenter restricts the entities to those charged to the
+	// given cost center.
+	CostCenter string
+
+	// ExpiresBefore restricts the entities to those with an expiry
+	// earlier than the given time.
+	ExpiresBefore time.Time
+}
+
+// Match reports whether the entity with the given tag and metadata is
+// selected by the filter.
+func (f OperatorMetadataFilter) Match(tag names.Tag, m OperatorMetadata) bool {
+	if f.Kind != "" && tag.Kind() != f.Kind {
+		return false
+	}
+	if f.Owner != "" && m.Owner != f.Owner {
+		return false
+	}
+	if f.CostCenter != "" && m.CostCenter != f.CostCenter {
+		return false
+	}
+	if !f.ExpiresBefore.IsZero() && (m.Expiry.IsZero() || !m.Expiry.Before(f.ExpiresBefore)) {
+		return false
+	}
+	return true
+}
+
+// EntityOperatorMetadata holds the operator metadata of an entity.
+type EntityOperatorMetadata struct {
+	Tag      names.Tag
+	Metadata OperatorMetadata
+}
+
+// SetOperatorMetadata replaces the operator metadata of the entity.
+// Setting empty metadata removes it.
+func (st *State) SetOperatorMetadata(entity GlobalEntity, metadata OperatorMetadata) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set operator metadata on %s", entity.Tag())
+	if err := metadata.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	var update bson.D
+	if metadata.IsEmpty() {
+		update = bson.D{{"$unset", bson.D{{"metadata", nil}}}}
+	} else {
+		update = bson.D{{"$set", bson.D{{"metadata", newOperatorMetadataDoc(metadata)}}}}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		annotations, closer := st.getCollection(annotationsC)
+		defer closer()
+		if count, err := annotations.FindId(entity.globalKey()).Count(); err != nil {
+			return nil, errors.Trace(err)
+		} else if count == 0 {
+			if metadata.IsEmpty() {
+				return nil, jujutxn.ErrNoOperations
+			}
+			// Check that the annotator entity was not previously destroyed.
+			if attempt != 0 {
+				return nil, errors.Errorf("%s no longer exists", entity.Tag())
+			}
+			return insertAnnotationsOps(st, entity, nil, newOperatorMetadataDoc(metadata))
+		}
+		return []txn.Op{{
+			C:      annotationsC,
+			Id:     st.docID(entity.globalKey()),
+			Assert: txn.DocExists,
+			Update: update,
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// OperatorMetadata returns the operator metadata of the entity. The
+// metadata is empty if none has been set.
+func (st *State) OperatorMetadata(entity GlobalEntity) (OperatorMetadata, error) {
+	annotations, closer := st.getCollection(annotationsC)
+	defer closer()
+	var doc annotatorDoc
+	err := annotations.FindId(entity.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return OperatorMetadata{}, nil
+	} else if err != nil {
+		return OperatorMetadata{}, errors.Annotatef(err, "cannot get operator metadata for %s", entity.Tag())
+	}
+	return doc.Metadata.metadata(), nil
+}
+
+// FindOperatorMetadata returns the operator metadata of every entity
+// in the model selected by the filter, ordered by tag.
+func (st *State) FindOperatorMetadata(filter OperatorMetadataFilter) ([]EntityOperatorMetadata, error) {
+	annotations, closer := st.getCollection(annotationsC)
+	defer closer()
+	var docs []annotatorDoc
+	err := annotations.Find(bson.D{{"metadata", bson.D{{"$exists", true}}}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot find operator metadata")
+	}
+	var result []EntityOperatorMetadata
+	for _, doc := range docs {
+		tag, err := names.ParseTag(doc.Tag)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid tag for %q", doc.GlobalKey)
+		}
+		metadata := doc.Metadata.metadata()
+		if filter.Match(tag, metadata) {
+			result = append(result, EntityOperatorMetadata{tag, metadata})
+		}
+	}
+	sort.Sort(byOperatorMetadataTag(result))
+	return result, nil
+}
+
+type byOperatorMetadataTag []EntityOperatorMetadata
+
+func (b byOperatorMetadataTag) Len() int      { return len(b) }
+func (b byOperatorMetadataTag) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byOperatorMetadataTag) Less(i, j int) bool {
+	return b[i].Tag.String() < b[j].Tag.String()
+}
@@ -0,0 +1,169 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+type OperatorMetadataSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&OperatorMetadataSuite{})
+
+func (s *OperatorMetadataSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OperatorMetadataSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		metadata state.OperatorMetadata
+		err      string
+	}{{
+		metadata: state.OperatorMetadata{},
+	}, {
+		metadata: state.OperatorMetadata{Owner: "bob@example.com", CostCenter: "ops-42"},
+	}, {
+		metadata: state.OperatorMetadata{Owner: "bob smith"},
+		err:      `owner "bob smith" not valid`,
+	}, {
+		metadata: state.OperatorMetadata{Owner: strings.Repeat("a", 65)},
+		err:      `owner "a+" not valid`,
+	}, {
+		metadata: state.OperatorMetadata{CostCenter: "-ops"},
+		err:      `cost center "-ops" not valid`,
+	}, {
+		metadata: state.OperatorMetadata{CostCenter: "ops@42"},
+		err:      `cost center "ops@42" not valid`,
+	}} {
+		c.Logf("test %d: %+v", i, test.metadata)
+		err := test.metadata.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *OperatorMetadataSuite) TestSetOperatorMetadata(c *gc.C) {
+	metadata, err := s.State.OperatorMetadata(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.IsEmpty(), jc.IsTrue)
+
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 6, time.UTC)
+	err = s.State.SetOperatorMetadata(s.machine, state.OperatorMetadata{
+		Owner:      "bob",
+		CostCenter: "ops",
+		Expiry:     expiry,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata, err = s.State.OperatorMetadata(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.DeepEquals, state.OperatorMetadata{
+		Owner:      "bob",
+		CostCenter: "ops",
+		Expiry:     expiry.Truncate(time.Second),
+	})
+
+	// The metadata is kept apart from the free-form annotations.
+	annotations, err := s.State.Annotations(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 0)
+}
+
+func (s *OperatorMetadataSuite) TestSetOperatorMetadataKeepsAnnotations(c *gc.C) {
+	err := s.State.SetAnnotations(s.machine, map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetOperatorMetadata(s.machine, state.OperatorMetadata{Owner: "bob"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	annotations, err := s.State.Annotations(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, jc.DeepEquals, map[string]string{"foo": "bar"})
+	metadata, err := s.State.OperatorMetadata(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.DeepEquals, state.OperatorMetadata{Owner: "bob"})
+}
+
+func (s *OperatorMetadataSuite) TestSetOperatorMetadataEmptyRemoves(c *gc.C) {
+	err := s.State.SetOperatorMetadata(s.machine, state.OperatorMetadata{Owner: "bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetOperatorMetadata(s.machine, state.OperatorMetadata{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata, err := s.State.OperatorMetadata(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.IsEmpty(), jc.IsTrue)
+	found, err := s.State.FindOperatorMetadata(state.OperatorMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 0)
+}
+
+func (s *OperatorMetadataSuite) TestSetOperatorMetadataInvalid(c *gc.C) {
+	err := s.State.SetOperatorMetadata(s.machine, state.OperatorMetadata{Owner: "bob smith"})
+	c.Assert(err, gc.ErrorMatches, `cannot set operator metadata on machine-0: owner "bob smith" not valid`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *OperatorMetadataSuite) TestFindOperatorMetadata(c *gc.C) {
+	soon := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	set := func(entity state.GlobalEntity, metadata state.OperatorMetadata) {
+		err := s.State.SetOperatorMetadata(entity, metadata)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	set(s.machine, state.OperatorMetadata{Owner: "bob", CostCenter: "ops", Expiry: soon})
+	set(other, state.OperatorMetadata{Owner: "mary", CostCenter: "ops", Expiry: later})
+	set(application, state.OperatorMetadata{Owner: "bob"})
+
+	for i, test := range []struct {
+		filter   state.OperatorMetadataFilter
+		expected []names.Tag
+	}{{
+		filter:   state.OperatorMetadataFilter{},
+		expected: []names.Tag{application.Tag(), s.machine.Tag(), other.Tag()},
+	}, {
+		filter:   state.OperatorMetadataFilter{Kind: names.MachineTagKind},
+		expected: []names.Tag{s.machine.Tag(), other.Tag()},
+	}, {
+		filter:   state.OperatorMetadataFilter{Owner: "bob"},
+		expected: []names.Tag{application.Tag(), s.machine.Tag()},
+	}, {
+		filter:   state.OperatorMetadataFilter{CostCenter: "ops", Owner: "mary"},
+		expected: []names.Tag{other.Tag()},
+	}, {
+		filter:   state.OperatorMetadataFilter{ExpiresBefore: later},
+		expected: []names.Tag{s.machine.Tag()},
+	}} {
+		c.Logf("test %d: %+v", i, test.filter)
+		found, err := s.State.FindOperatorMetadata(test.filter)
+		c.Assert(err, jc.ErrorIsNil)
+		var tags []names.Tag
+		for _, f := range found {
+			tags = append(tags, f.Tag)
+		}
+		c.Check(tags, jc.DeepEquals, test.expected)
+	}
+}
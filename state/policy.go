@@ -61,6 +61,15 @@ type Prechecker interface {
 	PrecheckInstance(series string, cons constraints.Value, placement string) error
 }
 
+// SeriesPrechecker may be implemented by a Prechecker that can check,
+// before any instances are requested, whether the model can host
+// machines running a given series.
+type SeriesPrechecker interface {
+	// PrecheckSeries returns an error if no instance running the
+	// given series could be created in this model.
+	PrecheckSeries(series string) error
+}
+
 // precheckInstance calls the state's assigned policy, if non-nil, to obtain
 // a Prechecker, and calls PrecheckInstance if a non-nil Prechecker is returned.
 func (st *State) precheckInstance(series string, cons constraints.Value, placement string) error {
@@ -79,6 +88,25 @@ func (st *State) precheckInstance(series string, cons constraints.Value, placeme
 	return prechecker.PrecheckInstance(series, cons, placement)
 }
 
+// precheckSeries calls the state's assigned policy, if non-nil, to obtain
+// a Prechecker, and calls PrecheckSeries if the Prechecker implements
+// SeriesPrechecker.
+func (st *State) precheckSeries(series string) error {
+	if st.policy == nil {
+		return nil
+	}
+	prechecker, err := st.policy.Prechecker()
+	if errors.IsNotImplemented(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if checker, ok := prechecker.(SeriesPrechecker); ok {
+		return checker.PrecheckSeries(series)
+	}
+	return nil
+}
+
 func (st *State) constraintsValidator() (constraints.Validator, error) {
 	// Default behaviour is to simply use a standard validator with
 	// no model specific behaviour built in.
@@ -111,6 +139,7 @@ func (st *State) constraintsValidator() (constraints.Validator, error) {
 		arches, err := st.CloudImageMetadataStorage.SupportedArchitectures(
 			cloudimagemetadata.MetadataFilter{
 				Stream: cfg.AgentStream(),
+				Cloud:  model.Cloud(),
 				Region: region,
 			},
 		)
@@ -135,19 +164,69 @@ func (st *State) resolveConstraints(cons constraints.Value) (constraints.Value,
 	if err != nil {
 		return constraints.Value{}, err
 	}
+	profiles, err := st.constraintProfiles()
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	envCons, err = constraints.ExpandProfile(validator, profiles, envCons)
+	if err != nil {
+		return constraints.Value{}, errors.Annotate(err, "expanding model constraints")
+	}
+	cons, err = constraints.ExpandProfile(validator, profiles, cons)
+	if err != nil {
+		return constraints.Value{}, err
+	}
 	return validator.Merge(envCons, cons)
 }
 
 // validateConstraints returns an error if the given constraints are not valid for the
-// current model, and also any unsupported attributes.
+// current model, and also any unsupported attributes. Any constraint profile named
+// by the constraints is expanded before validation.
 func (st *State) validateConstraints(cons constraints.Value) ([]string, error) {
 	validator, err := st.constraintsValidator()
 	if err != nil {
 		return nil, err
 	}
+	profiles, err := st.constraintProfiles()
+	if err != nil {
+		return nil, err
+	}
+	cons, err = constraints.ExpandProfile(validator, profiles, cons)
+	if err != nil {
+		return nil, err
+	}
 	return validator.Validate(cons)
 }
 
+// expandConstraintProfile returns cons with any constraint profile it
+// names replaced by the attributes of that profile. Constraints are
+// expanded before they are stored, so that later changes to, or removal
+// of, a profile in model config do not affect constraints already set.
+func (st *State) expandConstraintProfile(cons constraints.Value) (constraints.Value, error) {
+	if cons.Profile == nil {
+		return cons, nil
+	}
+	validator, err := st.constraintsValidator()
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	profiles, err := st.constraintProfiles()
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	return constraints.ExpandProfile(validator, profiles, cons)
+}
+
+// constraintProfiles returns the constraint profiles defined in the
+// model config, keyed by name.
+func (st *State) constraintProfiles() (map[string]constraints.Value, error) {
+	cfg, err := st.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg.ConstraintProfiles(), nil
+}
+
 // validate calls the state's assigned policy, if non-nil, to obtain
 // a config.Validator, and calls Validate if a non-nil config.Validator is
 // returned.
@@ -142,3 +142,32 @@ func (s *PrecheckerSuite) TestPrecheckContainerNewMachine(c *gc.C) {
 	c.Assert(s.prechecker.precheckInstanceSeries, gc.Equals, template.Series)
 	c.Assert(s.prechecker.precheckInstancePlacement, gc.Equals, template.Placement)
 }
+
+type mockSeriesPrechecker struct {
+	mockPrechecker
+	precheckSeriesError  error
+	precheckSeriesSeries string
+}
+
+func (p *mockSeriesPrechecker) PrecheckSeries(series string) error {
+	p.precheckSeriesSeries = series
+	return p.precheckSeriesError
+}
+
+func (s *PrecheckerSuite) TestPrecheckSeriesAddApplication(c *gc.C) {
+	prechecker := &mockSeriesPrechecker{
+		precheckSeriesError: errors.NotSupportedf("series quantal"),
+	}
+	s.policy.GetPrechecker = func() (state.Prechecker, error) {
+		return prechecker, nil
+	}
+	ch := s.AddTestingCharm(c, "wordpress")
+	_, err := s.State.AddApplication(state.AddApplicationArgs{Name: "wordpress", Charm: ch})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "series quantal not supported")
+	c.Assert(prechecker.precheckSeriesSeries, gc.Equals, "quantal")
+
+	prechecker.precheckSeriesError = nil
+	_, err = s.State.AddApplication(state.AddApplicationArgs{Name: "wordpress", Charm: ch})
+	c.Assert(err, jc.ErrorIsNil)
+}
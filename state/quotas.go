@@ -0,0 +1,372 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// modelQuotasKey is the id of the document holding a model's quotas.
+const modelQuotasKey = "quotas"
+
+// modelQuotaUsageKey is the id of the document counting the additions
+// made to a model under its quotas.
+const modelQuotaUsageKey = "usage"
+
+// ModelQuotas holds the limits on the resources a model may use. A
+// zero limit means that the resource is not limited.
+type ModelQuotas struct {
+	// MaxMachines limits the number of top-level machines in the
+	// model. Containers are not counted.
+	MaxMachines int
+
+	// MaxUnits limits the number of principal units in the model.
+	MaxUnits int
+
+	// MaxStorageGB limits the total size, in gigabytes of 1024MiB,
+	// of the model's volumes and of its filesystems that are not
+	// backed by volumes.
+	MaxStorageGB uint64
+}
+
+// Validate returns an error if the quotas are not valid.
+func (q ModelQuotas) Validate() error {
+	if q.MaxMachines < 0 {
+		return errors.NotValidf("negative machine quota")
+	}
+	if q.MaxUnits < 0 {
+		return errors.NotValidf("negative unit quota")
+	}
+	return nil
+}
+
+// ModelQuotaUsage describes the use a model makes of the resources
+// limited by its quotas.
+type ModelQuotaUsage struct {
+	// Machines is the number of top-level machines in the model.
+	Machines int
+
+	// Units is the number of principal units in the model.
+	Units int
+
+	// StorageMiB is the total size of the model's volumes and of its
+	// filesystems that are not backed by volumes.
+	StorageMiB uint64
+}
+
+// modelQuotasDoc records a model's quotas.
+type modelQuotasDoc struct {
+	DocID        string `bson:"_id"`
+	ModelUUID    string `bson:"model-uuid"`
+	TxnRevno     int64  `bson:"txn-revno"`
+	MaxMachines  int    `bson:"max-machines"`
+	MaxUnits     int    `bson:"max-units"`
+	MaxStorageGB uint64 `bson:"max-storage-gb"`
+}
+
+// modelQuotaUsageDoc counts the additions made to a model under its
+// quotas. Every such addition increments the count, asserting that
+// it is unchanged since the model's usage was checked, so that
+// concurrent additions cannot together take the model over quota.
+type modelQuotaUsageDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	TxnRevno  int64  `bson:"txn-revno"`
+	Additions int64  `bson:"additions"`
+}
+
+func (doc *modelQuotasDoc) quotas() ModelQuotas {
+	return ModelQuotas{
+		MaxMachines:  doc.MaxMachines,
+		MaxUnits:     doc.MaxUnits,
+		MaxStorageGB: doc.MaxStorageGB,
+	}
+}
+
+func (st *State) modelQuotasDoc() (*modelQuotasDoc, error) {
+	coll, closer := st.getCollection(modelQuotasC)
+	defer closer()
+	var doc modelQuotasDoc
+	if err := coll.FindId(modelQuotasKey).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("quotas for model %s", st.ModelUUID())
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read model quotas")
+	}
+	return &doc, nil
+}
+
+// ModelQuotas returns the model's quotas.
+func (st *State) ModelQuotas() (ModelQuotas, error) {
+	doc, err := st.modelQuotasDoc()
+	if errors.IsNotFound(err) {
+		return ModelQuotas{}, nil
+	} else if err != nil {
+		return ModelQuotas{}, errors.Trace(err)
+	}
+	return doc.quotas(), nil
+}
+
+// SetModelQuotas replaces the model's quotas. Quotas lower than the
+// model's current usage prevent further additions, but do not remove
+// anything from the model.
+func (st *State) SetModelQuotas(quotas ModelQuotas) error {
+	if err := quotas.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		_, err := st.modelQuotasDoc()
+		if errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      modelQuotasC,
+				Id:     modelQuotasKey,
+				Assert: txn.DocMissing,
+				Insert: &modelQuotasDoc{
+					MaxMachines:  quotas.MaxMachines,
+					MaxUnits:     quotas.MaxUnits,
+					MaxStorageGB: quotas.MaxStorageGB,
+				},
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      modelQuotasC,
+			Id:     modelQuotasKey,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"max-machines", quotas.MaxMachines},
+				{"max-units", quotas.MaxUnits},
+				{"max-storage-gb", quotas.MaxStorageGB},
+			}}},
+		}}, nil
+	}
+	return errors.Annotate(st.run(buildTxn), "cannot set model quotas")
+}
+
+// ModelQuotaUsage returns the model's use of the resources limited by
+// its quotas.
+func (st *State) ModelQuotaUsage() (ModelQuotaUsage, error) {
+	var usage ModelQuotaUsage
+
+	machines, closer := st.getCollection(machinesC)
+	defer closer()
+	n, err := machines.Find(bson.D{{"$or", []bson.D{
+		{{"containertype", ""}},
+		{{"containertype", bson.D{{"$exists", false}}}},
+	}}}).Count()
+	if err != nil {
+		return ModelQuotaUsage{}, errors.Annotate(err, "cannot count machines")
+	}
+	usage.Machines = n
+
+	units, closer := st.getCollection(unitsC)
+	defer closer()
+	n, err = units.Find(bson.D{{"principal", ""}}).Count()
+	if err != nil {
+		return ModelQuotaUsage{}, errors.Annotate(err, "cannot count units")
+	}
+	usage.Units = n
+
+	volumes, closer := st.getCollection(volumesC)
+	defer closer()
+	var volume volumeDoc
+	iter := volumes.Find(nil).Iter()
+	for iter.Next(&volume) {
+		usage.StorageMiB += volumeDocSize(&volume)
+	}
+	if err := iter.Close(); err != nil {
+		return ModelQuotaUsage{}, errors.Annotate(err, "cannot read volumes")
+	}
+
+	filesystems, closer := st.getCollection(filesystemsC)
+	defer closer()
+	var filesystem filesystemDoc
+	iter = filesystems.Find(bson.D{{"volumeid", bson.D{{"$exists", false}}}}).Iter()
+	for iter.Next(&filesystem) {
+		usage.StorageMiB += filesystemDocSize(&filesystem)
+	}
+	if err := iter.Close(); err != nil {
+		return ModelQuotaUsage{}, errors.Annotate(err, "cannot read filesystems")
+	}
+	return usage, nil
+}
+
+func volumeDocSize(doc *volumeDoc) uint64 {
+	if doc.Info != nil {
+		return doc.Info.Size
+	}
+	if doc.Params != nil {
+		return doc.Params.Size
+	}
+	return 0
+}
+
+func filesystemDocSize(doc *filesystemDoc) uint64 {
+	if doc.Info != nil {
+		return doc.Info.Size
+	}
+	if doc.Params != nil {
+		return doc.Params.Size
+	}
+	return 0
+}
+
+// quotaUsageForOps returns the machines, principal units and storage
+// added to the model by the supplied operations.
+func quotaUsageForOps(ops []txn.Op) ModelQuotaUsage {
+	var usage ModelQuotaUsage
+	for _, op := range ops {
+		switch doc := op.Insert.(type) {
+		case *machineDoc:
+			if doc.ContainerType == "" {
+				usage.Machines++
+			}
+		case *unitDoc:
+			if doc.Principal == "" {
+				usage.Units++
+			}
+		case *volumeDoc:
+			usage.StorageMiB += volumeDocSize(doc)
+		case *filesystemDoc:
+			if doc.VolumeId == "" {
+				usage.StorageMiB += filesystemDocSize(doc)
+			}
+		}
+	}
+	return usage
+}
+
+// withModelQuotas returns a transaction source that adds to the
+// operations built by buildTxn the assertions that enforce the model's
+// quotas on the machines, units and storage they add. The source fails
+// with an error satisfying IsQuotaExceededError if the operations
+// would take the model over quota.
+//
+// Changes to the quotas, and concurrent additions under them, abort
+// the transaction so that the quotas are checked again.
+func withModelQuotas(st *State, buildTxn jujutxn.TransactionSource) jujutxn.TransactionSource {
+	return func(attempt int) ([]txn.Op, error) {
+		ops, err := buildTxn(attempt)
+		if err != nil {
+			return nil, err
+		}
+		quotaOps, _, err := st.modelQuotaOps(quotaUsageForOps(ops))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, quotaOps...), nil
+	}
+}
+
+// runWithModelQuotas runs the supplied operations, which do not depend
+// on the model's state, together with the assertions that enforce the
+// model's quotas. The transaction is retried only if the quotas or the
+// model's usage changed concurrently; any other abort is reported by
+// aborted.
+func (st *State) runWithModelQuotas(ops []txn.Op, aborted func() error) error {
+	var last quotaRevisions
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		quotaOps, revisions, err := st.modelQuotaOps(quotaUsageForOps(ops))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if attempt > 0 && revisions == last {
+			return nil, aborted()
+		}
+		last = revisions
+		all := make([]txn.Op, 0, len(ops)+len(quotaOps))
+		return append(append(all, ops...), quotaOps...), nil
+	}
+	return st.run(buildTxn)
+}
+
+// quotaRevisions holds the revisions of the quota and usage documents
+// asserted by modelQuotaOps, or -1 for those not asserted or missing.
+type quotaRevisions struct {
+	quotas int64
+	usage  int64
+}
+
+// modelQuotaOps returns the operations that assert that adding the
+// supplied usage to the model keeps it within its quotas, and the
+// revisions of the documents they assert.
+func (st *State) modelQuotaOps(added ModelQuotaUsage) ([]txn.Op, quotaRevisions, error) {
+	revisions := quotaRevisions{quotas: -1, usage: -1}
+	if added == (ModelQuotaUsage{}) {
+		return nil, revisions, nil
+	}
+	doc, err := st.modelQuotasDoc()
+	if errors.IsNotFound(err) {
+		return []txn.Op{{
+			C:      modelQuotasC,
+			Id:     modelQuotasKey,
+			Assert: txn.DocMissing,
+		}}, revisions, nil
+	} else if err != nil {
+		return nil, revisions, errors.Trace(err)
+	}
+	revisions.quotas = doc.TxnRevno
+	quotasOp := txn.Op{
+		C:      modelQuotasC,
+		Id:     modelQuotasKey,
+		Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+	}
+	quotas := doc.quotas()
+	limited := quotas.MaxMachines > 0 && added.Machines > 0 ||
+		quotas.MaxUnits > 0 && added.Units > 0 ||
+		quotas.MaxStorageGB > 0 && added.StorageMiB > 0
+	if !limited {
+		return []txn.Op{quotasOp}, revisions, nil
+	}
+	// The usage document is read before the usage is counted, so
+	// that any addition made while counting aborts the transaction.
+	usageOp, usageRevno, err := st.modelQuotaUsageOp()
+	if err != nil {
+		return nil, revisions, errors.Trace(err)
+	}
+	revisions.usage = usageRevno
+	usage, err := st.ModelQuotaUsage()
+	if err != nil {
+		return nil, revisions, errors.Trace(err)
+	}
+	if quotas.MaxMachines > 0 && added.Machines > 0 && usage.Machines+added.Machines > quotas.MaxMachines {
+		return nil, revisions, newQuotaExceededError("machine", uint64(quotas.MaxMachines), "")
+	}
+	if quotas.MaxUnits > 0 && added.Units > 0 && usage.Units+added.Units > quotas.MaxUnits {
+		return nil, revisions, newQuotaExceededError("unit", uint64(quotas.MaxUnits), "")
+	}
+	if quotas.MaxStorageGB > 0 && added.StorageMiB > 0 && usage.StorageMiB+added.StorageMiB > quotas.MaxStorageGB*1024 {
+		return nil, revisions, newQuotaExceededError("storage", quotas.MaxStorageGB, "GB")
+	}
+	return []txn.Op{quotasOp, usageOp}, revisions, nil
+}
+
+// modelQuotaUsageOp returns the operation that counts an addition to
+// the model under its quotas, asserting that there has been no other
+// since the usage document was read, and the document's revision.
+func (st *State) modelQuotaUsageOp() (txn.Op, int64, error) {
+	coll, closer := st.getCollection(modelQuotasC)
+	defer closer()
+	var doc modelQuotaUsageDoc
+	if err := coll.FindId(modelQuotaUsageKey).One(&doc); err == mgo.ErrNotFound {
+		return txn.Op{
+			C:      modelQuotasC,
+			Id:     modelQuotaUsageKey,
+			Assert: txn.DocMissing,
+			Insert: &modelQuotaUsageDoc{Additions: 1},
+		}, -1, nil
+	} else if err != nil {
+		return txn.Op{}, -1, errors.Annotate(err, "cannot read model quota usage")
+	}
+	return txn.Op{
+		C:      modelQuotasC,
+		Id:     modelQuotaUsageKey,
+		Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+		Update: bson.D{{"$inc", bson.D{{"additions", 1}}}},
+	}, doc.TxnRevno, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type QuotasSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&QuotasSuite{})
+
+func (s *QuotasSuite) TestModelQuotasDefault(c *gc.C) {
+	quotas, err := s.State.ModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, state.ModelQuotas{})
+}
+
+func (s *QuotasSuite) TestSetModelQuotas(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxMachines: 3, MaxUnits: 5})
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err := s.State.ModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, state.ModelQuotas{MaxMachines: 3, MaxUnits: 5})
+
+	err = s.State.SetModelQuotas(state.ModelQuotas{MaxStorageGB: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err = s.State.ModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.Equals, state.ModelQuotas{MaxStorageGB: 10})
+}
+
+func (s *QuotasSuite) TestSetModelQuotasInvalid(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxMachines: -1})
+	c.Assert(err, gc.ErrorMatches, "negative machine quota not valid")
+}
+
+func (s *QuotasSuite) TestMachineQuota(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxMachines: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: machine quota of 1 exceeded")
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+
+	// Containers are not counted.
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *QuotasSuite) TestMachineQuotaIgnoresContainers(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxMachines: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.ModelQuotaUsage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.Machines, gc.Equals, 1)
+
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: machine quota of 2 exceeded")
+}
+
+func (s *QuotasSuite) TestUnitQuota(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxUnits: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = application.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = application.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "wordpress": unit quota of 1 exceeded`)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotasSuite) TestUnitQuotaConcurrentAddition(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxUnits: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = application.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := application.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = application.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "wordpress": unit quota of 2 exceeded`)
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotasSuite) TestAddUnitApplicationDying(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxUnits: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = application.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// An abort unrelated to the quotas is not retried.
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := application.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = application.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "wordpress": application is not alive`)
+}
+
+func (s *QuotasSuite) TestStorageQuota(c *gc.C) {
+	err := s.State.SetModelQuotas(state.ModelQuotas{MaxStorageGB: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.MachineVolumeParams{{
+			Volume: state.VolumeParams{Pool: "loop-pool", Size: 1024},
+		}},
+	}
+	_, err = s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddOneMachine(template)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: storage quota of 1GB exceeded")
+	c.Assert(err, jc.Satisfies, state.IsQuotaExceededError)
+}
+
+func (s *QuotasSuite) TestModelQuotaUsage(c *gc.C) {
+	_, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.MachineVolumeParams{{
+			Volume: state.VolumeParams{Pool: "loop-pool", Size: 2048},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = application.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.ModelQuotaUsage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, gc.Equals, state.ModelQuotaUsage{
+		Machines:   1,
+		Units:      1,
+		StorageMiB: 2048,
+	})
+}
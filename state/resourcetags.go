@@ -0,0 +1,106 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/environs/tags"
+)
+
+// validateResourceTags returns an error if the supplied tags cannot be
+// set on a machine or application. Tags beginning with the juju- prefix
+// are reserved for juju's own use.
+func validateResourceTags(resourceTags map[string]string) error {
+	for key := range resourceTags {
+		if key == "" {
+			return errors.NotValidf("empty tag key")
+		}
+		if strings.HasPrefix(key, tags.JujuTagPrefix) {
+			return errors.NotValidf("tag %q with reserved prefix %q", key, tags.JujuTagPrefix)
+		}
+		if strings.ContainsAny(key, ".$") {
+			return errors.NotValidf("tag %q", key)
+		}
+	}
+	return nil
+}
+
+// copyResourceTags returns a copy of the supplied tags, or nil if
+// there are none.
+func copyResourceTags(resourceTags map[string]string) map[string]string {
+	if len(resourceTags) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(resourceTags))
+	for k, v := range resourceTags {
+		result[k] = v
+	}
+	return result
+}
+
+// setResourceTagsUpdate returns the update that replaces the tags on a
+// machine or application document.
+func setResourceTagsUpdate(resourceTags map[string]string) bson.D {
+	if len(resourceTags) == 0 {
+		return bson.D{{"$unset", bson.D{{"tags", nil}}}}
+	}
+	return bson.D{{"$set", bson.D{{"tags", resourceTags}}}}
+}
+
+// Tags returns the user-defined tags set on the machine.
+func (m *Machine) Tags() map[string]string {
+	return copyResourceTags(m.doc.Tags)
+}
+
+// SetTags replaces the user-defined tags on the machine. The tags are
+// applied to the machine's instance, on providers that support
+// instance tags, when the instance is provisioned.
+func (m *Machine) SetTags(resourceTags map[string]string) error {
+	if err := validateResourceTags(resourceTags); err != nil {
+		return errors.Annotatef(err, "cannot set tags for machine %v", m)
+	}
+	resourceTags = copyResourceTags(resourceTags)
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: setResourceTagsUpdate(resourceTags),
+	}}
+	if err := m.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set tags for machine %v", m)
+	}
+	m.doc.Tags = resourceTags
+	return nil
+}
+
+// Tags returns the user-defined tags set on the application.
+func (a *Application) Tags() map[string]string {
+	return copyResourceTags(a.doc.Tags)
+}
+
+// SetTags replaces the user-defined tags on the application. The tags
+// are applied, on providers that support instance tags, to the
+// instances provisioned for the application's units.
+func (a *Application) SetTags(resourceTags map[string]string) error {
+	if err := validateResourceTags(resourceTags); err != nil {
+		return errors.Annotatef(err, "cannot set tags for application %q", a)
+	}
+	resourceTags = copyResourceTags(resourceTags)
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: setResourceTagsUpdate(resourceTags),
+	}}
+	if err := a.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, errNotAlive), "cannot set tags for application %q", a)
+	}
+	a.doc.Tags = resourceTags
+	return nil
+}
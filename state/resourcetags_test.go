@@ -0,0 +1,67 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ResourceTagsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ResourceTagsSuite{})
+
+func (s *ResourceTagsSuite) TestMachineTags(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Tags(), gc.HasLen, 0)
+
+	err = machine.SetTags(map[string]string{"team": "web", "purpose": "frontend"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Tags(), jc.DeepEquals, map[string]string{"team": "web", "purpose": "frontend"})
+
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Tags(), jc.DeepEquals, map[string]string{"team": "web", "purpose": "frontend"})
+
+	err = machine.SetTags(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Tags(), gc.HasLen, 0)
+}
+
+func (s *ResourceTagsSuite) TestMachineTagsDead(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetTags(map[string]string{"team": "web"})
+	c.Assert(err, gc.ErrorMatches, "cannot set tags for machine 0: not found or dead")
+}
+
+func (s *ResourceTagsSuite) TestApplicationTags(c *gc.C) {
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	c.Assert(application.Tags(), gc.HasLen, 0)
+
+	err := application.SetTags(map[string]string{"team": "web"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = application.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(application.Tags(), jc.DeepEquals, map[string]string{"team": "web"})
+}
+
+func (s *ResourceTagsSuite) TestTagsInvalid(c *gc.C) {
+	application := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := application.SetTags(map[string]string{"juju-model-uuid": "x"})
+	c.Assert(err, gc.ErrorMatches, `cannot set tags for application "wordpress": tag "juju-model-uuid" with reserved prefix "juju-" not valid`)
+	err = application.SetTags(map[string]string{"": "x"})
+	c.Assert(err, gc.ErrorMatches, `cannot set tags for application "wordpress": empty tag key not valid`)
+	err = application.SetTags(map[string]string{"a.b": "x"})
+	c.Assert(err, gc.ErrorMatches, `cannot set tags for application "wordpress": tag "a.b" not valid`)
+}
@@ -0,0 +1,322 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// schemaVersionKey is the id of the document in the controllers
+// collection that records the version of the database schema.
+const schemaVersionKey = "schemaVersion"
+
+// schemaVersionDoc records the version of the database schema, that
+// is, the Version of the last SchemaMigration to have run.
+type schemaVersionDoc struct {
+	Id      string `bson:"_id"`
+	Version int    `bson:"version"`
+}
+
+// SchemaIndex describes an index to be created or dropped by a
+// SchemaMigration.
+type SchemaIndex struct {
+	// Database names the database holding the collection. If empty,
+	// the juju database is used.
+	Database string
+
+	// Collection names the collection to be indexed.
+	Collection string
+
+	// Index describes the index. When dropping an index, only its
+	// Key is used.
+	Index mgo.Index
+
+	// Drop causes the index to be dropped rather than created.
+	Drop bool
+}
+
+func (index SchemaIndex) database() string {
+	if index.Database == "" {
+		return jujuDB
+	}
+	return index.Database
+}
+
+func (index SchemaIndex) String() string {
+	verb := "create"
+	if index.Drop {
+		verb = "drop"
+	}
+	return fmt.Sprintf("%s index %s on %s.%s",
+		verb, strings.Join(index.Index.Key, ","), index.database(), index.Collection,
+	)
+}
+
+// SchemaMigration describes a change to the layout of the database,
+// run once when a controller is bootstrapped or upgraded.
+type SchemaMigration struct {
+	// Version is the version of the schema once the migration has
+	// run. Each migration's Version must be one more than that of
+	// the migration before it.
+	Version int
+
+	// Description describes the change made by the migration.
+	Description string
+
+	// Indexes holds the indexes created and dropped by the
+	// migration, in order. Creating an index that already exists,
+	// or dropping one that does not, does nothing.
+	Indexes []SchemaIndex
+
+	// Transform, if non-nil, is run after the indexes have been
+	// changed to rewrite documents in the database. It must return
+	// the number of documents changed, or that would be changed if
+	// dryRun is true, in which case it must change nothing.
+	// Transform must be idempotent.
+	Transform func(st *State, dryRun bool) (int, error)
+}
+
+// schemaMigrations holds the migrations that bring the database up to
+// date, ordered by version. Migrations must never be removed or
+// reordered; add a new one to change the schema.
+var schemaMigrations = []SchemaMigration{{
+	Version:     1,
+	Description: "index the logs collection",
+	Indexes: []SchemaIndex{{
+		// This index needs to include _id because
+		// logTailer.processCollection uses _id to ensure log
+		// records with the same time have a consistent ordering.
+		Database:   logsDB,
+		Collection: logsC,
+		Index:      mgo.Index{Key: []string{"e", "t", "_id"}},
+	}, {
+		Database:   logsDB,
+		Collection: logsC,
+		Index:      mgo.Index{Key: []string{"e", "n"}},
+	}, {
+		// Superseded by the e,t,_id index.
+		Database:   logsDB,
+		Collection: logsC,
+		Index:      mgo.Index{Key: []string{"e", "t"}},
+		Drop:       true,
+	}},
+}}
+
+// SchemaMigrationStep describes a single change made, or that would
+// be made in a dry run, by a SchemaMigration.
+type SchemaMigrationStep struct {
+	// Version and Description identify the migration.
+	Version     int
+	Description string
+
+	// Action describes the change.
+	Action string
+
+	// Documents holds the number of documents changed by a
+	// Transform.
+	Documents int
+}
+
+// MigrateSchemaParams holds the parameters for MigrateSchema.
+type MigrateSchemaParams struct {
+	// DryRun causes MigrateSchema to report the changes it would
+	// make without making them.
+	DryRun bool
+
+	// Progress, if non-nil, is called with each step as it
+	// completes.
+	Progress func(SchemaMigrationStep)
+}
+
+// LatestSchemaVersion returns the version of the schema once every
+// migration has run.
+func LatestSchemaVersion() int {
+	if len(schemaMigrations) == 0 {
+		return 0
+	}
+	return schemaMigrations[len(schemaMigrations)-1].Version
+}
+
+// SchemaVersion returns the version of the database schema, or zero
+// if no migration has run.
+func (st *State) SchemaVersion() (int, error) {
+	controllers, closer := st.getCollection(controllersC)
+	defer closer()
+	var doc schemaVersionDoc
+	err := controllers.FindId(schemaVersionKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Annotate(err, "cannot read schema version")
+	}
+	return doc.Version, nil
+}
+
+// MigrateSchema runs the migrations needed to bring the database schema
+// up to date, recording the schema version as each one completes, and
+// then creates any missing indexes declared on the juju collections.
+// It returns the steps taken. Steps that would change nothing are
+// skipped. In a dry run, nothing is changed and the steps that would
+// be taken are returned.
+func (st *State) MigrateSchema(params MigrateSchemaParams) ([]SchemaMigrationStep, error) {
+	version, err := st.SchemaVersion()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var steps []SchemaMigrationStep
+	report := func(step SchemaMigrationStep) {
+		steps = append(steps, step)
+		if params.Progress != nil {
+			params.Progress(step)
+		}
+	}
+	for _, migration := range schemaMigrations {
+		if migration.Version <= version {
+			continue
+		}
+		if migration.Version != version+1 {
+			return steps, errors.Errorf("schema migration %d follows version %d", migration.Version, version)
+		}
+		if err := st.runSchemaMigration(migration, params.DryRun, report); err != nil {
+			return steps, errors.Annotatef(err, "schema migration %d (%s)", migration.Version, migration.Description)
+		}
+		if !params.DryRun {
+			if err := st.setSchemaVersion(version, migration.Version); err != nil {
+				return steps, errors.Trace(err)
+			}
+			logger.Infof("database schema migrated to version %d: %s", migration.Version, migration.Description)
+		}
+		version = migration.Version
+	}
+	// Indexes declared on the collections are kept up to date on
+	// every run, after any migrations that drop superseded ones.
+	indexCollections := SchemaMigration{
+		Version:     version,
+		Description: "index collections",
+		Indexes:     collectionIndexes(),
+	}
+	if err := st.runSchemaMigration(indexCollections, params.DryRun, report); err != nil {
+		return steps, errors.Annotate(err, "indexing collections")
+	}
+	return steps, nil
+}
+
+// collectionIndexes returns the indexes declared on the juju
+// collections, ordered by collection.
+func collectionIndexes() []SchemaIndex {
+	schema := allCollections()
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var indexes []SchemaIndex
+	for _, name := range names {
+		for _, index := range schema[name].indexes {
+			indexes = append(indexes, SchemaIndex{
+				Collection: name,
+				Index:      index,
+			})
+		}
+	}
+	return indexes
+}
+
+func (st *State) runSchemaMigration(migration SchemaMigration, dryRun bool, report func(SchemaMigrationStep)) error {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	for _, index := range migration.Indexes {
+		collection := session.DB(index.database()).C(index.Collection)
+		exists, err := indexExists(collection, index.Index.Key)
+		if err != nil {
+			return errors.Annotatef(err, "cannot read indexes on %s.%s", index.database(), index.Collection)
+		}
+		if exists != index.Drop {
+			continue
+		}
+		if !dryRun {
+			if index.Drop {
+				err = collection.DropIndex(index.Index.Key...)
+			} else {
+				err = collection.EnsureIndex(index.Index)
+			}
+			if err != nil {
+				return errors.Annotatef(err, "cannot %s", index)
+			}
+		}
+		report(SchemaMigrationStep{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Action:      index.String(),
+		})
+	}
+	if migration.Transform != nil {
+		count, err := migration.Transform(st, dryRun)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if count > 0 {
+			report(SchemaMigrationStep{
+				Version:     migration.Version,
+				Description: migration.Description,
+				Action:      "transform documents",
+				Documents:   count,
+			})
+		}
+	}
+	return nil
+}
+
+// setSchemaVersion records that the schema has been migrated from one
+// version to the next.
+func (st *State) setSchemaVersion(from, to int) error {
+	var op txn.Op
+	if from == 0 {
+		op = txn.Op{
+			C:      controllersC,
+			Id:     schemaVersionKey,
+			Assert: txn.DocMissing,
+			Insert: &schemaVersionDoc{Id: schemaVersionKey, Version: to},
+		}
+	} else {
+		op = txn.Op{
+			C:      controllersC,
+			Id:     schemaVersionKey,
+			Assert: bson.D{{"version", from}},
+			Update: bson.D{{"$set", bson.D{{"version", to}}}},
+		}
+	}
+	if err := st.runTransaction([]txn.Op{op}); err == txn.ErrAborted {
+		return errors.Errorf("cannot set schema version to %d: schema changed concurrently", to)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set schema version to %d", to)
+	}
+	return nil
+}
+
+// indexExists reports whether the collection has an index with the
+// given key. A collection that does not exist has no indexes.
+func indexExists(collection *mgo.Collection, key []string) (bool, error) {
+	indexes, err := collection.Indexes()
+	if queryErr, ok := err.(*mgo.QueryError); ok && queryErr.Code == 26 {
+		// NamespaceNotFound.
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, index := range indexes {
+		if reflect.DeepEqual(index.Key, key) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+)
+
+type schemaMigrationsSuite struct {
+	internalStateSuite
+}
+
+var _ = gc.Suite(&schemaMigrationsSuite{})
+
+func (s *schemaMigrationsSuite) TestInitializeMigratesSchema(c *gc.C) {
+	version, err := s.state.SchemaVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, LatestSchemaVersion())
+
+	logs := s.state.MongoSession().DB(logsDB).C(logsC)
+	for _, key := range [][]string{{"e", "t", "_id"}, {"e", "n"}} {
+		exists, err := indexExists(logs, key)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(exists, jc.IsTrue, gc.Commentf("%v", key))
+	}
+
+	steps, err := s.state.MigrateSchema(MigrateSchemaParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, gc.HasLen, 0)
+}
+
+func (s *schemaMigrationsSuite) TestMigrateSchema(c *gc.C) {
+	latest := LatestSchemaVersion()
+	var transformed []bool
+	s.PatchValue(&schemaMigrations, append(schemaMigrations, SchemaMigration{
+		Version:     latest + 1,
+		Description: "index things",
+		Indexes: []SchemaIndex{{
+			Collection: "things",
+			Index:      mgo.Index{Key: []string{"a", "b"}},
+		}, {
+			Collection: "things",
+			Index:      mgo.Index{Key: []string{"c"}},
+			Drop:       true,
+		}},
+	}, SchemaMigration{
+		Version:     latest + 2,
+		Description: "rewrite things",
+		Transform: func(st *State, dryRun bool) (int, error) {
+			transformed = append(transformed, dryRun)
+			return 3, nil
+		},
+	}))
+	expected := []SchemaMigrationStep{{
+		Version:     latest + 1,
+		Description: "index things",
+		Action:      "create index a,b on juju.things",
+	}, {
+		Version:     latest + 2,
+		Description: "rewrite things",
+		Action:      "transform documents",
+		Documents:   3,
+	}}
+	things := s.state.MongoSession().DB(jujuDB).C("things")
+
+	// A dry run reports the steps without taking them.
+	steps, err := s.state.MigrateSchema(MigrateSchemaParams{DryRun: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, jc.DeepEquals, expected)
+	c.Assert(transformed, jc.DeepEquals, []bool{true})
+	version, err := s.state.SchemaVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, latest)
+	exists, err := indexExists(things, []string{"a", "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+
+	var progress []SchemaMigrationStep
+	steps, err = s.state.MigrateSchema(MigrateSchemaParams{
+		Progress: func(step SchemaMigrationStep) {
+			progress = append(progress, step)
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, jc.DeepEquals, expected)
+	c.Assert(progress, jc.DeepEquals, expected)
+	c.Assert(transformed, jc.DeepEquals, []bool{true, false})
+	version, err = s.state.SchemaVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, latest+2)
+	exists, err = indexExists(things, []string{"a", "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsTrue)
+
+	// Once the schema is up to date, nothing more is done.
+	steps, err = s.state.MigrateSchema(MigrateSchemaParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, gc.HasLen, 0)
+	c.Assert(transformed, gc.HasLen, 2)
+}
+
+func (s *schemaMigrationsSuite) TestMigrateSchemaDropsIndex(c *gc.C) {
+	things := s.state.MongoSession().DB(jujuDB).C("things")
+	err := things.EnsureIndexKey("c")
+	c.Assert(err, jc.ErrorIsNil)
+	latest := LatestSchemaVersion()
+	s.PatchValue(&schemaMigrations, append(schemaMigrations, SchemaMigration{
+		Version:     latest + 1,
+		Description: "drop index",
+		Indexes: []SchemaIndex{{
+			Collection: "things",
+			Index:      mgo.Index{Key: []string{"c"}},
+			Drop:       true,
+		}},
+	}))
+
+	steps, err := s.state.MigrateSchema(MigrateSchemaParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, jc.DeepEquals, []SchemaMigrationStep{{
+		Version:     latest + 1,
+		Description: "drop index",
+		Action:      "drop index c on juju.things",
+	}})
+	exists, err := indexExists(things, []string{"c"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+}
+
+func (s *schemaMigrationsSuite) TestMigrateSchemaVersionGap(c *gc.C) {
+	latest := LatestSchemaVersion()
+	s.PatchValue(&schemaMigrations, append(schemaMigrations, SchemaMigration{
+		Version:     latest + 2,
+		Description: "skipped a version",
+	}))
+	_, err := s.state.MigrateSchema(MigrateSchemaParams{})
+	c.Assert(err, gc.ErrorMatches, `schema migration \d+ follows version \d+`)
+}
+
+func (s *schemaMigrationsSuite) TestMigrateSchemaRestoresCollectionIndexes(c *gc.C) {
+	credentials := s.state.MongoSession().DB(jujuDB).C(cloudCredentialsC)
+	err := credentials.DropIndex("owner", "cloud")
+	c.Assert(err, jc.ErrorIsNil)
+	expected := []SchemaMigrationStep{{
+		Version:     LatestSchemaVersion(),
+		Description: "index collections",
+		Action:      "create index owner,cloud on juju." + cloudCredentialsC,
+	}}
+
+	steps, err := s.state.MigrateSchema(MigrateSchemaParams{DryRun: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, jc.DeepEquals, expected)
+	exists, err := indexExists(credentials, []string{"owner", "cloud"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+
+	steps, err = s.state.MigrateSchema(MigrateSchemaParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, jc.DeepEquals, expected)
+	exists, err = indexExists(credentials, []string{"owner", "cloud"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsTrue)
+}
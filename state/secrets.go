@@ -0,0 +1,386 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// maxSecretRevisions is the number of revisions of each secret's value
+// that are kept. Older revisions are discarded as new ones are added.
+const maxSecretRevisions = 10
+
+var validSecretName = regexp.MustCompile("^[a-z][a-z0-9]*(-[a-z0-9]+)*$")
+
+// IsValidSecretName reports whether name is a valid secret name.
+func IsValidSecretName(name string) bool {
+	return validSecretName.MatchString(name)
+}
+
+// Secret represents a named secret stored in a model. Its value is
+// versioned: each rotation adds a new revision. Applications and units
+// may read the secret only once they have been granted access to it.
+type Secret struct {
+	st  *State
+	doc secretDoc
+}
+
+// secretDoc records a secret, its recent revisions and the entities
+// that may read it.
+type secretDoc struct {
+	DocID       string              `bson:"_id"`
+	ModelUUID   string              `bson:"model-uuid"`
+	Name        string              `bson:"name"`
+	Description string              `bson:"description"`
+	Revision    int                 `bson:"revision"`
+	Revisions   []secretRevisionDoc `bson:"revisions"`
+	Readers     []string            `bson:"readers"`
+}
+
+// secretRevisionDoc records a single revision of a secret's value.
+type secretRevisionDoc struct {
+	Revision int               `bson:"revision"`
+	Created  time.Time         `bson:"created"`
+	Data     map[string]string `bson:"data"`
+}
+
+// SecretParams holds the parameters for creating a secret.
+type SecretParams struct {
+	// Name uniquely identifies the secret within the model.
+	Name string
+
+	// Description describes the secret.
+	Description string
+
+	// Data holds the value of the secret.
+	Data map[string]string
+}
+
+// Validate returns an error if the params cannot create a secret.
+func (p SecretParams) Validate() error {
+	if !IsValidSecretName(p.Name) {
+		return errors.NotValidf("secret name %q", p.Name)
+	}
+	return validateSecretData(p.Data)
+}
+
+// validateSecretData returns an error if data cannot be stored as the
+// value of a secret. The keys are stored as mongo field names, so they
+// may not contain "." or "$".
+func validateSecretData(data map[string]string) error {
+	if len(data) == 0 {
+		return errors.NotValidf("empty secret value")
+	}
+	for key := range data {
+		if key == "" {
+			return errors.NotValidf("empty secret key")
+		}
+		if strings.ContainsAny(key, ".$") {
+			return errors.NotValidf("secret key %q", key)
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the secret.
+func (s *Secret) Name() string {
+	return s.doc.Name
+}
+
+// Description returns the description of the secret.
+func (s *Secret) Description() string {
+	return s.doc.Description
+}
+
+// Revision returns the latest revision of the secret's value.
+func (s *Secret) Revision() int {
+	return s.doc.Revision
+}
+
+// Data returns the latest revision of the secret's value.
+func (s *Secret) Data() map[string]string {
+	data, _ := s.DataAt(s.doc.Revision)
+	return data
+}
+
+// DataAt returns the given revision of the secret's value, or an error
+// satisfying errors.IsNotFound if that revision has been discarded.
+func (s *Secret) DataAt(revision int) (map[string]string, error) {
+	for _, rev := range s.doc.Revisions {
+		if rev.Revision == revision {
+			return copySecretData(rev.Data), nil
+		}
+	}
+	return nil, errors.NotFoundf("revision %d of secret %q", revision, s.doc.Name)
+}
+
+// Readers returns the tags of the applications and units that may read
+// the secret.
+func (s *Secret) Readers() ([]names.Tag, error) {
+	readers := make([]names.Tag, len(s.doc.Readers))
+	for i, reader := range s.doc.Readers {
+		tag, err := names.ParseTag(reader)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		readers[i] = tag
+	}
+	return readers, nil
+}
+
+// CanRead reports whether the given entity may read the secret. A unit
+// may read the secret if either it or its application has been
+// granted access.
+func (s *Secret) CanRead(tag names.Tag) bool {
+	allowed := []string{tag.String()}
+	if unitTag, ok := tag.(names.UnitTag); ok {
+		appName, err := names.UnitApplication(unitTag.Id())
+		if err == nil {
+			allowed = append(allowed, names.NewApplicationTag(appName).String())
+		}
+	}
+	for _, reader := range s.doc.Readers {
+		for _, a := range allowed {
+			if reader == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Refresh refreshes the contents of the secret from the underlying
+// state. It returns an error satisfying errors.IsNotFound if the
+// secret has been removed.
+func (s *Secret) Refresh() error {
+	doc, err := s.st.secretDoc(s.doc.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc = *doc
+	return nil
+}
+
+// Rotate replaces the value of the secret, recording it as a new
+// revision. The oldest revisions are discarded so that at most
+// maxSecretRevisions are kept.
+func (s *Secret) Rotate(data map[string]string) error {
+	if err := validateSecretData(data); err != nil {
+		return errors.Annotatef(err, "cannot rotate secret %q", s.doc.Name)
+	}
+	var revisions []secretRevisionDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		revisions = append(append([]secretRevisionDoc(nil), s.doc.Revisions...), secretRevisionDoc{
+			Revision: s.doc.Revision + 1,
+			Created:  s.st.NowToTheSecond(),
+			Data:     copySecretData(data),
+		})
+		if len(revisions) > maxSecretRevisions {
+			revisions = revisions[len(revisions)-maxSecretRevisions:]
+		}
+		return []txn.Op{{
+			C:      secretsC,
+			Id:     s.doc.Name,
+			Assert: bson.D{{"revision", s.doc.Revision}},
+			Update: bson.D{{"$set", bson.D{
+				{"revision", s.doc.Revision + 1},
+				{"revisions", revisions},
+			}}},
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot rotate secret %q", s.doc.Name)
+	}
+	s.doc.Revision++
+	s.doc.Revisions = revisions
+	return nil
+}
+
+// Grant allows the given application or unit, which must be alive, to
+// read the secret. The grant is removed along with the application or
+// unit.
+func (s *Secret) Grant(tag names.Tag) error {
+	if err := checkSecretReader(tag); err != nil {
+		return errors.Annotatef(err, "cannot grant access to secret %q", s.doc.Name)
+	}
+	readerC := applicationsC
+	if tag.Kind() == names.UnitTagKind {
+		readerC = unitsC
+	}
+	ops := []txn.Op{{
+		C:      readerC,
+		Id:     s.st.docID(tag.Id()),
+		Assert: isAliveDoc,
+	}, {
+		C:      secretsC,
+		Id:     s.doc.Name,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"readers", tag.String()}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Errorf("cannot grant access to secret %q: %s %q is not alive", s.doc.Name, tag.Kind(), tag.Id())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot grant access to secret %q", s.doc.Name)
+	}
+	return s.Refresh()
+}
+
+// Revoke stops the given application or unit from reading the secret.
+// A unit may still read the secret if its application has access.
+func (s *Secret) Revoke(tag names.Tag) error {
+	if err := checkSecretReader(tag); err != nil {
+		return errors.Annotatef(err, "cannot revoke access to secret %q", s.doc.Name)
+	}
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     s.doc.Name,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$pull", bson.D{{"readers", tag.String()}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("secret %q", s.doc.Name)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot revoke access to secret %q", s.doc.Name)
+	}
+	return s.Refresh()
+}
+
+// Remove removes the secret and all of its revisions.
+func (s *Secret) Remove() error {
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     s.doc.Name,
+		Remove: true,
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove secret %q", s.doc.Name)
+	}
+	return nil
+}
+
+// checkSecretReader returns an error unless tag identifies an entity
+// that may be granted access to a secret.
+func checkSecretReader(tag names.Tag) error {
+	switch tag.(type) {
+	case names.ApplicationTag, names.UnitTag:
+		return nil
+	}
+	return errors.NotValidf("secret reader %q", tag)
+}
+
+// removeSecretReaderOps returns the operations that stop the given
+// application or unit from reading any secret, so that access is not
+// inherited by a later application or unit with the same name.
+func removeSecretReaderOps(st *State, tag names.Tag) ([]txn.Op, error) {
+	secrets, closer := st.getCollection(secretsC)
+	defer closer()
+	var docs []secretDoc
+	query := secrets.Find(bson.D{{"readers", tag.String()}}).Select(bson.D{{"name", 1}})
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get secrets readable by %s", tag)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      secretsC,
+			Id:     doc.Name,
+			Update: bson.D{{"$pull", bson.D{{"readers", tag.String()}}}},
+		}
+	}
+	return ops, nil
+}
+
+func copySecretData(data map[string]string) map[string]string {
+	result := make(map[string]string, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	return result
+}
+
+// AddSecret creates a new secret in the model. Its value starts at
+// revision 1, and no application or unit may read it until granted
+// access.
+func (st *State) AddSecret(params SecretParams) (*Secret, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Annotate(err, "cannot add secret")
+	}
+	doc := secretDoc{
+		DocID:       st.docID(params.Name),
+		ModelUUID:   st.ModelUUID(),
+		Name:        params.Name,
+		Description: params.Description,
+		Revision:    1,
+		Revisions: []secretRevisionDoc{{
+			Revision: 1,
+			Created:  st.NowToTheSecond(),
+			Data:     copySecretData(params.Data),
+		}},
+		Readers: []string{},
+	}
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     params.Name,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("secret %q", params.Name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot add secret %q", params.Name)
+	}
+	return &Secret{st: st, doc: doc}, nil
+}
+
+func (st *State) secretDoc(name string) (*secretDoc, error) {
+	secrets, closer := st.getCollection(secretsC)
+	defer closer()
+	var doc secretDoc
+	if err := secrets.FindId(name).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secret %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get secret %q", name)
+	}
+	return &doc, nil
+}
+
+// Secret returns the secret with the given name.
+func (st *State) Secret(name string) (*Secret, error) {
+	doc, err := st.secretDoc(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Secret{st: st, doc: *doc}, nil
+}
+
+// AllSecrets returns all the secrets in the model, ordered by name.
+func (st *State) AllSecrets() ([]*Secret, error) {
+	secrets, closer := st.getCollection(secretsC)
+	defer closer()
+	var docs []secretDoc
+	if err := secrets.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get secrets")
+	}
+	result := make([]*Secret, len(docs))
+	for i, doc := range docs {
+		result[i] = &Secret{st: st, doc: doc}
+	}
+	return result, nil
+}
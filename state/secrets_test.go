@@ -0,0 +1,231 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type SecretsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) addSecret(c *gc.C, name string) *state.Secret {
+	secret, err := s.State.AddSecret(state.SecretParams{
+		Name:        name,
+		Description: "a secret",
+		Data:        map[string]string{"password": "hunter2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return secret
+}
+
+func (s *SecretsSuite) TestAddSecret(c *gc.C) {
+	s.addSecret(c, "db-password")
+
+	secret, err := s.State.Secret("db-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Name(), gc.Equals, "db-password")
+	c.Assert(secret.Description(), gc.Equals, "a secret")
+	c.Assert(secret.Revision(), gc.Equals, 1)
+	c.Assert(secret.Data(), jc.DeepEquals, map[string]string{"password": "hunter2"})
+	readers, err := secret.Readers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readers, gc.HasLen, 0)
+}
+
+func (s *SecretsSuite) TestAddSecretDuplicate(c *gc.C) {
+	s.addSecret(c, "db-password")
+	_, err := s.State.AddSecret(state.SecretParams{
+		Name: "db-password",
+		Data: map[string]string{"password": "secret"},
+	})
+	c.Assert(err, gc.ErrorMatches, `secret "db-password" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *SecretsSuite) TestAddSecretInvalid(c *gc.C) {
+	_, err := s.State.AddSecret(state.SecretParams{
+		Name: "Bad_Name",
+		Data: map[string]string{"password": "secret"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add secret: secret name "Bad_Name" not valid`)
+
+	_, err = s.State.AddSecret(state.SecretParams{Name: "empty"})
+	c.Assert(err, gc.ErrorMatches, `cannot add secret: empty secret value not valid`)
+}
+
+func (s *SecretsSuite) TestSecretNotFound(c *gc.C) {
+	_, err := s.State.Secret("missing")
+	c.Assert(err, gc.ErrorMatches, `secret "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestAllSecrets(c *gc.C) {
+	s.addSecret(c, "zzz")
+	s.addSecret(c, "aaa")
+	secrets, err := s.State.AllSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 2)
+	c.Assert(secrets[0].Name(), gc.Equals, "aaa")
+	c.Assert(secrets[1].Name(), gc.Equals, "zzz")
+}
+
+func (s *SecretsSuite) TestRotate(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	err := secret.Rotate(map[string]string{"password": "correct horse"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 2)
+
+	secret, err = s.State.Secret("db-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 2)
+	c.Assert(secret.Data(), jc.DeepEquals, map[string]string{"password": "correct horse"})
+	data, err := secret.DataAt(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "hunter2"})
+}
+
+func (s *SecretsSuite) TestRotateDiscardsOldRevisions(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	for i := 0; i < 10; i++ {
+		err := secret.Rotate(map[string]string{"password": fmt.Sprint(i)})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 11)
+	_, err = secret.DataAt(1)
+	c.Assert(err, gc.ErrorMatches, `revision 1 of secret "db-password" not found`)
+	data, err := secret.DataAt(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "0"})
+}
+
+func (s *SecretsSuite) TestRotateConcurrently(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	other, err := s.State.Secret("db-password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.Rotate(map[string]string{"password": "first"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = secret.Rotate(map[string]string{"password": "second"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 3)
+	data, err := secret.DataAt(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "first"})
+}
+
+func (s *SecretsSuite) TestGrantRevoke(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	wordpressApp := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: wordpressApp})
+	mysqlApp := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "mysql"})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysqlApp})
+	wordpress := names.NewApplicationTag("wordpress")
+	wordpressUnit := names.NewUnitTag("wordpress/0")
+	mysqlUnit := names.NewUnitTag("mysql/0")
+	c.Assert(secret.CanRead(wordpressUnit), jc.IsFalse)
+
+	err := secret.Grant(wordpress)
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.CanRead(wordpress), jc.IsTrue)
+	c.Assert(secret.CanRead(wordpressUnit), jc.IsTrue)
+	c.Assert(secret.CanRead(mysqlUnit), jc.IsTrue)
+	c.Assert(secret.CanRead(names.NewUnitTag("mysql/1")), jc.IsFalse)
+	readers, err := secret.Readers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readers, jc.SameContents, []names.Tag{wordpress, mysqlUnit})
+
+	err = secret.Revoke(wordpress)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.CanRead(wordpressUnit), jc.IsFalse)
+	c.Assert(secret.CanRead(mysqlUnit), jc.IsTrue)
+}
+
+func (s *SecretsSuite) TestGrantInvalidReader(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	err := secret.Grant(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, `cannot grant access to secret "db-password": secret reader "machine-0" not valid`)
+}
+
+func (s *SecretsSuite) TestGrantNotAlive(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	err := secret.Grant(names.NewApplicationTag("wordpress"))
+	c.Assert(err, gc.ErrorMatches, `cannot grant access to secret "db-password": application "wordpress" is not alive`)
+
+	unit := s.Factory.MakeUnit(c, nil)
+	err = unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(unit.Tag())
+	c.Assert(err, gc.ErrorMatches, `cannot grant access to secret "db-password": unit "`+unit.Name()+`" is not alive`)
+}
+
+func (s *SecretsSuite) TestRemovingReadersRevokesAccess(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: app})
+	err := secret.Grant(app.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	readers, err := secret.Readers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readers, jc.DeepEquals, []names.Tag{app.Tag()})
+
+	err = app.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	readers, err = secret.Readers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readers, gc.HasLen, 0)
+
+	// A new application with the same name does not inherit access.
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	c.Assert(secret.CanRead(names.NewUnitTag("wordpress/0")), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestInvalidDataKeys(c *gc.C) {
+	for _, key := range []string{"", "a.b", "$set"} {
+		_, err := s.State.AddSecret(state.SecretParams{
+			Name: "db-password",
+			Data: map[string]string{key: "secret"},
+		})
+		c.Check(err, gc.ErrorMatches, `cannot add secret: .* not valid`)
+	}
+	secret := s.addSecret(c, "db-password")
+	err := secret.Rotate(map[string]string{"a.b": "secret"})
+	c.Assert(err, gc.ErrorMatches, `cannot rotate secret "db-password": secret key "a.b" not valid`)
+}
+
+func (s *SecretsSuite) TestRemove(c *gc.C) {
+	secret := s.addSecret(c, "db-password")
+	err := secret.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Secret("db-password")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = secret.Grant(names.NewApplicationTag("wordpress"))
+	c.Assert(err, gc.ErrorMatches, `secret "db-password" not found`)
+}
@@ -21,6 +21,15 @@ type sequenceDoc struct {
 // sequence safely increments a database backed sequence, returning
 // the next value.
 func (st *State) sequence(name string) (int, error) {
+	return st.sequenceRange(name, 1)
+}
+
+// sequenceRange safely reserves count consecutive values from a
+// database backed sequence, returning the first of them.
+func (st *State) sequenceRange(name string, count int) (int, error) {
+	if count < 1 {
+		return -1, errors.Errorf("cannot reserve %d %q sequence numbers", count, name)
+	}
 	sequences, closer := st.getCollection(sequenceC)
 	defer closer()
 	query := sequences.FindId(name)
@@ -30,7 +39,7 @@ func (st *State) sequence(name string) (int, error) {
 				"name":       name,
 				"model-uuid": st.ModelUUID(),
 			},
-			"$inc": bson.M{"counter": 1},
+			"$inc": bson.M{"counter": count},
 		},
 		Upsert: true,
 	}
@@ -0,0 +1,120 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package sessionmetrics provides a prometheus.Collector that reports
+// on the mongo sessions and queries made through the state package.
+package sessionmetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/state"
+)
+
+const (
+	metricsNamespace = "juju_mgo"
+
+	databaseLabel   = "database"
+	collectionLabel = "collection"
+)
+
+var queryLabelNames = []string{
+	databaseLabel,
+	collectionLabel,
+}
+
+// Collector is a prometheus.Collector that collects metrics about
+// mongo sessions, sockets, and query latency.
+type Collector struct {
+	getStats func() state.MongoSessionStats
+
+	queryDuration *prometheus.HistogramVec
+
+	clusters     *prometheus.Desc
+	masterConns  *prometheus.Desc
+	slaveConns   *prometheus.Desc
+	socketsAlive *prometheus.Desc
+	socketsInUse *prometheus.Desc
+	socketRefs   *prometheus.Desc
+	sentOps      *prometheus.Desc
+	receivedOps  *prometheus.Desc
+	receivedDocs *prometheus.Desc
+}
+
+// New returns a new Collector, which reports the session statistics
+// returned by getStats; this will usually be state.GetMongoSessionStats.
+func New(getStats func() state.MongoSessionStats) *Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", name),
+			help, nil, nil,
+		)
+	}
+	return &Collector{
+		getStats: getStats,
+		queryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Name:      "query_duration_seconds",
+				Help:      "Time taken by mongo queries, by collection.",
+			},
+			queryLabelNames,
+		),
+		clusters:     desc("clusters", "Number of mongo clusters connected to."),
+		masterConns:  desc("master_connections", "Number of connections to mongo primaries."),
+		slaveConns:   desc("slave_connections", "Number of connections to mongo secondaries."),
+		socketsAlive: desc("sockets_alive", "Number of open mongo sockets."),
+		socketsInUse: desc("sockets_in_use", "Number of mongo sockets in use by sessions."),
+		socketRefs:   desc("socket_refs", "Number of references to mongo sockets held by sessions."),
+		sentOps:      desc("sent_ops_total", "Total number of operations sent to mongo."),
+		receivedOps:  desc("received_ops_total", "Total number of replies received from mongo."),
+		receivedDocs: desc("received_docs_total", "Total number of documents received from mongo."),
+	}
+}
+
+// ObserveQuery records the time taken by a query against the given
+// collection. It has the signature of state.QueryObserverFunc.
+func (c *Collector) ObserveQuery(dbName, collection string, duration time.Duration) {
+	c.queryDuration.With(prometheus.Labels{
+		databaseLabel:   dbName,
+		collectionLabel: collection,
+	}).Observe(duration.Seconds())
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.queryDuration.Describe(ch)
+	ch <- c.clusters
+	ch <- c.masterConns
+	ch <- c.slaveConns
+	ch <- c.socketsAlive
+	ch <- c.socketsInUse
+	ch <- c.socketRefs
+	ch <- c.sentOps
+	ch <- c.receivedOps
+	ch <- c.receivedDocs
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.queryDuration.Collect(ch)
+
+	stats := c.getStats()
+	gauge := func(desc *prometheus.Desc, value int) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value))
+	}
+	counter := func(desc *prometheus.Desc, value int) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value))
+	}
+	gauge(c.clusters, stats.Clusters)
+	gauge(c.masterConns, stats.MasterConns)
+	gauge(c.slaveConns, stats.SlaveConns)
+	gauge(c.socketsAlive, stats.SocketsAlive)
+	gauge(c.socketsInUse, stats.SocketsInUse)
+	gauge(c.socketRefs, stats.SocketRefs)
+	counter(c.sentOps, stats.SentOps)
+	counter(c.receivedOps, stats.ReceivedOps)
+	counter(c.receivedDocs, stats.ReceivedDocs)
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sessionmetrics_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/sessionmetrics"
+)
+
+type collectorSuite struct {
+	testing.IsolationSuite
+	collector *sessionmetrics.Collector
+}
+
+var _ = gc.Suite(&collectorSuite{})
+
+func (s *collectorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.collector = sessionmetrics.New(func() state.MongoSessionStats {
+		return state.MongoSessionStats{
+			Clusters:     1,
+			MasterConns:  2,
+			SlaveConns:   3,
+			SentOps:      4,
+			ReceivedOps:  5,
+			ReceivedDocs: 6,
+			SocketsAlive: 7,
+			SocketsInUse: 8,
+			SocketRefs:   9,
+		}
+	})
+}
+
+func (s *collectorSuite) TestDescribe(c *gc.C) {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		defer close(ch)
+		s.collector.Describe(ch)
+	}()
+	var descStrings []string
+	for desc := range ch {
+		descStrings = append(descStrings, desc.String())
+	}
+	expect := []string{
+		`.*fqName: "juju_mgo_query_duration_seconds".*`,
+		`.*fqName: "juju_mgo_clusters".*`,
+		`.*fqName: "juju_mgo_master_connections".*`,
+		`.*fqName: "juju_mgo_slave_connections".*`,
+		`.*fqName: "juju_mgo_sockets_alive".*`,
+		`.*fqName: "juju_mgo_sockets_in_use".*`,
+		`.*fqName: "juju_mgo_socket_refs".*`,
+		`.*fqName: "juju_mgo_sent_ops_total".*`,
+		`.*fqName: "juju_mgo_received_ops_total".*`,
+		`.*fqName: "juju_mgo_received_docs_total".*`,
+	}
+	c.Assert(descStrings, gc.HasLen, len(expect))
+	for i, expect := range expect {
+		c.Assert(descStrings[i], gc.Matches, expect)
+	}
+}
+
+func (s *collectorSuite) collect(c *gc.C) []dto.Metric {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		defer close(ch)
+		s.collector.Collect(ch)
+	}()
+	var metrics []dto.Metric
+	for metric := range ch {
+		var m dto.Metric
+		err := metric.Write(&m)
+		c.Assert(err, jc.ErrorIsNil)
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+func (s *collectorSuite) TestCollectSessionStats(c *gc.C) {
+	metrics := s.collect(c)
+	c.Assert(metrics, gc.HasLen, 9)
+	for i, m := range metrics[:6] {
+		c.Check(m.Gauge.GetValue(), gc.Equals, []float64{1, 2, 3, 7, 8, 9}[i])
+	}
+	for i, m := range metrics[6:] {
+		c.Check(m.Counter.GetValue(), gc.Equals, []float64{4, 5, 6}[i])
+	}
+}
+
+func (s *collectorSuite) TestObserveQuery(c *gc.C) {
+	s.collector.ObserveQuery("juju", "machines", 2*time.Second)
+	s.collector.ObserveQuery("juju", "machines", time.Second)
+	s.collector.ObserveQuery("juju", "units", time.Second)
+
+	metrics := s.collect(c)
+	c.Assert(metrics, gc.HasLen, 11)
+	byCollection := make(map[string]*dto.Histogram)
+	for _, m := range metrics[:2] {
+		for _, label := range m.Label {
+			if label.GetName() == "collection" {
+				byCollection[label.GetValue()] = m.Histogram
+			}
+		}
+	}
+	c.Assert(byCollection, gc.HasLen, 2)
+	c.Check(byCollection["machines"].GetSampleCount(), gc.Equals, uint64(2))
+	c.Check(byCollection["machines"].GetSampleSum(), gc.Equals, float64(3))
+	c.Check(byCollection["units"].GetSampleCount(), gc.Equals, uint64(1))
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"gopkg.in/mgo.v2"
+)
+
+// MongoSessionStats holds statistics about the mongo connections
+// made by this process, across all States.
+type MongoSessionStats struct {
+	// Clusters is the number of mongo clusters connected to.
+	Clusters int
+
+	// MasterConns and SlaveConns are the number of connections
+	// to primary and secondary servers respectively.
+	MasterConns int
+	SlaveConns  int
+
+	// SentOps, ReceivedOps and ReceivedDocs count the operations
+	// sent to, and the replies and documents received from, the
+	// servers.
+	SentOps      int
+	ReceivedOps  int
+	ReceivedDocs int
+
+	// SocketsAlive is the number of open sockets.
+	SocketsAlive int
+
+	// SocketsInUse is the number of sockets in use by sessions.
+	SocketsInUse int
+
+	// SocketRefs is the number of references to sockets held by
+	// sessions; it grows with the number of copied sessions that
+	// have not been closed.
+	SocketRefs int
+}
+
+// EnableMongoSessionStats starts collecting the statistics reported
+// by GetMongoSessionStats. Collection carries a small cost on every
+// mongo operation, so it is off by default.
+func EnableMongoSessionStats() {
+	mgo.SetStats(true)
+}
+
+// GetMongoSessionStats returns the current mongo session statistics.
+// They will all be zero unless EnableMongoSessionStats has been
+// called.
+func GetMongoSessionStats() MongoSessionStats {
+	stats := mgo.GetStats()
+	return MongoSessionStats{
+		Clusters:     stats.Clusters,
+		MasterConns:  stats.MasterConns,
+		SlaveConns:   stats.SlaveConns,
+		SentOps:      stats.SentOps,
+		ReceivedOps:  stats.ReceivedOps,
+		ReceivedDocs: stats.ReceivedDocs,
+		SocketsAlive: stats.SocketsAlive,
+		SocketsInUse: stats.SocketsInUse,
+		SocketRefs:   stats.SocketRefs,
+	}
+}
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -164,6 +165,28 @@ func cacheKeys(caches ...map[string]interface{}) map[string]bool {
 	return keys
 }
 
+// settingsChanges returns the changes, sorted by key, that turn the
+// old settings into the new ones.
+func settingsChanges(old, new map[string]interface{}) []ItemChange {
+	changes := []ItemChange{}
+	for key := range cacheKeys(old, new) {
+		oldv, inold := old[key]
+		newv, innew := new[key]
+		switch {
+		case inold && innew:
+			if !reflect.DeepEqual(oldv, newv) {
+				changes = append(changes, ItemChange{ItemModified, key, oldv, newv})
+			}
+		case innew:
+			changes = append(changes, ItemChange{ItemAdded, key, nil, newv})
+		default:
+			changes = append(changes, ItemChange{ItemDeleted, key, oldv, nil})
+		}
+	}
+	sort.Sort(itemChangeSlice(changes))
+	return changes
+}
+
 // settingsUpdateOps returns the item changes and txn ops necessary
 // to write the changes made to c back onto its node.
 func (s *Settings) settingsUpdateOps() ([]ItemChange, []txn.Op) {
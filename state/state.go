@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -87,6 +88,7 @@ type State struct {
 	policy                 Policy
 	newPolicy              NewPolicyFunc
 	runTransactionObserver RunTransactionObserverFunc
+	queryObserver          QueryObserverFunc
 
 	// cloudName is the name of the cloud on which the model
 	// represented by this state runs.
@@ -121,6 +123,7 @@ type StateServingInfo struct {
 	StatePort    int
 	Cert         string
 	PrivateKey   string
+	CertChain    string
 	CAPrivateKey string
 	// this will be passed as the KeyFile argument to MongoDB
 	SharedSecret   string
@@ -301,6 +304,7 @@ func (st *State) ForModel(modelTag names.ModelTag) (*State, error) {
 	newSt, err := newState(
 		modelTag, st.controllerModelTag, session, st.mongoInfo, st.newPolicy, st.clock,
 		st.runTransactionObserver,
+		st.queryObserver,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -509,6 +513,29 @@ func (st *State) db() Database {
 	return st.database
 }
 
+// ReadSecondaryPreferred returns a copy of the State whose queries are
+// served by a mongo secondary when one is available, and a func that
+// must be called when the copy is no longer needed. Results read
+// through the copy may lag slightly behind the primary, so it is only
+// suitable for expensive read-only queries, such as those behind
+// status, that can tolerate this; it must never be used to make
+// changes, and must not itself be closed.
+func (st *State) ReadSecondaryPreferred() (*State, SessionCloser) {
+	db, ok := st.database.(*database)
+	if !ok {
+		return st, dontCloseAnything
+	}
+	readDB, closer := db.copySecondaryPreferred()
+	copied := *st
+	copied.database = readDB
+	copied.session = readDB.raw.Session
+	copied.CloudImageMetadataStorage = cloudimagemetadata.NewStorage(
+		cloudimagemetadataC,
+		&environMongo{&copied},
+	)
+	return &copied, closer
+}
+
 // txnLogWatcher returns the TxnLogWatcher for the State. It is part
 // of the modelBackend interface.
 func (st *State) txnLogWatcher() *watcher.Watcher {
@@ -677,7 +704,13 @@ func (st *State) SetModelAgentVersion(newVersion version.Number) (err error) {
 				},
 			},
 		}
-		return ops, nil
+		// Upgrading the whole model ends any upgrade staged on
+		// some of its machines.
+		stagedOps, err := st.clearDesiredAgentVersionsOps()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, stagedOps...), nil
 	}
 	if err = st.run(buildTxn); err == jujutxn.ErrExcessiveContention {
 		// Although there is a small chance of a race here, try to
@@ -704,7 +737,12 @@ func (st *State) SetModelConstraints(cons constraints.Value) error {
 	if len(unsupported) > 0 {
 		logger.Warningf(
 			"setting model constraints: unsupported constraints: %v", strings.Join(unsupported, ","))
-	} else if err != nil {
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cons, err = st.expandConstraintProfile(cons)
+	if err != nil {
 		return errors.Trace(err)
 	}
 	return writeConstraints(st, modelGlobalKey, cons)
@@ -1051,6 +1089,10 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 		}
 	}
 
+	if err := st.precheckSeries(args.Series); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Ignore constraints that result from this call as
 	// these would be accumulation of model and application constraints
 	// but we only want application constraints to be persisted here.
@@ -1058,6 +1100,10 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	args.Constraints, err = st.expandConstraintProfile(args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	for _, placement := range args.Placement {
 		data, err := st.parsePlacement(placement)
@@ -1213,7 +1259,7 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 	// At the last moment before inserting the application, prime status history.
 	probablyUpdateStatusHistory(st, app.globalKey(), statusDoc)
 
-	if err = st.run(buildTxn); err == nil {
+	if err = st.run(withModelQuotas(st, buildTxn)); err == nil {
 		// Refresh to pick the txn-revno.
 		if err = app.Refresh(); err != nil {
 			return nil, errors.Trace(err)
@@ -2132,6 +2178,39 @@ func (st *State) PutAuditEntryFn() func(audit.AuditEntry) error {
 	return stateaudit.PutAuditEntryFn(auditingC, insert)
 }
 
+// AuditEntries returns the audit entries selected by filter, most
+// recent first.
+func (st *State) AuditEntries(filter audit.Filter) ([]audit.AuditEntry, error) {
+	find := func(collectionName string, query bson.M, sort []string, limit int, result interface{}) error {
+		collection, closeCollection := st.getCollection(collectionName)
+		defer closeCollection()
+
+		q := collection.Find(query).Sort(sort...)
+		if limit > 0 {
+			q = q.Limit(limit)
+		}
+		return errors.Trace(q.All(result))
+	}
+	entries, err := stateaudit.GetAuditEntriesFn(auditingC, find)(filter)
+	return entries, errors.Trace(err)
+}
+
+// PruneAuditLog removes the audit entries recorded before minTime,
+// and returns the number removed. Entries recorded by versions of
+// jujud that did not store the time as a date are never removed.
+func (st *State) PruneAuditLog(minTime time.Time) (int, error) {
+	collection, closeCollection := st.getCollection(auditingC)
+	defer closeCollection()
+
+	info, err := collection.Writeable().RemoveAll(bson.M{
+		"time": bson.M{"$lt": minTime},
+	})
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot prune audit log")
+	}
+	return info.Removed, nil
+}
+
 var tagPrefix = map[byte]string{
 	'm': names.MachineTagKind + "-",
 	'a': names.ApplicationTagKind + "-",
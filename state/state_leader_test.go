@@ -12,6 +12,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -140,6 +141,32 @@ func (s *LeadershipSuite) TestApplicationLeaders(c *gc.C) {
 	})
 }
 
+func (s *LeadershipSuite) TestLeaseDetails(c *gc.C) {
+	err := s.claimer.ClaimLeadership("blah", "blah/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.claimer.ClaimLeadership("application", "application/1", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	// Claiming again on behalf of the leader extends the lease.
+	err = s.claimer.ClaimLeadership("application", "application/1", 2*time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	details, err := s.State.LeaseDetails()
+	c.Assert(err, jc.ErrorIsNil)
+	now := s.Clock.Now()
+	c.Assert(details, jc.DeepEquals, []state.LeaseDetails{{
+		Namespace:  "application-leadership",
+		Name:       "application",
+		Holder:     "application/1",
+		Expiry:     now.Add(2 * time.Minute),
+		Extensions: 1,
+	}, {
+		Namespace: "application-leadership",
+		Name:      "blah",
+		Holder:    "blah/0",
+		Expiry:    now.Add(time.Minute),
+	}})
+}
+
 func (s *LeadershipSuite) expire(c *gc.C, applicationname string) {
 	s.Clock.Advance(time.Hour)
 	s.Session.Fsync(false)
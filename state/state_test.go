@@ -24,6 +24,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	mgotxn "gopkg.in/mgo.v2/txn"
 
@@ -37,6 +38,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/state/multiwatcher"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/status"
@@ -97,6 +99,25 @@ func (s *StateSuite) TestIsController(c *gc.C) {
 	c.Assert(st2.IsController(), jc.IsFalse)
 }
 
+func (s *StateSuite) TestReadSecondaryPreferred(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+
+	st, closer := s.State.ReadSecondaryPreferred()
+	c.Assert(st.MongoSession().Mode(), gc.Equals, mgo.SecondaryPreferred)
+	c.Assert(s.State.MongoSession().Mode(), gc.Not(gc.Equals), mgo.SecondaryPreferred)
+	c.Assert(st.ModelUUID(), gc.Equals, s.State.ModelUUID())
+
+	machines, err := st.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+	c.Assert(machines[0].Id(), gc.Equals, machine.Id())
+	closer()
+
+	// Closing the copy leaves the original usable.
+	_, err = s.State.Machine(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *StateSuite) TestUserModelNameIndex(c *gc.C) {
 	index := state.UserModelNameIndex("BoB", "testing")
 	c.Assert(index, gc.Equals, "bob:testing")
@@ -895,6 +916,43 @@ func (s *StateSuite) TestAddMachines(c *gc.C) {
 	c.Assert(string(instId), gc.Equals, "inst-id")
 }
 
+func (s *StateSuite) TestAddMachinesMultiple(c *gc.C) {
+	oneJob := []state.MachineJob{state.JobHostUnits}
+	machines, err := s.State.AddMachines(
+		state.MachineTemplate{Series: "quantal", Jobs: oneJob},
+		state.MachineTemplate{Series: "trusty", Jobs: oneJob, Constraints: constraints.MustParse("spaces=db")},
+		state.MachineTemplate{Series: "xenial", Jobs: oneJob},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 3)
+	for i, series := range []string{"quantal", "trusty", "xenial"} {
+		m, err := s.State.Machine(strconv.Itoa(i))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(machines[i].Id(), gc.Equals, m.Id())
+		c.Check(m.Series(), gc.Equals, series)
+	}
+	mcons, err := machines[1].Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*mcons.Spaces, jc.DeepEquals, []string{"db"})
+}
+
+func (s *StateSuite) TestAddMachinesInvalidTemplateAddsNone(c *gc.C) {
+	oneJob := []state.MachineJob{state.JobHostUnits}
+	_, err := s.State.AddMachines(
+		state.MachineTemplate{Series: "quantal", Jobs: oneJob},
+		state.MachineTemplate{Series: "quantal"},
+	)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: no jobs specified")
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+
+	// No machine ids were used up by the failed request.
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, "0")
+}
+
 func (s *StateSuite) TestAddMachinesEnvironmentDying(c *gc.C) {
 	env, err := s.State.Model()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1974,6 +2032,15 @@ func (s *StateSuite) TestSetInvalidConstraints(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
 }
 
+func (s *StateSuite) TestSetInvalidConstraintsWithUnsupported(c *gc.C) {
+	cons := constraints.MustParse("mem=4G instance-type=foo cpu-power=10")
+	err := s.State.SetModelConstraints(cons)
+	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
+	econs, err := s.State.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(&econs, jc.Satisfies, constraints.IsEmpty)
+}
+
 func (s *StateSuite) TestSetUnsupportedConstraintsWarning(c *gc.C) {
 	defer loggo.ResetWriters()
 	logger := loggo.GetLogger("test")
@@ -1993,6 +2060,40 @@ func (s *StateSuite) TestSetUnsupportedConstraintsWarning(c *gc.C) {
 	c.Assert(econs, gc.DeepEquals, cons)
 }
 
+func (s *StateSuite) TestWatchCloudImageMetadata(c *gc.C) {
+	w := s.State.WatchCloudImageMetadata()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	attrs := cloudimagemetadata.MetadataAttributes{
+		Stream:          "released",
+		Region:          "region",
+		Version:         "14.04",
+		Series:          "trusty",
+		Arch:            "amd64",
+		VirtType:        "hvm",
+		RootStorageType: "ebs",
+		Source:          "custom",
+	}
+	err := s.State.CloudImageMetadataStorage.SaveMetadata([]cloudimagemetadata.Metadata{{
+		MetadataAttributes: attrs,
+		Priority:           1,
+		ImageId:            "image-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	key := "released:region:trusty:amd64:hvm:ebs:custom"
+	wc.AssertChange(key)
+	wc.AssertNoChange()
+
+	// Removals are reported too, so that caches can be invalidated.
+	err = s.State.CloudImageMetadataStorage.DeleteMetadata("image-1")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(key)
+	wc.AssertNoChange()
+}
+
 func (s *StateSuite) TestWatchModelsBulkEvents(c *gc.C) {
 	// Alive model...
 	alive, err := s.State.Model()
@@ -2821,6 +2922,76 @@ func (s *StateSuite) TestWatchForModelConfigControllerChanges(c *gc.C) {
 	wc.AssertOneChange()
 }
 
+func (s *StateSuite) TestWatchModelConfigItemChanges(c *gc.C) {
+	w := s.State.WatchModelConfigItemChanges()
+	defer statetesting.AssertStop(c, w)
+
+	nextChanges := func() []state.ItemChange {
+		s.State.StartSync()
+		select {
+		case changes, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+			return changes
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for config changes")
+		}
+		panic("unreachable")
+	}
+	assertNoChange := func() {
+		s.State.StartSync()
+		select {
+		case changes := <-w.Changes():
+			c.Fatalf("unexpected config changes %v", changes)
+		case <-time.After(testing.ShortWait):
+		}
+	}
+
+	// Initially every attribute is reported as added.
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	changes := nextChanges()
+	c.Assert(changes, gc.HasLen, len(cfg.AllAttrs()))
+	for _, change := range changes {
+		c.Assert(change.Type, gc.Equals, state.ItemAdded)
+	}
+
+	// Changes made between events are reported together.
+	err = s.State.UpdateModelConfig(attrs{"arbitrary-key": "shazam!"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nextChanges(), jc.DeepEquals, []state.ItemChange{{
+		Type:     state.ItemAdded,
+		Key:      "arbitrary-key",
+		NewValue: "shazam!",
+	}})
+	err = s.State.UpdateModelConfig(attrs{"arbitrary-key": "kaboom!"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateModelConfig(attrs{"other-key": "eggs"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nextChanges(), jc.DeepEquals, []state.ItemChange{{
+		Type:     state.ItemModified,
+		Key:      "arbitrary-key",
+		OldValue: "shazam!",
+		NewValue: "kaboom!",
+	}, {
+		Type:     state.ItemAdded,
+		Key:      "other-key",
+		NewValue: "eggs",
+	}})
+
+	err = s.State.UpdateModelConfig(nil, []string{"arbitrary-key"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nextChanges(), jc.DeepEquals, []state.ItemChange{{
+		Type:     state.ItemDeleted,
+		Key:      "arbitrary-key",
+		OldValue: "kaboom!",
+	}})
+
+	// Setting an attribute to its current value is not reported.
+	err = s.State.UpdateModelConfig(attrs{"other-key": "eggs"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	assertNoChange()
+}
+
 func (s *StateSuite) TestAddAndGetEquivalence(c *gc.C) {
 	// The equivalence tested here isn't necessarily correct, and
 	// comparing private details is discouraged in the project.
@@ -3605,6 +3776,21 @@ func (s *StateSuite) TestSetEnvironAgentVersionSucceedsWithSameVersion(c *gc.C)
 	assertAgentVersion(c, s.State, "4.5.6")
 }
 
+func (s *StateSuite) TestSetModelAgentVersionClearsDesiredAgentVersions(c *gc.C) {
+	s.prepareAgentVersionTests(c, s.State)
+	machine, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetDesiredAgentVersion(version.MustParse("4.5.5"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetModelAgentVersion(version.MustParse("4.5.6"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := machine.DesiredAgentVersion()
+	c.Assert(ok, jc.IsFalse)
+}
+
 func (s *StateSuite) TestSetEnvironAgentVersionOnOtherEnviron(c *gc.C) {
 	current := version.MustParseBinary("1.24.7-trusty-amd64")
 	s.PatchValue(&jujuversion.Current, current.Number)
@@ -4176,6 +4362,31 @@ func (s *StateSuite) TestStateServingInfo(c *gc.C) {
 	c.Assert(info, jc.DeepEquals, data)
 }
 
+func (s *StateSuite) TestWatchStateServingInfo(c *gc.C) {
+	data := state.StateServingInfo{
+		APIPort:      69,
+		StatePort:    80,
+		Cert:         "Some cert",
+		PrivateKey:   "Some key",
+		SharedSecret: "Some Keyfile",
+	}
+	err := s.State.SetStateServingInfo(data)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchStateServingInfo()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	data.Cert = "Another cert"
+	data.PrivateKey = "Another key"
+	err = s.State.SetStateServingInfo(data)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 var setStateServingInfoWithInvalidInfoTests = []func(info *state.StateServingInfo){
 	func(info *state.StateServingInfo) { info.APIPort = 0 },
 	func(info *state.StateServingInfo) { info.StatePort = 0 },
@@ -4539,6 +4750,54 @@ func (s *StateSuite) TestRunTransactionObserver(c *gc.C) {
 	c.Assert(calls[0].ops[0].Update, gc.NotNil)
 }
 
+func (s *StateSuite) TestQueryObserver(c *gc.C) {
+	var mu sync.Mutex
+	var collections []string
+	getCollections := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return collections[:]
+	}
+
+	st, err := state.Open(state.OpenParams{
+		Clock:              clock.WallClock,
+		ControllerTag:      s.State.ControllerTag(),
+		ControllerModelTag: s.modelTag,
+		MongoInfo:          statetesting.NewMongoInfo(),
+		MongoDialOpts:      mongotest.DialOpts(),
+		QueryObserver: func(dbName, collection string, duration time.Duration) {
+			c.Check(dbName, gc.Equals, "juju")
+			c.Check(duration >= 0, jc.IsTrue)
+			mu.Lock()
+			defer mu.Unlock()
+			collections = append(collections, collection)
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(getCollections(), jc.Contains, "machines")
+
+	// States for other models report to the same observer.
+	otherSt, err := st.ForModel(s.modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	defer otherSt.Close()
+	_, err = otherSt.AllApplications()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(getCollections(), jc.Contains, "applications")
+}
+
+func (s *StateSuite) TestMongoSessionStats(c *gc.C) {
+	state.EnableMongoSessionStats()
+	_, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	stats := state.GetMongoSessionStats()
+	c.Assert(stats.SentOps > 0, jc.IsTrue)
+	c.Assert(stats.ReceivedOps > 0, jc.IsTrue)
+}
+
 type SetAdminMongoPasswordSuite struct {
 	testing.BaseSuite
 }
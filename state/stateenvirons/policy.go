@@ -34,8 +34,35 @@ func GetNewPolicyFunc(getEnviron func(*state.State) (environs.Environ, error)) s
 
 // Prechecker implements state.Policy.
 func (p environStatePolicy) Prechecker() (state.Prechecker, error) {
-	// Environ implements state.Prechecker.
-	return p.getEnviron(p.st)
+	env, err := p.getEnviron(p.st)
+	if err != nil {
+		return nil, err
+	}
+	return environPrechecker{env}, nil
+}
+
+// environPrechecker implements state.Prechecker and
+// state.SeriesPrechecker, checking that the environ supports the
+// requested series and any spot capacity before delegating to its
+// PrecheckInstance.
+type environPrechecker struct {
+	env environs.Environ
+}
+
+// PrecheckInstance implements state.Prechecker.
+func (p environPrechecker) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if err := p.PrecheckSeries(series); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := environs.SpotInstanceParamsFromConstraints(p.env, cons); err != nil {
+		return errors.Trace(err)
+	}
+	return p.env.PrecheckInstance(series, cons, placement)
+}
+
+// PrecheckSeries implements state.SeriesPrechecker.
+func (p environPrechecker) PrecheckSeries(series string) error {
+	return environs.CheckSeriesSupported(p.env, series)
 }
 
 // ConfigValidator implements state.Policy.
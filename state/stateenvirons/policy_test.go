@@ -0,0 +1,78 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateenvirons_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+)
+
+type policySuite struct {
+	statetesting.StateSuite
+}
+
+var _ = gc.Suite(&policySuite{})
+
+type seriesEnviron struct {
+	environs.Environ
+	cfg            *config.Config
+	precheckSeries string
+}
+
+func (e *seriesEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+func (e *seriesEnviron) SupportedSeries() ([]string, error) {
+	return []string{"xenial"}, nil
+}
+
+func (e *seriesEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	e.precheckSeries = series
+	return nil
+}
+
+func (s *policySuite) TestPrecheckerChecksSeries(c *gc.C) {
+	env := &seriesEnviron{cfg: testing.ModelConfig(c)}
+	policy := stateenvirons.GetNewPolicyFunc(func(*state.State) (environs.Environ, error) {
+		return env, nil
+	})(s.State)
+	prechecker, err := policy.Prechecker()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = prechecker.PrecheckInstance("centos7", constraints.Value{}, "")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `provider "someprovider" does not support CentOS series "centos7"`)
+	c.Assert(env.precheckSeries, gc.Equals, "")
+
+	c.Assert(prechecker.PrecheckInstance("xenial", constraints.Value{}, ""), jc.ErrorIsNil)
+	c.Assert(env.precheckSeries, gc.Equals, "xenial")
+
+	seriesPrechecker, ok := prechecker.(state.SeriesPrechecker)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(seriesPrechecker.PrecheckSeries("win2012r2"), jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *policySuite) TestPrecheckerChecksSpotInstances(c *gc.C) {
+	env := &seriesEnviron{cfg: testing.ModelConfig(c)}
+	policy := stateenvirons.GetNewPolicyFunc(func(*state.State) (environs.Environ, error) {
+		return env, nil
+	})(s.State)
+	prechecker, err := policy.Prechecker()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = prechecker.PrecheckInstance("xenial", constraints.MustParse("spot-price=0.05"), "")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "spot instances not supported")
+	c.Assert(env.precheckSeries, gc.Equals, "")
+}
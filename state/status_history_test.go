@@ -12,6 +12,7 @@ import (
 	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/status"
@@ -266,3 +267,86 @@ func (s *StatusHistorySuite) TestStatusHistoryFiltersByDateAndDelta(c *gc.C) {
 	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
 	c.Assert(history[2].Message, gc.Equals, "2 days ago")
 }
+
+func (s *StatusHistorySuite) primeUnitForPolicy(c *gc.C) *state.Unit {
+	clock := testing.NewClock(coretesting.NonZeroTime())
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.Factory.MakeUnit(c, nil)
+	// One entry a second for 100 seconds, following the entry
+	// recorded when the unit was added.
+	state.PrimeUnitStatusHistory(c, clock, unit, status.Active, 100, 100, nil)
+	return unit
+}
+
+func (s *StatusHistorySuite) checkHistoryLen(c *gc.C, unit *state.Unit, expect int) {
+	history, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 200})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, expect)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByPolicyDefaultAge(c *gc.C) {
+	unit := s.primeUnitForPolicy(c)
+	err := state.PruneStatusHistoryByPolicy(s.State, controller.StatusHistoryPolicy{
+		Default: controller.StatusHistoryRetention{MaxAge: 50 * time.Second},
+	}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkHistoryLen(c, unit, 51)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByPolicyKindAge(c *gc.C) {
+	unit := s.primeUnitForPolicy(c)
+	err := state.PruneStatusHistoryByPolicy(s.State, controller.StatusHistoryPolicy{
+		Kinds: map[string]controller.StatusHistoryRetention{
+			"unit": {MaxAge: 10 * time.Second},
+		},
+	}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkHistoryLen(c, unit, 11)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByPolicyKindOverridesDefault(c *gc.C) {
+	unit := s.primeUnitForPolicy(c)
+	err := state.PruneStatusHistoryByPolicy(s.State, controller.StatusHistoryPolicy{
+		Default: controller.StatusHistoryRetention{MaxAge: 10 * time.Second},
+		Kinds: map[string]controller.StatusHistoryRetention{
+			"unit": {MaxEntries: 1000},
+		},
+	}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkHistoryLen(c, unit, 101)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByPolicyMaxEntries(c *gc.C) {
+	unit := s.primeUnitForPolicy(c)
+	err := state.PruneStatusHistoryByPolicy(s.State, controller.StatusHistoryPolicy{
+		Kinds: map[string]controller.StatusHistoryRetention{
+			"unit": {MaxEntries: 10},
+		},
+	}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkHistoryLen(c, unit, 10)
+
+	history, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history[0].Status, gc.Equals, status.Active)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByPolicyDownsample(c *gc.C) {
+	unit := s.primeUnitForPolicy(c)
+	err := state.PruneStatusHistoryByPolicy(s.State, controller.StatusHistoryPolicy{
+		DownsampleAge:      40 * time.Second,
+		DownsampleInterval: 10 * time.Second,
+	}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	// The 41 entries from the last 40 seconds are kept, along with
+	// one entry for each 10 seconds before that.
+	s.checkHistoryLen(c, unit, 41+6)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByPolicyInvalid(c *gc.C) {
+	err := state.PruneStatusHistoryByPolicy(s.State, controller.StatusHistoryPolicy{
+		DownsampleAge: time.Hour,
+	}, 0)
+	c.Assert(err, gc.ErrorMatches, "downsampling without an interval not valid")
+}
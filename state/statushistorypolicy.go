@@ -0,0 +1,188 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/controller"
+)
+
+// statusHistoryKindPrefixes maps the kinds of entity named in
+// controller.StatusHistoryKinds to the prefix of their global keys.
+var statusHistoryKindPrefixes = map[string]string{
+	"application": "a#",
+	"filesystem":  "f#",
+	"machine":     "m#",
+	"unit":        "u#",
+	"volume":      "v#",
+}
+
+// statusHistoryKind returns the kind of entity whose status history is
+// recorded under the given global key, or "" if the kind has no limits
+// of its own.
+func statusHistoryKind(globalKey string) string {
+	for kind, prefix := range statusHistoryKindPrefixes {
+		if strings.HasPrefix(globalKey, prefix) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// PruneStatusHistoryByPolicy removes the model's status history entries
+// that are older, or more numerous, than the policy allows for each
+// kind of entity, and downsamples old entries as the policy requires.
+// If maxHistoryMB is non-zero, entries are then removed until the
+// status history collection is smaller than maxHistoryMB.
+func PruneStatusHistoryByPolicy(st *State, policy controller.StatusHistoryPolicy, maxHistoryMB int) error {
+	if maxHistoryMB < 0 {
+		return errors.NotValidf("non-positive maxHistoryMB")
+	}
+	if policy.DownsampleAge > 0 && policy.DownsampleInterval <= 0 {
+		return errors.NotValidf("downsampling without an interval")
+	}
+
+	// NOTE(axw) we require a raw collection to obtain the size of the
+	// collection. Take care to include model-uuid in queries where
+	// appropriate.
+	history, closer := st.getRawCollection(statusesHistoryC)
+	defer closer()
+
+	if err := pruneStatusHistoryByAge(st, history, policy); err != nil {
+		return errors.Annotate(err, "pruning status history by age")
+	}
+	var globalKeys []string
+	err := history.Find(bson.D{{"model-uuid", st.ModelUUID()}}).Distinct("globalkey", &globalKeys)
+	if err != nil {
+		return errors.Annotate(err, "reading status history keys")
+	}
+	for _, globalKey := range globalKeys {
+		retention := policy.Retention(statusHistoryKind(globalKey))
+		if retention.MaxEntries > 0 {
+			if err := pruneStatusHistoryEntries(st, history, globalKey, retention.MaxEntries); err != nil {
+				return errors.Annotatef(err, "pruning status history for %q", globalKey)
+			}
+		}
+		if policy.DownsampleAge > 0 {
+			if err := downsampleStatusHistory(st, history, globalKey, policy); err != nil {
+				return errors.Annotatef(err, "downsampling status history for %q", globalKey)
+			}
+		}
+	}
+	if maxHistoryMB > 0 {
+		return PruneStatusHistory(st, 0, maxHistoryMB)
+	}
+	return nil
+}
+
+// pruneStatusHistoryByAge removes the entries older than the policy
+// allows, first for each kind of entity with its own limits, and then
+// for every other entity.
+func pruneStatusHistoryByAge(st *State, history *mgo.Collection, policy controller.StatusHistoryPolicy) error {
+	now := st.clock.Now()
+	var overridden []string
+	for kind, retention := range policy.Kinds {
+		prefix, ok := statusHistoryKindPrefixes[kind]
+		if !ok {
+			continue
+		}
+		overridden = append(overridden, regexp.QuoteMeta(prefix))
+		if retention.MaxAge <= 0 {
+			continue
+		}
+		_, err := history.RemoveAll(bson.D{
+			{"model-uuid", st.ModelUUID()},
+			{"globalkey", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(prefix)}},
+			{"updated", bson.M{"$lt": now.Add(-retention.MaxAge).UnixNano()}},
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if policy.Default.MaxAge <= 0 {
+		return nil
+	}
+	query := bson.D{
+		{"model-uuid", st.ModelUUID()},
+		{"updated", bson.M{"$lt": now.Add(-policy.Default.MaxAge).UnixNano()}},
+	}
+	if len(overridden) > 0 {
+		query = append(query, bson.DocElem{"globalkey", bson.M{
+			"$not": bson.RegEx{Pattern: "^(" + strings.Join(overridden, "|") + ")"},
+		}})
+	}
+	_, err := history.RemoveAll(query)
+	return errors.Trace(err)
+}
+
+// pruneStatusHistoryEntries removes all but the newest maxEntries
+// entries recorded under the given global key.
+func pruneStatusHistoryEntries(st *State, history *mgo.Collection, globalKey string, maxEntries int) error {
+	iter := history.Find(bson.D{
+		{"model-uuid", st.ModelUUID()},
+		{"globalkey", globalKey},
+	}).Sort("-updated").Skip(maxEntries).Select(bson.D{{"_id", 1}}).Iter()
+	return removeStatusHistoryIds(history, iter, func(bson.M) bool { return true })
+}
+
+// downsampleStatusHistory removes the entries recorded under the given
+// global key that are older than the policy's DownsampleAge, except
+// for the newest entry in each DownsampleInterval.
+func downsampleStatusHistory(st *State, history *mgo.Collection, globalKey string, policy controller.StatusHistoryPolicy) error {
+	cutoff := st.clock.Now().Add(-policy.DownsampleAge).UnixNano()
+	interval := policy.DownsampleInterval.Nanoseconds()
+	iter := history.Find(bson.D{
+		{"model-uuid", st.ModelUUID()},
+		{"globalkey", globalKey},
+		{"updated", bson.M{"$lt": cutoff}},
+	}).Sort("-updated").Select(bson.D{{"_id", 1}, {"updated", 1}}).Iter()
+	lastBucket := int64(-1)
+	return removeStatusHistoryIds(history, iter, func(doc bson.M) bool {
+		updated, _ := doc["updated"].(int64)
+		bucket := updated / interval
+		if bucket == lastBucket {
+			return true
+		}
+		lastBucket = bucket
+		return false
+	})
+}
+
+// removeStatusHistoryIds removes the entries returned by iter for which
+// remove returns true, in batches.
+func removeStatusHistoryIds(history *mgo.Collection, iter *mgo.Iter, remove func(bson.M) bool) error {
+	const batchSize = 1000
+	var ids []interface{}
+	flush := func() error {
+		if len(ids) == 0 {
+			return nil
+		}
+		_, err := history.RemoveAll(bson.D{{"_id", bson.D{{"$in", ids}}}})
+		ids = ids[:0]
+		return errors.Trace(err)
+	}
+	var doc bson.M
+	for iter.Next(&doc) {
+		if remove(doc) {
+			ids = append(ids, doc["_id"])
+		}
+		if len(ids) >= batchSize {
+			if err := flush(); err != nil {
+				iter.Close()
+				return errors.Trace(err)
+			}
+		}
+		doc = nil
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return flush()
+}
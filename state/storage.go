@@ -1525,7 +1525,7 @@ func (st *State) AddStorageForUnit(
 		}
 		return st.addStorageForUnitOps(u, name, cons)
 	}
-	if err := st.run(buildTxn); err != nil {
+	if err := st.run(withModelQuotas(st, buildTxn)); err != nil {
 		return errors.Annotatef(err, "adding %q storage to %s", name, u)
 	}
 	return nil
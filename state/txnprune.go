@@ -0,0 +1,139 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// These are the states, as recorded by mgo/txn, of transactions
+	// that have finished running.
+	txnStateAborted = 5
+	txnStateApplied = 6
+
+	// txnPruneBatchSize is the number of transactions removed at once.
+	txnPruneBatchSize = 1000
+)
+
+// TransactionsSize returns the size, in bytes, of the collection
+// holding the transactions run against the database, excluding the
+// space used by its indexes.
+func (st *State) TransactionsSize() (int64, error) {
+	var result struct {
+		Size int64 `bson:"size"`
+	}
+	err := st.MongoSession().DB(jujuDB).Run(bson.D{
+		{"collStats", txnsC},
+	}, &result)
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot read transactions collection size")
+	}
+	return result.Size, nil
+}
+
+// PruneTransactions removes the completed transactions that were
+// started before minTime and that are no longer referenced by any
+// document, and returns the number of transactions removed.
+func (st *State) PruneTransactions(minTime time.Time) (int, error) {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	db := session.DB(jujuDB)
+
+	// Find the candidates before looking for references to them.
+	// A transaction that has completed never gains new references,
+	// so any references it still has are seen by the scan below.
+	candidates, err := completedTxnsBefore(db.C(txnsC), minTime)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	names, err := db.CollectionNames()
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot list collections")
+	}
+	for _, name := range names {
+		if name == txnsC || name == txnLogC || strings.HasPrefix(name, "system.") {
+			continue
+		}
+		if err := discardReferencedTxns(db.C(name), candidates); err != nil {
+			return 0, errors.Annotatef(err, "cannot read transaction queues in %q", name)
+		}
+	}
+
+	batch := make([]bson.ObjectId, 0, txnPruneBatchSize)
+	removed := 0
+	removeBatch := func() error {
+		info, err := db.C(txnsC).RemoveAll(bson.D{{"_id", bson.D{{"$in", batch}}}})
+		if err != nil {
+			return errors.Annotate(err, "cannot remove transactions")
+		}
+		removed += info.Removed
+		batch = batch[:0]
+		return nil
+	}
+	for id := range candidates {
+		batch = append(batch, id)
+		if len(batch) == txnPruneBatchSize {
+			if err := removeBatch(); err != nil {
+				return removed, errors.Trace(err)
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := removeBatch(); err != nil {
+			return removed, errors.Trace(err)
+		}
+	}
+	return removed, nil
+}
+
+// completedTxnsBefore returns the ids of the transactions in txns that
+// were started before minTime and have since completed.
+func completedTxnsBefore(txns *mgo.Collection, minTime time.Time) (map[bson.ObjectId]bool, error) {
+	iter := txns.Find(bson.D{
+		{"_id", bson.D{{"$lt", bson.NewObjectIdWithTime(minTime)}}},
+		{"s", bson.D{{"$in", []int{txnStateAborted, txnStateApplied}}}},
+	}).Select(bson.D{{"_id", 1}}).Iter()
+	ids := make(map[bson.ObjectId]bool)
+	var doc struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	for iter.Next(&doc) {
+		ids[doc.Id] = true
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot read transactions")
+	}
+	return ids, nil
+}
+
+// discardReferencedTxns removes from candidates the ids of the
+// transactions referenced by the txn-queue of any document in coll.
+func discardReferencedTxns(coll *mgo.Collection, candidates map[bson.ObjectId]bool) error {
+	iter := coll.Find(bson.D{
+		{"txn-queue.0", bson.D{{"$exists", true}}},
+	}).Select(bson.D{{"txn-queue", 1}}).Iter()
+	var doc struct {
+		Queue []string `bson:"txn-queue"`
+	}
+	for iter.Next(&doc) {
+		// Each token is the hex transaction id, followed by an
+		// underscore and a nonce.
+		for _, token := range doc.Queue {
+			if len(token) >= 24 && bson.IsObjectIdHex(token[:24]) {
+				delete(candidates, bson.ObjectIdHex(token[:24]))
+			}
+		}
+		doc.Queue = nil
+	}
+	return errors.Trace(iter.Close())
+}
@@ -0,0 +1,69 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type TxnPruneSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&TxnPruneSuite{})
+
+func (s *TxnPruneSuite) txnCount(c *gc.C) int {
+	count, err := s.State.MongoSession().DB("juju").C("txns").Count()
+	c.Assert(err, jc.ErrorIsNil)
+	return count
+}
+
+func (s *TxnPruneSuite) TestPruneTransactions(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 3; i++ {
+		err = m.SetPassword("password-that-is-long-enough")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	before := s.txnCount(c)
+
+	// Nothing was started before the distant past.
+	removed, err := s.State.PruneTransactions(time.Now().Add(-24 * time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.Equals, 0)
+
+	removed, err = s.State.PruneTransactions(time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.GreaterThan, 0)
+	c.Assert(s.txnCount(c), gc.Equals, before-removed)
+
+	// Transactions still referenced by documents are kept.
+	var doc struct {
+		Queue []string `bson:"txn-queue"`
+	}
+	err = s.State.MongoSession().DB("juju").C("machines").FindId(s.State.ModelUUID() + ":" + m.Id()).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Queue, gc.Not(gc.HasLen), 0)
+	for _, token := range doc.Queue {
+		n, err := s.State.MongoSession().DB("juju").C("txns").FindId(bson.ObjectIdHex(token[:24])).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(n, gc.Equals, 1)
+	}
+
+	// The machine can still be changed.
+	err = m.SetPassword("another-password-long-enough")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *TxnPruneSuite) TestTransactionsSize(c *gc.C) {
+	size, err := s.State.TransactionsSize()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, jc.GreaterThan, int64(0))
+}
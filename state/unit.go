@@ -1294,7 +1294,7 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 		}
 		return u.assignToMachineOps(m, unused)
 	}
-	if err := u.st.run(buildTxn); err != nil {
+	if err := u.st.run(withModelQuotas(u.st, buildTxn)); err != nil {
 		return errors.Trace(err)
 	}
 	u.doc.MachineId = m.doc.Id
@@ -1691,7 +1691,7 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 		m, ops, err = u.assignToNewMachineOps(template, host.Id, *cons.Container)
 		return ops, err
 	}
-	if err := u.st.run(buildTxn); err != nil {
+	if err := u.st.run(withModelQuotas(u.st, buildTxn)); err != nil {
 		if errors.Cause(err) == machineNotCleanErr {
 			// The clean machine was used before we got a chance
 			// to use it so just stick the unit on a new machine.
@@ -1749,7 +1749,7 @@ func (u *Unit) AssignToNewMachine() (err error) {
 		m, ops, err = u.assignToNewMachineOps(template, "", containerType)
 		return ops, err
 	}
-	if err := u.st.run(buildTxn); err != nil {
+	if err := u.st.run(withModelQuotas(u.st, buildTxn)); err != nil {
 		return errors.Trace(err)
 	}
 	u.doc.MachineId = m.doc.Id
@@ -1969,6 +1969,19 @@ var hasNoContainersTerm = bson.DocElem{
 		{{"children", bson.D{{"$exists", false}}}},
 	}}
 
+// rangeTerms returns the query terms matching values within the given
+// range constraint bounds, either of which may be unset.
+func rangeTerms(min, max *uint64) bson.D {
+	var terms bson.D
+	if min != nil && *min > 0 {
+		terms = append(terms, bson.DocElem{"$gte", *min})
+	}
+	if max != nil {
+		terms = append(terms, bson.DocElem{"$lte", *max})
+	}
+	return terms
+}
+
 // findCleanMachineQuery returns a Mongo query to find clean (and possibly empty) machines with
 // characteristics matching the specified constraints.
 func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value) (bson.D, error) {
@@ -2018,17 +2031,17 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	// to err on the side of caution and exclude such machines.
 	var suitableInstanceData []instanceData
 	var suitableTerms bson.D
-	if cons.Arch != nil && *cons.Arch != "" {
-		suitableTerms = append(suitableTerms, bson.DocElem{"arch", *cons.Arch})
+	if cons.HasArch() {
+		suitableTerms = append(suitableTerms, bson.DocElem{"arch", bson.D{{"$in", cons.Arches()}}})
 	}
-	if cons.Mem != nil && *cons.Mem > 0 {
-		suitableTerms = append(suitableTerms, bson.DocElem{"mem", bson.D{{"$gte", *cons.Mem}}})
+	if memTerms := rangeTerms(cons.Mem, cons.MaxMem); len(memTerms) > 0 {
+		suitableTerms = append(suitableTerms, bson.DocElem{"mem", memTerms})
 	}
 	if cons.RootDisk != nil && *cons.RootDisk > 0 {
 		suitableTerms = append(suitableTerms, bson.DocElem{"rootdisk", bson.D{{"$gte", *cons.RootDisk}}})
 	}
-	if cons.CpuCores != nil && *cons.CpuCores > 0 {
-		suitableTerms = append(suitableTerms, bson.DocElem{"cpucores", bson.D{{"$gte", *cons.CpuCores}}})
+	if coresTerms := rangeTerms(cons.CpuCores, cons.MaxCpuCores); len(coresTerms) > 0 {
+		suitableTerms = append(suitableTerms, bson.DocElem{"cpucores", coresTerms})
 	}
 	if cons.CpuPower != nil && *cons.CpuPower > 0 {
 		suitableTerms = append(suitableTerms, bson.DocElem{"cpupower", bson.D{{"$gte", *cons.CpuPower}}})
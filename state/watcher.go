@@ -69,6 +69,13 @@ type StringsWatcher interface {
 	Changes() <-chan []string
 }
 
+// SettingsChangesWatcher generates signals when a settings document
+// changes, returning the changed items sorted by key.
+type SettingsChangesWatcher interface {
+	Watcher
+	Changes() <-chan []ItemChange
+}
+
 // RelationUnitsWatcher generates signals when units enter or leave
 // the scope of a RelationUnit, and changes to the settings of those
 // units known to have entered.
@@ -209,6 +216,16 @@ func (st *State) WatchModels() StringsWatcher {
 	})
 }
 
+// WatchCloudImageMetadata returns a StringsWatcher that notifies of
+// the ids of cloud image metadata that are added, changed or removed.
+func (st *State) WatchCloudImageMetadata() StringsWatcher {
+	return newCollectionWatcher(st, colWCfg{
+		col:           cloudimagemetadataC,
+		global:        true,
+		notifyRemoved: true,
+	})
+}
+
 // WatchModelLives returns a StringsWatcher that notifies of changes
 // to any model life values. The most important difference between
 // this and WatchModels is that this will signal one last time if a
@@ -1321,6 +1338,20 @@ func (st *State) WatchControllerInfo() NotifyWatcher {
 	return newEntityWatcher(st, controllersC, modelGlobalKey)
 }
 
+// WatchStateServingInfo returns a NotifyWatcher that notifies when
+// the controller's state serving info, including its certificate and
+// private key, is changed.
+func (st *State) WatchStateServingInfo() NotifyWatcher {
+	return newEntityWatcher(st, controllersC, stateServingInfoKey)
+}
+
+// WatchControllerConfig returns a NotifyWatcher that notifies when
+// the controller's configuration, including its CA certificate, is
+// changed.
+func (st *State) WatchControllerConfig() NotifyWatcher {
+	return newEntityWatcher(st, controllersC, controllerSettingsGlobalKey)
+}
+
 // Watch returns a watcher for observing changes to a machine.
 func (m *Machine) Watch() NotifyWatcher {
 	return newEntityWatcher(m.st, machinesC, m.doc.DocID)
@@ -1366,6 +1397,14 @@ func (st *State) WatchForModelConfigChanges() NotifyWatcher {
 	return newEntityWatcher(st, settingsC, st.docID(modelGlobalKey))
 }
 
+// WatchModelConfigItemChanges returns a SettingsChangesWatcher that
+// reports the model config attributes that change, along with their
+// old and new values. The first event reports every attribute as
+// added.
+func (st *State) WatchModelConfigItemChanges() SettingsChangesWatcher {
+	return newSettingsChangesWatcher(st, settingsC, modelGlobalKey)
+}
+
 // WatchForUnitAssignment watches for new services that request units to be
 // assigned to machines.
 func (st *State) WatchForUnitAssignment() StringsWatcher {
@@ -1515,6 +1554,98 @@ func (w *docWatcher) loop(docKeys []docKey) error {
 	}
 }
 
+// settingsChangesWatcher reports the items that change in a single
+// settings document.
+type settingsChangesWatcher struct {
+	commonWatcher
+	collection string
+	key        string
+	out        chan []ItemChange
+}
+
+var _ Watcher = (*settingsChangesWatcher)(nil)
+
+func newSettingsChangesWatcher(backend modelBackend, collection, key string) SettingsChangesWatcher {
+	w := &settingsChangesWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		collection:    collection,
+		key:           key,
+		out:           make(chan []ItemChange),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for the settingsChangesWatcher.
+func (w *settingsChangesWatcher) Changes() <-chan []ItemChange {
+	return w.out
+}
+
+func (w *settingsChangesWatcher) read() (map[string]interface{}, error) {
+	doc, err := readSettingsDoc(w.backend, w.collection, w.key)
+	if errors.IsNotFound(err) {
+		return map[string]interface{}{}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return doc.Settings, nil
+}
+
+func (w *settingsChangesWatcher) loop() error {
+	in := make(chan watcher.Change)
+	coll, closer := w.db.GetCollection(w.collection)
+	docID := w.backend.docID(w.key)
+	txnRevno, err := getTxnRevno(coll, docID)
+	closer()
+	if err != nil {
+		return err
+	}
+	w.watcher.Watch(coll.Name(), docID, txnRevno, in)
+	defer w.watcher.Unwatch(coll.Name(), docID, in)
+
+	// sent holds the settings last reported, so that several
+	// changes between events are reported as one. The initial
+	// event is always sent.
+	var sent map[string]interface{}
+	initial := true
+	latest, err := w.read()
+	if err != nil {
+		return err
+	}
+	changes := settingsChanges(sent, latest)
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			if latest, err = w.read(); err != nil {
+				return err
+			}
+			changes = settingsChanges(sent, latest)
+			if initial || len(changes) > 0 {
+				out = w.out
+			} else {
+				out = nil
+			}
+		case out <- changes:
+			sent = latest
+			initial = false
+			changes = nil
+			out = nil
+		}
+	}
+}
+
 // machineUnitsWatcher notifies about assignments and lifecycle changes
 // for all units of a machine.
 //
@@ -1962,6 +2093,10 @@ type colWCfg struct {
 
 	// If global is true the watcher won't be limited to this model.
 	global bool
+
+	// If notifyRemoved is true the ids of removed documents are
+	// reported along with those of added and changed documents.
+	notifyRemoved bool
 }
 
 // newCollectionWatcher starts and returns a new StringsWatcher configured
@@ -2115,6 +2250,11 @@ func (w *collectionWatcher) initial() ([]string, error) {
 // Additionally, mergeIds strips the model UUID prefix from the id
 // before emitting it through the watcher.
 func (w *collectionWatcher) mergeIds(changes *[]string, updates map[interface{}]bool) error {
+	if w.notifyRemoved {
+		for id := range updates {
+			updates[id] = true
+		}
+	}
 	return mergeIds(w.backend, changes, updates, w.convertId)
 }
 
@@ -198,6 +198,11 @@ const (
 	Provisioning      Status = "allocating"
 	Running           Status = "running"
 	ProvisioningError Status = "provisioning error"
+
+	// Evicted indicates that the provider has reclaimed, or given
+	// notice that it will reclaim, the spot or preemptible capacity
+	// on which the instance was running.
+	Evicted Status = "evicted"
 )
 
 const (
@@ -214,6 +219,7 @@ func (status Status) KnownInstanceStatus() bool {
 		ProvisioningError,
 		Allocating,
 		Running,
+		Evicted,
 		Unknown:
 		return true
 	}
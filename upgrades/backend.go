@@ -26,6 +26,7 @@ type StateBackend interface {
 	UpgradeNoProxyDefaults() error
 	AddNonDetachableStorageMachineId() error
 	RemoveNilValueApplicationSettings() error
+	MigrateSchema() error
 }
 
 // Model is an interface providing access to the details of a model within the
@@ -92,6 +93,15 @@ func (s stateBackend) RemoveNilValueApplicationSettings() error {
 	return state.RemoveNilValueApplicationSettings(s.st)
 }
 
+func (s stateBackend) MigrateSchema() error {
+	_, err := s.st.MigrateSchema(state.MigrateSchemaParams{
+		Progress: func(step state.SchemaMigrationStep) {
+			logger.Infof("schema migration %d (%s): %s", step.Version, step.Description, step.Action)
+		},
+	})
+	return errors.Trace(err)
+}
+
 type modelShim struct {
 	st *state.State
 	m  *state.Model
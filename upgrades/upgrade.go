@@ -90,8 +90,17 @@ func (e *upgradeError) Error() string {
 }
 
 // PerformUpgrade runs the business logic needed to upgrade the current "from" version to this
-// version of Juju on the "target" type of machine.
+// version of Juju on the "target" type of machine. On the database master, the database
+// schema is brought up to date first, whatever the versions.
 func PerformUpgrade(from version.Number, targets []Target, context Context) error {
+	if hasDatabaseMasterTarget(targets) {
+		if err := context.StateContext().State().MigrateSchema(); err != nil {
+			return &upgradeError{
+				description: "migrate database schema",
+				err:         err,
+			}
+		}
+	}
 	if hasStateTarget(targets) {
 		ops := newStateUpgradeOpsIterator(from)
 		if err := runUpgradeSteps(ops, targets, context.StateContext()); err != nil {
@@ -228,6 +228,11 @@ func (mock *mockStateBackend) AllModels() ([]upgrades.Model, error) {
 	return mock.models, mock.NextErr()
 }
 
+func (mock *mockStateBackend) MigrateSchema() error {
+	mock.MethodCall(mock, "MigrateSchema")
+	return mock.NextErr()
+}
+
 type mockModel struct {
 	testing.Stub
 	config    *config.Config
@@ -632,7 +637,7 @@ func (s *upgradeSuite) TestStateStepsNotAttemptedWhenNoStateTarget(c *gc.C) {
 	}
 
 	check(upgrades.Controller, 1, nil)
-	check(upgrades.DatabaseMaster, 1, []string{"AllModels"})
+	check(upgrades.DatabaseMaster, 1, []string{"MigrateSchema", "AllModels"})
 	check(upgrades.AllMachines, 0, nil)
 	check(upgrades.HostMachine, 0, nil)
 }
@@ -690,7 +695,7 @@ func (s *upgradeSuite) TestEnvironUpgradeOperations(c *gc.C) {
 	err := upgrades.PerformUpgrade(fromVers, targets(upgrades.DatabaseMaster), ctx)
 	c.Assert(err, jc.ErrorIsNil)
 
-	state.CheckCallNames(c, "AllModels")
+	state.CheckCallNames(c, "MigrateSchema", "AllModels")
 	model0.CheckCallNames(c, "Config", "CloudSpec")
 	model1.CheckCallNames(c, "Config", "CloudSpec")
 	newEnvironStub.CheckCallNames(c, "NewEnviron", "NewEnviron")
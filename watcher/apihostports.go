@@ -0,0 +1,13 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher
+
+import "github.com/juju/juju/network"
+
+// APIHostPortsWatcher describes a watcher that reports the host/port
+// addresses of the API servers each time they change.
+type APIHostPortsWatcher interface {
+	CoreWatcher
+	Changes() <-chan [][]network.HostPort
+}
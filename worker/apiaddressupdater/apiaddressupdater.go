@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
 )
 
 var logger = loggo.GetLogger("juju.worker.apiaddressupdater")
@@ -30,6 +31,11 @@ type APIAddressUpdater struct {
 type APIAddresser interface {
 	APIHostPorts() ([][]network.HostPort, error)
 	WatchAPIHostPorts() (watcher.NotifyWatcher, error)
+
+	// WatchAPIHostPortsChanges returns a watcher that sends the
+	// addresses themselves whenever they change, or an error
+	// satisfying errors.IsNotImplemented if the API server cannot.
+	WatchAPIHostPortsChanges() (watcher.APIHostPortsWatcher, error)
 }
 
 // APIAddressSetter is an interface that is provided to NewAPIAddressUpdater
@@ -46,6 +52,14 @@ func NewAPIAddressUpdater(addresser APIAddresser, setter APIAddressSetter) (work
 		addresser: addresser,
 		setter:    setter,
 	}
+	changes, err := addresser.WatchAPIHostPortsChanges()
+	if err == nil {
+		return newPushedAddressUpdater(handler, changes)
+	} else if !errors.IsNotImplemented(err) {
+		return nil, errors.Trace(err)
+	}
+	// The API server can only tell us that the addresses have
+	// changed, so fetch them after each notification.
 	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
 		Handler: handler,
 	})
@@ -66,7 +80,12 @@ func (c *APIAddressUpdater) Handle(_ <-chan struct{}) error {
 	if err != nil {
 		return fmt.Errorf("error getting addresses: %v", err)
 	}
+	return c.update(addresses)
+}
 
+// update filters the supplied addresses and records them with the
+// setter.
+func (c *APIAddressUpdater) update(addresses [][]network.HostPort) error {
 	// Filter out any LXC or LXD bridge addresses. See LP bug #1416928. and
 	// bug #1567683
 	hpsToSet := make([][]network.HostPort, 0, len(addresses))
@@ -96,3 +115,58 @@ func (c *APIAddressUpdater) Handle(_ <-chan struct{}) error {
 func (c *APIAddressUpdater) TearDown() error {
 	return nil
 }
+
+// pushedAddressUpdater updates the addresses each time the API server
+// sends them.
+type pushedAddressUpdater struct {
+	catacomb catacomb.Catacomb
+	updater  *APIAddressUpdater
+	changes  watcher.APIHostPortsWatcher
+}
+
+func newPushedAddressUpdater(updater *APIAddressUpdater, changes watcher.APIHostPortsWatcher) (worker.Worker, error) {
+	w := &pushedAddressUpdater{
+		updater: updater,
+		changes: changes,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+		Init: []worker.Worker{changes},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+func (w *pushedAddressUpdater) loop() error {
+	// The watcher only reports changes made after it was started,
+	// so begin with the current addresses.
+	if err := w.updater.Handle(w.catacomb.Dying()); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case addresses, ok := <-w.changes.Changes():
+			if !ok {
+				return errors.New("API host ports watcher closed")
+			}
+			if err := w.updater.update(addresses); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *pushedAddressUpdater) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *pushedAddressUpdater) Wait() error {
+	return w.catacomb.Wait()
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/apiaddressupdater"
 )
 
@@ -49,7 +51,8 @@ func (s *apiAddressSetter) SetAPIHostPorts(servers [][]network.HostPort) error {
 
 func (s *APIAddressUpdaterSuite) TestStartStop(c *gc.C) {
 	st, _ := s.OpenAPIAsNewMachine(c, state.JobHostUnits)
-	worker, err := apiaddressupdater.NewAPIAddressUpdater(apimachiner.NewState(st), &apiAddressSetter{})
+	setter := &apiAddressSetter{servers: make(chan [][]network.HostPort, 1)}
+	worker, err := apiaddressupdater.NewAPIAddressUpdater(apimachiner.NewState(st), setter)
 	c.Assert(err, jc.ErrorIsNil)
 	worker.Kill()
 	c.Assert(worker.Wait(), gc.IsNil)
@@ -108,6 +111,46 @@ func (s *APIAddressUpdaterSuite) TestAddressChange(c *gc.C) {
 	}
 }
 
+func (s *APIAddressUpdaterSuite) TestAddressChangeWithoutPushedAddresses(c *gc.C) {
+	setter := &apiAddressSetter{servers: make(chan [][]network.HostPort, 1)}
+	st, _ := s.OpenAPIAsNewMachine(c, state.JobHostUnits)
+	addresser := notifyOnlyAddresser{apimachiner.NewState(st)}
+	worker, err := apiaddressupdater.NewAPIAddressUpdater(addresser, setter)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+	s.BackingState.StartSync()
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for SetAPIHostPorts to be called initially")
+	case servers := <-setter.servers:
+		c.Assert(servers, gc.HasLen, 0)
+	}
+
+	updatedServers := [][]network.HostPort{
+		network.NewHostPorts(1234, "localhost", "127.0.0.1"),
+	}
+	err = s.State.SetAPIHostPorts(updatedServers)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for SetAPIHostPorts to be called after update")
+	case servers := <-setter.servers:
+		c.Assert(servers, gc.DeepEquals, updatedServers)
+	}
+}
+
+// notifyOnlyAddresser behaves as if the API server cannot send the
+// addresses along with each change.
+type notifyOnlyAddresser struct {
+	apiaddressupdater.APIAddresser
+}
+
+func (notifyOnlyAddresser) WatchAPIHostPortsChanges() (watcher.APIHostPortsWatcher, error) {
+	return nil, errors.NotImplementedf("WatchAPIHostPortsChanges")
+}
+
 func (s *APIAddressUpdaterSuite) TestBridgeAddressesFiltering(c *gc.C) {
 	lxcFakeNetConfig := filepath.Join(c.MkDir(), "lxc-net")
 	netConf := []byte(`
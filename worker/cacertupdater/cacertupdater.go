@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cacertupdater provides a worker that keeps the CA
+// certificate in an agent's configuration in line with the
+// controller's, so that the agent goes on trusting the controllers
+// while their CA is rotated.
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/watcher"
+)
+
+var logger = loggo.GetLogger("juju.worker.cacertupdater")
+
+// Facade exposes the controller configuration to the worker.
+type Facade interface {
+	ControllerConfig() (controller.Config, error)
+	WatchControllerConfig() (watcher.NotifyWatcher, error)
+}
+
+// CACertSetter records the CA certificate the agent trusts.
+type CACertSetter interface {
+	CACert() string
+	SetCACert(caCert string) error
+}
+
+// Config holds the dependencies of the worker.
+type Config struct {
+	Facade Facade
+	Setter CACertSetter
+}
+
+// Validate returns an error if the config cannot drive a worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Setter == nil {
+		return errors.NotValidf("nil Setter")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that updates the agent's CA certificate
+// whenever the controller's changes.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &handler{config},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// handler implements watcher.NotifyHandler.
+type handler struct {
+	config Config
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (h *handler) SetUp() (watcher.NotifyWatcher, error) {
+	return h.config.Facade.WatchControllerConfig()
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (h *handler) Handle(_ <-chan struct{}) error {
+	cfg, err := h.config.Facade.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read controller config")
+	}
+	caCert, ok := cfg.CACert()
+	if !ok || caCert == "" || caCert == h.config.Setter.CACert() {
+		return nil
+	}
+	logger.Infof("updating CA certificate")
+	if err := h.config.Setter.SetCACert(caCert); err != nil {
+		return errors.Annotate(err, "cannot update CA certificate")
+	}
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (h *handler) TearDown() error {
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/controller"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/cacertupdater"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade *mockFacade
+	setter *mockSetter
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &mockFacade{
+		caCert:  "old",
+		watcher: newMockNotifyWatcher(),
+	}
+	s.setter = &mockSetter{
+		caCert:  "old",
+		updated: make(chan string, 10),
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := cacertupdater.NewWorker(cacertupdater.Config{Setter: s.setter})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+	_, err = cacertupdater.NewWorker(cacertupdater.Config{Facade: s.facade})
+	c.Assert(err, gc.ErrorMatches, "nil Setter not valid")
+}
+
+func (s *WorkerSuite) TestUpdatesCACert(c *gc.C) {
+	w, err := cacertupdater.NewWorker(cacertupdater.Config{
+		Facade: s.facade,
+		Setter: s.setter,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	// The initial event finds the CA certificate unchanged.
+	s.facade.watcher.changes <- struct{}{}
+	s.facade.setCACert("new")
+	s.facade.watcher.changes <- struct{}{}
+	s.assertUpdated(c, "new")
+	s.assertNotUpdated(c)
+}
+
+func (s *WorkerSuite) TestWatchError(c *gc.C) {
+	s.facade.watchErr = errors.New("boom")
+	w, err := cacertupdater.NewWorker(cacertupdater.Config{
+		Facade: s.facade,
+		Setter: s.setter,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *WorkerSuite) TestSetError(c *gc.C) {
+	s.setter.err = errors.New("boom")
+	s.facade.setCACert("new")
+	w, err := cacertupdater.NewWorker(cacertupdater.Config{
+		Facade: s.facade,
+		Setter: s.setter,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade.watcher.changes <- struct{}{}
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "cannot update CA certificate: boom")
+}
+
+func (s *WorkerSuite) assertUpdated(c *gc.C, expect string) {
+	select {
+	case caCert := <-s.setter.updated:
+		c.Assert(caCert, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for CA certificate update")
+	}
+}
+
+func (s *WorkerSuite) assertNotUpdated(c *gc.C) {
+	select {
+	case caCert := <-s.setter.updated:
+		c.Fatalf("unexpected CA certificate update %q", caCert)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type mockFacade struct {
+	mu       sync.Mutex
+	caCert   string
+	watcher  *mockNotifyWatcher
+	watchErr error
+}
+
+func (f *mockFacade) setCACert(caCert string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.caCert = caCert
+}
+
+func (f *mockFacade) ControllerConfig() (controller.Config, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return controller.Config{controller.CACertKey: f.caCert}, nil
+}
+
+func (f *mockFacade) WatchControllerConfig() (watcher.NotifyWatcher, error) {
+	if f.watchErr != nil {
+		return nil, f.watchErr
+	}
+	return f.watcher, nil
+}
+
+type mockSetter struct {
+	mu      sync.Mutex
+	caCert  string
+	err     error
+	updated chan string
+}
+
+func (s *mockSetter) CACert() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.caCert
+}
+
+func (s *mockSetter) SetCACert(caCert string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.mu.Lock()
+	s.caCert = caCert
+	s.mu.Unlock()
+	s.updated <- caCert
+	return nil
+}
+
+type mockNotifyWatcher struct {
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	w := &mockNotifyWatcher{changes: make(chan struct{}, 1)}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.changes
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
+type ManifoldConfig engine.AgentAPIManifoldConfig
+
+// Manifold returns a dependency manifold that runs a CA certificate
+// updater worker, using the resource names defined in the supplied
+// config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := engine.AgentAPIManifoldConfig(config)
+	manifold := engine.AgentAPIManifold(typedConfig, newWorker)
+	manifold.Filter = errorFilter
+	return manifold
+}
+
+// newWorker wraps NewWorker for use in a engine.AgentAPIManifold. The
+// worker is uninstalled if the controller cannot report changes to its
+// configuration.
+var newWorker = func(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	if apiCaller.BestFacadeVersion("Agent") < 3 {
+		logger.Debugf("controller does not support CA certificate updates")
+		return nil, dependency.ErrUninstall
+	}
+	w, err := NewWorker(Config{
+		Facade: apiagent.NewState(apiCaller),
+		Setter: agentCACertSetter{a},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// errorFilter uninstalls the worker if the controller cannot report
+// changes to its configuration.
+func errorFilter(err error) error {
+	if params.IsCodeNotImplemented(errors.Cause(err)) {
+		logger.Debugf("controller does not support CA certificate updates")
+		return dependency.ErrUninstall
+	}
+	return err
+}
+
+// agentCACertSetter records the CA certificate in an agent's
+// configuration.
+type agentCACertSetter struct {
+	agent agent.Agent
+}
+
+// CACert is part of the CACertSetter interface.
+func (s agentCACertSetter) CACert() string {
+	return s.agent.CurrentConfig().CACert()
+}
+
+// SetCACert is part of the CACertSetter interface.
+func (s agentCACertSetter) SetCACert(caCert string) error {
+	return s.agent.ChangeConfig(func(config agent.ConfigSetter) error {
+		config.SetCACert(caCert)
+		return nil
+	})
+}
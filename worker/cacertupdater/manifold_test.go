@@ -0,0 +1,37 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater_test
+
+import (
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/cmd/jujud/agent/engine/enginetest"
+	"github.com/juju/juju/worker/cacertupdater"
+	"github.com/juju/juju/worker/dependency"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) TestUninstalledWithoutAgentV3(c *gc.C) {
+	var called bool
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		called = true
+		return nil
+	})
+	config := cacertupdater.ManifoldConfig(enginetest.AgentAPIManifoldTestConfig())
+	_, err := enginetest.RunAgentAPIManifold(cacertupdater.Manifold(config), &fakeAgent{}, apiCaller)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	c.Assert(called, gc.Equals, false)
+}
+
+type fakeAgent struct {
+	agent.Agent
+}
@@ -11,11 +11,13 @@ import (
 	"github.com/juju/utils/cert"
 	"github.com/juju/utils/set"
 	worker "gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	statewatcher "github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/watcher/legacy"
 )
 
@@ -25,14 +27,19 @@ var logger = loggo.GetLogger("juju.worker.certupdater")
 //
 // In practice, CertificateUpdater is used by a controller's machine agent to watch
 // that server's machines addresses in state, and write a new certificate to the
-// agent's config file.
+// agent's config file. It also picks up certificates rotated in state, along
+// with the CA certificate they are signed with, and adds the machine's
+// addresses to them, so that the API server can serve them without restarting.
 type CertificateUpdater struct {
 	addressWatcher  AddressWatcher
 	getter          StateServingInfoGetter
 	setter          StateServingInfoSetter
+	caCertSetter    CACertSetter
 	configGetter    ControllerConfigGetter
 	hostPortsGetter APIHostPortsGetter
+	source          StateServingInfoSource
 	addresses       []network.Address
+	last            state.StateServingInfo
 }
 
 // AddressWatcher is an interface that is provided to NewCertificateUpdater
@@ -58,6 +65,18 @@ type StateServingInfoGetter interface {
 // StateServingInfo value with a newly generated certificate.
 type StateServingInfoSetter func(info params.StateServingInfo, done <-chan struct{}) error
 
+// CACertSetter defines a function that is called to record the CA
+// certificate a rotated controller certificate is signed with.
+type CACertSetter func(caCert string) error
+
+// StateServingInfoSource is an interface that is provided to
+// NewCertificateUpdater which can be used to watch and read the
+// state serving info held in state.
+type StateServingInfoSource interface {
+	WatchStateServingInfo() state.NotifyWatcher
+	StateServingInfo() (state.StateServingInfo, error)
+}
+
 // APIHostPortsGetter is an interface that is provided to NewCertificateUpdater
 // whose APIHostPorts method will be invoked to get controller addresses.
 type APIHostPortsGetter interface {
@@ -66,21 +85,34 @@ type APIHostPortsGetter interface {
 
 // NewCertificateUpdater returns a worker.Worker that watches for changes to
 // machine addresses and then generates a new controller certificate with those
-// addresses in the certificate's SAN value.
+// addresses in the certificate's SAN value. It also watches for certificates
+// rotated in state, and passes them to setter with the machine addresses
+// added, and their CA certificate to caCertSetter.
 func NewCertificateUpdater(addressWatcher AddressWatcher, getter StateServingInfoGetter,
-	configGetter ControllerConfigGetter, hostPortsGetter APIHostPortsGetter, setter StateServingInfoSetter,
+	configGetter ControllerConfigGetter, hostPortsGetter APIHostPortsGetter,
+	source StateServingInfoSource, setter StateServingInfoSetter, caCertSetter CACertSetter,
 ) worker.Worker {
 	return legacy.NewNotifyWorker(&CertificateUpdater{
 		addressWatcher:  addressWatcher,
 		configGetter:    configGetter,
 		hostPortsGetter: hostPortsGetter,
+		source:          source,
 		getter:          getter,
 		setter:          setter,
+		caCertSetter:    caCertSetter,
 	})
 }
 
 // SetUp is defined on the NotifyWatchHandler interface.
 func (c *CertificateUpdater) SetUp() (state.NotifyWatcher, error) {
+	// Record the certificate in state now, so that only later
+	// rotations replace the certificate generated for this machine.
+	info, err := c.source.StateServingInfo()
+	if err != nil {
+		return nil, errors.Annotate(err, "retrieving initial state serving info")
+	}
+	c.last = info
+
 	// Populate certificate SAN with any addresses we know about now.
 	apiHostPorts, err := c.hostPortsGetter.APIHostPorts()
 	if err != nil {
@@ -99,13 +131,17 @@ func (c *CertificateUpdater) SetUp() (state.NotifyWatcher, error) {
 		return nil, errors.Annotate(err, "setting initial cerificate SAN list")
 	}
 	// Return
-	return c.addressWatcher.WatchAddresses(), nil
+	return newNotifyWatchers(c.addressWatcher.WatchAddresses(), c.source.WatchStateServingInfo()), nil
 }
 
 // Handle is defined on the NotifyWatchHandler interface.
 func (c *CertificateUpdater) Handle(done <-chan struct{}) error {
+	reloaded, err := c.reloadCertificate(done)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	addresses := c.addressWatcher.Addresses()
-	if reflect.DeepEqual(addresses, c.addresses) {
+	if !reloaded && reflect.DeepEqual(addresses, c.addresses) {
 		// Sometimes the watcher will tell us things have changed, when they
 		// haven't as far as we can tell.
 		logger.Debugf("addresses haven't really changed since last updated cert")
@@ -114,6 +150,44 @@ func (c *CertificateUpdater) Handle(done <-chan struct{}) error {
 	return c.updateCertificate(addresses, done)
 }
 
+// reloadCertificate writes out the certificate held in state, and the
+// CA certificate it is signed with, if the certificate has been
+// rotated since it was last seen. It reports whether it did so.
+func (c *CertificateUpdater) reloadCertificate(done <-chan struct{}) (bool, error) {
+	info, err := c.source.StateServingInfo()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot read state serving info")
+	}
+	if info.Cert == c.last.Cert && info.PrivateKey == c.last.PrivateKey && info.CertChain == c.last.CertChain {
+		return false, nil
+	}
+	stateInfo, ok := c.getter.StateServingInfo()
+	if !ok {
+		return false, errors.New("no state serving info, cannot reload server certificate")
+	}
+	cfg, err := c.configGetter.ControllerConfig()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot read controller config")
+	}
+	if caCert, ok := cfg.CACert(); ok {
+		if err := c.caCertSetter(caCert); err != nil {
+			return false, errors.Annotate(err, "cannot write CA certificate")
+		}
+	}
+	stateInfo.Cert = info.Cert
+	stateInfo.PrivateKey = info.PrivateKey
+	stateInfo.CertChain = info.CertChain
+	if info.CAPrivateKey != "" {
+		stateInfo.CAPrivateKey = info.CAPrivateKey
+	}
+	if err := c.setter(stateInfo, done); err != nil {
+		return false, errors.Annotate(err, "cannot write agent config")
+	}
+	c.last = info
+	logger.Infof("controller certificate reloaded from state")
+	return true, nil
+}
+
 func (c *CertificateUpdater) updateCertificate(addresses []network.Address, done <-chan struct{}) error {
 	logger.Debugf("new machine addresses: %#v", addresses)
 	c.addresses = addresses
@@ -199,3 +273,75 @@ func updateRequired(serverCert string, newAddrs []string) ([]string, bool, error
 func (c *CertificateUpdater) TearDown() error {
 	return nil
 }
+
+// notifyWatchers is a state.NotifyWatcher that notifies when any of
+// the watchers it combines do.
+type notifyWatchers struct {
+	tomb     tomb.Tomb
+	watchers []state.NotifyWatcher
+	changes  chan struct{}
+}
+
+func newNotifyWatchers(watchers ...state.NotifyWatcher) *notifyWatchers {
+	w := &notifyWatchers{
+		watchers: watchers,
+		changes:  make(chan struct{}, 1),
+	}
+	for _, watcher := range watchers {
+		go w.forward(watcher)
+	}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+		for _, watcher := range w.watchers {
+			watcher.Stop()
+		}
+	}()
+	return w
+}
+
+// forward passes on the events of the given watcher, coalescing
+// them with any events not yet received.
+func (w *notifyWatchers) forward(watcher state.NotifyWatcher) {
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				w.tomb.Kill(statewatcher.EnsureErr(watcher))
+				return
+			}
+			select {
+			case w.changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Changes is defined on the state.NotifyWatcher interface.
+func (w *notifyWatchers) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Kill is defined on the state.Watcher interface.
+func (w *notifyWatchers) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is defined on the state.Watcher interface.
+func (w *notifyWatchers) Wait() error {
+	return w.tomb.Wait()
+}
+
+// Stop is defined on the state.Watcher interface.
+func (w *notifyWatchers) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+// Err is defined on the state.Watcher interface.
+func (w *notifyWatchers) Err() error {
+	return w.tomb.Err()
+}
@@ -107,6 +107,33 @@ func (g *mockAPIHostGetter) APIHostPorts() ([][]network.HostPort, error) {
 	}, nil
 }
 
+type mockStateServingInfoSource struct {
+	changes chan struct{}
+	info    state.StateServingInfo
+}
+
+func newMockStateServingInfoSource() *mockStateServingInfoSource {
+	return &mockStateServingInfoSource{
+		changes: make(chan struct{}),
+		info: state.StateServingInfo{
+			Cert:       coretesting.ServerCert,
+			PrivateKey: coretesting.ServerKey,
+		},
+	}
+}
+
+func (m *mockStateServingInfoSource) WatchStateServingInfo() state.NotifyWatcher {
+	return newMockNotifyWatcher(m.changes)
+}
+
+func (m *mockStateServingInfoSource) StateServingInfo() (state.StateServingInfo, error) {
+	return m.info, nil
+}
+
+func nopCACertSetter(string) error {
+	return nil
+}
+
 func (s *CertUpdaterSuite) TestStartStop(c *gc.C) {
 	var initialAddresses []string
 	setter := func(info params.StateServingInfo, dying <-chan struct{}) error {
@@ -124,7 +151,7 @@ func (s *CertUpdaterSuite) TestStartStop(c *gc.C) {
 	}
 	changes := make(chan struct{})
 	worker := certupdater.NewCertificateUpdater(
-		&mockMachine{changes}, s, &mockConfigGetter{}, &mockAPIHostGetter{}, setter,
+		&mockMachine{changes}, s, &mockConfigGetter{}, &mockAPIHostGetter{}, newMockStateServingInfoSource(), setter, nopCACertSetter,
 	)
 	worker.Kill()
 	c.Assert(worker.Wait(), gc.IsNil)
@@ -153,7 +180,7 @@ func (s *CertUpdaterSuite) TestAddressChange(c *gc.C) {
 	}
 	changes := make(chan struct{})
 	worker := certupdater.NewCertificateUpdater(
-		&mockMachine{changes}, s, &mockConfigGetter{}, &mockAPIHostGetter{}, setter,
+		&mockMachine{changes}, s, &mockConfigGetter{}, &mockAPIHostGetter{}, newMockStateServingInfoSource(), setter, nopCACertSetter,
 	)
 	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
 	defer worker.Kill()
@@ -194,7 +221,8 @@ func (s *CertUpdaterSuite) TestAddressChangeNoCAKey(c *gc.C) {
 	}
 	changes := make(chan struct{})
 	worker := certupdater.NewCertificateUpdater(
-		&mockMachine{changes}, &mockStateServingGetterNoCAKey{}, &mockConfigGetter{}, &mockAPIHostGetter{}, setter,
+		&mockMachine{changes}, &mockStateServingGetterNoCAKey{}, &mockConfigGetter{}, &mockAPIHostGetter{},
+		newMockStateServingInfoSource(), setter, nopCACertSetter,
 	)
 	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
 	defer worker.Kill()
@@ -207,3 +235,60 @@ func (s *CertUpdaterSuite) TestAddressChangeNoCAKey(c *gc.C) {
 		c.Fatalf("set state serving info unexpectedly called")
 	}
 }
+
+func (s *CertUpdaterSuite) TestCertificateRotated(c *gc.C) {
+	rotatedCert, rotatedKey, err := cert.NewServer(
+		coretesting.CACert, coretesting.CAKey, time.Now().AddDate(1, 0, 0), []string{"10.0.0.1"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var caCerts []string
+	caCertSetter := func(caCert string) error {
+		caCerts = append(caCerts, caCert)
+		return nil
+	}
+	rotated := make(chan struct{})
+	updated := make(chan struct{})
+	setter := func(info params.StateServingInfo, dying <-chan struct{}) error {
+		s.stateServingInfo = info
+		if info.Cert == rotatedCert {
+			c.Check(info.PrivateKey, gc.Equals, rotatedKey)
+			c.Check(info.CertChain, gc.Equals, "rotated chain")
+			close(rotated)
+			return nil
+		}
+		srvCert, err := cert.ParseCert(info.Cert)
+		c.Assert(err, jc.ErrorIsNil)
+		sanIPs := set.NewStrings()
+		for _, ip := range srvCert.IPAddresses {
+			sanIPs.Add(ip.String())
+		}
+		if sanIPs.Contains("10.0.0.1") && sanIPs.Contains("0.1.2.3") {
+			close(updated)
+		}
+		return nil
+	}
+	source := newMockStateServingInfoSource()
+	worker := certupdater.NewCertificateUpdater(
+		&mockMachine{make(chan struct{})}, s, &mockConfigGetter{}, &mockAPIHostGetter{}, source, setter, caCertSetter,
+	)
+	defer func() { c.Assert(worker.Wait(), jc.ErrorIsNil) }()
+	defer worker.Kill()
+
+	// The certificate is written out as rotated in state, and then
+	// has the machine addresses added to it.
+	source.info = state.StateServingInfo{
+		Cert:       rotatedCert,
+		PrivateKey: rotatedKey,
+		CertChain:  "rotated chain",
+	}
+	source.changes <- struct{}{}
+	for _, ch := range []chan struct{}{rotated, updated} {
+		select {
+		case <-ch:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for certificate to be reloaded")
+		}
+	}
+	c.Assert(caCerts, jc.DeepEquals, []string{coretesting.CACert})
+}
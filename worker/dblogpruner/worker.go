@@ -19,6 +19,14 @@ type LogPruneParams struct {
 	MaxLogAge       time.Duration
 	MaxCollectionMB int
 	PruneInterval   time.Duration
+
+	// MaxAuditLogAge is how long entries are kept in the audit
+	// log. If it is zero, audit entries are never pruned.
+	MaxAuditLogAge time.Duration
+
+	// MaxModelConfigHistoryAge is how long previous versions of
+	// model config are kept. If it is zero, they are never pruned.
+	MaxModelConfigHistoryAge time.Duration
 }
 
 const DefaultMaxLogAge = 3 * 24 * time.Hour // 3 days
@@ -64,6 +72,18 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 			if err != nil {
 				return errors.Trace(err)
 			}
+			if p.MaxAuditLogAge > 0 {
+				minAuditTime := time.Now().Add(-p.MaxAuditLogAge)
+				if _, err := w.st.PruneAuditLog(minAuditTime); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			if p.MaxModelConfigHistoryAge > 0 {
+				minHistoryTime := time.Now().Add(-p.MaxModelConfigHistoryAge)
+				if _, err := w.st.PruneModelConfigHistory(minHistoryTime); err != nil {
+					return errors.Trace(err)
+				}
+			}
 		}
 	}
 }
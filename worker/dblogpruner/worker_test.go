@@ -4,6 +4,7 @@
 package dblogpruner_test
 
 import (
+	"fmt"
 	stdtesting "testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
@@ -45,6 +47,10 @@ func (s *suite) StartWorker(c *gc.C, maxLogAge time.Duration, maxCollectionMB in
 		MaxCollectionMB: maxCollectionMB,
 		PruneInterval:   time.Millisecond, // Speed up pruning interval for testing
 	}
+	s.startWorker(c, params)
+}
+
+func (s *suite) startWorker(c *gc.C, params *dblogpruner.LogPruneParams) {
 	s.pruner = dblogpruner.New(s.State, params)
 	s.AddCleanup(func(*gc.C) {
 		s.pruner.Kill()
@@ -107,6 +113,66 @@ func (s *suite) TestPrunesLogsBySize(c *gc.C) {
 	c.Fatal("pruning didn't happen as expected")
 }
 
+func (s *suite) TestPrunesOldAuditEntries(c *gc.C) {
+	maxAuditLogAge := 24 * time.Hour
+	now := time.Now().UTC()
+	put := s.State.PutAuditEntryFn()
+	for i, t := range []time.Time{now.Add(-maxAuditLogAge - time.Hour), now} {
+		err := put(audit.AuditEntry{
+			JujuServerVersion: version.Current,
+			ModelUUID:         s.State.ModelUUID(),
+			Timestamp:         t,
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         fmt.Sprintf("Application:v1 - Deploy%d", i),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.startWorker(c, &dblogpruner.LogPruneParams{
+		MaxLogAge:       999 * time.Hour,
+		MaxCollectionMB: int(1e9),
+		PruneInterval:   time.Millisecond,
+		MaxAuditLogAge:  maxAuditLogAge,
+	})
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		entries, err := s.State.AuditEntries(audit.Filter{})
+		c.Assert(err, jc.ErrorIsNil)
+		if len(entries) == 1 {
+			c.Assert(entries[0].Operation, gc.Equals, "Application:v1 - Deploy1")
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) TestPrunesOldModelConfigHistory(c *gc.C) {
+	// The state's clock is set long in the past, so the change is
+	// recorded as being older than the maximum age.
+	err := s.State.UpdateModelConfig(map[string]interface{}{"arbitrary-key": "shazam!"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	history, err := s.State.ModelConfigHistory(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.Not(gc.HasLen), 0)
+
+	s.startWorker(c, &dblogpruner.LogPruneParams{
+		MaxLogAge:                999 * time.Hour,
+		MaxCollectionMB:          int(1e9),
+		PruneInterval:            time.Millisecond,
+		MaxModelConfigHistoryAge: 24 * time.Hour,
+	})
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		history, err := s.State.ModelConfigHistory(0)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(history) == 0 {
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
 func (s *suite) addLogs(c *gc.C, t0 time.Time, text string, count int) {
 	dbLogger := state.NewEntityDbLogger(s.State, names.NewMachineTag("0"), version.Current)
 	defer dbLogger.Close()
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/arch"
@@ -135,6 +136,15 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 	logger.Debugf("API addresses: %q", result.APIAddresses)
 	containerType := ctx.agentConfig.Value(agent.ContainerType)
 	namespace := ctx.agentConfig.Value(agent.Namespace)
+	values := map[string]string{
+		agent.ContainerType: containerType,
+		agent.Namespace:     namespace,
+	}
+	for _, key := range []string{agent.AgentCPUQuota, agent.AgentMemoryLimit} {
+		if value := ctx.agentConfig.Value(key); value != "" {
+			values[key] = value
+		}
+	}
 	conf, err := agent.NewAgentConfig(
 		agent.AgentConfigParams{
 			Paths: agent.Paths{
@@ -152,10 +162,7 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 			StateAddresses: result.StateAddresses,
 			APIAddresses:   result.APIAddresses,
 			CACert:         ctx.agentConfig.CACert(),
-			Values: map[string]string{
-				agent.ContainerType: containerType,
-				agent.Namespace:     namespace,
-			},
+			Values:         values,
 		})
 	if err != nil {
 		return errors.Trace(err)
@@ -277,6 +284,7 @@ func (ctx *SimpleContext) service(unitName string, renderer shell.Renderer) (dep
 		ctx.agentConfig.DataDir(),
 		ctx.agentConfig.LogDir(),
 	)
+	info.Limits = ctx.agentResourceLimits()
 
 	// TODO(thumper): 2013-09-02 bug 1219630
 	// As much as I'd like to remove JujuContainerType now, it is still
@@ -288,6 +296,29 @@ func (ctx *SimpleContext) service(unitName string, renderer shell.Renderer) (dep
 	return ctx.discoverService(svcName, conf)
 }
 
+// agentResourceLimits returns the resource limits recorded in the
+// machine agent's config, to be applied to deployed unit agents.
+func (ctx *SimpleContext) agentResourceLimits() service.AgentResourceLimits {
+	var limits service.AgentResourceLimits
+	if value := ctx.agentConfig.Value(agent.AgentCPUQuota); value != "" {
+		quota, err := strconv.Atoi(value)
+		if err != nil {
+			logger.Warningf("ignoring invalid %s value %q", agent.AgentCPUQuota, value)
+		} else {
+			limits.CPUQuota = quota
+		}
+	}
+	if value := ctx.agentConfig.Value(agent.AgentMemoryLimit); value != "" {
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			logger.Warningf("ignoring invalid %s value %q", agent.AgentMemoryLimit, value)
+		} else {
+			limits.MemoryLimit = limit
+		}
+	}
+	return limits
+}
+
 func removeOnErr(err *error, path string) {
 	if *err != nil {
 		if err := os.RemoveAll(path); err != nil {
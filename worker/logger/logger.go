@@ -4,6 +4,8 @@
 package logger
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	worker "gopkg.in/juju/worker.v1"
@@ -18,18 +20,22 @@ var log = loggo.GetLogger("juju.worker.logger")
 // Logger is responsible for updating the loggo configuration when the
 // environment watcher tells the agent that the value has changed.
 type Logger struct {
-	api         *logger.State
-	agentConfig agent.Config
-	lastConfig  string
+	api          *logger.State
+	agentConfig  agent.Config
+	changeConfig func(agent.ConfigMutator) error
+	lastConfig   string
 }
 
 // NewLogger returns a worker.Worker that uses the notify watcher returned
-// from the setup.
-func NewLogger(api *logger.State, agentConfig agent.Config) (worker.Worker, error) {
+// from the setup. If changeConfig is not nil, the model's agent settings
+// are also recorded in the agent's configuration, so that they apply
+// from the start when the agent is restarted.
+func NewLogger(api *logger.State, agentConfig agent.Config, changeConfig func(agent.ConfigMutator) error) (worker.Worker, error) {
 	logger := &Logger{
-		api:         api,
-		agentConfig: agentConfig,
-		lastConfig:  loggo.LoggerInfo(),
+		api:          api,
+		agentConfig:  agentConfig,
+		changeConfig: changeConfig,
+		lastConfig:   loggo.LoggerInfo(),
 	}
 	log.Debugf("initial log config: %q", logger.lastConfig)
 	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
@@ -59,6 +65,35 @@ func (logger *Logger) setLogging() {
 			logger.lastConfig = loggingConfig
 		}
 	}
+	logger.recordAgentSettings()
+}
+
+// recordAgentSettings records the model's agent settings in the agent's
+// configuration.
+func (logger *Logger) recordAgentSettings() {
+	if logger.changeConfig == nil {
+		return
+	}
+	settings, err := logger.api.AgentSettings(logger.agentConfig.Tag())
+	if errors.IsNotImplemented(err) {
+		// The controller is too old to report agent settings.
+		return
+	} else if err != nil {
+		log.Errorf("%v", err)
+		return
+	}
+	maxLogSize := ""
+	if settings.MaxLogSize > 0 {
+		maxLogSize = fmt.Sprint(settings.MaxLogSize)
+	}
+	err = logger.changeConfig(func(setter agent.ConfigSetter) error {
+		setter.SetValue(agent.LoggingConfig, settings.LoggingConfig)
+		setter.SetValue(agent.MaxLogSize, maxLogSize)
+		return nil
+	})
+	if err != nil {
+		log.Errorf("cannot record agent settings: %v", err)
+	}
 }
 
 func (logger *Logger) SetUp() (watcher.NotifyWatcher, error) {
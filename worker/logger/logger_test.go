@@ -74,9 +74,18 @@ func agentConfig(c *gc.C, tag names.Tag) *mockConfig {
 	return &mockConfig{c: c, tag: tag}
 }
 
+type mockConfigSetter struct {
+	agent.ConfigSetter
+	values map[string]string
+}
+
+func (mock *mockConfigSetter) SetValue(key, value string) {
+	mock.values[key] = value
+}
+
 func (s *LoggerSuite) makeLogger(c *gc.C) (worker.Worker, *mockConfig) {
 	config := agentConfig(c, s.machine.Tag())
-	w, err := logger.NewLogger(s.loggerAPI, config)
+	w, err := logger.NewLogger(s.loggerAPI, config, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	return w, config
 }
@@ -103,3 +112,37 @@ func (s *LoggerSuite) TestInitialState(c *gc.C) {
 
 	s.waitLoggingInfo(c, expected)
 }
+
+func (s *LoggerSuite) TestRecordsAgentSettings(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"agent-logging-config": "<root>=INFO",
+		"agent-max-log-size":   50,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	recorded := make(chan map[string]string, 1)
+	changeConfig := func(mutate agent.ConfigMutator) error {
+		setter := &mockConfigSetter{values: make(map[string]string)}
+		if err := mutate(setter); err != nil {
+			return err
+		}
+		select {
+		case recorded <- setter.values:
+		default:
+		}
+		return nil
+	}
+	loggingWorker, err := logger.NewLogger(s.loggerAPI, agentConfig(c, s.machine.Tag()), changeConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(loggingWorker)
+
+	select {
+	case values := <-recorded:
+		c.Assert(values, jc.DeepEquals, map[string]string{
+			agent.LoggingConfig: "<root>=INFO",
+			agent.MaxLogSize:    "50",
+		})
+	case <-time.After(worstCase):
+		c.Fatalf("timed out waiting for agent settings to be recorded")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package objectstoragesweeper
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig describes how to create a worker that removes expired
+// files from a model's object storage.
+type ManifoldConfig struct {
+	EnvironName string
+	ClockName   string
+
+	Period      time.Duration
+	OpenStorage func(*config.Config, storage.Observer) (storage.Storage, error)
+	NewWorker   func(Config) (worker.Worker, error)
+}
+
+// Manifold returns a dependency.Manifold that runs an object storage
+// sweeper according to the supplied configuration.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.EnvironName,
+			config.ClockName,
+		},
+		Start: func(context dependency.Context) (worker.Worker, error) {
+			var environ environs.Environ
+			if err := context.Get(config.EnvironName, &environ); err != nil {
+				return nil, errors.Trace(err)
+			}
+			var clock clock.Clock
+			if err := context.Get(config.ClockName, &clock); err != nil {
+				return nil, errors.Trace(err)
+			}
+			w, err := config.NewWorker(Config{
+				Environ:     environ,
+				OpenStorage: config.OpenStorage,
+				Clock:       clock,
+				Period:      config.Period,
+			})
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return w, nil
+		},
+	}
+}
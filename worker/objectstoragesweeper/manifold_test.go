@@ -0,0 +1,99 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package objectstoragesweeper_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/objectstoragesweeper"
+)
+
+type manifoldSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&manifoldSuite{})
+
+func (*manifoldSuite) TestInputs(c *gc.C) {
+	manifold := makeManifold(nil, nil)
+	c.Assert(manifold.Inputs, jc.DeepEquals, []string{"the-environ", "the-clock"})
+}
+
+func (*manifoldSuite) TestMissingEnviron(c *gc.C) {
+	manifold := makeManifold(nil, nil)
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-environ": dependency.ErrMissing,
+		"the-clock":   testing.NewClock(time.Time{}),
+	}))
+	c.Assert(result, gc.IsNil)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (*manifoldSuite) TestMissingClock(c *gc.C) {
+	manifold := makeManifold(nil, nil)
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-environ": &fakeEnviron{},
+		"the-clock":   dependency.ErrMissing,
+	}))
+	c.Assert(result, gc.IsNil)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (*manifoldSuite) TestWorkerError(c *gc.C) {
+	manifold := makeManifold(nil, errors.New("boglodite"))
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-environ": &fakeEnviron{},
+		"the-clock":   testing.NewClock(time.Time{}),
+	}))
+	c.Assert(result, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "boglodite")
+}
+
+func (*manifoldSuite) TestSuccess(c *gc.C) {
+	w := fakeWorker{name: "Boris"}
+	manifold := makeManifold(&w, nil)
+	result, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"the-environ": &fakeEnviron{},
+		"the-clock":   testing.NewClock(time.Time{}),
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, &w)
+}
+
+func makeManifold(workerResult worker.Worker, workerError error) dependency.Manifold {
+	return objectstoragesweeper.Manifold(objectstoragesweeper.ManifoldConfig{
+		EnvironName: "the-environ",
+		ClockName:   "the-clock",
+		Period:      time.Hour,
+		OpenStorage: func(*config.Config, storage.Observer) (storage.Storage, error) {
+			return nil, errors.NotFoundf("object storage settings")
+		},
+		NewWorker: func(config objectstoragesweeper.Config) (worker.Worker, error) {
+			if err := config.Validate(); err != nil {
+				return nil, err
+			}
+			return workerResult, workerError
+		},
+	})
+}
+
+type fakeEnviron struct {
+	environs.Environ
+}
+
+type fakeWorker struct {
+	worker.Worker
+	name string
+}
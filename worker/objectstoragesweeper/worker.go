@@ -0,0 +1,121 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package objectstoragesweeper provides a worker that removes the
+// temporary files marked with storage.SetExpiry from a model's object
+// storage once they have expired.
+package objectstoragesweeper
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/storage"
+)
+
+var logger = loggo.GetLogger("juju.worker.objectstoragesweeper")
+
+// Config defines the operation of an object storage sweeper worker.
+type Config struct {
+
+	// Environ supplies the model config naming the object storage.
+	Environ environs.ConfigGetter
+
+	// OpenStorage opens the object storage configured in the model
+	// config, returning an error satisfying errors.IsNotFound if
+	// there is none. modelstorage.Open is suitable for most clients.
+	OpenStorage func(*config.Config, storage.Observer) (storage.Storage, error)
+
+	// Clock is the worker's view of time.
+	Clock clock.Clock
+
+	// Period is the time between sweeps.
+	Period time.Duration
+}
+
+// Validate returns an error if the configuration cannot be expected
+// to start a functional worker.
+func (config Config) Validate() error {
+	if config.Environ == nil {
+		return errors.NotValidf("nil Environ")
+	}
+	if config.OpenStorage == nil {
+		return errors.NotValidf("nil OpenStorage")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Period <= 0 {
+		return errors.NotValidf("non-positive Period")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that removes expired files from the
+// model's object storage, once when started and subsequently every
+// Period. Failed sweeps are logged and retried at the next period, so
+// that an unavailable object store does not bounce the worker.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &sweeper{
+		config: config,
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w, nil
+}
+
+type sweeper struct {
+	tomb   tomb.Tomb
+	config Config
+}
+
+func (w *sweeper) loop() error {
+	var delay time.Duration
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.config.Clock.After(delay):
+			if err := w.sweep(); err != nil {
+				logger.Warningf("cannot remove expired files: %v", err)
+			}
+		}
+		delay = w.config.Period
+	}
+}
+
+func (w *sweeper) sweep() error {
+	stor, err := w.config.OpenStorage(w.config.Environ.Config(), storage.NewLoggingObserver(logger))
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	removed, err := storage.RemoveExpired(stor, w.config.Clock.Now())
+	for _, name := range removed {
+		logger.Debugf("removed expired file %q", name)
+	}
+	return errors.Trace(err)
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *sweeper) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *sweeper) Wait() error {
+	return w.tomb.Wait()
+}
@@ -0,0 +1,153 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package objectstoragesweeper_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/objectstoragesweeper"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	clock *testing.Clock
+	opens chan struct{}
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(coretesting.ZeroTime())
+	s.opens = make(chan struct{}, 10)
+}
+
+type mockEnviron struct {
+	cfg *config.Config
+}
+
+func (e mockEnviron) Config() *config.Config {
+	return e.cfg
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C, open func(*config.Config, storage.Observer) (storage.Storage, error)) worker.Worker {
+	cfg := coretesting.ModelConfig(c)
+	w, err := objectstoragesweeper.NewWorker(objectstoragesweeper.Config{
+		Environ: mockEnviron{cfg},
+		OpenStorage: func(got *config.Config, observer storage.Observer) (storage.Storage, error) {
+			c.Check(got, gc.Equals, cfg)
+			c.Check(observer, gc.NotNil)
+			s.opens <- struct{}{}
+			return open(got, observer)
+		},
+		Clock:  s.clock,
+		Period: time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(w), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *WorkerSuite) waitOpen(c *gc.C) {
+	select {
+	case <-s.opens:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for storage to be opened")
+	}
+}
+
+func (s *WorkerSuite) advance(c *gc.C) {
+	err := s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) TestRemovesExpired(c *gc.C) {
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	now := s.clock.Now()
+	for name, expiry := range map[string]time.Time{
+		"soon":  now.Add(time.Minute),
+		"later": now.Add(2 * time.Hour),
+	} {
+		err := stor.Put(name, bytes.NewReader([]byte(name)), int64(len(name)))
+		c.Assert(err, jc.ErrorIsNil)
+		err = storage.SetExpiry(stor, name, expiry)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	s.startWorker(c, func(*config.Config, storage.Observer) (storage.Storage, error) {
+		return stor, nil
+	})
+	s.waitOpen(c)
+	// The worker waits for the next period once it has swept.
+	s.advance(c)
+	s.waitOpen(c)
+	s.advance(c)
+
+	names, err := storage.List(stor, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.SameContents, []string{"later", storage.ExpiryPrefix + "later"})
+}
+
+func (s *WorkerSuite) TestNotConfigured(c *gc.C) {
+	s.startWorker(c, func(*config.Config, storage.Observer) (storage.Storage, error) {
+		return nil, errors.NotFoundf("object storage settings")
+	})
+	s.waitOpen(c)
+	s.advance(c)
+	s.waitOpen(c)
+}
+
+func (s *WorkerSuite) TestOpenErrorLogged(c *gc.C) {
+	s.startWorker(c, func(*config.Config, storage.Observer) (storage.Storage, error) {
+		return nil, errors.New("boom")
+	})
+	s.waitOpen(c)
+	// The worker keeps running, and tries again next period.
+	s.advance(c)
+	c.Assert(c.GetTestLog(), jc.Contains, "cannot remove expired files: boom")
+	s.waitOpen(c)
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	valid := objectstoragesweeper.Config{
+		Environ: mockEnviron{},
+		OpenStorage: func(*config.Config, storage.Observer) (storage.Storage, error) {
+			return nil, nil
+		},
+		Clock:  s.clock,
+		Period: time.Hour,
+	}
+	c.Assert(valid.Validate(), jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		mutate func(*objectstoragesweeper.Config)
+		err    string
+	}{
+		{func(cfg *objectstoragesweeper.Config) { cfg.Environ = nil }, "nil Environ not valid"},
+		{func(cfg *objectstoragesweeper.Config) { cfg.OpenStorage = nil }, "nil OpenStorage not valid"},
+		{func(cfg *objectstoragesweeper.Config) { cfg.Clock = nil }, "nil Clock not valid"},
+		{func(cfg *objectstoragesweeper.Config) { cfg.Period = 0 }, "non-positive Period not valid"},
+	} {
+		c.Logf("test %d", i)
+		cfg := valid
+		test.mutate(&cfg)
+		err := cfg.Validate()
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -35,4 +35,7 @@ var (
 	GetObservedNetworkConfig = &getObservedNetworkConfig
 )
 
-var ClassifyMachine = classifyMachine
+var (
+	ClassifyMachine   = classifyMachine
+	FilterToolsByArch = filterToolsByArch
+)
@@ -65,6 +65,10 @@ type provisioner struct {
 	broker      environs.InstanceBroker
 	toolsFinder ToolsFinder
 	catacomb    catacomb.Catacomb
+
+	// newAuthenticator returns the AuthenticationProvider used to
+	// set up credentials for the machines being provisioned.
+	newAuthenticator func(*apiprovisioner.State) (authentication.AuthenticationProvider, error)
 }
 
 // RetryStrategy defines the retry behavior when encountering a retryable
@@ -119,7 +123,7 @@ var getToolsFinder = func(st *apiprovisioner.State) ToolsFinder {
 
 // getStartTask creates a new worker for the provisioner,
 func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerTask, error) {
-	auth, err := authentication.NewAPIAuthenticator(p.st)
+	auth, err := p.newAuthenticator(p.st)
 	if err != nil {
 		return nil, err
 	}
@@ -174,9 +178,10 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 func NewEnvironProvisioner(st *apiprovisioner.State, agentConfig agent.Config, environ environs.Environ) (Provisioner, error) {
 	p := &environProvisioner{
 		provisioner: provisioner{
-			st:          st,
-			agentConfig: agentConfig,
-			toolsFinder: getToolsFinder(st),
+			st:               st,
+			agentConfig:      agentConfig,
+			toolsFinder:      getToolsFinder(st),
+			newAuthenticator: authentication.NewAPIAuthenticator,
 		},
 		environ: environ,
 	}
@@ -275,6 +280,9 @@ func NewContainerProvisioner(
 			agentConfig: agentConfig,
 			broker:      broker,
 			toolsFinder: toolsFinder,
+			// Containers are not provisioned as controllers,
+			// so they are given API connection info only.
+			newAuthenticator: authentication.NewAPIOnlyAuthenticator,
 		},
 		containerType: containerType,
 	}
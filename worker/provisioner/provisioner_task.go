@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
+	"github.com/juju/utils/arch"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 	worker "gopkg.in/juju/worker.v1"
@@ -51,6 +52,13 @@ type MachineGetter interface {
 	MachinesWithTransientErrors() ([]*apiprovisioner.Machine, []params.StatusResult, error)
 }
 
+// BulkProvisioningInfoGetter is implemented by a MachineGetter that can
+// fetch the provisioning info and tools for a batch of machines in a
+// single API call.
+type BulkProvisioningInfoGetter interface {
+	BulkProvisioningInfo(v version.Number, arch string, tags ...names.MachineTag) ([]params.BulkProvisioningInfoResult, error)
+}
+
 // ToolsFinder is an interface used for finding tools to run on
 // provisioned instances.
 type ToolsFinder interface {
@@ -530,8 +538,18 @@ func (task *provisionerTask) constructInstanceConfig(
 	if len(pInfo.Jobs) > 0 {
 		instanceConfig.Jobs = pInfo.Jobs
 	}
+	for _, binding := range pInfo.NetworkBindings {
+		instanceConfig.NetworkBindings = append(instanceConfig.NetworkBindings, instancecfg.NetworkBinding{
+			InterfaceName: binding.InterfaceName,
+			Space:         binding.Space,
+			SubnetCIDR:    binding.SubnetCIDR,
+		})
+	}
 
 	if multiwatcher.AnyJobNeedsState(instanceConfig.Jobs...) {
+		if stateInfo == nil {
+			return nil, errors.Errorf("cannot provision controller machine %v without mongo connection info", machine.Id())
+		}
 		publicKey, err := simplestreams.UserPublicSigningKey()
 		if err != nil {
 			return nil, err
@@ -650,8 +668,84 @@ func (task *provisionerTask) maintainMachines(machines []*apiprovisioner.Machine
 	return nil
 }
 
+// machineProvisioningInfo holds the provisioning info for a machine,
+// and the tools it may be started with if they were fetched along
+// with it.
+type machineProvisioningInfo struct {
+	pInfo     *params.ProvisioningInfo
+	err       error
+	tools     coretools.List
+	toolsErr  error
+	haveTools bool
+}
+
+// bulkToolsArch reports whether tools may be fetched along with the
+// machines' provisioning info, and the architecture to restrict them
+// to if so. This is only possible if the task finds tools with the
+// provisioner API itself, rather than with some other ToolsFinder.
+func (task *provisionerTask) bulkToolsArch() (string, bool) {
+	switch f := task.toolsFinder.(type) {
+	case *apiprovisioner.State:
+		return "", true
+	case hostArchToolsFinder:
+		if _, ok := f.f.(*apiprovisioner.State); ok {
+			return arch.HostArch(), true
+		}
+	}
+	return "", false
+}
+
+// provisioningInfo returns the provisioning info for each of the given
+// machines. If the API server supports it, the info and tools for all
+// of the machines are fetched in a single call; otherwise the info is
+// fetched for each machine in turn, and the tools are left to be found
+// with the task's ToolsFinder.
+func (task *provisionerTask) provisioningInfo(machines []*apiprovisioner.Machine) []machineProvisioningInfo {
+	infos := make([]machineProvisioningInfo, len(machines))
+	if getter, ok := task.machineGetter.(BulkProvisioningInfoGetter); ok {
+		var agentVersion version.Number
+		toolsArch, withTools := task.bulkToolsArch()
+		if withTools {
+			agentVersion = jujuversion.Current
+		}
+		tags := make([]names.MachineTag, len(machines))
+		for i, m := range machines {
+			tags[i] = m.MachineTag()
+		}
+		results, err := getter.BulkProvisioningInfo(agentVersion, toolsArch, tags...)
+		if err == nil {
+			for i, result := range results {
+				if result.Error != nil {
+					infos[i].err = result.Error
+					continue
+				}
+				infos[i].pInfo = result.Result
+				if withTools {
+					infos[i].haveTools = true
+					infos[i].tools = result.Tools
+					if result.ToolsError != nil {
+						infos[i].toolsErr = result.ToolsError
+					}
+				}
+			}
+			return infos
+		}
+		if !errors.IsNotImplemented(err) {
+			for i := range infos {
+				infos[i].err = err
+			}
+			return infos
+		}
+	}
+	for i, m := range machines {
+		infos[i].pInfo, infos[i].err = m.ProvisioningInfo()
+	}
+	return infos
+}
+
 func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) error {
-	for _, m := range machines {
+	infos := task.provisioningInfo(machines)
+	for i, m := range machines {
 		// Make sure we shouldn't be stopping before we start the next machine
 		select {
 		case <-task.catacomb.Dying():
@@ -659,7 +753,7 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 		default:
 		}
 
-		pInfo, err := m.ProvisioningInfo()
+		pInfo, err := infos[i].pInfo, infos[i].err
 		if err != nil {
 			return task.setErrorStatus("fetching provisioning info for machine %q: %v", m, err)
 		}
@@ -671,16 +765,10 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 
 		assocProvInfoAndMachCfg(pInfo, instanceCfg)
 
-		var arch string
-		if pInfo.Constraints.Arch != nil {
-			arch = *pInfo.Constraints.Arch
+		possibleTools, err := infos[i].tools, infos[i].toolsErr
+		if !infos[i].haveTools {
+			possibleTools, err = task.findTools(pInfo)
 		}
-
-		possibleTools, err := task.toolsFinder.FindTools(
-			jujuversion.Current,
-			pInfo.Series,
-			arch,
-		)
 		if err != nil {
 			return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
 		}
@@ -695,6 +783,15 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 		if err != nil {
 			return task.setErrorStatus("cannot construct params for machine %q: %v", m, err)
 		}
+		if pInfo.Constraints.HasSpread() {
+			if err := task.checkZoneSpread(); err != nil {
+				return task.setErrorStatus("cannot spread machine %q across zones: %v", m, err)
+			}
+		}
+		startInstanceParams.Spot, err = environs.SpotInstanceParamsFromConstraints(task.broker, pInfo.Constraints)
+		if err != nil {
+			return task.setErrorStatus("cannot request spot instance for machine %q: %v", m, err)
+		}
 
 		if err := task.startMachine(m, pInfo, startInstanceParams); err != nil {
 			return errors.Annotatef(err, "cannot start machine %v", m)
@@ -703,6 +800,62 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 	return nil
 }
 
+// findTools returns the tools that a machine with the given
+// provisioning info may be started with.
+func (task *provisionerTask) findTools(pInfo *params.ProvisioningInfo) (coretools.List, error) {
+	// If several arches are acceptable, find tools for all
+	// arches and keep only those for the acceptable ones.
+	var arch string
+	arches := pInfo.Constraints.Arches()
+	if len(arches) == 1 {
+		arch = arches[0]
+	}
+
+	possibleTools, err := task.toolsFinder.FindTools(
+		jujuversion.Current,
+		pInfo.Series,
+		arch,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(arches) > 1 {
+		possibleTools = filterToolsByArch(possibleTools, arches)
+		if len(possibleTools) == 0 {
+			return nil, errors.NotFoundf("tools for arches %q", arches)
+		}
+	}
+	return possibleTools, nil
+}
+
+// checkZoneSpread returns an error if the broker cannot honour the
+// "spread=zone" constraint. The spread is a preference rather than a
+// placement: a zoned broker given no placement directive tries the
+// available zones in ascending order of the number of instances of the
+// machine's distribution group they hold, and falls back to the next
+// zone when an instance cannot be started in one.
+func (task *provisionerTask) checkZoneSpread() error {
+	if _, ok := task.broker.(environs.ZonedEnviron); !ok {
+		return errors.NotSupportedf("spreading across availability zones")
+	}
+	return nil
+}
+
+// filterToolsByArch returns the tools in the given list that are built
+// for any of the given arches.
+func filterToolsByArch(list coretools.List, arches []string) coretools.List {
+	var result coretools.List
+	for _, t := range list {
+		for _, arch := range arches {
+			if t.Version.Arch == arch {
+				result = append(result, t)
+				break
+			}
+		}
+	}
+	return result
+}
+
 func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
 	logger.Errorf(message, machine, err)
 	if err := machine.SetInstanceStatus(status.ProvisioningError, err.Error(), nil); err != nil {
@@ -718,8 +871,15 @@ func (task *provisionerTask) startMachine(
 	startInstanceParams environs.StartInstanceParams,
 ) error {
 	var result *environs.StartInstanceResult
+	// Record when the user has pinned the image, as it will not have
+	// been checked against any image metadata.
+	var statusData map[string]interface{}
+	if imageId, ok := pinnedImageId(provisioningInfo); ok {
+		logger.Infof("starting machine %v from user-pinned image %q", machine, imageId)
+		statusData = map[string]interface{}{"pinned-image-id": imageId}
+	}
 	// TODO (jam): 2017-01-19 Should we be setting this earlier in the cycle?
-	if err := machine.SetInstanceStatus(status.Provisioning, "starting", nil); err != nil {
+	if err := machine.SetInstanceStatus(status.Provisioning, "starting", statusData); err != nil {
 		logger.Errorf("%v", err)
 	}
 	for attemptsLeft := task.retryStartInstanceStrategy.retryCount; attemptsLeft >= 0; attemptsLeft-- {
@@ -783,6 +943,17 @@ func (task *provisionerTask) startMachine(
 	return nil
 }
 
+// pinnedImageId returns the ID of the image pinned by the machine's
+// image-id constraint, if any.
+func pinnedImageId(provisioningInfo *params.ProvisioningInfo) (string, bool) {
+	for _, metadata := range provisioningInfo.ImageMetadata {
+		if metadata.Source == params.ImageSourcePinned {
+			return metadata.ImageId, true
+		}
+	}
+	return "", false
+}
+
 type provisioningInfo struct {
 	Constraints    constraints.Value
 	Series         string
@@ -463,6 +463,58 @@ func (s *ProvisionerSuite) TestConstraints(c *gc.C) {
 	s.checkStartInstanceCustom(c, m, "pork", cons, nil, nil, nil, nil, true)
 }
 
+func (s *ProvisionerSuite) TestSpotInstance(c *gc.C) {
+	m, err := s.addMachineWithConstraints(constraints.MustParse("spot-price=0.05"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+	s.BackingState.StartSync()
+	for {
+		select {
+		case o := <-s.op:
+			start, ok := o.(dummy.OpStartInstance)
+			if !ok {
+				c.Logf("ignoring unexpected operation %#v", o)
+				continue
+			}
+			c.Assert(start.MachineId, gc.Equals, m.Id())
+			c.Assert(start.Spot, jc.DeepEquals, &environs.SpotInstanceParams{MaxPrice: 0.05})
+			return
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("provisioner did not start an instance")
+		}
+	}
+}
+
+func (s *ProvisionerSuite) TestSpreadZoneIsNotPlacement(c *gc.C) {
+	m, err := s.addMachineWithConstraints(constraints.MustParse("spread=zone"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+	s.BackingState.StartSync()
+	for {
+		select {
+		case o := <-s.op:
+			start, ok := o.(dummy.OpStartInstance)
+			if !ok {
+				c.Logf("ignoring unexpected operation %#v", o)
+				continue
+			}
+			// The zone is left to the broker, which spreads
+			// instances across zones and falls back to other
+			// zones if it cannot start one in the best zone.
+			c.Assert(start.MachineId, gc.Equals, m.Id())
+			c.Assert(start.Placement, gc.Equals, "")
+			c.Assert(start.Constraints.HasSpread(), jc.IsTrue)
+			return
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("provisioner did not start an instance")
+		}
+	}
+}
+
 func (s *ProvisionerSuite) TestPossibleTools(c *gc.C) {
 
 	storageDir := c.MkDir()
@@ -710,6 +762,21 @@ func (s *ProvisionerSuite) TestProvisioningDoesNotOccurForKVM(c *gc.C) {
 	s.waitForRemovalMark(c, m)
 }
 
+func (s *ProvisionerSuite) TestFilterToolsByArch(c *gc.C) {
+	var list coretools.List
+	for _, v := range []string{"2.0.0-xenial-amd64", "2.0.0-xenial-arm64", "2.0.0-xenial-ppc64el"} {
+		list = append(list, &coretools.Tools{Version: version.MustParseBinary(v)})
+	}
+	cons := constraints.MustParse("arch=arm64,amd64,s390x")
+	filtered := provisioner.FilterToolsByArch(list, cons.Arches())
+	c.Assert(filtered, gc.HasLen, 2)
+	c.Assert(filtered[0].Version.Arch, gc.Equals, "amd64")
+	c.Assert(filtered[1].Version.Arch, gc.Equals, "arm64")
+
+	cons = constraints.MustParse("arch=s390x")
+	c.Assert(provisioner.FilterToolsByArch(list, cons.Arches()), gc.HasLen, 0)
+}
+
 type MachineClassifySuite struct {
 }
 
@@ -1130,13 +1197,24 @@ func (s *ProvisionerSuite) newProvisionerTask(
 	machineGetter provisioner.MachineGetter,
 	toolsFinder provisioner.ToolsFinder,
 ) provisioner.ProvisionerTask {
+	auth, err := authentication.NewAPIAuthenticator(s.provisioner)
+	c.Assert(err, jc.ErrorIsNil)
+	return s.newProvisionerTaskWithAuth(c, harvestingMethod, broker, machineGetter, toolsFinder, auth)
+}
+
+func (s *ProvisionerSuite) newProvisionerTaskWithAuth(
+	c *gc.C,
+	harvestingMethod config.HarvestMode,
+	broker environs.InstanceBroker,
+	machineGetter provisioner.MachineGetter,
+	toolsFinder provisioner.ToolsFinder,
+	auth authentication.AuthenticationProvider,
+) provisioner.ProvisionerTask {
 
 	machineWatcher, err := s.provisioner.WatchModelMachines()
 	c.Assert(err, jc.ErrorIsNil)
 	retryWatcher, err := s.provisioner.WatchMachineErrorRetry()
 	c.Assert(err, jc.ErrorIsNil)
-	auth, err := authentication.NewAPIAuthenticator(s.provisioner)
-	c.Assert(err, jc.ErrorIsNil)
 
 	retryStrategy := provisioner.NewRetryStrategy(0*time.Second, 0)
 
@@ -1157,6 +1235,17 @@ func (s *ProvisionerSuite) newProvisionerTask(
 	return w
 }
 
+func (s *ProvisionerSuite) TestProvisioningWithAPIOnlyAuthenticator(c *gc.C) {
+	auth, err := authentication.NewAPIOnlyAuthenticator(s.provisioner)
+	c.Assert(err, jc.ErrorIsNil)
+	task := s.newProvisionerTaskWithAuth(c, config.HarvestDestroyed, s.Environ, s.provisioner, mockToolsFinder{}, auth)
+	defer stop(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+}
+
 func (s *ProvisionerSuite) TestHarvestNoneReapsNothing(c *gc.C) {
 
 	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, mockToolsFinder{})
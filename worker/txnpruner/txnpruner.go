@@ -7,32 +7,176 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/worker.v1"
 
 	jworker "github.com/juju/juju/worker"
 )
 
+var logger = loggo.GetLogger("juju.worker.txnpruner")
+
+const (
+	metricsNamespace = "juju"
+	metricsSubsystem = "txnpruner"
+)
+
 // TransactionPruner defines the interface for types capable of
 // pruning transactions.
 type TransactionPruner interface {
-	MaybePruneTransactions() error
+	// TransactionsSize returns the size, in bytes, of the
+	// collection holding the transactions.
+	TransactionsSize() (int64, error)
+
+	// PruneTransactions removes the completed transactions started
+	// before minTime that are no longer needed, and returns the
+	// number of transactions removed.
+	PruneTransactions(minTime time.Time) (int, error)
+}
+
+// Config holds the configuration for a transaction pruning worker.
+type Config struct {
+	// Pruner is used to measure and prune the transactions.
+	Pruner TransactionPruner
+
+	// Interval is the time between pruning runs.
+	Interval time.Duration
+
+	// Clock is the clock to use for all time-related operations.
+	Clock clock.Clock
+
+	// MaxAge is how long completed transactions are kept before
+	// they are pruned. If zero, transactions are pruned as soon as
+	// they are no longer needed.
+	MaxAge time.Duration
+
+	// MaxSize is the size, in bytes, beyond which the transactions
+	// are pruned regardless of their age. If zero, the size of the
+	// transactions collection is not limited.
+	MaxSize int64
+
+	// PrometheusRegisterer is the prometheus.Registerer in which
+	// the pruning metric collectors will be registered.
+	PrometheusRegisterer prometheus.Registerer
+}
+
+// Validate returns an error if the configuration cannot be used to
+// start a worker.
+func (config Config) Validate() error {
+	if config.Pruner == nil {
+		return errors.NotValidf("nil Pruner")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.MaxAge < 0 {
+		return errors.NotValidf("negative MaxAge")
+	}
+	if config.MaxSize < 0 {
+		return errors.NotValidf("negative MaxSize")
+	}
+	if config.PrometheusRegisterer == nil {
+		return errors.NotValidf("nil PrometheusRegisterer")
+	}
+	return nil
+}
+
+// metrics holds the collectors updated by each pruning run.
+type metrics struct {
+	runs   prometheus.Counter
+	pruned prometheus.Counter
+	size   prometheus.Gauge
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		runs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "runs_total",
+			Help:      "Number of times the transactions have been pruned.",
+		}),
+		pruned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "pruned_transactions_total",
+			Help:      "Number of transactions removed by pruning.",
+		}),
+		size: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "transactions_size_bytes",
+			Help:      "Size of the transactions collection before the last pruning run.",
+		}),
+	}
+}
+
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.runs, m.pruned, m.size}
 }
 
 // New returns a worker which periodically prunes the data for
 // completed transactions.
-func New(tp TransactionPruner, interval time.Duration, clock clock.Clock) worker.Worker {
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m := newMetrics()
+	for i, collector := range m.collectors() {
+		if err := config.PrometheusRegisterer.Register(collector); err != nil {
+			for _, registered := range m.collectors()[:i] {
+				config.PrometheusRegisterer.Unregister(registered)
+			}
+			return nil, errors.Annotate(err, "registering txnpruner metrics")
+		}
+	}
 	return jworker.NewSimpleWorker(func(stopCh <-chan struct{}) error {
+		defer func() {
+			for _, collector := range m.collectors() {
+				config.PrometheusRegisterer.Unregister(collector)
+			}
+		}()
 		for {
 			select {
-			case <-clock.After(interval):
-				err := tp.MaybePruneTransactions()
-				if err != nil {
+			case <-config.Clock.After(config.Interval):
+				if err := prune(config, m); err != nil {
 					return errors.Annotate(err, "pruning failed, txnpruner stopping")
 				}
 			case <-stopCh:
 				return nil
 			}
 		}
-	})
+	}), nil
+}
+
+// prune removes the transactions that are older than the configured
+// age, or all those no longer needed if the transactions collection
+// has grown beyond the configured size.
+func prune(config Config, m *metrics) error {
+	size, err := config.Pruner.TransactionsSize()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m.size.Set(float64(size))
+
+	now := config.Clock.Now()
+	minTime := now.Add(-config.MaxAge)
+	if config.MaxSize > 0 && size > config.MaxSize {
+		logger.Infof("transactions collection is %d bytes, over the limit of %d; pruning all completed transactions", size, config.MaxSize)
+		minTime = now
+	}
+	pruned, err := config.Pruner.PruneTransactions(minTime)
+	m.pruned.Add(float64(pruned))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m.runs.Inc()
+	if pruned > 0 {
+		logger.Debugf("pruned %d transactions started before %s", pruned, minTime)
+	}
+	return nil
 }
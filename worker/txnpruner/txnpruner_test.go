@@ -4,11 +4,15 @@
 package txnpruner_test
 
 import (
+	"sync"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	gc "gopkg.in/check.v1"
 
 	coretesting "github.com/juju/juju/testing"
@@ -17,46 +21,111 @@ import (
 
 type TxnPrunerSuite struct {
 	coretesting.BaseSuite
+	registry *prometheus.Registry
 }
 
 var _ = gc.Suite(&TxnPrunerSuite{})
 
+func (s *TxnPrunerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.registry = prometheus.NewRegistry()
+}
+
+func (s *TxnPrunerSuite) config(pruner txnpruner.TransactionPruner, clock clock.Clock) txnpruner.Config {
+	return txnpruner.Config{
+		Pruner:               pruner,
+		Interval:             time.Minute,
+		Clock:                clock,
+		MaxAge:               time.Hour,
+		PrometheusRegisterer: s.registry,
+	}
+}
+
+func (s *TxnPrunerSuite) TestValidate(c *gc.C) {
+	valid := s.config(newFakeTransactionPruner(0), clock.WallClock)
+	c.Assert(valid.Validate(), jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		modify func(*txnpruner.Config)
+		err    string
+	}{{
+		func(cfg *txnpruner.Config) { cfg.Pruner = nil },
+		"nil Pruner not valid",
+	}, {
+		func(cfg *txnpruner.Config) { cfg.Interval = 0 },
+		"non-positive Interval not valid",
+	}, {
+		func(cfg *txnpruner.Config) { cfg.Clock = nil },
+		"nil Clock not valid",
+	}, {
+		func(cfg *txnpruner.Config) { cfg.MaxAge = -time.Second },
+		"negative MaxAge not valid",
+	}, {
+		func(cfg *txnpruner.Config) { cfg.MaxSize = -1 },
+		"negative MaxSize not valid",
+	}, {
+		func(cfg *txnpruner.Config) { cfg.PrometheusRegisterer = nil },
+		"nil PrometheusRegisterer not valid",
+	}} {
+		c.Logf("test %d: %s", i, test.err)
+		cfg := valid
+		test.modify(&cfg)
+		err := cfg.Validate()
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *TxnPrunerSuite) TestPrunes(c *gc.C) {
-	fakePruner := newFakeTransactionPruner()
+	fakePruner := newFakeTransactionPruner(1000)
 	testClock := testing.NewClock(time.Now())
-	interval := time.Minute
-	p := txnpruner.New(fakePruner, interval, testClock)
+	p, err := txnpruner.New(s.config(fakePruner, testClock))
+	c.Assert(err, jc.ErrorIsNil)
 	defer p.Kill()
 
-	select {
-	case <-testClock.Alarms():
-	case <-time.After(coretesting.LongWait):
-		c.Fatalf("timed out waiting for worker to stat")
-	}
-	c.Logf("pruner running and waiting: %s (%s)", testClock.Now(), time.Now())
-	// Show that we prune every minute
+	waitAlarm(c, testClock)
+	// Show that we prune every minute, keeping an hour of
+	// transactions.
 	for i := 0; i < 5; i++ {
-		testClock.Advance(interval)
+		testClock.Advance(time.Minute)
 		c.Logf("loop %d: %s (%s)", i, testClock.Now(), time.Now())
-		select {
-		case <-fakePruner.pruneCh:
-		case <-time.After(coretesting.LongWait):
-			c.Fatal("timed out waiting for pruning to happen")
-		}
+		c.Assert(fakePruner.waitPrune(c), gc.Equals, testClock.Now().Add(-time.Hour))
 		// Now we need to wait for the txn pruner to call clock.After again
 		// before we advance the clock, or it will be waiting for the wrong time.
-		select {
-		case <-testClock.Alarms():
-		case <-time.After(coretesting.LongWait):
-			c.Fatalf("timed out waiting for worker to loop around")
-		}
+		waitAlarm(c, testClock)
 	}
+
+	metrics := s.gatherMetrics(c)
+	c.Check(metrics["juju_txnpruner_runs_total"].GetCounter().GetValue(), gc.Equals, float64(5))
+	c.Check(metrics["juju_txnpruner_pruned_transactions_total"].GetCounter().GetValue(), gc.Equals, float64(5*fakePruner.count))
+	c.Check(metrics["juju_txnpruner_transactions_size_bytes"].GetGauge().GetValue(), gc.Equals, float64(1000))
+}
+
+func (s *TxnPrunerSuite) TestPrunesAllWhenTooLarge(c *gc.C) {
+	fakePruner := newFakeTransactionPruner(2048)
+	testClock := testing.NewClock(time.Now())
+	config := s.config(fakePruner, testClock)
+	config.MaxSize = 1024
+	p, err := txnpruner.New(config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer p.Kill()
+
+	waitAlarm(c, testClock)
+	testClock.Advance(time.Minute)
+	c.Assert(fakePruner.waitPrune(c), gc.Equals, testClock.Now())
+	waitAlarm(c, testClock)
+
+	// Once the collection is small enough again, the age limit applies.
+	fakePruner.setSize(512)
+	testClock.Advance(time.Minute)
+	c.Assert(fakePruner.waitPrune(c), gc.Equals, testClock.Now().Add(-time.Hour))
 }
 
 func (s *TxnPrunerSuite) TestStops(c *gc.C) {
 	success := make(chan bool)
 	check := func() {
-		p := txnpruner.New(newFakeTransactionPruner(), time.Minute, clock.WallClock)
+		p, err := txnpruner.New(s.config(newFakeTransactionPruner(0), clock.WallClock))
+		c.Check(err, jc.ErrorIsNil)
 		p.Kill()
 		c.Check(p.Wait(), jc.ErrorIsNil)
 		success <- true
@@ -68,21 +137,72 @@ func (s *TxnPrunerSuite) TestStops(c *gc.C) {
 	case <-time.After(coretesting.LongWait):
 		c.Fatal("timed out waiting for worker to stop")
 	}
+
+	// The metrics are unregistered once the worker has stopped.
+	c.Assert(s.gatherMetrics(c), gc.HasLen, 0)
+}
+
+func (s *TxnPrunerSuite) gatherMetrics(c *gc.C) map[string]*dto.Metric {
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	metrics := make(map[string]*dto.Metric)
+	for _, family := range families {
+		c.Assert(family.Metric, gc.HasLen, 1)
+		metrics[family.GetName()] = family.Metric[0]
+	}
+	return metrics
+}
+
+func waitAlarm(c *gc.C, clock *testing.Clock) {
+	select {
+	case <-clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for worker to wait on the clock")
+	}
 }
 
-func newFakeTransactionPruner() *fakeTransactionPruner {
+func newFakeTransactionPruner(size int64) *fakeTransactionPruner {
 	return &fakeTransactionPruner{
-		pruneCh: make(chan bool),
+		size:    size,
+		count:   3,
+		pruneCh: make(chan time.Time),
 	}
 }
 
 type fakeTransactionPruner struct {
-	pruneCh chan bool
+	mu      sync.Mutex
+	size    int64
+	count   int
+	pruneCh chan time.Time
+}
+
+func (p *fakeTransactionPruner) setSize(size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.size = size
+}
+
+func (p *fakeTransactionPruner) waitPrune(c *gc.C) time.Time {
+	select {
+	case minTime := <-p.pruneCh:
+		return minTime
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for pruning to happen")
+	}
+	panic("unreachable")
+}
+
+// TransactionsSize implements the txnpruner.TransactionPruner
+// interface.
+func (p *fakeTransactionPruner) TransactionsSize() (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size, nil
 }
 
-// MaybePruneTransactions implements the txnpruner.TransactionPruner
+// PruneTransactions implements the txnpruner.TransactionPruner
 // interface.
-func (p *fakeTransactionPruner) MaybePruneTransactions() error {
-	p.pruneCh <- true
-	return nil
+func (p *fakeTransactionPruner) PruneTransactions(minTime time.Time) (int, error) {
+	p.pruneCh <- minTime
+	return p.count, nil
 }